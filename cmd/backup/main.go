@@ -0,0 +1,73 @@
+// Command backup writes a consistent snapshot of the SQLite database. It is
+// safe to run while the server is up, and the copy can replace site.db
+// directly to restore (after gunzip, if compressed).
+//
+// Usage:
+//
+//	go run ./cmd/backup                 # ./data/backups/site-YYYYMMDD-HHMMSS.db
+//	go run ./cmd/backup -gzip           # same, gzipped
+//	go run ./cmd/backup -dir /mnt/bak   # write to another directory
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/config"
+	"github.com/EC-9624/0xec.dev/internal/database"
+)
+
+func main() {
+	cfg := config.Load()
+
+	dir := flag.String("dir", filepath.Join(filepath.Dir(cfg.DatabaseURL), "backups"), "Directory to write the backup to")
+	compress := flag.Bool("gzip", false, "Gzip the backup")
+	flag.Parse()
+
+	if err := run(cfg.DatabaseURL, *dir, *compress); err != nil {
+		fmt.Fprintf(os.Stderr, "Backup failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dbPath, dir string, compress bool) error {
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("database %s: %w", dbPath, err)
+	}
+
+	db, err := database.Init(dbPath, database.Options{})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	dest := filepath.Join(dir, database.BackupFileName(time.Now(), compress))
+	if err := database.Backup(context.Background(), db, dest, compress); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Backed up %s to %s (%s)\n", dbPath, dest, formatSize(info.Size()))
+	return nil
+}
+
+// formatSize renders a byte count with a binary unit
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
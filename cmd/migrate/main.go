@@ -0,0 +1,93 @@
+// Command migrate applies and reverts the embedded schema migrations.
+//
+// Usage:
+//
+//	go run ./cmd/migrate up           # apply all pending migrations
+//	go run ./cmd/migrate down         # revert the latest migration
+//	go run ./cmd/migrate down -n 3    # revert the latest three
+//	go run ./cmd/migrate status       # list migrations and when they ran
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/EC-9624/0xec.dev/internal/config"
+	"github.com/EC-9624/0xec.dev/internal/database"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate up | down [-n steps] | status")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	cfg := config.Load()
+	db, err := database.Init(cfg.DatabaseURL, database.Options{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	switch os.Args[1] {
+	case "up":
+		err = up(db)
+	case "down":
+		fs := flag.NewFlagSet("down", flag.ExitOnError)
+		steps := fs.Int("n", 1, "Number of migrations to revert")
+		fs.Parse(os.Args[2:])
+		err = down(db, *steps)
+	case "status":
+		err = status(db)
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func up(db *sql.DB) error {
+	applied, err := database.MigrateUp(db)
+	for _, name := range applied {
+		fmt.Println("applied", name)
+	}
+	if err == nil && len(applied) == 0 {
+		fmt.Println("No pending migrations")
+	}
+	return err
+}
+
+func down(db *sql.DB, steps int) error {
+	reverted, err := database.MigrateDown(db, steps)
+	for _, name := range reverted {
+		fmt.Println("reverted", name)
+	}
+	if err == nil && len(reverted) == 0 {
+		fmt.Println("No applied migrations")
+	}
+	return err
+}
+
+func status(db *sql.DB) error {
+	statuses, err := database.MigrationStatuses(db)
+	if err != nil {
+		return err
+	}
+	for _, s := range statuses {
+		state := "pending"
+		if s.AppliedAt != nil {
+			state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%-32s %s\n", s.Name, state)
+	}
+	return nil
+}
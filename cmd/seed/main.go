@@ -26,7 +26,7 @@ func main() {
 	cfg := config.Load()
 
 	// Initialize database
-	db, err := database.Init(cfg.DatabaseURL)
+	db, err := database.Init(cfg.DatabaseURL, database.Options{Migrate: true})
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
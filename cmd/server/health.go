@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/version"
+)
+
+// dbPingTimeout bounds how long a probe waits on the database
+const dbPingTimeout = 2 * time.Second
+
+// healthChecker serves the liveness, readiness and health probes
+type healthChecker struct {
+	db           *sql.DB
+	started      time.Time
+	shuttingDown atomic.Bool
+}
+
+func newHealthChecker(db *sql.DB) *healthChecker {
+	return &healthChecker{db: db, started: time.Now()}
+}
+
+// SetShuttingDown makes every probe report unavailable so load balancers
+// stop routing traffic here while in-flight requests drain
+func (hc *healthChecker) SetShuttingDown() {
+	hc.shuttingDown.Store(true)
+}
+
+type healthResponse struct {
+	Status    string            `json:"status"`
+	Version   string            `json:"version"`
+	Commit    string            `json:"commit"`
+	UptimeSec int64             `json:"uptime_seconds"`
+	DBLatency *int64            `json:"db_latency_ms,omitempty"`
+	Checks    map[string]string `json:"checks"`
+}
+
+// check runs the dependency checks and reports whether all passed
+func (hc *healthChecker) check(ctx context.Context) (healthResponse, bool) {
+	resp := healthResponse{
+		Status:    "ok",
+		Version:   version.Version,
+		Commit:    version.Commit,
+		UptimeSec: int64(time.Since(hc.started).Seconds()),
+		Checks:    map[string]string{},
+	}
+	ok := true
+
+	if hc.shuttingDown.Load() {
+		resp.Checks["process"] = "shutting down"
+		ok = false
+	} else {
+		resp.Checks["process"] = "ok"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dbPingTimeout)
+	defer cancel()
+	start := time.Now()
+	if err := hc.db.PingContext(ctx); err != nil {
+		resp.Checks["database"] = "unreachable"
+		ok = false
+	} else {
+		latency := time.Since(start).Milliseconds()
+		resp.DBLatency = &latency
+		resp.Checks["database"] = "ok"
+	}
+
+	if !ok {
+		resp.Status = "unavailable"
+	}
+	return resp, ok
+}
+
+// Live reports whether the process should keep running. It only fails
+// once shutdown has started.
+func (hc *healthChecker) Live(w http.ResponseWriter, r *http.Request) {
+	if hc.shuttingDown.Load() {
+		writeProbe(w, http.StatusServiceUnavailable, map[string]string{"status": "shutting down"})
+		return
+	}
+	writeProbe(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Ready reports whether the process can serve traffic
+func (hc *healthChecker) Ready(w http.ResponseWriter, r *http.Request) {
+	resp, ok := hc.check(r.Context())
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusServiceUnavailable
+	}
+	writeProbe(w, status, map[string]any{"status": resp.Status, "checks": resp.Checks})
+}
+
+// Health reports readiness along with build information and uptime
+func (hc *healthChecker) Health(w http.ResponseWriter, r *http.Request) {
+	resp, ok := hc.check(r.Context())
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusServiceUnavailable
+	}
+	writeProbe(w, status, resp)
+}
+
+func writeProbe(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
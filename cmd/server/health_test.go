@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/database"
+)
+
+func newTestHealthChecker(t *testing.T) *healthChecker {
+	t.Helper()
+
+	db, err := database.Init(filepath.Join(t.TempDir(), "site.db"), database.Options{Migrate: true})
+	if err != nil {
+		t.Fatalf("database.Init() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return newHealthChecker(db)
+}
+
+func TestHealthChecker_Ready(t *testing.T) {
+	hc := newTestHealthChecker(t)
+
+	rec := httptest.NewRecorder()
+	hc.Ready(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Ready() status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	rec = httptest.NewRecorder()
+	hc.Health(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var body healthResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode /health body: %v", err)
+	}
+	if body.Status != "ok" || body.Checks["database"] != "ok" || body.DBLatency == nil {
+		t.Errorf("Health() body = %+v, want ok with database latency", body)
+	}
+}
+
+func TestHealthChecker_ShuttingDown(t *testing.T) {
+	hc := newTestHealthChecker(t)
+	hc.SetShuttingDown()
+
+	for name, probe := range map[string]http.HandlerFunc{
+		"/ready":  hc.Ready,
+		"/live":   hc.Live,
+		"/health": hc.Health,
+	} {
+		rec := httptest.NewRecorder()
+		probe(rec, httptest.NewRequest(http.MethodGet, name, nil))
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s status = %d during shutdown, want %d", name, rec.Code, http.StatusServiceUnavailable)
+		}
+	}
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/EC-9624/0xec.dev/internal/handlers"
 	"github.com/EC-9624/0xec.dev/internal/logger"
 	"github.com/EC-9624/0xec.dev/internal/middleware"
+	"github.com/EC-9624/0xec.dev/internal/scheduler"
+	"github.com/EC-9624/0xec.dev/internal/version"
 )
 
 func main() {
@@ -26,15 +28,31 @@ func main() {
 
 	// Validate configuration (fails fast in production with insecure defaults)
 	cfg.MustValidate()
+	if _, err := middleware.ParseCIDRs(cfg.AdminAllowedIPs); err != nil {
+		slog.Error("invalid ADMIN_ALLOWED_IPS", "error", err)
+		os.Exit(1)
+	}
 
 	// Initialize database
-	db, err := database.Init(cfg.DatabaseURL)
+	db, err := database.Init(cfg.DatabaseURL, database.Options{
+		Migrate:      cfg.AutoMigrate,
+		MaxOpenConns: cfg.DBMaxOpenConns,
+		MaxIdleConns: cfg.DBMaxIdleConns,
+	})
 	if err != nil {
 		slog.Error("failed to initialize database", "error", err)
 		os.Exit(1)
 	}
 	defer db.Close()
 
+	if !cfg.AutoMigrate {
+		if pending, err := database.PendingMigrations(db); err != nil {
+			slog.Warn("failed to check for pending migrations", "error", err)
+		} else if len(pending) > 0 {
+			slog.Warn("database has pending migrations; run cmd/migrate up", "pending", pending)
+		}
+	}
+
 	// Create handlers
 	h := handlers.NewWithDB(cfg, db)
 
@@ -43,191 +61,48 @@ func main() {
 		slog.Warn("failed to ensure admin user exists", "error", err)
 	}
 
-	// Create router
-	mux := http.NewServeMux()
-
-	// CSRF middleware configuration (secure cookies in production)
-	csrfConfig := middleware.CSRFConfig{
-		Secure: !cfg.IsDevelopment(),
-	}
-	csrfMiddleware := middleware.CSRF(csrfConfig)
-
-	// Rate limiter for login endpoint (5 attempts per minute per IP)
-	loginLimiter := middleware.NewRateLimiter(5.0/60.0, 5)
-
-	// Static files with caching headers
-	staticDir := "./web/static"
-	mux.Handle("GET /static/", http.StripPrefix("/static/", middleware.StaticFileServer(staticDir)))
-
-	// ============================================
-	// PUBLIC ROUTES (with caching for prefetch + hx-boost)
-	// ============================================
-
-	// Cache middleware for public pages (60 second TTL)
-	// This allows prefetched content to be reused by hx-boost requests
-	publicCache := middleware.CacheControl(60 * time.Second)
-
-	// Helper to wrap a handler with cache middleware
-	cached := func(h http.HandlerFunc) http.Handler {
-		return publicCache(h)
+	// Fill in normalized URLs for bookmarks saved before they existed
+	if n, err := h.BackfillNormalizedURLs(context.Background()); err != nil {
+		slog.Warn("failed to backfill normalized bookmark URLs", "error", err)
+	} else if n > 0 {
+		slog.Info("backfilled normalized bookmark URLs", "count", n)
 	}
 
-	// Public page routes
-	mux.Handle("GET /{$}", cached(h.Home))
-	mux.Handle("GET /posts", cached(h.PostsIndex))
-	mux.Handle("GET /posts/{slug}", cached(h.PostShow))
-	mux.Handle("GET /bookmarks", cached(h.BookmarksIndex))
-	mux.Handle("GET /bookmarks/{slug}", cached(h.BookmarksByCollection))
-
-	// HTMX partial routes
-	mux.Handle("GET /htmx/posts/{slug}", cached(h.HTMXPostContent))
-	mux.Handle("GET /htmx/bookmarks", cached(h.HTMXBookmarksContent))
-	mux.Handle("GET /htmx/bookmarks/more", cached(h.HTMXBookmarksMore))
-	mux.Handle("GET /htmx/bookmarks/more/{slug}", cached(h.HTMXBookmarksMore))
-	mux.Handle("GET /htmx/bookmarks/{slug}", cached(h.HTMXBookmarksCollectionContent))
-
-	// RSS feeds (no cache - should be fresh)
-	mux.HandleFunc("GET /feed.xml", h.PostsFeed)
-	mux.HandleFunc("GET /posts/feed.xml", h.PostsFeed)
-	mux.HandleFunc("GET /bookmarks/feed.xml", h.BookmarksFeed)
-
-	// Health check endpoint (no cache - must be real-time)
-	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
-		// Check database connectivity
-		if err := db.Ping(); err != nil {
-			w.WriteHeader(http.StatusServiceUnavailable)
-			w.Write([]byte(`{"status":"unhealthy","error":"database connection failed"}`))
-			return
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"healthy"}`))
-	})
-
-	// ============================================
-	// AUTH ROUTES (CSRF protected, no auth required)
-	// ============================================
-
-	// Login routes need CSRF but not auth
-	// Rate limiting applied to prevent brute-force attacks
-	authMux := http.NewServeMux()
-	authMux.HandleFunc("GET /admin/login", h.LoginPage)
-	authMux.HandleFunc("POST /admin/login", h.Login)
-	mux.Handle("/admin/login", loginLimiter.Limit(csrfMiddleware(authMux)))
-
-	// Logout needs CSRF + auth (handled via admin routes below)
-
-	// ============================================
-	// ADMIN ROUTES (CSRF + Auth protected)
-	// ============================================
-
-	adminMux := http.NewServeMux()
-
-	// Dashboard
-	adminMux.HandleFunc("GET /admin", h.AdminDashboard)
-	adminMux.HandleFunc("GET /admin/", func(w http.ResponseWriter, r *http.Request) {
-		http.Redirect(w, r, "/admin", http.StatusSeeOther)
-	})
-
-	// Logout (in admin routes so it gets auth + csrf)
-	adminMux.HandleFunc("POST /admin/logout", h.Logout)
-
-	// ============================================
-	// ADMIN PAGE ROUTES
-	// ============================================
-
-	// Posts
-	adminMux.HandleFunc("GET /admin/posts", h.AdminPostsList)
-	adminMux.HandleFunc("POST /admin/posts", h.AdminPostCreate)
-	adminMux.HandleFunc("GET /admin/posts/new", h.AdminPostNew)
-	adminMux.HandleFunc("GET /admin/posts/{slug}/edit", h.AdminPostEdit)
-	adminMux.HandleFunc("POST /admin/posts/{slug}", h.AdminPostUpdate)
-	adminMux.HandleFunc("DELETE /admin/posts/{slug}", h.AdminPostDelete)
-	adminMux.HandleFunc("PATCH /admin/posts/{slug}/autosave", h.AdminPostAutosave)
-
-	// Bookmarks
-	adminMux.HandleFunc("GET /admin/bookmarks", h.AdminBookmarksList)
-	adminMux.HandleFunc("POST /admin/bookmarks", h.AdminBookmarkCreate)
-	adminMux.HandleFunc("GET /admin/bookmarks/new", h.AdminBookmarkNew)
-	adminMux.HandleFunc("GET /admin/bookmarks/{id}/edit", h.AdminBookmarkEdit)
-	adminMux.HandleFunc("POST /admin/bookmarks/{id}", h.AdminBookmarkUpdate)
-	adminMux.HandleFunc("DELETE /admin/bookmarks/{id}", h.AdminBookmarkDelete)
-
-	// Import
-	adminMux.HandleFunc("GET /admin/import", h.AdminImportPage)
-	adminMux.HandleFunc("POST /admin/import", h.AdminImportBookmarks)
-
-	// Collections (CRUD only - list is now part of bookmarks board view)
-	adminMux.HandleFunc("POST /admin/collections", h.AdminCollectionCreate)
-	adminMux.HandleFunc("POST /admin/collections/{id}", h.AdminCollectionUpdate)
-	adminMux.HandleFunc("DELETE /admin/collections/{id}", h.AdminCollectionDelete)
-
-	// Tags
-	adminMux.HandleFunc("GET /admin/tags", h.AdminTagsList)
-	adminMux.HandleFunc("DELETE /admin/tags/{id}", h.AdminTagDelete)
-
-	// ============================================
-	// ADMIN HTMX PARTIAL ROUTES
-	// ============================================
-
-	// Posts (HTMX)
-	adminMux.HandleFunc("POST /admin/htmx/posts/{id}/toggle-draft", h.AdminTogglePostDraft)
-
-	// Bookmarks (HTMX)
-	adminMux.HandleFunc("GET /admin/htmx/bookmarks/view", h.HTMXBookmarksView)
-	adminMux.HandleFunc("GET /admin/htmx/bookmarks/new-drawer", h.HTMXAdminBookmarkNewDrawer)
-	adminMux.HandleFunc("GET /admin/htmx/bookmarks/{id}/edit-drawer", h.HTMXAdminBookmarkEditDrawer)
-	adminMux.HandleFunc("POST /admin/htmx/bookmarks/fetch-metadata", h.AdminBookmarkFetchMetadata)
-	adminMux.HandleFunc("GET /admin/htmx/bookmarks/refresh-all", h.AdminRefreshAllMetadata)
-	adminMux.HandleFunc("POST /admin/htmx/bookmarks/{id}/refresh", h.AdminRefreshBookmarkMetadata)
-	adminMux.HandleFunc("POST /admin/htmx/bookmarks/{id}/toggle-public", h.AdminToggleBookmarkPublic)
-	adminMux.HandleFunc("POST /admin/htmx/bookmarks/{id}/toggle-favorite", h.AdminToggleBookmarkFavorite)
-	adminMux.HandleFunc("POST /admin/htmx/bookmarks/{id}/collection", h.AdminUpdateBookmarkCollection)
-	adminMux.HandleFunc("POST /admin/htmx/bookmarks/bulk/move", h.AdminBulkMoveBookmarks)
-	adminMux.HandleFunc("DELETE /admin/htmx/bookmarks/bulk/delete", h.AdminBulkDeleteBookmarks)
-
-	// Collections (HTMX)
-	adminMux.HandleFunc("GET /admin/htmx/collections/new-drawer", h.HTMXAdminCollectionNewDrawer)
-	adminMux.HandleFunc("GET /admin/htmx/collections/{id}/edit-drawer", h.HTMXAdminCollectionEditDrawer)
-	adminMux.HandleFunc("GET /admin/htmx/collections/{id}/bookmarks", h.AdminCollectionBookmarks)
-	adminMux.HandleFunc("POST /admin/htmx/collections/{id}/toggle-public", h.AdminToggleCollectionPublic)
-
-	// Tags (HTMX)
-	adminMux.HandleFunc("POST /admin/htmx/tags/create-inline", h.AdminTagCreateInline)
-	adminMux.HandleFunc("GET /admin/htmx/tags/{id}/posts", h.AdminTagPosts)
-
-	// Uploads (for post editor image upload)
-	adminMux.HandleFunc("POST /admin/uploads/image", h.AdminUploadImage)
-	adminMux.HandleFunc("DELETE /admin/uploads/image", h.AdminDeleteImage)
-
-	// Test routes (development only)
-	if cfg.IsDevelopment() {
-		adminMux.HandleFunc("GET /admin/test/errors", h.TestErrorPage)
-		adminMux.HandleFunc("GET /admin/test/error-500", h.TestError500)
-		adminMux.HandleFunc("GET /admin/test/slow", h.TestSlow)
-		adminMux.HandleFunc("GET /admin/test/success", h.TestSuccess)
-		adminMux.HandleFunc("GET /admin/test/retry-workflow", h.TestRetryWorkflow)
-		adminMux.HandleFunc("POST /admin/test/reset-retry", h.TestResetRetry)
+	// Prometheus metrics (nil when the metrics feature is off)
+	var metrics *middleware.Metrics
+	if cfg.FeatureEnabled(config.FeatureMetrics) {
+		metrics = middleware.NewMetrics()
 	}
 
-	// Wrap admin routes with CSRF + Auth middleware
-	// Order: CSRF runs first (sets token), then Auth checks session
-	authMiddleware := middleware.Auth(h.AuthService())
-	protectedAdmin := csrfMiddleware(authMiddleware(adminMux))
-	mux.Handle("/admin", protectedAdmin)
-	mux.Handle("/admin/", protectedAdmin)
+	health := newHealthChecker(db)
+	mux := newRouter(cfg, h, health, metrics)
 
 	// Apply global middleware
-	// Order: Logger → SecurityHeaders → Compress → Recoverer → Router
+	// Order: RequestID → ResolveClientIP → Logger → SecurityHeaders → Compress → Recoverer → CanonicalPath → Features → ImageBaseURL → Metrics → Router
+	// Metrics wraps the router directly so it can read the matched route pattern
 	var handler http.Handler = mux
+	if metrics != nil {
+		handler = metrics.Instrument(handler)
+	}
+	// Rewrite stored image URLs to the CDN in rendered output
+	handler = middleware.ImageBaseURL(cfg.ImageBaseURL)(handler)
+	// Hide disabled features in the admin UI
+	handler = middleware.Features(cfg.FeatureEnabled)(handler)
+	handler = middleware.CanonicalPath(middleware.TrailingSlash(cfg.TrailingSlash))(handler)
 	handler = middleware.Compress(handler)
 	handler = middleware.Recoverer(handler)
-	if cfg.IsDevelopment() {
-		handler = middleware.SecurityHeaders(handler)
-	} else {
-		handler = middleware.SecurityHeadersWithHSTS(handler)
-	}
-	handler = middleware.Logger(handler)
+	handler = middleware.SecurityHeadersWith(middleware.SecurityConfig{
+		ContentSecurityPolicy: cfg.ContentSecurityPolicy,
+		CSPReportOnly:         cfg.CSPReportOnly,
+		CSPReportURI:          "/csp-report",
+		HSTS:                  !cfg.IsDevelopment(),
+	})(handler)
+	handler = middleware.LoggerWith(middleware.LoggerConfig{
+		SlowThreshold: time.Duration(cfg.SlowRequestThresholdMS) * time.Millisecond,
+	})(handler)
+	// Client IPs come from proxy headers only when the proxy is trusted
+	handler = middleware.ResolveClientIP(cfg.TrustProxy)(handler)
+	handler = middleware.RequestID(handler)
 
 	// Get absolute path for static directory
 	if absPath, err := filepath.Abs(staticDir); err == nil {
@@ -249,6 +124,8 @@ func main() {
 	// Start server in a goroutine
 	go func() {
 		slog.Info("starting server",
+			"version", version.Version,
+			"commit", version.Commit,
 			"address", "http://localhost"+addr,
 			"admin", "http://localhost"+addr+"/admin",
 		)
@@ -265,39 +142,51 @@ func main() {
 		}
 	}()
 
-	// Start periodic session cleanup (every hour)
-	quit := make(chan os.Signal, 1)
-	go func() {
-		ticker := time.NewTicker(1 * time.Hour)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ticker.C:
-				if err := h.CleanupExpiredSessions(context.Background()); err != nil {
-					slog.Warn("failed to cleanup expired sessions", "error", err)
-				} else {
-					slog.Debug("cleaned up expired sessions")
-				}
-			case <-quit:
-				return
-			}
-		}
-	}()
+	// Shutdown context shared by background jobs; cancelled on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Background maintenance jobs (schedules come from config)
+	jobs := scheduler.New()
+	if err := jobs.Add("session-cleanup", cfg.SessionCleanupSchedule, h.CleanupExpiredSessions); err != nil {
+		slog.Error("invalid job schedule", "error", err)
+		os.Exit(1)
+	}
+	if err := jobs.Add("post-trash-purge", cfg.PostTrashPurgeSchedule, h.PurgeTrashedPosts); err != nil {
+		slog.Error("invalid job schedule", "error", err)
+		os.Exit(1)
+	}
+	if err := jobs.Add("search-log-prune", cfg.SearchLogPruneSchedule, h.PruneSearchLog); err != nil {
+		slog.Error("invalid job schedule", "error", err)
+		os.Exit(1)
+	}
+	jobs.Start(ctx)
 
 	// Wait for interrupt signal to gracefully shutdown the server
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	<-ctx.Done()
+	stop()
 
 	slog.Info("shutting down server")
+	health.SetShuttingDown()
 
 	// Give outstanding requests 30 seconds to complete
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
+	if err := server.Shutdown(shutdownCtx); err != nil {
 		slog.Error("server forced to shutdown", "error", err)
 		os.Exit(1)
 	}
 
+	jobs.Wait()
+
+	// Stop metadata fetches and other service work, bounded so a stuck
+	// request can't hold up exit
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelDrain()
+	if err := h.Shutdown(drainCtx); err != nil {
+		slog.Warn("background work did not finish before shutdown", "error", err)
+	}
+
 	slog.Info("server stopped gracefully")
 }
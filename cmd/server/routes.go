@@ -0,0 +1,309 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/config"
+	"github.com/EC-9624/0xec.dev/internal/handlers"
+	"github.com/EC-9624/0xec.dev/internal/middleware"
+)
+
+// staticDir is where static assets are served from
+const staticDir = "./web/static"
+
+// newRouter registers every route on a new mux. Routes belonging to a
+// disabled feature aren't registered, so they 404 like any unknown path.
+// metrics is nil when the metrics feature is off.
+func newRouter(cfg *config.Config, h *handlers.Handlers, health *healthChecker, metrics *middleware.Metrics) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	// CSRF middleware configuration (secure cookies in production). Forms
+	// carrying the token are parsed there, so the upload cap applies too.
+	csrfConfig := middleware.CSRFConfig{
+		Secure:       !cfg.IsDevelopment(),
+		MaxBodyBytes: cfg.MaxUploadBytes,
+	}
+	csrfMiddleware := middleware.CSRF(csrfConfig)
+
+	// Rate limiter for login endpoint (5 attempts per minute per IP)
+	loginLimiter := middleware.NewRateLimiter(5.0/60.0, 5)
+
+	// Separate rate limiter for the public API (per IP)
+	apiLimiter := middleware.NewRateLimiter(float64(cfg.APIRateLimitPerMinute)/60.0, cfg.APIRateLimitBurst)
+
+	// Public search limit (per IP): each search scans posts and bookmarks
+	// and may be logged
+	searchLimiter := middleware.NewRateLimiter(float64(cfg.SearchRateLimitPerMinute)/60.0, cfg.SearchRateLimitBurst)
+
+	// Browsers may send a CSP report for every blocked resource; cap them
+	// per IP so the endpoint can't flood the logs
+	cspReportLimiter := middleware.NewRateLimiter(1, 20)
+
+	// Per-user limits on admin endpoints that fetch remote pages or import files
+	metadataLimiter := middleware.NewKeyedRateLimiter(float64(cfg.MetadataRateLimitPerMinute)/60.0, cfg.MetadataRateLimitBurst, middleware.KeyByUser)
+	importLimiter := middleware.NewKeyedRateLimiter(float64(cfg.ImportRateLimitPerMinute)/60.0, cfg.ImportRateLimitBurst, middleware.KeyByUser)
+
+	// Static files with caching headers
+	mux.Handle("GET /static/", http.StripPrefix("/static/", middleware.StaticFileServer(staticDir)))
+
+	// Locally stored images (handler sets its own caching headers)
+	mux.HandleFunc("GET /images/{id}", h.ServeImage)
+
+	// ============================================
+	// PUBLIC ROUTES (with caching for prefetch + hx-boost)
+	// ============================================
+
+	// Cache middleware for public pages (60 second TTL)
+	// This allows prefetched content to be reused by hx-boost requests
+	publicCache := middleware.CacheControl(60 * time.Second)
+
+	// Helper to wrap a handler with cache middleware
+	cached := func(h http.HandlerFunc) http.Handler {
+		return publicCache(h)
+	}
+
+	// Pages that also answer If-None-Match/If-Modified-Since with a 304
+	revalidated := func(h http.HandlerFunc) http.Handler {
+		return publicCache(middleware.ConditionalGet(h))
+	}
+
+	// Public page routes
+	mux.Handle("GET /{$}", cached(h.Home))
+	mux.Handle("GET /posts", cached(h.PostsIndex))
+	mux.Handle("GET /posts/{slug}", revalidated(h.PostShow))
+	mux.HandleFunc("GET /posts/{slug}/card.png", h.PostCard)
+	mux.HandleFunc("GET /posts/{slug}/preview", h.PostPreview)
+	mux.Handle("GET /tags", cached(h.TagsIndex))
+	mux.Handle("GET /tags/{slug}", cached(h.PostsByTag))
+	mux.Handle("GET /bookmarks", revalidated(h.BookmarksIndex))
+	mux.Handle("GET /bookmarks/favorites", cached(h.BookmarksFavorites))
+	mux.Handle("GET /bookmarks/{slug}", cached(h.BookmarksByCollection))
+
+	// Sitewide search (not cached: results change and searches are logged)
+	if cfg.FeatureEnabled(config.FeatureSearch) {
+		mux.Handle("GET /search", searchLimiter.Limit(http.HandlerFunc(h.Search)))
+	}
+
+	// Bookmark click tracking (no cache - every click is counted)
+	mux.HandleFunc("GET /go/{id}", h.BookmarkRedirect)
+
+	// HTMX partial routes
+	mux.Handle("GET /htmx/posts/{slug}", cached(h.HTMXPostContent))
+	mux.Handle("GET /htmx/bookmarks", cached(h.HTMXBookmarksContent))
+	mux.Handle("GET /htmx/bookmarks/more", cached(h.HTMXBookmarksMore))
+	mux.Handle("GET /htmx/bookmarks/more/favorites", cached(h.HTMXBookmarksFavoritesMore))
+	mux.Handle("GET /htmx/bookmarks/more/{slug}", cached(h.HTMXBookmarksMore))
+	mux.Handle("GET /htmx/bookmarks/favorites", cached(h.HTMXBookmarksFavoritesContent))
+	mux.Handle("GET /htmx/bookmarks/{slug}", cached(h.HTMXBookmarksCollectionContent))
+
+	// RSS, Atom and JSON feeds (no cache - should be fresh)
+	mux.HandleFunc("GET /feed.xml", h.PostsFeed)
+	mux.HandleFunc("GET /posts/feed.xml", h.PostsFeed)
+	mux.HandleFunc("GET /bookmarks/feed.xml", h.BookmarksFeed)
+	mux.HandleFunc("GET /bookmarks/favorites/feed.xml", h.BookmarksFavoritesFeed)
+	mux.HandleFunc("GET /bookmarks/{slug}/feed.xml", h.BookmarksCollectionFeed)
+	mux.HandleFunc("GET /feed.atom", h.PostsAtomFeed)
+	mux.HandleFunc("GET /feed.json", h.PostsJSONFeed)
+	mux.HandleFunc("GET /bookmarks/feed.atom", h.BookmarksAtomFeed)
+
+	// Sitemap (cached in memory by the handler) and robots.txt
+	mux.HandleFunc("GET /sitemap.xml", h.Sitemap)
+	mux.HandleFunc("GET /robots.txt", h.RobotsTxt)
+
+	// Content Security Policy violation reports
+	mux.Handle("POST /csp-report", cspReportLimiter.Limit(http.HandlerFunc(h.CSPReport)))
+
+	// Health and orchestration probes (no cache - must be real-time)
+	mux.HandleFunc("GET /health", health.Health)
+	mux.HandleFunc("GET /live", health.Live)
+	mux.HandleFunc("GET /ready", health.Ready)
+
+	// ============================================
+	// API ROUTES (rate limited per IP)
+	// ============================================
+
+	// JSON endpoints register on apiMux so they share the API rate limit
+	if cfg.FeatureEnabled(config.FeatureAPI) {
+		apiMux := http.NewServeMux()
+		apiMux.Handle("GET /api/bookmarks", revalidated(h.APIBookmarksList))
+		// CORS sits outside the limiter so preflights aren't counted and
+		// rate limit rejections stay readable cross-origin
+		cors := middleware.CORS(middleware.CORSConfig{
+			AllowedOrigins:   cfg.CORSAllowedOrigins,
+			AllowCredentials: cfg.CORSAllowCredentials,
+		})
+		mux.Handle("/api/", cors(apiLimiter.LimitAPI(middleware.NestedRoute(apiMux))))
+	}
+
+	// ============================================
+	// AUTH ROUTES (CSRF protected, no auth required)
+	// ============================================
+
+	// Restrict every admin route, login included, to the allowed IPs
+	adminAllowlist := middleware.IPAllowlist(cfg.AdminAllowedIPs)
+
+	// Login routes need CSRF but not auth
+	// Rate limiting applied to prevent brute-force attacks
+	authMux := http.NewServeMux()
+	authMux.HandleFunc("GET /admin/login", h.LoginPage)
+	authMux.HandleFunc("POST /admin/login", h.Login)
+	mux.Handle("/admin/login", adminAllowlist(loginLimiter.Limit(csrfMiddleware(middleware.NestedRoute(authMux)))))
+
+	// Logout needs CSRF + auth (handled via admin routes below)
+
+	// ============================================
+	// ADMIN ROUTES (CSRF + Auth protected)
+	// ============================================
+
+	adminMux := http.NewServeMux()
+
+	// Dashboard
+	adminMux.HandleFunc("GET /admin", h.AdminDashboard)
+	adminMux.HandleFunc("GET /admin/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+	})
+
+	// Logout (in admin routes so it gets auth + csrf)
+	adminMux.HandleFunc("POST /admin/logout", h.Logout)
+	adminMux.HandleFunc("GET /admin/logout-all", h.AdminLogoutAllConfirm)
+	adminMux.HandleFunc("POST /admin/logout-all", h.AdminLogoutAll)
+
+	// Activity log (filterable by ?type= and ?action=)
+	adminMux.HandleFunc("GET /admin/activity", h.AdminActivityList)
+
+	// ============================================
+	// ADMIN PAGE ROUTES
+	// ============================================
+
+	// Posts
+	adminMux.HandleFunc("GET /admin/posts", h.AdminPostsList)
+	adminMux.HandleFunc("POST /admin/posts", h.AdminPostCreate)
+	adminMux.HandleFunc("GET /admin/posts/new", h.AdminPostNew)
+	adminMux.HandleFunc("GET /admin/posts/export.zip", h.AdminPostsExportZip)
+	adminMux.HandleFunc("GET /admin/posts/trash", h.AdminPostsTrash)
+	adminMux.HandleFunc("POST /admin/posts/trash/{id}/restore", h.AdminPostRestore)
+	adminMux.HandleFunc("DELETE /admin/posts/trash/{id}", h.AdminPostPurge)
+	adminMux.HandleFunc("GET /admin/posts/{slug}/edit", h.AdminPostEdit)
+	adminMux.HandleFunc("POST /admin/posts/{slug}", h.AdminPostUpdate)
+	adminMux.HandleFunc("DELETE /admin/posts/{slug}", h.AdminPostDelete)
+	adminMux.HandleFunc("POST /admin/posts/{slug}/duplicate", h.AdminPostDuplicate)
+	adminMux.HandleFunc("PATCH /admin/posts/{slug}/autosave", h.AdminPostAutosave)
+	adminMux.HandleFunc("GET /admin/posts/{slug}/export.md", h.AdminPostExportMarkdown)
+
+	// Bookmarks
+	adminMux.HandleFunc("GET /admin/bookmarks", h.AdminBookmarksList)
+	adminMux.HandleFunc("POST /admin/bookmarks", h.AdminBookmarkCreate)
+	adminMux.HandleFunc("GET /admin/bookmarks/new", h.AdminBookmarkNew)
+	adminMux.HandleFunc("GET /admin/bookmarks/{id}/edit", h.AdminBookmarkEdit)
+	adminMux.HandleFunc("GET /admin/bookmarks/{id}/markdown", h.AdminBookmarkMarkdown)
+	adminMux.HandleFunc("POST /admin/bookmarks/{id}", h.AdminBookmarkUpdate)
+	adminMux.HandleFunc("DELETE /admin/bookmarks/{id}", h.AdminBookmarkDelete)
+
+	// Import
+	adminMux.HandleFunc("GET /admin/import", h.AdminImportPage)
+	adminMux.Handle("POST /admin/import", importLimiter.Limit(http.HandlerFunc(h.AdminImportBookmarks)))
+	adminMux.Handle("POST /admin/import/preview", importLimiter.Limit(http.HandlerFunc(h.AdminImportPreview)))
+	adminMux.Handle("POST /admin/import/commit", importLimiter.Limit(http.HandlerFunc(h.AdminImportCommit)))
+	adminMux.Handle("POST /admin/import/site", importLimiter.Limit(http.HandlerFunc(h.AdminImportSite)))
+	adminMux.HandleFunc("GET /admin/export", h.AdminExportSite)
+
+	// Collections (CRUD only - list is now part of bookmarks board view)
+	adminMux.HandleFunc("POST /admin/collections", h.AdminCollectionCreate)
+	adminMux.HandleFunc("POST /admin/collections/{id}", h.AdminCollectionUpdate)
+	adminMux.HandleFunc("DELETE /admin/collections/{id}", h.AdminCollectionDelete)
+	adminMux.HandleFunc("GET /admin/collections/{id}/opml", h.AdminExportCollectionOPML)
+	adminMux.Handle("POST /admin/collections/{id}/opml", importLimiter.Limit(http.HandlerFunc(h.AdminImportCollectionOPML)))
+
+	// Tags
+	adminMux.HandleFunc("GET /admin/tags", h.AdminTagsList)
+	adminMux.HandleFunc("POST /admin/tags/merge", h.AdminTagMerge)
+	adminMux.HandleFunc("DELETE /admin/tags/{id}", h.AdminTagDelete)
+
+	// Search report
+	if cfg.FeatureEnabled(config.FeatureSearch) {
+		adminMux.HandleFunc("GET /admin/searches", h.AdminSearchGaps)
+	}
+
+	// Session management
+	adminMux.HandleFunc("GET /admin/sessions", h.AdminSessionsList)
+	adminMux.HandleFunc("POST /admin/sessions/revoke-others", h.AdminSessionsRevokeOthers)
+	adminMux.HandleFunc("DELETE /admin/sessions/{handle}", h.AdminSessionRevoke)
+
+	// Account
+	adminMux.HandleFunc("GET /admin/account", h.AdminAccountPage)
+	adminMux.HandleFunc("POST /admin/account/password", h.AdminChangePassword)
+
+	// ============================================
+	// ADMIN HTMX PARTIAL ROUTES
+	// ============================================
+
+	// Posts (HTMX)
+	adminMux.HandleFunc("POST /admin/htmx/posts/{id}/toggle-draft", h.AdminTogglePostDraft)
+	adminMux.HandleFunc("POST /admin/htmx/posts/{id}/toggle-featured", h.AdminTogglePostFeatured)
+	adminMux.HandleFunc("GET /admin/htmx/posts/slug-suggest", h.HTMXPostSlugSuggest)
+	adminMux.HandleFunc("POST /admin/htmx/posts/{id}/preview-link", h.HTMXPostPreviewLink)
+
+	// Bookmarks (HTMX)
+	adminMux.HandleFunc("GET /admin/htmx/bookmarks/view", h.HTMXBookmarksView)
+	adminMux.HandleFunc("GET /admin/htmx/bookmarks/new-drawer", h.HTMXAdminBookmarkNewDrawer)
+	adminMux.HandleFunc("GET /admin/htmx/bookmarks/{id}/edit-drawer", h.HTMXAdminBookmarkEditDrawer)
+	adminMux.Handle("POST /admin/htmx/bookmarks/fetch-metadata", metadataLimiter.Limit(http.HandlerFunc(h.AdminBookmarkFetchMetadata)))
+	adminMux.HandleFunc("POST /admin/htmx/bookmarks/apply-metadata", h.AdminBookmarkApplyMetadata)
+	adminMux.Handle("GET /admin/htmx/bookmarks/refresh-all", metadataLimiter.Limit(http.HandlerFunc(h.AdminRefreshAllMetadata)))
+	adminMux.Handle("POST /admin/htmx/bookmarks/{id}/refresh", metadataLimiter.Limit(http.HandlerFunc(h.AdminRefreshBookmarkMetadata)))
+	adminMux.HandleFunc("POST /admin/htmx/bookmarks/{id}/toggle-public", h.AdminToggleBookmarkPublic)
+	adminMux.HandleFunc("POST /admin/htmx/bookmarks/{id}/toggle-favorite", h.AdminToggleBookmarkFavorite)
+	adminMux.HandleFunc("POST /admin/htmx/bookmarks/{id}/toggle-archived", h.AdminToggleBookmarkArchived)
+	adminMux.HandleFunc("POST /admin/htmx/bookmarks/{id}/collection", h.AdminUpdateBookmarkCollection)
+	adminMux.HandleFunc("POST /admin/htmx/bookmarks/bulk/move", h.AdminBulkMoveBookmarks)
+	adminMux.HandleFunc("POST /admin/htmx/bookmarks/bulk/move/undo", h.AdminUndoBulkMove)
+	adminMux.HandleFunc("DELETE /admin/htmx/bookmarks/bulk/delete", h.AdminBulkDeleteBookmarks)
+	adminMux.HandleFunc("POST /admin/htmx/bookmarks/bulk-tag", h.AdminBulkTagBookmarks)
+
+	// Collections (HTMX)
+	adminMux.HandleFunc("GET /admin/htmx/collections/new-drawer", h.HTMXAdminCollectionNewDrawer)
+	adminMux.HandleFunc("GET /admin/htmx/collections/{id}/edit-drawer", h.HTMXAdminCollectionEditDrawer)
+	adminMux.HandleFunc("GET /admin/htmx/collections/{id}/bookmarks", h.AdminCollectionBookmarks)
+	adminMux.HandleFunc("POST /admin/htmx/collections/{id}/toggle-public", h.AdminToggleCollectionPublic)
+	adminMux.HandleFunc("POST /admin/htmx/collections/{id}/move", h.AdminMoveCollection)
+
+	// Tags (HTMX)
+	adminMux.HandleFunc("POST /admin/htmx/tags/create-inline", h.AdminTagCreateInline)
+	adminMux.HandleFunc("GET /admin/htmx/tags/{id}/posts", h.AdminTagPosts)
+
+	// Uploads (for post editor image upload)
+	adminMux.HandleFunc("POST /admin/uploads/image", h.AdminUploadImage)
+	adminMux.HandleFunc("DELETE /admin/uploads/image", h.AdminDeleteImage)
+
+	// Test routes (development only)
+	if cfg.IsDevelopment() {
+		adminMux.HandleFunc("GET /admin/test/errors", h.TestErrorPage)
+		adminMux.HandleFunc("GET /admin/test/error-500", h.TestError500)
+		adminMux.HandleFunc("GET /admin/test/slow", h.TestSlow)
+		adminMux.HandleFunc("GET /admin/test/success", h.TestSuccess)
+		adminMux.HandleFunc("GET /admin/test/retry-workflow", h.TestRetryWorkflow)
+		adminMux.HandleFunc("POST /admin/test/reset-retry", h.TestResetRetry)
+	}
+
+	// Wrap admin routes with CSRF + Auth middleware
+	// Order: CSRF runs first (sets token), then Auth checks session
+	idleTimeout := time.Duration(cfg.SessionIdleTimeoutMinutes) * time.Minute
+	authMiddleware := middleware.Auth(h.AuthService(), idleTimeout)
+	// The IP allowlist runs before auth so blocked IPs never reach it
+	// NestedRoute gives each admin route its own metrics label
+	protectedAdmin := adminAllowlist(csrfMiddleware(authMiddleware(middleware.NestedRoute(adminMux))))
+	mux.Handle("/admin", protectedAdmin)
+	mux.Handle("/admin/", protectedAdmin)
+
+	// Prometheus metrics: open in development, admin session required otherwise
+	if metrics != nil {
+		if cfg.IsDevelopment() {
+			mux.Handle("GET /metrics", metrics.Handler())
+		} else {
+			mux.Handle("GET /metrics", authMiddleware(metrics.Handler()))
+		}
+	}
+
+	return mux
+}
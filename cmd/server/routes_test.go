@@ -0,0 +1,113 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/config"
+	"github.com/EC-9624/0xec.dev/internal/database"
+	"github.com/EC-9624/0xec.dev/internal/handlers"
+	"github.com/EC-9624/0xec.dev/internal/middleware"
+)
+
+// testRouter builds the router with only the given features enabled
+func testRouter(t *testing.T, features ...string) *http.ServeMux {
+	t.Helper()
+
+	db, err := database.Init(filepath.Join(t.TempDir(), "site.db"), database.Options{Migrate: true})
+	if err != nil {
+		t.Fatalf("database.Init() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	cfg := config.Load()
+	cfg.Features = make(map[string]bool)
+	for _, name := range features {
+		cfg.Features[name] = true
+	}
+
+	var metrics *middleware.Metrics
+	if cfg.FeatureEnabled(config.FeatureMetrics) {
+		metrics = middleware.NewMetrics()
+	}
+
+	return newRouter(cfg, handlers.NewWithDB(cfg, db), newHealthChecker(db), metrics)
+}
+
+// routePattern returns the pattern mux would serve the request with, or ""
+// when no route matches
+func routePattern(mux *http.ServeMux, method, path string) string {
+	_, pattern := mux.Handler(httptest.NewRequest(method, path, nil))
+	return pattern
+}
+
+func TestNewRouter_EnabledFeatures(t *testing.T) {
+	mux := testRouter(t, config.FeatureAPI, config.FeatureMetrics, config.FeatureSearch)
+
+	if got := routePattern(mux, http.MethodGet, "/api/bookmarks"); got != "/api/" {
+		t.Errorf("/api/bookmarks pattern = %q, want %q", got, "/api/")
+	}
+	if got := routePattern(mux, http.MethodGet, "/metrics"); got != "GET /metrics" {
+		t.Errorf("/metrics pattern = %q, want %q", got, "GET /metrics")
+	}
+	if got := routePattern(mux, http.MethodGet, "/search"); got != "GET /search" {
+		t.Errorf("/search pattern = %q, want %q", got, "GET /search")
+	}
+}
+
+func TestNewRouter_SearchRateLimited(t *testing.T) {
+	t.Setenv("SEARCH_RATE_LIMIT_BURST", "2")
+	mux := testRouter(t, config.FeatureSearch)
+
+	var codes []int
+	for range 3 {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/search?q=sqlite", nil))
+		codes = append(codes, rec.Code)
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusOK || codes[2] != http.StatusTooManyRequests {
+		t.Errorf("search statuses = %v, want [200 200 429]", codes)
+	}
+}
+
+func TestNewRouter_DisabledFeatures(t *testing.T) {
+	mux := testRouter(t)
+
+	if got := routePattern(mux, http.MethodGet, "/api/bookmarks"); got != "" {
+		t.Errorf("/api/bookmarks pattern = %q, want no route", got)
+	}
+	if got := routePattern(mux, http.MethodGet, "/metrics"); got != "" {
+		t.Errorf("/metrics pattern = %q, want no route", got)
+	}
+	if got := routePattern(mux, http.MethodGet, "/search"); got != "" {
+		t.Errorf("/search pattern = %q, want no route", got)
+	}
+
+	// Routes outside optional features are always registered
+	if got := routePattern(mux, http.MethodGet, "/posts"); got != "GET /posts" {
+		t.Errorf("/posts pattern = %q, want %q", got, "GET /posts")
+	}
+}
+
+func TestNewRouter_CORSOnlyOnAPI(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://tools.example.com")
+	mux := testRouter(t, config.FeatureAPI)
+
+	preflight := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, path, nil)
+		req.Header.Set("Origin", "https://tools.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if got := preflight("/api/bookmarks").Header().Get("Access-Control-Allow-Origin"); got != "https://tools.example.com" {
+		t.Errorf("/api/ Allow-Origin = %q, want the allowed origin", got)
+	}
+	if got := preflight("/admin/login").Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("/admin/login Allow-Origin = %q, want unset", got)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 // Default insecure values that must be changed in production
@@ -23,10 +25,179 @@ type Config struct {
 	BaseURL     string
 	Environment string
 
+	// AutoMigrate applies pending schema migrations on startup. With it off,
+	// migrations are run with cmd/migrate and the server only warns about
+	// pending ones.
+	AutoMigrate bool
+
+	// DBMaxOpenConns and DBMaxIdleConns size the SQLite connection pool.
+	// SQLite takes one writer at a time regardless; more connections only
+	// add concurrent readers, and writers wait on the busy timeout.
+	DBMaxOpenConns int
+	DBMaxIdleConns int
+
+	// Features holds the optional features switched on for this
+	// deployment, from the comma-separated FEATURES list. When FEATURES is
+	// unset every known feature is on.
+	Features map[string]bool
+
+	// ImageBaseURL optionally serves stored images from a CDN. When set,
+	// /images/{id} URLs in rendered output are rewritten to this base; the
+	// app still serves them locally as a fallback.
+	ImageBaseURL string
+
+	// ThumbnailMaxWidth is the widest a generated cover thumbnail may be.
+	// Smaller images are stored as-is without a thumbnail.
+	ThumbnailMaxWidth int
+
+	// MaxUploadBytes caps the body of every form submission, uploads
+	// included. Larger requests are rejected with a 413.
+	MaxUploadBytes int64
+
+	// SessionCleanupSchedule is when expired sessions are purged, as a cron
+	// expression or @every interval (empty disables the job)
+	SessionCleanupSchedule string
+
+	// PostTrashRetentionDays is how long deleted posts stay in the trash
+	// before PostTrashPurgeSchedule removes them for good
+	PostTrashRetentionDays int
+	PostTrashPurgeSchedule string
+
+	// RememberSessionDays is how long a "remember me" login stays valid
+	RememberSessionDays int
+
+	// SessionIdleTimeoutMinutes ends sessions unused for this long, before
+	// their absolute expiry (0 disables the idle check). "Remember me"
+	// sessions are exempt.
+	SessionIdleTimeoutMinutes int
+
+	// LoginMaxAttempts failed logins in a row lock the account for
+	// LoginLockoutMinutes (0 attempts disables lockout)
+	LoginMaxAttempts    int
+	LoginLockoutMinutes int
+
 	// Pagination settings
-	BookmarksPerPage    int
-	AdminBookmarksLimit int
-	PostsPerPage        int
+	BookmarksPerPage      int
+	AdminBookmarksPerPage int
+	PostsPerPage          int
+
+	// DashboardStatsTTLSeconds is how long the admin dashboard counts are
+	// cached (0 disables caching)
+	DashboardStatsTTLSeconds int
+
+	// UpdatedRecentlyDays is how long after a meaningful edit a post shows
+	// the "Updated recently" badge
+	UpdatedRecentlyDays int
+
+	// FeaturedPostsMax caps how many posts can be featured on the home page
+	// at once (0 removes the cap)
+	FeaturedPostsMax int
+
+	// Home page sections: the intro under the heading, whether featured
+	// posts are shown, and how many recent posts and bookmarks are listed
+	// (0 hides the section)
+	HomeIntro           string
+	HomeShowFeatured    bool
+	HomeRecentPosts     int
+	HomeRecentBookmarks int
+
+	// FeedTagLinks appends links to a post's tag archives to its feed entry
+	FeedTagLinks bool
+
+	// RSSItemLimit caps the number of entries in post and bookmark feeds.
+	// Zero keeps each feed's built-in default.
+	RSSItemLimit int
+
+	// RSSFullContent puts each entry's full HTML body in feeds instead of
+	// only its summary
+	RSSFullContent bool
+
+	// SearchLogging records anonymized search queries for the "searches
+	// with no results" report. Logged searches are kept for
+	// SearchLogRetentionDays, then SearchLogPruneSchedule deletes them.
+	SearchLogging          bool
+	SearchLogRetentionDays int
+	SearchLogPruneSchedule string
+
+	// PostCardImages serves a generated title card at /posts/{slug}/card.png
+	// and uses it as the share image for posts without a cover image. The
+	// card font covers Latin text only; other titles get a site-name card.
+	PostCardImages bool
+
+	// DefaultShareImage is the link preview image for pages without one of
+	// their own. A path is resolved against BaseURL.
+	DefaultShareImage string
+
+	// Public API rate limits, separate from the login limiter. Requests are
+	// budgeted per client IP.
+	APIRateLimitPerMinute int
+	APIRateLimitBurst     int
+
+	// Public search rate limit, per client IP. Every search runs several
+	// LIKE scans and may be logged.
+	SearchRateLimitPerMinute int
+	SearchRateLimitBurst     int
+
+	// CORSAllowedOrigins may call the JSON API from a browser; "*" allows
+	// any origin. CORSAllowCredentials lets those calls carry cookies and
+	// can't be combined with "*".
+	CORSAllowedOrigins   []string
+	CORSAllowCredentials bool
+
+	// ContentSecurityPolicy overrides the default policy. CSPReportOnly
+	// sends it as report-only, so violations are logged but not blocked.
+	ContentSecurityPolicy string
+	CSPReportOnly         bool
+
+	// TrustProxy trusts X-Forwarded-For/X-Real-IP for the client IP. Only
+	// enable it behind a reverse proxy that sets them.
+	TrustProxy bool
+
+	// AdminAllowedIPs restricts /admin to these CIDR ranges or addresses.
+	// Empty allows any IP.
+	AdminAllowedIPs []string
+
+	// Per-user limits on expensive admin endpoints: fetching bookmark
+	// metadata (single fetch, refresh and refresh-all) and bookmark import
+	MetadataRateLimitPerMinute int
+	MetadataRateLimitBurst     int
+	ImportRateLimitPerMinute   int
+	ImportRateLimitBurst       int
+
+	// ImportFolderDepth is how many levels of folders in an imported
+	// bookmarks file become (nested) collections. 0 imports everything into
+	// the collection picked on the import form.
+	ImportFolderDepth int
+
+	// MetadataFetchAttempts is how many times a metadata fetch is tried when
+	// it times out or the site returns a 5xx. Retries wait
+	// MetadataRetryDelayMS, doubled after each attempt, with jitter.
+	MetadataFetchAttempts int
+	MetadataRetryDelayMS  int
+
+	// MetadataRespectRobots skips metadata fetches a site's robots.txt
+	// disallows. MetadataMaxBodyKB caps how much of a page is downloaded.
+	MetadataRespectRobots bool
+	MetadataMaxBodyKB     int
+
+	// TrackingParams are the query parameters ignored when comparing
+	// bookmark URLs for duplicates; a trailing * matches any suffix. Nil
+	// uses the built-in list.
+	TrackingParams []string
+
+	// TrailingSlash is the canonical URL policy: "strip" redirects /path/
+	// to /path, "add" the other way round, and "off" redirects neither.
+	// Both collapse duplicate slashes.
+	TrailingSlash string
+
+	// SlowRequestThresholdMS is how long a request may take before it is
+	// logged at WARN as slow. 0 disables slow request logging.
+	SlowRequestThresholdMS int
+
+	// ValidateRedirects makes the /go/{id} click tracker refuse to redirect
+	// to stored URLs that aren't public http(s) targets. When false the
+	// tracker redirects to any stored http(s) URL.
+	ValidateRedirects bool
 }
 
 // Load loads configuration from environment variables with sensible defaults
@@ -39,11 +210,88 @@ func Load() *Config {
 		AdminPass:   getEnv("ADMIN_PASS", defaultAdminPass),
 		BaseURL:     getEnv("BASE_URL", "http://localhost:8080"),
 		Environment: getEnv("ENVIRONMENT", "development"),
+		AutoMigrate: getEnvBool("AUTO_MIGRATE", true),
+
+		DBMaxOpenConns: getEnvInt("DB_MAX_OPEN_CONNS", 10),
+		DBMaxIdleConns: getEnvInt("DB_MAX_IDLE_CONNS", 10),
+
+		Features: parseFeatures(getEnv("FEATURES", strings.Join(KnownFeatures, ","))),
+
+		ImageBaseURL:      getEnv("IMAGE_BASE_URL", ""),
+		ThumbnailMaxWidth: getEnvInt("THUMBNAIL_MAX_WIDTH", 480),
+		MaxUploadBytes:    int64(getEnvInt("MAX_UPLOAD_MB", 32)) << 20,
+
+		RememberSessionDays:       getEnvInt("REMEMBER_SESSION_DAYS", 30),
+		SessionIdleTimeoutMinutes: getEnvInt("SESSION_IDLE_TIMEOUT_MINUTES", 120),
+		SessionCleanupSchedule:    getEnv("SESSION_CLEANUP_SCHEDULE", "@hourly"),
+
+		PostTrashRetentionDays: getEnvInt("POST_TRASH_RETENTION_DAYS", 30),
+		PostTrashPurgeSchedule: getEnv("POST_TRASH_PURGE_SCHEDULE", "@daily"),
+
+		LoginMaxAttempts:    getEnvInt("LOGIN_MAX_ATTEMPTS", 5),
+		LoginLockoutMinutes: getEnvInt("LOGIN_LOCKOUT_MINUTES", 15),
 
 		// Pagination defaults
-		BookmarksPerPage:    getEnvInt("BOOKMARKS_PER_PAGE", 24),
-		AdminBookmarksLimit: getEnvInt("ADMIN_BOOKMARKS_LIMIT", 500),
-		PostsPerPage:        getEnvInt("POSTS_PER_PAGE", 100),
+		BookmarksPerPage:      getEnvInt("BOOKMARKS_PER_PAGE", 24),
+		AdminBookmarksPerPage: getEnvInt("ADMIN_BOOKMARKS_PER_PAGE", 100),
+		PostsPerPage:          getEnvInt("POSTS_PER_PAGE", 100),
+
+		UpdatedRecentlyDays: getEnvInt("UPDATED_RECENTLY_DAYS", 30),
+		FeaturedPostsMax:    getEnvInt("FEATURED_POSTS_MAX", 3),
+
+		HomeIntro:           getEnv("HOME_INTRO", "A personal space for writing and collecting interesting things from the web."),
+		HomeShowFeatured:    getEnvBool("HOME_SHOW_FEATURED", true),
+		HomeRecentPosts:     getEnvInt("HOME_RECENT_POSTS", 5),
+		HomeRecentBookmarks: getEnvInt("HOME_RECENT_BOOKMARKS", 6),
+
+		DashboardStatsTTLSeconds: getEnvInt("DASHBOARD_STATS_TTL_SECONDS", 30),
+
+		FeedTagLinks:   getEnvBool("FEED_TAG_LINKS", false),
+		RSSItemLimit:   getEnvInt("RSS_ITEM_LIMIT", 0),
+		RSSFullContent: getEnvBool("RSS_FULL_CONTENT", false),
+		PostCardImages: getEnvBool("POST_CARD_IMAGES", false),
+		SearchLogging:  getEnvBool("SEARCH_LOGGING", true),
+
+		SearchLogRetentionDays: getEnvInt("SEARCH_LOG_RETENTION_DAYS", 90),
+		SearchLogPruneSchedule: getEnv("SEARCH_LOG_PRUNE_SCHEDULE", "@daily"),
+
+		DefaultShareImage: getEnv("DEFAULT_SHARE_IMAGE", ""),
+
+		// API rate limit defaults
+		APIRateLimitPerMinute: getEnvInt("API_RATE_LIMIT_PER_MINUTE", 60),
+		APIRateLimitBurst:     getEnvInt("API_RATE_LIMIT_BURST", 20),
+
+		// Search rate limit defaults
+		SearchRateLimitPerMinute: getEnvInt("SEARCH_RATE_LIMIT_PER_MINUTE", 30),
+		SearchRateLimitBurst:     getEnvInt("SEARCH_RATE_LIMIT_BURST", 10),
+
+		CORSAllowedOrigins:   getEnvList("CORS_ALLOWED_ORIGINS", nil),
+		CORSAllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", false),
+
+		ContentSecurityPolicy: getEnv("CONTENT_SECURITY_POLICY", ""),
+		CSPReportOnly:         getEnvBool("CSP_REPORT_ONLY", false),
+
+		TrustProxy:      getEnvBool("TRUST_PROXY", false),
+		AdminAllowedIPs: getEnvList("ADMIN_ALLOWED_IPS", nil),
+
+		// Expensive admin endpoint limits (per user)
+		MetadataRateLimitPerMinute: getEnvInt("METADATA_RATE_LIMIT_PER_MINUTE", 30),
+		MetadataRateLimitBurst:     getEnvInt("METADATA_RATE_LIMIT_BURST", 10),
+		ImportRateLimitPerMinute:   getEnvInt("IMPORT_RATE_LIMIT_PER_MINUTE", 2),
+		ImportRateLimitBurst:       getEnvInt("IMPORT_RATE_LIMIT_BURST", 3),
+		ImportFolderDepth:          getEnvInt("IMPORT_FOLDER_DEPTH", 1),
+
+		MetadataFetchAttempts: getEnvInt("METADATA_FETCH_ATTEMPTS", 3),
+		MetadataRetryDelayMS:  getEnvInt("METADATA_RETRY_DELAY_MS", 500),
+		MetadataRespectRobots: getEnvBool("METADATA_RESPECT_ROBOTS", true),
+		MetadataMaxBodyKB:     getEnvInt("METADATA_MAX_BODY_KB", 512),
+
+		TrackingParams: getEnvList("TRACKING_PARAMS", nil),
+
+		ValidateRedirects: getEnvBool("VALIDATE_REDIRECTS", false),
+
+		SlowRequestThresholdMS: getEnvInt("SLOW_REQUEST_THRESHOLD_MS", 1000),
+		TrailingSlash:          getEnv("TRAILING_SLASH", "strip"),
 	}
 }
 
@@ -78,6 +326,20 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.CORSAllowCredentials && slices.Contains(c.CORSAllowedOrigins, "*") {
+		return fmt.Errorf("CORS_ALLOW_CREDENTIALS can't be used with CORS_ALLOWED_ORIGINS=*; list the trusted origins instead")
+	}
+
+	if unknown := c.UnknownFeatures(); len(unknown) > 0 {
+		slog.Warn("ignoring unknown features in FEATURES", "features", unknown, "known", KnownFeatures)
+	}
+
+	switch c.TrailingSlash {
+	case "strip", "add", "off":
+	default:
+		slog.Warn("unknown TRAILING_SLASH, stripping trailing slashes", "value", c.TrailingSlash)
+	}
+
 	// Warn about insecure settings in non-production environments
 	if !c.IsProduction() && !c.IsDevelopment() {
 		if c.SessionKey == defaultSessionKey {
@@ -115,3 +377,28 @@ func getEnvInt(key string, fallback int) int {
 	}
 	return fallback
 }
+
+// getEnvList splits a comma-separated variable, dropping blank entries.
+// A set but empty variable gives an empty, non-nil list.
+func getEnvList(key string, fallback []string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	list := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,29 @@
+package config
+
+import "testing"
+
+func TestValidate_CORSCredentialsWithWildcard(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     []string
+		credentials bool
+		wantErr     bool
+	}{
+		{"wildcard without credentials", []string{"*"}, false, false},
+		{"listed origins with credentials", []string{"https://tools.example.com"}, true, false},
+		{"wildcard with credentials", []string{"https://tools.example.com", "*"}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				TrailingSlash:        "strip",
+				CORSAllowedOrigins:   tt.origins,
+				CORSAllowCredentials: tt.credentials,
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
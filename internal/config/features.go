@@ -0,0 +1,64 @@
+package config
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// Optional features that can be switched per deployment with FEATURES
+const (
+	FeatureAPI     = "api"     // JSON endpoints under /api/
+	FeatureMetrics = "metrics" // Prometheus /metrics and request instrumentation
+	FeatureSearch  = "search"  // sitewide /search, search logging and the admin search report
+)
+
+// KnownFeatures lists every feature name FEATURES accepts
+var KnownFeatures = []string{FeatureAPI, FeatureMetrics, FeatureSearch}
+
+// parseFeatures parses a comma-separated feature list such as
+// "api,search". Names are case-insensitive; blanks are ignored.
+func parseFeatures(list string) map[string]bool {
+	features := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			features[name] = true
+		}
+	}
+	return features
+}
+
+// FeatureEnabled reports whether the named optional feature is switched on
+func (c *Config) FeatureEnabled(name string) bool {
+	return c.Features[name]
+}
+
+// featuresContextKey holds the feature check of a request
+type featuresContextKey struct{}
+
+// WithFeatures returns ctx carrying the check templates use to hide
+// disabled features, usually a Config's FeatureEnabled
+func WithFeatures(ctx context.Context, enabled func(name string) bool) context.Context {
+	return context.WithValue(ctx, featuresContextKey{}, enabled)
+}
+
+// FeatureEnabledIn reports whether the feature check carried by ctx allows
+// the named feature. Without a check every feature shows.
+func FeatureEnabledIn(ctx context.Context, name string) bool {
+	enabled, ok := ctx.Value(featuresContextKey{}).(func(name string) bool)
+	return !ok || enabled(name)
+}
+
+// UnknownFeatures returns the enabled feature names that don't match any
+// known feature, usually typos in FEATURES
+func (c *Config) UnknownFeatures() []string {
+	var unknown []string
+	for name, enabled := range c.Features {
+		if enabled && !slices.Contains(KnownFeatures, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	slices.Sort(unknown)
+	return unknown
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestParseFeatures(t *testing.T) {
+	features := parseFeatures(" API, search ,,Search")
+
+	cfg := &Config{Features: features}
+	if !cfg.FeatureEnabled(FeatureAPI) {
+		t.Error("FeatureEnabled(api) = false, want true")
+	}
+	if !cfg.FeatureEnabled(FeatureSearch) {
+		t.Error("FeatureEnabled(search) = false, want true")
+	}
+	if cfg.FeatureEnabled(FeatureMetrics) {
+		t.Error("FeatureEnabled(metrics) = true, want false")
+	}
+	if len(features) != 2 {
+		t.Errorf("parseFeatures() = %v, want 2 features", features)
+	}
+}
+
+func TestParseFeatures_Empty(t *testing.T) {
+	if features := parseFeatures(""); len(features) != 0 {
+		t.Errorf("parseFeatures(\"\") = %v, want none", features)
+	}
+}
+
+func TestUnknownFeatures(t *testing.T) {
+	cfg := &Config{Features: parseFeatures("api,serach,comments")}
+
+	want := []string{"comments", "serach"}
+	if got := cfg.UnknownFeatures(); !slices.Equal(got, want) {
+		t.Errorf("UnknownFeatures() = %v, want %v", got, want)
+	}
+}
+
+func TestFeatureEnabledIn(t *testing.T) {
+	ctx := context.Background()
+	if !FeatureEnabledIn(ctx, FeatureSearch) {
+		t.Error("FeatureEnabledIn() without a check = false, want every feature shown")
+	}
+
+	cfg := &Config{Features: map[string]bool{FeatureAPI: true}}
+	ctx = WithFeatures(ctx, cfg.FeatureEnabled)
+	if !FeatureEnabledIn(ctx, FeatureAPI) {
+		t.Error("FeatureEnabledIn(api) = false, want true")
+	}
+	if FeatureEnabledIn(ctx, FeatureSearch) {
+		t.Error("FeatureEnabledIn(search) = true, want false")
+	}
+}
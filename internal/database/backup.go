@@ -0,0 +1,99 @@
+package database
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BackupFileName returns a timestamped name for a backup taken at t
+func BackupFileName(t time.Time, compress bool) string {
+	name := "site-" + t.Format("20060102-150405") + ".db"
+	if compress {
+		name += ".gz"
+	}
+	return name
+}
+
+// Backup writes a consistent copy of the open database to dest using
+// VACUUM INTO, which is safe while the server is writing. With compress set
+// the copy is gzipped. dest must not already exist.
+func Backup(ctx context.Context, db *sql.DB, dest string, compress bool) error {
+	dir := filepath.Dir(dest)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("backup directory %s is not writable: %w", dir, err)
+	}
+	if err := checkWritable(dir); err != nil {
+		return fmt.Errorf("backup directory %s is not writable: %w", dir, err)
+	}
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("backup file %s already exists", dest)
+	}
+
+	if !compress {
+		return vacuumInto(ctx, db, dest)
+	}
+
+	// SQLite can't write compressed output, so copy to a temporary file
+	// next to the destination and gzip that
+	tmp := dest + ".tmp"
+	if err := vacuumInto(ctx, db, tmp); err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	if err := gzipFile(tmp, dest); err != nil {
+		os.Remove(dest)
+		return fmt.Errorf("failed to compress backup: %w", err)
+	}
+	return nil
+}
+
+func vacuumInto(ctx context.Context, db *sql.DB, path string) error {
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	return nil
+}
+
+// checkWritable reports whether a file can be created in dir. MkdirAll
+// succeeds on existing read-only directories, so this catches them up front
+// rather than through a less clear SQLite error.
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".backup-check-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+func gzipFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := gzip.NewWriter(out)
+	zw.Name = strings.TrimSuffix(filepath.Base(dest), ".gz")
+	if _, err := io.Copy(zw, in); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Init(filepath.Join(t.TempDir(), "site.db"), Options{Migrate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`INSERT INTO tags (name, slug) VALUES ('Go', 'go')`); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// countTags opens the database file at path and counts its tags
+func countTags(t *testing.T, path string) int {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM tags`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestBackupFileName(t *testing.T) {
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	if got := BackupFileName(at, false); got != "site-20260304-050607.db" {
+		t.Errorf("BackupFileName = %q", got)
+	}
+	if got := BackupFileName(at, true); got != "site-20260304-050607.db.gz" {
+		t.Errorf("BackupFileName(compressed) = %q", got)
+	}
+}
+
+func TestBackup(t *testing.T) {
+	db := openTestDB(t)
+	dest := filepath.Join(t.TempDir(), "backups", "copy.db")
+
+	if err := Backup(context.Background(), db, dest, false); err != nil {
+		t.Fatal(err)
+	}
+	if n := countTags(t, dest); n != 1 {
+		t.Errorf("backup has %d tags, want 1", n)
+	}
+
+	if err := Backup(context.Background(), db, dest, false); err == nil {
+		t.Error("expected error when the backup file already exists")
+	}
+}
+
+func TestBackup_Gzip(t *testing.T) {
+	db := openTestDB(t)
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "copy.db.gz")
+
+	if err := Backup(context.Background(), db, dest, true); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := filepath.Join(dir, "restored.db")
+	out, err := os.Create(restored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.Copy(out, zr); err != nil {
+		t.Fatal(err)
+	}
+	out.Close()
+
+	if n := countTags(t, restored); n != 1 {
+		t.Errorf("restored backup has %d tags, want 1", n)
+	}
+	if _, err := os.Stat(dest + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary uncompressed copy was left behind")
+	}
+}
+
+func TestBackup_UnwritableDir(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	db := openTestDB(t)
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(dir, 0755) })
+
+	err := Backup(context.Background(), db, filepath.Join(dir, "copy.db"), false)
+	if err == nil || !strings.Contains(err.Error(), "not writable") {
+		t.Errorf("error = %v, want not writable", err)
+	}
+}
@@ -2,121 +2,86 @@ package database
 
 import (
 	"database/sql"
-	_ "embed"
 	"fmt"
-	"log/slog"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
-//go:embed migrations/001_initial.sql
-var initialMigration string
+// DefaultBusyTimeout is how long a connection waits for another writer to
+// finish before failing with "database is locked"
+const DefaultBusyTimeout = 5 * time.Second
 
-//go:embed migrations/002_activities.sql
-var activitiesMigration string
+// Options configures how Init opens the database
+type Options struct {
+	// Migrate applies pending migrations after connecting
+	Migrate bool
 
-//go:embed migrations/003_remove_collection_icon.sql
-var removeCollectionIconMigration string
+	// MaxOpenConns and MaxIdleConns size the connection pool; zero keeps
+	// the database/sql defaults. SQLite allows a single writer at a time
+	// even in WAL mode, so extra connections only help concurrent reads;
+	// writers queue on the busy timeout.
+	MaxOpenConns int
+	MaxIdleConns int
 
-// migration represents a database migration
-type migration struct {
-	name string
-	sql  string
+	// BusyTimeout defaults to DefaultBusyTimeout
+	BusyTimeout time.Duration
 }
 
-// migrations list in order of execution
-var migrations = []migration{
-	{"001_initial", initialMigration},
-	{"002_activities", activitiesMigration},
-	{"003_remove_collection_icon", removeCollectionIconMigration},
-}
-
-// Init initializes the database connection and runs migrations.
-// Returns the database connection which the caller is responsible for closing.
-func Init(dbPath string) (*sql.DB, error) {
+// Init initializes the database connection and, with opts.Migrate set,
+// applies pending migrations. Returns the database connection which the
+// caller is responsible for closing.
+func Init(dbPath string, opts Options) (*sql.DB, error) {
 	// Ensure the directory exists
 	dir := filepath.Dir(dbPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	db, err := sql.Open("sqlite3", dbPath+"?_foreign_keys=on")
+	db, err := sql.Open("sqlite3", dsn(dbPath, opts))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	db.SetMaxOpenConns(opts.MaxOpenConns)
+	if opts.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(opts.MaxIdleConns)
+	}
 
 	// Test the connection
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	// Run migrations
-	if err := runMigrations(db); err != nil {
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	if opts.Migrate {
+		if _, err := MigrateUp(db); err != nil {
+			return nil, fmt.Errorf("failed to run migrations: %w", err)
+		}
 	}
 
 	return db, nil
 }
 
-// ensureMigrationsTable creates the migrations tracking table if it doesn't exist
-func ensureMigrationsTable(db *sql.DB) error {
-	_, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS schema_migrations (
-			name TEXT PRIMARY KEY,
-			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)
-	`)
-	return err
-}
-
-// isMigrationApplied checks if a migration has already been applied
-func isMigrationApplied(db *sql.DB, name string) (bool, error) {
-	var count int
-	err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations WHERE name = ?", name).Scan(&count)
-	if err != nil {
-		return false, err
-	}
-	return count > 0, nil
-}
-
-// recordMigration records that a migration has been applied
-func recordMigration(db *sql.DB, name string) error {
-	_, err := db.Exec("INSERT INTO schema_migrations (name) VALUES (?)", name)
-	return err
-}
-
-// runMigrations runs all database migrations that haven't been applied yet
-func runMigrations(db *sql.DB) error {
-	// Ensure migrations table exists
-	if err := ensureMigrationsTable(db); err != nil {
-		return fmt.Errorf("failed to create migrations table: %w", err)
-	}
-
-	for _, m := range migrations {
-		applied, err := isMigrationApplied(db, m.name)
-		if err != nil {
-			return fmt.Errorf("failed to check migration %s: %w", m.name, err)
-		}
-
-		if applied {
-			continue
-		}
-
-		slog.Info("running migration", "name", m.name)
-
-		_, err = db.Exec(m.sql)
-		if err != nil {
-			return fmt.Errorf("failed to run migration %s: %w", m.name, err)
-		}
-
-		if err := recordMigration(db, m.name); err != nil {
-			return fmt.Errorf("failed to record migration %s: %w", m.name, err)
-		}
-
-		slog.Info("migration applied successfully", "name", m.name)
+// dsn builds the connection string. The driver applies these settings to
+// every connection it opens, which a one-off PRAGMA would not:
+//   - WAL lets readers carry on while a write is in progress
+//   - the busy timeout makes a second writer wait instead of failing
+//   - immediate transactions take the write lock up front, since a
+//     transaction that upgrades from read to write can't wait on the
+//     busy timeout and fails straight away
+func dsn(dbPath string, opts Options) string {
+	timeout := opts.BusyTimeout
+	if timeout <= 0 {
+		timeout = DefaultBusyTimeout
 	}
 
-	return nil
+	params := url.Values{}
+	params.Set("_foreign_keys", "on")
+	params.Set("_journal_mode", "WAL")
+	params.Set("_busy_timeout", strconv.FormatInt(timeout.Milliseconds(), 10))
+	params.Set("_txlock", "immediate")
+	return dbPath + "?" + params.Encode()
 }
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInit_Pragmas(t *testing.T) {
+	db, err := Init(filepath.Join(t.TempDir(), "site.db"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var mode string
+	var timeout, fk int
+	if err := db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow("PRAGMA busy_timeout").Scan(&timeout); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow("PRAGMA foreign_keys").Scan(&fk); err != nil {
+		t.Fatal(err)
+	}
+
+	if mode != "wal" {
+		t.Errorf("journal_mode = %q, want wal", mode)
+	}
+	if timeout != int(DefaultBusyTimeout.Milliseconds()) {
+		t.Errorf("busy_timeout = %d, want %d", timeout, DefaultBusyTimeout.Milliseconds())
+	}
+	if fk != 1 {
+		t.Errorf("foreign_keys = %d, want 1", fk)
+	}
+}
+
+func TestInit_ConcurrentWrites(t *testing.T) {
+	db, err := Init(filepath.Join(t.TempDir(), "site.db"), Options{MaxOpenConns: 8, MaxIdleConns: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("CREATE TABLE counter (n INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+
+	const writers, writes = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*writes)
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range writes {
+				// Read then write in one transaction, the pattern that
+				// fails without immediate locking
+				tx, err := db.Begin()
+				if err != nil {
+					errs <- err
+					return
+				}
+				var n int
+				if err := tx.QueryRow("SELECT COUNT(*) FROM counter").Scan(&n); err != nil {
+					tx.Rollback()
+					errs <- err
+					return
+				}
+				// Hold the transaction open so writers overlap
+				time.Sleep(time.Millisecond)
+				if _, err := tx.Exec("INSERT INTO counter (n) VALUES (?)", n); err != nil {
+					tx.Rollback()
+					errs <- err
+					return
+				}
+				if err := tx.Commit(); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent write failed: %v", err)
+	}
+
+	var total int
+	if err := db.QueryRow("SELECT COUNT(*) FROM counter").Scan(&total); err != nil {
+		t.Fatal(err)
+	}
+	if total != writers*writes {
+		t.Errorf("wrote %d rows, want %d", total, writers*writes)
+	}
+}
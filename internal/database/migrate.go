@@ -0,0 +1,228 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// migrationFiles holds the schema migrations. Each NNN_name.sql file is
+// applied in name order; an optional NNN_name.down.sql reverts it.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is a single schema change
+type Migration struct {
+	Name string
+	Up   string
+	Down string // empty when the migration can't be reverted
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Name      string
+	AppliedAt *time.Time // nil when pending
+}
+
+// Migrations returns the embedded migrations in the order they apply
+func Migrations() ([]Migration, error) {
+	return loadMigrations(migrationFiles, "migrations")
+}
+
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*Migration)
+	for _, entry := range entries {
+		file := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(file, ".sql") {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, file))
+		if err != nil {
+			return nil, err
+		}
+
+		name, down := strings.CutSuffix(strings.TrimSuffix(file, ".sql"), ".down")
+		m := byName[name]
+		if m == nil {
+			m = &Migration{Name: name}
+			byName[name] = m
+		}
+		if down {
+			m.Down = string(data)
+		} else {
+			m.Up = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byName))
+	for _, m := range byName {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %s has a down file but no up file", m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Name < migrations[j].Name })
+	return migrations, nil
+}
+
+// ensureMigrationsTable creates the migrations tracking table if it doesn't exist
+func ensureMigrationsTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			name TEXT PRIMARY KEY,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+// appliedMigrations returns when each applied migration ran, by name
+func appliedMigrations(db *sql.DB) (map[string]time.Time, error) {
+	if err := ensureMigrationsTable(db); err != nil {
+		return nil, fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	rows, err := db.Query("SELECT name, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[string]time.Time)
+	for rows.Next() {
+		var name string
+		var at time.Time
+		if err := rows.Scan(&name, &at); err != nil {
+			return nil, err
+		}
+		applied[name] = at
+	}
+	return applied, rows.Err()
+}
+
+// MigrationStatuses lists every known migration with its applied time
+func MigrationStatuses(db *sql.DB) ([]MigrationStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, m := range migrations {
+		statuses[i].Name = m.Name
+		if at, ok := applied[m.Name]; ok {
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// PendingMigrations returns the names of migrations not yet applied
+func PendingMigrations(db *sql.DB) ([]string, error) {
+	statuses, err := MigrationStatuses(db)
+	if err != nil {
+		return nil, err
+	}
+	var pending []string
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			pending = append(pending, s.Name)
+		}
+	}
+	return pending, nil
+}
+
+// MigrateUp applies every pending migration, each in its own transaction,
+// and returns the names applied. Running it again is a no-op.
+func MigrateUp(db *sql.DB) ([]string, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []string
+	for _, m := range migrations {
+		if _, ok := applied[m.Name]; ok {
+			continue
+		}
+
+		slog.Info("running migration", "name", m.Name)
+		if err := runInTx(db, m.Up, "INSERT INTO schema_migrations (name) VALUES (?)", m.Name); err != nil {
+			return done, fmt.Errorf("failed to run migration %s: %w", m.Name, err)
+		}
+		slog.Info("migration applied successfully", "name", m.Name)
+		done = append(done, m.Name)
+	}
+	return done, nil
+}
+
+// MigrateDown reverts the latest steps applied migrations, newest first,
+// and returns the names reverted. It stops at the first migration without
+// a down file.
+func MigrateDown(db *sql.DB, steps int) ([]string, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []string
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Name]; !ok {
+			continue
+		}
+		if m.Down == "" {
+			return done, fmt.Errorf("migration %s has no down file", m.Name)
+		}
+
+		slog.Info("reverting migration", "name", m.Name)
+		if err := runInTx(db, m.Down, "DELETE FROM schema_migrations WHERE name = ?", m.Name); err != nil {
+			return done, fmt.Errorf("failed to revert migration %s: %w", m.Name, err)
+		}
+		slog.Info("migration reverted successfully", "name", m.Name)
+		done = append(done, m.Name)
+	}
+	return done, nil
+}
+
+// runInTx runs a migration script and its schema_migrations bookkeeping
+// together, so a failed migration leaves no partial changes behind
+func runInTx(db *sql.DB, script, record, name string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(script); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(record, name); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
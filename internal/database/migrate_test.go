@@ -0,0 +1,120 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/002_second.sql":     {Data: []byte("CREATE TABLE b (id INTEGER);")},
+		"m/001_first.sql":      {Data: []byte("CREATE TABLE a (id INTEGER);")},
+		"m/001_first.down.sql": {Data: []byte("DROP TABLE a;")},
+		"m/README.md":          {Data: []byte("ignored")},
+	}
+
+	migrations, err := loadMigrations(fsys, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 || migrations[0].Name != "001_first" || migrations[1].Name != "002_second" {
+		t.Fatalf("migrations = %+v, want 001_first then 002_second", migrations)
+	}
+	if migrations[0].Down != "DROP TABLE a;" || migrations[1].Down != "" {
+		t.Errorf("down scripts = %q, %q", migrations[0].Down, migrations[1].Down)
+	}
+
+	_, err = loadMigrations(fstest.MapFS{"m/003_orphan.down.sql": {Data: []byte("SELECT 1;")}}, "m")
+	if err == nil {
+		t.Error("expected error for down file without up file")
+	}
+}
+
+func TestEmbeddedMigrationsHaveDownFiles(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range migrations {
+		if m.Down == "" {
+			t.Errorf("migration %s has no down file", m.Name)
+		}
+	}
+}
+
+func TestMigrateUpDown(t *testing.T) {
+	db, err := Init(filepath.Join(t.TempDir(), "site.db"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	all, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := MigrateUp(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(all) {
+		t.Errorf("applied %d migrations, want %d", len(applied), len(all))
+	}
+
+	// Running again applies nothing
+	applied, err = MigrateUp(db)
+	if err != nil || len(applied) != 0 {
+		t.Errorf("second MigrateUp = %v, %v; want nothing applied", applied, err)
+	}
+
+	reverted, err := MigrateDown(db, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != 2 || reverted[0] != all[len(all)-1].Name {
+		t.Errorf("reverted = %v, want the latest two", reverted)
+	}
+	pending, err := PendingMigrations(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 {
+		t.Errorf("pending = %v, want 2", pending)
+	}
+
+	// Every down file reverts cleanly and the schema can be rebuilt
+	if _, err := MigrateDown(db, len(all)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MigrateUp(db); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := PendingMigrations(db); len(pending) != 0 {
+		t.Errorf("pending after rebuild = %v", pending)
+	}
+}
+
+func TestRunInTx_RollsBackOnError(t *testing.T) {
+	db, err := Init(filepath.Join(t.TempDir(), "site.db"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := ensureMigrationsTable(db); err != nil {
+		t.Fatal(err)
+	}
+
+	err = runInTx(db, "CREATE TABLE partial (id INTEGER); NOT VALID SQL;", "INSERT INTO schema_migrations (name) VALUES (?)", "999_broken")
+	if err == nil {
+		t.Fatal("expected error from invalid migration")
+	}
+
+	var tables, records int
+	db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'partial'").Scan(&tables)
+	db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&records)
+	if tables != 0 || records != 0 {
+		t.Errorf("failed migration left %d tables and %d records behind", tables, records)
+	}
+}
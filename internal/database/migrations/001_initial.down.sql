@@ -0,0 +1,9 @@
+-- Drops every table created by 001_initial, children first
+DROP TABLE IF EXISTS post_tags;
+DROP TABLE IF EXISTS bookmark_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS bookmarks;
+DROP TABLE IF EXISTS collections;
+DROP TABLE IF EXISTS posts;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS users;
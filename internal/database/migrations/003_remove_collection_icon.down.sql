@@ -0,0 +1 @@
+ALTER TABLE collections ADD COLUMN icon TEXT;
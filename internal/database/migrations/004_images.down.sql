@@ -0,0 +1,3 @@
+ALTER TABLE bookmarks DROP COLUMN favicon_id;
+ALTER TABLE bookmarks DROP COLUMN cover_image_id;
+DROP TABLE IF EXISTS images;
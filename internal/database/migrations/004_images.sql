@@ -0,0 +1,15 @@
+-- ============================================
+-- IMAGES (locally stored image blobs)
+-- ============================================
+CREATE TABLE IF NOT EXISTS images (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    mime_type       TEXT NOT NULL,
+    data            BLOB NOT NULL,
+    size            INTEGER NOT NULL,
+    source_url      TEXT,
+    created_at      DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Bookmarks can reference stored images instead of remote URLs
+ALTER TABLE bookmarks ADD COLUMN cover_image_id INTEGER REFERENCES images(id) ON DELETE SET NULL;
+ALTER TABLE bookmarks ADD COLUMN favicon_id INTEGER REFERENCES images(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+-- Downscaled copy of the stored cover image, used by the bookmarks grid
+ALTER TABLE bookmarks ADD COLUMN thumbnail_id INTEGER REFERENCES images(id) ON DELETE SET NULL;
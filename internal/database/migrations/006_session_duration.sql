@@ -0,0 +1,3 @@
+-- Remember how long each session was issued for, so "remember me" sessions
+-- keep their lifetime when rotated on re-authentication
+ALTER TABLE sessions ADD COLUMN duration_seconds INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Per-account lockout after repeated failed logins
+ALTER TABLE users ADD COLUMN failed_login_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN locked_until DATETIME;
@@ -0,0 +1,4 @@
+-- Record which browser and address each session was created from, so the
+-- sessions page can tell them apart
+ALTER TABLE sessions ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN ip_address TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+-- ============================================
+-- SEARCH_QUERIES (anonymous search log for content gap reports)
+-- ============================================
+CREATE TABLE IF NOT EXISTS search_queries (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    query           TEXT NOT NULL,
+    result_count    INTEGER NOT NULL,
+    created_at      DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_queries_zero ON search_queries(result_count, query);
@@ -0,0 +1,4 @@
+-- Track when each session was last used so idle sessions can be expired
+-- before their absolute expiry. Existing sessions start from creation time.
+ALTER TABLE sessions ADD COLUMN last_seen_at DATETIME;
+UPDATE sessions SET last_seen_at = created_at;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bookmarks_normalized_url;
+ALTER TABLE bookmarks DROP COLUMN normalized_url;
@@ -0,0 +1,5 @@
+-- Store a normalized form of each bookmark URL for duplicate detection, so
+-- trailing slashes, default ports and tracking params don't hide repeats.
+-- Existing rows are filled in by the app on startup.
+ALTER TABLE bookmarks ADD COLUMN normalized_url TEXT;
+CREATE INDEX IF NOT EXISTS idx_bookmarks_normalized_url ON bookmarks(normalized_url);
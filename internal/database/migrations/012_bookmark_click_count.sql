@@ -0,0 +1,2 @@
+-- Count how often each bookmark is opened through the /go/{id} redirect
+ALTER TABLE bookmarks ADD COLUMN click_count INTEGER NOT NULL DEFAULT 0;
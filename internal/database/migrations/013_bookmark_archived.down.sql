@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bookmarks_archived;
+ALTER TABLE bookmarks DROP COLUMN is_archived;
@@ -0,0 +1,4 @@
+-- Archived bookmarks are kept but hidden from default listings and the public site
+ALTER TABLE bookmarks ADD COLUMN is_archived INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_archived ON bookmarks(is_archived);
@@ -0,0 +1,2 @@
+-- Personal notes on bookmarks, kept apart from the fetched description
+ALTER TABLE bookmarks ADD COLUMN note TEXT;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_posts_deleted;
+ALTER TABLE posts DROP COLUMN deleted_at;
@@ -0,0 +1,4 @@
+-- Deleted posts move to the trash and are purged after a retention period
+ALTER TABLE posts ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_posts_deleted ON posts(deleted_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_posts_featured;
+ALTER TABLE posts DROP COLUMN is_featured;
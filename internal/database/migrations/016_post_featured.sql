@@ -0,0 +1,4 @@
+-- Published posts can be pinned to a featured section on the home page
+ALTER TABLE posts ADD COLUMN is_featured INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_posts_featured ON posts(is_featured, published_at DESC);
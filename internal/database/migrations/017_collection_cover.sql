@@ -0,0 +1,2 @@
+-- Collections can have a cover image, shown in the header of their public page
+ALTER TABLE collections ADD COLUMN cover_image TEXT;
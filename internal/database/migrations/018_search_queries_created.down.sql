@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_search_queries_created;
@@ -0,0 +1,2 @@
+-- Logged searches older than the retention window are pruned by created_at
+CREATE INDEX IF NOT EXISTS idx_search_queries_created ON search_queries(created_at);
@@ -10,6 +10,59 @@ import (
 	"time"
 )
 
+const countActivities = `-- name: CountActivities :one
+SELECT COUNT(*) FROM activities
+WHERE (entity_type = ?1 OR ?1 = '')
+  AND (action = ?2 OR ?2 = '')
+`
+
+type CountActivitiesParams struct {
+	EntityType string `json:"entity_type"`
+	Action     string `json:"action"`
+}
+
+func (q *Queries) CountActivities(ctx context.Context, arg CountActivitiesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActivities, arg.EntityType, arg.Action)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countActivitiesByEntityType = `-- name: CountActivitiesByEntityType :many
+SELECT entity_type, COUNT(*) AS count
+FROM activities
+GROUP BY entity_type
+ORDER BY count DESC, entity_type
+`
+
+type CountActivitiesByEntityTypeRow struct {
+	EntityType string `json:"entity_type"`
+	Count      int64  `json:"count"`
+}
+
+func (q *Queries) CountActivitiesByEntityType(ctx context.Context) ([]CountActivitiesByEntityTypeRow, error) {
+	rows, err := q.db.QueryContext(ctx, countActivitiesByEntityType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountActivitiesByEntityTypeRow{}
+	for rows.Next() {
+		var i CountActivitiesByEntityTypeRow
+		if err := rows.Scan(&i.EntityType, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createActivity = `-- name: CreateActivity :one
 INSERT INTO activities (action, entity_type, entity_id, entity_title, metadata, created_at)
 VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
@@ -45,6 +98,21 @@ func (q *Queries) CreateActivity(ctx context.Context, arg CreateActivityParams)
 	return i, err
 }
 
+const deleteActivitiesForEntity = `-- name: DeleteActivitiesForEntity :exec
+DELETE FROM activities
+WHERE entity_type = ? AND entity_id = ?
+`
+
+type DeleteActivitiesForEntityParams struct {
+	EntityType string `json:"entity_type"`
+	EntityID   *int64 `json:"entity_id"`
+}
+
+func (q *Queries) DeleteActivitiesForEntity(ctx context.Context, arg DeleteActivitiesForEntityParams) error {
+	_, err := q.db.ExecContext(ctx, deleteActivitiesForEntity, arg.EntityType, arg.EntityID)
+	return err
+}
+
 const deleteOldActivities = `-- name: DeleteOldActivities :exec
 DELETE FROM activities
 WHERE created_at < ?
@@ -55,6 +123,57 @@ func (q *Queries) DeleteOldActivities(ctx context.Context, createdAt *time.Time)
 	return err
 }
 
+const listActivities = `-- name: ListActivities :many
+SELECT id, "action", entity_type, entity_id, entity_title, metadata, created_at FROM activities
+WHERE (entity_type = ?1 OR ?1 = '')
+  AND (action = ?2 OR ?2 = '')
+ORDER BY created_at DESC, id DESC
+LIMIT ?3 OFFSET ?4
+`
+
+type ListActivitiesParams struct {
+	EntityType string `json:"entity_type"`
+	Action     string `json:"action"`
+	Limit      int64  `json:"limit"`
+	Offset     int64  `json:"offset"`
+}
+
+func (q *Queries) ListActivities(ctx context.Context, arg ListActivitiesParams) ([]Activity, error) {
+	rows, err := q.db.QueryContext(ctx, listActivities,
+		arg.EntityType,
+		arg.Action,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Activity{}
+	for rows.Next() {
+		var i Activity
+		if err := rows.Scan(
+			&i.ID,
+			&i.Action,
+			&i.EntityType,
+			&i.EntityID,
+			&i.EntityTitle,
+			&i.Metadata,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listRecentActivities = `-- name: ListRecentActivities :many
 SELECT id, "action", entity_type, entity_id, entity_title, metadata, created_at FROM activities
 ORDER BY created_at DESC
@@ -10,41 +10,97 @@ import (
 	"time"
 )
 
+const addTagToBookmarks = `-- name: AddTagToBookmarks :execrows
+INSERT OR IGNORE INTO bookmark_tags (bookmark_id, tag_id, created_at)
+SELECT id, CAST(? AS INTEGER), CURRENT_TIMESTAMP
+FROM bookmarks
+WHERE id IN (SELECT value FROM json_each(CAST(? AS TEXT)))
+`
+
+type AddTagToBookmarksParams struct {
+	TagID       int64  `json:"tag_id"`
+	BookmarkIds string `json:"bookmark_ids"`
+}
+
+// Tags each existing bookmark in a JSON array of IDs. Bookmarks that
+// already have the tag are skipped.
+func (q *Queries) AddTagToBookmarks(ctx context.Context, arg AddTagToBookmarksParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, addTagToBookmarks, arg.TagID, arg.BookmarkIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const countAllBookmarks = `-- name: CountAllBookmarks :one
-SELECT COUNT(*) FROM bookmarks
+SELECT COUNT(*) FROM bookmarks WHERE (is_archived = ? OR CAST(? AS INTEGER) = 1)
 `
 
-func (q *Queries) CountAllBookmarks(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countAllBookmarks)
+type CountAllBookmarksParams struct {
+	Archived        int64 `json:"archived"`
+	IncludeArchived int64 `json:"include_archived"`
+}
+
+func (q *Queries) CountAllBookmarks(ctx context.Context, arg CountAllBookmarksParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAllBookmarks, arg.Archived, arg.IncludeArchived)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const countBookmarksByCollection = `-- name: CountBookmarksByCollection :one
-SELECT COUNT(*) FROM bookmarks WHERE collection_id = ?
+SELECT COUNT(*) FROM bookmarks WHERE collection_id = ? AND (is_archived = ? OR CAST(? AS INTEGER) = 1)
+`
+
+type CountBookmarksByCollectionParams struct {
+	CollectionID    *int64 `json:"collection_id"`
+	Archived        int64  `json:"archived"`
+	IncludeArchived int64  `json:"include_archived"`
+}
+
+func (q *Queries) CountBookmarksByCollection(ctx context.Context, arg CountBookmarksByCollectionParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBookmarksByCollection, arg.CollectionID, arg.Archived, arg.IncludeArchived)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countBookmarksInCollections = `-- name: CountBookmarksInCollections :one
+SELECT COUNT(*) FROM bookmarks WHERE collection_id IN (SELECT value FROM json_each(CAST(? AS TEXT))) AND (is_archived = ? OR CAST(? AS INTEGER) = 1)
 `
 
-func (q *Queries) CountBookmarksByCollection(ctx context.Context, collectionID *int64) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countBookmarksByCollection, collectionID)
+type CountBookmarksInCollectionsParams struct {
+	CollectionIds   string `json:"collection_ids"`
+	Archived        int64  `json:"archived"`
+	IncludeArchived int64  `json:"include_archived"`
+}
+
+// Like CountBookmarksByCollection, for any collection in a JSON array of IDs.
+func (q *Queries) CountBookmarksInCollections(ctx context.Context, arg CountBookmarksInCollectionsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBookmarksInCollections, arg.CollectionIds, arg.Archived, arg.IncludeArchived)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const countFavoriteBookmarks = `-- name: CountFavoriteBookmarks :one
-SELECT COUNT(*) FROM bookmarks WHERE is_favorite = 1
+SELECT COUNT(*) FROM bookmarks WHERE is_favorite = 1 AND (is_archived = ? OR CAST(? AS INTEGER) = 1)
 `
 
-func (q *Queries) CountFavoriteBookmarks(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countFavoriteBookmarks)
+type CountFavoriteBookmarksParams struct {
+	Archived        int64 `json:"archived"`
+	IncludeArchived int64 `json:"include_archived"`
+}
+
+func (q *Queries) CountFavoriteBookmarks(ctx context.Context, arg CountFavoriteBookmarksParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFavoriteBookmarks, arg.Archived, arg.IncludeArchived)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const countPublicBookmarks = `-- name: CountPublicBookmarks :one
-SELECT COUNT(*) FROM bookmarks WHERE is_public = 1
+SELECT COUNT(*) FROM bookmarks WHERE is_public = 1 AND is_archived = 0
 `
 
 func (q *Queries) CountPublicBookmarks(ctx context.Context) (int64, error) {
@@ -55,7 +111,7 @@ func (q *Queries) CountPublicBookmarks(ctx context.Context) (int64, error) {
 }
 
 const countPublicBookmarksByCollection = `-- name: CountPublicBookmarksByCollection :one
-SELECT COUNT(*) FROM bookmarks WHERE is_public = 1 AND collection_id = ?
+SELECT COUNT(*) FROM bookmarks WHERE is_public = 1 AND is_archived = 0 AND collection_id = ?
 `
 
 func (q *Queries) CountPublicBookmarksByCollection(ctx context.Context, collectionID *int64) (int64, error) {
@@ -65,8 +121,19 @@ func (q *Queries) CountPublicBookmarksByCollection(ctx context.Context, collecti
 	return count, err
 }
 
+const countPublicBookmarksInCollections = `-- name: CountPublicBookmarksInCollections :one
+SELECT COUNT(*) FROM bookmarks WHERE is_public = 1 AND is_archived = 0 AND collection_id IN (SELECT value FROM json_each(CAST(? AS TEXT)))
+`
+
+func (q *Queries) CountPublicBookmarksInCollections(ctx context.Context, collectionIds string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPublicBookmarksInCollections, collectionIds)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countPublicFavoriteBookmarks = `-- name: CountPublicFavoriteBookmarks :one
-SELECT COUNT(*) FROM bookmarks WHERE is_public = 1 AND is_favorite = 1
+SELECT COUNT(*) FROM bookmarks WHERE is_public = 1 AND is_favorite = 1 AND is_archived = 0
 `
 
 func (q *Queries) CountPublicFavoriteBookmarks(ctx context.Context) (int64, error) {
@@ -78,7 +145,7 @@ func (q *Queries) CountPublicFavoriteBookmarks(ctx context.Context) (int64, erro
 
 const countUnsortedBookmarks = `-- name: CountUnsortedBookmarks :one
 
-SELECT COUNT(*) FROM bookmarks WHERE collection_id IS NULL
+SELECT COUNT(*) FROM bookmarks WHERE collection_id IS NULL AND is_archived = 0
 `
 
 // ============================================
@@ -92,29 +159,33 @@ func (q *Queries) CountUnsortedBookmarks(ctx context.Context) (int64, error) {
 }
 
 const createBookmark = `-- name: CreateBookmark :one
-INSERT INTO bookmarks (url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-RETURNING id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at
+INSERT INTO bookmarks (url, normalized_url, title, description, note, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+RETURNING id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note
 `
 
 type CreateBookmarkParams struct {
-	Url          string  `json:"url"`
-	Title        string  `json:"title"`
-	Description  *string `json:"description"`
-	CoverImage   *string `json:"cover_image"`
-	Favicon      *string `json:"favicon"`
-	Domain       *string `json:"domain"`
-	CollectionID *int64  `json:"collection_id"`
-	IsPublic     *int64  `json:"is_public"`
-	IsFavorite   *int64  `json:"is_favorite"`
-	SortOrder    *int64  `json:"sort_order"`
+	Url           string  `json:"url"`
+	NormalizedUrl *string `json:"normalized_url"`
+	Title         string  `json:"title"`
+	Description   *string `json:"description"`
+	Note          *string `json:"note"`
+	CoverImage    *string `json:"cover_image"`
+	Favicon       *string `json:"favicon"`
+	Domain        *string `json:"domain"`
+	CollectionID  *int64  `json:"collection_id"`
+	IsPublic      *int64  `json:"is_public"`
+	IsFavorite    *int64  `json:"is_favorite"`
+	SortOrder     *int64  `json:"sort_order"`
 }
 
 func (q *Queries) CreateBookmark(ctx context.Context, arg CreateBookmarkParams) (Bookmark, error) {
 	row := q.db.QueryRowContext(ctx, createBookmark,
 		arg.Url,
+		arg.NormalizedUrl,
 		arg.Title,
 		arg.Description,
+		arg.Note,
 		arg.CoverImage,
 		arg.Favicon,
 		arg.Domain,
@@ -138,10 +209,58 @@ func (q *Queries) CreateBookmark(ctx context.Context, arg CreateBookmarkParams)
 		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CoverImageID,
+		&i.FaviconID,
+		&i.ThumbnailID,
+		&i.NormalizedUrl,
+		&i.ClickCount,
+		&i.IsArchived,
+		&i.Note,
 	)
 	return i, err
 }
 
+const createBookmarksBatch = `-- name: CreateBookmarksBatch :many
+INSERT INTO bookmarks (url, normalized_url, title, domain, collection_id, is_public, is_favorite, created_at, updated_at)
+SELECT
+    json_extract(value, '$.url'),
+    json_extract(value, '$.normalized_url'),
+    json_extract(value, '$.title'),
+    json_extract(value, '$.domain'),
+    json_extract(value, '$.collection_id'),
+    1,
+    0,
+    CURRENT_TIMESTAMP,
+    CURRENT_TIMESTAMP
+FROM json_each(CAST(? AS TEXT))
+RETURNING id
+`
+
+// Inserts public bookmarks from a JSON array of objects with url,
+// normalized_url, title, domain and collection_id keys, in a single statement.
+func (q *Queries) CreateBookmarksBatch(ctx context.Context, bookmarks string) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, createBookmarksBatch, bookmarks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const deleteBookmark = `-- name: DeleteBookmark :exec
 DELETE FROM bookmarks WHERE id = ?
 `
@@ -152,7 +271,7 @@ func (q *Queries) DeleteBookmark(ctx context.Context, id int64) error {
 }
 
 const getBookmarkByID = `-- name: GetBookmarkByID :one
-SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at FROM bookmarks WHERE id = ?
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks WHERE id = ?
 `
 
 func (q *Queries) GetBookmarkByID(ctx context.Context, id int64) (Bookmark, error) {
@@ -172,12 +291,51 @@ func (q *Queries) GetBookmarkByID(ctx context.Context, id int64) (Bookmark, erro
 		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CoverImageID,
+		&i.FaviconID,
+		&i.ThumbnailID,
+		&i.NormalizedUrl,
+		&i.ClickCount,
+		&i.IsArchived,
+		&i.Note,
+	)
+	return i, err
+}
+
+const getBookmarkByNormalizedURL = `-- name: GetBookmarkByNormalizedURL :one
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks WHERE normalized_url = ? ORDER BY id LIMIT 1
+`
+
+func (q *Queries) GetBookmarkByNormalizedURL(ctx context.Context, normalizedUrl *string) (Bookmark, error) {
+	row := q.db.QueryRowContext(ctx, getBookmarkByNormalizedURL, normalizedUrl)
+	var i Bookmark
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Title,
+		&i.Description,
+		&i.CoverImage,
+		&i.Favicon,
+		&i.Domain,
+		&i.CollectionID,
+		&i.IsPublic,
+		&i.IsFavorite,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CoverImageID,
+		&i.FaviconID,
+		&i.ThumbnailID,
+		&i.NormalizedUrl,
+		&i.ClickCount,
+		&i.IsArchived,
+		&i.Note,
 	)
 	return i, err
 }
 
 const getBookmarkByURL = `-- name: GetBookmarkByURL :one
-SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at FROM bookmarks WHERE url = ? LIMIT 1
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks WHERE url = ? LIMIT 1
 `
 
 func (q *Queries) GetBookmarkByURL(ctx context.Context, url string) (Bookmark, error) {
@@ -197,6 +355,13 @@ func (q *Queries) GetBookmarkByURL(ctx context.Context, url string) (Bookmark, e
 		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CoverImageID,
+		&i.FaviconID,
+		&i.ThumbnailID,
+		&i.NormalizedUrl,
+		&i.ClickCount,
+		&i.IsArchived,
+		&i.Note,
 	)
 	return i, err
 }
@@ -275,19 +440,38 @@ func (q *Queries) GetUnsortedBookmarkSortOrders(ctx context.Context) ([]GetUnsor
 	return items, nil
 }
 
+const incrementBookmarkClickCount = `-- name: IncrementBookmarkClickCount :exec
+UPDATE bookmarks SET click_count = click_count + 1 WHERE id = ?
+`
+
+func (q *Queries) IncrementBookmarkClickCount(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, incrementBookmarkClickCount, id)
+	return err
+}
+
 const listAllBookmarks = `-- name: ListAllBookmarks :many
-SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at FROM bookmarks 
-ORDER BY sort_order, created_at DESC 
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks 
+WHERE (is_archived = ? OR CAST(? AS INTEGER) = 1)
+ORDER BY sort_order, created_at DESC, id DESC
 LIMIT ? OFFSET ?
 `
 
 type ListAllBookmarksParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	Archived        int64 `json:"archived"`
+	IncludeArchived int64 `json:"include_archived"`
+	Limit           int64 `json:"limit"`
+	Offset          int64 `json:"offset"`
 }
 
+// Lists bookmarks whose is_archived matches archived, or every bookmark
+// with include_archived set. The other admin listings filter the same way.
 func (q *Queries) ListAllBookmarks(ctx context.Context, arg ListAllBookmarksParams) ([]Bookmark, error) {
-	rows, err := q.db.QueryContext(ctx, listAllBookmarks, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listAllBookmarks,
+		arg.Archived,
+		arg.IncludeArchived,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -309,6 +493,13 @@ func (q *Queries) ListAllBookmarks(ctx context.Context, arg ListAllBookmarksPara
 			&i.SortOrder,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
 		); err != nil {
 			return nil, err
 		}
@@ -324,20 +515,96 @@ func (q *Queries) ListAllBookmarks(ctx context.Context, arg ListAllBookmarksPara
 }
 
 const listBookmarksByCollection = `-- name: ListBookmarksByCollection :many
-SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at FROM bookmarks 
-WHERE collection_id = ? 
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks 
+WHERE collection_id = ?
+  AND (is_archived = ? OR CAST(? AS INTEGER) = 1)
 ORDER BY sort_order, created_at DESC 
 LIMIT ? OFFSET ?
 `
 
 type ListBookmarksByCollectionParams struct {
-	CollectionID *int64 `json:"collection_id"`
-	Limit        int64  `json:"limit"`
-	Offset       int64  `json:"offset"`
+	CollectionID    *int64 `json:"collection_id"`
+	Archived        int64  `json:"archived"`
+	IncludeArchived int64  `json:"include_archived"`
+	Limit           int64  `json:"limit"`
+	Offset          int64  `json:"offset"`
 }
 
 func (q *Queries) ListBookmarksByCollection(ctx context.Context, arg ListBookmarksByCollectionParams) ([]Bookmark, error) {
-	rows, err := q.db.QueryContext(ctx, listBookmarksByCollection, arg.CollectionID, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listBookmarksByCollection,
+		arg.CollectionID,
+		arg.Archived,
+		arg.IncludeArchived,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookmarksInCollections = `-- name: ListBookmarksInCollections :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE collection_id IN (SELECT value FROM json_each(CAST(? AS TEXT)))
+  AND (is_archived = ? OR CAST(? AS INTEGER) = 1)
+ORDER BY sort_order, created_at DESC
+LIMIT ? OFFSET ?
+`
+
+type ListBookmarksInCollectionsParams struct {
+	CollectionIds   string `json:"collection_ids"`
+	Archived        int64  `json:"archived"`
+	IncludeArchived int64  `json:"include_archived"`
+	Limit           int64  `json:"limit"`
+	Offset          int64  `json:"offset"`
+}
+
+// Like ListBookmarksByCollection, for any collection in a JSON array of IDs.
+func (q *Queries) ListBookmarksInCollections(ctx context.Context, arg ListBookmarksInCollectionsParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listBookmarksInCollections,
+		arg.CollectionIds,
+		arg.Archived,
+		arg.IncludeArchived,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -359,6 +626,13 @@ func (q *Queries) ListBookmarksByCollection(ctx context.Context, arg ListBookmar
 			&i.SortOrder,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
 		); err != nil {
 			return nil, err
 		}
@@ -373,20 +647,59 @@ func (q *Queries) ListBookmarksByCollection(ctx context.Context, arg ListBookmar
 	return items, nil
 }
 
+const listBookmarksWithoutNormalizedURL = `-- name: ListBookmarksWithoutNormalizedURL :many
+SELECT id, url FROM bookmarks WHERE normalized_url IS NULL
+`
+
+type ListBookmarksWithoutNormalizedURLRow struct {
+	ID  int64  `json:"id"`
+	Url string `json:"url"`
+}
+
+func (q *Queries) ListBookmarksWithoutNormalizedURL(ctx context.Context) ([]ListBookmarksWithoutNormalizedURLRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBookmarksWithoutNormalizedURL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListBookmarksWithoutNormalizedURLRow{}
+	for rows.Next() {
+		var i ListBookmarksWithoutNormalizedURLRow
+		if err := rows.Scan(&i.ID, &i.Url); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listFavoriteBookmarks = `-- name: ListFavoriteBookmarks :many
-SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at FROM bookmarks 
-WHERE is_favorite = 1 
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks 
+WHERE is_favorite = 1 AND (is_archived = ? OR CAST(? AS INTEGER) = 1)
 ORDER BY sort_order, created_at DESC 
 LIMIT ? OFFSET ?
 `
 
 type ListFavoriteBookmarksParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	Archived        int64 `json:"archived"`
+	IncludeArchived int64 `json:"include_archived"`
+	Limit           int64 `json:"limit"`
+	Offset          int64 `json:"offset"`
 }
 
 func (q *Queries) ListFavoriteBookmarks(ctx context.Context, arg ListFavoriteBookmarksParams) ([]Bookmark, error) {
-	rows, err := q.db.QueryContext(ctx, listFavoriteBookmarks, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listFavoriteBookmarks,
+		arg.Archived,
+		arg.IncludeArchived,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -408,6 +721,13 @@ func (q *Queries) ListFavoriteBookmarks(ctx context.Context, arg ListFavoriteBoo
 			&i.SortOrder,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
 		); err != nil {
 			return nil, err
 		}
@@ -423,9 +743,9 @@ func (q *Queries) ListFavoriteBookmarks(ctx context.Context, arg ListFavoriteBoo
 }
 
 const listPublicBookmarks = `-- name: ListPublicBookmarks :many
-SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at FROM bookmarks 
-WHERE is_public = 1 
-ORDER BY sort_order, created_at DESC 
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks 
+WHERE is_public = 1 AND is_archived = 0
+ORDER BY sort_order, created_at DESC, id DESC
 LIMIT ? OFFSET ?
 `
 
@@ -457,6 +777,84 @@ func (q *Queries) ListPublicBookmarks(ctx context.Context, arg ListPublicBookmar
 			&i.SortOrder,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicBookmarksAfter = `-- name: ListPublicBookmarksAfter :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (COALESCE(sort_order, -1) > ?
+    OR (COALESCE(sort_order, -1) = ?
+      AND (datetime(created_at) < ?
+        OR (datetime(created_at) = ? AND id < ?))))
+ORDER BY sort_order, created_at DESC, id DESC
+LIMIT ?
+`
+
+type ListPublicBookmarksAfterParams struct {
+	AfterSortOrder int64  `json:"after_sort_order"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        int64  `json:"after_id"`
+	Limit          int64  `json:"limit"`
+}
+
+// Keyset page of public bookmarks following the given position. NULL
+// sort_order sorts first, so it compares as -1 (real orders are positive).
+func (q *Queries) ListPublicBookmarksAfter(ctx context.Context, arg ListPublicBookmarksAfterParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicBookmarksAfter,
+		arg.AfterSortOrder,
+		arg.AfterSortOrder,
+		arg.AfterCreatedAt,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
 		); err != nil {
 			return nil, err
 		}
@@ -472,9 +870,9 @@ func (q *Queries) ListPublicBookmarks(ctx context.Context, arg ListPublicBookmar
 }
 
 const listPublicBookmarksByCollection = `-- name: ListPublicBookmarksByCollection :many
-SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at FROM bookmarks 
-WHERE is_public = 1 AND collection_id = ? 
-ORDER BY sort_order, created_at DESC 
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks 
+WHERE is_public = 1 AND is_archived = 0 AND collection_id = ? 
+ORDER BY sort_order, created_at DESC, id DESC
 LIMIT ? OFFSET ?
 `
 
@@ -507,6 +905,13 @@ func (q *Queries) ListPublicBookmarksByCollection(ctx context.Context, arg ListP
 			&i.SortOrder,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
 		); err != nil {
 			return nil, err
 		}
@@ -521,20 +926,35 @@ func (q *Queries) ListPublicBookmarksByCollection(ctx context.Context, arg ListP
 	return items, nil
 }
 
-const listPublicFavoriteBookmarks = `-- name: ListPublicFavoriteBookmarks :many
-SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at FROM bookmarks 
-WHERE is_public = 1 AND is_favorite = 1 
-ORDER BY sort_order, created_at DESC 
-LIMIT ? OFFSET ?
+const listPublicBookmarksByCollectionAfter = `-- name: ListPublicBookmarksByCollectionAfter :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0 AND collection_id = ?
+  AND (COALESCE(sort_order, -1) > ?
+    OR (COALESCE(sort_order, -1) = ?
+      AND (datetime(created_at) < ?
+        OR (datetime(created_at) = ? AND id < ?))))
+ORDER BY sort_order, created_at DESC, id DESC
+LIMIT ?
 `
 
-type ListPublicFavoriteBookmarksParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+type ListPublicBookmarksByCollectionAfterParams struct {
+	CollectionID   *int64 `json:"collection_id"`
+	AfterSortOrder int64  `json:"after_sort_order"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        int64  `json:"after_id"`
+	Limit          int64  `json:"limit"`
 }
 
-func (q *Queries) ListPublicFavoriteBookmarks(ctx context.Context, arg ListPublicFavoriteBookmarksParams) ([]Bookmark, error) {
-	rows, err := q.db.QueryContext(ctx, listPublicFavoriteBookmarks, arg.Limit, arg.Offset)
+func (q *Queries) ListPublicBookmarksByCollectionAfter(ctx context.Context, arg ListPublicBookmarksByCollectionAfterParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicBookmarksByCollectionAfter,
+		arg.CollectionID,
+		arg.AfterSortOrder,
+		arg.AfterSortOrder,
+		arg.AfterCreatedAt,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -556,6 +976,13 @@ func (q *Queries) ListPublicFavoriteBookmarks(ctx context.Context, arg ListPubli
 			&i.SortOrder,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
 		); err != nil {
 			return nil, err
 		}
@@ -570,41 +997,56 @@ func (q *Queries) ListPublicFavoriteBookmarks(ctx context.Context, arg ListPubli
 	return items, nil
 }
 
-const listRecentUnsortedBookmarks = `-- name: ListRecentUnsortedBookmarks :many
-SELECT id, title, url, domain, is_favorite, is_public, updated_at
-FROM bookmarks
-WHERE collection_id IS NULL
-ORDER BY COALESCE(sort_order, 999999) ASC, updated_at DESC
-LIMIT ?
+const listPublicBookmarksByDomain = `-- name: ListPublicBookmarksByDomain :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(? AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(? AS TEXT))))
+ORDER BY lower(COALESCE(domain, '')), id
+LIMIT ? OFFSET ?
 `
 
-type ListRecentUnsortedBookmarksRow struct {
-	ID         int64      `json:"id"`
-	Title      string     `json:"title"`
-	Url        string     `json:"url"`
-	Domain     *string    `json:"domain"`
-	IsFavorite *int64     `json:"is_favorite"`
-	IsPublic   *int64     `json:"is_public"`
-	UpdatedAt  *time.Time `json:"updated_at"`
+type ListPublicBookmarksByDomainParams struct {
+	CollectionIds string `json:"collection_ids"`
+	Limit         int64  `json:"limit"`
+	Offset        int64  `json:"offset"`
 }
 
-func (q *Queries) ListRecentUnsortedBookmarks(ctx context.Context, limit int64) ([]ListRecentUnsortedBookmarksRow, error) {
-	rows, err := q.db.QueryContext(ctx, listRecentUnsortedBookmarks, limit)
+// Public bookmarks grouped by domain, case-insensitive
+func (q *Queries) ListPublicBookmarksByDomain(ctx context.Context, arg ListPublicBookmarksByDomainParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicBookmarksByDomain,
+		arg.CollectionIds,
+		arg.CollectionIds,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListRecentUnsortedBookmarksRow{}
+	items := []Bookmark{}
 	for rows.Next() {
-		var i ListRecentUnsortedBookmarksRow
+		var i Bookmark
 		if err := rows.Scan(
 			&i.ID,
-			&i.Title,
 			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
 			&i.Domain,
-			&i.IsFavorite,
+			&i.CollectionID,
 			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
 		); err != nil {
 			return nil, err
 		}
@@ -619,20 +1061,32 @@ func (q *Queries) ListRecentUnsortedBookmarks(ctx context.Context, limit int64)
 	return items, nil
 }
 
-const listUnsortedBookmarks = `-- name: ListUnsortedBookmarks :many
-SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at FROM bookmarks
-WHERE collection_id IS NULL
-ORDER BY COALESCE(sort_order, 999999) ASC, updated_at DESC
-LIMIT ? OFFSET ?
+const listPublicBookmarksByDomainAfter = `-- name: ListPublicBookmarksByDomainAfter :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(? AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(? AS TEXT))))
+  AND (lower(COALESCE(domain, '')) > lower(?)
+    OR (lower(COALESCE(domain, '')) = lower(?) AND id > ?))
+ORDER BY lower(COALESCE(domain, '')), id
+LIMIT ?
 `
 
-type ListUnsortedBookmarksParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+type ListPublicBookmarksByDomainAfterParams struct {
+	CollectionIds string `json:"collection_ids"`
+	AfterKey      string `json:"after_key"`
+	AfterID       int64  `json:"after_id"`
+	Limit         int64  `json:"limit"`
 }
 
-func (q *Queries) ListUnsortedBookmarks(ctx context.Context, arg ListUnsortedBookmarksParams) ([]Bookmark, error) {
-	rows, err := q.db.QueryContext(ctx, listUnsortedBookmarks, arg.Limit, arg.Offset)
+func (q *Queries) ListPublicBookmarksByDomainAfter(ctx context.Context, arg ListPublicBookmarksByDomainAfterParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicBookmarksByDomainAfter,
+		arg.CollectionIds,
+		arg.CollectionIds,
+		arg.AfterKey,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -654,6 +1108,13 @@ func (q *Queries) ListUnsortedBookmarks(ctx context.Context, arg ListUnsortedBoo
 			&i.SortOrder,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
 		); err != nil {
 			return nil, err
 		}
@@ -668,31 +1129,922 @@ func (q *Queries) ListUnsortedBookmarks(ctx context.Context, arg ListUnsortedBoo
 	return items, nil
 }
 
-const updateBookmark = `-- name: UpdateBookmark :exec
-UPDATE bookmarks 
-SET url = ?, title = ?, description = ?, cover_image = ?, favicon = ?, domain = ?,
-    collection_id = ?, is_public = ?, is_favorite = ?, updated_at = CURRENT_TIMESTAMP 
-WHERE id = ?
+const listPublicBookmarksByTitle = `-- name: ListPublicBookmarksByTitle :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(? AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(? AS TEXT))))
+ORDER BY lower(title), id
+LIMIT ? OFFSET ?
 `
 
-type UpdateBookmarkParams struct {
-	Url          string  `json:"url"`
-	Title        string  `json:"title"`
-	Description  *string `json:"description"`
-	CoverImage   *string `json:"cover_image"`
-	Favicon      *string `json:"favicon"`
-	Domain       *string `json:"domain"`
-	CollectionID *int64  `json:"collection_id"`
-	IsPublic     *int64  `json:"is_public"`
-	IsFavorite   *int64  `json:"is_favorite"`
-	ID           int64   `json:"id"`
+type ListPublicBookmarksByTitleParams struct {
+	CollectionIds string `json:"collection_ids"`
+	Limit         int64  `json:"limit"`
+	Offset        int64  `json:"offset"`
 }
 
-func (q *Queries) UpdateBookmark(ctx context.Context, arg UpdateBookmarkParams) error {
+// Public bookmarks in title order, case-insensitive
+func (q *Queries) ListPublicBookmarksByTitle(ctx context.Context, arg ListPublicBookmarksByTitleParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicBookmarksByTitle,
+		arg.CollectionIds,
+		arg.CollectionIds,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicBookmarksByTitleAfter = `-- name: ListPublicBookmarksByTitleAfter :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(? AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(? AS TEXT))))
+  AND (lower(title) > lower(?)
+    OR (lower(title) = lower(?) AND id > ?))
+ORDER BY lower(title), id
+LIMIT ?
+`
+
+type ListPublicBookmarksByTitleAfterParams struct {
+	CollectionIds string `json:"collection_ids"`
+	AfterKey      string `json:"after_key"`
+	AfterID       int64  `json:"after_id"`
+	Limit         int64  `json:"limit"`
+}
+
+func (q *Queries) ListPublicBookmarksByTitleAfter(ctx context.Context, arg ListPublicBookmarksByTitleAfterParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicBookmarksByTitleAfter,
+		arg.CollectionIds,
+		arg.CollectionIds,
+		arg.AfterKey,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicBookmarksInCollections = `-- name: ListPublicBookmarksInCollections :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0 AND collection_id IN (SELECT value FROM json_each(CAST(? AS TEXT)))
+ORDER BY sort_order, created_at DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type ListPublicBookmarksInCollectionsParams struct {
+	CollectionIds string `json:"collection_ids"`
+	Limit         int64  `json:"limit"`
+	Offset        int64  `json:"offset"`
+}
+
+func (q *Queries) ListPublicBookmarksInCollections(ctx context.Context, arg ListPublicBookmarksInCollectionsParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicBookmarksInCollections,
+		arg.CollectionIds,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicBookmarksInCollectionsAfter = `-- name: ListPublicBookmarksInCollectionsAfter :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0 AND collection_id IN (SELECT value FROM json_each(CAST(? AS TEXT)))
+  AND (COALESCE(sort_order, -1) > ?
+    OR (COALESCE(sort_order, -1) = ?
+      AND (datetime(created_at) < ?
+        OR (datetime(created_at) = ? AND id < ?))))
+ORDER BY sort_order, created_at DESC, id DESC
+LIMIT ?
+`
+
+type ListPublicBookmarksInCollectionsAfterParams struct {
+	CollectionIds  string `json:"collection_ids"`
+	AfterSortOrder int64  `json:"after_sort_order"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        int64  `json:"after_id"`
+	Limit          int64  `json:"limit"`
+}
+
+func (q *Queries) ListPublicBookmarksInCollectionsAfter(ctx context.Context, arg ListPublicBookmarksInCollectionsAfterParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicBookmarksInCollectionsAfter,
+		arg.CollectionIds,
+		arg.AfterSortOrder,
+		arg.AfterSortOrder,
+		arg.AfterCreatedAt,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicBookmarksNewest = `-- name: ListPublicBookmarksNewest :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(? AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(? AS TEXT))))
+ORDER BY COALESCE(datetime(created_at), '') DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type ListPublicBookmarksNewestParams struct {
+	CollectionIds string `json:"collection_ids"`
+	Limit         int64  `json:"limit"`
+	Offset        int64  `json:"offset"`
+}
+
+// Public bookmarks newest added first, ignoring the manual order. Bookmarks
+// without a creation time sort last. collection_ids is as for
+// ListPublicBookmarksOldest.
+func (q *Queries) ListPublicBookmarksNewest(ctx context.Context, arg ListPublicBookmarksNewestParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicBookmarksNewest,
+		arg.CollectionIds,
+		arg.CollectionIds,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicBookmarksNewestAfter = `-- name: ListPublicBookmarksNewestAfter :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(? AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(? AS TEXT))))
+  AND (COALESCE(datetime(created_at), '') < ?
+    OR (COALESCE(datetime(created_at), '') = ? AND id < ?))
+ORDER BY COALESCE(datetime(created_at), '') DESC, id DESC
+LIMIT ?
+`
+
+type ListPublicBookmarksNewestAfterParams struct {
+	CollectionIds string `json:"collection_ids"`
+	AfterKey      string `json:"after_key"`
+	AfterID       int64  `json:"after_id"`
+	Limit         int64  `json:"limit"`
+}
+
+func (q *Queries) ListPublicBookmarksNewestAfter(ctx context.Context, arg ListPublicBookmarksNewestAfterParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicBookmarksNewestAfter,
+		arg.CollectionIds,
+		arg.CollectionIds,
+		arg.AfterKey,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicBookmarksOldest = `-- name: ListPublicBookmarksOldest :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(? AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(? AS TEXT))))
+ORDER BY datetime(created_at), id
+LIMIT ? OFFSET ?
+`
+
+type ListPublicBookmarksOldestParams struct {
+	CollectionIds string `json:"collection_ids"`
+	Limit         int64  `json:"limit"`
+	Offset        int64  `json:"offset"`
+}
+
+// Public bookmarks oldest first. An empty collection_ids lists every
+// collection; otherwise it is a JSON array of collection IDs.
+func (q *Queries) ListPublicBookmarksOldest(ctx context.Context, arg ListPublicBookmarksOldestParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicBookmarksOldest,
+		arg.CollectionIds,
+		arg.CollectionIds,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicBookmarksOldestAfter = `-- name: ListPublicBookmarksOldestAfter :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(? AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(? AS TEXT))))
+  AND (datetime(created_at) > ?
+    OR (datetime(created_at) = ? AND id > ?))
+ORDER BY datetime(created_at), id
+LIMIT ?
+`
+
+type ListPublicBookmarksOldestAfterParams struct {
+	CollectionIds string `json:"collection_ids"`
+	AfterKey      string `json:"after_key"`
+	AfterID       int64  `json:"after_id"`
+	Limit         int64  `json:"limit"`
+}
+
+func (q *Queries) ListPublicBookmarksOldestAfter(ctx context.Context, arg ListPublicBookmarksOldestAfterParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicBookmarksOldestAfter,
+		arg.CollectionIds,
+		arg.CollectionIds,
+		arg.AfterKey,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicFavoriteBookmarks = `-- name: ListPublicFavoriteBookmarks :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks 
+WHERE is_public = 1 AND is_favorite = 1 AND is_archived = 0
+ORDER BY sort_order, created_at DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type ListPublicFavoriteBookmarksParams struct {
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+}
+
+func (q *Queries) ListPublicFavoriteBookmarks(ctx context.Context, arg ListPublicFavoriteBookmarksParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicFavoriteBookmarks, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicFavoriteBookmarksAfter = `-- name: ListPublicFavoriteBookmarksAfter :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_favorite = 1 AND is_archived = 0
+  AND (COALESCE(sort_order, -1) > ?
+    OR (COALESCE(sort_order, -1) = ?
+      AND (datetime(created_at) < ?
+        OR (datetime(created_at) = ? AND id < ?))))
+ORDER BY sort_order, created_at DESC, id DESC
+LIMIT ?
+`
+
+type ListPublicFavoriteBookmarksAfterParams struct {
+	AfterSortOrder int64  `json:"after_sort_order"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        int64  `json:"after_id"`
+	Limit          int64  `json:"limit"`
+}
+
+// Keyset page of public favorites, ordered like ListPublicBookmarksAfter
+func (q *Queries) ListPublicFavoriteBookmarksAfter(ctx context.Context, arg ListPublicFavoriteBookmarksAfterParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicFavoriteBookmarksAfter,
+		arg.AfterSortOrder,
+		arg.AfterSortOrder,
+		arg.AfterCreatedAt,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicFavoriteBookmarksNewest = `-- name: ListPublicFavoriteBookmarksNewest :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE is_public = 1 AND is_favorite = 1 AND is_archived = 0
+ORDER BY COALESCE(datetime(created_at), '') DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type ListPublicFavoriteBookmarksNewestParams struct {
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+}
+
+// Public favorites newest added first, ignoring the manual order, as
+// ListPublicBookmarksNewest
+func (q *Queries) ListPublicFavoriteBookmarksNewest(ctx context.Context, arg ListPublicFavoriteBookmarksNewestParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicFavoriteBookmarksNewest, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecentUnsortedBookmarks = `-- name: ListRecentUnsortedBookmarks :many
+SELECT id, title, url, domain, is_favorite, is_public, updated_at
+FROM bookmarks
+WHERE collection_id IS NULL AND is_archived = 0
+ORDER BY COALESCE(sort_order, 999999) ASC, updated_at DESC
+LIMIT ?
+`
+
+type ListRecentUnsortedBookmarksRow struct {
+	ID         int64      `json:"id"`
+	Title      string     `json:"title"`
+	Url        string     `json:"url"`
+	Domain     *string    `json:"domain"`
+	IsFavorite *int64     `json:"is_favorite"`
+	IsPublic   *int64     `json:"is_public"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+}
+
+func (q *Queries) ListRecentUnsortedBookmarks(ctx context.Context, limit int64) ([]ListRecentUnsortedBookmarksRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRecentUnsortedBookmarks, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecentUnsortedBookmarksRow{}
+	for rows.Next() {
+		var i ListRecentUnsortedBookmarksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.Domain,
+			&i.IsFavorite,
+			&i.IsPublic,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTagsForBookmarks = `-- name: ListTagsForBookmarks :many
+SELECT bt.bookmark_id, t.id, t.name, t.slug, t.color, t.created_at
+FROM bookmark_tags bt
+INNER JOIN tags t ON t.id = bt.tag_id
+WHERE bt.bookmark_id IN (SELECT value FROM json_each(CAST(? AS TEXT)))
+ORDER BY t.name
+`
+
+type ListTagsForBookmarksRow struct {
+	BookmarkID int64      `json:"bookmark_id"`
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Slug       string     `json:"slug"`
+	Color      *string    `json:"color"`
+	CreatedAt  *time.Time `json:"created_at"`
+}
+
+// Tags of every bookmark in a JSON array of IDs, by name.
+func (q *Queries) ListTagsForBookmarks(ctx context.Context, bookmarkIds string) ([]ListTagsForBookmarksRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTagsForBookmarks, bookmarkIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTagsForBookmarksRow{}
+	for rows.Next() {
+		var i ListTagsForBookmarksRow
+		if err := rows.Scan(
+			&i.BookmarkID,
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Color,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnsortedBookmarks = `-- name: ListUnsortedBookmarks :many
+SELECT id, url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at, cover_image_id, favicon_id, thumbnail_id, normalized_url, click_count, is_archived, note FROM bookmarks
+WHERE collection_id IS NULL AND is_archived = 0
+ORDER BY COALESCE(sort_order, 999999) ASC, updated_at DESC
+LIMIT ? OFFSET ?
+`
+
+type ListUnsortedBookmarksParams struct {
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+}
+
+func (q *Queries) ListUnsortedBookmarks(ctx context.Context, arg ListUnsortedBookmarksParams) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listUnsortedBookmarks, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Bookmark{}
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CoverImage,
+			&i.Favicon,
+			&i.Domain,
+			&i.CollectionID,
+			&i.IsPublic,
+			&i.IsFavorite,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+			&i.FaviconID,
+			&i.ThumbnailID,
+			&i.NormalizedUrl,
+			&i.ClickCount,
+			&i.IsArchived,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeTagFromBookmarks = `-- name: RemoveTagFromBookmarks :execrows
+DELETE FROM bookmark_tags
+WHERE tag_id = ?
+  AND bookmark_id IN (SELECT value FROM json_each(CAST(? AS TEXT)))
+`
+
+type RemoveTagFromBookmarksParams struct {
+	TagID       int64  `json:"tag_id"`
+	BookmarkIds string `json:"bookmark_ids"`
+}
+
+func (q *Queries) RemoveTagFromBookmarks(ctx context.Context, arg RemoveTagFromBookmarksParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeTagFromBookmarks, arg.TagID, arg.BookmarkIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateBookmark = `-- name: UpdateBookmark :exec
+UPDATE bookmarks 
+SET url = ?, normalized_url = ?, title = ?, description = ?, note = ?, cover_image = ?, favicon = ?, domain = ?,
+    collection_id = ?, is_public = ?, is_favorite = ?, updated_at = CURRENT_TIMESTAMP 
+WHERE id = ?
+`
+
+type UpdateBookmarkParams struct {
+	Url           string  `json:"url"`
+	NormalizedUrl *string `json:"normalized_url"`
+	Title         string  `json:"title"`
+	Description   *string `json:"description"`
+	Note          *string `json:"note"`
+	CoverImage    *string `json:"cover_image"`
+	Favicon       *string `json:"favicon"`
+	Domain        *string `json:"domain"`
+	CollectionID  *int64  `json:"collection_id"`
+	IsPublic      *int64  `json:"is_public"`
+	IsFavorite    *int64  `json:"is_favorite"`
+	ID            int64   `json:"id"`
+}
+
+func (q *Queries) UpdateBookmark(ctx context.Context, arg UpdateBookmarkParams) error {
 	_, err := q.db.ExecContext(ctx, updateBookmark,
 		arg.Url,
+		arg.NormalizedUrl,
 		arg.Title,
 		arg.Description,
+		arg.Note,
 		arg.CoverImage,
 		arg.Favicon,
 		arg.Domain,
@@ -704,6 +2056,20 @@ func (q *Queries) UpdateBookmark(ctx context.Context, arg UpdateBookmarkParams)
 	return err
 }
 
+const updateBookmarkArchived = `-- name: UpdateBookmarkArchived :exec
+UPDATE bookmarks SET is_archived = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type UpdateBookmarkArchivedParams struct {
+	IsArchived int64 `json:"is_archived"`
+	ID         int64 `json:"id"`
+}
+
+func (q *Queries) UpdateBookmarkArchived(ctx context.Context, arg UpdateBookmarkArchivedParams) error {
+	_, err := q.db.ExecContext(ctx, updateBookmarkArchived, arg.IsArchived, arg.ID)
+	return err
+}
+
 const updateBookmarkFavorite = `-- name: UpdateBookmarkFavorite :exec
 UPDATE bookmarks SET is_favorite = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
 `
@@ -718,6 +2084,35 @@ func (q *Queries) UpdateBookmarkFavorite(ctx context.Context, arg UpdateBookmark
 	return err
 }
 
+const updateBookmarkImages = `-- name: UpdateBookmarkImages :exec
+UPDATE bookmarks SET cover_image_id = ?, thumbnail_id = ? WHERE id = ?
+`
+
+type UpdateBookmarkImagesParams struct {
+	CoverImageID *int64 `json:"cover_image_id"`
+	ThumbnailID  *int64 `json:"thumbnail_id"`
+	ID           int64  `json:"id"`
+}
+
+func (q *Queries) UpdateBookmarkImages(ctx context.Context, arg UpdateBookmarkImagesParams) error {
+	_, err := q.db.ExecContext(ctx, updateBookmarkImages, arg.CoverImageID, arg.ThumbnailID, arg.ID)
+	return err
+}
+
+const updateBookmarkNormalizedURL = `-- name: UpdateBookmarkNormalizedURL :exec
+UPDATE bookmarks SET normalized_url = ? WHERE id = ?
+`
+
+type UpdateBookmarkNormalizedURLParams struct {
+	NormalizedUrl *string `json:"normalized_url"`
+	ID            int64   `json:"id"`
+}
+
+func (q *Queries) UpdateBookmarkNormalizedURL(ctx context.Context, arg UpdateBookmarkNormalizedURLParams) error {
+	_, err := q.db.ExecContext(ctx, updateBookmarkNormalizedURL, arg.NormalizedUrl, arg.ID)
+	return err
+}
+
 const updateBookmarkPosition = `-- name: UpdateBookmarkPosition :exec
 UPDATE bookmarks 
 SET collection_id = ?, sort_order = ?, updated_at = CURRENT_TIMESTAMP 
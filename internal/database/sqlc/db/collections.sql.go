@@ -11,9 +11,9 @@ import (
 )
 
 const createCollection = `-- name: CreateCollection :one
-INSERT INTO collections (name, slug, description, color, parent_id, sort_order, is_public, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-RETURNING id, name, slug, description, color, parent_id, sort_order, is_public, created_at, updated_at
+INSERT INTO collections (name, slug, description, color, cover_image, parent_id, sort_order, is_public, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+RETURNING id, name, slug, description, color, parent_id, sort_order, is_public, created_at, updated_at, cover_image
 `
 
 type CreateCollectionParams struct {
@@ -21,6 +21,7 @@ type CreateCollectionParams struct {
 	Slug        string  `json:"slug"`
 	Description *string `json:"description"`
 	Color       *string `json:"color"`
+	CoverImage  *string `json:"cover_image"`
 	ParentID    *int64  `json:"parent_id"`
 	SortOrder   *int64  `json:"sort_order"`
 	IsPublic    *int64  `json:"is_public"`
@@ -32,6 +33,7 @@ func (q *Queries) CreateCollection(ctx context.Context, arg CreateCollectionPara
 		arg.Slug,
 		arg.Description,
 		arg.Color,
+		arg.CoverImage,
 		arg.ParentID,
 		arg.SortOrder,
 		arg.IsPublic,
@@ -48,6 +50,7 @@ func (q *Queries) CreateCollection(ctx context.Context, arg CreateCollectionPara
 		&i.IsPublic,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CoverImage,
 	)
 	return i, err
 }
@@ -64,7 +67,7 @@ func (q *Queries) DeleteCollection(ctx context.Context, id int64) error {
 const getBookmarksByCollectionID = `-- name: GetBookmarksByCollectionID :many
 SELECT id, title, url, domain, is_public, is_favorite, created_at
 FROM bookmarks
-WHERE collection_id = ?
+WHERE collection_id = ? AND is_archived = 0
 ORDER BY COALESCE(sort_order, 999999) ASC, updated_at DESC
 LIMIT 6
 `
@@ -110,24 +113,31 @@ func (q *Queries) GetBookmarksByCollectionID(ctx context.Context, collectionID *
 	return items, nil
 }
 
-const getCollectionBookmarkCount = `-- name: GetCollectionBookmarkCount :one
-SELECT COUNT(*) FROM bookmarks WHERE collection_id = ?
+const getCollectionByID = `-- name: GetCollectionByID :one
+SELECT c.id, c.name, c.slug, c.description, c.color, c.parent_id, c.sort_order, c.is_public, c.created_at, c.updated_at, c.cover_image,
+    (SELECT COUNT(*) FROM bookmarks b WHERE b.collection_id = c.id) as bookmark_count
+FROM collections c
+WHERE c.id = ?
 `
 
-func (q *Queries) GetCollectionBookmarkCount(ctx context.Context, collectionID *int64) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getCollectionBookmarkCount, collectionID)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+type GetCollectionByIDRow struct {
+	ID            int64      `json:"id"`
+	Name          string     `json:"name"`
+	Slug          string     `json:"slug"`
+	Description   *string    `json:"description"`
+	Color         *string    `json:"color"`
+	ParentID      *int64     `json:"parent_id"`
+	SortOrder     *int64     `json:"sort_order"`
+	IsPublic      *int64     `json:"is_public"`
+	CreatedAt     *time.Time `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at"`
+	CoverImage    *string    `json:"cover_image"`
+	BookmarkCount int64      `json:"bookmark_count"`
 }
 
-const getCollectionByID = `-- name: GetCollectionByID :one
-SELECT id, name, slug, description, color, parent_id, sort_order, is_public, created_at, updated_at FROM collections WHERE id = ?
-`
-
-func (q *Queries) GetCollectionByID(ctx context.Context, id int64) (Collection, error) {
+func (q *Queries) GetCollectionByID(ctx context.Context, id int64) (GetCollectionByIDRow, error) {
 	row := q.db.QueryRowContext(ctx, getCollectionByID, id)
-	var i Collection
+	var i GetCollectionByIDRow
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
@@ -139,17 +149,37 @@ func (q *Queries) GetCollectionByID(ctx context.Context, id int64) (Collection,
 		&i.IsPublic,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CoverImage,
+		&i.BookmarkCount,
 	)
 	return i, err
 }
 
 const getCollectionBySlug = `-- name: GetCollectionBySlug :one
-SELECT id, name, slug, description, color, parent_id, sort_order, is_public, created_at, updated_at FROM collections WHERE slug = ?
+SELECT c.id, c.name, c.slug, c.description, c.color, c.parent_id, c.sort_order, c.is_public, c.created_at, c.updated_at, c.cover_image,
+    (SELECT COUNT(*) FROM bookmarks b WHERE b.collection_id = c.id) as bookmark_count
+FROM collections c
+WHERE c.slug = ?
 `
 
-func (q *Queries) GetCollectionBySlug(ctx context.Context, slug string) (Collection, error) {
+type GetCollectionBySlugRow struct {
+	ID            int64      `json:"id"`
+	Name          string     `json:"name"`
+	Slug          string     `json:"slug"`
+	Description   *string    `json:"description"`
+	Color         *string    `json:"color"`
+	ParentID      *int64     `json:"parent_id"`
+	SortOrder     *int64     `json:"sort_order"`
+	IsPublic      *int64     `json:"is_public"`
+	CreatedAt     *time.Time `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at"`
+	CoverImage    *string    `json:"cover_image"`
+	BookmarkCount int64      `json:"bookmark_count"`
+}
+
+func (q *Queries) GetCollectionBySlug(ctx context.Context, slug string) (GetCollectionBySlugRow, error) {
 	row := q.db.QueryRowContext(ctx, getCollectionBySlug, slug)
-	var i Collection
+	var i GetCollectionBySlugRow
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
@@ -161,14 +191,50 @@ func (q *Queries) GetCollectionBySlug(ctx context.Context, slug string) (Collect
 		&i.IsPublic,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CoverImage,
+		&i.BookmarkCount,
 	)
 	return i, err
 }
 
+const getCollectionSortOrders = `-- name: GetCollectionSortOrders :many
+SELECT id, COALESCE(sort_order, 0) as sort_order
+FROM collections
+ORDER BY sort_order, name
+`
+
+type GetCollectionSortOrdersRow struct {
+	ID        int64 `json:"id"`
+	SortOrder int64 `json:"sort_order"`
+}
+
+func (q *Queries) GetCollectionSortOrders(ctx context.Context) ([]GetCollectionSortOrdersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCollectionSortOrders)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetCollectionSortOrdersRow{}
+	for rows.Next() {
+		var i GetCollectionSortOrdersRow
+		if err := rows.Scan(&i.ID, &i.SortOrder); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRecentBookmarksByCollectionID = `-- name: GetRecentBookmarksByCollectionID :many
 SELECT id, title, url, domain, is_favorite, is_public, updated_at
 FROM bookmarks
-WHERE collection_id = ?
+WHERE collection_id = ? AND is_archived = 0
 ORDER BY COALESCE(sort_order, 999999) ASC, updated_at DESC
 LIMIT ?
 `
@@ -219,8 +285,68 @@ func (q *Queries) GetRecentBookmarksByCollectionID(ctx context.Context, arg GetR
 	return items, nil
 }
 
+const getRecentBookmarksForAllCollections = `-- name: GetRecentBookmarksForAllCollections :many
+SELECT id, collection_id, title, url, domain, is_favorite, is_public, updated_at
+FROM (
+    SELECT id, collection_id, title, url, domain, is_favorite, is_public, updated_at,
+        ROW_NUMBER() OVER (
+            PARTITION BY collection_id
+            ORDER BY COALESCE(sort_order, 999999) ASC, updated_at DESC
+        ) AS rn
+    FROM bookmarks
+    WHERE collection_id IS NOT NULL AND is_archived = 0
+)
+WHERE rn <= CAST(? AS INTEGER)
+ORDER BY collection_id, rn
+`
+
+type GetRecentBookmarksForAllCollectionsRow struct {
+	ID           int64      `json:"id"`
+	CollectionID *int64     `json:"collection_id"`
+	Title        string     `json:"title"`
+	Url          string     `json:"url"`
+	Domain       *string    `json:"domain"`
+	IsFavorite   *int64     `json:"is_favorite"`
+	IsPublic     *int64     `json:"is_public"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+}
+
+// Recent bookmarks of every collection, at most per_collection each, in the
+// order GetRecentBookmarksByCollectionID uses, grouped by collection.
+func (q *Queries) GetRecentBookmarksForAllCollections(ctx context.Context, perCollection int64) ([]GetRecentBookmarksForAllCollectionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentBookmarksForAllCollections, perCollection)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetRecentBookmarksForAllCollectionsRow{}
+	for rows.Next() {
+		var i GetRecentBookmarksForAllCollectionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CollectionID,
+			&i.Title,
+			&i.Url,
+			&i.Domain,
+			&i.IsFavorite,
+			&i.IsPublic,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAllCollections = `-- name: ListAllCollections :many
-SELECT id, name, slug, description, color, parent_id, sort_order, is_public, created_at, updated_at FROM collections ORDER BY sort_order, name
+SELECT id, name, slug, description, color, parent_id, sort_order, is_public, created_at, updated_at, cover_image FROM collections ORDER BY sort_order, name
 `
 
 func (q *Queries) ListAllCollections(ctx context.Context) ([]Collection, error) {
@@ -243,6 +369,7 @@ func (q *Queries) ListAllCollections(ctx context.Context) ([]Collection, error)
 			&i.IsPublic,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CoverImage,
 		); err != nil {
 			return nil, err
 		}
@@ -258,7 +385,7 @@ func (q *Queries) ListAllCollections(ctx context.Context) ([]Collection, error)
 }
 
 const listAllCollectionsWithCounts = `-- name: ListAllCollectionsWithCounts :many
-SELECT c.id, c.name, c.slug, c.description, c.color, c.parent_id, c.sort_order, c.is_public, c.created_at, c.updated_at, 
+SELECT c.id, c.name, c.slug, c.description, c.color, c.parent_id, c.sort_order, c.is_public, c.created_at, c.updated_at, c.cover_image, 
     (SELECT COUNT(*) FROM bookmarks b WHERE b.collection_id = c.id) as bookmark_count
 FROM collections c
 ORDER BY c.sort_order, c.name
@@ -275,6 +402,7 @@ type ListAllCollectionsWithCountsRow struct {
 	IsPublic      *int64     `json:"is_public"`
 	CreatedAt     *time.Time `json:"created_at"`
 	UpdatedAt     *time.Time `json:"updated_at"`
+	CoverImage    *string    `json:"cover_image"`
 	BookmarkCount int64      `json:"bookmark_count"`
 }
 
@@ -298,6 +426,7 @@ func (q *Queries) ListAllCollectionsWithCounts(ctx context.Context) ([]ListAllCo
 			&i.IsPublic,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CoverImage,
 			&i.BookmarkCount,
 		); err != nil {
 			return nil, err
@@ -314,7 +443,7 @@ func (q *Queries) ListAllCollectionsWithCounts(ctx context.Context) ([]ListAllCo
 }
 
 const listPublicCollections = `-- name: ListPublicCollections :many
-SELECT id, name, slug, description, color, parent_id, sort_order, is_public, created_at, updated_at FROM collections WHERE is_public = 1 ORDER BY sort_order, name
+SELECT id, name, slug, description, color, parent_id, sort_order, is_public, created_at, updated_at, cover_image FROM collections WHERE is_public = 1 ORDER BY sort_order, name
 `
 
 func (q *Queries) ListPublicCollections(ctx context.Context) ([]Collection, error) {
@@ -337,6 +466,7 @@ func (q *Queries) ListPublicCollections(ctx context.Context) ([]Collection, erro
 			&i.IsPublic,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CoverImage,
 		); err != nil {
 			return nil, err
 		}
@@ -352,7 +482,7 @@ func (q *Queries) ListPublicCollections(ctx context.Context) ([]Collection, erro
 }
 
 const listPublicCollectionsWithCounts = `-- name: ListPublicCollectionsWithCounts :many
-SELECT c.id, c.name, c.slug, c.description, c.color, c.parent_id, c.sort_order, c.is_public, c.created_at, c.updated_at, 
+SELECT c.id, c.name, c.slug, c.description, c.color, c.parent_id, c.sort_order, c.is_public, c.created_at, c.updated_at, c.cover_image, 
     (SELECT COUNT(*) FROM bookmarks b WHERE b.collection_id = c.id) as bookmark_count
 FROM collections c
 WHERE c.is_public = 1
@@ -370,6 +500,7 @@ type ListPublicCollectionsWithCountsRow struct {
 	IsPublic      *int64     `json:"is_public"`
 	CreatedAt     *time.Time `json:"created_at"`
 	UpdatedAt     *time.Time `json:"updated_at"`
+	CoverImage    *string    `json:"cover_image"`
 	BookmarkCount int64      `json:"bookmark_count"`
 }
 
@@ -393,6 +524,7 @@ func (q *Queries) ListPublicCollectionsWithCounts(ctx context.Context) ([]ListPu
 			&i.IsPublic,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CoverImage,
 			&i.BookmarkCount,
 		); err != nil {
 			return nil, err
@@ -410,8 +542,8 @@ func (q *Queries) ListPublicCollectionsWithCounts(ctx context.Context) ([]ListPu
 
 const updateCollection = `-- name: UpdateCollection :exec
 UPDATE collections 
-SET name = ?, slug = ?, description = ?, color = ?,
-    parent_id = ?, sort_order = ?, is_public = ?, updated_at = CURRENT_TIMESTAMP 
+SET name = ?, slug = ?, description = ?, color = ?, cover_image = ?,
+    parent_id = ?, is_public = ?, updated_at = CURRENT_TIMESTAMP 
 WHERE id = ?
 `
 
@@ -420,8 +552,8 @@ type UpdateCollectionParams struct {
 	Slug        string  `json:"slug"`
 	Description *string `json:"description"`
 	Color       *string `json:"color"`
+	CoverImage  *string `json:"cover_image"`
 	ParentID    *int64  `json:"parent_id"`
-	SortOrder   *int64  `json:"sort_order"`
 	IsPublic    *int64  `json:"is_public"`
 	ID          int64   `json:"id"`
 }
@@ -432,14 +564,28 @@ func (q *Queries) UpdateCollection(ctx context.Context, arg UpdateCollectionPara
 		arg.Slug,
 		arg.Description,
 		arg.Color,
+		arg.CoverImage,
 		arg.ParentID,
-		arg.SortOrder,
 		arg.IsPublic,
 		arg.ID,
 	)
 	return err
 }
 
+const updateCollectionPosition = `-- name: UpdateCollectionPosition :exec
+UPDATE collections SET sort_order = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type UpdateCollectionPositionParams struct {
+	SortOrder *int64 `json:"sort_order"`
+	ID        int64  `json:"id"`
+}
+
+func (q *Queries) UpdateCollectionPosition(ctx context.Context, arg UpdateCollectionPositionParams) error {
+	_, err := q.db.ExecContext(ctx, updateCollectionPosition, arg.SortOrder, arg.ID)
+	return err
+}
+
 const updateCollectionPublic = `-- name: UpdateCollectionPublic :exec
 
 UPDATE collections SET is_public = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
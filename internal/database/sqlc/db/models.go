@@ -19,19 +19,26 @@ type Activity struct {
 }
 
 type Bookmark struct {
-	ID           int64      `json:"id"`
-	Url          string     `json:"url"`
-	Title        string     `json:"title"`
-	Description  *string    `json:"description"`
-	CoverImage   *string    `json:"cover_image"`
-	Favicon      *string    `json:"favicon"`
-	Domain       *string    `json:"domain"`
-	CollectionID *int64     `json:"collection_id"`
-	IsPublic     *int64     `json:"is_public"`
-	IsFavorite   *int64     `json:"is_favorite"`
-	SortOrder    *int64     `json:"sort_order"`
-	CreatedAt    *time.Time `json:"created_at"`
-	UpdatedAt    *time.Time `json:"updated_at"`
+	ID            int64      `json:"id"`
+	Url           string     `json:"url"`
+	Title         string     `json:"title"`
+	Description   *string    `json:"description"`
+	CoverImage    *string    `json:"cover_image"`
+	Favicon       *string    `json:"favicon"`
+	Domain        *string    `json:"domain"`
+	CollectionID  *int64     `json:"collection_id"`
+	IsPublic      *int64     `json:"is_public"`
+	IsFavorite    *int64     `json:"is_favorite"`
+	SortOrder     *int64     `json:"sort_order"`
+	CreatedAt     *time.Time `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at"`
+	CoverImageID  *int64     `json:"cover_image_id"`
+	FaviconID     *int64     `json:"favicon_id"`
+	ThumbnailID   *int64     `json:"thumbnail_id"`
+	NormalizedUrl *string    `json:"normalized_url"`
+	ClickCount    int64      `json:"click_count"`
+	IsArchived    int64      `json:"is_archived"`
+	Note          *string    `json:"note"`
 }
 
 type Collection struct {
@@ -45,6 +52,16 @@ type Collection struct {
 	IsPublic    *int64     `json:"is_public"`
 	CreatedAt   *time.Time `json:"created_at"`
 	UpdatedAt   *time.Time `json:"updated_at"`
+	CoverImage  *string    `json:"cover_image"`
+}
+
+type Image struct {
+	ID        int64      `json:"id"`
+	MimeType  string     `json:"mime_type"`
+	Data      []byte     `json:"data"`
+	Size      int64      `json:"size"`
+	SourceUrl *string    `json:"source_url"`
+	CreatedAt *time.Time `json:"created_at"`
 }
 
 type Post struct {
@@ -58,6 +75,8 @@ type Post struct {
 	PublishedAt *time.Time `json:"published_at"`
 	CreatedAt   *time.Time `json:"created_at"`
 	UpdatedAt   *time.Time `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+	IsFeatured  int64      `json:"is_featured"`
 }
 
 type PostTag struct {
@@ -66,24 +85,38 @@ type PostTag struct {
 	CreatedAt *time.Time `json:"created_at"`
 }
 
+type SearchQuery struct {
+	ID          int64      `json:"id"`
+	Query       string     `json:"query"`
+	ResultCount int64      `json:"result_count"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
 type Session struct {
-	ID        string     `json:"id"`
-	UserID    int64      `json:"user_id"`
-	ExpiresAt time.Time  `json:"expires_at"`
-	CreatedAt *time.Time `json:"created_at"`
+	ID              string     `json:"id"`
+	UserID          int64      `json:"user_id"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	CreatedAt       *time.Time `json:"created_at"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	UserAgent       string     `json:"user_agent"`
+	IpAddress       string     `json:"ip_address"`
+	LastSeenAt      *time.Time `json:"last_seen_at"`
 }
 
 type Tag struct {
 	ID        int64      `json:"id"`
 	Name      string     `json:"name"`
 	Slug      string     `json:"slug"`
+	Color     *string    `json:"color"`
 	CreatedAt *time.Time `json:"created_at"`
 }
 
 type User struct {
-	ID           int64      `json:"id"`
-	Username     string     `json:"username"`
-	PasswordHash string     `json:"password_hash"`
-	CreatedAt    *time.Time `json:"created_at"`
-	UpdatedAt    *time.Time `json:"updated_at"`
+	ID                  int64      `json:"id"`
+	Username            string     `json:"username"`
+	PasswordHash        string     `json:"password_hash"`
+	CreatedAt           *time.Time `json:"created_at"`
+	UpdatedAt           *time.Time `json:"updated_at"`
+	FailedLoginAttempts int64      `json:"failed_login_attempts"`
+	LockedUntil         *time.Time `json:"locked_until"`
 }
@@ -24,10 +24,43 @@ func (q *Queries) AddPostTag(ctx context.Context, arg AddPostTagParams) error {
 	return err
 }
 
+const countAllPosts = `-- name: CountAllPosts :one
+SELECT COUNT(*) FROM posts WHERE deleted_at IS NULL
+`
+
+func (q *Queries) CountAllPosts(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAllPosts)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFeaturedPosts = `-- name: CountFeaturedPosts :one
+SELECT COUNT(*) FROM posts WHERE is_featured = 1 AND is_draft = 0 AND deleted_at IS NULL
+`
+
+func (q *Queries) CountFeaturedPosts(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeaturedPosts)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPublishedPosts = `-- name: CountPublishedPosts :one
+SELECT COUNT(*) FROM posts WHERE is_draft = 0 AND deleted_at IS NULL
+`
+
+func (q *Queries) CountPublishedPosts(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPublishedPosts)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createPost = `-- name: CreatePost :one
 INSERT INTO posts (title, slug, content, excerpt, cover_image, is_draft, published_at, created_at, updated_at)
 VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-RETURNING id, title, slug, content, excerpt, cover_image, is_draft, published_at, created_at, updated_at
+RETURNING id, title, slug, content, excerpt, cover_image, is_draft, published_at, created_at, updated_at, deleted_at, is_featured
 `
 
 type CreatePostParams struct {
@@ -62,19 +95,12 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.PublishedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.IsFeatured,
 	)
 	return i, err
 }
 
-const deletePost = `-- name: DeletePost :exec
-DELETE FROM posts WHERE id = ?
-`
-
-func (q *Queries) DeletePost(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deletePost, id)
-	return err
-}
-
 const deletePostTags = `-- name: DeletePostTags :exec
 DELETE FROM post_tags WHERE post_id = ?
 `
@@ -85,7 +111,7 @@ func (q *Queries) DeletePostTags(ctx context.Context, postID int64) error {
 }
 
 const getPostByID = `-- name: GetPostByID :one
-SELECT id, title, slug, content, excerpt, cover_image, is_draft, published_at, created_at, updated_at FROM posts WHERE id = ?
+SELECT id, title, slug, content, excerpt, cover_image, is_draft, published_at, created_at, updated_at, deleted_at, is_featured FROM posts WHERE id = ?
 `
 
 func (q *Queries) GetPostByID(ctx context.Context, id int64) (Post, error) {
@@ -102,12 +128,14 @@ func (q *Queries) GetPostByID(ctx context.Context, id int64) (Post, error) {
 		&i.PublishedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.IsFeatured,
 	)
 	return i, err
 }
 
 const getPostBySlug = `-- name: GetPostBySlug :one
-SELECT id, title, slug, content, excerpt, cover_image, is_draft, published_at, created_at, updated_at FROM posts WHERE slug = ?
+SELECT id, title, slug, content, excerpt, cover_image, is_draft, published_at, created_at, updated_at, deleted_at, is_featured FROM posts WHERE slug = ?
 `
 
 func (q *Queries) GetPostBySlug(ctx context.Context, slug string) (Post, error) {
@@ -124,12 +152,14 @@ func (q *Queries) GetPostBySlug(ctx context.Context, slug string) (Post, error)
 		&i.PublishedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.IsFeatured,
 	)
 	return i, err
 }
 
 const getPostTags = `-- name: GetPostTags :many
-SELECT t.id, t.name, t.slug, t.created_at
+SELECT t.id, t.name, t.slug, t.color, t.created_at
 FROM tags t
 INNER JOIN post_tags pt ON t.id = pt.tag_id
 WHERE pt.post_id = ?
@@ -148,6 +178,7 @@ func (q *Queries) GetPostTags(ctx context.Context, postID int64) ([]Tag, error)
 			&i.ID,
 			&i.Name,
 			&i.Slug,
+			&i.Color,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -164,8 +195,9 @@ func (q *Queries) GetPostTags(ctx context.Context, postID int64) ([]Tag, error)
 }
 
 const listAllPosts = `-- name: ListAllPosts :many
-SELECT id, title, slug, content, excerpt, cover_image, is_draft, published_at, created_at, updated_at FROM posts 
-ORDER BY COALESCE(published_at, created_at) DESC 
+SELECT id, title, slug, content, excerpt, cover_image, is_draft, published_at, created_at, updated_at, deleted_at, is_featured FROM posts 
+WHERE deleted_at IS NULL
+ORDER BY COALESCE(published_at, created_at) DESC, id DESC
 LIMIT ? OFFSET ?
 `
 
@@ -194,6 +226,8 @@ func (q *Queries) ListAllPosts(ctx context.Context, arg ListAllPostsParams) ([]P
 			&i.PublishedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.IsFeatured,
 		); err != nil {
 			return nil, err
 		}
@@ -208,9 +242,80 @@ func (q *Queries) ListAllPosts(ctx context.Context, arg ListAllPostsParams) ([]P
 	return items, nil
 }
 
+const listFeaturedPosts = `-- name: ListFeaturedPosts :many
+SELECT id, title, slug, content, excerpt, cover_image, is_draft, published_at, created_at, updated_at, deleted_at, is_featured FROM posts
+WHERE is_featured = 1 AND is_draft = 0 AND deleted_at IS NULL
+ORDER BY COALESCE(published_at, created_at) DESC, id DESC
+LIMIT ?
+`
+
+func (q *Queries) ListFeaturedPosts(ctx context.Context, limit int64) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listFeaturedPosts, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Content,
+			&i.Excerpt,
+			&i.CoverImage,
+			&i.IsDraft,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.IsFeatured,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostsTrashedBefore = `-- name: ListPostsTrashedBefore :many
+SELECT id FROM posts
+WHERE deleted_at IS NOT NULL AND deleted_at < ?
+`
+
+func (q *Queries) ListPostsTrashedBefore(ctx context.Context, deletedAt *time.Time) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsTrashedBefore, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPublishedPosts = `-- name: ListPublishedPosts :many
-SELECT id, title, slug, content, excerpt, cover_image, is_draft, published_at, created_at, updated_at FROM posts 
-WHERE is_draft = 0 
+SELECT id, title, slug, content, excerpt, cover_image, is_draft, published_at, created_at, updated_at, deleted_at, is_featured FROM posts 
+WHERE is_draft = 0 AND deleted_at IS NULL
 ORDER BY COALESCE(published_at, created_at) DESC 
 LIMIT ? OFFSET ?
 `
@@ -240,6 +345,51 @@ func (q *Queries) ListPublishedPosts(ctx context.Context, arg ListPublishedPosts
 			&i.PublishedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.IsFeatured,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublishedPostsByTag = `-- name: ListPublishedPostsByTag :many
+SELECT p.id, p.title, p.slug, p.content, p.excerpt, p.cover_image, p.is_draft, p.published_at, p.created_at, p.updated_at, p.deleted_at, p.is_featured FROM posts p
+INNER JOIN post_tags pt ON p.id = pt.post_id
+WHERE pt.tag_id = ? AND p.is_draft = 0 AND p.deleted_at IS NULL
+ORDER BY COALESCE(p.published_at, p.created_at) DESC
+`
+
+func (q *Queries) ListPublishedPostsByTag(ctx context.Context, tagID int64) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPublishedPostsByTag, tagID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Content,
+			&i.Excerpt,
+			&i.CoverImage,
+			&i.IsDraft,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.IsFeatured,
 		); err != nil {
 			return nil, err
 		}
@@ -254,6 +404,90 @@ func (q *Queries) ListPublishedPosts(ctx context.Context, arg ListPublishedPosts
 	return items, nil
 }
 
+const listTrashedPosts = `-- name: ListTrashedPosts :many
+SELECT id, title, slug, content, excerpt, cover_image, is_draft, published_at, created_at, updated_at, deleted_at, is_featured FROM posts
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id DESC
+`
+
+func (q *Queries) ListTrashedPosts(ctx context.Context) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedPosts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Content,
+			&i.Excerpt,
+			&i.CoverImage,
+			&i.IsDraft,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.IsFeatured,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgePost = `-- name: PurgePost :execrows
+DELETE FROM posts WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+// Only posts already in the trash can be removed for good.
+func (q *Queries) PurgePost(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgePost, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restorePost = `-- name: RestorePost :execrows
+UPDATE posts SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestorePost(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restorePost, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const trashPost = `-- name: TrashPost :execrows
+UPDATE posts SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL
+`
+
+type TrashPostParams struct {
+	DeletedAt *time.Time `json:"deleted_at"`
+	ID        int64      `json:"id"`
+}
+
+func (q *Queries) TrashPost(ctx context.Context, arg TrashPostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, trashPost, arg.DeletedAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updatePost = `-- name: UpdatePost :exec
 UPDATE posts 
 SET title = ?, slug = ?, content = ?, excerpt = ?, cover_image = ?, 
@@ -304,3 +538,17 @@ func (q *Queries) UpdatePostDraft(ctx context.Context, arg UpdatePostDraftParams
 	_, err := q.db.ExecContext(ctx, updatePostDraft, arg.IsDraft, arg.PublishedAt, arg.ID)
 	return err
 }
+
+const updatePostFeatured = `-- name: UpdatePostFeatured :exec
+UPDATE posts SET is_featured = ? WHERE id = ?
+`
+
+type UpdatePostFeaturedParams struct {
+	IsFeatured int64 `json:"is_featured"`
+	ID         int64 `json:"id"`
+}
+
+func (q *Queries) UpdatePostFeatured(ctx context.Context, arg UpdatePostFeaturedParams) error {
+	_, err := q.db.ExecContext(ctx, updatePostFeatured, arg.IsFeatured, arg.ID)
+	return err
+}
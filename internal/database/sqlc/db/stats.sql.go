@@ -11,7 +11,7 @@ import (
 )
 
 const countDraftPosts = `-- name: CountDraftPosts :one
-SELECT COUNT(*) FROM posts WHERE is_draft = 1
+SELECT COUNT(*) FROM posts WHERE is_draft = 1 AND deleted_at IS NULL
 `
 
 func (q *Queries) CountDraftPosts(ctx context.Context) (int64, error) {
@@ -77,7 +77,7 @@ func (q *Queries) GetBookmarksCreatedSince(ctx context.Context, createdAt *time.
 const getDatabaseStats = `-- name: GetDatabaseStats :one
 SELECT 
     (SELECT COUNT(*) FROM bookmarks) as total_bookmarks,
-    (SELECT COUNT(*) FROM posts) as total_posts,
+    (SELECT COUNT(*) FROM posts WHERE deleted_at IS NULL) as total_posts,
     (SELECT COUNT(*) FROM collections) as total_collections,
     (SELECT COUNT(*) FROM tags) as total_tags
 `
@@ -103,7 +103,7 @@ func (q *Queries) GetDatabaseStats(ctx context.Context) (GetDatabaseStatsRow, er
 
 const getPostsCreatedSince = `-- name: GetPostsCreatedSince :one
 SELECT COUNT(*) FROM posts
-WHERE created_at >= ?
+WHERE created_at >= ? AND deleted_at IS NULL
 `
 
 func (q *Queries) GetPostsCreatedSince(ctx context.Context, createdAt *time.Time) (int64, error) {
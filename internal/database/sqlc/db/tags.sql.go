@@ -10,24 +10,37 @@ import (
 	"time"
 )
 
+const countPostsByTagID = `-- name: CountPostsByTagID :one
+SELECT COUNT(*) FROM post_tags WHERE tag_id = ?
+`
+
+func (q *Queries) CountPostsByTagID(ctx context.Context, tagID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPostsByTagID, tagID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createTag = `-- name: CreateTag :one
-INSERT INTO tags (name, slug, created_at)
-VALUES (?, ?, CURRENT_TIMESTAMP)
-RETURNING id, name, slug, created_at
+INSERT INTO tags (name, slug, color, created_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+RETURNING id, name, slug, color, created_at
 `
 
 type CreateTagParams struct {
-	Name string `json:"name"`
-	Slug string `json:"slug"`
+	Name  string  `json:"name"`
+	Slug  string  `json:"slug"`
+	Color *string `json:"color"`
 }
 
 func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error) {
-	row := q.db.QueryRowContext(ctx, createTag, arg.Name, arg.Slug)
+	row := q.db.QueryRowContext(ctx, createTag, arg.Name, arg.Slug, arg.Color)
 	var i Tag
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.Slug,
+		&i.Color,
 		&i.CreatedAt,
 	)
 	return i, err
@@ -46,7 +59,7 @@ const getPostsByTagID = `-- name: GetPostsByTagID :many
 SELECT p.id, p.title, p.slug, p.is_draft, p.published_at, p.created_at
 FROM posts p
 JOIN post_tags pt ON p.id = pt.post_id
-WHERE pt.tag_id = ?
+WHERE pt.tag_id = ? AND p.deleted_at IS NULL
 ORDER BY p.created_at DESC
 `
 
@@ -89,8 +102,25 @@ func (q *Queries) GetPostsByTagID(ctx context.Context, tagID int64) ([]GetPostsB
 	return items, nil
 }
 
+const getTagByID = `-- name: GetTagByID :one
+SELECT id, name, slug, color, created_at FROM tags WHERE id = ?
+`
+
+func (q *Queries) GetTagByID(ctx context.Context, id int64) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, getTagByID, id)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Color,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getTagByName = `-- name: GetTagByName :one
-SELECT id, name, slug, created_at FROM tags WHERE name = ?
+SELECT id, name, slug, color, created_at FROM tags WHERE name = ?
 `
 
 func (q *Queries) GetTagByName(ctx context.Context, name string) (Tag, error) {
@@ -100,13 +130,14 @@ func (q *Queries) GetTagByName(ctx context.Context, name string) (Tag, error) {
 		&i.ID,
 		&i.Name,
 		&i.Slug,
+		&i.Color,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const getTagBySlug = `-- name: GetTagBySlug :one
-SELECT id, name, slug, created_at FROM tags WHERE slug = ?
+SELECT id, name, slug, color, created_at FROM tags WHERE slug = ?
 `
 
 func (q *Queries) GetTagBySlug(ctx context.Context, slug string) (Tag, error) {
@@ -116,13 +147,64 @@ func (q *Queries) GetTagBySlug(ctx context.Context, slug string) (Tag, error) {
 		&i.ID,
 		&i.Name,
 		&i.Slug,
+		&i.Color,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
+const listPublishedTagsWithCounts = `-- name: ListPublishedTagsWithCounts :many
+SELECT t.id, t.name, t.slug, t.color, t.created_at, COUNT(*) as usage_count
+FROM tags t
+INNER JOIN post_tags pt ON pt.tag_id = t.id
+INNER JOIN posts p ON p.id = pt.post_id
+WHERE p.is_draft = 0 AND p.deleted_at IS NULL
+GROUP BY t.id
+ORDER BY usage_count DESC, t.name
+`
+
+type ListPublishedTagsWithCountsRow struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Slug       string     `json:"slug"`
+	Color      *string    `json:"color"`
+	CreatedAt  *time.Time `json:"created_at"`
+	UsageCount int64      `json:"usage_count"`
+}
+
+// Only tags used by at least one published post
+func (q *Queries) ListPublishedTagsWithCounts(ctx context.Context) ([]ListPublishedTagsWithCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPublishedTagsWithCounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPublishedTagsWithCountsRow{}
+	for rows.Next() {
+		var i ListPublishedTagsWithCountsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Color,
+			&i.CreatedAt,
+			&i.UsageCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTags = `-- name: ListTags :many
-SELECT id, name, slug, created_at FROM tags ORDER BY name
+SELECT id, name, slug, color, created_at FROM tags ORDER BY name
 `
 
 func (q *Queries) ListTags(ctx context.Context) ([]Tag, error) {
@@ -138,6 +220,7 @@ func (q *Queries) ListTags(ctx context.Context) ([]Tag, error) {
 			&i.ID,
 			&i.Name,
 			&i.Slug,
+			&i.Color,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -154,7 +237,7 @@ func (q *Queries) ListTags(ctx context.Context) ([]Tag, error) {
 }
 
 const listTagsWithCounts = `-- name: ListTagsWithCounts :many
-SELECT t.id, t.name, t.slug, t.created_at,
+SELECT t.id, t.name, t.slug, t.color, t.created_at,
     (SELECT COUNT(*) FROM post_tags pt WHERE pt.tag_id = t.id) as usage_count
 FROM tags t
 ORDER BY usage_count DESC, t.name
@@ -164,6 +247,7 @@ type ListTagsWithCountsRow struct {
 	ID         int64      `json:"id"`
 	Name       string     `json:"name"`
 	Slug       string     `json:"slug"`
+	Color      *string    `json:"color"`
 	CreatedAt  *time.Time `json:"created_at"`
 	UsageCount int64      `json:"usage_count"`
 }
@@ -181,6 +265,7 @@ func (q *Queries) ListTagsWithCounts(ctx context.Context) ([]ListTagsWithCountsR
 			&i.ID,
 			&i.Name,
 			&i.Slug,
+			&i.Color,
 			&i.CreatedAt,
 			&i.UsageCount,
 		); err != nil {
@@ -196,3 +281,40 @@ func (q *Queries) ListTagsWithCounts(ctx context.Context) ([]ListTagsWithCountsR
 	}
 	return items, nil
 }
+
+const retagBookmarks = `-- name: RetagBookmarks :exec
+INSERT OR IGNORE INTO bookmark_tags (bookmark_id, tag_id, created_at)
+SELECT bookmark_id, CAST(? AS INTEGER), created_at
+FROM bookmark_tags
+WHERE tag_id = ?
+`
+
+type RetagBookmarksParams struct {
+	TargetID int64 `json:"target_id"`
+	SourceID int64 `json:"source_id"`
+}
+
+// Like RetagPosts, for bookmark_tags.
+func (q *Queries) RetagBookmarks(ctx context.Context, arg RetagBookmarksParams) error {
+	_, err := q.db.ExecContext(ctx, retagBookmarks, arg.TargetID, arg.SourceID)
+	return err
+}
+
+const retagPosts = `-- name: RetagPosts :exec
+INSERT OR IGNORE INTO post_tags (post_id, tag_id, created_at)
+SELECT post_id, CAST(? AS INTEGER), created_at
+FROM post_tags
+WHERE tag_id = ?
+`
+
+type RetagPostsParams struct {
+	TargetID int64 `json:"target_id"`
+	SourceID int64 `json:"source_id"`
+}
+
+// Adds target_id to every post tagged source_id. Posts that already have
+// target_id are skipped instead of violating the primary key.
+func (q *Queries) RetagPosts(ctx context.Context, arg RetagPostsParams) error {
+	_, err := q.db.ExecContext(ctx, retagPosts, arg.TargetID, arg.SourceID)
+	return err
+}
@@ -10,35 +10,52 @@ import (
 	"time"
 )
 
-const cleanupExpiredSessions = `-- name: CleanupExpiredSessions :exec
+const cleanupExpiredSessions = `-- name: CleanupExpiredSessions :execrows
 DELETE FROM sessions WHERE expires_at < ?
 `
 
-func (q *Queries) CleanupExpiredSessions(ctx context.Context, expiresAt time.Time) error {
-	_, err := q.db.ExecContext(ctx, cleanupExpiredSessions, expiresAt)
-	return err
+func (q *Queries) CleanupExpiredSessions(ctx context.Context, expiresAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, cleanupExpiredSessions, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const createSession = `-- name: CreateSession :one
-INSERT INTO sessions (id, user_id, expires_at, created_at)
-VALUES (?, ?, ?, CURRENT_TIMESTAMP)
-RETURNING id, user_id, expires_at, created_at
+INSERT INTO sessions (id, user_id, expires_at, duration_seconds, user_agent, ip_address, created_at, last_seen_at)
+VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+RETURNING id, user_id, expires_at, created_at, duration_seconds, user_agent, ip_address, last_seen_at
 `
 
 type CreateSessionParams struct {
-	ID        string    `json:"id"`
-	UserID    int64     `json:"user_id"`
-	ExpiresAt time.Time `json:"expires_at"`
+	ID              string    `json:"id"`
+	UserID          int64     `json:"user_id"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	DurationSeconds int64     `json:"duration_seconds"`
+	UserAgent       string    `json:"user_agent"`
+	IpAddress       string    `json:"ip_address"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
-	row := q.db.QueryRowContext(ctx, createSession, arg.ID, arg.UserID, arg.ExpiresAt)
+	row := q.db.QueryRowContext(ctx, createSession,
+		arg.ID,
+		arg.UserID,
+		arg.ExpiresAt,
+		arg.DurationSeconds,
+		arg.UserAgent,
+		arg.IpAddress,
+	)
 	var i Session
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.DurationSeconds,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastSeenAt,
 	)
 	return i, err
 }
@@ -46,7 +63,7 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (username, password_hash, created_at, updated_at)
 VALUES (?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-RETURNING id, username, password_hash, created_at, updated_at
+RETURNING id, username, password_hash, created_at, updated_at, failed_login_attempts, locked_until
 `
 
 type CreateUserParams struct {
@@ -63,10 +80,41 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
 	)
 	return i, err
 }
 
+const deleteAllUserSessions = `-- name: DeleteAllUserSessions :execrows
+DELETE FROM sessions WHERE user_id = ?
+`
+
+func (q *Queries) DeleteAllUserSessions(ctx context.Context, userID int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAllUserSessions, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteOtherUserSessions = `-- name: DeleteOtherUserSessions :execrows
+DELETE FROM sessions WHERE user_id = ? AND id != ?
+`
+
+type DeleteOtherUserSessionsParams struct {
+	UserID int64  `json:"user_id"`
+	ID     string `json:"id"`
+}
+
+func (q *Queries) DeleteOtherUserSessions(ctx context.Context, arg DeleteOtherUserSessionsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOtherUserSessions, arg.UserID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteSession = `-- name: DeleteSession :exec
 DELETE FROM sessions WHERE id = ?
 `
@@ -76,8 +124,25 @@ func (q *Queries) DeleteSession(ctx context.Context, id string) error {
 	return err
 }
 
+const deleteUserSession = `-- name: DeleteUserSession :execrows
+DELETE FROM sessions WHERE id = ? AND user_id = ?
+`
+
+type DeleteUserSessionParams struct {
+	ID     string `json:"id"`
+	UserID int64  `json:"user_id"`
+}
+
+func (q *Queries) DeleteUserSession(ctx context.Context, arg DeleteUserSessionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserSession, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, username, password_hash, created_at, updated_at FROM users WHERE id = ?
+SELECT id, username, password_hash, created_at, updated_at, failed_login_attempts, locked_until FROM users WHERE id = ?
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id int64) (User, error) {
@@ -89,12 +154,14 @@ func (q *Queries) GetUserByID(ctx context.Context, id int64) (User, error) {
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, password_hash, created_at, updated_at FROM users WHERE username = ?
+SELECT id, username, password_hash, created_at, updated_at, failed_login_attempts, locked_until FROM users WHERE username = ?
 `
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
@@ -106,12 +173,14 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FailedLoginAttempts,
+		&i.LockedUntil,
 	)
 	return i, err
 }
 
 const getValidSession = `-- name: GetValidSession :one
-SELECT id, user_id, expires_at, created_at FROM sessions WHERE id = ? AND expires_at > ?
+SELECT id, user_id, expires_at, created_at, duration_seconds, user_agent, ip_address, last_seen_at FROM sessions WHERE id = ? AND expires_at > ?
 `
 
 type GetValidSessionParams struct {
@@ -127,6 +196,115 @@ func (q *Queries) GetValidSession(ctx context.Context, arg GetValidSessionParams
 		&i.UserID,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.DurationSeconds,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastSeenAt,
 	)
 	return i, err
 }
+
+const listSessionsByUser = `-- name: ListSessionsByUser :many
+SELECT id, user_id, expires_at, created_at, duration_seconds, user_agent, ip_address, last_seen_at FROM sessions WHERE user_id = ? AND expires_at > ?
+ORDER BY created_at DESC
+`
+
+type ListSessionsByUserParams struct {
+	UserID    int64     `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) ListSessionsByUser(ctx context.Context, arg ListSessionsByUserParams) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, listSessionsByUser, arg.UserID, arg.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Session{}
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.DurationSeconds,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUser = `-- name: LockUser :exec
+UPDATE users SET locked_until = ?, failed_login_attempts = 0 WHERE id = ?
+`
+
+type LockUserParams struct {
+	LockedUntil *time.Time `json:"locked_until"`
+	ID          int64      `json:"id"`
+}
+
+func (q *Queries) LockUser(ctx context.Context, arg LockUserParams) error {
+	_, err := q.db.ExecContext(ctx, lockUser, arg.LockedUntil, arg.ID)
+	return err
+}
+
+const recordFailedLogin = `-- name: RecordFailedLogin :one
+UPDATE users SET failed_login_attempts = failed_login_attempts + 1 WHERE id = ?
+RETURNING failed_login_attempts
+`
+
+func (q *Queries) RecordFailedLogin(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, recordFailedLogin, id)
+	var failed_login_attempts int64
+	err := row.Scan(&failed_login_attempts)
+	return failed_login_attempts, err
+}
+
+const resetFailedLogins = `-- name: ResetFailedLogins :exec
+UPDATE users SET failed_login_attempts = 0, locked_until = NULL WHERE id = ?
+`
+
+func (q *Queries) ResetFailedLogins(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, resetFailedLogins, id)
+	return err
+}
+
+const touchSession = `-- name: TouchSession :exec
+UPDATE sessions SET last_seen_at = ? WHERE id = ?
+`
+
+type TouchSessionParams struct {
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	ID         string     `json:"id"`
+}
+
+func (q *Queries) TouchSession(ctx context.Context, arg TouchSessionParams) error {
+	_, err := q.db.ExecContext(ctx, touchSession, arg.LastSeenAt, arg.ID)
+	return err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type UpdateUserPasswordParams struct {
+	PasswordHash string `json:"password_hash"`
+	ID           int64  `json:"id"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPassword, arg.PasswordHash, arg.ID)
+	return err
+}
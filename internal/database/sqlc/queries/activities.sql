@@ -8,6 +8,28 @@ SELECT * FROM activities
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?;
 
+-- name: DeleteActivitiesForEntity :exec
+DELETE FROM activities
+WHERE entity_type = ? AND entity_id = ?;
+
 -- name: DeleteOldActivities :exec
 DELETE FROM activities
 WHERE created_at < ?;
+
+-- name: ListActivities :many
+SELECT * FROM activities
+WHERE (entity_type = sqlc.arg(entity_type) OR sqlc.arg(entity_type) = '')
+  AND (action = sqlc.arg(action) OR sqlc.arg(action) = '')
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CountActivities :one
+SELECT COUNT(*) FROM activities
+WHERE (entity_type = sqlc.arg(entity_type) OR sqlc.arg(entity_type) = '')
+  AND (action = sqlc.arg(action) OR sqlc.arg(action) = '');
+
+-- name: CountActivitiesByEntityType :many
+SELECT entity_type, COUNT(*) AS count
+FROM activities
+GROUP BY entity_type
+ORDER BY count DESC, entity_type;
@@ -1,11 +1,28 @@
 -- name: CreateBookmark :one
-INSERT INTO bookmarks (url, title, description, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+INSERT INTO bookmarks (url, normalized_url, title, description, note, cover_image, favicon, domain, collection_id, is_public, is_favorite, sort_order, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 RETURNING *;
 
+-- name: CreateBookmarksBatch :many
+-- Inserts public bookmarks from a JSON array of objects with url,
+-- normalized_url, title, domain and collection_id keys, in a single statement.
+INSERT INTO bookmarks (url, normalized_url, title, domain, collection_id, is_public, is_favorite, created_at, updated_at)
+SELECT
+    json_extract(value, '$.url'),
+    json_extract(value, '$.normalized_url'),
+    json_extract(value, '$.title'),
+    json_extract(value, '$.domain'),
+    json_extract(value, '$.collection_id'),
+    1,
+    0,
+    CURRENT_TIMESTAMP,
+    CURRENT_TIMESTAMP
+FROM json_each(CAST(sqlc.arg(bookmarks) AS TEXT))
+RETURNING id;
+
 -- name: UpdateBookmark :exec
 UPDATE bookmarks 
-SET url = ?, title = ?, description = ?, cover_image = ?, favicon = ?, domain = ?,
+SET url = ?, normalized_url = ?, title = ?, description = ?, note = ?, cover_image = ?, favicon = ?, domain = ?,
     collection_id = ?, is_public = ?, is_favorite = ?, updated_at = CURRENT_TIMESTAMP 
 WHERE id = ?;
 
@@ -18,58 +35,214 @@ SELECT * FROM bookmarks WHERE id = ?;
 -- name: GetBookmarkByURL :one
 SELECT * FROM bookmarks WHERE url = ? LIMIT 1;
 
+-- name: GetBookmarkByNormalizedURL :one
+SELECT * FROM bookmarks WHERE normalized_url = ? ORDER BY id LIMIT 1;
+
+-- name: ListBookmarksWithoutNormalizedURL :many
+SELECT id, url FROM bookmarks WHERE normalized_url IS NULL;
+
+-- name: UpdateBookmarkNormalizedURL :exec
+UPDATE bookmarks SET normalized_url = ? WHERE id = ?;
+
 -- name: ListAllBookmarks :many
+-- Lists bookmarks whose is_archived matches archived, or every bookmark
+-- with include_archived set. The other admin listings filter the same way.
 SELECT * FROM bookmarks 
-ORDER BY sort_order, created_at DESC 
-LIMIT ? OFFSET ?;
+WHERE (is_archived = sqlc.arg(archived) OR CAST(sqlc.arg(include_archived) AS INTEGER) = 1)
+ORDER BY sort_order, created_at DESC, id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
 
 -- name: ListPublicBookmarks :many
 SELECT * FROM bookmarks 
-WHERE is_public = 1 
-ORDER BY sort_order, created_at DESC 
+WHERE is_public = 1 AND is_archived = 0
+ORDER BY sort_order, created_at DESC, id DESC
 LIMIT ? OFFSET ?;
 
 -- name: ListBookmarksByCollection :many
 SELECT * FROM bookmarks 
-WHERE collection_id = ? 
+WHERE collection_id = sqlc.arg(collection_id)
+  AND (is_archived = sqlc.arg(archived) OR CAST(sqlc.arg(include_archived) AS INTEGER) = 1)
 ORDER BY sort_order, created_at DESC 
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: ListBookmarksInCollections :many
+-- Like ListBookmarksByCollection, for any collection in a JSON array of IDs.
+SELECT * FROM bookmarks
+WHERE collection_id IN (SELECT value FROM json_each(CAST(sqlc.arg(collection_ids) AS TEXT)))
+  AND (is_archived = sqlc.arg(archived) OR CAST(sqlc.arg(include_archived) AS INTEGER) = 1)
+ORDER BY sort_order, created_at DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: ListPublicBookmarksAfter :many
+-- Keyset page of public bookmarks following the given position. NULL
+-- sort_order sorts first, so it compares as -1 (real orders are positive).
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (COALESCE(sort_order, -1) > sqlc.arg(after_sort_order)
+    OR (COALESCE(sort_order, -1) = sqlc.arg(after_sort_order)
+      AND (datetime(created_at) < sqlc.arg(after_created_at)
+        OR (datetime(created_at) = sqlc.arg(after_created_at) AND id < sqlc.arg(after_id)))))
+ORDER BY sort_order, created_at DESC, id DESC
+LIMIT sqlc.arg(limit);
 
 -- name: ListPublicBookmarksByCollection :many
 SELECT * FROM bookmarks 
-WHERE is_public = 1 AND collection_id = ? 
-ORDER BY sort_order, created_at DESC 
+WHERE is_public = 1 AND is_archived = 0 AND collection_id = ? 
+ORDER BY sort_order, created_at DESC, id DESC
 LIMIT ? OFFSET ?;
 
+-- name: ListPublicBookmarksByCollectionAfter :many
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0 AND collection_id = sqlc.arg(collection_id)
+  AND (COALESCE(sort_order, -1) > sqlc.arg(after_sort_order)
+    OR (COALESCE(sort_order, -1) = sqlc.arg(after_sort_order)
+      AND (datetime(created_at) < sqlc.arg(after_created_at)
+        OR (datetime(created_at) = sqlc.arg(after_created_at) AND id < sqlc.arg(after_id)))))
+ORDER BY sort_order, created_at DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListPublicBookmarksInCollections :many
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0 AND collection_id IN (SELECT value FROM json_each(CAST(sqlc.arg(collection_ids) AS TEXT)))
+ORDER BY sort_order, created_at DESC, id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: ListPublicBookmarksInCollectionsAfter :many
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0 AND collection_id IN (SELECT value FROM json_each(CAST(sqlc.arg(collection_ids) AS TEXT)))
+  AND (COALESCE(sort_order, -1) > sqlc.arg(after_sort_order)
+    OR (COALESCE(sort_order, -1) = sqlc.arg(after_sort_order)
+      AND (datetime(created_at) < sqlc.arg(after_created_at)
+        OR (datetime(created_at) = sqlc.arg(after_created_at) AND id < sqlc.arg(after_id)))))
+ORDER BY sort_order, created_at DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListPublicBookmarksNewest :many
+-- Public bookmarks newest added first, ignoring the manual order. Bookmarks
+-- without a creation time sort last. collection_ids is as for
+-- ListPublicBookmarksOldest.
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(sqlc.arg(collection_ids) AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(sqlc.arg(collection_ids) AS TEXT))))
+ORDER BY COALESCE(datetime(created_at), '') DESC, id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: ListPublicBookmarksNewestAfter :many
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(sqlc.arg(collection_ids) AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(sqlc.arg(collection_ids) AS TEXT))))
+  AND (COALESCE(datetime(created_at), '') < sqlc.arg(after_key)
+    OR (COALESCE(datetime(created_at), '') = sqlc.arg(after_key) AND id < sqlc.arg(after_id)))
+ORDER BY COALESCE(datetime(created_at), '') DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListPublicBookmarksOldest :many
+-- Public bookmarks oldest first. An empty collection_ids lists every
+-- collection; otherwise it is a JSON array of collection IDs.
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(sqlc.arg(collection_ids) AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(sqlc.arg(collection_ids) AS TEXT))))
+ORDER BY datetime(created_at), id
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: ListPublicBookmarksOldestAfter :many
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(sqlc.arg(collection_ids) AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(sqlc.arg(collection_ids) AS TEXT))))
+  AND (datetime(created_at) > sqlc.arg(after_key)
+    OR (datetime(created_at) = sqlc.arg(after_key) AND id > sqlc.arg(after_id)))
+ORDER BY datetime(created_at), id
+LIMIT sqlc.arg(limit);
+
+-- name: ListPublicBookmarksByTitle :many
+-- Public bookmarks in title order, case-insensitive
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(sqlc.arg(collection_ids) AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(sqlc.arg(collection_ids) AS TEXT))))
+ORDER BY lower(title), id
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: ListPublicBookmarksByTitleAfter :many
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(sqlc.arg(collection_ids) AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(sqlc.arg(collection_ids) AS TEXT))))
+  AND (lower(title) > lower(sqlc.arg(after_key))
+    OR (lower(title) = lower(sqlc.arg(after_key)) AND id > sqlc.arg(after_id)))
+ORDER BY lower(title), id
+LIMIT sqlc.arg(limit);
+
+-- name: ListPublicBookmarksByDomain :many
+-- Public bookmarks grouped by domain, case-insensitive
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(sqlc.arg(collection_ids) AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(sqlc.arg(collection_ids) AS TEXT))))
+ORDER BY lower(COALESCE(domain, '')), id
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: ListPublicBookmarksByDomainAfter :many
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (CAST(sqlc.arg(collection_ids) AS TEXT) = '' OR collection_id IN (SELECT value FROM json_each(CAST(sqlc.arg(collection_ids) AS TEXT))))
+  AND (lower(COALESCE(domain, '')) > lower(sqlc.arg(after_key))
+    OR (lower(COALESCE(domain, '')) = lower(sqlc.arg(after_key)) AND id > sqlc.arg(after_id)))
+ORDER BY lower(COALESCE(domain, '')), id
+LIMIT sqlc.arg(limit);
+
 -- name: ListFavoriteBookmarks :many
 SELECT * FROM bookmarks 
-WHERE is_favorite = 1 
+WHERE is_favorite = 1 AND (is_archived = sqlc.arg(archived) OR CAST(sqlc.arg(include_archived) AS INTEGER) = 1)
 ORDER BY sort_order, created_at DESC 
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
 
 -- name: ListPublicFavoriteBookmarks :many
 SELECT * FROM bookmarks 
-WHERE is_public = 1 AND is_favorite = 1 
-ORDER BY sort_order, created_at DESC 
+WHERE is_public = 1 AND is_favorite = 1 AND is_archived = 0
+ORDER BY sort_order, created_at DESC, id DESC
 LIMIT ? OFFSET ?;
 
+-- name: ListPublicFavoriteBookmarksNewest :many
+-- Public favorites newest added first, ignoring the manual order, as
+-- ListPublicBookmarksNewest
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_favorite = 1 AND is_archived = 0
+ORDER BY COALESCE(datetime(created_at), '') DESC, id DESC
+LIMIT ? OFFSET ?;
+
+-- name: ListPublicFavoriteBookmarksAfter :many
+-- Keyset page of public favorites, ordered like ListPublicBookmarksAfter
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_favorite = 1 AND is_archived = 0
+  AND (COALESCE(sort_order, -1) > sqlc.arg(after_sort_order)
+    OR (COALESCE(sort_order, -1) = sqlc.arg(after_sort_order)
+      AND (datetime(created_at) < sqlc.arg(after_created_at)
+        OR (datetime(created_at) = sqlc.arg(after_created_at) AND id < sqlc.arg(after_id)))))
+ORDER BY sort_order, created_at DESC, id DESC
+LIMIT sqlc.arg(limit);
+
 -- name: CountAllBookmarks :one
-SELECT COUNT(*) FROM bookmarks;
+SELECT COUNT(*) FROM bookmarks WHERE (is_archived = sqlc.arg(archived) OR CAST(sqlc.arg(include_archived) AS INTEGER) = 1);
 
 -- name: CountPublicBookmarks :one
-SELECT COUNT(*) FROM bookmarks WHERE is_public = 1;
+SELECT COUNT(*) FROM bookmarks WHERE is_public = 1 AND is_archived = 0;
 
 -- name: CountBookmarksByCollection :one
-SELECT COUNT(*) FROM bookmarks WHERE collection_id = ?;
+SELECT COUNT(*) FROM bookmarks WHERE collection_id = sqlc.arg(collection_id) AND (is_archived = sqlc.arg(archived) OR CAST(sqlc.arg(include_archived) AS INTEGER) = 1);
+
+-- name: CountBookmarksInCollections :one
+-- Like CountBookmarksByCollection, for any collection in a JSON array of IDs.
+SELECT COUNT(*) FROM bookmarks WHERE collection_id IN (SELECT value FROM json_each(CAST(sqlc.arg(collection_ids) AS TEXT))) AND (is_archived = sqlc.arg(archived) OR CAST(sqlc.arg(include_archived) AS INTEGER) = 1);
 
 -- name: CountPublicBookmarksByCollection :one
-SELECT COUNT(*) FROM bookmarks WHERE is_public = 1 AND collection_id = ?;
+SELECT COUNT(*) FROM bookmarks WHERE is_public = 1 AND is_archived = 0 AND collection_id = ?;
+
+-- name: CountPublicBookmarksInCollections :one
+SELECT COUNT(*) FROM bookmarks WHERE is_public = 1 AND is_archived = 0 AND collection_id IN (SELECT value FROM json_each(CAST(sqlc.arg(collection_ids) AS TEXT)));
 
 -- name: CountFavoriteBookmarks :one
-SELECT COUNT(*) FROM bookmarks WHERE is_favorite = 1;
+SELECT COUNT(*) FROM bookmarks WHERE is_favorite = 1 AND (is_archived = sqlc.arg(archived) OR CAST(sqlc.arg(include_archived) AS INTEGER) = 1);
 
 -- name: CountPublicFavoriteBookmarks :one
-SELECT COUNT(*) FROM bookmarks WHERE is_public = 1 AND is_favorite = 1;
+SELECT COUNT(*) FROM bookmarks WHERE is_public = 1 AND is_favorite = 1 AND is_archived = 0;
 
 -- ============================================
 -- INLINE EDITING QUERIES
@@ -81,23 +254,32 @@ UPDATE bookmarks SET is_public = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
 -- name: UpdateBookmarkFavorite :exec
 UPDATE bookmarks SET is_favorite = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
 
+-- name: UpdateBookmarkArchived :exec
+UPDATE bookmarks SET is_archived = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: IncrementBookmarkClickCount :exec
+UPDATE bookmarks SET click_count = click_count + 1 WHERE id = ?;
+
+-- name: UpdateBookmarkImages :exec
+UPDATE bookmarks SET cover_image_id = ?, thumbnail_id = ? WHERE id = ?;
+
 -- ============================================
 -- BOARD VIEW QUERIES
 -- ============================================
 
 -- name: CountUnsortedBookmarks :one
-SELECT COUNT(*) FROM bookmarks WHERE collection_id IS NULL;
+SELECT COUNT(*) FROM bookmarks WHERE collection_id IS NULL AND is_archived = 0;
 
 -- name: ListRecentUnsortedBookmarks :many
 SELECT id, title, url, domain, is_favorite, is_public, updated_at
 FROM bookmarks
-WHERE collection_id IS NULL
+WHERE collection_id IS NULL AND is_archived = 0
 ORDER BY COALESCE(sort_order, 999999) ASC, updated_at DESC
 LIMIT ?;
 
 -- name: ListUnsortedBookmarks :many
 SELECT * FROM bookmarks
-WHERE collection_id IS NULL
+WHERE collection_id IS NULL AND is_archived = 0
 ORDER BY COALESCE(sort_order, 999999) ASC, updated_at DESC
 LIMIT ? OFFSET ?;
 
@@ -121,3 +303,28 @@ ORDER BY COALESCE(sort_order, 999999) ASC, updated_at DESC;
 UPDATE bookmarks 
 SET collection_id = ?, sort_order = ?, updated_at = CURRENT_TIMESTAMP 
 WHERE id = ?;
+
+-- ============================================
+-- BOOKMARK TAG QUERIES
+-- ============================================
+
+-- name: AddTagToBookmarks :execrows
+-- Tags each existing bookmark in a JSON array of IDs. Bookmarks that
+-- already have the tag are skipped.
+INSERT OR IGNORE INTO bookmark_tags (bookmark_id, tag_id, created_at)
+SELECT id, CAST(sqlc.arg(tag_id) AS INTEGER), CURRENT_TIMESTAMP
+FROM bookmarks
+WHERE id IN (SELECT value FROM json_each(CAST(sqlc.arg(bookmark_ids) AS TEXT)));
+
+-- name: RemoveTagFromBookmarks :execrows
+DELETE FROM bookmark_tags
+WHERE tag_id = sqlc.arg(tag_id)
+  AND bookmark_id IN (SELECT value FROM json_each(CAST(sqlc.arg(bookmark_ids) AS TEXT)));
+
+-- name: ListTagsForBookmarks :many
+-- Tags of every bookmark in a JSON array of IDs, by name.
+SELECT bt.bookmark_id, t.*
+FROM bookmark_tags bt
+INNER JOIN tags t ON t.id = bt.tag_id
+WHERE bt.bookmark_id IN (SELECT value FROM json_each(CAST(sqlc.arg(bookmark_ids) AS TEXT)))
+ORDER BY t.name;
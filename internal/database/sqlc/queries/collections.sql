@@ -1,22 +1,28 @@
 -- name: CreateCollection :one
-INSERT INTO collections (name, slug, description, color, parent_id, sort_order, is_public, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+INSERT INTO collections (name, slug, description, color, cover_image, parent_id, sort_order, is_public, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 RETURNING *;
 
 -- name: UpdateCollection :exec
 UPDATE collections 
-SET name = ?, slug = ?, description = ?, color = ?,
-    parent_id = ?, sort_order = ?, is_public = ?, updated_at = CURRENT_TIMESTAMP 
+SET name = ?, slug = ?, description = ?, color = ?, cover_image = ?,
+    parent_id = ?, is_public = ?, updated_at = CURRENT_TIMESTAMP 
 WHERE id = ?;
 
 -- name: DeleteCollection :exec
 DELETE FROM collections WHERE id = ?;
 
 -- name: GetCollectionByID :one
-SELECT * FROM collections WHERE id = ?;
+SELECT c.*,
+    (SELECT COUNT(*) FROM bookmarks b WHERE b.collection_id = c.id) as bookmark_count
+FROM collections c
+WHERE c.id = ?;
 
 -- name: GetCollectionBySlug :one
-SELECT * FROM collections WHERE slug = ?;
+SELECT c.*,
+    (SELECT COUNT(*) FROM bookmarks b WHERE b.collection_id = c.id) as bookmark_count
+FROM collections c
+WHERE c.slug = ?;
 
 -- name: ListAllCollections :many
 SELECT * FROM collections ORDER BY sort_order, name;
@@ -37,9 +43,6 @@ FROM collections c
 WHERE c.is_public = 1
 ORDER BY c.sort_order, c.name;
 
--- name: GetCollectionBookmarkCount :one
-SELECT COUNT(*) FROM bookmarks WHERE collection_id = ?;
-
 -- ============================================
 -- INLINE EDITING QUERIES
 -- ============================================
@@ -47,16 +50,40 @@ SELECT COUNT(*) FROM bookmarks WHERE collection_id = ?;
 -- name: UpdateCollectionPublic :exec
 UPDATE collections SET is_public = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
 
+-- name: GetCollectionSortOrders :many
+SELECT id, COALESCE(sort_order, 0) as sort_order
+FROM collections
+ORDER BY sort_order, name;
+
+-- name: UpdateCollectionPosition :exec
+UPDATE collections SET sort_order = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
 -- name: GetBookmarksByCollectionID :many
 SELECT id, title, url, domain, is_public, is_favorite, created_at
 FROM bookmarks
-WHERE collection_id = ?
+WHERE collection_id = ? AND is_archived = 0
 ORDER BY COALESCE(sort_order, 999999) ASC, updated_at DESC
 LIMIT 6;
 
 -- name: GetRecentBookmarksByCollectionID :many
 SELECT id, title, url, domain, is_favorite, is_public, updated_at
 FROM bookmarks
-WHERE collection_id = ?
+WHERE collection_id = ? AND is_archived = 0
 ORDER BY COALESCE(sort_order, 999999) ASC, updated_at DESC
 LIMIT ?;
+
+-- name: GetRecentBookmarksForAllCollections :many
+-- Recent bookmarks of every collection, at most per_collection each, in the
+-- order GetRecentBookmarksByCollectionID uses, grouped by collection.
+SELECT id, collection_id, title, url, domain, is_favorite, is_public, updated_at
+FROM (
+    SELECT id, collection_id, title, url, domain, is_favorite, is_public, updated_at,
+        ROW_NUMBER() OVER (
+            PARTITION BY collection_id
+            ORDER BY COALESCE(sort_order, 999999) ASC, updated_at DESC
+        ) AS rn
+    FROM bookmarks
+    WHERE collection_id IS NOT NULL AND is_archived = 0
+)
+WHERE rn <= CAST(sqlc.arg(per_collection) AS INTEGER)
+ORDER BY collection_id, rn;
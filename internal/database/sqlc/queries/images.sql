@@ -0,0 +1,18 @@
+-- name: GetImageByID :one
+SELECT * FROM images WHERE id = ?;
+
+-- name: CreateImage :one
+INSERT INTO images (mime_type, data, size, source_url, created_at)
+VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+RETURNING *;
+
+-- name: DeleteImage :exec
+DELETE FROM images WHERE id = ?;
+
+-- name: CountImagePathReferences :one
+-- How many posts, collections and bookmarks use the /images/{id} path as
+-- their cover image, including trashed posts.
+SELECT
+  (SELECT COUNT(*) FROM posts WHERE cover_image = sqlc.arg(path))
+  + (SELECT COUNT(*) FROM collections WHERE cover_image = sqlc.arg(path))
+  + (SELECT COUNT(*) FROM bookmarks WHERE cover_image = sqlc.arg(path)) AS refs;
@@ -9,8 +9,24 @@ SET title = ?, slug = ?, content = ?, excerpt = ?, cover_image = ?,
     is_draft = ?, published_at = ?, updated_at = CURRENT_TIMESTAMP 
 WHERE id = ?;
 
--- name: DeletePost :exec
-DELETE FROM posts WHERE id = ?;
+-- name: TrashPost :execrows
+UPDATE posts SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL;
+
+-- name: RestorePost :execrows
+UPDATE posts SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- name: PurgePost :execrows
+-- Only posts already in the trash can be removed for good.
+DELETE FROM posts WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- name: ListTrashedPosts :many
+SELECT * FROM posts
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id DESC;
+
+-- name: ListPostsTrashedBefore :many
+SELECT id FROM posts
+WHERE deleted_at IS NOT NULL AND deleted_at < ?;
 
 -- name: GetPostByID :one
 SELECT * FROM posts WHERE id = ?;
@@ -20,17 +36,39 @@ SELECT * FROM posts WHERE slug = ?;
 
 -- name: ListAllPosts :many
 SELECT * FROM posts 
-ORDER BY COALESCE(published_at, created_at) DESC 
+WHERE deleted_at IS NULL
+ORDER BY COALESCE(published_at, created_at) DESC, id DESC
 LIMIT ? OFFSET ?;
 
 -- name: ListPublishedPosts :many
 SELECT * FROM posts 
-WHERE is_draft = 0 
+WHERE is_draft = 0 AND deleted_at IS NULL
 ORDER BY COALESCE(published_at, created_at) DESC 
 LIMIT ? OFFSET ?;
 
+-- name: CountAllPosts :one
+SELECT COUNT(*) FROM posts WHERE deleted_at IS NULL;
+
+-- name: CountPublishedPosts :one
+SELECT COUNT(*) FROM posts WHERE is_draft = 0 AND deleted_at IS NULL;
+
+-- name: ListFeaturedPosts :many
+SELECT * FROM posts
+WHERE is_featured = 1 AND is_draft = 0 AND deleted_at IS NULL
+ORDER BY COALESCE(published_at, created_at) DESC, id DESC
+LIMIT ?;
+
+-- name: CountFeaturedPosts :one
+SELECT COUNT(*) FROM posts WHERE is_featured = 1 AND is_draft = 0 AND deleted_at IS NULL;
+
+-- name: ListPublishedPostsByTag :many
+SELECT p.* FROM posts p
+INNER JOIN post_tags pt ON p.id = pt.post_id
+WHERE pt.tag_id = ? AND p.is_draft = 0 AND p.deleted_at IS NULL
+ORDER BY COALESCE(p.published_at, p.created_at) DESC;
+
 -- name: GetPostTags :many
-SELECT t.id, t.name, t.slug, t.created_at
+SELECT t.id, t.name, t.slug, t.color, t.created_at
 FROM tags t
 INNER JOIN post_tags pt ON t.id = pt.tag_id
 WHERE pt.post_id = ?;
@@ -47,3 +85,6 @@ INSERT INTO post_tags (post_id, tag_id, created_at) VALUES (?, ?, CURRENT_TIMEST
 
 -- name: UpdatePostDraft :exec
 UPDATE posts SET is_draft = ?, published_at = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: UpdatePostFeatured :exec
+UPDATE posts SET is_featured = ? WHERE id = ?;
@@ -0,0 +1,54 @@
+-- name: CreateSearchQuery :exec
+INSERT INTO search_queries (query, result_count, created_at)
+VALUES (?, ?, CURRENT_TIMESTAMP);
+
+-- name: DeleteSearchQueriesBefore :execrows
+-- created_at is written by CURRENT_TIMESTAMP, so the cutoff is normalized
+-- to the same UTC text form before comparing
+DELETE FROM search_queries
+WHERE created_at < datetime(sqlc.arg(cutoff));
+
+-- name: GetZeroResultSearches :many
+SELECT query, COUNT(*) AS searches
+FROM search_queries
+WHERE result_count = 0
+GROUP BY query
+ORDER BY searches DESC, query
+LIMIT ?;
+
+-- name: SearchPublishedPosts :many
+-- Published posts whose title, excerpt or content contains the pattern, a
+-- LIKE pattern escaped with backslashes. Title matches rank first, then
+-- excerpt matches, newest first within each.
+SELECT * FROM posts
+WHERE is_draft = 0 AND deleted_at IS NULL
+  AND (title LIKE sqlc.arg(pattern) ESCAPE '\'
+    OR excerpt LIKE sqlc.arg(pattern) ESCAPE '\'
+    OR content LIKE sqlc.arg(pattern) ESCAPE '\')
+ORDER BY
+  CASE
+    WHEN title LIKE sqlc.arg(pattern) ESCAPE '\' THEN 0
+    WHEN excerpt LIKE sqlc.arg(pattern) ESCAPE '\' THEN 1
+    ELSE 2
+  END,
+  COALESCE(published_at, created_at) DESC
+LIMIT sqlc.arg(limit);
+
+-- name: SearchPublicBookmarks :many
+-- Public, unarchived bookmarks whose title, description or URL contains
+-- the pattern, ranked like SearchPublishedPosts: title matches, then
+-- description matches, newest first within each. Notes are admin-only and
+-- never searched here.
+SELECT * FROM bookmarks
+WHERE is_public = 1 AND is_archived = 0
+  AND (title LIKE sqlc.arg(pattern) ESCAPE '\'
+    OR description LIKE sqlc.arg(pattern) ESCAPE '\'
+    OR url LIKE sqlc.arg(pattern) ESCAPE '\')
+ORDER BY
+  CASE
+    WHEN title LIKE sqlc.arg(pattern) ESCAPE '\' THEN 0
+    WHEN description LIKE sqlc.arg(pattern) ESCAPE '\' THEN 1
+    ELSE 2
+  END,
+  created_at DESC, id DESC
+LIMIT sqlc.arg(limit);
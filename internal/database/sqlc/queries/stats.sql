@@ -4,7 +4,7 @@ WHERE created_at >= ?;
 
 -- name: GetPostsCreatedSince :one
 SELECT COUNT(*) FROM posts
-WHERE created_at >= ?;
+WHERE created_at >= ? AND deleted_at IS NULL;
 
 -- name: GetBookmarkCountsByCollection :many
 SELECT 
@@ -30,9 +30,9 @@ LIMIT ?;
 -- name: GetDatabaseStats :one
 SELECT 
     (SELECT COUNT(*) FROM bookmarks) as total_bookmarks,
-    (SELECT COUNT(*) FROM posts) as total_posts,
+    (SELECT COUNT(*) FROM posts WHERE deleted_at IS NULL) as total_posts,
     (SELECT COUNT(*) FROM collections) as total_collections,
     (SELECT COUNT(*) FROM tags) as total_tags;
 
 -- name: CountDraftPosts :one
-SELECT COUNT(*) FROM posts WHERE is_draft = 1;
+SELECT COUNT(*) FROM posts WHERE is_draft = 1 AND deleted_at IS NULL;
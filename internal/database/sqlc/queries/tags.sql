@@ -1,11 +1,14 @@
 -- name: CreateTag :one
-INSERT INTO tags (name, slug, created_at)
-VALUES (?, ?, CURRENT_TIMESTAMP)
+INSERT INTO tags (name, slug, color, created_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP)
 RETURNING *;
 
 -- name: DeleteTag :exec
 DELETE FROM tags WHERE id = ?;
 
+-- name: GetTagByID :one
+SELECT * FROM tags WHERE id = ?;
+
 -- name: GetTagBySlug :one
 SELECT * FROM tags WHERE slug = ?;
 
@@ -21,9 +24,37 @@ SELECT t.*,
 FROM tags t
 ORDER BY usage_count DESC, t.name;
 
+-- name: ListPublishedTagsWithCounts :many
+-- Only tags used by at least one published post
+SELECT t.*, COUNT(*) as usage_count
+FROM tags t
+INNER JOIN post_tags pt ON pt.tag_id = t.id
+INNER JOIN posts p ON p.id = pt.post_id
+WHERE p.is_draft = 0 AND p.deleted_at IS NULL
+GROUP BY t.id
+ORDER BY usage_count DESC, t.name;
+
 -- name: GetPostsByTagID :many
 SELECT p.id, p.title, p.slug, p.is_draft, p.published_at, p.created_at
 FROM posts p
 JOIN post_tags pt ON p.id = pt.post_id
-WHERE pt.tag_id = ?
+WHERE pt.tag_id = ? AND p.deleted_at IS NULL
 ORDER BY p.created_at DESC;
+
+-- name: CountPostsByTagID :one
+SELECT COUNT(*) FROM post_tags WHERE tag_id = ?;
+
+-- name: RetagBookmarks :exec
+-- Like RetagPosts, for bookmark_tags.
+INSERT OR IGNORE INTO bookmark_tags (bookmark_id, tag_id, created_at)
+SELECT bookmark_id, CAST(sqlc.arg(target_id) AS INTEGER), created_at
+FROM bookmark_tags
+WHERE tag_id = sqlc.arg(source_id);
+
+-- name: RetagPosts :exec
+-- Adds target_id to every post tagged source_id. Posts that already have
+-- target_id are skipped instead of violating the primary key.
+INSERT OR IGNORE INTO post_tags (post_id, tag_id, created_at)
+SELECT post_id, CAST(sqlc.arg(target_id) AS INTEGER), created_at
+FROM post_tags
+WHERE tag_id = sqlc.arg(source_id);
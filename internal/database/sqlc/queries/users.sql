@@ -9,16 +9,45 @@ SELECT * FROM users WHERE id = ?;
 -- name: GetUserByUsername :one
 SELECT * FROM users WHERE username = ?;
 
+-- name: RecordFailedLogin :one
+UPDATE users SET failed_login_attempts = failed_login_attempts + 1 WHERE id = ?
+RETURNING failed_login_attempts;
+
+-- name: LockUser :exec
+UPDATE users SET locked_until = ?, failed_login_attempts = 0 WHERE id = ?;
+
+-- name: ResetFailedLogins :exec
+UPDATE users SET failed_login_attempts = 0, locked_until = NULL WHERE id = ?;
+
 -- name: CreateSession :one
-INSERT INTO sessions (id, user_id, expires_at, created_at)
-VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+INSERT INTO sessions (id, user_id, expires_at, duration_seconds, user_agent, ip_address, created_at, last_seen_at)
+VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 RETURNING *;
 
 -- name: GetValidSession :one
 SELECT * FROM sessions WHERE id = ? AND expires_at > ?;
 
+-- name: ListSessionsByUser :many
+SELECT * FROM sessions WHERE user_id = ? AND expires_at > ?
+ORDER BY created_at DESC;
+
 -- name: DeleteSession :exec
 DELETE FROM sessions WHERE id = ?;
 
--- name: CleanupExpiredSessions :exec
+-- name: DeleteUserSession :execrows
+DELETE FROM sessions WHERE id = ? AND user_id = ?;
+
+-- name: DeleteOtherUserSessions :execrows
+DELETE FROM sessions WHERE user_id = ? AND id != ?;
+
+-- name: DeleteAllUserSessions :execrows
+DELETE FROM sessions WHERE user_id = ?;
+
+-- name: TouchSession :exec
+UPDATE sessions SET last_seen_at = ? WHERE id = ?;
+
+-- name: CleanupExpiredSessions :execrows
 DELETE FROM sessions WHERE expires_at < ?;
+
+-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
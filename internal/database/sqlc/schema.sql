@@ -6,7 +6,9 @@ CREATE TABLE IF NOT EXISTS users (
     username        TEXT NOT NULL UNIQUE,
     password_hash   TEXT NOT NULL,
     created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at      DATETIME DEFAULT CURRENT_TIMESTAMP
+    updated_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+    failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+    locked_until    DATETIME
 );
 
 -- ============================================
@@ -17,6 +19,10 @@ CREATE TABLE IF NOT EXISTS sessions (
     user_id         INTEGER NOT NULL,
     expires_at      DATETIME NOT NULL,
     created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    user_agent      TEXT NOT NULL DEFAULT '',
+    ip_address      TEXT NOT NULL DEFAULT '',
+    last_seen_at    DATETIME,
     
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
@@ -37,11 +43,15 @@ CREATE TABLE IF NOT EXISTS posts (
     is_draft        INTEGER DEFAULT 1,
     published_at    DATETIME,
     created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at      DATETIME DEFAULT CURRENT_TIMESTAMP
+    updated_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at      DATETIME,
+    is_featured     INTEGER NOT NULL DEFAULT 0
 );
 
 CREATE INDEX IF NOT EXISTS idx_posts_slug ON posts(slug);
 CREATE INDEX IF NOT EXISTS idx_posts_published ON posts(is_draft, published_at DESC);
+CREATE INDEX IF NOT EXISTS idx_posts_deleted ON posts(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_posts_featured ON posts(is_featured, published_at DESC);
 
 -- ============================================
 -- COLLECTIONS (bookmark folders)
@@ -57,6 +67,7 @@ CREATE TABLE IF NOT EXISTS collections (
     is_public       INTEGER DEFAULT 1,
     created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+    cover_image     TEXT,
     
     FOREIGN KEY (parent_id) REFERENCES collections(id) ON DELETE SET NULL
 );
@@ -64,6 +75,18 @@ CREATE TABLE IF NOT EXISTS collections (
 CREATE INDEX IF NOT EXISTS idx_collections_slug ON collections(slug);
 CREATE INDEX IF NOT EXISTS idx_collections_parent ON collections(parent_id);
 
+-- ============================================
+-- IMAGES (locally stored image blobs)
+-- ============================================
+CREATE TABLE IF NOT EXISTS images (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    mime_type       TEXT NOT NULL,
+    data            BLOB NOT NULL,
+    size            INTEGER NOT NULL,
+    source_url      TEXT,
+    created_at      DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
 -- ============================================
 -- BOOKMARKS
 -- ============================================
@@ -81,14 +104,26 @@ CREATE TABLE IF NOT EXISTS bookmarks (
     sort_order      INTEGER DEFAULT 0,
     created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+    cover_image_id  INTEGER,
+    favicon_id      INTEGER,
+    thumbnail_id    INTEGER,
+    normalized_url  TEXT,
+    click_count     INTEGER NOT NULL DEFAULT 0,
+    is_archived     INTEGER NOT NULL DEFAULT 0,
+    note            TEXT,
     
-    FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE SET NULL
+    FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE SET NULL,
+    FOREIGN KEY (cover_image_id) REFERENCES images(id) ON DELETE SET NULL,
+    FOREIGN KEY (favicon_id) REFERENCES images(id) ON DELETE SET NULL,
+    FOREIGN KEY (thumbnail_id) REFERENCES images(id) ON DELETE SET NULL
 );
 
 CREATE INDEX IF NOT EXISTS idx_bookmarks_collection ON bookmarks(collection_id);
 CREATE INDEX IF NOT EXISTS idx_bookmarks_domain ON bookmarks(domain);
 CREATE INDEX IF NOT EXISTS idx_bookmarks_favorite ON bookmarks(is_favorite);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_archived ON bookmarks(is_archived);
 CREATE INDEX IF NOT EXISTS idx_bookmarks_public ON bookmarks(is_public, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_normalized_url ON bookmarks(normalized_url);
 
 -- ============================================
 -- TAGS (for posts)
@@ -97,11 +132,27 @@ CREATE TABLE IF NOT EXISTS tags (
     id              INTEGER PRIMARY KEY AUTOINCREMENT,
     name            TEXT NOT NULL UNIQUE,
     slug            TEXT NOT NULL UNIQUE,
+    color           TEXT,
     created_at      DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
 CREATE INDEX IF NOT EXISTS idx_tags_slug ON tags(slug);
 
+-- ============================================
+-- BOOKMARK_TAGS (many-to-many)
+-- ============================================
+CREATE TABLE IF NOT EXISTS bookmark_tags (
+    bookmark_id     INTEGER NOT NULL,
+    tag_id          INTEGER NOT NULL,
+    created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (bookmark_id, tag_id),
+    FOREIGN KEY (bookmark_id) REFERENCES bookmarks(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmark_tags_tag ON bookmark_tags(tag_id);
+
 -- ============================================
 -- POST_TAGS (many-to-many)
 -- ============================================
@@ -132,3 +183,16 @@ CREATE TABLE IF NOT EXISTS activities (
 
 CREATE INDEX IF NOT EXISTS idx_activities_created ON activities(created_at DESC);
 CREATE INDEX IF NOT EXISTS idx_activities_entity ON activities(entity_type, entity_id);
+
+-- ============================================
+-- SEARCH_QUERIES (anonymous search log for content gap reports)
+-- ============================================
+CREATE TABLE IF NOT EXISTS search_queries (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    query           TEXT NOT NULL,
+    result_count    INTEGER NOT NULL,
+    created_at      DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_queries_zero ON search_queries(result_count, query);
+CREATE INDEX IF NOT EXISTS idx_search_queries_created ON search_queries(created_at);
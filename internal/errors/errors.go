@@ -13,6 +13,7 @@ var (
 	ErrNotFound   = errors.New("not found")
 	ErrBadRequest = errors.New("bad request")
 	ErrInternal   = errors.New("internal error")
+	ErrTooLarge   = errors.New("request too large")
 )
 
 // AppError represents an application error with context
@@ -60,6 +61,16 @@ func BadRequest(message string) *AppError {
 	}
 }
 
+// TooLarge creates a 413 error
+func TooLarge(message string) *AppError {
+	return &AppError{
+		Code:    "REQUEST_TOO_LARGE",
+		Message: message,
+		Status:  http.StatusRequestEntityTooLarge,
+		Err:     ErrTooLarge,
+	}
+}
+
 // Internal creates a 500 error with an underlying cause
 func Internal(message string, err error) *AppError {
 	return &AppError{
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EC-9624/0xec.dev/internal/logger"
+	"github.com/EC-9624/0xec.dev/internal/middleware"
+	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
+	"github.com/EC-9624/0xec.dev/web/templates/admin"
+)
+
+// AdminAccountPage shows the account settings page with the password form
+func (h *Handlers) AdminAccountPage(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+		return
+	}
+
+	changed := r.URL.Query().Get("changed") == "1"
+	h.renderPage(w, r, admin.AccountPage(user, nil, changed), user)
+}
+
+// AdminChangePassword changes the signed-in user's password. On success
+// every other session is signed out so a leaked password stops working
+// everywhere but here.
+func (h *Handlers) AdminChangePassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := ctx.Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+		return
+	}
+
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Bad request")
+		return
+	}
+
+	input := models.ChangePasswordInput{
+		CurrentPassword: r.FormValue("current_password"),
+		NewPassword:     r.FormValue("new_password"),
+		ConfirmPassword: r.FormValue("confirm_password"),
+	}
+
+	// Re-render form with errors if validation failed
+	if formErrors := input.Validate(); formErrors != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		render(w, r, admin.AccountPage(user, formErrors, false))
+		return
+	}
+
+	err := h.service.ChangePassword(ctx, user.ID, input.CurrentPassword, input.NewPassword)
+	if err != nil {
+		formErrors := models.NewFormErrors()
+		status := http.StatusUnprocessableEntity
+		switch {
+		case errors.Is(err, service.ErrIncorrectPassword):
+			formErrors.AddField("current_password", "Current password is incorrect")
+		case errors.Is(err, service.ErrWeakPassword):
+			formErrors.AddField("new_password", models.PasswordStrengthError(input.NewPassword))
+		default:
+			logger.Error(ctx, "failed to change password", "error", err, "user_id", user.ID)
+			formErrors.General = "Failed to change password. Please try again."
+			status = http.StatusInternalServerError
+		}
+		w.WriteHeader(status)
+		render(w, r, admin.AccountPage(user, formErrors, false))
+		return
+	}
+
+	if current := currentSessionID(r); current != "" {
+		if _, err := h.service.RevokeAllOtherSessions(ctx, user.ID, current); err != nil {
+			logger.Error(ctx, "failed to revoke sessions after password change", "error", err, "user_id", user.ID)
+		}
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", "/admin/account?changed=1")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/account?changed=1", http.StatusSeeOther)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
+)
+
+func TestAdminChangePassword(t *testing.T) {
+	user := &models.User{ID: 1, Username: "admin"}
+
+	tests := []struct {
+		name        string
+		newPassword string
+		confirm     string
+		serviceErr  error
+		wantStatus  int
+		wantChanged bool
+		wantRevoked bool
+	}{
+		{"success", "new-password-456", "new-password-456", nil, http.StatusSeeOther, true, true},
+		{"confirmation mismatch", "new-password-456", "new-password-789", nil, http.StatusUnprocessableEntity, false, false},
+		{"too short", "short", "short", nil, http.StatusUnprocessableEntity, false, false},
+		{"incorrect current password", "new-password-456", "new-password-456", service.ErrIncorrectPassword, http.StatusUnprocessableEntity, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var changed bool
+			var keptSession string
+			mock := &mockService{
+				changePasswordFunc: func(ctx context.Context, userID int64, oldPassword, newPassword string) error {
+					changed = true
+					return tt.serviceErr
+				},
+				revokeAllOtherSessionsFunc: func(ctx context.Context, userID int64, currentSessionID string) (int64, error) {
+					keptSession = currentSessionID
+					return 2, nil
+				},
+			}
+			h := newTestHandlers(mock)
+
+			form := url.Values{}
+			form.Set("current_password", "old-password-123")
+			form.Set("new_password", tt.newPassword)
+			form.Set("confirm_password", tt.confirm)
+			req := httptest.NewRequest(http.MethodPost, "/admin/account/password", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.AddCookie(&http.Cookie{Name: "session", Value: "current-session"})
+			req = withUser(req, user)
+			rec := httptest.NewRecorder()
+
+			h.AdminChangePassword(rec, req)
+
+			assertStatus(t, rec, tt.wantStatus)
+			if changed != tt.wantChanged {
+				t.Errorf("ChangePassword called = %v, want %v", changed, tt.wantChanged)
+			}
+			if tt.wantRevoked {
+				assertRedirect(t, rec, "/admin/account?changed=1")
+				if keptSession != "current-session" {
+					t.Errorf("RevokeAllOtherSessions kept %q, want current session", keptSession)
+				}
+			} else if keptSession != "" {
+				t.Error("sessions revoked although the password wasn't changed")
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/EC-9624/0xec.dev/internal/errors"
+	"github.com/EC-9624/0xec.dev/internal/service"
+	"github.com/EC-9624/0xec.dev/web/templates/admin"
+	"github.com/EC-9624/0xec.dev/web/templates/components"
+)
+
+// activityPerPage is how many entries the activity log shows per page
+const activityPerPage = 50
+
+// AdminActivityList shows the activity log, optionally filtered by
+// ?type= (entity type) and ?action= (full action, e.g. post.published)
+func (h *Handlers) AdminActivityList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	filter := service.ActivityFilter{
+		EntityType: query.Get("type"),
+		Action:     query.Get("action"),
+	}
+
+	total, err := h.service.CountActivities(ctx, filter)
+	if err != nil {
+		errors.WriteInternalError(w, r, "Failed to load activity", err)
+		return
+	}
+
+	page := components.Pagination{
+		Page:    getPageParam(r),
+		PerPage: activityPerPage,
+		Total:   total,
+	}
+	if last := page.TotalPages(); page.Page > last {
+		page.Page = last
+	}
+
+	activities, err := h.service.ListActivities(ctx, filter, activityPerPage, (page.Page-1)*activityPerPage)
+	if err != nil {
+		errors.WriteInternalError(w, r, "Failed to load activity", err)
+		return
+	}
+
+	typeCounts, err := h.service.CountActivitiesByType(ctx)
+	if err != nil {
+		errors.WriteInternalError(w, r, "Failed to load activity", err)
+		return
+	}
+
+	data := admin.ActivityPageData{
+		Activities: activities,
+		Filter:     filter,
+		TypeCounts: typeCounts,
+		Page:       page,
+	}
+	h.renderPage(w, r, admin.ActivityPage(data), data)
+}
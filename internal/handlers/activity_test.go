@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/service"
+)
+
+func TestAdminActivityList_Filter(t *testing.T) {
+	var gotFilter service.ActivityFilter
+	var gotOffset int
+	mock := &mockService{
+		countActivitiesFunc: func(ctx context.Context, filter service.ActivityFilter) (int, error) {
+			return 120, nil
+		},
+		listActivitiesFunc: func(ctx context.Context, filter service.ActivityFilter, limit, offset int) ([]service.Activity, error) {
+			gotFilter = filter
+			gotOffset = offset
+			return []service.Activity{
+				{ID: 1, Action: service.ActionPostPublished, EntityType: service.EntityPost, Title: "Hello"},
+			}, nil
+		},
+		countActivitiesByTypeFunc: func(ctx context.Context) ([]service.ActivityTypeCount, error) {
+			return []service.ActivityTypeCount{{EntityType: service.EntityPost, Count: 120}}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/activity?type=post&action=post.published&page=2", nil)
+	rec := httptest.NewRecorder()
+
+	h.AdminActivityList(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	want := service.ActivityFilter{EntityType: service.EntityPost, Action: service.ActionPostPublished}
+	if gotFilter != want {
+		t.Errorf("filter = %+v, want %+v", gotFilter, want)
+	}
+	if gotOffset != activityPerPage {
+		t.Errorf("offset = %d, want %d", gotOffset, activityPerPage)
+	}
+	assertBodyContains(t, rec, "Published post")
+	assertBodyContains(t, rec, "Hello")
+}
+
+func TestAdminActivityList_Unfiltered(t *testing.T) {
+	var gotFilter service.ActivityFilter
+	mock := &mockService{
+		listActivitiesFunc: func(ctx context.Context, filter service.ActivityFilter, limit, offset int) ([]service.Activity, error) {
+			gotFilter = filter
+			return nil, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/activity", nil)
+	rec := httptest.NewRecorder()
+
+	h.AdminActivityList(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if gotFilter != (service.ActivityFilter{}) {
+		t.Errorf("filter = %+v, want empty", gotFilter)
+	}
+	assertBodyContains(t, rec, "No matching activity")
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/middleware"
+	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
+)
+
+// ============================================
+// JSON API
+// ============================================
+// These handlers serve /api/ and are only routed with the api feature on.
+
+const (
+	// apiBookmarksDefaultLimit is the page size when ?limit= is missing
+	apiBookmarksDefaultLimit = 20
+
+	// apiBookmarksMaxLimit caps ?limit=
+	apiBookmarksMaxLimit = 100
+)
+
+// APIBookmark is a bookmark as listed by the JSON API
+type APIBookmark struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Domain      string `json:"domain,omitempty"`
+	Description string `json:"description,omitempty"`
+	CoverImage  string `json:"cover_image,omitempty"` // absolute, under the image base URL when stored locally
+	CreatedAt   string `json:"created_at"`
+}
+
+// APIBookmarksResponse is the body of GET /api/bookmarks
+type APIBookmarksResponse struct {
+	Bookmarks []APIBookmark `json:"bookmarks"`
+	Total     int           `json:"total"`
+	Limit     int           `json:"limit"`
+	Offset    int           `json:"offset"`
+}
+
+// APIBookmarksList lists public bookmarks as JSON. ?collection= (a slug,
+// sub-collections included) and ?favorites=true narrow the listing; ?limit=
+// and ?offset= page through it. There are no API keys yet, so private
+// bookmarks are never listed and a request carrying a token is rejected.
+func (h *Handlers) APIBookmarksList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	if middleware.APIToken(r) != "" {
+		writeAPIError(w, http.StatusUnauthorized, "invalid API token")
+		return
+	}
+
+	opts := service.BookmarkListOptions{
+		PublicOnly: true,
+		Limit:      apiBookmarksDefaultLimit,
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > apiBookmarksMaxLimit {
+			writeAPIError(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(apiBookmarksMaxLimit))
+			return
+		}
+		opts.Limit = limit
+	}
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			writeAPIError(w, http.StatusBadRequest, "offset must be a non-negative integer")
+			return
+		}
+		opts.Offset = offset
+	}
+	if v := query.Get("favorites"); v != "" {
+		favorites, err := strconv.ParseBool(v)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, "favorites must be true or false")
+			return
+		}
+		opts.FavoritesOnly = favorites
+	}
+
+	if slug := query.Get("collection"); slug != "" {
+		// Favorites span every collection, so the filters don't combine
+		if opts.FavoritesOnly {
+			writeAPIError(w, http.StatusBadRequest, "collection and favorites can't be combined")
+			return
+		}
+		collection, err := h.service.GetCollectionBySlug(ctx, slug)
+		if err != nil || !collection.IsPublic {
+			writeAPIError(w, http.StatusNotFound, "collection not found")
+			return
+		}
+		opts.CollectionID = &collection.ID
+		opts.IncludeDescendants = true
+	}
+
+	bookmarks, err := h.service.ListBookmarks(ctx, opts)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to load bookmarks")
+		return
+	}
+	total, err := h.service.CountBookmarks(ctx, opts)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to load bookmarks")
+		return
+	}
+
+	resp := APIBookmarksResponse{
+		Bookmarks: make([]APIBookmark, 0, len(bookmarks)),
+		Total:     total,
+		Limit:     opts.Limit,
+		Offset:    opts.Offset,
+	}
+	updated := make([]time.Time, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		resp.Bookmarks = append(resp.Bookmarks, h.apiBookmark(b))
+		updated = append(updated, b.UpdatedAt)
+	}
+
+	setLastModified(w, updated...)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// apiBookmark converts a bookmark to its API representation
+func (h *Handlers) apiBookmark(b models.Bookmark) APIBookmark {
+	return APIBookmark{
+		URL:         b.URL,
+		Title:       b.Title,
+		Domain:      b.GetDomain(),
+		Description: b.GetDescription(),
+		CoverImage:  h.absoluteURL(b.CoverImageURL(h.config.ImageBaseURL)),
+		CreatedAt:   b.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// writeAPIError writes a JSON error body in the same shape as the API rate
+// limiter's
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
+)
+
+func TestAPIBookmarksList_PublicOnly(t *testing.T) {
+	var listed service.BookmarkListOptions
+	mock := &mockService{
+		listBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
+			listed = opts
+			return []models.Bookmark{{
+				ID:           1,
+				URL:          "https://example.com/post",
+				Title:        "Example",
+				Domain:       sql.NullString{String: "example.com", Valid: true},
+				CoverImageID: sql.NullInt64{Int64: 7, Valid: true},
+				CoverImage:   sql.NullString{String: "https://example.com/cover.png", Valid: true},
+				IsPublic:     true,
+				CreatedAt:    time.Date(2024, 11, 2, 10, 0, 0, 0, time.UTC),
+			}}, nil
+		},
+		countBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) (int, error) {
+			return 1, nil
+		},
+	}
+	h := newTestHandlers(mock)
+	h.config.BaseURL = "https://example.com"
+
+	req := httptest.NewRequest(http.MethodGet, "/api/bookmarks?limit=5&offset=10", nil)
+	rec := httptest.NewRecorder()
+
+	h.APIBookmarksList(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if !listed.PublicOnly {
+		t.Error("unauthenticated request listed without PublicOnly")
+	}
+	if listed.Limit != 5 || listed.Offset != 10 {
+		t.Errorf("Limit, Offset = %d, %d; want 5, 10", listed.Limit, listed.Offset)
+	}
+
+	var resp APIBookmarksResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Bookmarks) != 1 {
+		t.Fatalf("got %d bookmarks, want 1", len(resp.Bookmarks))
+	}
+	got := resp.Bookmarks[0]
+	if got.Domain != "example.com" || got.CoverImage != "https://example.com/images/7" || got.CreatedAt != "2024-11-02T10:00:00Z" {
+		t.Errorf("bookmark = %+v", got)
+	}
+}
+
+func TestAPIBookmarksList_ImageBaseURL(t *testing.T) {
+	mock := &mockService{
+		listBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
+			return []models.Bookmark{{
+				ID:           1,
+				URL:          "https://example.com/post",
+				Title:        "Example",
+				CoverImageID: sql.NullInt64{Int64: 7, Valid: true},
+			}}, nil
+		},
+		countBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) (int, error) {
+			return 1, nil
+		},
+	}
+	h := newTestHandlers(mock)
+	h.config.BaseURL = "https://example.com"
+	h.config.ImageBaseURL = "https://cdn.example.net"
+
+	rec := httptest.NewRecorder()
+	h.APIBookmarksList(rec, httptest.NewRequest(http.MethodGet, "/api/bookmarks", nil))
+
+	var resp APIBookmarksResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Bookmarks) != 1 || resp.Bookmarks[0].CoverImage != "https://cdn.example.net/images/7" {
+		t.Errorf("bookmarks = %+v, want cover under the image base URL", resp.Bookmarks)
+	}
+}
+
+func TestAPIBookmarksList_RejectsToken(t *testing.T) {
+	mock := &mockService{
+		listBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
+			t.Error("listed bookmarks for an unknown token")
+			return nil, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/bookmarks", nil)
+	req.Header.Set("Authorization", "Bearer guessed")
+	rec := httptest.NewRecorder()
+
+	h.APIBookmarksList(rec, req)
+
+	assertStatus(t, rec, http.StatusUnauthorized)
+}
+
+func TestAPIBookmarksList_Collection(t *testing.T) {
+	collections := map[string]*models.Collection{
+		"reading": {ID: 3, Slug: "reading", IsPublic: true},
+		"private": {ID: 4, Slug: "private", IsPublic: false},
+	}
+	var listed service.BookmarkListOptions
+	mock := &mockService{
+		getCollectionBySlugFunc: func(ctx context.Context, slug string) (*models.Collection, error) {
+			if c, ok := collections[slug]; ok {
+				return c, nil
+			}
+			return nil, errors.New("not found")
+		},
+		listBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
+			listed = opts
+			return []models.Bookmark{}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	tests := []struct {
+		query  string
+		status int
+	}{
+		{"?collection=reading", http.StatusOK},
+		{"?collection=private", http.StatusNotFound},
+		{"?collection=missing", http.StatusNotFound},
+		{"?collection=reading&favorites=true", http.StatusBadRequest},
+		{"?limit=0", http.StatusBadRequest},
+		{"?offset=-1", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			listed = service.BookmarkListOptions{}
+			req := httptest.NewRequest(http.MethodGet, "/api/bookmarks"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			h.APIBookmarksList(rec, req)
+
+			assertStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				return
+			}
+			if listed.CollectionID == nil || *listed.CollectionID != 3 || !listed.IncludeDescendants {
+				t.Errorf("CollectionID = %v, IncludeDescendants = %v; want 3 with descendants", listed.CollectionID, listed.IncludeDescendants)
+			}
+			if !listed.PublicOnly {
+				t.Error("collection listed without PublicOnly")
+			}
+		})
+	}
+}
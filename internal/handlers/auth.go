@@ -1,14 +1,19 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/EC-9624/0xec.dev/internal/middleware"
+	"github.com/EC-9624/0xec.dev/internal/models"
 	"github.com/EC-9624/0xec.dev/web/templates/admin"
 	"github.com/EC-9624/0xec.dev/web/templates/pages"
 )
 
-const sessionDuration = 7 * 24 * time.Hour // 7 days
+// sessionDuration is the lifetime of a login without "remember me". Its
+// cookie has no MaxAge, so it also ends when the browser is closed.
+const sessionDuration = models.SessionDuration
 
 // LoginPage handles the login page
 func (h *Handlers) LoginPage(w http.ResponseWriter, r *http.Request) {
@@ -25,7 +30,7 @@ func (h *Handlers) LoginPage(w http.ResponseWriter, r *http.Request) {
 
 // Login handles the login form submission
 func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
-	if err := r.ParseForm(); err != nil {
+	if err := h.parseForm(w, r); err != nil {
 		render(w, r, pages.Login("Invalid form data"))
 		return
 	}
@@ -33,60 +38,119 @@ func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
 	username := r.FormValue("username")
 	password := r.FormValue("password")
 
+	// Unknown usernames still go through ValidatePassword (with a nil user)
+	// so they take as long as a wrong password for a real account
 	user, err := h.service.GetUserByUsername(r.Context(), username)
 	if err != nil {
-		render(w, r, pages.Login("Invalid username or password"))
+		user = nil
+	}
+	valid := h.service.ValidatePassword(user, password)
+
+	// Locked accounts get the same response as a wrong password, so the
+	// lockout doesn't reveal which usernames exist
+	if user != nil && user.IsLocked(time.Now()) {
+		render(w, r, pages.Login(h.loginFailedMessage()))
 		return
 	}
 
-	if !h.service.ValidatePassword(user, password) {
-		render(w, r, pages.Login("Invalid username or password"))
+	if !valid {
+		if user != nil {
+			lockout := time.Duration(h.config.LoginLockoutMinutes) * time.Minute
+			h.service.RecordFailedLogin(r.Context(), user.ID, h.config.LoginMaxAttempts, lockout)
+		}
+		render(w, r, pages.Login(h.loginFailedMessage()))
 		return
 	}
 
+	if user.FailedLoginAttempts > 0 || user.LockedUntil.Valid {
+		h.service.ResetFailedLogins(r.Context(), user.ID)
+	}
+
 	// Get existing session ID if any (for rotation to prevent session fixation)
 	var oldSessionID string
 	if cookie, err := r.Cookie("session"); err == nil {
 		oldSessionID = cookie.Value
 	}
 
+	duration := sessionDuration
+	if r.FormValue("remember") != "" {
+		duration = h.rememberDuration()
+	}
+
 	// Create new session (and delete old one if it exists)
-	session, err := h.service.RotateSession(r.Context(), user.ID, oldSessionID, sessionDuration)
+	client := models.SessionClient{
+		UserAgent: r.UserAgent(),
+		IPAddress: middleware.ClientIP(r),
+	}
+	session, err := h.service.RotateSession(r.Context(), user.ID, oldSessionID, duration, client)
 	if err != nil {
 		render(w, r, pages.Login("Failed to create session"))
 		return
 	}
 
-	// Set session cookie
-	http.SetCookie(w, &http.Cookie{
+	// Remembered sessions get a persistent cookie matching their lifetime;
+	// RotateSession may have kept a longer duration from the old session.
+	// Short sessions use a browser-session cookie.
+	cookie := &http.Cookie{
 		Name:     "session",
 		Value:    session.ID,
 		Path:     "/",
 		HttpOnly: true,
 		Secure:   !h.config.IsDevelopment(),
 		SameSite: http.SameSiteLaxMode,
-		MaxAge:   int(sessionDuration.Seconds()),
-	})
+	}
+	if session.Remembered() {
+		cookie.MaxAge = int(session.Duration.Seconds())
+	}
+	http.SetCookie(w, cookie)
 
 	http.Redirect(w, r, "/admin", http.StatusSeeOther)
 }
 
+// rememberDuration returns the configured "remember me" session lifetime
+func (h *Handlers) rememberDuration() time.Duration {
+	if h.config.RememberSessionDays <= 0 {
+		return sessionDuration
+	}
+	return time.Duration(h.config.RememberSessionDays) * 24 * time.Hour
+}
+
+// loginFailedMessage is shown for every refused login: a wrong password, an
+// unknown username or a locked account. It mentions the lockout policy
+// without saying whether it applies, so usernames can't be probed.
+func (h *Handlers) loginFailedMessage() string {
+	if h.config.LoginMaxAttempts <= 0 || h.config.LoginLockoutMinutes <= 0 {
+		return "Invalid username or password"
+	}
+	return fmt.Sprintf("Invalid username or password. After %d failed attempts, sign-in is paused for %d minutes.",
+		h.config.LoginMaxAttempts, h.config.LoginLockoutMinutes)
+}
+
 // Logout handles logging out
 func (h *Handlers) Logout(w http.ResponseWriter, r *http.Request) {
 	if cookie, err := r.Cookie("session"); err == nil {
 		h.service.DeleteSession(r.Context(), cookie.Value)
 	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:   "session",
-		Value:  "",
-		Path:   "/",
-		MaxAge: -1,
-	})
+	h.clearSessionCookie(w)
 
 	http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
 }
 
+// clearSessionCookie expires the session cookie, using the same attributes
+// it was set with so the browser replaces it
+func (h *Handlers) clearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "session",
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   !h.config.IsDevelopment(),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
 // AdminDashboard handles the admin dashboard
 func (h *Handlers) AdminDashboard(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -101,8 +165,9 @@ func (h *Handlers) AdminDashboard(w http.ResponseWriter, r *http.Request) {
 	// Get recent activities
 	activities, _ := h.service.ListRecentActivities(ctx, 10, 0)
 
-	render(w, r, admin.Dashboard(admin.DashboardData{
+	data := admin.DashboardData{
 		Stats:      stats,
 		Activities: activities,
-	}))
+	}
+	h.renderPage(w, r, admin.Dashboard(data), data)
 }
@@ -146,7 +146,7 @@ func TestLogin_Success(t *testing.T) {
 		validatePasswordFunc: func(user *models.User, password string) bool {
 			return password == "correctpassword"
 		},
-		rotateSessionFunc: func(ctx context.Context, userID int64, oldSessionID string, duration time.Duration) (*models.Session, error) {
+		rotateSessionFunc: func(ctx context.Context, userID int64, oldSessionID string, duration time.Duration, client models.SessionClient) (*models.Session, error) {
 			if userID == testUser.ID {
 				return testSession, nil
 			}
@@ -169,6 +169,250 @@ func TestLogin_Success(t *testing.T) {
 	assertCookie(t, rec, "session", testSession.ID)
 }
 
+func TestLogin_RememberMe(t *testing.T) {
+	tests := []struct {
+		name         string
+		remember     bool
+		wantDuration time.Duration
+		wantMaxAge   int
+	}{
+		{"remembered", true, 30 * 24 * time.Hour, int((30 * 24 * time.Hour).Seconds())},
+		{"not remembered", false, sessionDuration, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotDuration time.Duration
+			mock := &mockService{
+				getUserByUsernameFunc: func(ctx context.Context, username string) (*models.User, error) {
+					return &models.User{ID: 1, Username: "admin"}, nil
+				},
+				validatePasswordFunc: func(user *models.User, password string) bool {
+					return true
+				},
+				rotateSessionFunc: func(ctx context.Context, userID int64, oldSessionID string, duration time.Duration, client models.SessionClient) (*models.Session, error) {
+					gotDuration = duration
+					return &models.Session{
+						ID:        "new-session-id",
+						UserID:    userID,
+						ExpiresAt: time.Now().Add(duration),
+						Duration:  duration,
+					}, nil
+				},
+			}
+			h := newTestHandlers(mock)
+			h.config.RememberSessionDays = 30
+
+			form := url.Values{}
+			form.Set("username", "admin")
+			form.Set("password", "password")
+			if tt.remember {
+				form.Set("remember", "true")
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/login", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+
+			h.Login(rec, req)
+
+			assertRedirect(t, rec, "/admin")
+			if gotDuration != tt.wantDuration {
+				t.Errorf("session duration = %v, want %v", gotDuration, tt.wantDuration)
+			}
+			for _, c := range rec.Result().Cookies() {
+				if c.Name == "session" && c.MaxAge != tt.wantMaxAge {
+					t.Errorf("cookie MaxAge = %d, want %d", c.MaxAge, tt.wantMaxAge)
+				}
+			}
+		})
+	}
+}
+
+func TestLogin_UnknownUserStillChecksPassword(t *testing.T) {
+	var checked bool
+	mock := &mockService{
+		getUserByUsernameFunc: func(ctx context.Context, username string) (*models.User, error) {
+			return nil, sql.ErrNoRows
+		},
+		validatePasswordFunc: func(user *models.User, password string) bool {
+			checked = user == nil
+			return false
+		},
+		recordFailedLoginFunc: func(ctx context.Context, userID int64, maxAttempts int, lockout time.Duration) (bool, error) {
+			t.Error("RecordFailedLogin called for unknown user")
+			return false, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	form := url.Values{}
+	form.Set("username", "nonexistent")
+	form.Set("password", "password")
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	h.Login(rec, req)
+
+	if !checked {
+		t.Error("ValidatePassword not called with nil user for unknown username")
+	}
+	assertBodyContains(t, rec, "Invalid username or password")
+}
+
+func TestLogin_LockedAccount(t *testing.T) {
+	lockedUser := &models.User{
+		ID:          1,
+		Username:    "admin",
+		LockedUntil: sql.NullTime{Time: time.Now().Add(10 * time.Minute), Valid: true},
+	}
+
+	mock := &mockService{
+		getUserByUsernameFunc: func(ctx context.Context, username string) (*models.User, error) {
+			return lockedUser, nil
+		},
+		validatePasswordFunc: func(user *models.User, password string) bool {
+			return true // Correct password is still refused while locked
+		},
+		rotateSessionFunc: func(ctx context.Context, userID int64, oldSessionID string, duration time.Duration, client models.SessionClient) (*models.Session, error) {
+			t.Error("session created for locked account")
+			return nil, errors.New("unexpected")
+		},
+	}
+	h := newTestHandlers(mock)
+
+	form := url.Values{}
+	form.Set("username", "admin")
+	form.Set("password", "correctpassword")
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	h.Login(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, "Invalid username or password")
+	if strings.Contains(rec.Body.String(), "locked") {
+		t.Error("response reveals that the account is locked")
+	}
+}
+
+func TestLogin_LocksAfterMaxAttempts(t *testing.T) {
+	var gotMax int
+	var gotLockout time.Duration
+	mock := &mockService{
+		getUserByUsernameFunc: func(ctx context.Context, username string) (*models.User, error) {
+			return &models.User{ID: 1, Username: "admin", FailedLoginAttempts: 4}, nil
+		},
+		validatePasswordFunc: func(user *models.User, password string) bool {
+			return false
+		},
+		recordFailedLoginFunc: func(ctx context.Context, userID int64, maxAttempts int, lockout time.Duration) (bool, error) {
+			gotMax, gotLockout = maxAttempts, lockout
+			return true, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	form := url.Values{}
+	form.Set("username", "admin")
+	form.Set("password", "wrongpassword")
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	h.Login(rec, req)
+
+	if gotMax != 5 || gotLockout != 15*time.Minute {
+		t.Errorf("RecordFailedLogin(max=%d, lockout=%v), want 5 and 15m", gotMax, gotLockout)
+	}
+	assertBodyContains(t, rec, "Invalid username or password")
+}
+
+func TestLogin_LockedAndUnknownLookTheSame(t *testing.T) {
+	login := func(user *models.User) string {
+		mock := &mockService{
+			getUserByUsernameFunc: func(ctx context.Context, username string) (*models.User, error) {
+				if user == nil {
+					return nil, sql.ErrNoRows
+				}
+				return user, nil
+			},
+			validatePasswordFunc: func(u *models.User, password string) bool {
+				return false
+			},
+			recordFailedLoginFunc: func(ctx context.Context, userID int64, maxAttempts int, lockout time.Duration) (bool, error) {
+				return true, nil
+			},
+		}
+		h := newTestHandlers(mock)
+
+		form := url.Values{}
+		form.Set("username", "admin")
+		form.Set("password", "wrongpassword")
+		req := httptest.NewRequest(http.MethodPost, "/admin/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+
+		h.Login(rec, req)
+		return rec.Body.String()
+	}
+
+	unknown := login(nil)
+	locking := login(&models.User{ID: 1, Username: "admin", FailedLoginAttempts: 4})
+	locked := login(&models.User{
+		ID:          1,
+		Username:    "admin",
+		LockedUntil: sql.NullTime{Time: time.Now().Add(10 * time.Minute), Valid: true},
+	})
+
+	if locking != unknown {
+		t.Error("the attempt that locks an account differs from an unknown username")
+	}
+	if locked != unknown {
+		t.Error("a locked account differs from an unknown username")
+	}
+}
+
+func TestLogin_SuccessResetsFailedLogins(t *testing.T) {
+	var reset bool
+	mock := &mockService{
+		getUserByUsernameFunc: func(ctx context.Context, username string) (*models.User, error) {
+			return &models.User{ID: 1, Username: "admin", FailedLoginAttempts: 2}, nil
+		},
+		validatePasswordFunc: func(user *models.User, password string) bool {
+			return true
+		},
+		resetFailedLoginsFunc: func(ctx context.Context, userID int64) error {
+			reset = true
+			return nil
+		},
+		rotateSessionFunc: func(ctx context.Context, userID int64, oldSessionID string, duration time.Duration, client models.SessionClient) (*models.Session, error) {
+			return &models.Session{ID: "new-session-id", UserID: userID, Duration: duration}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	form := url.Values{}
+	form.Set("username", "admin")
+	form.Set("password", "correctpassword")
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	h.Login(rec, req)
+
+	assertRedirect(t, rec, "/admin")
+	if !reset {
+		t.Error("ResetFailedLogins not called after successful login")
+	}
+}
+
 func TestLogin_SessionCreationFails(t *testing.T) {
 	testUser := &models.User{
 		ID:           1,
@@ -183,7 +427,7 @@ func TestLogin_SessionCreationFails(t *testing.T) {
 		validatePasswordFunc: func(user *models.User, password string) bool {
 			return true
 		},
-		rotateSessionFunc: func(ctx context.Context, userID int64, oldSessionID string, duration time.Duration) (*models.Session, error) {
+		rotateSessionFunc: func(ctx context.Context, userID int64, oldSessionID string, duration time.Duration, client models.SessionClient) (*models.Session, error) {
 			return nil, errors.New("database error")
 		},
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/EC-9624/0xec.dev/internal/models"
 	"github.com/EC-9624/0xec.dev/internal/service"
 	"github.com/EC-9624/0xec.dev/web/templates/admin"
+	"github.com/EC-9624/0xec.dev/web/templates/components"
 )
 
 // ============================================
@@ -29,13 +30,17 @@ func (h *Handlers) AdminBookmarksList(w http.ResponseWriter, r *http.Request) {
 	// Check if filtering to a specific collection
 	collectionParam := r.URL.Query().Get("collection")
 
+	// Archived bookmarks are only listed on request, in the table view
+	archivedOnly := r.URL.Query().Get("archived") == "1"
+
 	// Build page data
 	data := admin.BookmarksPageData{
-		View: view,
+		View:         view,
+		ArchivedOnly: archivedOnly,
 	}
 
 	// Handle different views
-	if view == "board" && collectionParam == "" {
+	if view == "board" && collectionParam == "" && !archivedOnly {
 		// Board view - show Kanban columns with all bookmarks
 		boardData, err := h.service.GetBoardViewData(ctx, 100) // All bookmarks per collection (up to 100)
 		if err != nil {
@@ -53,52 +58,19 @@ func (h *Handlers) AdminBookmarksList(w http.ResponseWriter, r *http.Request) {
 		}
 		data.Collections = collections
 
-		// Handle filtering
-		if collectionParam == "unsorted" {
-			// Filter to unsorted bookmarks
-			data.FilteredCollectionID = "unsorted"
-			bookmarks, err := h.service.ListUnsortedBookmarks(ctx, h.config.AdminBookmarksLimit, 0)
-			if err != nil {
-				errors.WriteInternalError(w, r, "Failed to load unsorted bookmarks", err)
-				return
-			}
-			data.Bookmarks = bookmarks
-		} else if collectionParam != "" {
-			// Filter to specific collection
-			collectionID, err := strconv.ParseInt(collectionParam, 10, 64)
-			if err == nil {
-				collection, err := h.service.GetCollectionByID(ctx, collectionID)
-				if err == nil {
-					data.FilteredCollection = collection
-					data.PreselectedCollectionID = collectionID
-				}
-
-				bookmarks, err := h.service.ListBookmarks(ctx, service.BookmarkListOptions{
-					CollectionID: &collectionID,
-					Limit:        h.config.AdminBookmarksLimit,
-					Offset:       0,
-				})
-				if err != nil {
-					errors.WriteInternalError(w, r, "Failed to load bookmarks", err)
-					return
-				}
-				data.Bookmarks = bookmarks
-			}
-		} else {
-			// All bookmarks
-			bookmarks, err := h.service.ListBookmarks(ctx, service.BookmarkListOptions{
-				Limit:  h.config.AdminBookmarksLimit,
-				Offset: 0,
-			})
-			if err != nil {
-				errors.WriteInternalError(w, r, "Failed to load bookmarks", err)
-				return
-			}
-			data.Bookmarks = bookmarks
+		tags, err := h.service.ListTags(ctx)
+		if err != nil {
+			logger.Error(ctx, "failed to load tags for bookmarks list", "error", err)
+		}
+		data.Tags = tags
+
+		if err := h.loadAdminBookmarksTable(r, &data, collectionParam); err != nil {
+			errors.WriteInternalError(w, r, "Failed to load bookmarks", err)
+			return
 		}
 	}
 
-	render(w, r, admin.BookmarksPage(data))
+	h.renderPage(w, r, admin.BookmarksPage(data), data)
 }
 
 // HTMXBookmarksView handles HTMX requests to switch between board/table views
@@ -119,30 +91,83 @@ func (h *Handlers) HTMXBookmarksView(w http.ResponseWriter, r *http.Request) {
 		}
 		render(w, r, admin.BoardViewPartial(boardData))
 	} else {
-		// Table view - load bookmarks list
-		bookmarks, err := h.service.ListBookmarks(ctx, service.BookmarkListOptions{
-			Limit:  h.config.AdminBookmarksLimit,
-			Offset: 0,
-		})
+		// Table view - load the first page of bookmarks
+		collections, err := h.service.ListCollections(ctx, false)
 		if err != nil {
-			errors.WriteInternalError(w, r, "Failed to load bookmarks", err)
-			return
+			logger.Error(ctx, "failed to load collections for table view", "error", err)
 		}
 
-		collections, err := h.service.ListCollections(ctx, false)
+		tags, err := h.service.ListTags(ctx)
 		if err != nil {
-			logger.Error(ctx, "failed to load collections for table view", "error", err)
+			logger.Error(ctx, "failed to load tags for table view", "error", err)
 		}
 
 		data := admin.BookmarksPageData{
 			View:        "table",
-			Bookmarks:   bookmarks,
 			Collections: collections,
+			Tags:        tags,
+		}
+		if err := h.loadAdminBookmarksTable(r, &data, ""); err != nil {
+			errors.WriteInternalError(w, r, "Failed to load bookmarks", err)
+			return
 		}
 		render(w, r, admin.TableViewPartial(data))
 	}
 }
 
+// loadAdminBookmarksTable loads the page of the admin bookmarks table given
+// by ?page= into data. collectionParam filters to a collection ID or to
+// "unsorted"; empty lists every bookmark. A page past the end is clamped
+// to the last page.
+func (h *Handlers) loadAdminBookmarksTable(r *http.Request, data *admin.BookmarksPageData, collectionParam string) error {
+	ctx := r.Context()
+	opts := service.BookmarkListOptions{ArchivedOnly: data.ArchivedOnly}
+	unsorted := collectionParam == "unsorted"
+
+	if unsorted {
+		data.FilteredCollectionID = "unsorted"
+	} else if collectionParam != "" {
+		collectionID, err := strconv.ParseInt(collectionParam, 10, 64)
+		if err != nil {
+			return nil
+		}
+		if collection, err := h.service.GetCollectionByID(ctx, collectionID); err == nil {
+			data.FilteredCollection = collection
+			data.PreselectedCollectionID = collectionID
+		}
+		opts.CollectionID = &collectionID
+	}
+
+	var total int
+	var err error
+	if unsorted {
+		total, err = h.service.CountUnsortedBookmarks(ctx)
+	} else {
+		total, err = h.service.CountBookmarks(ctx, opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	data.Page = components.Pagination{
+		Page:    getPageParam(r),
+		PerPage: h.config.AdminBookmarksPerPage,
+		Total:   total,
+	}
+	if data.Page.Page > data.Page.TotalPages() {
+		data.Page.Page = data.Page.TotalPages()
+	}
+	opts.Limit = data.Page.PerPage
+	opts.Offset = (data.Page.Page - 1) * data.Page.PerPage
+
+	if unsorted {
+		data.Bookmarks, err = h.service.ListUnsortedBookmarks(ctx, opts.Limit, opts.Offset)
+	} else {
+		data.Bookmarks, err = h.service.ListBookmarks(ctx, opts)
+	}
+	return err
+}
+
 // AdminBookmarkNew handles the new bookmark form
 func (h *Handlers) AdminBookmarkNew(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -157,8 +182,8 @@ func (h *Handlers) AdminBookmarkNew(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) AdminBookmarkCreate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Invalid form data")
 		return
 	}
 
@@ -168,16 +193,22 @@ func (h *Handlers) AdminBookmarkCreate(w http.ResponseWriter, r *http.Request) {
 		URL:          r.FormValue("url"),
 		Title:        r.FormValue("title"),
 		Description:  r.FormValue("description"),
+		Note:         r.FormValue("note"),
 		CoverImage:   r.FormValue("cover_image"),
+		Favicon:      r.FormValue("favicon"),
 		CollectionID: parseFormInt64(r, "collection_id"),
 		IsPublic:     r.FormValue("is_public") == "true",
 		IsFavorite:   r.FormValue("is_favorite") == "true",
+
+		KeepOriginalURL: r.FormValue("keep_original_url") == "true",
 	}
 
 	// Validate input
 	formErrors := input.Validate()
 
-	// Check URL uniqueness (only if URL is valid so far)
+	// Check URL uniqueness (only if URL is valid so far). The lookup
+	// matches normalized URLs, so trailing slashes or tracking params
+	// don't hide a duplicate.
 	if formErrors == nil || !formErrors.HasField("url") {
 		existing, _ := h.service.GetBookmarkByURL(ctx, input.URL)
 		if existing != nil {
@@ -247,7 +278,10 @@ func (h *Handlers) AdminBookmarkEdit(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		logger.Error(ctx, "failed to load collections for bookmark edit form", "error", err)
 	}
-	render(w, r, admin.BookmarkForm(bookmark, collections, false, nil, nil))
+	h.renderPage(w, r, admin.BookmarkForm(bookmark, collections, false, nil, nil), map[string]any{
+		"Bookmark":    bookmark,
+		"Collections": collections,
+	})
 }
 
 // AdminBookmarkUpdate handles updating a bookmark
@@ -266,18 +300,22 @@ func (h *Handlers) AdminBookmarkUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := r.ParseForm(); err != nil {
-		errors.WriteBadRequest(w, r, "Invalid form data")
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Invalid form data")
 		return
 	}
 
+	upload, uploadProblem := readCoverUpload(r)
+
 	isDrawer := r.FormValue("_drawer") == "true"
 
 	input := models.UpdateBookmarkInput{
 		URL:          r.FormValue("url"),
 		Title:        r.FormValue("title"),
 		Description:  r.FormValue("description"),
+		Note:         r.FormValue("note"),
 		CoverImage:   r.FormValue("cover_image"),
+		Favicon:      r.FormValue("favicon"),
 		CollectionID: parseFormInt64(r, "collection_id"),
 		IsPublic:     r.FormValue("is_public") == "true",
 		IsFavorite:   r.FormValue("is_favorite") == "true",
@@ -285,11 +323,18 @@ func (h *Handlers) AdminBookmarkUpdate(w http.ResponseWriter, r *http.Request) {
 
 	// Validate input
 	formErrors := input.Validate()
+	if uploadProblem != "" {
+		if formErrors == nil {
+			formErrors = models.NewFormErrors()
+		}
+		formErrors.AddField("cover_image", uploadProblem)
+	}
 
-	// Check URL uniqueness (only if URL changed and is valid so far)
+	// Check URL uniqueness (only if URL changed and is valid so far).
+	// A variant of the bookmark's own URL matches itself, which is fine.
 	if (formErrors == nil || !formErrors.HasField("url")) && input.URL != bookmark.URL {
 		existing, _ := h.service.GetBookmarkByURL(ctx, input.URL)
-		if existing != nil {
+		if existing != nil && existing.ID != bookmark.ID {
 			if formErrors == nil {
 				formErrors = models.NewFormErrors()
 			}
@@ -305,7 +350,9 @@ func (h *Handlers) AdminBookmarkUpdate(w http.ResponseWriter, r *http.Request) {
 			URL:          input.URL,
 			Title:        input.Title,
 			Description:  input.Description,
+			Note:         input.Note,
 			CoverImage:   input.CoverImage,
+			Favicon:      input.Favicon,
 			CollectionID: input.CollectionID,
 			IsPublic:     input.IsPublic,
 			IsFavorite:   input.IsFavorite,
@@ -319,7 +366,14 @@ func (h *Handlers) AdminBookmarkUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Store an uploaded cover after the update, which would otherwise drop
+	// it if the cover URL changed too
 	updatedBookmark, err := h.service.UpdateBookmark(ctx, id, input)
+	if err == nil && upload != nil {
+		if _, err = h.service.StoreBookmarkCover(ctx, id, upload.MimeType, upload.Data); err == nil {
+			updatedBookmark, err = h.service.GetBookmarkByID(ctx, id)
+		}
+	}
 	if err != nil {
 		logger.Error(ctx, "failed to update bookmark", "error", err, "id", id)
 		formErrors := models.NewFormErrors()
@@ -329,6 +383,7 @@ func (h *Handlers) AdminBookmarkUpdate(w http.ResponseWriter, r *http.Request) {
 			URL:          input.URL,
 			Title:        input.Title,
 			Description:  input.Description,
+			Note:         input.Note,
 			CoverImage:   input.CoverImage,
 			CollectionID: input.CollectionID,
 			IsPublic:     input.IsPublic,
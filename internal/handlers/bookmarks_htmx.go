@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/EC-9624/0xec.dev/internal/logger"
 	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
 	"github.com/EC-9624/0xec.dev/web/templates/admin"
 )
 
@@ -16,42 +20,106 @@ import (
 // ============================================
 // These handlers serve HTMX partial responses for bookmark management.
 
-// AdminBookmarkFetchMetadata handles fetching metadata for a URL via HTMX
+// metadataFields are the bookmark form fields fetched metadata can fill,
+// with their labels in the review partial
+var metadataFields = []struct{ name, label string }{
+	{"title", "Title"},
+	{"description", "Description"},
+	{"cover_image", "Cover image"},
+	{"favicon", "Favicon"},
+}
+
+// AdminBookmarkFetchMetadata handles fetching metadata for a URL via HTMX.
+// The request carries the form's current field values. If they're all
+// empty the fetched values fill the form directly; otherwise the admin
+// reviews current vs fetched values and picks which to apply, with empty
+// fields checked by default.
 func (h *Handlers) AdminBookmarkFetchMetadata(w http.ResponseWriter, r *http.Request) {
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Invalid form data")
 		return
 	}
 
+	current := map[string]string{}
+	for _, f := range metadataFields {
+		current[f.name] = r.FormValue(f.name)
+	}
+
 	url := r.FormValue("url")
 	if url == "" {
-		// Return empty fields
-		render(w, r, admin.BookmarkMetadataFields(nil))
+		render(w, r, admin.BookmarkMetadataFields(metadataBookmark(current)))
 		return
 	}
 
 	metadata, err := h.service.FetchPageMetadata(r.Context(), url)
 	if err != nil {
-		// On error, return empty fields (user can fill manually)
-		render(w, r, admin.BookmarkMetadataFields(nil))
+		// On error, keep the current values (user can fill manually)
+		render(w, r, admin.BookmarkMetadataFields(metadataBookmark(current)))
 		return
 	}
 
-	// Create a temporary bookmark struct to populate the form
-	bookmark := &models.Bookmark{
-		Title: metadata.Title,
+	fetched := map[string]string{
+		"title":       metadata.Title,
+		"description": metadata.Description,
+		"cover_image": metadata.Image,
+		"favicon":     metadata.Favicon,
 	}
-	// Set description and cover image via the nullable fields
-	if metadata.Description != "" {
-		bookmark.Description.String = metadata.Description
-		bookmark.Description.Valid = true
+
+	// Nothing to overwrite: fill the form straight away. The favicon isn't
+	// editable, so it doesn't count as curated data.
+	if current["title"] == "" && current["description"] == "" && current["cover_image"] == "" {
+		if fetched["favicon"] == "" {
+			fetched["favicon"] = current["favicon"]
+		}
+		render(w, r, admin.BookmarkMetadataFields(metadataBookmark(fetched)))
+		return
 	}
-	if metadata.Image != "" {
-		bookmark.CoverImage.String = metadata.Image
-		bookmark.CoverImage.Valid = true
+
+	choices := make([]admin.MetadataChoice, 0, len(metadataFields))
+	for _, f := range metadataFields {
+		choices = append(choices, admin.MetadataChoice{
+			Field:   f.name,
+			Label:   f.label,
+			Current: current[f.name],
+			Fetched: fetched[f.name],
+			Apply:   current[f.name] == "" && fetched[f.name] != "",
+		})
+	}
+	render(w, r, admin.BookmarkMetadataReview(choices))
+}
+
+// AdminBookmarkApplyMetadata applies the fetched values of the fields
+// checked in the review partial and renders the metadata fields
+func (h *Handlers) AdminBookmarkApplyMetadata(w http.ResponseWriter, r *http.Request) {
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Invalid form data")
+		return
 	}
 
-	render(w, r, admin.BookmarkMetadataFields(bookmark))
+	apply := map[string]bool{}
+	for _, name := range r.Form["apply"] {
+		apply[name] = true
+	}
+
+	values := map[string]string{}
+	for _, f := range metadataFields {
+		values[f.name] = r.FormValue(f.name)
+		if apply[f.name] {
+			values[f.name] = r.FormValue("fetched_" + f.name)
+		}
+	}
+	render(w, r, admin.BookmarkMetadataFields(metadataBookmark(values)))
+}
+
+// metadataBookmark builds a bookmark holding metadata field values, to
+// populate the form
+func metadataBookmark(values map[string]string) *models.Bookmark {
+	return &models.Bookmark{
+		Title:       values["title"],
+		Description: sql.NullString{String: values["description"], Valid: values["description"] != ""},
+		CoverImage:  sql.NullString{String: values["cover_image"], Valid: values["cover_image"] != ""},
+		Favicon:     sql.NullString{String: values["favicon"], Valid: values["favicon"] != ""},
+	}
 }
 
 // AdminRefreshAllMetadata streams progress of metadata refresh using SSE
@@ -67,14 +135,29 @@ func (h *Handlers) AdminRefreshAllMetadata(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Create progress channel
+	// Create progress channel. The request context stops the refresh if
+	// the browser closes the connection.
+	ctx := r.Context()
 	progressChan := make(chan string, 10)
-	h.service.RefreshAllMissingMetadataAsync(progressChan)
+	h.service.RefreshAllMissingMetadataAsync(ctx, progressChan)
 
 	// Stream progress to client
-	for msg := range progressChan {
-		fmt.Fprintf(w, "data: %s\n\n", msg)
-		flusher.Flush()
+	for {
+		select {
+		case msg, ok := <-progressChan:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "data: %s\n\n", msg)
+			flusher.Flush()
+		case <-ctx.Done():
+			// Keep reading so the refresh can send its final message and exit
+			go func() {
+				for range progressChan {
+				}
+			}()
+			return
+		}
 	}
 }
 
@@ -159,6 +242,36 @@ func (h *Handlers) AdminToggleBookmarkFavorite(w http.ResponseWriter, r *http.Re
 	render(w, r, admin.BookmarkFavoriteStar(id, newStatus, true))
 }
 
+// AdminToggleBookmarkArchived archives or unarchives a bookmark
+func (h *Handlers) AdminToggleBookmarkArchived(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(r, "id")
+	if !ok {
+		http.Error(w, "Invalid bookmark ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	bookmark, err := h.service.GetBookmarkByID(ctx, id)
+	if err != nil {
+		http.Error(w, "Bookmark not found", http.StatusNotFound)
+		return
+	}
+
+	// Toggle the archived status
+	newStatus := !bookmark.IsArchived
+	err = h.service.UpdateBookmarkArchived(ctx, id, newStatus)
+	if err != nil {
+		http.Error(w, "Failed to update bookmark", http.StatusInternalServerError)
+		return
+	}
+
+	// Send HX-Trigger to update row data attributes for filtering
+	w.Header().Set("HX-Trigger", fmt.Sprintf(`{"updateRowData": {"id": %d, "isArchived": %t}}`, id, newStatus))
+
+	// Return the updated button with success animation
+	render(w, r, admin.BookmarkArchiveButton(id, newStatus, true))
+}
+
 // AdminUpdateBookmarkCollection updates the collection and position of a bookmark
 func (h *Handlers) AdminUpdateBookmarkCollection(w http.ResponseWriter, r *http.Request) {
 	id, ok := parsePathID(r, "id")
@@ -167,8 +280,8 @@ func (h *Handlers) AdminUpdateBookmarkCollection(w http.ResponseWriter, r *http.
 		return
 	}
 
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Invalid form data")
 		return
 	}
 
@@ -268,11 +381,67 @@ type BulkMoveRequest struct {
 	AfterBookmarkID *int64  `json:"after_id"`
 }
 
+// BulkMoveResponse is the JSON response for a bulk move. UndoToken can be
+// sent to AdminUndoBulkMove for a few minutes to reverse the move.
+type BulkMoveResponse struct {
+	UndoToken string `json:"undo_token"`
+}
+
+// UndoBulkMoveRequest represents the JSON request body for undoing a bulk move
+type UndoBulkMoveRequest struct {
+	Token string `json:"token"`
+}
+
 // BulkDeleteRequest represents the JSON request body for bulk delete
 type BulkDeleteRequest struct {
 	BookmarkIDs []int64 `json:"bookmark_ids"`
 }
 
+// BookmarkMarkdownResponse is the JSON response for AdminBookmarkMarkdown
+type BookmarkMarkdownResponse struct {
+	Markdown string `json:"markdown"`
+	URL      string `json:"url"`
+}
+
+// AdminBookmarkMarkdown returns a bookmark as a markdown link, and its URL,
+// for the copy buttons on the bookmark list
+func (h *Handlers) AdminBookmarkMarkdown(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(r, "id")
+	if !ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid bookmark ID")
+		return
+	}
+
+	bookmark, err := h.service.GetBookmarkByID(r.Context(), id)
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, "bookmark not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(BookmarkMarkdownResponse{
+		Markdown: markdownLink(bookmark.Title, bookmark.URL),
+		URL:      bookmark.URL,
+	})
+}
+
+// markdownLinkText escapes the characters that would end or break the text
+// of a markdown link
+var markdownLinkText = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`)
+
+// markdownLinkURL escapes the characters that would end a markdown link
+// destination early
+var markdownLinkURL = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29")
+
+// markdownLink formats [title](url), falling back to the URL as the text
+// for an untitled bookmark
+func markdownLink(title, url string) string {
+	if strings.TrimSpace(title) == "" {
+		title = url
+	}
+	return "[" + markdownLinkText.Replace(title) + "](" + markdownLinkURL.Replace(url) + ")"
+}
+
 // AdminBulkMoveBookmarks handles moving multiple bookmarks to a collection
 func (h *Handlers) AdminBulkMoveBookmarks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -293,14 +462,39 @@ func (h *Handlers) AdminBulkMoveBookmarks(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	err := h.service.BulkMoveBookmarks(ctx, req.BookmarkIDs, req.CollectionID, req.AfterBookmarkID)
+	token, err := h.service.BulkMoveBookmarks(ctx, req.BookmarkIDs, req.CollectionID, req.AfterBookmarkID)
 	if err != nil {
 		logger.Error(ctx, "failed to bulk move bookmarks", "error", err)
 		http.Error(w, "Failed to move bookmarks", http.StatusInternalServerError)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(BulkMoveResponse{UndoToken: token})
+}
+
+// AdminUndoBulkMove puts bookmarks moved by AdminBulkMoveBookmarks back
+// where they were
+func (h *Handlers) AdminUndoBulkMove(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req UndoBulkMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.UndoBulkMove(ctx, req.Token); err != nil {
+		if errors.Is(err, service.ErrInvalidUndoToken) {
+			http.Error(w, "This move can no longer be undone", http.StatusGone)
+			return
+		}
+		logger.Error(ctx, "failed to undo bulk move", "error", err)
+		http.Error(w, "Failed to undo move", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // AdminBulkDeleteBookmarks handles deleting multiple bookmarks
@@ -332,3 +526,70 @@ func (h *Handlers) AdminBulkDeleteBookmarks(w http.ResponseWriter, r *http.Reque
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// AdminBulkTagBookmarks adds a tag to, or with mode=remove takes it off, the
+// bookmarks selected in the table. Responds with the updated rows as
+// out-of-band swaps.
+func (h *Handlers) AdminBulkTagBookmarks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Invalid form data")
+		return
+	}
+
+	var bookmarkIDs []int64
+	for _, value := range r.Form["bookmark_ids"] {
+		if id, err := strconv.ParseInt(value, 10, 64); err == nil {
+			bookmarkIDs = append(bookmarkIDs, id)
+		}
+	}
+
+	if len(bookmarkIDs) == 0 {
+		http.Error(w, "No bookmarks specified", http.StatusBadRequest)
+		return
+	}
+
+	if len(bookmarkIDs) > 50 {
+		http.Error(w, "Too many bookmarks (max 50)", http.StatusBadRequest)
+		return
+	}
+
+	tagID := parseFormInt64(r, "tag_id")
+	if tagID == nil {
+		http.Error(w, "No tag specified", http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	if r.FormValue("mode") == "remove" {
+		err = h.service.BulkRemoveTag(ctx, bookmarkIDs, *tagID)
+	} else {
+		err = h.service.BulkAddTagToBookmarks(ctx, bookmarkIDs, *tagID)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Tag not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "failed to bulk tag bookmarks", "error", err)
+		http.Error(w, "Failed to tag bookmarks", http.StatusInternalServerError)
+		return
+	}
+
+	collections, err := h.service.ListCollections(ctx, false)
+	if err != nil {
+		logger.Error(ctx, "failed to load collections for bulk tag", "error", err)
+	}
+
+	bookmarks := make([]models.Bookmark, 0, len(bookmarkIDs))
+	for _, id := range bookmarkIDs {
+		bookmark, err := h.service.GetBookmarkByID(ctx, id)
+		if err != nil {
+			continue
+		}
+		bookmarks = append(bookmarks, *bookmark)
+	}
+
+	render(w, r, admin.BookmarkRowsOOB(bookmarks, collections))
+}
@@ -1,8 +1,9 @@
 package handlers
 
 import (
+	stderrors "errors"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/EC-9624/0xec.dev/internal/errors"
 	"github.com/EC-9624/0xec.dev/internal/models"
@@ -24,17 +25,22 @@ func (h *Handlers) bookmarksPerPage() int {
 
 // BookmarksIndex handles the bookmarks listing page (full page only)
 func (h *Handlers) BookmarksIndex(w http.ResponseWriter, r *http.Request) {
-	data, err := h.getBookmarksData(r, nil)
+	data, err := h.getBookmarksData(r, nil, false)
 	if err != nil {
 		errors.WriteInternalError(w, r, "Failed to load bookmarks", err)
 		return
 	}
-	render(w, r, pages.BookmarksIndex(data))
+	updated := make([]time.Time, 0, len(data.Bookmarks))
+	for _, b := range data.Bookmarks {
+		updated = append(updated, b.UpdatedAt)
+	}
+	setLastModified(w, updated...)
+	render(w, r, pages.BookmarksIndex(data, h.bookmarksMeta(nil)))
 }
 
 // HTMXBookmarksContent returns the bookmarks content partial + OOB sidebar update
 func (h *Handlers) HTMXBookmarksContent(w http.ResponseWriter, r *http.Request) {
-	data, err := h.getBookmarksData(r, nil)
+	data, err := h.getBookmarksData(r, nil, false)
 	if err != nil {
 		errors.WriteInternalError(w, r, "Failed to load bookmarks", err)
 		return
@@ -42,20 +48,15 @@ func (h *Handlers) HTMXBookmarksContent(w http.ResponseWriter, r *http.Request)
 	render(w, r, pages.BookmarksContentPartial(data))
 }
 
-// HTMXBookmarksMore returns only new bookmark items for infinite scroll (append)
+// HTMXBookmarksMore returns only new bookmark items for infinite scroll (append).
+// The cursor query parameter is the NextCursor of the previous page.
 func (h *Handlers) HTMXBookmarksMore(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	page := getPageParam(r)
-
 	// Extract collection slug if present
 	slug := r.PathValue("slug")
 
-	var collection *models.Collection
 	var collectionID *int64
-
 	if slug != "" {
-		var err error
-		collection, err = h.service.GetCollectionBySlug(ctx, slug)
+		collection, err := h.service.GetCollectionBySlug(r.Context(), slug)
 		if err != nil || !collection.IsPublic {
 			http.NotFound(w, r)
 			return
@@ -63,63 +64,89 @@ func (h *Handlers) HTMXBookmarksMore(w http.ResponseWriter, r *http.Request) {
 		collectionID = &collection.ID
 	}
 
-	perPage := h.bookmarksPerPage()
-	limit := perPage
-	offset := (page - 1) * perPage
+	opts := service.BookmarkListOptions{
+		PublicOnly:         true,
+		CollectionID:       collectionID,
+		IncludeDescendants: true,
+		Limit:              h.bookmarksPerPage(),
+		SortBy:             bookmarkSortParam(r),
+	}
+	h.writeBookmarksMore(w, r, opts, slug)
+}
 
+// HTMXBookmarksFavoritesMore is HTMXBookmarksMore for the favorites page
+func (h *Handlers) HTMXBookmarksFavoritesMore(w http.ResponseWriter, r *http.Request) {
 	opts := service.BookmarkListOptions{
-		PublicOnly:   true,
-		CollectionID: collectionID,
-		Limit:        limit,
-		Offset:       offset,
+		PublicOnly:    true,
+		FavoritesOnly: true,
+		Limit:         h.bookmarksPerPage(),
 	}
+	h.writeBookmarksMore(w, r, opts, components.FavoritesSlug)
+}
 
-	bookmarks, err := h.service.ListBookmarks(ctx, opts)
+// writeBookmarksMore renders the page of opts after ?cursor= for the
+// listing at slug. The reading list also reads ?month=, the month the
+// previous page ended in.
+func (h *Handlers) writeBookmarksMore(w http.ResponseWriter, r *http.Request, opts service.BookmarkListOptions, slug string) {
+	page, err := h.service.ListBookmarksCursor(r.Context(), opts, r.URL.Query().Get("cursor"))
 	if err != nil {
+		if stderrors.Is(err, service.ErrInvalidCursor) {
+			w.WriteHeader(http.StatusBadRequest)
+			render(w, r, components.InlineError("Invalid cursor"))
+			return
+		}
 		w.WriteHeader(http.StatusInternalServerError)
 		render(w, r, components.InlineError("Failed to load"))
 		return
 	}
 
-	countOpts := service.BookmarkListOptions{PublicOnly: true, CollectionID: collectionID}
-	total, _ := h.service.CountBookmarks(ctx, countOpts)
-	hasMore := (page * perPage) < total
+	if opts.SortBy == service.BookmarkSortAdded {
+		render(w, r, pages.BookmarkMonthsAppend(page.Bookmarks, slug, opts.SortBy, page.NextCursor, r.URL.Query().Get("month")))
+		return
+	}
+	render(w, r, pages.BookmarkGridAppend(page.Bookmarks, slug, opts.SortBy, page.NextCursor))
+}
 
-	render(w, r, pages.BookmarkGridAppend(bookmarks, collection, page, hasMore))
+// bookmarkSortParam reads the ?sort= of a public bookmarks listing,
+// falling back to the default order for a missing or unknown value
+func bookmarkSortParam(r *http.Request) service.BookmarkSort {
+	return service.ParseBookmarkSort(r.URL.Query().Get("sort"))
 }
 
-// getBookmarksData fetches all data needed for bookmarks pages
-func (h *Handlers) getBookmarksData(r *http.Request, collection *models.Collection) (templates.BookmarksData, error) {
+// getBookmarksData fetches all data needed for bookmarks pages. Only the
+// first page is rendered here; later pages come from HTMXBookmarksMore.
+// A collection page also lists the bookmarks of its public sub-collections.
+// favorites lists public favorites instead, in their default order.
+func (h *Handlers) getBookmarksData(r *http.Request, collection *models.Collection, favorites bool) (templates.BookmarksData, error) {
 	ctx := r.Context()
-	page := getPageParam(r)
 
 	var collectionID *int64
 	if collection != nil {
 		collectionID = &collection.ID
 	}
 
-	perPage := h.bookmarksPerPage()
-	limit := perPage
-	offset := (page - 1) * perPage
-
 	opts := service.BookmarkListOptions{
-		PublicOnly:   true,
-		CollectionID: collectionID,
-		Limit:        limit,
-		Offset:       offset,
+		PublicOnly:         true,
+		CollectionID:       collectionID,
+		IncludeDescendants: true,
+		FavoritesOnly:      favorites,
+		Limit:              h.bookmarksPerPage(),
+	}
+	if !favorites {
+		opts.SortBy = bookmarkSortParam(r)
 	}
 
-	bookmarks, err := h.service.ListBookmarks(ctx, opts)
+	page, err := h.service.ListBookmarksCursor(ctx, opts, "")
 	if err != nil {
 		return templates.BookmarksData{}, err
 	}
 
-	collections, err := h.service.ListCollections(ctx, true)
+	collections, err := h.service.ListCollectionTree(ctx, true)
 	if err != nil {
 		return templates.BookmarksData{}, err
 	}
 
-	countOpts := service.BookmarkListOptions{PublicOnly: true, CollectionID: collectionID}
+	countOpts := service.BookmarkListOptions{PublicOnly: true, CollectionID: collectionID, IncludeDescendants: true, FavoritesOnly: favorites}
 	total, err := h.service.CountBookmarks(ctx, countOpts)
 	if err != nil {
 		return templates.BookmarksData{}, err
@@ -132,16 +159,23 @@ func (h *Handlers) getBookmarksData(r *http.Request, collection *models.Collecti
 		return templates.BookmarksData{}, err
 	}
 
-	hasMore := (page * perPage) < total
+	// And the favorites count for the "Favorites" sidebar item
+	favoritesCountOpts := service.BookmarkListOptions{PublicOnly: true, FavoritesOnly: true}
+	totalFavorites, err := h.service.CountBookmarks(ctx, favoritesCountOpts)
+	if err != nil {
+		return templates.BookmarksData{}, err
+	}
 
 	return templates.BookmarksData{
-		Bookmarks:         bookmarks,
+		Bookmarks:         page.Bookmarks,
 		Collections:       collections,
 		ActiveCollection:  collection,
 		Total:             total,
 		TotalAllBookmarks: totalAllBookmarks,
-		Page:              page,
-		HasMore:           hasMore,
+		TotalFavorites:    totalFavorites,
+		FavoritesOnly:     favorites,
+		NextCursor:        page.NextCursor,
+		Sort:              opts.SortBy,
 	}, nil
 }
 
@@ -165,13 +199,13 @@ func (h *Handlers) BookmarksByCollection(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	data, err := h.getBookmarksData(r, collection)
+	data, err := h.getBookmarksData(r, collection, false)
 	if err != nil {
 		errors.WriteInternalError(w, r, "Failed to load bookmarks", err)
 		return
 	}
 
-	render(w, r, pages.BookmarksIndex(data))
+	render(w, r, pages.BookmarksIndex(data, h.bookmarksMeta(collection)))
 }
 
 // HTMXBookmarksCollectionContent returns the collection bookmarks content partial + OOB sidebar
@@ -194,7 +228,7 @@ func (h *Handlers) HTMXBookmarksCollectionContent(w http.ResponseWriter, r *http
 		return
 	}
 
-	data, err := h.getBookmarksData(r, collection)
+	data, err := h.getBookmarksData(r, collection, false)
 	if err != nil {
 		errors.WriteInternalError(w, r, "Failed to load bookmarks", err)
 		return
@@ -203,12 +237,50 @@ func (h *Handlers) HTMXBookmarksCollectionContent(w http.ResponseWriter, r *http
 	render(w, r, pages.BookmarksContentPartial(data))
 }
 
-// getPageParam extracts the page parameter from query string
-func getPageParam(r *http.Request) int {
-	pageStr := r.URL.Query().Get("page")
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		return 1
+// BookmarksFavorites handles the public favorites listing (full page only)
+func (h *Handlers) BookmarksFavorites(w http.ResponseWriter, r *http.Request) {
+	data, err := h.getBookmarksData(r, nil, true)
+	if err != nil {
+		errors.WriteInternalError(w, r, "Failed to load bookmarks", err)
+		return
+	}
+	render(w, r, pages.BookmarksIndex(data, h.favoritesMeta()))
+}
+
+// HTMXBookmarksFavoritesContent returns the favorites content partial + OOB sidebar
+func (h *Handlers) HTMXBookmarksFavoritesContent(w http.ResponseWriter, r *http.Request) {
+	data, err := h.getBookmarksData(r, nil, true)
+	if err != nil {
+		errors.WriteInternalError(w, r, "Failed to load bookmarks", err)
+		return
+	}
+	render(w, r, pages.BookmarksContentPartial(data))
+}
+
+// BookmarkRedirect counts a click on a public, unarchived bookmark and
+// redirects to its URL. Only the stored URL is ever used as the target,
+// so the endpoint can't be turned into an open redirect.
+func (h *Handlers) BookmarkRedirect(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(r, "id")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	bookmark, err := h.service.GetBookmarkByID(r.Context(), id)
+	if err != nil || !bookmark.IsPublic || bookmark.IsArchived || !h.isRedirectTarget(bookmark.URL) {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.service.IncrementBookmarkClick(r.Context(), bookmark.ID)
+	http.Redirect(w, r, bookmark.URL, http.StatusFound)
+}
+
+// isRedirectTarget reports whether BookmarkRedirect may send readers to rawURL
+func (h *Handlers) isRedirectTarget(rawURL string) bool {
+	if h.config.ValidateRedirects {
+		return models.IsPublicURL(rawURL)
 	}
-	return page
+	return models.IsValidURL(rawURL)
 }
@@ -3,14 +3,18 @@ package handlers
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/EC-9624/0xec.dev/internal/models"
 	"github.com/EC-9624/0xec.dev/internal/service"
+	"github.com/EC-9624/0xec.dev/web/templates/admin"
 )
 
 // helper to create a sql.NullString
@@ -49,6 +53,129 @@ func TestBookmarksIndex(t *testing.T) {
 	assertBodyContains(t, rec, "Test Site")
 }
 
+func TestBookmarksIndex_Sort(t *testing.T) {
+	tests := []struct {
+		query string
+		want  service.BookmarkSort
+	}{
+		{"", service.BookmarkSortRecent},
+		{"?sort=oldest", service.BookmarkSortOldest},
+		{"?sort=title", service.BookmarkSortTitle},
+		{"?sort=domain", service.BookmarkSortDomain},
+		{"?sort=added", service.BookmarkSortAdded},
+		{"?sort=bogus", service.BookmarkSortRecent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got service.BookmarkSort
+			mock := &mockService{
+				listBookmarksCursorFunc: func(ctx context.Context, opts service.BookmarkListOptions, cursor string) (*service.BookmarkPage, error) {
+					got = opts.SortBy
+					return &service.BookmarkPage{
+						Bookmarks:  []models.Bookmark{{ID: 1, URL: "https://example.com", Title: "Example", IsPublic: true}},
+						NextCursor: "next",
+					}, nil
+				},
+			}
+			h := newTestHandlers(mock)
+
+			req := httptest.NewRequest(http.MethodGet, "/bookmarks"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			h.BookmarksIndex(rec, req)
+
+			assertStatus(t, rec, http.StatusOK)
+			if got != tt.want {
+				t.Errorf("SortBy = %q, want %q", got, tt.want)
+			}
+			// The load-more link keeps the sort so later pages match
+			if tt.want != service.BookmarkSortRecent {
+				assertBodyContains(t, rec, "&amp;sort="+string(tt.want))
+			}
+		})
+	}
+}
+
+func TestBookmarksFavorites(t *testing.T) {
+	var listed service.BookmarkListOptions
+	var favoritesCounted bool
+	mock := &mockService{
+		listBookmarksCursorFunc: func(ctx context.Context, opts service.BookmarkListOptions, cursor string) (*service.BookmarkPage, error) {
+			listed = opts
+			return &service.BookmarkPage{
+				Bookmarks:  []models.Bookmark{{ID: 1, URL: "https://example.com", Title: "Example", IsPublic: true, IsFavorite: true}},
+				NextCursor: "next",
+			}, nil
+		},
+		countBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) (int, error) {
+			if opts.PublicOnly && opts.FavoritesOnly {
+				favoritesCounted = true
+				return 7, nil
+			}
+			return 10, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/bookmarks/favorites?sort=title", nil)
+	rec := httptest.NewRecorder()
+
+	h.BookmarksFavorites(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if !listed.FavoritesOnly || !listed.PublicOnly {
+		t.Errorf("listed with FavoritesOnly = %v, PublicOnly = %v; want both true", listed.FavoritesOnly, listed.PublicOnly)
+	}
+	if !favoritesCounted {
+		t.Error("favorites count was not loaded for the sidebar")
+	}
+	assertBodyContains(t, rec, "Favorites")
+	assertBodyContains(t, rec, "/htmx/bookmarks/more/favorites?cursor=next")
+	assertBodyContains(t, rec, "/bookmarks/favorites/feed.xml")
+}
+
+func TestBookmarksByCollection_Header(t *testing.T) {
+	collection := &models.Collection{
+		ID:          3,
+		Name:        "Go Reading",
+		Slug:        "go-reading",
+		Description: nullString("Articles worth rereading about Go."),
+		Color:       nullString("#3b82f6"),
+		CoverImage:  nullString("https://example.com/cover.jpg"),
+		IsPublic:    true,
+	}
+	mock := &mockService{
+		getCollectionBySlugFunc: func(ctx context.Context, slug string) (*models.Collection, error) {
+			if slug != collection.Slug {
+				return nil, sql.ErrNoRows
+			}
+			return collection, nil
+		},
+		listBookmarksCursorFunc: func(ctx context.Context, opts service.BookmarkListOptions, cursor string) (*service.BookmarkPage, error) {
+			return &service.BookmarkPage{
+				Bookmarks: []models.Bookmark{{ID: 1, URL: "https://go.dev", Title: "Go", IsPublic: true}},
+			}, nil
+		},
+		countBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) (int, error) {
+			return 12, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/bookmarks/go-reading", nil)
+	req.SetPathValue("slug", "go-reading")
+	rec := httptest.NewRecorder()
+
+	h.BookmarksByCollection(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, "Articles worth rereading about Go.")
+	assertBodyContains(t, rec, `src="https://example.com/cover.jpg"`)
+	assertBodyContains(t, rec, "border-color: #3b82f6")
+	assertBodyContains(t, rec, "12 bookmarks")
+}
+
 func TestBookmarksIndex_Empty(t *testing.T) {
 	mock := &mockService{
 		listBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
@@ -92,37 +219,63 @@ func TestHTMXBookmarksMore(t *testing.T) {
 		{ID: 3, URL: "https://page2.com", Title: "Page 2 Bookmark", IsPublic: true},
 	}
 
+	var gotCursor string
 	mock := &mockService{
-		listBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
-			return testBookmarks, nil
-		},
-		countBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) (int, error) {
-			return 15, nil // More than one page
+		listBookmarksCursorFunc: func(ctx context.Context, opts service.BookmarkListOptions, cursor string) (*service.BookmarkPage, error) {
+			gotCursor = cursor
+			return &service.BookmarkPage{Bookmarks: testBookmarks, NextCursor: "next-token"}, nil
 		},
 	}
 	h := newTestHandlers(mock)
 
-	req := httptest.NewRequest(http.MethodGet, "/htmx/bookmarks/more?page=2", nil)
+	req := httptest.NewRequest(http.MethodGet, "/htmx/bookmarks/more?cursor=abc", nil)
 	rec := httptest.NewRecorder()
 
 	h.HTMXBookmarksMore(rec, req)
 
 	assertStatus(t, rec, http.StatusOK)
 	assertBodyContains(t, rec, "Page 2 Bookmark")
+	if gotCursor != "abc" {
+		t.Errorf("cursor = %q, want %q", gotCursor, "abc")
+	}
 }
 
-func TestHTMXBookmarksMore_NoMore(t *testing.T) {
+func TestHTMXBookmarksMore_ReadingList(t *testing.T) {
+	testBookmarks := []models.Bookmark{
+		{ID: 3, URL: "https://a.example", Title: "Late November", IsPublic: true, CreatedAt: time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, URL: "https://b.example", Title: "October", IsPublic: true, CreatedAt: time.Date(2024, 10, 31, 0, 0, 0, 0, time.UTC)},
+	}
 	mock := &mockService{
-		listBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
-			return []models.Bookmark{}, nil
+		listBookmarksCursorFunc: func(ctx context.Context, opts service.BookmarkListOptions, cursor string) (*service.BookmarkPage, error) {
+			return &service.BookmarkPage{Bookmarks: testBookmarks, NextCursor: "next"}, nil
 		},
-		countBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) (int, error) {
-			return 5, nil // Less than items per page
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/htmx/bookmarks/more?cursor=abc&sort=added&month=2024-11", nil)
+	rec := httptest.NewRecorder()
+
+	h.HTMXBookmarksMore(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	// November continues the previous page's section; October starts a new one
+	assertBodyContains(t, rec, "last-child .bookmark-month-grid")
+	assertBodyContains(t, rec, "October 2024")
+	if strings.Contains(rec.Body.String(), "November 2024") {
+		t.Error("repeated the November heading from the previous page")
+	}
+	assertBodyContains(t, rec, "&amp;month=2024-10")
+}
+
+func TestHTMXBookmarksMore_NoMore(t *testing.T) {
+	mock := &mockService{
+		listBookmarksCursorFunc: func(ctx context.Context, opts service.BookmarkListOptions, cursor string) (*service.BookmarkPage, error) {
+			return &service.BookmarkPage{Bookmarks: []models.Bookmark{}}, nil
 		},
 	}
 	h := newTestHandlers(mock)
 
-	req := httptest.NewRequest(http.MethodGet, "/htmx/bookmarks/more?page=1", nil)
+	req := httptest.NewRequest(http.MethodGet, "/htmx/bookmarks/more?cursor=abc", nil)
 	rec := httptest.NewRecorder()
 
 	h.HTMXBookmarksMore(rec, req)
@@ -130,6 +283,22 @@ func TestHTMXBookmarksMore_NoMore(t *testing.T) {
 	assertStatus(t, rec, http.StatusOK)
 }
 
+func TestHTMXBookmarksMore_InvalidCursor(t *testing.T) {
+	mock := &mockService{
+		listBookmarksCursorFunc: func(ctx context.Context, opts service.BookmarkListOptions, cursor string) (*service.BookmarkPage, error) {
+			return nil, service.ErrInvalidCursor
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/htmx/bookmarks/more?cursor=garbage", nil)
+	rec := httptest.NewRecorder()
+
+	h.HTMXBookmarksMore(rec, req)
+
+	assertStatus(t, rec, http.StatusBadRequest)
+}
+
 func TestAdminBookmarksList(t *testing.T) {
 	testBookmarks := []models.Bookmark{
 		{ID: 1, URL: "https://example.com", Title: "Example", IsPublic: true},
@@ -198,6 +367,107 @@ func TestAdminBookmarksList(t *testing.T) {
 		assertStatus(t, rec, http.StatusOK)
 		assertBodyContains(t, rec, "Bookmarks")
 	})
+
+	t.Run("archived filter", func(t *testing.T) {
+		var gotOpts service.BookmarkListOptions
+		mock := &mockService{
+			getBoardViewDataFunc: func(ctx context.Context, recentLimit int) (*service.BoardViewData, error) {
+				t.Error("Archived filter should use the table view")
+				return &service.BoardViewData{}, nil
+			},
+			listBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
+				gotOpts = opts
+				return testBookmarks, nil
+			},
+		}
+		h := newTestHandlers(mock)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/bookmarks?archived=1", nil)
+		rec := httptest.NewRecorder()
+
+		h.AdminBookmarksList(rec, req)
+
+		assertStatus(t, rec, http.StatusOK)
+		if !gotOpts.ArchivedOnly {
+			t.Error("Archived filter should list archived bookmarks only")
+		}
+	})
+}
+
+func TestLoadAdminBookmarksTable_Pagination(t *testing.T) {
+	collectionID := int64(7)
+
+	tests := []struct {
+		name         string
+		target       string
+		collection   string
+		total        int
+		wantPage     int
+		wantOffset   int
+		wantMore     bool
+		wantUnsorted bool
+	}{
+		{"first page", "/admin/bookmarks?view=table", "", 25, 1, 0, true, false},
+		{"page 2", "/admin/bookmarks?view=table&page=2", "", 25, 2, 10, true, false},
+		{"last page", "/admin/bookmarks?view=table&page=3", "", 25, 3, 20, false, false},
+		{"past the end clamps", "/admin/bookmarks?view=table&page=9", "", 25, 3, 20, false, false},
+		{"collection page 2", "/admin/bookmarks?view=table&collection=7&page=2", "7", 15, 2, 10, false, false},
+		{"unsorted page 2", "/admin/bookmarks?view=table&collection=unsorted&page=2", "unsorted", 30, 2, 10, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOffset := -1
+			var gotCollection *int64
+			usedUnsorted := false
+			mock := &mockService{
+				countBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) (int, error) {
+					return tt.total, nil
+				},
+				countUnsortedBookmarksFunc: func(ctx context.Context) (int, error) {
+					return tt.total, nil
+				},
+				listBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
+					if opts.Limit != 10 {
+						t.Errorf("limit = %d, want 10", opts.Limit)
+					}
+					gotOffset, gotCollection = opts.Offset, opts.CollectionID
+					return nil, nil
+				},
+				listUnsortedBookmarksFunc: func(ctx context.Context, limit, offset int) ([]models.Bookmark, error) {
+					usedUnsorted, gotOffset = true, offset
+					return nil, nil
+				},
+				getCollectionByIDFunc: func(ctx context.Context, id int64) (*models.Collection, error) {
+					return &models.Collection{ID: id, Name: "Reading"}, nil
+				},
+			}
+			h := newTestHandlers(mock)
+			h.config.AdminBookmarksPerPage = 10
+
+			var data admin.BookmarksPageData
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if err := h.loadAdminBookmarksTable(req, &data, tt.collection); err != nil {
+				t.Fatal(err)
+			}
+
+			if gotOffset != tt.wantOffset {
+				t.Errorf("offset = %d, want %d", gotOffset, tt.wantOffset)
+			}
+			if data.Page.Page != tt.wantPage || data.Page.Total != tt.total {
+				t.Errorf("page = %+v, want page %d of %d items", data.Page, tt.wantPage, tt.total)
+			}
+			if data.Page.HasMore() != tt.wantMore {
+				t.Errorf("HasMore() = %v, want %v", data.Page.HasMore(), tt.wantMore)
+			}
+			if usedUnsorted != tt.wantUnsorted {
+				t.Errorf("used unsorted listing = %v, want %v", usedUnsorted, tt.wantUnsorted)
+			}
+			if tt.collection == "7" && (gotCollection == nil || *gotCollection != collectionID || data.FilteredCollection == nil) {
+				t.Errorf("collection filter not applied: %v, %+v", gotCollection, data.FilteredCollection)
+			}
+		})
+	}
 }
 
 func TestAdminBookmarkNew(t *testing.T) {
@@ -267,6 +537,44 @@ func TestAdminBookmarkEdit_NotFound(t *testing.T) {
 	assertStatus(t, rec, http.StatusNotFound)
 }
 
+func TestAdminBookmarkMarkdown(t *testing.T) {
+	mock := &mockService{
+		getBookmarkByIDFunc: func(ctx context.Context, id int64) (*models.Bookmark, error) {
+			if id == 1 {
+				return &models.Bookmark{ID: 1, URL: "https://en.wikipedia.org/wiki/Go_(game)", Title: `[RFC] a\b [draft]`}, nil
+			}
+			return nil, sql.ErrNoRows
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/bookmarks/1/markdown", nil)
+	req.SetPathValue("id", "1")
+	rec := httptest.NewRecorder()
+
+	h.AdminBookmarkMarkdown(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	var resp BookmarkMarkdownResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if want := `[\[RFC\] a\\b \[draft\]](https://en.wikipedia.org/wiki/Go_%28game%29)`; resp.Markdown != want {
+		t.Errorf("markdown = %q, want %q", resp.Markdown, want)
+	}
+	if resp.URL != "https://en.wikipedia.org/wiki/Go_(game)" {
+		t.Errorf("url = %q", resp.URL)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/bookmarks/2/markdown", nil)
+	req.SetPathValue("id", "2")
+	rec = httptest.NewRecorder()
+
+	h.AdminBookmarkMarkdown(rec, req)
+
+	assertStatus(t, rec, http.StatusNotFound)
+}
+
 func TestAdminBookmarkDelete(t *testing.T) {
 	deleted := false
 	mock := &mockService{
@@ -358,6 +666,40 @@ func TestAdminToggleBookmarkFavorite(t *testing.T) {
 	}
 }
 
+func TestAdminToggleBookmarkArchived(t *testing.T) {
+	currentArchived := true
+	mock := &mockService{
+		getBookmarkByIDFunc: func(ctx context.Context, id int64) (*models.Bookmark, error) {
+			return &models.Bookmark{
+				ID:         id,
+				IsArchived: currentArchived,
+				Title:      "Test",
+			}, nil
+		},
+		updateBookmarkArchivedFunc: func(ctx context.Context, id int64, archived bool) error {
+			currentArchived = archived
+			return nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/htmx/bookmarks/1/toggle-archived", nil)
+	req.SetPathValue("id", "1")
+	rec := httptest.NewRecorder()
+
+	h.AdminToggleBookmarkArchived(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+
+	// Should have toggled from true to false
+	if currentArchived != false {
+		t.Error("Archived status should be toggled to false")
+	}
+	if got := rec.Header().Get("HX-Trigger"); !strings.Contains(got, `"isArchived": false`) {
+		t.Errorf("HX-Trigger = %q, want isArchived false", got)
+	}
+}
+
 func TestAdminBookmarkFetchMetadata(t *testing.T) {
 	mock := &mockService{
 		fetchPageMetadataFunc: func(ctx context.Context, url string) (*service.PageMetadata, error) {
@@ -380,6 +722,65 @@ func TestAdminBookmarkFetchMetadata(t *testing.T) {
 	assertBodyContains(t, rec, "Fetched Title")
 }
 
+func TestAdminBookmarkFetchMetadata_ReviewsCuratedFields(t *testing.T) {
+	mock := &mockService{
+		fetchPageMetadataFunc: func(ctx context.Context, url string) (*service.PageMetadata, error) {
+			return &service.PageMetadata{
+				Title:       "Fetched Title",
+				Description: "Fetched description",
+			}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	form := url.Values{}
+	form.Set("url", "https://example.com")
+	form.Set("title", "Curated Title")
+	req := httptest.NewRequest(http.MethodPost, "/admin/htmx/bookmarks/fetch-metadata", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	h.AdminBookmarkFetchMetadata(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, "Curated Title")
+	assertBodyContains(t, rec, "Fetched Title")
+	body := rec.Body.String()
+	// The empty description is checked by default, the curated title isn't
+	if !strings.Contains(body, `value="description" checked`) {
+		t.Error("description should be checked by default")
+	}
+	if strings.Contains(body, `value="title" checked`) {
+		t.Error("curated title should not be checked by default")
+	}
+}
+
+func TestAdminBookmarkApplyMetadata(t *testing.T) {
+	h := newTestHandlers(&mockService{})
+
+	form := url.Values{}
+	form.Set("title", "Curated Title")
+	form.Set("fetched_title", "Fetched Title")
+	form.Set("description", "Curated description")
+	form.Set("fetched_description", "Fetched description")
+	form.Set("cover_image", "")
+	form.Set("fetched_cover_image", "https://example.com/cover.png")
+	form.Add("apply", "cover_image")
+	req := httptest.NewRequest(http.MethodPost, "/admin/htmx/bookmarks/apply-metadata", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	h.AdminBookmarkApplyMetadata(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, "Curated Title")
+	assertBodyContains(t, rec, "Curated description")
+	assertBodyContains(t, rec, "https://example.com/cover.png")
+	if body := rec.Body.String(); strings.Contains(body, "Fetched Title") || strings.Contains(body, "Fetched description") {
+		t.Error("unchecked fields were overwritten with fetched values")
+	}
+}
+
 func TestAdminBookmarkCreate_DuplicateURL(t *testing.T) {
 	mock := &mockService{
 		getBookmarkByURLFunc: func(ctx context.Context, url string) (*models.Bookmark, error) {
@@ -439,3 +840,304 @@ func TestAdminBookmarkCreate_Success(t *testing.T) {
 	// Should redirect to bookmarks list on success
 	assertRedirect(t, rec, "/admin/bookmarks")
 }
+
+func TestAdminBookmarkUpdate_CoverUpload(t *testing.T) {
+	existing := &models.Bookmark{ID: 3, URL: "https://example.com", Title: "Example"}
+	var storedFor int64
+	var updated bool
+
+	mock := &mockService{
+		getBookmarkByIDFunc: func(ctx context.Context, id int64) (*models.Bookmark, error) {
+			return existing, nil
+		},
+		storeBookmarkCoverFunc: func(ctx context.Context, bookmarkID int64, mimeType string, data []byte) (*models.Image, error) {
+			if !updated {
+				t.Error("cover should be stored after the bookmark is updated")
+			}
+			storedFor = bookmarkID
+			return &models.Image{ID: 12}, nil
+		},
+		updateBookmarkFunc: func(ctx context.Context, id int64, input models.UpdateBookmarkInput) (*models.Bookmark, error) {
+			updated = true
+			return existing, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := newMultipartRequest(t, "/admin/bookmarks/3", map[string]string{
+		"url":   "https://example.com",
+		"title": "Example",
+	}, testPNG)
+	req.SetPathValue("id", "3")
+	rec := httptest.NewRecorder()
+
+	h.AdminBookmarkUpdate(rec, req)
+
+	assertRedirect(t, rec, "/admin/bookmarks")
+	if storedFor != 3 {
+		t.Errorf("cover stored for bookmark %d, want 3", storedFor)
+	}
+	if !updated {
+		t.Error("expected bookmark to be updated")
+	}
+}
+
+func TestAdminBookmarkUpdate_CoverUploadNotImage(t *testing.T) {
+	mock := &mockService{
+		getBookmarkByIDFunc: func(ctx context.Context, id int64) (*models.Bookmark, error) {
+			return &models.Bookmark{ID: 3, URL: "https://example.com", Title: "Example"}, nil
+		},
+		storeBookmarkCoverFunc: func(ctx context.Context, bookmarkID int64, mimeType string, data []byte) (*models.Image, error) {
+			t.Error("a non-image file should not be stored")
+			return nil, nil
+		},
+		updateBookmarkFunc: func(ctx context.Context, id int64, input models.UpdateBookmarkInput) (*models.Bookmark, error) {
+			t.Error("bookmark should not be updated when the upload is rejected")
+			return nil, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := newMultipartRequest(t, "/admin/bookmarks/3", map[string]string{
+		"url":   "https://example.com",
+		"title": "Example",
+	}, []byte("<html>not an image</html>"))
+	req.SetPathValue("id", "3")
+	rec := httptest.NewRecorder()
+
+	h.AdminBookmarkUpdate(rec, req)
+
+	assertStatus(t, rec, http.StatusUnprocessableEntity)
+}
+
+func TestAdminBookmarkUpdate_CoverUploadTooLarge(t *testing.T) {
+	mock := &mockService{
+		getBookmarkByIDFunc: func(ctx context.Context, id int64) (*models.Bookmark, error) {
+			return &models.Bookmark{ID: 3, URL: "https://example.com", Title: "Example"}, nil
+		},
+		updateBookmarkFunc: func(ctx context.Context, id int64, input models.UpdateBookmarkInput) (*models.Bookmark, error) {
+			t.Error("bookmark should not be updated for an oversized request")
+			return nil, nil
+		},
+	}
+	h := newTestHandlers(mock)
+	h.config.MaxUploadBytes = 1 << 20
+
+	file := append(append([]byte{}, testPNG...), make([]byte, 2<<20)...)
+	req := newMultipartRequest(t, "/admin/bookmarks/3", map[string]string{
+		"url":   "https://example.com",
+		"title": "Example",
+	}, file)
+	req.SetPathValue("id", "3")
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+
+	h.AdminBookmarkUpdate(rec, req)
+
+	assertStatus(t, rec, http.StatusRequestEntityTooLarge)
+	assertBodyContains(t, rec, "The maximum size is 1 MB.")
+}
+
+func TestBookmarkRedirect(t *testing.T) {
+	bookmarks := map[int64]*models.Bookmark{
+		1: {ID: 1, URL: "https://example.com/article", IsPublic: true},
+		2: {ID: 2, URL: "https://example.com/private", IsPublic: false},
+		3: {ID: 3, URL: "javascript:alert(1)", IsPublic: true},
+		4: {ID: 4, URL: "http://127.0.0.1:8080/admin", IsPublic: true},
+		5: {ID: 5, URL: "https://example.com/archived", IsPublic: true, IsArchived: true},
+	}
+
+	tests := []struct {
+		name              string
+		id                string
+		validateRedirects bool
+		wantStatus        int
+		wantLocation      string
+	}{
+		{name: "public bookmark", id: "1", wantStatus: http.StatusFound, wantLocation: "https://example.com/article"},
+		{name: "private bookmark", id: "2", wantStatus: http.StatusNotFound},
+		{name: "archived bookmark", id: "5", wantStatus: http.StatusNotFound},
+		{name: "non-http URL", id: "3", wantStatus: http.StatusNotFound},
+		{name: "private host allowed", id: "4", wantStatus: http.StatusFound, wantLocation: "http://127.0.0.1:8080/admin"},
+		{name: "private host refused", id: "4", validateRedirects: true, wantStatus: http.StatusNotFound},
+		{name: "unknown ID", id: "99", wantStatus: http.StatusNotFound},
+		{name: "invalid ID", id: "abc", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var clicked []int64
+			mock := &mockService{
+				getBookmarkByIDFunc: func(ctx context.Context, id int64) (*models.Bookmark, error) {
+					if b, ok := bookmarks[id]; ok {
+						return b, nil
+					}
+					return nil, sql.ErrNoRows
+				},
+				incrementBookmarkClickFunc: func(ctx context.Context, id int64) {
+					clicked = append(clicked, id)
+				},
+			}
+			h := newTestHandlers(mock)
+			h.config.ValidateRedirects = tt.validateRedirects
+
+			req := httptest.NewRequest(http.MethodGet, "/go/"+tt.id, nil)
+			req.SetPathValue("id", tt.id)
+			rec := httptest.NewRecorder()
+
+			h.BookmarkRedirect(rec, req)
+
+			assertStatus(t, rec, tt.wantStatus)
+			if got := rec.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("Location = %q, want %q", got, tt.wantLocation)
+			}
+			if wantClick := tt.wantLocation != ""; (len(clicked) == 1) != wantClick {
+				t.Errorf("clicks recorded = %v, want click: %v", clicked, wantClick)
+			}
+		})
+	}
+}
+
+func TestAdminBulkTagBookmarks(t *testing.T) {
+	tests := []struct {
+		name       string
+		form       string
+		tagErr     error
+		wantStatus int
+		wantMode   string
+	}{
+		{name: "add", form: "bookmark_ids=1&bookmark_ids=2&tag_id=5", wantStatus: http.StatusOK, wantMode: "add"},
+		{name: "remove", form: "bookmark_ids=1&tag_id=5&mode=remove", wantStatus: http.StatusOK, wantMode: "remove"},
+		{name: "no bookmarks", form: "tag_id=5", wantStatus: http.StatusBadRequest},
+		{name: "no tag", form: "bookmark_ids=1", wantStatus: http.StatusBadRequest},
+		{name: "unknown tag", form: "bookmark_ids=1&tag_id=9", tagErr: sql.ErrNoRows, wantStatus: http.StatusNotFound, wantMode: "add"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mode string
+			var gotIDs []int64
+			mock := &mockService{
+				bulkAddTagToBookmarksFunc: func(ctx context.Context, bookmarkIDs []int64, tagID int64) error {
+					mode, gotIDs = "add", bookmarkIDs
+					return tt.tagErr
+				},
+				bulkRemoveTagFunc: func(ctx context.Context, bookmarkIDs []int64, tagID int64) error {
+					mode, gotIDs = "remove", bookmarkIDs
+					return tt.tagErr
+				},
+				getBookmarkByIDFunc: func(ctx context.Context, id int64) (*models.Bookmark, error) {
+					return &models.Bookmark{ID: id, URL: "https://example.com", Title: "Example"}, nil
+				},
+			}
+			h := newTestHandlers(mock)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/htmx/bookmarks/bulk-tag", strings.NewReader(tt.form))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+
+			h.AdminBulkTagBookmarks(rec, req)
+
+			assertStatus(t, rec, tt.wantStatus)
+			if mode != tt.wantMode {
+				t.Errorf("mode = %q, want %q", mode, tt.wantMode)
+			}
+			if tt.name == "add" && len(gotIDs) != 2 {
+				t.Errorf("bookmark IDs = %v, want [1 2]", gotIDs)
+			}
+		})
+	}
+}
+
+func TestAdminBulkMoveBookmarks_UndoToken(t *testing.T) {
+	mock := &mockService{
+		bulkMoveBookmarksFunc: func(ctx context.Context, bookmarkIDs []int64, collectionID *int64, afterBookmarkID *int64) (string, error) {
+			return "tok123", nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/htmx/bookmarks/bulk/move", strings.NewReader(`{"bookmark_ids":[1,2],"collection_id":3}`))
+	rec := httptest.NewRecorder()
+
+	h.AdminBulkMoveBookmarks(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, `"undo_token":"tok123"`)
+}
+
+func TestAdminUndoBulkMove(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		undoErr    error
+		wantStatus int
+		wantToken  string
+	}{
+		{name: "undone", body: `{"token":"tok123"}`, wantStatus: http.StatusNoContent, wantToken: "tok123"},
+		{name: "expired", body: `{"token":"old"}`, undoErr: service.ErrInvalidUndoToken, wantStatus: http.StatusGone, wantToken: "old"},
+		{name: "failure", body: `{"token":"tok123"}`, undoErr: errors.New("db down"), wantStatus: http.StatusInternalServerError, wantToken: "tok123"},
+		{name: "missing token", body: `{}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotToken string
+			mock := &mockService{
+				undoBulkMoveFunc: func(ctx context.Context, token string) error {
+					gotToken = token
+					return tt.undoErr
+				},
+			}
+			h := newTestHandlers(mock)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/htmx/bookmarks/bulk/move/undo", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			h.AdminUndoBulkMove(rec, req)
+
+			assertStatus(t, rec, tt.wantStatus)
+			if gotToken != tt.wantToken {
+				t.Errorf("undo token = %q, want %q", gotToken, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestAdminRefreshAllMetadata_ClientDisconnect(t *testing.T) {
+	stopped := make(chan struct{})
+	mock := &mockService{
+		refreshAllMissingMetadataAsyncFunc: func(ctx context.Context, progressChan chan<- string) {
+			go func() {
+				defer close(progressChan)
+				progressChan <- "start:3"
+				<-ctx.Done()
+				close(stopped)
+				progressChan <- "error:Refresh cancelled"
+			}()
+		},
+	}
+	h := newTestHandlers(mock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/admin/htmx/bookmarks/refresh-all", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	returned := make(chan struct{})
+	go func() {
+		h.AdminRefreshAllMetadata(rec, req)
+		close(returned)
+	}()
+	cancel()
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("handler did not return after the client disconnected")
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("refresh context was not cancelled")
+	}
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 	"net/http"
 
 	"github.com/EC-9624/0xec.dev/internal/logger"
 	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
 	"github.com/EC-9624/0xec.dev/web/templates/admin"
 	"github.com/EC-9624/0xec.dev/web/templates/components"
 )
@@ -14,8 +17,8 @@ import (
 func (h *Handlers) AdminCollectionCreate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Invalid form data")
 		return
 	}
 
@@ -26,6 +29,8 @@ func (h *Handlers) AdminCollectionCreate(w http.ResponseWriter, r *http.Request)
 		Slug:        r.FormValue("slug"),
 		Description: r.FormValue("description"),
 		Color:       r.FormValue("color"),
+		CoverImage:  r.FormValue("cover_image"),
+		ParentID:    parseFormInt64(r, "parent_id"),
 		IsPublic:    r.FormValue("is_public") == "true",
 	}
 
@@ -47,9 +52,9 @@ func (h *Handlers) AdminCollectionCreate(w http.ResponseWriter, r *http.Request)
 	if errors != nil && errors.HasErrors() {
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		if isDrawer {
-			render(w, r, admin.CollectionFormDrawer(nil, true, errors, &input))
+			render(w, r, admin.CollectionFormDrawer(nil, true, errors, &input, h.collectionParents(ctx)))
 		} else {
-			render(w, r, admin.CollectionForm(nil, true, errors, &input))
+			render(w, r, admin.CollectionForm(nil, true, errors, &input, h.collectionParents(ctx)))
 		}
 		return
 	}
@@ -61,9 +66,9 @@ func (h *Handlers) AdminCollectionCreate(w http.ResponseWriter, r *http.Request)
 		formErrors.General = "Failed to create collection. Please try again."
 		w.WriteHeader(http.StatusInternalServerError)
 		if isDrawer {
-			render(w, r, admin.CollectionFormDrawer(nil, true, formErrors, &input))
+			render(w, r, admin.CollectionFormDrawer(nil, true, formErrors, &input, h.collectionParents(ctx)))
 		} else {
-			render(w, r, admin.CollectionForm(nil, true, formErrors, &input))
+			render(w, r, admin.CollectionForm(nil, true, formErrors, &input, h.collectionParents(ctx)))
 		}
 		return
 	}
@@ -95,8 +100,8 @@ func (h *Handlers) AdminCollectionUpdate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Invalid form data")
 		return
 	}
 
@@ -107,6 +112,8 @@ func (h *Handlers) AdminCollectionUpdate(w http.ResponseWriter, r *http.Request)
 		Slug:        r.FormValue("slug"),
 		Description: r.FormValue("description"),
 		Color:       r.FormValue("color"),
+		CoverImage:  r.FormValue("cover_image"),
+		ParentID:    parseFormInt64(r, "parent_id"),
 		IsPublic:    r.FormValue("is_public") == "true",
 	}
 
@@ -132,34 +139,44 @@ func (h *Handlers) AdminCollectionUpdate(w http.ResponseWriter, r *http.Request)
 			Slug:        input.Slug,
 			Description: input.Description,
 			Color:       input.Color,
+			CoverImage:  input.CoverImage,
+			ParentID:    input.ParentID,
 			IsPublic:    input.IsPublic,
 		}
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		if isDrawer {
-			render(w, r, admin.CollectionFormDrawer(collection, false, errors, formInput))
+			render(w, r, admin.CollectionFormDrawer(collection, false, errors, formInput, h.collectionParents(ctx)))
 		} else {
-			render(w, r, admin.CollectionForm(collection, false, errors, formInput))
+			render(w, r, admin.CollectionForm(collection, false, errors, formInput, h.collectionParents(ctx)))
 		}
 		return
 	}
 
 	_, err = h.service.UpdateCollection(ctx, id, input)
 	if err != nil {
-		logger.Error(ctx, "failed to update collection", "error", err, "id", id)
 		formErrors := models.NewFormErrors()
-		formErrors.General = "Failed to update collection. Please try again."
+		status := http.StatusInternalServerError
+		if stderrors.Is(err, service.ErrCollectionCycle) {
+			formErrors.AddField("parent_id", "A collection can't be nested inside itself or its sub-collections")
+			status = http.StatusUnprocessableEntity
+		} else {
+			logger.Error(ctx, "failed to update collection", "error", err, "id", id)
+			formErrors.General = "Failed to update collection. Please try again."
+		}
 		formInput := &models.CreateCollectionInput{
 			Name:        input.Name,
 			Slug:        input.Slug,
 			Description: input.Description,
 			Color:       input.Color,
+			CoverImage:  input.CoverImage,
+			ParentID:    input.ParentID,
 			IsPublic:    input.IsPublic,
 		}
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(status)
 		if isDrawer {
-			render(w, r, admin.CollectionFormDrawer(collection, false, formErrors, formInput))
+			render(w, r, admin.CollectionFormDrawer(collection, false, formErrors, formInput, h.collectionParents(ctx)))
 		} else {
-			render(w, r, admin.CollectionForm(collection, false, formErrors, formInput))
+			render(w, r, admin.CollectionForm(collection, false, formErrors, formInput, h.collectionParents(ctx)))
 		}
 		return
 	}
@@ -255,13 +272,49 @@ func (h *Handlers) AdminToggleCollectionPublic(w http.ResponseWriter, r *http.Re
 	render(w, r, admin.CollectionPublicBadge(id, newIsPublic, true))
 }
 
+// AdminMoveCollection moves a collection after another one in the board and sidebar order
+func (h *Handlers) AdminMoveCollection(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(r, "id")
+	if !ok {
+		http.Error(w, "Invalid collection ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Invalid form data")
+		return
+	}
+
+	ctx := r.Context()
+
+	// Parse after_id for position (empty string means insert at beginning)
+	afterCollectionID := parseFormInt64(r, "after_id")
+
+	if err := h.service.MoveCollection(ctx, id, afterCollectionID); err != nil {
+		logger.Error(ctx, "failed to move collection", "error", err, "collection_id", id)
+		http.Error(w, "Failed to move collection", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// collectionParents loads the collection tree for the parent picker
+func (h *Handlers) collectionParents(ctx context.Context) []service.CollectionNode {
+	tree, err := h.service.ListCollectionTree(ctx, false)
+	if err != nil {
+		logger.Error(ctx, "failed to load collections for parent picker", "error", err)
+	}
+	return tree
+}
+
 // ============================================
 // HTMX PARTIAL HANDLERS (DRAWER)
 // ============================================
 
 // HTMXAdminCollectionNewDrawer returns the new collection form for the drawer
 func (h *Handlers) HTMXAdminCollectionNewDrawer(w http.ResponseWriter, r *http.Request) {
-	render(w, r, admin.CollectionFormDrawer(nil, true, nil, nil))
+	render(w, r, admin.CollectionFormDrawer(nil, true, nil, nil, h.collectionParents(r.Context())))
 }
 
 // HTMXAdminCollectionEditDrawer returns the edit collection form for the drawer
@@ -278,5 +331,5 @@ func (h *Handlers) HTMXAdminCollectionEditDrawer(w http.ResponseWriter, r *http.
 		return
 	}
 
-	render(w, r, admin.CollectionFormDrawer(collection, false, nil, nil))
+	render(w, r, admin.CollectionFormDrawer(collection, false, nil, nil, h.collectionParents(r.Context())))
 }
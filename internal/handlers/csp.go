@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/EC-9624/0xec.dev/internal/logger"
+)
+
+// maxCSPReportSize caps how much of a violation report is read
+const maxCSPReportSize = 64 << 10
+
+// cspViolation holds the fields of a CSP violation report worth logging.
+// Browsers send either the legacy report-uri format (hyphenated keys under
+// "csp-report") or the Reporting API format (camelCase keys under "body").
+type cspViolation struct {
+	DocumentURI        string `json:"document-uri"`
+	BlockedURI         string `json:"blocked-uri"`
+	ViolatedDirective  string `json:"violated-directive"`
+	EffectiveDirective string `json:"effective-directive"`
+	Disposition        string `json:"disposition"`
+	SourceFile         string `json:"source-file"`
+	LineNumber         int    `json:"line-number"`
+}
+
+type reportingAPIViolation struct {
+	DocumentURL        string `json:"documentURL"`
+	BlockedURL         string `json:"blockedURL"`
+	EffectiveDirective string `json:"effectiveDirective"`
+	Disposition        string `json:"disposition"`
+	SourceFile         string `json:"sourceFile"`
+	LineNumber         int    `json:"lineNumber"`
+}
+
+// CSPReport logs Content Security Policy violation reports sent by
+// browsers. It always answers 204 so a bad report can't be retried.
+func (h *Handlers) CSPReport(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxCSPReportSize))
+	if err != nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	for _, v := range parseCSPReports(data) {
+		logger.Warn(r.Context(), "csp violation",
+			"document_uri", v.DocumentURI,
+			"blocked_uri", v.BlockedURI,
+			"violated_directive", v.ViolatedDirective,
+			"effective_directive", v.EffectiveDirective,
+			"disposition", v.Disposition,
+			"source_file", v.SourceFile,
+			"line", v.LineNumber,
+			"user_agent", r.UserAgent(),
+		)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseCSPReports decodes either report format, skipping anything that
+// isn't a CSP violation
+func parseCSPReports(data []byte) []cspViolation {
+	var legacy struct {
+		Report *cspViolation `json:"csp-report"`
+	}
+	if err := json.Unmarshal(data, &legacy); err == nil && legacy.Report != nil {
+		return []cspViolation{*legacy.Report}
+	}
+
+	var reports []struct {
+		Type string                `json:"type"`
+		Body reportingAPIViolation `json:"body"`
+	}
+	if err := json.Unmarshal(data, &reports); err != nil {
+		return nil
+	}
+	var violations []cspViolation
+	for _, report := range reports {
+		if report.Type != "csp-violation" {
+			continue
+		}
+		violations = append(violations, cspViolation{
+			DocumentURI:        report.Body.DocumentURL,
+			BlockedURI:         report.Body.BlockedURL,
+			EffectiveDirective: report.Body.EffectiveDirective,
+			Disposition:        report.Body.Disposition,
+			SourceFile:         report.Body.SourceFile,
+			LineNumber:         report.Body.LineNumber,
+		})
+	}
+	return violations
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseCSPReports(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		blocked []string
+	}{
+		{
+			name:    "legacy report-uri",
+			body:    `{"csp-report":{"document-uri":"https://example.com/","blocked-uri":"https://evil.example/x.js","violated-directive":"script-src"}}`,
+			blocked: []string{"https://evil.example/x.js"},
+		},
+		{
+			name: "reporting API",
+			body: `[{"type":"csp-violation","body":{"documentURL":"https://example.com/","blockedURL":"https://img.example/a.png","effectiveDirective":"img-src"}},
+				{"type":"deprecation","body":{}}]`,
+			blocked: []string{"https://img.example/a.png"},
+		},
+		{name: "garbage", body: "not json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseCSPReports([]byte(tt.body))
+			if len(got) != len(tt.blocked) {
+				t.Fatalf("got %d violations, want %d", len(got), len(tt.blocked))
+			}
+			for i, v := range got {
+				if v.BlockedURI != tt.blocked[i] {
+					t.Errorf("blocked = %q, want %q", v.BlockedURI, tt.blocked[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCSPReport(t *testing.T) {
+	h := newTestHandlers(&mockService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/csp-report", strings.NewReader(`{"csp-report":{"blocked-uri":"inline"}}`))
+	req.Header.Set("Content-Type", "application/csp-report")
+	rec := httptest.NewRecorder()
+
+	h.CSPReport(rec, req)
+
+	assertStatus(t, rec, http.StatusNoContent)
+}
@@ -0,0 +1,49 @@
+//go:build dev
+
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/EC-9624/0xec.dev/internal/middleware"
+	"github.com/EC-9624/0xec.dev/internal/models"
+
+	"github.com/a-h/templ"
+)
+
+// debugParam is the query parameter that enables the template data dump
+const debugParam = "__debug"
+
+// renderPage renders a full admin page. In development, authenticated
+// requests with ?__debug=1 also get the data the template received appended
+// as a JSON HTML comment. Outside development this is identical to render,
+// and builds without the dev tag leave the dump out entirely.
+func (h *Handlers) renderPage(w http.ResponseWriter, r *http.Request, component templ.Component, data any) {
+	render(w, r, component)
+	if h.wantsDebugDump(r) {
+		writeDebugDump(w, data)
+	}
+}
+
+// wantsDebugDump reports whether the template data dump should be written
+func (h *Handlers) wantsDebugDump(r *http.Request) bool {
+	if !h.config.IsDevelopment() || r.URL.Query().Get(debugParam) != "1" {
+		return false
+	}
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	return ok && user != nil
+}
+
+// writeDebugDump writes data as indented JSON inside an HTML comment
+func writeDebugDump(w io.Writer, data any) {
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		out = []byte(`"failed to encode template data: ` + err.Error() + `"`)
+	}
+	// "--" can't appear inside an HTML comment, so keep the JSON from closing it early
+	dump := strings.ReplaceAll(string(out), "--", "-\\u002d")
+	io.WriteString(w, "\n<!-- template data\n"+dump+"\n-->\n")
+}
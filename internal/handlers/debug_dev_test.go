@@ -0,0 +1,69 @@
+//go:build dev
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/middleware"
+	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
+)
+
+func TestTemplateDebugDump(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		query       string
+		authed      bool
+		wantDump    bool
+	}{
+		{"development with debug param", "development", "?__debug=1", true, true},
+		{"development without debug param", "development", "", true, false},
+		{"development unauthenticated", "development", "?__debug=1", false, false},
+		{"production with debug param", "production", "?__debug=1", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockService{
+				getDashboardStatsFunc: func(ctx context.Context) (*service.DashboardStats, error) {
+					return &service.DashboardStats{TotalPosts: 7}, nil
+				},
+			}
+			h := newTestHandlers(mock)
+			h.config.Environment = tt.environment
+
+			req := httptest.NewRequest(http.MethodGet, "/admin"+tt.query, nil)
+			if tt.authed {
+				user := &models.User{ID: 1, Username: "admin"}
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, user))
+			}
+			rec := httptest.NewRecorder()
+
+			h.AdminDashboard(rec, req)
+
+			assertStatus(t, rec, http.StatusOK)
+			if tt.wantDump {
+				assertBodyContains(t, rec, "<!-- template data")
+				assertBodyContains(t, rec, `"TotalPosts": 7`)
+			} else {
+				assertBodyNotContains(t, rec, "<!-- template data")
+			}
+		})
+	}
+}
+
+func TestWriteDebugDump_EscapesCommentTerminator(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeDebugDump(rec, map[string]string{"note": "ends here -->"})
+
+	if body := rec.Body.String(); !strings.HasSuffix(body, "\n-->\n") {
+		t.Fatalf("dump should end with the comment terminator, got %q", body)
+	}
+	assertBodyNotContains(t, rec, "here -->")
+}
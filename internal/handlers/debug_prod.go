@@ -0,0 +1,15 @@
+//go:build !dev
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/a-h/templ"
+)
+
+// renderPage renders a full admin page. The template data dump is only
+// compiled into dev builds, so data is unused here.
+func (h *Handlers) renderPage(w http.ResponseWriter, r *http.Request, component templ.Component, data any) {
+	render(w, r, component)
+}
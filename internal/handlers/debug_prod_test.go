@@ -0,0 +1,34 @@
+//go:build !dev
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/middleware"
+	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
+)
+
+func TestTemplateDebugDump_NotInProductionBuilds(t *testing.T) {
+	mock := &mockService{
+		getDashboardStatsFunc: func(ctx context.Context) (*service.DashboardStats, error) {
+			return &service.DashboardStats{TotalPosts: 7}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+	h.config.Environment = "development"
+
+	req := httptest.NewRequest(http.MethodGet, "/admin?__debug=1", nil)
+	user := &models.User{ID: 1, Username: "admin"}
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, user))
+	rec := httptest.NewRecorder()
+
+	h.AdminDashboard(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyNotContains(t, rec, "<!-- template data")
+}
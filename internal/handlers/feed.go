@@ -1,11 +1,28 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
 	"encoding/xml"
+	"html"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/EC-9624/0xec.dev/internal/errors"
+	"github.com/EC-9624/0xec.dev/internal/models"
 	"github.com/EC-9624/0xec.dev/internal/service"
+	"github.com/EC-9624/0xec.dev/web/templates/components"
+)
+
+const (
+	// postsFeedLimit is the default number of entries in post feeds
+	postsFeedLimit = 20
+
+	// bookmarksFeedLimit is the default number of entries in bookmark feeds
+	bookmarksFeedLimit = 50
 )
 
 // RSS feed structures
@@ -29,77 +46,414 @@ type RSSItem struct {
 	Description string `xml:"description"`
 	PubDate     string `xml:"pubDate"`
 	GUID        string `xml:"guid"`
+
+	Content *RSSContent `xml:"http://purl.org/rss/1.0/modules/content/ encoded,omitempty"`
 }
 
-// PostsFeed generates RSS feed for posts
-func (h *Handlers) PostsFeed(w http.ResponseWriter, r *http.Request) {
-	posts, err := h.service.ListPosts(r.Context(), true, 20, 0)
+// RSSContent is the full HTML body of an item (content:encoded)
+type RSSContent struct {
+	Body string `xml:",cdata"`
+}
+
+// Atom feed structures
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  AtomAuthor  `xml:"author"`
+	Links   []AtomLink  `xml:"link"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+type AtomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type AtomEntry struct {
+	Title     string       `xml:"title"`
+	ID        string       `xml:"id"`
+	Updated   string       `xml:"updated"`
+	Published string       `xml:"published"`
+	Link      AtomLink     `xml:"link"`
+	Summary   *AtomSummary `xml:"summary,omitempty"`
+	Content   *AtomSummary `xml:"content,omitempty"`
+}
+
+type AtomSummary struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// JSON Feed structures (https://www.jsonfeed.org/version/1.1/)
+type JSONFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Items       []JSONFeedItem `json:"items"`
+}
+
+type JSONFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentHTML   string   `json:"content_html"`
+	Summary       string   `json:"summary,omitempty"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// feed is the format-agnostic representation shared by the RSS and Atom handlers
+type feed struct {
+	Title       string
+	Path        string // site-relative path of the HTML page the feed mirrors
+	Description string
+	Items       []feedItem
+}
+
+// feedItem is a single entry in a feed
+type feedItem struct {
+	Title       string
+	Link        string
+	Path        string // site-relative path used to build the entry's tag URI
+	Description string
+	Content     string // optional HTML body; omitted from the feed when empty
+	Published   time.Time
+	Updated     time.Time
+}
+
+// feedLimit returns the configured number of feed entries, or
+// defaultLimit when none is set
+func (h *Handlers) feedLimit(defaultLimit int) int {
+	if h.config.RSSItemLimit > 0 {
+		return h.config.RSSItemLimit
+	}
+	return defaultLimit
+}
+
+// postsFeed loads the latest published posts as a feed
+func (h *Handlers) postsFeed(ctx context.Context) (*feed, error) {
+	posts, err := h.service.ListPosts(ctx, true, h.feedLimit(postsFeedLimit), 0)
 	if err != nil {
-		http.Error(w, "Failed to load posts", http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 
-	var items []RSSItem
+	items := make([]feedItem, 0, len(posts))
 	for _, post := range posts {
-		pubDate := post.CreatedAt
-		if post.PublishedAt.Valid {
-			pubDate = post.PublishedAt.Time
-		}
-
-		items = append(items, RSSItem{
+		pubDate, updated := postFeedDates(post)
+		items = append(items, feedItem{
 			Title:       post.Title,
 			Link:        h.config.BaseURL + "/posts/" + post.Slug,
+			Path:        "/posts/" + post.Slug,
 			Description: post.GetExcerpt(),
-			PubDate:     pubDate.Format(time.RFC1123Z),
-			GUID:        h.config.BaseURL + "/posts/" + post.Slug,
+			Content:     h.postFeedContent(post),
+			Published:   pubDate,
+			Updated:     updated,
 		})
 	}
 
-	rss := RSS{
-		Version: "2.0",
-		Channel: RSSChannel{
-			Title:         "Posts",
-			Link:          h.config.BaseURL + "/posts",
-			Description:   "Latest posts",
-			LastBuildDate: time.Now().Format(time.RFC1123Z),
-			Items:         items,
-		},
+	return &feed{
+		Title:       "Posts",
+		Path:        "/posts",
+		Description: "Latest posts",
+		Items:       items,
+	}, nil
+}
+
+// postFeedDates returns when a post was published and last updated for
+// feeds. Drafts published later keep their publish date; an update never
+// predates publication.
+func postFeedDates(post models.Post) (published, updated time.Time) {
+	published = post.CreatedAt
+	if post.PublishedAt.Valid {
+		published = post.PublishedAt.Time
 	}
+	updated = post.UpdatedAt
+	if updated.Before(published) {
+		updated = published
+	}
+	return published, updated
+}
 
-	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
-	w.Write([]byte(xml.Header))
-	xml.NewEncoder(w).Encode(rss)
+// postFeedContent builds the HTML body of a post's feed entry: the full
+// rendered post when full content is enabled, else the excerpt, followed by
+// links to the post's tag archives. In summary mode it returns empty string
+// when tag links are disabled or the post has no tags, leaving the entry
+// with just its plain-text description.
+func (h *Handlers) postFeedContent(post models.Post) string {
+	tagLinks := h.config.FeedTagLinks && len(post.Tags) > 0
+	if !h.config.RSSFullContent && !tagLinks {
+		return ""
+	}
+
+	var b strings.Builder
+	if h.config.RSSFullContent {
+		b.WriteString(markdownToHTML(post.Content, post.Slug))
+	} else if excerpt := post.GetExcerpt(); excerpt != "" {
+		b.WriteString("<p>" + html.EscapeString(excerpt) + "</p>\n")
+	}
+	if !tagLinks {
+		return b.String()
+	}
+
+	b.WriteString("<p>Tagged: ")
+	for i, tag := range post.Tags {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		href := h.config.BaseURL + "/tags/" + url.PathEscape(tag.Slug)
+		b.WriteString(`<a href="` + html.EscapeString(href) + `">` + html.EscapeString(tag.Name) + "</a>")
+	}
+	b.WriteString("</p>")
+	return b.String()
 }
 
-// BookmarksFeed generates RSS feed for bookmarks
-func (h *Handlers) BookmarksFeed(w http.ResponseWriter, r *http.Request) {
-	bookmarks, err := h.service.ListBookmarks(r.Context(), service.BookmarkListOptions{
+// bookmarksFeed loads the latest public bookmarks as a feed
+func (h *Handlers) bookmarksFeed(ctx context.Context) (*feed, error) {
+	bookmarks, err := h.service.ListBookmarks(ctx, service.BookmarkListOptions{
 		PublicOnly: true,
-		Limit:      50,
+		Limit:      h.feedLimit(bookmarksFeedLimit),
 		Offset:     0,
 	})
 	if err != nil {
-		http.Error(w, "Failed to load bookmarks", http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 
-	var items []RSSItem
+	return &feed{
+		Title:       "Bookmarks",
+		Path:        "/bookmarks",
+		Description: "Latest bookmarks",
+		Items:       h.bookmarkFeedItems(bookmarks),
+	}, nil
+}
+
+// bookmarkFeedItems converts bookmarks to feed items
+func (h *Handlers) bookmarkFeedItems(bookmarks []models.Bookmark) []feedItem {
+	items := make([]feedItem, 0, len(bookmarks))
 	for _, bookmark := range bookmarks {
-		items = append(items, RSSItem{
+		updated := bookmark.UpdatedAt
+		if updated.Before(bookmark.CreatedAt) {
+			updated = bookmark.CreatedAt
+		}
+
+		items = append(items, feedItem{
 			Title:       bookmark.Title,
 			Link:        bookmark.URL,
+			Path:        "/bookmarks/" + strconv.FormatInt(bookmark.ID, 10),
 			Description: bookmark.GetDescription(),
-			PubDate:     bookmark.CreatedAt.Format(time.RFC1123Z),
-			GUID:        bookmark.URL,
+			Content:     h.bookmarkFeedContent(&bookmark),
+			Published:   bookmark.CreatedAt,
+			Updated:     updated,
+		})
+	}
+	return items
+}
+
+// bookmarkFeedContent builds the HTML body of a bookmark's feed entry when
+// full content is enabled: its cover image, description and a link to the
+// original page. The personal note is admin only and never included.
+func (h *Handlers) bookmarkFeedContent(bookmark *models.Bookmark) string {
+	if !h.config.RSSFullContent {
+		return ""
+	}
+
+	var b strings.Builder
+	if cover := bookmark.CoverImageURL(h.config.ImageBaseURL); cover != "" {
+		b.WriteString(`<p><img src="` + html.EscapeString(h.absoluteURL(cover)) + `" alt=""></p>` + "\n")
+	}
+	if description := bookmark.GetDescription(); description != "" {
+		b.WriteString("<p>" + html.EscapeString(description) + "</p>\n")
+	}
+	label := bookmark.GetDomain()
+	if label == "" {
+		label = bookmark.URL
+	}
+	b.WriteString(`<p><a href="` + html.EscapeString(bookmark.URL) + `">` + html.EscapeString(label) + "</a></p>")
+	return b.String()
+}
+
+// collectionFeed loads the newest public bookmarks in a collection and its
+// sub-collections as a feed
+func (h *Handlers) collectionFeed(ctx context.Context, collection *models.Collection) (*feed, error) {
+	// Collection listings default to sort_order; feeds want creation order
+	bookmarks, err := h.service.ListBookmarks(ctx, service.BookmarkListOptions{
+		PublicOnly:         true,
+		CollectionID:       &collection.ID,
+		IncludeDescendants: true,
+		SortBy:             service.BookmarkSortAdded,
+		Limit:              h.feedLimit(bookmarksFeedLimit),
+		Offset:             0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &feed{
+		Title:       collection.Name + " bookmarks",
+		Path:        "/bookmarks/" + collection.Slug,
+		Description: "Latest bookmarks in " + collection.Name,
+		Items:       h.bookmarkFeedItems(bookmarks),
+	}, nil
+}
+
+// favoritesFeed loads the newest public favorites as a feed
+func (h *Handlers) favoritesFeed(ctx context.Context) (*feed, error) {
+	bookmarks, err := h.service.ListBookmarks(ctx, service.BookmarkListOptions{
+		PublicOnly:    true,
+		FavoritesOnly: true,
+		SortBy:        service.BookmarkSortAdded,
+		Limit:         h.feedLimit(bookmarksFeedLimit),
+		Offset:        0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &feed{
+		Title:       "Favorite bookmarks",
+		Path:        "/bookmarks/" + components.FavoritesSlug,
+		Description: "Latest favorite bookmarks",
+		Items:       h.bookmarkFeedItems(bookmarks),
+	}, nil
+}
+
+// PostsFeed generates RSS feed for posts
+func (h *Handlers) PostsFeed(w http.ResponseWriter, r *http.Request) {
+	f, err := h.postsFeed(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load posts", http.StatusInternalServerError)
+		return
+	}
+	h.writeRSS(w, f)
+}
+
+// PostsJSONFeed generates JSON Feed for posts, with each post's full
+// rendered HTML as its content
+func (h *Handlers) PostsJSONFeed(w http.ResponseWriter, r *http.Request) {
+	posts, err := h.service.ListPosts(r.Context(), true, h.feedLimit(postsFeedLimit), 0)
+	if err != nil {
+		http.Error(w, "Failed to load posts", http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]JSONFeedItem, 0, len(posts))
+	for _, post := range posts {
+		published, updated := postFeedDates(post)
+		link := h.config.BaseURL + "/posts/" + post.Slug
+
+		item := JSONFeedItem{
+			ID:            link,
+			URL:           link,
+			Title:         post.Title,
+			ContentHTML:   markdownToHTML(post.Content, post.Slug),
+			Summary:       post.GetExcerpt(),
+			DatePublished: published.UTC().Format(time.RFC3339),
+			DateModified:  updated.UTC().Format(time.RFC3339),
+		}
+		for _, tag := range post.Tags {
+			item.Tags = append(item.Tags, tag.Name)
+		}
+		items = append(items, item)
+	}
+
+	jsonFeed := JSONFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       "Posts",
+		HomePageURL: h.config.BaseURL + "/posts",
+		FeedURL:     h.config.BaseURL + r.URL.Path,
+		Description: "Latest posts",
+		Items:       items,
+	}
+
+	w.Header().Set("Content-Type", "application/feed+json; charset=utf-8")
+	json.NewEncoder(w).Encode(jsonFeed)
+}
+
+// BookmarksCollectionFeed generates RSS feed for a single public collection
+func (h *Handlers) BookmarksCollectionFeed(w http.ResponseWriter, r *http.Request) {
+	collection, err := h.service.GetCollectionBySlug(r.Context(), r.PathValue("slug"))
+	if err != nil || !collection.IsPublic {
+		errors.WriteNotFound(w, r, "Collection")
+		return
+	}
+
+	f, err := h.collectionFeed(r.Context(), collection)
+	if err != nil {
+		http.Error(w, "Failed to load bookmarks", http.StatusInternalServerError)
+		return
+	}
+	h.writeRSS(w, f)
+}
+
+// BookmarksFavoritesFeed generates RSS feed for public favorites
+func (h *Handlers) BookmarksFavoritesFeed(w http.ResponseWriter, r *http.Request) {
+	f, err := h.favoritesFeed(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load bookmarks", http.StatusInternalServerError)
+		return
+	}
+	h.writeRSS(w, f)
+}
+
+// BookmarksFeed generates RSS feed for bookmarks
+func (h *Handlers) BookmarksFeed(w http.ResponseWriter, r *http.Request) {
+	f, err := h.bookmarksFeed(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load bookmarks", http.StatusInternalServerError)
+		return
+	}
+	h.writeRSS(w, f)
+}
+
+// PostsAtomFeed generates Atom feed for posts
+func (h *Handlers) PostsAtomFeed(w http.ResponseWriter, r *http.Request) {
+	f, err := h.postsFeed(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load posts", http.StatusInternalServerError)
+		return
+	}
+	h.writeAtom(w, f, r.URL.Path)
+}
+
+// BookmarksAtomFeed generates Atom feed for bookmarks
+func (h *Handlers) BookmarksAtomFeed(w http.ResponseWriter, r *http.Request) {
+	f, err := h.bookmarksFeed(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load bookmarks", http.StatusInternalServerError)
+		return
+	}
+	h.writeAtom(w, f, r.URL.Path)
+}
+
+// writeRSS renders a feed as RSS 2.0
+func (h *Handlers) writeRSS(w http.ResponseWriter, f *feed) {
+	items := make([]RSSItem, 0, len(f.Items))
+	for _, item := range f.Items {
+		items = append(items, RSSItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			PubDate:     item.Published.Format(time.RFC1123Z),
+			GUID:        item.Link,
+			Content:     rssContent(item.Content),
 		})
 	}
 
 	rss := RSS{
 		Version: "2.0",
 		Channel: RSSChannel{
-			Title:         "Bookmarks",
-			Link:          h.config.BaseURL + "/bookmarks",
-			Description:   "Latest bookmarks",
+			Title:         f.Title,
+			Link:          h.config.BaseURL + f.Path,
+			Description:   f.Description,
 			LastBuildDate: time.Now().Format(time.RFC1123Z),
 			Items:         items,
 		},
@@ -109,3 +463,73 @@ func (h *Handlers) BookmarksFeed(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(xml.Header))
 	xml.NewEncoder(w).Encode(rss)
 }
+
+// rssContent wraps an item body for content:encoded, or nil when empty
+func rssContent(body string) *RSSContent {
+	if body == "" {
+		return nil
+	}
+	return &RSSContent{Body: body}
+}
+
+// writeAtom renders a feed as Atom 1.0. selfPath is the path the feed is served at.
+func (h *Handlers) writeAtom(w http.ResponseWriter, f *feed, selfPath string) {
+	// The feed's <updated> is the most recent entry change; an empty feed
+	// falls back to now so the element is always present as the spec requires.
+	updated := time.Time{}
+	entries := make([]AtomEntry, 0, len(f.Items))
+	for _, item := range f.Items {
+		if item.Updated.After(updated) {
+			updated = item.Updated
+		}
+
+		entry := AtomEntry{
+			Title:     item.Title,
+			ID:        h.tagURI(item.Published, item.Path),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Link:      AtomLink{Href: item.Link, Rel: "alternate"},
+		}
+		if item.Description != "" {
+			entry.Summary = &AtomSummary{Type: "text", Body: item.Description}
+		}
+		if item.Content != "" {
+			entry.Content = &AtomSummary{Type: "html", Body: item.Content}
+		}
+		entries = append(entries, entry)
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	atom := AtomFeed{
+		Title:   f.Title,
+		ID:      h.config.BaseURL + selfPath,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Author:  AtomAuthor{Name: h.feedHost()},
+		Links: []AtomLink{
+			{Href: h.config.BaseURL + selfPath, Rel: "self", Type: "application/atom+xml"},
+			{Href: h.config.BaseURL + f.Path, Rel: "alternate", Type: "text/html"},
+		},
+		Entries: entries,
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(atom)
+}
+
+// tagURI builds a stable RFC 4151 tag URI for a feed entry, e.g.
+// tag:example.com,2024-01-02:/posts/hello. The date is the day the entry was
+// first published so the ID never changes when the entry is edited.
+func (h *Handlers) tagURI(published time.Time, path string) string {
+	return "tag:" + h.feedHost() + "," + published.UTC().Format("2006-01-02") + ":" + path
+}
+
+// feedHost returns the authority part of BaseURL for use in tag URIs
+func (h *Handlers) feedHost() string {
+	if u, err := url.Parse(h.config.BaseURL); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return "localhost"
+}
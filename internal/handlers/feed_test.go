@@ -0,0 +1,488 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
+)
+
+func TestPostsAtomFeed(t *testing.T) {
+	published := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	updated := time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC)
+
+	mock := &mockService{
+		listPostsFunc: func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
+			if !publishedOnly {
+				t.Error("Atom feed should only include published posts")
+			}
+			return []models.Post{{
+				ID:          1,
+				Title:       "Hello Atom",
+				Slug:        "hello-atom",
+				Excerpt:     sql.NullString{String: "An excerpt", Valid: true},
+				PublishedAt: sql.NullTime{Time: published, Valid: true},
+				CreatedAt:   published,
+				UpdatedAt:   updated,
+			}}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+	h.config.BaseURL = "https://example.com"
+
+	req := httptest.NewRequest(http.MethodGet, "/feed.atom", nil)
+	rec := httptest.NewRecorder()
+
+	h.PostsAtomFeed(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if ct := rec.Header().Get("Content-Type"); ct != "application/atom+xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want application/atom+xml", ct)
+	}
+
+	var feed AtomFeed
+	if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("failed to parse Atom feed: %v", err)
+	}
+	if feed.XMLName.Space != "http://www.w3.org/2005/Atom" {
+		t.Errorf("namespace = %q, want Atom namespace", feed.XMLName.Space)
+	}
+	if feed.Updated != "2024-03-05T08:30:00Z" {
+		t.Errorf("feed updated = %q, want latest entry update", feed.Updated)
+	}
+
+	var self string
+	for _, link := range feed.Links {
+		if link.Rel == "self" {
+			self = link.Href
+		}
+	}
+	if self != "https://example.com/feed.atom" {
+		t.Errorf("self link = %q, want https://example.com/feed.atom", self)
+	}
+
+	if len(feed.Entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(feed.Entries))
+	}
+	entry := feed.Entries[0]
+	if entry.ID != "tag:example.com,2024-03-01:/posts/hello-atom" {
+		t.Errorf("entry id = %q", entry.ID)
+	}
+	if entry.Published != "2024-03-01T12:00:00Z" {
+		t.Errorf("entry published = %q", entry.Published)
+	}
+	if entry.Link.Href != "https://example.com/posts/hello-atom" {
+		t.Errorf("entry link = %q", entry.Link.Href)
+	}
+	if entry.Summary == nil || entry.Summary.Body != "An excerpt" {
+		t.Errorf("entry summary = %+v, want excerpt", entry.Summary)
+	}
+}
+
+func TestPostsJSONFeed(t *testing.T) {
+	published := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var gotLimit int
+	mock := &mockService{
+		listPostsFunc: func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
+			if !publishedOnly {
+				t.Error("JSON feed should only include published posts")
+			}
+			gotLimit = limit
+			return []models.Post{{
+				ID:          1,
+				Title:       "Hello JSON",
+				Slug:        "hello-json",
+				Content:     "Some **bold** text",
+				PublishedAt: sql.NullTime{Time: published, Valid: true},
+				CreatedAt:   published,
+				UpdatedAt:   published,
+				Tags:        []models.Tag{{Name: "Go", Slug: "go"}},
+			}}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+	h.config.BaseURL = "https://example.com"
+
+	req := httptest.NewRequest(http.MethodGet, "/feed.json", nil)
+	rec := httptest.NewRecorder()
+
+	h.PostsJSONFeed(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/feed+json") {
+		t.Errorf("Content-Type = %q, want application/feed+json", ct)
+	}
+	if gotLimit != postsFeedLimit {
+		t.Errorf("ListPosts limit = %d, want %d", gotLimit, postsFeedLimit)
+	}
+
+	var feed JSONFeed
+	if err := json.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("failed to parse JSON feed: %v", err)
+	}
+	if feed.Version != "https://jsonfeed.org/version/1.1" {
+		t.Errorf("version = %q", feed.Version)
+	}
+	if feed.HomePageURL != "https://example.com/posts" {
+		t.Errorf("home_page_url = %q", feed.HomePageURL)
+	}
+	if feed.FeedURL != "https://example.com/feed.json" {
+		t.Errorf("feed_url = %q", feed.FeedURL)
+	}
+
+	if len(feed.Items) != 1 {
+		t.Fatalf("got %d items, want 1", len(feed.Items))
+	}
+	item := feed.Items[0]
+	if item.ID != "https://example.com/posts/hello-json" || item.URL != item.ID {
+		t.Errorf("item id = %q, url = %q", item.ID, item.URL)
+	}
+	if !strings.Contains(item.ContentHTML, "<strong>bold</strong>") {
+		t.Errorf("content_html = %q, want rendered markdown", item.ContentHTML)
+	}
+	if item.DatePublished != "2024-03-01T12:00:00Z" {
+		t.Errorf("date_published = %q", item.DatePublished)
+	}
+	if len(item.Tags) != 1 || item.Tags[0] != "Go" {
+		t.Errorf("tags = %v, want [Go]", item.Tags)
+	}
+}
+
+func TestPostsFeed_TagLinks(t *testing.T) {
+	mock := &mockService{
+		listPostsFunc: func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
+			return []models.Post{{
+				ID:          1,
+				Title:       "Tagged Post",
+				Slug:        "tagged-post",
+				Excerpt:     sql.NullString{String: "An excerpt", Valid: true},
+				PublishedAt: sql.NullTime{Time: time.Now(), Valid: true},
+				Tags: []models.Tag{
+					{ID: 1, Name: "Go", Slug: "go"},
+					{ID: 2, Name: "Web Dev", Slug: "web-dev"},
+				},
+			}}, nil
+		},
+	}
+
+	tests := []struct {
+		name      string
+		tagLinks  bool
+		wantLinks bool
+	}{
+		{"toggle on", true, true},
+		{"toggle off", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandlers(mock)
+			h.config.BaseURL = "https://example.com"
+			h.config.FeedTagLinks = tt.tagLinks
+
+			// Atom <content>
+			rec := httptest.NewRecorder()
+			h.PostsAtomFeed(rec, httptest.NewRequest(http.MethodGet, "/feed.atom", nil))
+			assertStatus(t, rec, http.StatusOK)
+
+			var feed AtomFeed
+			if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+				t.Fatalf("failed to parse Atom feed: %v", err)
+			}
+			if len(feed.Entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(feed.Entries))
+			}
+			content := feed.Entries[0].Content
+
+			// RSS content:encoded
+			rssRec := httptest.NewRecorder()
+			h.PostsFeed(rssRec, httptest.NewRequest(http.MethodGet, "/feed.xml", nil))
+			var rss RSS
+			if err := xml.Unmarshal(rssRec.Body.Bytes(), &rss); err != nil {
+				t.Fatalf("failed to parse RSS feed: %v", err)
+			}
+			rssContent := rss.Channel.Items[0].Content
+
+			if !tt.wantLinks {
+				if content != nil {
+					t.Errorf("Atom content = %+v, want none", content)
+				}
+				if rssContent != nil {
+					t.Errorf("RSS content = %+v, want none", rssContent)
+				}
+				return
+			}
+
+			if content == nil || content.Type != "html" {
+				t.Fatalf("Atom content = %+v, want html content", content)
+			}
+			if rssContent == nil {
+				t.Fatal("RSS item missing content:encoded")
+			}
+			for _, href := range []string{
+				`href="https://example.com/tags/go"`,
+				`href="https://example.com/tags/web-dev"`,
+			} {
+				if !strings.Contains(content.Body, href) {
+					t.Errorf("Atom content missing %s: %s", href, content.Body)
+				}
+				if !strings.Contains(rssContent.Body, href) {
+					t.Errorf("RSS content missing %s: %s", href, rssContent.Body)
+				}
+			}
+			if !strings.Contains(content.Body, "An excerpt") {
+				t.Errorf("Atom content missing excerpt: %s", content.Body)
+			}
+		})
+	}
+}
+
+func TestBookmarksAtomFeed(t *testing.T) {
+	created := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	mock := &mockService{
+		listBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
+			if !opts.PublicOnly {
+				t.Error("Atom feed should only include public bookmarks")
+			}
+			return []models.Bookmark{{
+				ID:        42,
+				Title:     "Go",
+				URL:       "https://go.dev",
+				CreatedAt: created,
+				UpdatedAt: created,
+			}}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+	h.config.BaseURL = "https://example.com"
+
+	req := httptest.NewRequest(http.MethodGet, "/bookmarks/feed.atom", nil)
+	rec := httptest.NewRecorder()
+
+	h.BookmarksAtomFeed(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, "tag:example.com,2024-01-02:/bookmarks/42")
+	assertBodyContains(t, rec, `href="https://go.dev"`)
+	assertBodyContains(t, rec, `href="https://example.com/bookmarks/feed.atom" rel="self"`)
+}
+
+func TestPostsAtomFeed_Error(t *testing.T) {
+	mock := &mockService{
+		listPostsFunc: func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
+			return nil, errors.New("database error")
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/feed.atom", nil)
+	rec := httptest.NewRecorder()
+
+	h.PostsAtomFeed(rec, req)
+
+	assertStatus(t, rec, http.StatusInternalServerError)
+}
+
+func TestBookmarksCollectionFeed(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	mock := &mockService{
+		getCollectionBySlugFunc: func(ctx context.Context, slug string) (*models.Collection, error) {
+			if slug != "reading" {
+				return nil, sql.ErrNoRows
+			}
+			return &models.Collection{ID: 3, Name: "Reading", Slug: "reading", IsPublic: true}, nil
+		},
+		listBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
+			if !opts.PublicOnly {
+				t.Error("Collection feed should only include public bookmarks")
+			}
+			if opts.CollectionID == nil || *opts.CollectionID != 3 || !opts.IncludeDescendants {
+				t.Errorf("CollectionID = %v, IncludeDescendants = %v; want 3 with descendants", opts.CollectionID, opts.IncludeDescendants)
+			}
+			// The newest entries are picked in SQL, not by scanning the listing
+			if opts.SortBy != service.BookmarkSortAdded || opts.Limit != bookmarksFeedLimit {
+				t.Errorf("SortBy = %q, Limit = %d; want %q, %d", opts.SortBy, opts.Limit, service.BookmarkSortAdded, bookmarksFeedLimit)
+			}
+			return []models.Bookmark{
+				{ID: 2, Title: "Newer", URL: "https://newer.example", CreatedAt: newer},
+				{ID: 1, Title: "Older", URL: "https://older.example", CreatedAt: older},
+			}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/bookmarks/reading/feed.xml", nil)
+	req.SetPathValue("slug", "reading")
+	rec := httptest.NewRecorder()
+
+	h.BookmarksCollectionFeed(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, "<title>Reading bookmarks</title>")
+
+	var rss RSS
+	if err := xml.Unmarshal(rec.Body.Bytes(), &rss); err != nil {
+		t.Fatalf("failed to parse RSS feed: %v", err)
+	}
+	if len(rss.Channel.Items) != 2 || rss.Channel.Items[0].Title != "Newer" {
+		t.Errorf("items should be newest first, got %+v", rss.Channel.Items)
+	}
+}
+
+func TestBookmarksCollectionFeed_NotFound(t *testing.T) {
+	tests := []struct {
+		name       string
+		collection *models.Collection
+		err        error
+	}{
+		{"unknown slug", nil, sql.ErrNoRows},
+		{"private collection", &models.Collection{ID: 1, Slug: "secret", IsPublic: false}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockService{
+				getCollectionBySlugFunc: func(ctx context.Context, slug string) (*models.Collection, error) {
+					return tt.collection, tt.err
+				},
+				listBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
+					t.Error("should not list bookmarks for a missing or private collection")
+					return nil, nil
+				},
+			}
+			h := newTestHandlers(mock)
+
+			req := httptest.NewRequest(http.MethodGet, "/bookmarks/secret/feed.xml", nil)
+			req.SetPathValue("slug", "secret")
+			rec := httptest.NewRecorder()
+
+			h.BookmarksCollectionFeed(rec, req)
+
+			assertStatus(t, rec, http.StatusNotFound)
+		})
+	}
+}
+
+func TestRSSFeeds_ContentMode(t *testing.T) {
+	mock := &mockService{
+		listPostsFunc: func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
+			return []models.Post{{
+				ID:          1,
+				Title:       "Full Post",
+				Slug:        "full-post",
+				Excerpt:     sql.NullString{String: "An excerpt", Valid: true},
+				Content:     "## Intro\n\nSome **bold** text with a stray ]]> marker.",
+				PublishedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			}}, nil
+		},
+		listBookmarksFunc: func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
+			return []models.Bookmark{{
+				ID:          1,
+				Title:       "Go",
+				URL:         "https://go.dev/?a=1&b=2",
+				Description: sql.NullString{String: "The Go site", Valid: true},
+				Note:        sql.NullString{String: "private note", Valid: true},
+				Domain:      sql.NullString{String: "go.dev", Valid: true},
+				CreatedAt:   time.Now(),
+			}}, nil
+		},
+	}
+
+	tests := []struct {
+		name        string
+		fullContent bool
+	}{
+		{"summary", false},
+		{"full content", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandlers(mock)
+			h.config.BaseURL = "https://example.com"
+			h.config.RSSFullContent = tt.fullContent
+
+			feeds := []struct {
+				name    string
+				handler http.HandlerFunc
+				want    string
+			}{
+				{"posts", h.PostsFeed, "<strong>bold</strong>"},
+				{"bookmarks", h.BookmarksFeed, `<a href="https://go.dev/?a=1&amp;b=2">go.dev</a>`},
+			}
+			for _, f := range feeds {
+				rec := httptest.NewRecorder()
+				f.handler(rec, httptest.NewRequest(http.MethodGet, "/feed.xml", nil))
+				assertStatus(t, rec, http.StatusOK)
+
+				var rss RSS
+				if err := xml.Unmarshal(rec.Body.Bytes(), &rss); err != nil {
+					t.Fatalf("%s: failed to parse RSS feed: %v", f.name, err)
+				}
+				if len(rss.Channel.Items) != 1 {
+					t.Fatalf("%s: got %d items, want 1", f.name, len(rss.Channel.Items))
+				}
+				item := rss.Channel.Items[0]
+				if item.Description == "" {
+					t.Errorf("%s: description is empty", f.name)
+				}
+
+				if !tt.fullContent {
+					if item.Content != nil {
+						t.Errorf("%s: content:encoded = %q, want none in summary mode", f.name, item.Content.Body)
+					}
+					if strings.Contains(rec.Body.String(), "encoded") {
+						t.Errorf("%s: feed contains content element in summary mode", f.name)
+					}
+					continue
+				}
+
+				if item.Content == nil {
+					t.Fatalf("%s: missing content:encoded in full content mode", f.name)
+				}
+				if !strings.Contains(item.Content.Body, f.want) {
+					t.Errorf("%s: content:encoded = %q, want it to contain %q", f.name, item.Content.Body, f.want)
+				}
+				if !strings.Contains(rec.Body.String(), "<![CDATA[") {
+					t.Errorf("%s: content:encoded is not CDATA wrapped", f.name)
+				}
+				if strings.Contains(item.Content.Body, "private note") {
+					t.Errorf("%s: content:encoded leaks the bookmark note", f.name)
+				}
+			}
+		})
+	}
+}
+
+func TestPostsFeed_ItemLimit(t *testing.T) {
+	var gotLimit int
+	mock := &mockService{
+		listPostsFunc: func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
+			gotLimit = limit
+			return nil, nil
+		},
+	}
+	h := newTestHandlers(mock)
+	h.config.RSSItemLimit = 5
+
+	rec := httptest.NewRecorder()
+	h.PostsFeed(rec, httptest.NewRequest(http.MethodGet, "/feed.xml", nil))
+
+	assertStatus(t, rec, http.StatusOK)
+	if gotLimit != 5 {
+		t.Errorf("ListPosts limit = %d, want 5", gotLimit)
+	}
+}
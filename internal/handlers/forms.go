@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/EC-9624/0xec.dev/internal/errors"
+)
+
+// maxFormMemory is how much of a multipart form is held in memory; larger
+// files spill to temporary files
+const maxFormMemory = 10 << 20
+
+// parseForm caps the request body at the configured MaxUploadBytes and
+// parses the form, multipart or URL-encoded. Every handler that reads a
+// submitted form goes through it, so they all share the same cap.
+func (h *Handlers) parseForm(w http.ResponseWriter, r *http.Request) error {
+	if h.config.MaxUploadBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.config.MaxUploadBytes)
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.ParseMultipartForm(maxFormMemory)
+	}
+	return r.ParseForm()
+}
+
+// isBodyTooLarge reports whether err came from reading past a body cap
+func isBodyTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return stderrors.As(err, &maxErr)
+}
+
+// bodyTooLargeMessage explains a rejected oversized request
+func (h *Handlers) bodyTooLargeMessage() string {
+	return fmt.Sprintf("The upload is too large. The maximum size is %d MB.", h.config.MaxUploadBytes>>20)
+}
+
+// writeFormError responds to a form parseForm couldn't read: a 413 with
+// bodyTooLargeMessage when the body was over the cap, otherwise a 400 with
+// message. HTMX requests get the message as an error partial.
+func (h *Handlers) writeFormError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	if isBodyTooLarge(err) {
+		errors.WriteError(w, r, errors.TooLarge(h.bodyTooLargeMessage()))
+		return
+	}
+	errors.WriteBadRequest(w, r, message)
+}
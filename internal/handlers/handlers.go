@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/EC-9624/0xec.dev/internal/config"
+	"github.com/EC-9624/0xec.dev/internal/logger"
 	"github.com/EC-9624/0xec.dev/internal/middleware"
 	"github.com/EC-9624/0xec.dev/internal/service"
 
@@ -17,6 +19,9 @@ import (
 type Handlers struct {
 	config  *config.Config
 	service service.ServiceInterface
+
+	// sitemap caches the generated sitemap.xml between crawler requests
+	sitemap xmlCache
 }
 
 // New creates a new Handlers instance with a service interface.
@@ -31,7 +36,26 @@ func New(cfg *config.Config, svc service.ServiceInterface) *Handlers {
 // NewWithDB creates a new Handlers instance with a database connection.
 // This is the standard constructor for production use.
 func NewWithDB(cfg *config.Config, db *sql.DB) *Handlers {
-	return New(cfg, service.New(db))
+	svc := service.New(db)
+	svc.SetThumbnailMaxWidth(cfg.ThumbnailMaxWidth)
+	svc.SetSearchLogging(cfg.SearchLogging && cfg.FeatureEnabled(config.FeatureSearch))
+	svc.SetDashboardStatsTTL(time.Duration(cfg.DashboardStatsTTLSeconds) * time.Second)
+	svc.SetPreviewSecret(cfg.SessionKey)
+	svc.SetMetadataRetry(cfg.MetadataFetchAttempts, time.Duration(cfg.MetadataRetryDelayMS)*time.Millisecond)
+	svc.SetRespectRobots(cfg.MetadataRespectRobots)
+	svc.SetMetadataMaxBodySize(int64(cfg.MetadataMaxBodyKB) * 1024)
+	svc.SetFeaturedPostsMax(cfg.FeaturedPostsMax)
+	svc.SetHomeConfig(service.HomeConfig{
+		Intro:           cfg.HomeIntro,
+		ShowFeatured:    cfg.HomeShowFeatured,
+		RecentPosts:     cfg.HomeRecentPosts,
+		RecentBookmarks: cfg.HomeRecentBookmarks,
+	})
+	svc.SetImportFolderDepth(cfg.ImportFolderDepth)
+	if cfg.TrackingParams != nil {
+		svc.SetTrackingParams(cfg.TrackingParams)
+	}
+	return New(cfg, svc)
 }
 
 // AuthService returns an interface for authentication middleware.
@@ -46,10 +70,48 @@ func (h *Handlers) EnsureAdminExists(ctx context.Context, username, password str
 	return h.service.EnsureAdminExists(ctx, username, password)
 }
 
+// BackfillNormalizedURLs fills in the normalized URL of bookmarks saved
+// before duplicate detection used them. Run once at startup.
+func (h *Handlers) BackfillNormalizedURLs(ctx context.Context) (int, error) {
+	return h.service.BackfillNormalizedURLs(ctx)
+}
+
 // CleanupExpiredSessions removes expired sessions from the database.
 // This is a convenience method for periodic cleanup.
 func (h *Handlers) CleanupExpiredSessions(ctx context.Context) error {
-	return h.service.CleanupExpiredSessions(ctx)
+	n, err := h.service.CleanupExpiredSessions(ctx)
+	if n > 0 {
+		logger.Info(ctx, "removed expired sessions", "count", n)
+	}
+	return err
+}
+
+// PurgeTrashedPosts permanently deletes posts that have been in the trash
+// longer than the configured retention. Run periodically.
+func (h *Handlers) PurgeTrashedPosts(ctx context.Context) error {
+	retention := time.Duration(h.config.PostTrashRetentionDays) * 24 * time.Hour
+	n, err := h.service.PurgeTrashedPosts(ctx, retention)
+	if n > 0 {
+		logger.Info(ctx, "purged trashed posts", "count", n)
+	}
+	return err
+}
+
+// PruneSearchLog deletes logged searches older than the configured
+// retention. Run periodically.
+func (h *Handlers) PruneSearchLog(ctx context.Context) error {
+	retention := time.Duration(h.config.SearchLogRetentionDays) * 24 * time.Hour
+	n, err := h.service.PruneSearchQueries(ctx, retention)
+	if n > 0 {
+		logger.Info(ctx, "pruned search log", "count", n)
+	}
+	return err
+}
+
+// Shutdown cancels background work started by the service and waits for
+// it to finish, giving up when ctx is done
+func (h *Handlers) Shutdown(ctx context.Context) error {
+	return h.service.Shutdown(ctx)
 }
 
 // render is a helper to render templ components
@@ -58,6 +120,20 @@ func render(w http.ResponseWriter, r *http.Request, component templ.Component) {
 	component.Render(r.Context(), w)
 }
 
+// setLastModified sets Last-Modified to the latest of times, for
+// middleware.ConditionalGet to compare against If-Modified-Since
+func setLastModified(w http.ResponseWriter, times ...time.Time) {
+	var latest time.Time
+	for _, t := range times {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	if !latest.IsZero() {
+		w.Header().Set("Last-Modified", latest.UTC().Format(http.TimeFormat))
+	}
+}
+
 // parsePathID parses an int64 ID from the request path.
 // Returns the parsed ID and true if successful, or 0 and false if parsing fails.
 func parsePathID(r *http.Request, name string) (int64, bool) {
@@ -78,3 +154,13 @@ func parseFormInt64(r *http.Request, field string) *int64 {
 	}
 	return nil
 }
+
+// getPageParam returns the 1-based ?page= query value, defaulting to 1 when
+// it is missing or invalid
+func getPageParam(r *http.Request) int {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		return 1
+	}
+	return page
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -24,12 +27,20 @@ type mockService struct {
 	getUserByIDFunc            func(ctx context.Context, id int64) (*models.User, error)
 	getUserByUsernameFunc      func(ctx context.Context, username string) (*models.User, error)
 	validatePasswordFunc       func(user *models.User, password string) bool
-	createSessionFunc          func(ctx context.Context, userID int64, duration time.Duration) (*models.Session, error)
+	createSessionFunc          func(ctx context.Context, userID int64, duration time.Duration, client models.SessionClient) (*models.Session, error)
 	getSessionFunc             func(ctx context.Context, sessionID string) (*models.Session, error)
 	deleteSessionFunc          func(ctx context.Context, sessionID string) error
-	rotateSessionFunc          func(ctx context.Context, userID int64, oldSessionID string, duration time.Duration) (*models.Session, error)
-	cleanupExpiredSessionsFunc func(ctx context.Context) error
+	rotateSessionFunc          func(ctx context.Context, userID int64, oldSessionID string, duration time.Duration, client models.SessionClient) (*models.Session, error)
+	cleanupExpiredSessionsFunc func(ctx context.Context) (int64, error)
 	ensureAdminExistsFunc      func(ctx context.Context, username, password string) error
+	recordFailedLoginFunc      func(ctx context.Context, userID int64, maxAttempts int, lockout time.Duration) (bool, error)
+	resetFailedLoginsFunc      func(ctx context.Context, userID int64) error
+	listSessionsForUserFunc    func(ctx context.Context, userID int64) ([]models.Session, error)
+	revokeSessionFunc          func(ctx context.Context, userID int64, sessionID string) error
+	revokeAllOtherSessionsFunc func(ctx context.Context, userID int64, currentSessionID string) (int64, error)
+	revokeAllSessionsFunc      func(ctx context.Context, userID int64) (int64, error)
+	changePasswordFunc         func(ctx context.Context, userID int64, oldPassword, newPassword string) error
+	touchSessionFunc           func(ctx context.Context, sessionID string, at time.Time) error
 
 	// Bookmark methods
 	createBookmarkFunc                 func(ctx context.Context, input models.CreateBookmarkInput) (*models.Bookmark, error)
@@ -37,25 +48,48 @@ type mockService struct {
 	deleteBookmarkFunc                 func(ctx context.Context, id int64) error
 	getBookmarkByIDFunc                func(ctx context.Context, id int64) (*models.Bookmark, error)
 	getBookmarkByURLFunc               func(ctx context.Context, url string) (*models.Bookmark, error)
+	backfillNormalizedURLsFunc         func(ctx context.Context) (int, error)
 	listBookmarksFunc                  func(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error)
+	listBookmarksCursorFunc            func(ctx context.Context, opts service.BookmarkListOptions, cursor string) (*service.BookmarkPage, error)
 	listUnsortedBookmarksFunc          func(ctx context.Context, limit, offset int) ([]models.Bookmark, error)
+	countUnsortedBookmarksFunc         func(ctx context.Context) (int, error)
 	countBookmarksFunc                 func(ctx context.Context, opts service.BookmarkListOptions) (int, error)
 	updateBookmarkPublicFunc           func(ctx context.Context, id int64, isPublic bool) error
 	updateBookmarkFavoriteFunc         func(ctx context.Context, id int64, isFavorite bool) error
+	updateBookmarkArchivedFunc         func(ctx context.Context, id int64, archived bool) error
+	incrementBookmarkClickFunc         func(ctx context.Context, id int64)
 	moveBookmarkFunc                   func(ctx context.Context, bookmarkID int64, collectionID *int64, afterBookmarkID *int64) error
-	bulkMoveBookmarksFunc              func(ctx context.Context, bookmarkIDs []int64, collectionID *int64, afterBookmarkID *int64) error
+	bulkMoveBookmarksFunc              func(ctx context.Context, bookmarkIDs []int64, collectionID *int64, afterBookmarkID *int64) (string, error)
+	undoBulkMoveFunc                   func(ctx context.Context, token string) error
 	bulkDeleteBookmarksFunc            func(ctx context.Context, bookmarkIDs []int64) error
+	bulkAddTagToBookmarksFunc          func(ctx context.Context, bookmarkIDs []int64, tagID int64) error
+	bulkRemoveTagFunc                  func(ctx context.Context, bookmarkIDs []int64, tagID int64) error
 	refreshBookmarkMetadataFunc        func(ctx context.Context, id int64) error
-	refreshAllMissingMetadataAsyncFunc func(progressChan chan<- string)
+	refreshAllMissingMetadataAsyncFunc func(ctx context.Context, progressChan chan<- string)
 
 	// Post methods
-	createPostFunc      func(ctx context.Context, input models.CreatePostInput) (*models.Post, error)
-	updatePostFunc      func(ctx context.Context, id int64, input models.UpdatePostInput) (*models.Post, error)
-	deletePostFunc      func(ctx context.Context, id int64) error
-	getPostByIDFunc     func(ctx context.Context, id int64) (*models.Post, error)
-	getPostBySlugFunc   func(ctx context.Context, slug string) (*models.Post, error)
-	listPostsFunc       func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error)
-	updatePostDraftFunc func(ctx context.Context, id int64, isDraft bool) error
+	createPostFunc               func(ctx context.Context, input models.CreatePostInput) (*models.Post, error)
+	updatePostFunc               func(ctx context.Context, id int64, input models.UpdatePostInput) (*models.Post, error)
+	duplicatePostFunc            func(ctx context.Context, id int64) (*models.Post, error)
+	deletePostFunc               func(ctx context.Context, id int64) error
+	listTrashedPostsFunc         func(ctx context.Context) ([]models.Post, error)
+	restorePostFunc              func(ctx context.Context, id int64) error
+	purgePostFunc                func(ctx context.Context, id int64) error
+	purgeTrashedPostsFunc        func(ctx context.Context, retention time.Duration) (int, error)
+	getPostByIDFunc              func(ctx context.Context, id int64) (*models.Post, error)
+	getPostBySlugFunc            func(ctx context.Context, slug string) (*models.Post, error)
+	generateUniqueSlugFunc       func(ctx context.Context, title string, excludeID *int64) (string, error)
+	listPostsFunc                func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error)
+	countPostsFunc               func(ctx context.Context, publishedOnly bool) (int, error)
+	listPublishedPostsByTagFunc  func(ctx context.Context, tagID int64) ([]models.Post, error)
+	updatePostDraftFunc          func(ctx context.Context, id int64, isDraft bool) error
+	setPostFeaturedFunc          func(ctx context.Context, id int64, featured bool) error
+	listFeaturedPostsFunc        func(ctx context.Context, limit int) ([]models.Post, error)
+	postCardImageFunc            func(post *models.Post, label string) ([]byte, error)
+	generatePostPreviewTokenFunc func(postID int64) string
+	verifyPostPreviewTokenFunc   func(postID int64, token string) error
+	exportPostMarkdownFunc       func(ctx context.Context, id int64) (string, error)
+	exportPostsZipFunc           func(ctx context.Context, w io.Writer) error
 
 	// Collection methods
 	createCollectionFunc           func(ctx context.Context, input models.CreateCollectionInput) (*models.Collection, error)
@@ -64,30 +98,60 @@ type mockService struct {
 	getCollectionByIDFunc          func(ctx context.Context, id int64) (*models.Collection, error)
 	getCollectionBySlugFunc        func(ctx context.Context, slug string) (*models.Collection, error)
 	listCollectionsFunc            func(ctx context.Context, publicOnly bool) ([]models.Collection, error)
+	listCollectionTreeFunc         func(ctx context.Context, publicOnly bool) ([]service.CollectionNode, error)
 	updateCollectionPublicFunc     func(ctx context.Context, id int64, isPublic bool) error
+	moveCollectionFunc             func(ctx context.Context, collectionID int64, afterCollectionID *int64) error
 	getBookmarksByCollectionIDFunc func(ctx context.Context, collectionID int64) ([]service.CollectionBookmark, error)
 	getBoardViewDataFunc           func(ctx context.Context, recentLimit int) (*service.BoardViewData, error)
 
 	// Tag methods
 	createTagFunc         func(ctx context.Context, input models.CreateTagInput) (*models.Tag, error)
 	deleteTagFunc         func(ctx context.Context, id int64) error
+	mergeTagsFunc         func(ctx context.Context, sourceID, targetID int64) (int, error)
 	getTagBySlugFunc      func(ctx context.Context, slug string) (*models.Tag, error)
 	listTagsFunc          func(ctx context.Context) ([]models.Tag, error)
-	getTagsWithCountsFunc func(ctx context.Context) ([]service.TagWithCount, error)
+	getTagsWithCountsFunc func(ctx context.Context, publishedOnly bool) ([]service.TagWithCount, error)
 	getPostsByTagIDFunc   func(ctx context.Context, tagID int64) ([]service.TagPost, error)
 
 	// Stats methods
 	getDashboardStatsFunc func(ctx context.Context) (*service.DashboardStats, error)
 
+	// Home methods
+	getHomeDataFunc func(ctx context.Context) (*service.HomeData, error)
+
 	// Activity methods
-	logActivityFunc          func(ctx context.Context, action, entityType string, entityID int64, title string, metadata map[string]interface{}) (*service.Activity, error)
-	listRecentActivitiesFunc func(ctx context.Context, limit, offset int) ([]service.Activity, error)
+	logActivityFunc           func(ctx context.Context, action, entityType string, entityID int64, title string, metadata map[string]interface{}) (*service.Activity, error)
+	listRecentActivitiesFunc  func(ctx context.Context, limit, offset int) ([]service.Activity, error)
+	listActivitiesFunc        func(ctx context.Context, filter service.ActivityFilter, limit, offset int) ([]service.Activity, error)
+	countActivitiesFunc       func(ctx context.Context, filter service.ActivityFilter) (int, error)
+	countActivitiesByTypeFunc func(ctx context.Context) ([]service.ActivityTypeCount, error)
 
 	// Metadata methods
 	fetchPageMetadataFunc func(ctx context.Context, url string) (*service.PageMetadata, error)
 
 	// Import methods
-	importBookmarksFunc func(ctx context.Context, bookmarks []service.ImportedBookmark, defaultCollectionID *int64) (*service.ImportResult, error)
+	importBookmarksFunc      func(ctx context.Context, bookmarks []service.ImportedBookmark, defaultCollectionID *int64) (*service.ImportResult, error)
+	previewImportFunc        func(ctx context.Context, bookmarks []service.ImportedBookmark, defaultCollectionID *int64) (*service.ImportPreview, error)
+	commitImportFunc         func(ctx context.Context, token string, selected []int) (*service.ImportResult, error)
+	exportCollectionOPMLFunc func(ctx context.Context, collectionID int64) (string, error)
+	importCollectionOPMLFunc func(ctx context.Context, collectionID int64, r io.Reader) (*service.ImportResult, error)
+	exportSiteFunc           func(ctx context.Context) (io.ReadCloser, error)
+	importSiteFunc           func(ctx context.Context, r io.Reader) (*service.SiteImportResult, error)
+
+	// Image methods
+	createImageFunc        func(ctx context.Context, mimeType string, data []byte, sourceURL string) (*models.Image, error)
+	getImageFunc           func(ctx context.Context, id int64) (*models.Image, error)
+	deleteImageFunc        func(ctx context.Context, id int64) error
+	storeBookmarkCoverFunc func(ctx context.Context, bookmarkID int64, mimeType string, data []byte) (*models.Image, error)
+
+	// Search methods
+	searchFunc                func(ctx context.Context, query string) (service.SearchResults, error)
+	recordSearchQueryFunc     func(ctx context.Context, query string, resultCount int) error
+	pruneSearchQueriesFunc    func(ctx context.Context, retention time.Duration) (int64, error)
+	getZeroResultSearchesFunc func(ctx context.Context, limit int) ([]service.SearchGap, error)
+
+	// Lifecycle methods
+	shutdownFunc func(ctx context.Context) error
 }
 
 // Ensure mockService implements ServiceInterface
@@ -107,6 +171,12 @@ func testConfig() *config.Config {
 		BookmarksPerPage: 10,
 		PostsPerPage:     10,
 		Environment:      "development",
+
+		UpdatedRecentlyDays: 30,
+		LoginMaxAttempts:    5,
+		LoginLockoutMinutes: 15,
+
+		MaxUploadBytes: 32 << 20,
 	}
 }
 
@@ -143,6 +213,15 @@ func assertBodyContains(t *testing.T, rec *httptest.ResponseRecorder, substring
 	}
 }
 
+// assertBodyNotContains checks that the response body does not contain the substring
+func assertBodyNotContains(t *testing.T, rec *httptest.ResponseRecorder, substring string) {
+	t.Helper()
+	body := rec.Body.String()
+	if strings.Contains(body, substring) {
+		t.Errorf("Response body should not contain %q, got: %s", substring, truncate(body, 500))
+	}
+}
+
 // assertCookie checks if the response sets a cookie with the expected name and value
 func assertCookie(t *testing.T, rec *httptest.ResponseRecorder, name, expectedValue string) {
 	t.Helper()
@@ -213,9 +292,9 @@ func (m *mockService) ValidatePassword(user *models.User, password string) bool
 	return false
 }
 
-func (m *mockService) CreateSession(ctx context.Context, userID int64, duration time.Duration) (*models.Session, error) {
+func (m *mockService) CreateSession(ctx context.Context, userID int64, duration time.Duration, client models.SessionClient) (*models.Session, error) {
 	if m.createSessionFunc != nil {
-		return m.createSessionFunc(ctx, userID, duration)
+		return m.createSessionFunc(ctx, userID, duration, client)
 	}
 	return nil, nil
 }
@@ -234,18 +313,18 @@ func (m *mockService) DeleteSession(ctx context.Context, sessionID string) error
 	return nil
 }
 
-func (m *mockService) RotateSession(ctx context.Context, userID int64, oldSessionID string, duration time.Duration) (*models.Session, error) {
+func (m *mockService) RotateSession(ctx context.Context, userID int64, oldSessionID string, duration time.Duration, client models.SessionClient) (*models.Session, error) {
 	if m.rotateSessionFunc != nil {
-		return m.rotateSessionFunc(ctx, userID, oldSessionID, duration)
+		return m.rotateSessionFunc(ctx, userID, oldSessionID, duration, client)
 	}
 	return nil, nil
 }
 
-func (m *mockService) CleanupExpiredSessions(ctx context.Context) error {
+func (m *mockService) CleanupExpiredSessions(ctx context.Context) (int64, error) {
 	if m.cleanupExpiredSessionsFunc != nil {
 		return m.cleanupExpiredSessionsFunc(ctx)
 	}
-	return nil
+	return 0, nil
 }
 
 func (m *mockService) EnsureAdminExists(ctx context.Context, username, password string) error {
@@ -290,6 +369,13 @@ func (m *mockService) GetBookmarkByURL(ctx context.Context, url string) (*models
 	return nil, nil
 }
 
+func (m *mockService) BackfillNormalizedURLs(ctx context.Context) (int, error) {
+	if m.backfillNormalizedURLsFunc != nil {
+		return m.backfillNormalizedURLsFunc(ctx)
+	}
+	return 0, nil
+}
+
 func (m *mockService) ListBookmarks(ctx context.Context, opts service.BookmarkListOptions) ([]models.Bookmark, error) {
 	if m.listBookmarksFunc != nil {
 		return m.listBookmarksFunc(ctx, opts)
@@ -297,6 +383,19 @@ func (m *mockService) ListBookmarks(ctx context.Context, opts service.BookmarkLi
 	return nil, nil
 }
 
+// ListBookmarksCursor falls back to listBookmarksFunc as a single page, so
+// tests written against ListBookmarks keep working for cursor listings
+func (m *mockService) ListBookmarksCursor(ctx context.Context, opts service.BookmarkListOptions, cursor string) (*service.BookmarkPage, error) {
+	if m.listBookmarksCursorFunc != nil {
+		return m.listBookmarksCursorFunc(ctx, opts, cursor)
+	}
+	bookmarks, err := m.ListBookmarks(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &service.BookmarkPage{Bookmarks: bookmarks}, nil
+}
+
 func (m *mockService) ListUnsortedBookmarks(ctx context.Context, limit, offset int) ([]models.Bookmark, error) {
 	if m.listUnsortedBookmarksFunc != nil {
 		return m.listUnsortedBookmarksFunc(ctx, limit, offset)
@@ -304,6 +403,13 @@ func (m *mockService) ListUnsortedBookmarks(ctx context.Context, limit, offset i
 	return nil, nil
 }
 
+func (m *mockService) CountUnsortedBookmarks(ctx context.Context) (int, error) {
+	if m.countUnsortedBookmarksFunc != nil {
+		return m.countUnsortedBookmarksFunc(ctx)
+	}
+	return 0, nil
+}
+
 func (m *mockService) CountBookmarks(ctx context.Context, opts service.BookmarkListOptions) (int, error) {
 	if m.countBookmarksFunc != nil {
 		return m.countBookmarksFunc(ctx, opts)
@@ -325,6 +431,19 @@ func (m *mockService) UpdateBookmarkFavorite(ctx context.Context, id int64, isFa
 	return nil
 }
 
+func (m *mockService) UpdateBookmarkArchived(ctx context.Context, id int64, archived bool) error {
+	if m.updateBookmarkArchivedFunc != nil {
+		return m.updateBookmarkArchivedFunc(ctx, id, archived)
+	}
+	return nil
+}
+
+func (m *mockService) IncrementBookmarkClick(ctx context.Context, id int64) {
+	if m.incrementBookmarkClickFunc != nil {
+		m.incrementBookmarkClickFunc(ctx, id)
+	}
+}
+
 func (m *mockService) MoveBookmark(ctx context.Context, bookmarkID int64, collectionID *int64, afterBookmarkID *int64) error {
 	if m.moveBookmarkFunc != nil {
 		return m.moveBookmarkFunc(ctx, bookmarkID, collectionID, afterBookmarkID)
@@ -332,10 +451,17 @@ func (m *mockService) MoveBookmark(ctx context.Context, bookmarkID int64, collec
 	return nil
 }
 
-func (m *mockService) BulkMoveBookmarks(ctx context.Context, bookmarkIDs []int64, collectionID *int64, afterBookmarkID *int64) error {
+func (m *mockService) BulkMoveBookmarks(ctx context.Context, bookmarkIDs []int64, collectionID *int64, afterBookmarkID *int64) (string, error) {
 	if m.bulkMoveBookmarksFunc != nil {
 		return m.bulkMoveBookmarksFunc(ctx, bookmarkIDs, collectionID, afterBookmarkID)
 	}
+	return "", nil
+}
+
+func (m *mockService) UndoBulkMove(ctx context.Context, token string) error {
+	if m.undoBulkMoveFunc != nil {
+		return m.undoBulkMoveFunc(ctx, token)
+	}
 	return nil
 }
 
@@ -346,6 +472,20 @@ func (m *mockService) BulkDeleteBookmarks(ctx context.Context, bookmarkIDs []int
 	return nil
 }
 
+func (m *mockService) BulkAddTagToBookmarks(ctx context.Context, bookmarkIDs []int64, tagID int64) error {
+	if m.bulkAddTagToBookmarksFunc != nil {
+		return m.bulkAddTagToBookmarksFunc(ctx, bookmarkIDs, tagID)
+	}
+	return nil
+}
+
+func (m *mockService) BulkRemoveTag(ctx context.Context, bookmarkIDs []int64, tagID int64) error {
+	if m.bulkRemoveTagFunc != nil {
+		return m.bulkRemoveTagFunc(ctx, bookmarkIDs, tagID)
+	}
+	return nil
+}
+
 func (m *mockService) RefreshBookmarkMetadata(ctx context.Context, id int64) error {
 	if m.refreshBookmarkMetadataFunc != nil {
 		return m.refreshBookmarkMetadataFunc(ctx, id)
@@ -353,9 +493,9 @@ func (m *mockService) RefreshBookmarkMetadata(ctx context.Context, id int64) err
 	return nil
 }
 
-func (m *mockService) RefreshAllMissingMetadataAsync(progressChan chan<- string) {
+func (m *mockService) RefreshAllMissingMetadataAsync(ctx context.Context, progressChan chan<- string) {
 	if m.refreshAllMissingMetadataAsyncFunc != nil {
-		m.refreshAllMissingMetadataAsyncFunc(progressChan)
+		m.refreshAllMissingMetadataAsyncFunc(ctx, progressChan)
 	}
 }
 
@@ -373,6 +513,13 @@ func (m *mockService) UpdatePost(ctx context.Context, id int64, input models.Upd
 	return nil, nil
 }
 
+func (m *mockService) DuplicatePost(ctx context.Context, id int64) (*models.Post, error) {
+	if m.duplicatePostFunc != nil {
+		return m.duplicatePostFunc(ctx, id)
+	}
+	return nil, nil
+}
+
 func (m *mockService) DeletePost(ctx context.Context, id int64) error {
 	if m.deletePostFunc != nil {
 		return m.deletePostFunc(ctx, id)
@@ -380,6 +527,34 @@ func (m *mockService) DeletePost(ctx context.Context, id int64) error {
 	return nil
 }
 
+func (m *mockService) ListTrashedPosts(ctx context.Context) ([]models.Post, error) {
+	if m.listTrashedPostsFunc != nil {
+		return m.listTrashedPostsFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockService) RestorePost(ctx context.Context, id int64) error {
+	if m.restorePostFunc != nil {
+		return m.restorePostFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockService) PurgePost(ctx context.Context, id int64) error {
+	if m.purgePostFunc != nil {
+		return m.purgePostFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockService) PurgeTrashedPosts(ctx context.Context, retention time.Duration) (int, error) {
+	if m.purgeTrashedPostsFunc != nil {
+		return m.purgeTrashedPostsFunc(ctx, retention)
+	}
+	return 0, nil
+}
+
 func (m *mockService) GetPostByID(ctx context.Context, id int64) (*models.Post, error) {
 	if m.getPostByIDFunc != nil {
 		return m.getPostByIDFunc(ctx, id)
@@ -394,6 +569,13 @@ func (m *mockService) GetPostBySlug(ctx context.Context, slug string) (*models.P
 	return nil, nil
 }
 
+func (m *mockService) GenerateUniqueSlug(ctx context.Context, title string, excludeID *int64) (string, error) {
+	if m.generateUniqueSlugFunc != nil {
+		return m.generateUniqueSlugFunc(ctx, title, excludeID)
+	}
+	return "", nil
+}
+
 func (m *mockService) ListPosts(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
 	if m.listPostsFunc != nil {
 		return m.listPostsFunc(ctx, publishedOnly, limit, offset)
@@ -401,6 +583,20 @@ func (m *mockService) ListPosts(ctx context.Context, publishedOnly bool, limit,
 	return nil, nil
 }
 
+func (m *mockService) CountPosts(ctx context.Context, publishedOnly bool) (int, error) {
+	if m.countPostsFunc != nil {
+		return m.countPostsFunc(ctx, publishedOnly)
+	}
+	return 0, nil
+}
+
+func (m *mockService) ListPublishedPostsByTag(ctx context.Context, tagID int64) ([]models.Post, error) {
+	if m.listPublishedPostsByTagFunc != nil {
+		return m.listPublishedPostsByTagFunc(ctx, tagID)
+	}
+	return nil, nil
+}
+
 func (m *mockService) UpdatePostDraft(ctx context.Context, id int64, isDraft bool) error {
 	if m.updatePostDraftFunc != nil {
 		return m.updatePostDraftFunc(ctx, id, isDraft)
@@ -408,6 +604,20 @@ func (m *mockService) UpdatePostDraft(ctx context.Context, id int64, isDraft boo
 	return nil
 }
 
+func (m *mockService) SetPostFeatured(ctx context.Context, id int64, featured bool) error {
+	if m.setPostFeaturedFunc != nil {
+		return m.setPostFeaturedFunc(ctx, id, featured)
+	}
+	return nil
+}
+
+func (m *mockService) ListFeaturedPosts(ctx context.Context, limit int) ([]models.Post, error) {
+	if m.listFeaturedPostsFunc != nil {
+		return m.listFeaturedPostsFunc(ctx, limit)
+	}
+	return nil, nil
+}
+
 func (m *mockService) CreateCollection(ctx context.Context, input models.CreateCollectionInput) (*models.Collection, error) {
 	if m.createCollectionFunc != nil {
 		return m.createCollectionFunc(ctx, input)
@@ -450,6 +660,21 @@ func (m *mockService) ListCollections(ctx context.Context, publicOnly bool) ([]m
 	return nil, nil
 }
 
+func (m *mockService) ListCollectionTree(ctx context.Context, publicOnly bool) ([]service.CollectionNode, error) {
+	if m.listCollectionTreeFunc != nil {
+		return m.listCollectionTreeFunc(ctx, publicOnly)
+	}
+	collections, err := m.ListCollections(ctx, publicOnly)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]service.CollectionNode, 0, len(collections))
+	for _, c := range collections {
+		nodes = append(nodes, service.CollectionNode{Collection: c})
+	}
+	return nodes, nil
+}
+
 func (m *mockService) UpdateCollectionPublic(ctx context.Context, id int64, isPublic bool) error {
 	if m.updateCollectionPublicFunc != nil {
 		return m.updateCollectionPublicFunc(ctx, id, isPublic)
@@ -457,6 +682,13 @@ func (m *mockService) UpdateCollectionPublic(ctx context.Context, id int64, isPu
 	return nil
 }
 
+func (m *mockService) MoveCollection(ctx context.Context, collectionID int64, afterCollectionID *int64) error {
+	if m.moveCollectionFunc != nil {
+		return m.moveCollectionFunc(ctx, collectionID, afterCollectionID)
+	}
+	return nil
+}
+
 func (m *mockService) GetBookmarksByCollectionID(ctx context.Context, collectionID int64) ([]service.CollectionBookmark, error) {
 	if m.getBookmarksByCollectionIDFunc != nil {
 		return m.getBookmarksByCollectionIDFunc(ctx, collectionID)
@@ -485,6 +717,13 @@ func (m *mockService) DeleteTag(ctx context.Context, id int64) error {
 	return nil
 }
 
+func (m *mockService) MergeTags(ctx context.Context, sourceID, targetID int64) (int, error) {
+	if m.mergeTagsFunc != nil {
+		return m.mergeTagsFunc(ctx, sourceID, targetID)
+	}
+	return 0, nil
+}
+
 func (m *mockService) GetTagBySlug(ctx context.Context, slug string) (*models.Tag, error) {
 	if m.getTagBySlugFunc != nil {
 		return m.getTagBySlugFunc(ctx, slug)
@@ -499,9 +738,9 @@ func (m *mockService) ListTags(ctx context.Context) ([]models.Tag, error) {
 	return nil, nil
 }
 
-func (m *mockService) GetTagsWithCounts(ctx context.Context) ([]service.TagWithCount, error) {
+func (m *mockService) GetTagsWithCounts(ctx context.Context, publishedOnly bool) ([]service.TagWithCount, error) {
 	if m.getTagsWithCountsFunc != nil {
-		return m.getTagsWithCountsFunc(ctx)
+		return m.getTagsWithCountsFunc(ctx, publishedOnly)
 	}
 	return nil, nil
 }
@@ -520,6 +759,13 @@ func (m *mockService) GetDashboardStats(ctx context.Context) (*service.Dashboard
 	return nil, nil
 }
 
+func (m *mockService) GetHomeData(ctx context.Context) (*service.HomeData, error) {
+	if m.getHomeDataFunc != nil {
+		return m.getHomeDataFunc(ctx)
+	}
+	return nil, nil
+}
+
 func (m *mockService) LogActivity(ctx context.Context, action, entityType string, entityID int64, title string, metadata map[string]interface{}) (*service.Activity, error) {
 	if m.logActivityFunc != nil {
 		return m.logActivityFunc(ctx, action, entityType, entityID, title, metadata)
@@ -534,6 +780,27 @@ func (m *mockService) ListRecentActivities(ctx context.Context, limit, offset in
 	return nil, nil
 }
 
+func (m *mockService) ListActivities(ctx context.Context, filter service.ActivityFilter, limit, offset int) ([]service.Activity, error) {
+	if m.listActivitiesFunc != nil {
+		return m.listActivitiesFunc(ctx, filter, limit, offset)
+	}
+	return nil, nil
+}
+
+func (m *mockService) CountActivities(ctx context.Context, filter service.ActivityFilter) (int, error) {
+	if m.countActivitiesFunc != nil {
+		return m.countActivitiesFunc(ctx, filter)
+	}
+	return 0, nil
+}
+
+func (m *mockService) CountActivitiesByType(ctx context.Context) ([]service.ActivityTypeCount, error) {
+	if m.countActivitiesByTypeFunc != nil {
+		return m.countActivitiesByTypeFunc(ctx)
+	}
+	return nil, nil
+}
+
 func (m *mockService) FetchPageMetadata(ctx context.Context, url string) (*service.PageMetadata, error) {
 	if m.fetchPageMetadataFunc != nil {
 		return m.fetchPageMetadataFunc(ctx, url)
@@ -547,3 +814,225 @@ func (m *mockService) ImportBookmarks(ctx context.Context, bookmarks []service.I
 	}
 	return nil, nil
 }
+
+func (m *mockService) PreviewImport(ctx context.Context, bookmarks []service.ImportedBookmark, defaultCollectionID *int64) (*service.ImportPreview, error) {
+	if m.previewImportFunc != nil {
+		return m.previewImportFunc(ctx, bookmarks, defaultCollectionID)
+	}
+	return nil, nil
+}
+
+func (m *mockService) CommitImport(ctx context.Context, token string, selected []int) (*service.ImportResult, error) {
+	if m.commitImportFunc != nil {
+		return m.commitImportFunc(ctx, token, selected)
+	}
+	return nil, nil
+}
+
+func (m *mockService) ExportCollectionOPML(ctx context.Context, collectionID int64) (string, error) {
+	if m.exportCollectionOPMLFunc != nil {
+		return m.exportCollectionOPMLFunc(ctx, collectionID)
+	}
+	return "", nil
+}
+
+func (m *mockService) ImportCollectionOPML(ctx context.Context, collectionID int64, r io.Reader) (*service.ImportResult, error) {
+	if m.importCollectionOPMLFunc != nil {
+		return m.importCollectionOPMLFunc(ctx, collectionID, r)
+	}
+	return nil, nil
+}
+
+func (m *mockService) ExportSite(ctx context.Context) (io.ReadCloser, error) {
+	if m.exportSiteFunc != nil {
+		return m.exportSiteFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockService) ImportSite(ctx context.Context, r io.Reader) (*service.SiteImportResult, error) {
+	if m.importSiteFunc != nil {
+		return m.importSiteFunc(ctx, r)
+	}
+	return nil, nil
+}
+
+func (m *mockService) CreateImage(ctx context.Context, mimeType string, data []byte, sourceURL string) (*models.Image, error) {
+	if m.createImageFunc != nil {
+		return m.createImageFunc(ctx, mimeType, data, sourceURL)
+	}
+	return nil, nil
+}
+
+func (m *mockService) GetImage(ctx context.Context, id int64) (*models.Image, error) {
+	if m.getImageFunc != nil {
+		return m.getImageFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *mockService) DeleteImage(ctx context.Context, id int64) error {
+	if m.deleteImageFunc != nil {
+		return m.deleteImageFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockService) StoreBookmarkCover(ctx context.Context, bookmarkID int64, mimeType string, data []byte) (*models.Image, error) {
+	if m.storeBookmarkCoverFunc != nil {
+		return m.storeBookmarkCoverFunc(ctx, bookmarkID, mimeType, data)
+	}
+	return nil, nil
+}
+
+func (m *mockService) RecordFailedLogin(ctx context.Context, userID int64, maxAttempts int, lockout time.Duration) (bool, error) {
+	if m.recordFailedLoginFunc != nil {
+		return m.recordFailedLoginFunc(ctx, userID, maxAttempts, lockout)
+	}
+	return false, nil
+}
+
+func (m *mockService) ResetFailedLogins(ctx context.Context, userID int64) error {
+	if m.resetFailedLoginsFunc != nil {
+		return m.resetFailedLoginsFunc(ctx, userID)
+	}
+	return nil
+}
+
+func (m *mockService) PostCardImage(post *models.Post, label string) ([]byte, error) {
+	if m.postCardImageFunc != nil {
+		return m.postCardImageFunc(post, label)
+	}
+	return nil, nil
+}
+
+func (m *mockService) GeneratePostPreviewToken(postID int64) string {
+	if m.generatePostPreviewTokenFunc != nil {
+		return m.generatePostPreviewTokenFunc(postID)
+	}
+	return ""
+}
+
+func (m *mockService) VerifyPostPreviewToken(postID int64, token string) error {
+	if m.verifyPostPreviewTokenFunc != nil {
+		return m.verifyPostPreviewTokenFunc(postID, token)
+	}
+	return nil
+}
+
+func (m *mockService) ExportPostMarkdown(ctx context.Context, id int64) (string, error) {
+	if m.exportPostMarkdownFunc != nil {
+		return m.exportPostMarkdownFunc(ctx, id)
+	}
+	return "", nil
+}
+
+func (m *mockService) ExportPostsZip(ctx context.Context, w io.Writer) error {
+	if m.exportPostsZipFunc != nil {
+		return m.exportPostsZipFunc(ctx, w)
+	}
+	return nil
+}
+
+func (m *mockService) ListSessionsForUser(ctx context.Context, userID int64) ([]models.Session, error) {
+	if m.listSessionsForUserFunc != nil {
+		return m.listSessionsForUserFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockService) RevokeSession(ctx context.Context, userID int64, sessionID string) error {
+	if m.revokeSessionFunc != nil {
+		return m.revokeSessionFunc(ctx, userID, sessionID)
+	}
+	return nil
+}
+
+func (m *mockService) RevokeAllOtherSessions(ctx context.Context, userID int64, currentSessionID string) (int64, error) {
+	if m.revokeAllOtherSessionsFunc != nil {
+		return m.revokeAllOtherSessionsFunc(ctx, userID, currentSessionID)
+	}
+	return 0, nil
+}
+
+func (m *mockService) RevokeAllSessions(ctx context.Context, userID int64) (int64, error) {
+	if m.revokeAllSessionsFunc != nil {
+		return m.revokeAllSessionsFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *mockService) Search(ctx context.Context, query string) (service.SearchResults, error) {
+	if m.searchFunc != nil {
+		return m.searchFunc(ctx, query)
+	}
+	return service.SearchResults{Query: query}, nil
+}
+
+func (m *mockService) RecordSearchQuery(ctx context.Context, query string, resultCount int) error {
+	if m.recordSearchQueryFunc != nil {
+		return m.recordSearchQueryFunc(ctx, query, resultCount)
+	}
+	return nil
+}
+
+func (m *mockService) PruneSearchQueries(ctx context.Context, retention time.Duration) (int64, error) {
+	if m.pruneSearchQueriesFunc != nil {
+		return m.pruneSearchQueriesFunc(ctx, retention)
+	}
+	return 0, nil
+}
+
+func (m *mockService) GetZeroResultSearches(ctx context.Context, limit int) ([]service.SearchGap, error) {
+	if m.getZeroResultSearchesFunc != nil {
+		return m.getZeroResultSearchesFunc(ctx, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockService) Shutdown(ctx context.Context) error {
+	if m.shutdownFunc != nil {
+		return m.shutdownFunc(ctx)
+	}
+	return nil
+}
+
+func (m *mockService) ChangePassword(ctx context.Context, userID int64, oldPassword, newPassword string) error {
+	if m.changePasswordFunc != nil {
+		return m.changePasswordFunc(ctx, userID, oldPassword, newPassword)
+	}
+	return nil
+}
+
+func (m *mockService) TouchSession(ctx context.Context, sessionID string, at time.Time) error {
+	if m.touchSessionFunc != nil {
+		return m.touchSessionFunc(ctx, sessionID, at)
+	}
+	return nil
+}
+
+// newMultipartRequest builds a multipart form request with the given fields
+// and, when file is non-nil, a cover image file
+func newMultipartRequest(t *testing.T, target string, fields map[string]string, file []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range fields {
+		mw.WriteField(name, value)
+	}
+	if file != nil {
+		fw, err := mw.CreateFormFile(coverImageField, "cover.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write(file)
+	}
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, target, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+// testPNG is the smallest data http.DetectContentType reports as a PNG
+var testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/EC-9624/0xec.dev/internal/service"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// headingAnchors gives every h2–h4 in a post a slug ID and appends an
+// <a class="anchor"> linking to it, so readers can link to sections.
+// IDs use service.Slugify, the same rules as post slugs, and repeated
+// headings get -2, -3, … so IDs stay unique within the post.
+type headingAnchors struct{}
+
+// Transform implements parser.ASTTransformer
+func (headingAnchors) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	seen := make(map[string]bool)
+
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		if heading.Level < 2 || heading.Level > 4 {
+			return ast.WalkSkipChildren, nil
+		}
+
+		id := uniqueHeadingID(service.Slugify(headingText(heading, source)), seen)
+		heading.SetAttributeString("id", []byte(id))
+
+		anchor := ast.NewLink()
+		anchor.Destination = []byte("#" + id)
+		anchor.SetAttributeString("class", []byte("anchor"))
+		anchor.AppendChild(anchor, ast.NewString([]byte("#")))
+		heading.AppendChild(heading, anchor)
+
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+// headingText returns the plain text of a heading, without formatting
+func headingText(n ast.Node, source []byte) string {
+	var b []byte
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch t := c.(type) {
+		case *ast.Text:
+			b = append(b, t.Segment.Value(source)...)
+		case *ast.String:
+			b = append(b, t.Value...)
+		default:
+			b = append(b, headingText(c, source)...)
+		}
+	}
+	return string(b)
+}
+
+// uniqueHeadingID returns base, or base with the first free numeric
+// suffix if it was already used, and marks the result as used
+func uniqueHeadingID(base string, seen map[string]bool) string {
+	if base == "" {
+		base = "section"
+	}
+	id := base
+	for n := 2; seen[id]; n++ {
+		id = base + "-" + strconv.Itoa(n)
+	}
+	seen[id] = true
+	return id
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ServeImage serves a locally stored image blob.
+// Stored images never change, so responses are cached indefinitely and
+// revalidated by content hash.
+// GET /images/{id}
+func (h *Handlers) ServeImage(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(r, "id")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	image, err := h.service.GetImage(r.Context(), id)
+	if err != nil || image == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	sum := sha256.Sum256(image.Data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", image.MimeType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(image.Data)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(image.Data)
+}
+
+// etagMatches reports whether an If-None-Match header matches etag.
+// Weak validators match too, as If-None-Match uses weak comparison.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
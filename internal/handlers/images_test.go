@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func imageMock() *mockService {
+	return &mockService{
+		getImageFunc: func(ctx context.Context, id int64) (*models.Image, error) {
+			if id != 7 {
+				return nil, sql.ErrNoRows
+			}
+			return &models.Image{ID: 7, MimeType: "image/png", Data: []byte("\x89PNG fake"), Size: 9}, nil
+		},
+	}
+}
+
+func TestServeImage(t *testing.T) {
+	h := newTestHandlers(imageMock())
+
+	req := httptest.NewRequest(http.MethodGet, "/images/7", nil)
+	req.SetPathValue("id", "7")
+	rec := httptest.NewRecorder()
+
+	h.ServeImage(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if ct := rec.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("Content-Type = %q, want image/png", ct)
+	}
+	if rec.Header().Get("ETag") == "" {
+		t.Error("expected an ETag header")
+	}
+	if rec.Header().Get("Cache-Control") == "" {
+		t.Error("expected a Cache-Control header")
+	}
+	assertBodyContains(t, rec, "PNG fake")
+}
+
+func TestServeImage_NotModified(t *testing.T) {
+	h := newTestHandlers(imageMock())
+
+	// First request to learn the ETag
+	req := httptest.NewRequest(http.MethodGet, "/images/7", nil)
+	req.SetPathValue("id", "7")
+	rec := httptest.NewRecorder()
+	h.ServeImage(rec, req)
+	etag := rec.Header().Get("ETag")
+
+	for _, header := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		req := httptest.NewRequest(http.MethodGet, "/images/7", nil)
+		req.SetPathValue("id", "7")
+		req.Header.Set("If-None-Match", header)
+		rec := httptest.NewRecorder()
+
+		h.ServeImage(rec, req)
+
+		assertStatus(t, rec, http.StatusNotModified)
+		if rec.Body.Len() != 0 {
+			t.Errorf("If-None-Match %q: 304 should have an empty body", header)
+		}
+	}
+}
+
+func TestServeImage_NotFound(t *testing.T) {
+	h := newTestHandlers(imageMock())
+
+	for _, id := range []string{"99", "abc"} {
+		req := httptest.NewRequest(http.MethodGet, "/images/"+id, nil)
+		req.SetPathValue("id", id)
+		rec := httptest.NewRecorder()
+
+		h.ServeImage(rec, req)
+
+		assertStatus(t, rec, http.StatusNotFound)
+	}
+}
@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"database/sql"
+	"errors"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/EC-9624/0xec.dev/internal/logger"
 	"github.com/EC-9624/0xec.dev/internal/service"
 	"github.com/EC-9624/0xec.dev/web/templates/admin"
 )
@@ -11,24 +16,100 @@ import (
 // AdminImportPage handles the import page
 func (h *Handlers) AdminImportPage(w http.ResponseWriter, r *http.Request) {
 	collections, _ := h.service.ListCollections(r.Context(), false)
-	render(w, r, admin.ImportPage(collections))
+	h.renderPage(w, r, admin.ImportPage(collections), collections)
 }
 
 // AdminImportBookmarks handles the bookmark import form submission
 func (h *Handlers) AdminImportBookmarks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Parse multipart form (max 10MB)
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
-		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+	bookmarks, ok := h.parseBookmarksUpload(w, r)
+	if !ok {
 		return
 	}
 
+	// Get optional collection ID
+	collectionID := parseFormInt64(r, "collection_id")
+
+	// Import the bookmarks
+	result, err := h.service.ImportBookmarks(ctx, bookmarks, collectionID)
+	if err != nil {
+		http.Error(w, "Failed to import bookmarks", http.StatusInternalServerError)
+		return
+	}
+
+	render(w, r, admin.ImportResult(result))
+}
+
+// AdminImportPreview parses an uploaded bookmarks file and lists what it
+// would import, flagging duplicates, without saving anything. The form it
+// renders posts the selected rows to AdminImportCommit.
+func (h *Handlers) AdminImportPreview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	bookmarks, ok := h.parseBookmarksUpload(w, r)
+	if !ok {
+		return
+	}
+
+	preview, err := h.service.PreviewImport(ctx, bookmarks, parseFormInt64(r, "collection_id"))
+	if err != nil {
+		logger.Error(ctx, "failed to preview bookmark import", "error", err)
+		http.Error(w, "Failed to preview import", http.StatusInternalServerError)
+		return
+	}
+
+	collections, _ := h.service.ListCollections(ctx, false)
+	h.renderPage(w, r, admin.ImportPreviewPage(preview, collections), preview)
+}
+
+// AdminImportCommit imports the rows selected on the import preview
+func (h *Handlers) AdminImportCommit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Failed to parse form")
+		return
+	}
+
+	var selected []int
+	for _, v := range r.Form["selected"] {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "Invalid selection", http.StatusBadRequest)
+			return
+		}
+		selected = append(selected, i)
+	}
+
+	result, err := h.service.CommitImport(ctx, r.FormValue("token"), selected)
+	if errors.Is(err, service.ErrInvalidImportToken) {
+		http.Error(w, "This import preview has expired, upload the file again", http.StatusGone)
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "failed to commit bookmark import", "error", err)
+		http.Error(w, "Failed to import bookmarks", http.StatusInternalServerError)
+		return
+	}
+
+	render(w, r, admin.ImportResult(result))
+}
+
+// parseBookmarksUpload reads and parses the bookmarks file uploaded as
+// "file". On failure it writes the error response and returns false.
+func (h *Handlers) parseBookmarksUpload(w http.ResponseWriter, r *http.Request) ([]service.ImportedBookmark, bool) {
+	// Parse multipart form
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Failed to parse form")
+		return nil, false
+	}
+
 	// Get the file
 	file, _, err := r.FormFile("file")
 	if err != nil {
 		http.Error(w, "Failed to get file", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 	defer file.Close()
 
@@ -36,25 +117,147 @@ func (h *Handlers) AdminImportBookmarks(w http.ResponseWriter, r *http.Request)
 	content, err := io.ReadAll(file)
 	if err != nil {
 		http.Error(w, "Failed to read file", http.StatusInternalServerError)
-		return
+		return nil, false
 	}
 
 	// Parse the bookmarks
 	bookmarks, err := service.ParseChromeBookmarks(string(content))
 	if err != nil {
 		http.Error(w, "Failed to parse bookmarks file", http.StatusBadRequest)
+		return nil, false
+	}
+	return bookmarks, true
+}
+
+// AdminExportCollectionOPML downloads a collection's bookmarks as an OPML
+// subscription list
+func (h *Handlers) AdminExportCollectionOPML(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := parsePathID(r, "id")
+	if !ok {
+		http.Error(w, "Invalid collection ID", http.StatusBadRequest)
+		return
+	}
+	collection, err := h.service.GetCollectionByID(ctx, id)
+	if err != nil {
+		http.Error(w, "Collection not found", http.StatusNotFound)
 		return
 	}
 
-	// Get optional collection ID
-	collectionID := parseFormInt64(r, "collection_id")
+	opml, err := h.service.ExportCollectionOPML(ctx, id)
+	if err != nil {
+		logger.Error(ctx, "failed to export collection OPML", "error", err, "collection_id", id)
+		http.Error(w, "Failed to export collection", http.StatusInternalServerError)
+		return
+	}
 
-	// Import the bookmarks
-	result, err := h.service.ImportBookmarks(ctx, bookmarks, collectionID)
+	w.Header().Set("Content-Type", "text/x-opml; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+collection.Slug+`.opml"`)
+	io.WriteString(w, opml)
+}
+
+// AdminImportCollectionOPML adds the feeds of an uploaded OPML file to a
+// collection as bookmarks
+func (h *Handlers) AdminImportCollectionOPML(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := parsePathID(r, "id")
+	if !ok {
+		http.Error(w, "Invalid collection ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Failed to parse form")
+		return
+	}
+	file, _, err := r.FormFile("file")
 	if err != nil {
-		http.Error(w, "Failed to import bookmarks", http.StatusInternalServerError)
+		http.Error(w, "Failed to get file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.ImportCollectionOPML(ctx, id, file)
+	if errors.Is(err, service.ErrInvalidOPML) {
+		http.Error(w, "Not an OPML file", http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Collection not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "failed to import collection OPML", "error", err, "collection_id", id)
+		http.Error(w, "Failed to import OPML", http.StatusInternalServerError)
 		return
 	}
 
 	render(w, r, admin.ImportResult(result))
 }
+
+// siteExportWriteTimeout replaces the server's write timeout for site
+// exports, which stream for as long as the site takes to read
+const siteExportWriteTimeout = 10 * time.Minute
+
+// AdminExportSite streams a JSON backup of all site content
+func (h *Handlers) AdminExportSite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	export, err := h.service.ExportSite(ctx)
+	if err != nil {
+		logger.Error(ctx, "failed to start site export", "error", err)
+		http.Error(w, "Failed to export site", http.StatusInternalServerError)
+		return
+	}
+	defer export.Close()
+
+	// Not every ResponseWriter supports deadlines; those without one have
+	// no server timeout to extend
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(siteExportWriteTimeout))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="site-export-`+time.Now().Format("2006-01-02")+`.json"`)
+
+	// Headers are already sent, so a failure here can only be logged; the
+	// download ends up truncated and won't parse
+	if _, err := io.Copy(w, export); err != nil {
+		logger.Error(ctx, "site export interrupted", "error", err)
+	}
+}
+
+// AdminImportSite restores content from a site export file
+func (h *Handlers) AdminImportSite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Larger files are spooled to disk by the multipart reader
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Failed to parse form")
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Failed to get file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.ImportSite(ctx, file)
+	if errors.Is(err, service.ErrUnsupportedSiteExport) {
+		http.Error(w, "Not a site export file", http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, service.ErrCollectionCycle) {
+		http.Error(w, "The export nests a collection inside itself", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "failed to import site export", "error", err)
+		http.Error(w, "Failed to import site", http.StatusInternalServerError)
+		return
+	}
+
+	render(w, r, admin.SiteImportResult(result))
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/service"
+)
+
+// closeRecorder is an export body that records whether it was closed
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestAdminExportSite(t *testing.T) {
+	export := &closeRecorder{Reader: strings.NewReader(`{"version":1}`)}
+	mock := &mockService{
+		exportSiteFunc: func(ctx context.Context) (io.ReadCloser, error) {
+			return export, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/export", nil)
+	rec := httptest.NewRecorder()
+
+	h.AdminExportSite(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(got, `attachment; filename="site-export-`) {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	assertBodyContains(t, rec, `{"version":1}`)
+	if !export.closed {
+		t.Error("export was not closed")
+	}
+}
+
+func newSiteImportRequest(t *testing.T, content string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "export.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(fw, content)
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/import/site", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestAdminImportSite(t *testing.T) {
+	var received string
+	mock := &mockService{
+		importSiteFunc: func(ctx context.Context, r io.Reader) (*service.SiteImportResult, error) {
+			data, _ := io.ReadAll(r)
+			received = string(data)
+			return &service.SiteImportResult{Posts: service.ImportResult{Total: 1, Created: 1}}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	rec := httptest.NewRecorder()
+	h.AdminImportSite(rec, newSiteImportRequest(t, `{"version":1}`))
+
+	assertStatus(t, rec, http.StatusOK)
+	if received != `{"version":1}` {
+		t.Errorf("service received %q", received)
+	}
+}
+
+func TestAdminImportSite_BadFile(t *testing.T) {
+	mock := &mockService{
+		importSiteFunc: func(ctx context.Context, r io.Reader) (*service.SiteImportResult, error) {
+			return nil, fmt.Errorf("%w: version 9", service.ErrUnsupportedSiteExport)
+		},
+	}
+	h := newTestHandlers(mock)
+
+	rec := httptest.NewRecorder()
+	h.AdminImportSite(rec, newSiteImportRequest(t, `{"version":9}`))
+
+	assertStatus(t, rec, http.StatusBadRequest)
+}
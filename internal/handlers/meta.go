@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/web/templates/components"
+)
+
+// siteName is used for og:site_name
+const siteName = "0xec"
+
+// absoluteURL resolves a site path against the configured base URL.
+// Absolute URLs are returned unchanged.
+func (h *Handlers) absoluteURL(path string) string {
+	if path == "" || !strings.HasPrefix(path, "/") {
+		return path
+	}
+	return strings.TrimRight(h.config.BaseURL, "/") + path
+}
+
+// defaultShareImage returns the site-wide link preview image, if any
+func (h *Handlers) defaultShareImage() string {
+	return h.absoluteURL(h.config.DefaultShareImage)
+}
+
+// homeMeta describes the home page for link previews
+func (h *Handlers) homeMeta() components.PageMeta {
+	return components.PageMeta{
+		Title:       siteName,
+		Description: "A personal space for writing and collecting interesting things from the web.",
+		URL:         h.absoluteURL("/"),
+		Image:       h.defaultShareImage(),
+		SiteName:    siteName,
+	}
+}
+
+// postMeta describes a published post for link previews
+func (h *Handlers) postMeta(post *models.Post) components.PageMeta {
+	return components.PageMeta{
+		Title:       post.Title,
+		Description: post.GetExcerpt(),
+		URL:         h.absoluteURL("/posts/" + url.PathEscape(post.Slug)),
+		Image:       h.postShareImage(post),
+		Type:        "article",
+		SiteName:    siteName,
+	}
+}
+
+// bookmarksMeta describes the bookmarks page, or one collection of it,
+// for link previews
+func (h *Handlers) bookmarksMeta(collection *models.Collection) components.PageMeta {
+	meta := components.PageMeta{
+		Title:       "Bookmarks",
+		Description: "Interesting things from around the web.",
+		URL:         h.absoluteURL("/bookmarks"),
+		Image:       h.defaultShareImage(),
+		SiteName:    siteName,
+	}
+	if collection != nil {
+		meta.Title = collection.Name + " bookmarks"
+		if description := collection.GetDescription(); description != "" {
+			meta.Description = description
+		}
+		meta.URL = h.absoluteURL("/bookmarks/" + url.PathEscape(collection.Slug))
+	}
+	return meta
+}
+
+// favoritesMeta builds the link preview tags for the favorites page
+func (h *Handlers) favoritesMeta() components.PageMeta {
+	meta := h.bookmarksMeta(nil)
+	meta.Title = "Favorite bookmarks"
+	meta.URL = h.absoluteURL("/bookmarks/" + components.FavoritesSlug)
+	return meta
+}
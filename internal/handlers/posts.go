@@ -3,29 +3,86 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"html/template"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/EC-9624/0xec.dev/internal/logger"
 	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/renderer"
+	"github.com/EC-9624/0xec.dev/internal/service"
 	"github.com/EC-9624/0xec.dev/web/templates/admin"
+	"github.com/EC-9624/0xec.dev/web/templates/components"
 	"github.com/EC-9624/0xec.dev/web/templates/pages"
 
 	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
 )
 
 // PostsIndex handles the posts listing page
 func (h *Handlers) PostsIndex(w http.ResponseWriter, r *http.Request) {
-	posts, err := h.service.ListPosts(r.Context(), true, 100, 0)
+	page, err := h.postsPage(r, true)
 	if err != nil {
 		http.Error(w, "Failed to load posts", http.StatusInternalServerError)
 		return
 	}
 
-	render(w, r, pages.PostsIndex(posts))
+	posts, err := h.service.ListPosts(r.Context(), true, page.PerPage, (page.Page-1)*page.PerPage)
+	if err != nil {
+		http.Error(w, "Failed to load posts", http.StatusInternalServerError)
+		return
+	}
+
+	tags, err := h.service.GetTagsWithCounts(r.Context(), true)
+	if err != nil {
+		http.Error(w, "Failed to load tags", http.StatusInternalServerError)
+		return
+	}
+
+	render(w, r, pages.PostsIndex(posts, nil, tags, page))
+}
+
+// PostsByTag handles the posts listing filtered to a single tag
+func (h *Handlers) PostsByTag(w http.ResponseWriter, r *http.Request) {
+	tag, err := h.service.GetTagBySlug(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	posts, err := h.service.ListPublishedPostsByTag(r.Context(), tag.ID)
+	if err != nil {
+		http.Error(w, "Failed to load posts", http.StatusInternalServerError)
+		return
+	}
+
+	tags, err := h.service.GetTagsWithCounts(r.Context(), true)
+	if err != nil {
+		http.Error(w, "Failed to load tags", http.StatusInternalServerError)
+		return
+	}
+
+	render(w, r, pages.PostsIndex(posts, tag, tags, components.Pagination{}))
+}
+
+// TagsIndex handles the tag cloud page
+func (h *Handlers) TagsIndex(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.service.GetTagsWithCounts(r.Context(), true)
+	if err != nil {
+		http.Error(w, "Failed to load tags", http.StatusInternalServerError)
+		return
+	}
+
+	render(w, r, pages.TagsIndex(tags))
 }
 
 // PostShow handles a single post page (full page only)
@@ -42,7 +99,76 @@ func (h *Handlers) PostShow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	render(w, r, pages.PostShow(*post, allPosts, contentHTML))
+	// The page lists other posts too, so any of them changing counts
+	updated := []time.Time{post.UpdatedAt}
+	for _, p := range allPosts {
+		updated = append(updated, p.UpdatedAt)
+	}
+	setLastModified(w, updated...)
+
+	render(w, r, pages.PostShow(*post, allPosts, contentHTML, h.postFreshness(post, time.Now()), h.postMeta(post)))
+}
+
+// PostPreview renders a post, draft or not, for anyone holding a valid
+// preview token. Preview pages are never cached or indexed.
+func (h *Handlers) PostPreview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	post, err := h.service.GetPostBySlug(ctx, r.PathValue("slug"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := h.service.VerifyPostPreviewToken(post.ID, r.URL.Query().Get("token")); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	allPosts, err := h.service.ListPosts(ctx, true, 100, 0)
+	if err != nil {
+		allPosts = []models.Post{}
+	}
+
+	w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+	w.Header().Set("Cache-Control", "private, no-store")
+	render(w, r, pages.PostShow(*post, allPosts, markdownToHTML(post.Content, post.Slug), h.postFreshness(post, time.Now()), components.PageMeta{}))
+}
+
+// PostCard serves the generated title card image for a published post
+func (h *Handlers) PostCard(w http.ResponseWriter, r *http.Request) {
+	if !h.config.PostCardImages {
+		http.NotFound(w, r)
+		return
+	}
+
+	post, err := h.service.GetPostBySlug(r.Context(), r.PathValue("slug"))
+	if err != nil || post.IsDraft {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := h.service.PostCardImage(post, h.feedHost())
+	if err != nil {
+		logger.Error(r.Context(), "failed to render post card", "slug", post.Slug, "error", err)
+		http.Error(w, "Failed to render card", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Write(data)
+}
+
+// postShareImage picks the link preview image for a post: its cover image,
+// else the generated title card when enabled, else the site default
+func (h *Handlers) postShareImage(post *models.Post) string {
+	if cover := post.CoverImageURL(h.config.ImageBaseURL); cover != "" {
+		return h.absoluteURL(cover)
+	}
+	if h.config.PostCardImages {
+		return h.config.BaseURL + "/posts/" + url.PathEscape(post.Slug) + "/card.png"
+	}
+	return h.defaultShareImage()
 }
 
 // HTMXPostContent returns the post content partial + OOB sidebar update
@@ -59,7 +185,7 @@ func (h *Handlers) HTMXPostContent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	render(w, r, pages.PostContentPartial(*post, contentHTML, allPosts))
+	render(w, r, pages.PostContentPartial(*post, contentHTML, allPosts, h.postFreshness(post, time.Now())))
 }
 
 // getPostData fetches all data needed for a post page
@@ -81,17 +207,41 @@ func (h *Handlers) getPostData(ctx context.Context, slug string) (*models.Post,
 	}
 
 	// Convert markdown content to HTML
-	contentHTML := markdownToHTML(post.Content)
+	contentHTML := markdownToHTML(post.Content, post.Slug)
 
 	return post, allPosts, contentHTML, nil
 }
 
+// postFreshness decides whether a post shows the "Updated on" line and the
+// "Updated recently" badge as of now
+func (h *Handlers) postFreshness(post *models.Post, now time.Time) pages.PostFreshness {
+	if !post.MeaningfullyUpdated() {
+		return pages.PostFreshness{}
+	}
+	window := time.Duration(h.config.UpdatedRecentlyDays) * 24 * time.Hour
+	return pages.PostFreshness{
+		Updated:   true,
+		UpdatedAt: post.UpdatedAt,
+		Recent:    now.Sub(post.UpdatedAt) <= window,
+	}
+}
+
 // markdownToHTML converts markdown to HTML safely using goldmark.
 // By default, goldmark does NOT render raw HTML in markdown (safe mode),
 // preventing XSS attacks from malicious content.
-func markdownToHTML(content string) string {
+//
+// Footnote IDs are prefixed with slug so footnotes from different posts
+// on the same page (such as a feed) don't collide. Headings get anchor
+// links (see headingAnchors).
+func markdownToHTML(content, slug string) string {
 	var buf bytes.Buffer
 	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.NewFootnote(extension.WithFootnoteIDPrefix(slug+"-")),
+		),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(headingAnchors{}, 100)),
+		),
 		goldmark.WithRendererOptions(
 			html.WithHardWraps(),
 			// Note: html.WithUnsafe() is intentionally NOT enabled
@@ -107,13 +257,38 @@ func markdownToHTML(content string) string {
 
 // AdminPostsList handles the admin posts listing
 func (h *Handlers) AdminPostsList(w http.ResponseWriter, r *http.Request) {
-	posts, err := h.service.ListPosts(r.Context(), false, 100, 0)
+	page, err := h.postsPage(r, false)
+	if err != nil {
+		http.Error(w, "Failed to load posts", http.StatusInternalServerError)
+		return
+	}
+
+	posts, err := h.service.ListPosts(r.Context(), false, page.PerPage, (page.Page-1)*page.PerPage)
 	if err != nil {
 		http.Error(w, "Failed to load posts", http.StatusInternalServerError)
 		return
 	}
 
-	render(w, r, admin.PostsList(posts))
+	h.renderPage(w, r, admin.PostsList(posts, page), posts)
+}
+
+// postsPage builds the pagination for a post list from ?page= and the post
+// count. A page past the end is clamped to the last page.
+func (h *Handlers) postsPage(r *http.Request, publishedOnly bool) (components.Pagination, error) {
+	total, err := h.service.CountPosts(r.Context(), publishedOnly)
+	if err != nil {
+		return components.Pagination{}, err
+	}
+
+	page := components.Pagination{
+		Page:    getPageParam(r),
+		PerPage: h.config.PostsPerPage,
+		Total:   total,
+	}
+	if page.Page > page.TotalPages() {
+		page.Page = page.TotalPages()
+	}
+	return page, nil
 }
 
 // AdminPostNew handles the new post form
@@ -146,8 +321,8 @@ func parseTagIDs(r *http.Request) []int64 {
 func (h *Handlers) AdminPostCreate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Invalid form data")
 		return
 	}
 
@@ -158,9 +333,20 @@ func (h *Handlers) AdminPostCreate(w http.ResponseWriter, r *http.Request) {
 		Excerpt:    r.FormValue("excerpt"),
 		CoverImage: r.FormValue("cover_image"),
 		IsDraft:    r.FormValue("is_draft") == "true",
+		IsFeatured: r.FormValue("is_featured") == "true",
 		TagIDs:     parseTagIDs(r),
 	}
 
+	// A blank slug is generated from the title, avoiding existing slugs
+	if strings.TrimSpace(input.Slug) == "" && strings.TrimSpace(input.Title) != "" {
+		slug, err := h.service.GenerateUniqueSlug(ctx, input.Title, nil)
+		if err != nil {
+			logger.Error(ctx, "failed to generate post slug", "error", err, "title", input.Title)
+		} else {
+			input.Slug = slug
+		}
+	}
+
 	// Validate input
 	errors := input.Validate()
 
@@ -184,6 +370,14 @@ func (h *Handlers) AdminPostCreate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	_, err := h.service.CreatePost(ctx, input)
+	if msg := featuredFormError(err); msg != "" {
+		formErrors := models.NewFormErrors()
+		formErrors.AddField("is_featured", msg)
+		tags, _ := h.service.ListTags(ctx)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		render(w, r, admin.PostForm(nil, nil, tags, true, formErrors, &input))
+		return
+	}
 	if err != nil {
 		logger.Error(ctx, "failed to create post", "error", err, "title", input.Title)
 		formErrors := models.NewFormErrors()
@@ -197,6 +391,47 @@ func (h *Handlers) AdminPostCreate(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/admin/posts", http.StatusSeeOther)
 }
 
+// HTMXPostSlugSuggest suggests a unique slug for the title being typed.
+// post_id, when set, is the post being edited, whose own slug is allowed.
+func (h *Handlers) HTMXPostSlugSuggest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	title := strings.TrimSpace(r.FormValue("title"))
+	if title == "" {
+		render(w, r, admin.PostSlugSuggestion(""))
+		return
+	}
+
+	slug, err := h.service.GenerateUniqueSlug(ctx, title, parseFormInt64(r, "post_id"))
+	if err != nil {
+		logger.Error(ctx, "failed to suggest post slug", "error", err)
+		http.Error(w, "Failed to suggest slug", http.StatusInternalServerError)
+		return
+	}
+
+	render(w, r, admin.PostSlugSuggestion(slug))
+}
+
+// HTMXPostPreviewLink issues a fresh preview link for a post and returns it
+// ready to copy
+func (h *Handlers) HTMXPostPreviewLink(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(r, "id")
+	if !ok {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	post, err := h.service.GetPostByID(r.Context(), id)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	link := h.config.BaseURL + "/posts/" + url.PathEscape(post.Slug) + "/preview?token=" +
+		url.QueryEscape(h.service.GeneratePostPreviewToken(post.ID))
+	render(w, r, admin.PostPreviewLink(link, time.Now().Add(service.PostPreviewTTL)))
+}
+
 // AdminPostEdit handles the edit post form
 func (h *Handlers) AdminPostEdit(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -216,7 +451,10 @@ func (h *Handlers) AdminPostEdit(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		logger.Error(ctx, "failed to load tags for post edit form", "error", err)
 	}
-	render(w, r, admin.PostForm(post, post.Tags, tags, false, nil, nil))
+	h.renderPage(w, r, admin.PostForm(post, post.Tags, tags, false, nil, nil), map[string]any{
+		"Post": post,
+		"Tags": tags,
+	})
 }
 
 // AdminPostUpdate handles updating a post
@@ -234,11 +472,13 @@ func (h *Handlers) AdminPostUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Invalid form data")
 		return
 	}
 
+	upload, uploadProblem := readCoverUpload(r)
+
 	input := models.UpdatePostInput{
 		Title:      r.FormValue("title"),
 		Slug:       r.FormValue("slug"),
@@ -246,11 +486,28 @@ func (h *Handlers) AdminPostUpdate(w http.ResponseWriter, r *http.Request) {
 		Excerpt:    r.FormValue("excerpt"),
 		CoverImage: r.FormValue("cover_image"),
 		IsDraft:    r.FormValue("is_draft") == "true",
+		IsFeatured: r.FormValue("is_featured") == "true",
 		TagIDs:     parseTagIDs(r),
 	}
 
+	// An uploaded file replaces the cover image URL, so the URL field is
+	// only validated without one. It stays in the input for re-rendering.
+	coverURL := input.CoverImage
+	if upload != nil {
+		input.CoverImage = ""
+	}
+
 	// Validate input
 	errors := input.Validate()
+	if upload != nil {
+		input.CoverImage = strings.TrimSpace(coverURL)
+	}
+	if uploadProblem != "" {
+		if errors == nil {
+			errors = models.NewFormErrors()
+		}
+		errors.AddField("cover_image", uploadProblem)
+	}
 
 	// Check slug uniqueness (only if slug changed and is valid so far)
 	if (errors == nil || !errors.HasField("slug")) && input.Slug != post.Slug {
@@ -266,44 +523,76 @@ func (h *Handlers) AdminPostUpdate(w http.ResponseWriter, r *http.Request) {
 	// Re-render form with errors if validation failed
 	if errors != nil && errors.HasErrors() {
 		tags, _ := h.service.ListTags(ctx)
-		// Convert UpdatePostInput to CreatePostInput for re-rendering
-		formInput := &models.CreatePostInput{
-			Title:      input.Title,
-			Slug:       input.Slug,
-			Content:    input.Content,
-			Excerpt:    input.Excerpt,
-			CoverImage: input.CoverImage,
-			IsDraft:    input.IsDraft,
-			TagIDs:     input.TagIDs,
-		}
+		formInput := updatePostFormInput(input)
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		render(w, r, admin.PostForm(post, post.Tags, tags, false, errors, formInput))
 		return
 	}
 
+	// Store the upload only once the form is valid, so a rejected form
+	// doesn't leave an unused image behind
+	var uploaded *models.Image
+	if upload != nil {
+		uploaded, err = h.service.CreateImage(ctx, upload.MimeType, upload.Data, "")
+		if err != nil {
+			logger.Error(ctx, "failed to store cover image", "error", err, "id", post.ID)
+			tags, _ := h.service.ListTags(ctx)
+			formErrors := models.NewFormErrors()
+			formErrors.AddField("cover_image", coverNotSavedMessage)
+			formInput := updatePostFormInput(input)
+			w.WriteHeader(http.StatusInternalServerError)
+			render(w, r, admin.PostForm(post, post.Tags, tags, false, formErrors, formInput))
+			return
+		}
+		input.CoverImage = models.ImagePath(uploaded.ID)
+	}
+
 	_, err = h.service.UpdatePost(ctx, post.ID, input)
 	if err != nil {
-		logger.Error(ctx, "failed to update post", "error", err, "id", post.ID)
+		if uploaded != nil {
+			h.service.DeleteImage(ctx, uploaded.ID)
+			input.CoverImage = strings.TrimSpace(coverURL)
+		}
 		formErrors := models.NewFormErrors()
-		formErrors.General = "Failed to update post. Please try again."
-		tags, _ := h.service.ListTags(ctx)
-		formInput := &models.CreatePostInput{
-			Title:      input.Title,
-			Slug:       input.Slug,
-			Content:    input.Content,
-			Excerpt:    input.Excerpt,
-			CoverImage: input.CoverImage,
-			IsDraft:    input.IsDraft,
-			TagIDs:     input.TagIDs,
+		status := http.StatusInternalServerError
+		if msg := featuredFormError(err); msg != "" {
+			formErrors.AddField("is_featured", msg)
+			status = http.StatusUnprocessableEntity
+		} else {
+			logger.Error(ctx, "failed to update post", "error", err, "id", post.ID)
+			formErrors.General = "Failed to update post. Please try again."
 		}
-		w.WriteHeader(http.StatusInternalServerError)
+		tags, _ := h.service.ListTags(ctx)
+		formInput := updatePostFormInput(input)
+		w.WriteHeader(status)
 		render(w, r, admin.PostForm(post, post.Tags, tags, false, formErrors, formInput))
 		return
 	}
 
+	// Stay in the editor after attaching a cover
+	if upload != nil {
+		http.Redirect(w, r, "/admin/posts/"+input.Slug+"/edit", http.StatusSeeOther)
+		return
+	}
+
 	http.Redirect(w, r, "/admin/posts", http.StatusSeeOther)
 }
 
+// updatePostFormInput converts an UpdatePostInput to the CreatePostInput
+// the post form is re-rendered with
+func updatePostFormInput(input models.UpdatePostInput) *models.CreatePostInput {
+	return &models.CreatePostInput{
+		Title:      input.Title,
+		Slug:       input.Slug,
+		Content:    input.Content,
+		Excerpt:    input.Excerpt,
+		CoverImage: input.CoverImage,
+		IsDraft:    input.IsDraft,
+		IsFeatured: input.IsFeatured,
+		TagIDs:     input.TagIDs,
+	}
+}
+
 // AutosaveResponse is the JSON response for autosave requests
 type AutosaveResponse struct {
 	UpdatedAt string `json:"updated_at"`
@@ -311,25 +600,84 @@ type AutosaveResponse struct {
 	Message   string `json:"message"`
 }
 
+// AutosaveErrorResponse is the JSON response for failed autosave requests.
+// Errors maps form field names to messages so the editor can highlight
+// them; General holds errors not tied to a field. UpdatedAt is the server's
+// current value, set only when the post changed since the editor loaded it.
+type AutosaveErrorResponse struct {
+	Errors    map[string]string `json:"errors"`
+	General   string            `json:"general"`
+	UpdatedAt string            `json:"updated_at,omitempty"`
+}
+
+// writeAutosaveErrors writes formErrors as an AutosaveErrorResponse
+func writeAutosaveErrors(w http.ResponseWriter, status int, formErrors *models.FormErrors) {
+	writeAutosaveError(w, status, AutosaveErrorResponse{
+		Errors:  formErrors.Fields,
+		General: formErrors.General,
+	})
+}
+
+// writeAutosaveError writes resp as JSON with the given status
+func writeAutosaveError(w http.ResponseWriter, status int, resp AutosaveErrorResponse) {
+	if resp.Errors == nil {
+		resp.Errors = map[string]string{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// autosaveGeneralError writes a JSON autosave error not tied to a field
+func autosaveGeneralError(w http.ResponseWriter, status int, message string) {
+	writeAutosaveError(w, status, AutosaveErrorResponse{General: message})
+}
+
+// autosaveConflict reports whether the post was saved elsewhere after
+// loadedAt, the updated_at the editor last saw. Timestamps are compared at
+// second precision, the precision they are sent to the client with. An
+// empty or unparseable token never conflicts.
+func autosaveConflict(post *models.Post, loadedAt string) bool {
+	loaded, err := time.Parse(time.RFC3339, loadedAt)
+	if err != nil {
+		return false
+	}
+	return post.UpdatedAt.Truncate(time.Second).After(loaded)
+}
+
 // AdminPostAutosave handles auto-saving a post (PATCH request, returns JSON)
-// Supports action parameter: "save" (default), "publish", "unpublish"
+// Supports action parameter: "save" (default), "publish", "unpublish".
+// The editor sends the updated_at it loaded; if the post has been saved
+// since, the request is rejected with 409 unless force=true.
 func (h *Handlers) AdminPostAutosave(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	slug := r.PathValue("slug")
 	if slug == "" {
-		http.Error(w, "Missing slug", http.StatusBadRequest)
+		autosaveGeneralError(w, http.StatusBadRequest, "Missing slug")
 		return
 	}
 
 	post, err := h.service.GetPostBySlug(ctx, slug)
 	if err != nil {
-		http.Error(w, "Post not found", http.StatusNotFound)
+		autosaveGeneralError(w, http.StatusNotFound, "Post not found")
 		return
 	}
 
 	// Parse multipart form data (FormData from JavaScript sends multipart/form-data)
-	if err := r.ParseMultipartForm(32 << 20); err != nil { // 32MB max
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
+	if err := h.parseForm(w, r); err != nil {
+		if isBodyTooLarge(err) {
+			autosaveGeneralError(w, http.StatusRequestEntityTooLarge, h.bodyTooLargeMessage())
+			return
+		}
+		autosaveGeneralError(w, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	if r.FormValue("force") != "true" && autosaveConflict(post, r.FormValue("updated_at")) {
+		writeAutosaveError(w, http.StatusConflict, AutosaveErrorResponse{
+			General:   "This post was changed in another tab or session since you opened it",
+			UpdatedAt: post.UpdatedAt.Format(time.RFC3339),
+		})
 		return
 	}
 
@@ -357,18 +705,22 @@ func (h *Handlers) AdminPostAutosave(w http.ResponseWriter, r *http.Request) {
 		Excerpt:    r.FormValue("excerpt"),
 		CoverImage: r.FormValue("cover_image"),
 		IsDraft:    isDraft,
+		IsFeatured: r.FormValue("is_featured") == "true",
 		TagIDs:     parseTagIDs(r),
 	}
 
 	// Basic validation - we're more lenient for autosave
+	formErrors := models.NewFormErrors()
 	if input.Title == "" {
-		http.Error(w, "Title is required", http.StatusUnprocessableEntity)
-		return
+		formErrors.AddField("title", "Title is required")
 	}
 
 	// For publish action, require content
-	if action == "publish" && input.Content == "" {
-		http.Error(w, "Content is required to publish", http.StatusUnprocessableEntity)
+	if action == "publish" && renderer.IsEmptyContent(input.Content) {
+		formErrors.AddField("content", "Content is required to publish")
+	}
+	if formErrors.HasErrors() {
+		writeAutosaveErrors(w, http.StatusUnprocessableEntity, formErrors)
 		return
 	}
 
@@ -376,7 +728,8 @@ func (h *Handlers) AdminPostAutosave(w http.ResponseWriter, r *http.Request) {
 	if input.Slug != post.Slug && input.Slug != "" {
 		existing, _ := h.service.GetPostBySlug(ctx, input.Slug)
 		if existing != nil {
-			http.Error(w, "Slug already in use", http.StatusConflict)
+			formErrors.AddField("slug", "Slug already in use")
+			writeAutosaveErrors(w, http.StatusConflict, formErrors)
 			return
 		}
 	}
@@ -387,9 +740,14 @@ func (h *Handlers) AdminPostAutosave(w http.ResponseWriter, r *http.Request) {
 	}
 
 	updatedPost, err := h.service.UpdatePost(ctx, post.ID, input)
+	if msg := featuredFormError(err); msg != "" {
+		formErrors.AddField("is_featured", msg)
+		writeAutosaveErrors(w, http.StatusUnprocessableEntity, formErrors)
+		return
+	}
 	if err != nil {
 		logger.Error(ctx, "failed to autosave post", "error", err, "id", post.ID)
-		http.Error(w, "Failed to save", http.StatusInternalServerError)
+		autosaveGeneralError(w, http.StatusInternalServerError, "Failed to save")
 		return
 	}
 
@@ -408,7 +766,7 @@ func (h *Handlers) AdminPostAutosave(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(AutosaveResponse{
-		UpdatedAt: updatedPost.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: updatedPost.UpdatedAt.Format(time.RFC3339),
 		IsDraft:   updatedPost.IsDraft,
 		Message:   message,
 	})
@@ -443,6 +801,119 @@ func (h *Handlers) AdminPostDelete(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/admin/posts", http.StatusSeeOther)
 }
 
+// AdminPostDuplicate copies a post into a new draft and opens it for
+// editing
+func (h *Handlers) AdminPostDuplicate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	post, err := h.service.GetPostBySlug(ctx, r.PathValue("slug"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	duplicate, err := h.service.DuplicatePost(ctx, post.ID)
+	if err != nil {
+		logger.Error(ctx, "failed to duplicate post", "error", err, "post_id", post.ID)
+		http.Error(w, "Failed to duplicate post", http.StatusInternalServerError)
+		return
+	}
+
+	editURL := "/admin/posts/" + duplicate.Slug + "/edit"
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", editURL)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, editURL, http.StatusSeeOther)
+}
+
+// AdminPostsTrash lists deleted posts that can still be restored
+func (h *Handlers) AdminPostsTrash(w http.ResponseWriter, r *http.Request) {
+	posts, err := h.service.ListTrashedPosts(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load deleted posts", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPage(w, r, admin.PostsTrash(posts, h.config.PostTrashRetentionDays), posts)
+}
+
+// AdminPostRestore takes a post out of the trash
+func (h *Handlers) AdminPostRestore(w http.ResponseWriter, r *http.Request) {
+	h.trashAction(w, r, h.service.RestorePost, "Failed to restore post")
+}
+
+// AdminPostPurge permanently deletes a post from the trash
+func (h *Handlers) AdminPostPurge(w http.ResponseWriter, r *http.Request) {
+	h.trashAction(w, r, h.service.PurgePost, "Failed to delete post")
+}
+
+// trashAction applies action to the trashed post in the path. HTMX
+// requests get an empty body so the row is swapped out; others are
+// redirected back to the trash.
+func (h *Handlers) trashAction(w http.ResponseWriter, r *http.Request, action func(context.Context, int64) error, failure string) {
+	id, ok := parsePathID(r, "id")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := action(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, failure, http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/posts/trash", http.StatusSeeOther)
+}
+
+// AdminPostExportMarkdown downloads a post as a Markdown file
+func (h *Handlers) AdminPostExportMarkdown(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	post, err := h.service.GetPostBySlug(ctx, r.PathValue("slug"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	md, err := h.service.ExportPostMarkdown(ctx, post.ID)
+	if err != nil {
+		logger.Error(ctx, "failed to export post", "post_id", post.ID, "error", err)
+		http.Error(w, "Failed to export post", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+post.Slug+`.md"`)
+	w.Write([]byte(md))
+}
+
+// AdminPostsExportZip downloads every post as Markdown files in a zip archive
+func (h *Handlers) AdminPostsExportZip(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Build the archive in memory so a failure can still return an error status
+	var buf bytes.Buffer
+	if err := h.service.ExportPostsZip(ctx, &buf); err != nil {
+		logger.Error(ctx, "failed to export posts", "error", err)
+		http.Error(w, "Failed to export posts", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="posts-`+time.Now().Format("2006-01-02")+`.zip"`)
+	w.Write(buf.Bytes())
+}
+
 // ============================================
 // INLINE EDITING HANDLERS
 // ============================================
@@ -478,5 +949,5 @@ func (h *Handlers) AdminTogglePostDraft(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Return the updated badge + OOB published date update
-	render(w, r, admin.PostDraftToggleResponse(id, newIsDraft, updatedPost.PublishedAt))
+	render(w, r, admin.PostDraftToggleResponse(id, newIsDraft, updatedPost.IsFeatured, updatedPost.PublishedAt))
 }
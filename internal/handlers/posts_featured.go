@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/EC-9624/0xec.dev/internal/errors"
+	"github.com/EC-9624/0xec.dev/internal/service"
+	"github.com/EC-9624/0xec.dev/web/templates/admin"
+)
+
+// AdminTogglePostFeatured features or unfeatures a post. Drafts and posts
+// past the featured cap are refused with the reason.
+func (h *Handlers) AdminTogglePostFeatured(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid post ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	post, err := h.service.GetPostByID(ctx, id)
+	if err != nil {
+		http.Error(w, "Post not found", http.StatusNotFound)
+		return
+	}
+
+	newIsFeatured := !post.IsFeatured
+	err = h.service.SetPostFeatured(ctx, id, newIsFeatured)
+	if msg := featuredFormError(err); msg != "" {
+		errors.WriteBadRequest(w, r, msg)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to update post", http.StatusInternalServerError)
+		return
+	}
+
+	render(w, r, admin.PostFeaturedBadge(id, newIsFeatured, true))
+}
+
+// featuredFormError returns the message to show on the featured field when
+// err is a featuring rule from the service, or "" for any other error
+func featuredFormError(err error) string {
+	switch {
+	case stderrors.Is(err, service.ErrFeaturedDraft):
+		return "Only published posts can be featured"
+	case stderrors.Is(err, service.ErrFeaturedLimit):
+		return "Too many featured posts. Unfeature one first."
+	}
+	return ""
+}
@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -12,6 +16,7 @@ import (
 	"time"
 
 	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
 )
 
 func TestPostsIndex(t *testing.T) {
@@ -56,6 +61,109 @@ func TestPostsIndex_Empty(t *testing.T) {
 	assertStatus(t, rec, http.StatusOK)
 }
 
+func TestPostsIndex_Pagination(t *testing.T) {
+	// testConfig uses 10 posts per page
+	tests := []struct {
+		name       string
+		query      string
+		wantOffset int
+	}{
+		{"first page", "", 0},
+		{"second page", "?page=2", 10},
+		{"last page", "?page=3", 20},
+		{"past the end clamps", "?page=99", 20},
+		{"invalid page", "?page=abc", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotLimit, gotOffset int
+			mock := &mockService{
+				countPostsFunc: func(ctx context.Context, publishedOnly bool) (int, error) {
+					return 25, nil
+				},
+				listPostsFunc: func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
+					gotLimit, gotOffset = limit, offset
+					return []models.Post{{ID: 1, Title: "A Post", Slug: "a-post"}}, nil
+				},
+			}
+			h := newTestHandlers(mock)
+
+			req := httptest.NewRequest(http.MethodGet, "/posts"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			h.PostsIndex(rec, req)
+
+			assertStatus(t, rec, http.StatusOK)
+			if gotLimit != 10 {
+				t.Errorf("limit = %d, want 10", gotLimit)
+			}
+			if gotOffset != tt.wantOffset {
+				t.Errorf("offset = %d, want %d", gotOffset, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestPostsByTag(t *testing.T) {
+	mock := &mockService{
+		getTagBySlugFunc: func(ctx context.Context, slug string) (*models.Tag, error) {
+			if slug != "go" {
+				return nil, sql.ErrNoRows
+			}
+			return &models.Tag{ID: 7, Name: "Go", Slug: "go"}, nil
+		},
+		listPublishedPostsByTagFunc: func(ctx context.Context, tagID int64) ([]models.Post, error) {
+			if tagID != 7 {
+				t.Errorf("tagID = %d, want 7", tagID)
+			}
+			return []models.Post{{ID: 1, Title: "Tagged Post", Slug: "tagged-post"}}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	t.Run("known tag", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/tags/go", nil)
+		req.SetPathValue("slug", "go")
+		rec := httptest.NewRecorder()
+
+		h.PostsByTag(rec, req)
+
+		assertStatus(t, rec, http.StatusOK)
+		assertBodyContains(t, rec, "Tagged Post")
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/tags/nope", nil)
+		req.SetPathValue("slug", "nope")
+		rec := httptest.NewRecorder()
+
+		h.PostsByTag(rec, req)
+
+		assertStatus(t, rec, http.StatusNotFound)
+	})
+}
+
+func TestTagsIndex(t *testing.T) {
+	mock := &mockService{
+		getTagsWithCountsFunc: func(ctx context.Context, publishedOnly bool) ([]service.TagWithCount, error) {
+			if !publishedOnly {
+				t.Error("Public tag cloud should only count published posts")
+			}
+			return []service.TagWithCount{{Tag: models.Tag{Name: "Go", Slug: "go"}, Count: 3}}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+	rec := httptest.NewRecorder()
+
+	h.TagsIndex(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, "Go")
+}
+
 func TestPostShow(t *testing.T) {
 	testPost := &models.Post{
 		ID:      1,
@@ -90,6 +198,30 @@ func TestPostShow(t *testing.T) {
 	assertBodyContains(t, rec, "This is the post content")
 }
 
+func TestPostShow_LastModified(t *testing.T) {
+	updated := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock := &mockService{
+		getPostBySlugFunc: func(ctx context.Context, slug string) (*models.Post, error) {
+			return &models.Post{ID: 1, Title: "Test Post", Slug: slug, UpdatedAt: updated}, nil
+		},
+		listPostsFunc: func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
+			return []models.Post{{ID: 2, Slug: "older", UpdatedAt: updated.Add(-time.Hour)}}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/test-post", nil)
+	req.SetPathValue("slug", "test-post")
+	rec := httptest.NewRecorder()
+
+	h.PostShow(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if got := rec.Header().Get("Last-Modified"); got != updated.Format(http.TimeFormat) {
+		t.Errorf("Last-Modified = %q, want %q", got, updated.Format(http.TimeFormat))
+	}
+}
+
 func TestPostShow_NotFound(t *testing.T) {
 	mock := &mockService{
 		getPostBySlugFunc: func(ctx context.Context, slug string) (*models.Post, error) {
@@ -133,6 +265,189 @@ func TestPostShow_Draft(t *testing.T) {
 	assertStatus(t, rec, http.StatusNotFound)
 }
 
+func TestPostPreview(t *testing.T) {
+	draftPost := &models.Post{
+		ID:      1,
+		Title:   "Draft Post",
+		Slug:    "draft-post",
+		Content: "Draft content",
+		IsDraft: true,
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{"valid token", "good", http.StatusOK},
+		{"invalid token", "bad", http.StatusNotFound},
+		{"missing token", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockService{
+				getPostBySlugFunc: func(ctx context.Context, slug string) (*models.Post, error) {
+					return draftPost, nil
+				},
+				verifyPostPreviewTokenFunc: func(postID int64, token string) error {
+					if postID != draftPost.ID || token != "good" {
+						return service.ErrInvalidPreviewToken
+					}
+					return nil
+				},
+			}
+			h := newTestHandlers(mock)
+
+			req := httptest.NewRequest(http.MethodGet, "/posts/draft-post/preview?token="+tt.token, nil)
+			req.SetPathValue("slug", "draft-post")
+			rec := httptest.NewRecorder()
+
+			h.PostPreview(rec, req)
+
+			assertStatus(t, rec, tt.wantStatus)
+			if tt.wantStatus == http.StatusOK {
+				assertBodyContains(t, rec, "Draft Post")
+				if got := rec.Header().Get("X-Robots-Tag"); !strings.Contains(got, "noindex") {
+					t.Errorf("X-Robots-Tag = %q, want noindex", got)
+				}
+			}
+		})
+	}
+}
+
+func TestHTMXPostPreviewLink(t *testing.T) {
+	mock := &mockService{
+		getPostByIDFunc: func(ctx context.Context, id int64) (*models.Post, error) {
+			return &models.Post{ID: id, Slug: "draft-post", IsDraft: true}, nil
+		},
+		generatePostPreviewTokenFunc: func(postID int64) string {
+			return "123.sig"
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/htmx/posts/1/preview-link", nil)
+	req.SetPathValue("id", "1")
+	rec := httptest.NewRecorder()
+
+	h.HTMXPostPreviewLink(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, "/posts/draft-post/preview?token=123.sig")
+}
+
+func TestAdminPostExportMarkdown(t *testing.T) {
+	var exportedID int64
+	mock := &mockService{
+		getPostBySlugFunc: func(ctx context.Context, slug string) (*models.Post, error) {
+			return &models.Post{ID: 7, Slug: slug}, nil
+		},
+		exportPostMarkdownFunc: func(ctx context.Context, id int64) (string, error) {
+			exportedID = id
+			return "---\ntitle: \"Hello\"\n---\n\nBody\n", nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/posts/hello/export.md", nil)
+	req.SetPathValue("slug", "hello")
+	rec := httptest.NewRecorder()
+
+	h.AdminPostExportMarkdown(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if exportedID != 7 {
+		t.Errorf("exported post %d, want 7", exportedID)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/markdown; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="hello.md"` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	assertBodyContains(t, rec, "Body")
+}
+
+func TestAdminPostsExportZip(t *testing.T) {
+	mock := &mockService{
+		exportPostsZipFunc: func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, "PK-data")
+			return err
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/posts/export.zip", nil)
+	rec := httptest.NewRecorder()
+
+	h.AdminPostsExportZip(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if got := rec.Header().Get("Content-Type"); got != "application/zip" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(got, `attachment; filename="posts-`) {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	assertBodyContains(t, rec, "PK-data")
+}
+
+func TestPostFreshness(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	published := now.AddDate(0, -6, 0)
+
+	tests := []struct {
+		name        string
+		publishedAt sql.NullTime
+		updatedAt   time.Time
+		wantUpdated bool
+		wantRecent  bool
+	}{
+		{
+			name:        "updated well after publish and recently",
+			publishedAt: sql.NullTime{Time: published, Valid: true},
+			updatedAt:   now.AddDate(0, 0, -3),
+			wantUpdated: true,
+			wantRecent:  true,
+		},
+		{
+			name:        "updated after publish but outside window",
+			publishedAt: sql.NullTime{Time: published, Valid: true},
+			updatedAt:   now.AddDate(0, -2, 0),
+			wantUpdated: true,
+			wantRecent:  false,
+		},
+		{
+			name:        "never edited after publishing",
+			publishedAt: sql.NullTime{Time: published, Valid: true},
+			updatedAt:   published.Add(time.Second),
+		},
+		{
+			name:      "unpublished",
+			updatedAt: now,
+		},
+	}
+
+	h := newTestHandlers(&mockService{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post := &models.Post{PublishedAt: tt.publishedAt, UpdatedAt: tt.updatedAt}
+
+			got := h.postFreshness(post, now)
+			if got.Updated != tt.wantUpdated {
+				t.Errorf("Updated = %v, want %v", got.Updated, tt.wantUpdated)
+			}
+			if got.Recent != tt.wantRecent {
+				t.Errorf("Recent = %v, want %v", got.Recent, tt.wantRecent)
+			}
+			if got.Updated && !got.UpdatedAt.Equal(tt.updatedAt) {
+				t.Errorf("UpdatedAt = %v, want %v", got.UpdatedAt, tt.updatedAt)
+			}
+		})
+	}
+}
+
 func TestAdminPostsList(t *testing.T) {
 	testPosts := []models.Post{
 		{ID: 1, Title: "Published", Slug: "published", IsDraft: false},
@@ -158,6 +473,33 @@ func TestAdminPostsList(t *testing.T) {
 	assertBodyContains(t, rec, "Posts")
 }
 
+func TestAdminPostsList_Pagination(t *testing.T) {
+	var gotOffset int
+	mock := &mockService{
+		countPostsFunc: func(ctx context.Context, publishedOnly bool) (int, error) {
+			if publishedOnly {
+				t.Error("Admin count should include drafts")
+			}
+			return 15, nil
+		},
+		listPostsFunc: func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
+			gotOffset = offset
+			return []models.Post{{ID: 11, Title: "Older", Slug: "older"}}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/posts?page=2", nil)
+	rec := httptest.NewRecorder()
+
+	h.AdminPostsList(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if gotOffset != 10 {
+		t.Errorf("offset = %d, want 10", gotOffset)
+	}
+}
+
 func TestAdminPostNew(t *testing.T) {
 	mock := &mockService{
 		listTagsFunc: func(ctx context.Context) ([]models.Tag, error) {
@@ -227,6 +569,210 @@ func TestAdminPostEdit_NotFound(t *testing.T) {
 	assertStatus(t, rec, http.StatusNotFound)
 }
 
+func TestAdminPostAutosave_Conflict(t *testing.T) {
+	stored := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		loadedAt   string
+		force      bool
+		wantStatus int
+	}{
+		{"loaded version is current", stored.Format(time.RFC3339), false, http.StatusOK},
+		{"saved elsewhere since", stored.Add(-time.Minute).Format(time.RFC3339), false, http.StatusConflict},
+		{"force overwrites", stored.Add(-time.Minute).Format(time.RFC3339), true, http.StatusOK},
+		{"no token", "", false, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := false
+			post := &models.Post{ID: 1, Title: "Post", Slug: "post", IsDraft: true, UpdatedAt: stored}
+			mock := &mockService{
+				getPostBySlugFunc: func(ctx context.Context, slug string) (*models.Post, error) {
+					return post, nil
+				},
+				updatePostFunc: func(ctx context.Context, id int64, input models.UpdatePostInput) (*models.Post, error) {
+					saved = true
+					return &models.Post{ID: id, Title: input.Title, Slug: input.Slug, IsDraft: true, UpdatedAt: stored.Add(time.Hour)}, nil
+				},
+			}
+			h := newTestHandlers(mock)
+
+			fields := map[string]string{"title": "Post", "slug": "post", "is_draft": "true", "updated_at": tt.loadedAt}
+			if tt.force {
+				fields["force"] = "true"
+			}
+			req := newMultipartRequest(t, "/admin/posts/post/autosave", fields, nil)
+			req.SetPathValue("slug", "post")
+			rec := httptest.NewRecorder()
+
+			h.AdminPostAutosave(rec, req)
+
+			assertStatus(t, rec, tt.wantStatus)
+			if saved != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("post saved = %v, want %v", saved, !saved)
+			}
+			if tt.wantStatus != http.StatusConflict {
+				return
+			}
+
+			var resp AutosaveErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("conflict response is not JSON: %v", err)
+			}
+			if resp.UpdatedAt != stored.Format(time.RFC3339) {
+				t.Errorf("updated_at = %q, want %q", resp.UpdatedAt, stored.Format(time.RFC3339))
+			}
+			if resp.General == "" {
+				t.Error("conflict response has no error message")
+			}
+		})
+	}
+}
+
+func TestAdminPostAutosave_ValidationErrors(t *testing.T) {
+	post := &models.Post{ID: 1, Title: "Post", Slug: "post", IsDraft: true}
+	taken := &models.Post{ID: 2, Title: "Other", Slug: "taken"}
+
+	tests := []struct {
+		name       string
+		fields     map[string]string
+		wantStatus int
+		wantField  string
+	}{
+		{"missing title", map[string]string{"title": "", "slug": "post"}, http.StatusUnprocessableEntity, "title"},
+		{"slug conflict", map[string]string{"title": "Post", "slug": "taken"}, http.StatusConflict, "slug"},
+		{"publish without content", map[string]string{"title": "Post", "slug": "post", "action": "publish"}, http.StatusUnprocessableEntity, "content"},
+		{"publish empty Editor.js doc", map[string]string{"title": "Post", "slug": "post", "action": "publish", "content": `{"blocks":[{"type":"paragraph","data":{"text":""}}]}`}, http.StatusUnprocessableEntity, "content"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockService{
+				getPostBySlugFunc: func(ctx context.Context, slug string) (*models.Post, error) {
+					switch slug {
+					case "post":
+						return post, nil
+					case "taken":
+						return taken, nil
+					}
+					return nil, sql.ErrNoRows
+				},
+				updatePostFunc: func(ctx context.Context, id int64, input models.UpdatePostInput) (*models.Post, error) {
+					t.Error("UpdatePost should not be called for invalid input")
+					return post, nil
+				},
+			}
+			h := newTestHandlers(mock)
+
+			req := newMultipartRequest(t, "/admin/posts/post/autosave", tt.fields, nil)
+			req.SetPathValue("slug", "post")
+			rec := httptest.NewRecorder()
+
+			h.AdminPostAutosave(rec, req)
+
+			assertStatus(t, rec, tt.wantStatus)
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+
+			var resp map[string]json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("response is not JSON: %v\n%s", err, rec.Body.String())
+			}
+			var fieldErrors map[string]string
+			if err := json.Unmarshal(resp["errors"], &fieldErrors); err != nil {
+				t.Fatalf("errors is not an object: %s", resp["errors"])
+			}
+			if len(fieldErrors) != 1 || fieldErrors[tt.wantField] == "" {
+				t.Errorf("errors = %v, want a single %q error", fieldErrors, tt.wantField)
+			}
+			if _, ok := resp["general"]; !ok {
+				t.Errorf("response missing general: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminPostAutosave_TooLarge(t *testing.T) {
+	mock := &mockService{
+		getPostBySlugFunc: func(ctx context.Context, slug string) (*models.Post, error) {
+			return &models.Post{ID: 1, Title: "Post", Slug: slug, IsDraft: true}, nil
+		},
+		updatePostFunc: func(ctx context.Context, id int64, input models.UpdatePostInput) (*models.Post, error) {
+			t.Error("UpdatePost should not be called for an oversized request")
+			return nil, nil
+		},
+	}
+	h := newTestHandlers(mock)
+	h.config.MaxUploadBytes = 1 << 20
+
+	req := newMultipartRequest(t, "/admin/posts/post/autosave", map[string]string{
+		"title":   "Post",
+		"content": strings.Repeat("a", 2<<20),
+	}, nil)
+	req.SetPathValue("slug", "post")
+	rec := httptest.NewRecorder()
+
+	h.AdminPostAutosave(rec, req)
+
+	assertStatus(t, rec, http.StatusRequestEntityTooLarge)
+	var resp AutosaveErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("response is not JSON: %v\n%s", err, rec.Body.String())
+	}
+	if resp.General != "The upload is too large. The maximum size is 1 MB." {
+		t.Errorf("general = %q", resp.General)
+	}
+}
+
+func TestAdminPostCreate_TooLarge(t *testing.T) {
+	mock := &mockService{
+		createPostFunc: func(ctx context.Context, input models.CreatePostInput) (*models.Post, error) {
+			t.Error("CreatePost should not be called for an oversized request")
+			return nil, nil
+		},
+	}
+	h := newTestHandlers(mock)
+	h.config.MaxUploadBytes = 1 << 20
+
+	form := url.Values{"title": {"Post"}, "content": {strings.Repeat("a", 2<<20)}}
+	req := httptest.NewRequest(http.MethodPost, "/admin/posts", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+
+	h.AdminPostCreate(rec, req)
+
+	assertStatus(t, rec, http.StatusRequestEntityTooLarge)
+	assertBodyContains(t, rec, `<div class="error-message">The upload is too large. The maximum size is 1 MB.</div>`)
+}
+
+func TestAdminUploadImage_TooLarge(t *testing.T) {
+	h := newTestHandlers(&mockService{})
+	h.config.MaxUploadBytes = 1 << 20
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("image", "big.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(testPNG)
+	fw.Write(make([]byte, 2<<20))
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/uploads/image", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rec := httptest.NewRecorder()
+
+	h.AdminUploadImage(rec, req)
+
+	assertStatus(t, rec, http.StatusRequestEntityTooLarge)
+	assertBodyContains(t, rec, `"error":"The upload is too large. The maximum size is 1 MB."`)
+}
+
 func TestAdminPostDelete(t *testing.T) {
 	deleted := false
 	mock := &mockService{
@@ -259,6 +805,113 @@ func TestAdminPostDelete(t *testing.T) {
 	}
 }
 
+func TestAdminPostDuplicate(t *testing.T) {
+	mock := &mockService{
+		getPostBySlugFunc: func(ctx context.Context, slug string) (*models.Post, error) {
+			return &models.Post{ID: 1, Slug: slug}, nil
+		},
+		duplicatePostFunc: func(ctx context.Context, id int64) (*models.Post, error) {
+			return &models.Post{ID: 2, Slug: "test-post-copy", IsDraft: true}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/posts/test-post/duplicate", nil)
+	req.SetPathValue("slug", "test-post")
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+
+	h.AdminPostDuplicate(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if got := rec.Header().Get("HX-Redirect"); got != "/admin/posts/test-post-copy/edit" {
+		t.Errorf("HX-Redirect = %q, want the copy's edit page", got)
+	}
+}
+
+func TestAdminPostsTrash(t *testing.T) {
+	mock := &mockService{
+		listTrashedPostsFunc: func(ctx context.Context) ([]models.Post, error) {
+			return []models.Post{{
+				ID:        3,
+				Title:     "Deleted Post",
+				Slug:      "deleted-post",
+				DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			}}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/posts/trash", nil)
+	rec := httptest.NewRecorder()
+
+	h.AdminPostsTrash(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, "Deleted Post")
+	assertBodyContains(t, rec, "/admin/posts/trash/3/restore")
+}
+
+func TestAdminPostRestore(t *testing.T) {
+	var restored int64
+	mock := &mockService{
+		restorePostFunc: func(ctx context.Context, id int64) error {
+			if id != 3 {
+				return sql.ErrNoRows
+			}
+			restored = id
+			return nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/posts/trash/3/restore", nil)
+	req.SetPathValue("id", "3")
+	rec := httptest.NewRecorder()
+
+	h.AdminPostRestore(rec, req)
+
+	assertRedirect(t, rec, "/admin/posts/trash")
+	if restored != 3 {
+		t.Errorf("restored post %d, want 3", restored)
+	}
+
+	// A post that isn't in the trash is not found
+	req = httptest.NewRequest(http.MethodPost, "/admin/posts/trash/9/restore", nil)
+	req.SetPathValue("id", "9")
+	rec = httptest.NewRecorder()
+
+	h.AdminPostRestore(rec, req)
+
+	assertStatus(t, rec, http.StatusNotFound)
+}
+
+func TestAdminPostPurge_HTMX(t *testing.T) {
+	var purged int64
+	mock := &mockService{
+		purgePostFunc: func(ctx context.Context, id int64) error {
+			purged = id
+			return nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/posts/trash/4", nil)
+	req.SetPathValue("id", "4")
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+
+	h.AdminPostPurge(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if purged != 4 {
+		t.Errorf("purged post %d, want 4", purged)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("body = %q, want empty so the row is removed", rec.Body.String())
+	}
+}
+
 func TestAdminTogglePostDraft(t *testing.T) {
 	currentDraft := true
 	mock := &mockService{
@@ -309,6 +962,31 @@ func TestAdminTogglePostDraft_NotFound(t *testing.T) {
 	assertStatus(t, rec, http.StatusNotFound)
 }
 
+func TestAdminTogglePostFeatured_DraftRejected(t *testing.T) {
+	mock := &mockService{
+		getPostByIDFunc: func(ctx context.Context, id int64) (*models.Post, error) {
+			return &models.Post{ID: id, Title: "Test", Slug: "test", IsDraft: true}, nil
+		},
+		setPostFeaturedFunc: func(ctx context.Context, id int64, featured bool) error {
+			return service.ErrFeaturedDraft
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/htmx/posts/1/toggle-featured", nil)
+	req.SetPathValue("id", "1")
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+
+	h.AdminTogglePostFeatured(rec, req)
+
+	assertStatus(t, rec, http.StatusBadRequest)
+	assertBodyContains(t, rec, "Only published posts can be featured")
+	if got := rec.Header().Get("HX-Reswap"); got != "none" {
+		t.Errorf("HX-Reswap = %q, want none so the toggle is left as it was", got)
+	}
+}
+
 func TestAdminPostCreate_Success(t *testing.T) {
 	createdPost := &models.Post{
 		ID:        1,
@@ -345,6 +1023,63 @@ func TestAdminPostCreate_Success(t *testing.T) {
 	}
 }
 
+func TestAdminPostCreate_GeneratesSlug(t *testing.T) {
+	var gotInput models.CreatePostInput
+	mock := &mockService{
+		generateUniqueSlugFunc: func(ctx context.Context, title string, excludeID *int64) (string, error) {
+			if title != "Hello, World" {
+				t.Errorf("Expected slug generated from title, got %q", title)
+			}
+			return "hello-world-2", nil
+		},
+		createPostFunc: func(ctx context.Context, input models.CreatePostInput) (*models.Post, error) {
+			gotInput = input
+			return &models.Post{ID: 1, Title: input.Title, Slug: input.Slug}, nil
+		},
+		listTagsFunc: func(ctx context.Context) ([]models.Tag, error) {
+			return []models.Tag{}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/posts", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.ParseForm()
+	req.Form.Set("title", "Hello, World")
+	req.Form.Set("content", "Content")
+	rec := httptest.NewRecorder()
+
+	h.AdminPostCreate(rec, req)
+
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("Expected redirect, got %d", rec.Code)
+	}
+	if gotInput.Slug != "hello-world-2" {
+		t.Errorf("Expected generated slug 'hello-world-2', got %q", gotInput.Slug)
+	}
+}
+
+func TestHTMXPostSlugSuggest(t *testing.T) {
+	mock := &mockService{
+		generateUniqueSlugFunc: func(ctx context.Context, title string, excludeID *int64) (string, error) {
+			return "my-first-post", nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/htmx/posts/slug-suggest?title=My+First+Post", nil)
+	rec := httptest.NewRecorder()
+
+	h.HTMXPostSlugSuggest(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "my-first-post") {
+		t.Error("Expected suggested slug in response")
+	}
+}
+
 func TestAdminPostCreate_ValidationError(t *testing.T) {
 	mock := &mockService{
 		listTagsFunc: func(ctx context.Context) ([]models.Tag, error) {
@@ -420,6 +1155,128 @@ func TestAdminPostUpdate_Success(t *testing.T) {
 	}
 }
 
+func TestAdminPostUpdate_CoverUpload(t *testing.T) {
+	existingPost := &models.Post{ID: 1, Title: "Post", Slug: "post", IsDraft: true}
+	fields := map[string]string{
+		"title":       "Post",
+		"slug":        "post",
+		"cover_image": "https://example.com/old.jpg",
+		"is_draft":    "true",
+	}
+
+	tests := []struct {
+		name       string
+		file       []byte
+		wantStatus int
+		wantCover  string
+	}{
+		{"no file keeps url", nil, http.StatusSeeOther, "https://example.com/old.jpg"},
+		{"image replaces url", testPNG, http.StatusSeeOther, "/images/9"},
+		{"non-image rejected", []byte("just some text"), http.StatusUnprocessableEntity, ""},
+		{"oversized rejected", append(append([]byte{}, testPNG...), make([]byte, models.MaxCoverImageSize)...), http.StatusUnprocessableEntity, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCover string
+			mock := &mockService{
+				getPostBySlugFunc: func(ctx context.Context, slug string) (*models.Post, error) {
+					return existingPost, nil
+				},
+				createImageFunc: func(ctx context.Context, mimeType string, data []byte, sourceURL string) (*models.Image, error) {
+					if mimeType != "image/png" {
+						t.Errorf("mimeType = %q, want image/png", mimeType)
+					}
+					return &models.Image{ID: 9}, nil
+				},
+				updatePostFunc: func(ctx context.Context, id int64, input models.UpdatePostInput) (*models.Post, error) {
+					gotCover = input.CoverImage
+					return existingPost, nil
+				},
+			}
+			h := newTestHandlers(mock)
+
+			req := newMultipartRequest(t, "/admin/posts/post", fields, tt.file)
+			req.SetPathValue("slug", "post")
+			rec := httptest.NewRecorder()
+
+			h.AdminPostUpdate(rec, req)
+
+			assertStatus(t, rec, tt.wantStatus)
+			if gotCover != tt.wantCover {
+				t.Errorf("saved cover = %q, want %q", gotCover, tt.wantCover)
+			}
+			if tt.file != nil && tt.wantStatus == http.StatusSeeOther {
+				assertRedirect(t, rec, "/admin/posts/post/edit")
+			}
+		})
+	}
+}
+
+func TestAdminPostUpdate_CoverUploadStoredAfterValidation(t *testing.T) {
+	existingPost := &models.Post{ID: 1, Title: "Post", Slug: "post", IsDraft: true}
+
+	t.Run("invalid form stores nothing", func(t *testing.T) {
+		mock := &mockService{
+			getPostBySlugFunc: func(ctx context.Context, slug string) (*models.Post, error) {
+				return existingPost, nil
+			},
+			createImageFunc: func(ctx context.Context, mimeType string, data []byte, sourceURL string) (*models.Image, error) {
+				t.Error("cover stored for a form that failed validation")
+				return &models.Image{ID: 9}, nil
+			},
+		}
+		h := newTestHandlers(mock)
+
+		req := newMultipartRequest(t, "/admin/posts/post", map[string]string{
+			"title":    "",
+			"slug":     "post",
+			"is_draft": "true",
+		}, testPNG)
+		req.SetPathValue("slug", "post")
+		rec := httptest.NewRecorder()
+
+		h.AdminPostUpdate(rec, req)
+
+		assertStatus(t, rec, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("failed update deletes the stored cover", func(t *testing.T) {
+		var deleted int64
+		mock := &mockService{
+			getPostBySlugFunc: func(ctx context.Context, slug string) (*models.Post, error) {
+				return existingPost, nil
+			},
+			createImageFunc: func(ctx context.Context, mimeType string, data []byte, sourceURL string) (*models.Image, error) {
+				return &models.Image{ID: 9}, nil
+			},
+			updatePostFunc: func(ctx context.Context, id int64, input models.UpdatePostInput) (*models.Post, error) {
+				return nil, errors.New("database error")
+			},
+			deleteImageFunc: func(ctx context.Context, id int64) error {
+				deleted = id
+				return nil
+			},
+		}
+		h := newTestHandlers(mock)
+
+		req := newMultipartRequest(t, "/admin/posts/post", map[string]string{
+			"title":    "Post",
+			"slug":     "post",
+			"is_draft": "true",
+		}, testPNG)
+		req.SetPathValue("slug", "post")
+		rec := httptest.NewRecorder()
+
+		h.AdminPostUpdate(rec, req)
+
+		assertStatus(t, rec, http.StatusInternalServerError)
+		if deleted != 9 {
+			t.Errorf("deleted image %d, want the new cover 9", deleted)
+		}
+	})
+}
+
 func TestAdminPostUpdate_NotFound(t *testing.T) {
 	mock := &mockService{
 		getPostBySlugFunc: func(ctx context.Context, slug string) (*models.Post, error) {
@@ -452,3 +1309,153 @@ func TestPostsIndex_ServiceError(t *testing.T) {
 
 	assertStatus(t, rec, http.StatusInternalServerError)
 }
+
+func TestPostCard(t *testing.T) {
+	published := &models.Post{ID: 1, Title: "Hello", Slug: "hello"}
+	draft := &models.Post{ID: 2, Title: "Draft", Slug: "draft", IsDraft: true}
+
+	tests := []struct {
+		name       string
+		enabled    bool
+		slug       string
+		wantStatus int
+	}{
+		{"enabled", true, "hello", http.StatusOK},
+		{"disabled", false, "hello", http.StatusNotFound},
+		{"draft", true, "draft", http.StatusNotFound},
+		{"missing", true, "missing", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockService{
+				getPostBySlugFunc: func(ctx context.Context, slug string) (*models.Post, error) {
+					switch slug {
+					case published.Slug:
+						return published, nil
+					case draft.Slug:
+						return draft, nil
+					}
+					return nil, sql.ErrNoRows
+				},
+				postCardImageFunc: func(post *models.Post, label string) ([]byte, error) {
+					return []byte("\x89PNG"), nil
+				},
+			}
+			h := newTestHandlers(mock)
+			h.config.PostCardImages = tt.enabled
+
+			req := httptest.NewRequest(http.MethodGet, "/posts/"+tt.slug+"/card.png", nil)
+			req.SetPathValue("slug", tt.slug)
+			rec := httptest.NewRecorder()
+
+			h.PostCard(rec, req)
+
+			assertStatus(t, rec, tt.wantStatus)
+			if tt.wantStatus == http.StatusOK && rec.Header().Get("Content-Type") != "image/png" {
+				t.Errorf("Content-Type = %q, want image/png", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestPostShareImage(t *testing.T) {
+	h := newTestHandlers(&mockService{})
+	h.config.BaseURL = "https://example.com"
+
+	withCover := &models.Post{Slug: "a", CoverImage: sql.NullString{String: "/images/5", Valid: true}}
+	withoutCover := &models.Post{Slug: "b"}
+
+	if got := h.postShareImage(withCover); got != "https://example.com/images/5" {
+		t.Errorf("postShareImage(cover) = %q", got)
+	}
+	h.config.ImageBaseURL = "https://cdn.example.net"
+	if got := h.postShareImage(withCover); got != "https://cdn.example.net/images/5" {
+		t.Errorf("postShareImage(cover) with image base = %q", got)
+	}
+	if got := h.postShareImage(withoutCover); got != "" {
+		t.Errorf("postShareImage() with cards disabled = %q, want empty", got)
+	}
+
+	h.config.PostCardImages = true
+	if got := h.postShareImage(withoutCover); got != "https://example.com/posts/b/card.png" {
+		t.Errorf("postShareImage() with cards enabled = %q", got)
+	}
+}
+
+func TestPostShareImage_SiteDefault(t *testing.T) {
+	h := newTestHandlers(&mockService{})
+	h.config.BaseURL = "https://example.com"
+	h.config.DefaultShareImage = "/static/images/share.png"
+
+	if got := h.postShareImage(&models.Post{Slug: "b"}); got != "https://example.com/static/images/share.png" {
+		t.Errorf("postShareImage() = %q, want site default", got)
+	}
+
+	h.config.DefaultShareImage = "https://cdn.example.com/share.png"
+	if got := h.postShareImage(&models.Post{Slug: "b"}); got != "https://cdn.example.com/share.png" {
+		t.Errorf("postShareImage() = %q, want absolute default unchanged", got)
+	}
+}
+
+func TestPostMeta(t *testing.T) {
+	h := newTestHandlers(&mockService{})
+	h.config.BaseURL = "https://example.com"
+
+	post := &models.Post{
+		Title:      "Hello",
+		Slug:       "hello",
+		Excerpt:    sql.NullString{String: "A short intro", Valid: true},
+		CoverImage: sql.NullString{String: "/images/3", Valid: true},
+	}
+	meta := h.postMeta(post)
+
+	if meta.Title != "Hello" || meta.Description != "A short intro" {
+		t.Errorf("postMeta() title/description = %q/%q", meta.Title, meta.Description)
+	}
+	if meta.URL != "https://example.com/posts/hello" {
+		t.Errorf("postMeta().URL = %q", meta.URL)
+	}
+	if meta.Image != "https://example.com/images/3" {
+		t.Errorf("postMeta().Image = %q", meta.Image)
+	}
+	if meta.Type != "article" {
+		t.Errorf("postMeta().Type = %q, want article", meta.Type)
+	}
+}
+
+func TestMarkdownToHTML_Footnotes(t *testing.T) {
+	got := markdownToHTML("A claim.[^1]\n\n[^1]: The source.\n", "my-post")
+
+	for _, want := range []string{
+		`<sup id="my-post-fnref:1"><a href="#my-post-fn:1" class="footnote-ref" role="doc-noteref">1</a></sup>`,
+		`class="footnotes" role="doc-endnotes"`,
+		`<li id="my-post-fn:1">`,
+		`The source.`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+
+	// Raw HTML stays disabled with footnotes on
+	if unsafe := markdownToHTML("<script>alert(1)</script>[^x]\n\n[^x]: note\n", "p"); strings.Contains(unsafe, "<script>") {
+		t.Errorf("raw HTML rendered: %s", unsafe)
+	}
+}
+
+func TestMarkdownToHTML_HeadingAnchors(t *testing.T) {
+	got := markdownToHTML("# Title\n\n## Setup\n\ntext\n\n## Setup\n\n### Go *1.25* & you\n\n##### Deep\n", "p")
+
+	for _, want := range []string{
+		`<h2 id="setup">Setup<a href="#setup" class="anchor">#</a></h2>`,
+		`<h2 id="setup-2">Setup<a href="#setup-2" class="anchor">#</a></h2>`,
+		`<h3 id="go-1-25-you">`,
+		`<h1>Title</h1>`,
+		`<h5>Deep</h5>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/EC-9624/0xec.dev/internal/logger"
+	"github.com/EC-9624/0xec.dev/web/templates/admin"
+	"github.com/EC-9624/0xec.dev/web/templates/pages"
+)
+
+// searchGapsLimit is how many zero-result searches the report shows
+const searchGapsLimit = 50
+
+// Search shows the posts and public bookmarks matching the q parameter.
+// Without a query it shows just the search form. Searches are recorded for
+// the admin search report.
+func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	results, err := h.service.Search(ctx, r.URL.Query().Get("q"))
+	if err != nil {
+		logger.Error(ctx, "search failed", "error", err)
+		http.Error(w, "Search failed", http.StatusInternalServerError)
+		return
+	}
+
+	if results.Query != "" {
+		if err := h.service.RecordSearchQuery(ctx, results.Query, results.Total()); err != nil {
+			logger.Warn(ctx, "failed to record search query", "error", err)
+		}
+	}
+
+	render(w, r, pages.Search(results))
+}
+
+// AdminSearchGaps shows the most common searches that found nothing
+func (h *Handlers) AdminSearchGaps(w http.ResponseWriter, r *http.Request) {
+	gaps, err := h.service.GetZeroResultSearches(r.Context(), searchGapsLimit)
+	if err != nil {
+		http.Error(w, "Failed to load search report", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPage(w, r, admin.SearchGaps(gaps, h.config.SearchLogging), gaps)
+}
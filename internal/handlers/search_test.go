@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
+)
+
+func TestSearch_GroupsResults(t *testing.T) {
+	var recorded string
+	var recordedCount int
+	mock := &mockService{
+		searchFunc: func(ctx context.Context, query string) (service.SearchResults, error) {
+			return service.SearchResults{
+				Query:     query,
+				Posts:     []models.Post{{ID: 1, Title: "Tuning SQLite", Slug: "tuning-sqlite"}},
+				Bookmarks: []models.Bookmark{{ID: 7, URL: "https://sqlite.org/wal.html", Title: "SQLite WAL", IsPublic: true}},
+			}, nil
+		},
+		recordSearchQueryFunc: func(ctx context.Context, query string, resultCount int) error {
+			recorded, recordedCount = query, resultCount
+			return nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/search?q=sqlite", nil)
+	rec := httptest.NewRecorder()
+
+	h.Search(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, "Writing")
+	assertBodyContains(t, rec, "Bookmarks")
+	assertBodyContains(t, rec, `href="/posts/tuning-sqlite"`)
+	assertBodyContains(t, rec, `href="/go/7"`)
+	assertBodyContains(t, rec, "<mark>SQLite</mark>")
+	if recorded != "sqlite" || recordedCount != 2 {
+		t.Errorf("recorded search = (%q, %d), want (%q, 2)", recorded, recordedCount, "sqlite")
+	}
+}
+
+func TestSearch_EmptyQuery(t *testing.T) {
+	mock := &mockService{
+		recordSearchQueryFunc: func(ctx context.Context, query string, resultCount int) error {
+			t.Errorf("RecordSearchQuery called for an empty query")
+			return nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/search", nil)
+	rec := httptest.NewRecorder()
+
+	h.Search(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, `name="q"`)
+	if strings.Contains(rec.Body.String(), "search-section-title") {
+		t.Error("empty query rendered result sections")
+	}
+}
+
+func TestPruneSearchLog_UsesRetention(t *testing.T) {
+	var retention time.Duration
+	mock := &mockService{
+		pruneSearchQueriesFunc: func(ctx context.Context, r time.Duration) (int64, error) {
+			retention = r
+			return 3, nil
+		},
+	}
+	h := newTestHandlers(mock)
+	h.config.SearchLogRetentionDays = 90
+
+	if err := h.PruneSearchLog(context.Background()); err != nil {
+		t.Fatalf("PruneSearchLog() error = %v", err)
+	}
+	if retention != 90*24*time.Hour {
+		t.Errorf("retention = %v, want 90 days", retention)
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/EC-9624/0xec.dev/internal/logger"
+	"github.com/EC-9624/0xec.dev/internal/middleware"
+	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/web/templates/admin"
+)
+
+// AdminSessionsList shows the signed-in user's active sessions
+func (h *Handlers) AdminSessionsList(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+		return
+	}
+
+	sessions, err := h.service.ListSessionsForUser(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, "Failed to load sessions", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPage(w, r, admin.SessionsList(sessions, currentSessionID(r)), sessions)
+}
+
+// AdminSessionRevoke signs out one of the user's other sessions, identified
+// by its handle. The current session can't be revoked here; that's what
+// logout is for.
+func (h *Handlers) AdminSessionRevoke(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+		return
+	}
+
+	sessions, err := h.service.ListSessionsForUser(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, "Failed to load sessions", http.StatusInternalServerError)
+		return
+	}
+
+	handle := r.PathValue("handle")
+	var target *models.Session
+	for i := range sessions {
+		if sessions[i].Handle() == handle {
+			target = &sessions[i]
+			break
+		}
+	}
+	if target == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if target.ID == currentSessionID(r) {
+		http.Error(w, "Use Logout to end your current session", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RevokeSession(r.Context(), user.ID, target.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.NotFound(w, r)
+			return
+		}
+		logger.Error(r.Context(), "failed to revoke session", "error", err)
+		http.Error(w, "Failed to revoke session", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/sessions", http.StatusSeeOther)
+}
+
+// AdminSessionsRevokeOthers signs out every session except the current one
+func (h *Handlers) AdminSessionsRevokeOthers(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+		return
+	}
+
+	current := currentSessionID(r)
+	if current == "" {
+		http.Error(w, "No current session", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.service.RevokeAllOtherSessions(r.Context(), user.ID, current); err != nil {
+		logger.Error(r.Context(), "failed to revoke sessions", "error", err)
+		http.Error(w, "Failed to revoke sessions", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", "/admin/sessions")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/sessions", http.StatusSeeOther)
+}
+
+// AdminLogoutAllConfirm asks for confirmation before signing out of every
+// device. HTMX requests get just the confirmation partial.
+func (h *Handlers) AdminLogoutAllConfirm(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("HX-Request") == "true" {
+		render(w, r, admin.LogoutAllConfirm())
+		return
+	}
+	render(w, r, admin.LogoutAllPage())
+}
+
+// AdminLogoutAll signs out every session of the user, this one included
+func (h *Handlers) AdminLogoutAll(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+		return
+	}
+
+	if _, err := h.service.RevokeAllSessions(r.Context(), user.ID); err != nil {
+		logger.Error(r.Context(), "failed to revoke sessions", "error", err)
+		http.Error(w, "Failed to sign out everywhere", http.StatusInternalServerError)
+		return
+	}
+
+	h.clearSessionCookie(w)
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", "/admin/login")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+}
+
+// currentSessionID returns the session ID from the request's cookie
+func currentSessionID(r *http.Request) string {
+	if cookie, err := r.Cookie("session"); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/middleware"
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+// withUser returns req with user set as the authenticated user
+func withUser(req *http.Request, user *models.User) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, user))
+}
+
+func TestAdminSessionRevoke(t *testing.T) {
+	user := &models.User{ID: 1, Username: "admin"}
+	current := models.Session{ID: "current-session", UserID: 1}
+	other := models.Session{ID: "other-session", UserID: 1}
+
+	tests := []struct {
+		name        string
+		handle      string
+		wantStatus  int
+		wantRevoked string
+	}{
+		{"other session", other.Handle(), http.StatusOK, other.ID},
+		{"current session is protected", current.Handle(), http.StatusBadRequest, ""},
+		{"unknown handle", "0123456789abcdef", http.StatusNotFound, ""},
+		{"raw session ID is not a handle", other.ID, http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var revoked string
+			mock := &mockService{
+				listSessionsForUserFunc: func(ctx context.Context, userID int64) ([]models.Session, error) {
+					return []models.Session{current, other}, nil
+				},
+				revokeSessionFunc: func(ctx context.Context, userID int64, sessionID string) error {
+					revoked = sessionID
+					return nil
+				},
+			}
+			h := newTestHandlers(mock)
+
+			req := httptest.NewRequest(http.MethodDelete, "/admin/sessions/"+tt.handle, nil)
+			req.SetPathValue("handle", tt.handle)
+			req.Header.Set("HX-Request", "true")
+			req.AddCookie(&http.Cookie{Name: "session", Value: current.ID})
+			req = withUser(req, user)
+			rec := httptest.NewRecorder()
+
+			h.AdminSessionRevoke(rec, req)
+
+			assertStatus(t, rec, tt.wantStatus)
+			if revoked != tt.wantRevoked {
+				t.Errorf("revoked session = %q, want %q", revoked, tt.wantRevoked)
+			}
+		})
+	}
+}
+
+func TestAdminSessionsRevokeOthers(t *testing.T) {
+	var gotUserID int64
+	var gotCurrent string
+	mock := &mockService{
+		revokeAllOtherSessionsFunc: func(ctx context.Context, userID int64, currentSessionID string) (int64, error) {
+			gotUserID, gotCurrent = userID, currentSessionID
+			return 2, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/sessions/revoke-others", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "current-session"})
+	req = withUser(req, &models.User{ID: 7})
+	rec := httptest.NewRecorder()
+
+	h.AdminSessionsRevokeOthers(rec, req)
+
+	assertRedirect(t, rec, "/admin/sessions")
+	if gotUserID != 7 || gotCurrent != "current-session" {
+		t.Errorf("RevokeAllOtherSessions(%d, %q), want (7, %q)", gotUserID, gotCurrent, "current-session")
+	}
+}
+
+func TestAdminLogoutAll(t *testing.T) {
+	var gotUserID int64
+	mock := &mockService{
+		revokeAllSessionsFunc: func(ctx context.Context, userID int64) (int64, error) {
+			gotUserID = userID
+			return 3, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/logout-all", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "current-session"})
+	req = withUser(req, &models.User{ID: 7})
+	rec := httptest.NewRecorder()
+
+	h.AdminLogoutAll(rec, req)
+
+	assertRedirect(t, rec, "/admin/login")
+	if gotUserID != 7 {
+		t.Errorf("RevokeAllSessions(%d), want 7", gotUserID)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "session" || cookies[0].MaxAge >= 0 || !cookies[0].HttpOnly {
+		t.Errorf("cookies = %+v, want an expired HttpOnly session cookie", cookies)
+	}
+}
+
+func TestAdminLogoutAll_HTMX(t *testing.T) {
+	h := newTestHandlers(&mockService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/logout-all", nil)
+	req.Header.Set("HX-Request", "true")
+	req = withUser(req, &models.User{ID: 7})
+	rec := httptest.NewRecorder()
+
+	h.AdminLogoutAll(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if got := rec.Header().Get("HX-Redirect"); got != "/admin/login" {
+		t.Errorf("HX-Redirect = %q, want /admin/login", got)
+	}
+}
+
+func TestAdminSessionsList_ListsOwnSessions(t *testing.T) {
+	var gotUserID int64
+	mock := &mockService{
+		listSessionsForUserFunc: func(ctx context.Context, userID int64) ([]models.Session, error) {
+			gotUserID = userID
+			return []models.Session{{ID: "current-session", UserID: userID}}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/sessions", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "current-session"})
+	req = withUser(req, &models.User{ID: 3})
+	rec := httptest.NewRecorder()
+
+	h.AdminSessionsList(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	if gotUserID != 3 {
+		t.Errorf("ListSessionsForUser(%d), want 3", gotUserID)
+	}
+}
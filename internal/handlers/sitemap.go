@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// sitemapTTL is how long the generated sitemap is served from memory
+	sitemapTTL = 10 * time.Minute
+
+	// sitemapMaxURLs is the per-file URL limit from the sitemaps protocol
+	sitemapMaxURLs = 50000
+)
+
+// Sitemap structures
+type URLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+type SitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// xmlCache holds a generated document until it expires
+type xmlCache struct {
+	mu      sync.Mutex
+	body    []byte
+	expires time.Time
+}
+
+// get returns the cached document if it hasn't expired
+func (c *xmlCache) get(now time.Time) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.body == nil || now.After(c.expires) {
+		return nil, false
+	}
+	return c.body, true
+}
+
+// set stores a document for the given TTL
+func (c *xmlCache) set(body []byte, now time.Time, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.body = body
+	c.expires = now.Add(ttl)
+}
+
+// Sitemap serves sitemap.xml listing the public pages of the site
+func (h *Handlers) Sitemap(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	body, ok := h.sitemap.get(now)
+	if !ok {
+		var err error
+		body, err = h.buildSitemap(r.Context())
+		if err != nil {
+			http.Error(w, "Failed to build sitemap", http.StatusInternalServerError)
+			return
+		}
+		h.sitemap.set(body, now, sitemapTTL)
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Write(body)
+}
+
+// buildSitemap renders the sitemap XML from published posts and public collections
+func (h *Handlers) buildSitemap(ctx context.Context) ([]byte, error) {
+	posts, err := h.service.ListPosts(ctx, true, sitemapMaxURLs, 0)
+	if err != nil {
+		return nil, err
+	}
+	collections, err := h.service.ListCollections(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	lastmod := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format("2006-01-02")
+	}
+
+	// Section pages take the lastmod of their newest entry
+	var postsUpdated, collectionsUpdated time.Time
+	for _, post := range posts {
+		if post.UpdatedAt.After(postsUpdated) {
+			postsUpdated = post.UpdatedAt
+		}
+	}
+	for _, collection := range collections {
+		if collection.UpdatedAt.After(collectionsUpdated) {
+			collectionsUpdated = collection.UpdatedAt
+		}
+	}
+	siteUpdated := postsUpdated
+	if collectionsUpdated.After(siteUpdated) {
+		siteUpdated = collectionsUpdated
+	}
+
+	urls := []SitemapURL{
+		{Loc: h.config.BaseURL + "/", LastMod: lastmod(siteUpdated)},
+		{Loc: h.config.BaseURL + "/posts", LastMod: lastmod(postsUpdated)},
+	}
+	for _, post := range posts {
+		urls = append(urls, SitemapURL{
+			Loc:     h.config.BaseURL + "/posts/" + post.Slug,
+			LastMod: lastmod(post.UpdatedAt),
+		})
+	}
+	urls = append(urls, SitemapURL{Loc: h.config.BaseURL + "/bookmarks", LastMod: lastmod(collectionsUpdated)})
+	for _, collection := range collections {
+		urls = append(urls, SitemapURL{
+			Loc:     h.config.BaseURL + "/bookmarks/" + collection.Slug,
+			LastMod: lastmod(collection.UpdatedAt),
+		})
+	}
+	if len(urls) > sitemapMaxURLs {
+		urls = urls[:sitemapMaxURLs]
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(URLSet{URLs: urls}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RobotsTxt serves robots.txt, keeping crawlers out of admin pages and
+// pointing them at the sitemap
+func (h *Handlers) RobotsTxt(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte("User-agent: *\n" +
+		"Disallow: /admin\n" +
+		"Disallow: /htmx/\n" +
+		"\n" +
+		"Sitemap: " + h.config.BaseURL + "/sitemap.xml\n"))
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestSitemap(t *testing.T) {
+	updated := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+
+	mock := &mockService{
+		listPostsFunc: func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
+			if !publishedOnly {
+				t.Error("Sitemap should only list published posts")
+			}
+			return []models.Post{{ID: 1, Slug: "hello", UpdatedAt: updated}}, nil
+		},
+		listCollectionsFunc: func(ctx context.Context, publicOnly bool) ([]models.Collection, error) {
+			if !publicOnly {
+				t.Error("Sitemap should only list public collections")
+			}
+			return []models.Collection{{ID: 1, Slug: "reading", UpdatedAt: updated}}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+	h.config.BaseURL = "https://example.com"
+
+	req := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+	rec := httptest.NewRecorder()
+
+	h.Sitemap(rec, req)
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, "<loc>https://example.com/</loc>")
+	assertBodyContains(t, rec, "<loc>https://example.com/posts</loc>")
+	assertBodyContains(t, rec, "<loc>https://example.com/posts/hello</loc>")
+	assertBodyContains(t, rec, "<loc>https://example.com/bookmarks</loc>")
+	assertBodyContains(t, rec, "<loc>https://example.com/bookmarks/reading</loc>")
+	assertBodyContains(t, rec, "<lastmod>2024-05-10</lastmod>")
+}
+
+func TestSitemap_Cached(t *testing.T) {
+	calls := 0
+	mock := &mockService{
+		listPostsFunc: func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
+			calls++
+			return []models.Post{}, nil
+		},
+	}
+	h := newTestHandlers(mock)
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		h.Sitemap(rec, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+		assertStatus(t, rec, http.StatusOK)
+	}
+
+	if calls != 1 {
+		t.Errorf("ListPosts called %d times, want 1 (sitemap should be cached)", calls)
+	}
+}
+
+func TestSitemap_Error(t *testing.T) {
+	mock := &mockService{
+		listPostsFunc: func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
+			return nil, errors.New("database error")
+		},
+	}
+	h := newTestHandlers(mock)
+
+	rec := httptest.NewRecorder()
+	h.Sitemap(rec, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+
+	assertStatus(t, rec, http.StatusInternalServerError)
+}
+
+func TestRobotsTxt(t *testing.T) {
+	h := newTestHandlers(&mockService{})
+	h.config.BaseURL = "https://example.com"
+
+	rec := httptest.NewRecorder()
+	h.RobotsTxt(rec, httptest.NewRequest(http.MethodGet, "/robots.txt", nil))
+
+	assertStatus(t, rec, http.StatusOK)
+	assertBodyContains(t, rec, "Disallow: /admin")
+	assertBodyContains(t, rec, "Sitemap: https://example.com/sitemap.xml")
+}
@@ -1,25 +1,29 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 
+	"github.com/EC-9624/0xec.dev/internal/logger"
 	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
 	"github.com/EC-9624/0xec.dev/web/templates/admin"
 	"github.com/EC-9624/0xec.dev/web/templates/components"
 )
 
 // AdminTagsList handles the admin tags listing
 func (h *Handlers) AdminTagsList(w http.ResponseWriter, r *http.Request) {
-	tags, err := h.service.GetTagsWithCounts(r.Context())
+	tags, err := h.service.GetTagsWithCounts(r.Context(), false)
 	if err != nil {
 		http.Error(w, "Failed to load tags", http.StatusInternalServerError)
 		return
 	}
 
-	render(w, r, admin.TagsList(tags))
+	h.renderPage(w, r, admin.TagsList(tags), tags)
 }
 
 // AdminTagDelete handles deleting a tag
@@ -44,26 +48,67 @@ func (h *Handlers) AdminTagDelete(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/admin/tags", http.StatusSeeOther)
 }
 
-// AdminTagCreateInline handles creating a tag via AJAX and returns JSON
-func (h *Handlers) AdminTagCreateInline(w http.ResponseWriter, r *http.Request) {
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
+// AdminTagMerge merges the source tag into the target tag
+func (h *Handlers) AdminTagMerge(w http.ResponseWriter, r *http.Request) {
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Invalid form data")
+		return
+	}
+
+	sourceID := parseFormInt64(r, "source_id")
+	targetID := parseFormInt64(r, "target_id")
+	if sourceID == nil || targetID == nil {
+		http.Error(w, "Choose a tag to merge and a tag to merge it into", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if _, err := h.service.MergeTags(ctx, *sourceID, *targetID); err != nil {
+		if errors.Is(err, service.ErrMergeTagIntoItself) {
+			http.Error(w, "Cannot merge a tag into itself", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Tag not found", http.StatusNotFound)
+			return
+		}
+		logger.Error(ctx, "failed to merge tags", "error", err, "source_id", *sourceID, "target_id", *targetID)
+		http.Error(w, "Failed to merge tags", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", "/admin/tags")
+		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	name := r.FormValue("name")
-	slug := r.FormValue("slug")
+	http.Redirect(w, r, "/admin/tags", http.StatusSeeOther)
+}
 
-	if name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+// AdminTagCreateInline handles creating a tag via AJAX and returns JSON
+func (h *Handlers) AdminTagCreateInline(w http.ResponseWriter, r *http.Request) {
+	if err := h.parseForm(w, r); err != nil {
+		h.writeFormError(w, r, err, "Invalid form data")
 		return
 	}
 
+	input := models.CreateTagInput{
+		Name:  r.FormValue("name"),
+		Slug:  r.FormValue("slug"),
+		Color: r.FormValue("color"),
+	}
+	if formErrors := input.Validate(); formErrors != nil {
+		for _, field := range []string{"name", "slug", "color"} {
+			if msg := formErrors.GetField(field); msg != "" {
+				http.Error(w, msg, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
 	// Create the tag
-	tag, err := h.service.CreateTag(r.Context(), models.CreateTagInput{
-		Name: name,
-		Slug: slug,
-	})
+	tag, err := h.service.CreateTag(r.Context(), input)
 	if err != nil {
 		http.Error(w, "Failed to create tag", http.StatusInternalServerError)
 		return
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/EC-9624/0xec.dev/internal/logger"
+	"github.com/EC-9624/0xec.dev/internal/models"
 	"github.com/google/uuid"
 )
 
@@ -23,10 +25,7 @@ type UploadErrorResponse struct {
 	Error string `json:"error"`
 }
 
-const (
-	maxUploadSize = 10 << 20 // 10 MB
-	uploadDir     = "./web/static/uploads"
-)
+const uploadDir = "./web/static/uploads"
 
 var allowedMimeTypes = map[string]string{
 	"image/jpeg": ".jpg",
@@ -40,13 +39,14 @@ var allowedMimeTypes = map[string]string{
 func (h *Handlers) AdminUploadImage(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Limit request body size
-	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
-
-	// Parse multipart form
-	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+	// Parse the form under the shared MAX_UPLOAD_MB cap
+	if err := h.parseForm(w, r); err != nil {
 		logger.Error(ctx, "failed to parse multipart form", "error", err)
-		writeUploadError(w, "File too large. Maximum size is 10MB.", http.StatusBadRequest)
+		if isBodyTooLarge(err) {
+			writeUploadError(w, h.bodyTooLargeMessage(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		writeUploadError(w, "Invalid upload.", http.StatusBadRequest)
 		return
 	}
 
@@ -177,3 +177,55 @@ func writeUploadError(w http.ResponseWriter, message string, status int) {
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(UploadErrorResponse{Error: message})
 }
+
+// coverImageField is the form field cover image files are uploaded in
+const coverImageField = "cover_image_file"
+
+// Messages for rejected cover image uploads, shown on the cover_image field
+var (
+	coverTooLargeMessage = fmt.Sprintf("Cover image must be %d MB or smaller", models.MaxCoverImageSize>>20)
+	coverNotImageMessage = "Cover image must be a JPEG, PNG, GIF or WebP file"
+	coverNotSavedMessage = "Failed to store cover image. Please try again."
+)
+
+// coverUpload is a cover image file posted with a form
+type coverUpload struct {
+	MimeType string
+	Data     []byte
+}
+
+// readCoverUpload returns the cover image file posted with a multipart form,
+// or nil when the form isn't multipart or no file was chosen. The form must
+// already be parsed by parseForm, which enforces the request size cap; the
+// file itself must also fit models.MaxCoverImageSize. A rejected file is
+// reported as a message for the cover_image field.
+func readCoverUpload(r *http.Request) (*coverUpload, string) {
+	if r.MultipartForm == nil {
+		return nil, ""
+	}
+
+	file, header, err := r.FormFile(coverImageField)
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, ""
+	}
+	if err != nil {
+		return nil, coverNotImageMessage
+	}
+	defer file.Close()
+
+	if header.Size > models.MaxCoverImageSize {
+		return nil, coverTooLargeMessage
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, coverNotSavedMessage
+	}
+
+	mimeType := http.DetectContentType(data)
+	if _, ok := allowedMimeTypes[mimeType]; !ok {
+		return nil, coverNotImageMessage
+	}
+
+	return &coverUpload{MimeType: mimeType, Data: data}, ""
+}
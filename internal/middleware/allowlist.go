@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/EC-9624/0xec.dev/internal/logger"
+)
+
+// ParseCIDRs parses allowlist entries. Each entry is a CIDR range such as
+// "203.0.113.0/24" or "2001:db8::/32", or a single address.
+func ParseCIDRs(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid IP %q: %w", entry, err)
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", entry, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// IPAllowlist rejects requests from client IPs outside cidrs with 403
+// Forbidden. An empty list allows everyone. Entries must be valid for
+// ParseCIDRs; config validation rejects bad ones at startup, so an invalid
+// entry here panics.
+func IPAllowlist(cidrs []string) func(http.Handler) http.Handler {
+	prefixes, err := ParseCIDRs(cidrs)
+	if err != nil {
+		panic("middleware.IPAllowlist: " + err.Error())
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(prefixes) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addr, ok := allowlistAddr(r)
+			if !ok || !containsAddr(prefixes, addr) {
+				logger.Warn(r.Context(), "request blocked by IP allowlist", "ip", addr.String(), "path", r.URL.Path)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// allowlistAddr returns the client address to check
+func allowlistAddr(r *http.Request) (netip.Addr, bool) {
+	addr, err := netip.ParseAddr(ClientIP(r))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.WithZone("").Unmap(), true
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func allowlistStatus(t *testing.T, cidrs []string, trustProxy bool, remoteAddr string, headers map[string]string) int {
+	t.Helper()
+	handler := ResolveClientIP(trustProxy)(IPAllowlist(cidrs)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.RemoteAddr = remoteAddr
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestIPAllowlist(t *testing.T) {
+	cidrs := []string{"203.0.113.0/24", "198.51.100.7", "2001:db8::/32"}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		want       int
+	}{
+		{"IPv4 in range", "203.0.113.42:5000", http.StatusOK},
+		{"single IPv4", "198.51.100.7:5000", http.StatusOK},
+		{"IPv6 in range", "[2001:db8::1]:5000", http.StatusOK},
+		{"IPv4-mapped IPv6", "[::ffff:203.0.113.9]:5000", http.StatusOK},
+		{"IPv4 outside", "192.0.2.1:5000", http.StatusForbidden},
+		{"IPv6 outside", "[2001:db9::1]:5000", http.StatusForbidden},
+		{"unparseable", "not-an-ip", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allowlistStatus(t, cidrs, false, tt.remoteAddr, nil); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIPAllowlist_EmptyAllowsAll(t *testing.T) {
+	if got := allowlistStatus(t, nil, false, "192.0.2.1:5000", nil); got != http.StatusOK {
+		t.Errorf("status = %d, want 200 with no allowlist", got)
+	}
+}
+
+func TestIPAllowlist_ProxyHeaders(t *testing.T) {
+	cidrs := []string{"203.0.113.0/24"}
+	proxy := "10.0.0.2:5000"
+
+	t.Run("untrusted ignores spoofed header", func(t *testing.T) {
+		got := allowlistStatus(t, cidrs, false, proxy, map[string]string{"X-Forwarded-For": "203.0.113.5"})
+		if got != http.StatusForbidden {
+			t.Errorf("status = %d, want 403", got)
+		}
+	})
+
+	t.Run("trusted uses the proxy's hop", func(t *testing.T) {
+		got := allowlistStatus(t, cidrs, true, proxy, map[string]string{"X-Forwarded-For": "192.0.2.1, 203.0.113.5"})
+		if got != http.StatusOK {
+			t.Errorf("status = %d, want 200", got)
+		}
+		// A client-supplied first entry doesn't get it through
+		got = allowlistStatus(t, cidrs, true, proxy, map[string]string{"X-Forwarded-For": "203.0.113.5, 192.0.2.1"})
+		if got != http.StatusForbidden {
+			t.Errorf("spoofed status = %d, want 403", got)
+		}
+	})
+}
+
+func TestParseCIDRs_Invalid(t *testing.T) {
+	for _, entry := range []string{"203.0.113.0/33", "example.com", "10.0.0.1/abc"} {
+		if _, err := ParseCIDRs([]string{entry}); err == nil {
+			t.Errorf("ParseCIDRs(%q) error = nil, want an error", entry)
+		}
+	}
+}
@@ -3,7 +3,9 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"time"
 
+	"github.com/EC-9624/0xec.dev/internal/logger"
 	"github.com/EC-9624/0xec.dev/internal/models"
 )
 
@@ -11,15 +13,25 @@ type contextKey string
 
 const UserContextKey contextKey = "user"
 
+// sessionTouchInterval is how stale a session's last-seen time may get
+// before a request writes it again, so busy sessions don't cost a database
+// write per request
+const sessionTouchInterval = time.Minute
+
 // AuthService defines the interface for authentication-related service methods.
 // This interface allows for easier testing by enabling mock implementations.
 type AuthService interface {
 	GetSession(ctx context.Context, sessionID string) (*models.Session, error)
 	GetUserByID(ctx context.Context, id int64) (*models.User, error)
+	DeleteSession(ctx context.Context, sessionID string) error
+	TouchSession(ctx context.Context, sessionID string, at time.Time) error
 }
 
-// Auth middleware checks for valid session
-func Auth(svc AuthService) func(http.Handler) http.Handler {
+// Auth middleware checks for valid session. Sessions unused for longer than
+// idleTimeout are ended even before they expire; zero disables the idle
+// check and leaves only the absolute expiry. "Remember me" sessions are
+// meant to survive long gaps, so only their expiry applies.
+func Auth(svc AuthService, idleTimeout time.Duration) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
@@ -32,12 +44,19 @@ func Auth(svc AuthService) func(http.Handler) http.Handler {
 			session, err := svc.GetSession(ctx, cookie.Value)
 			if err != nil {
 				// Invalid or expired session
-				http.SetCookie(w, &http.Cookie{
-					Name:   "session",
-					Value:  "",
-					Path:   "/",
-					MaxAge: -1,
-				})
+				clearSessionCookie(w)
+				http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+				return
+			}
+
+			now := time.Now()
+			idle := session.IdleFor(now)
+			if idleTimeout > 0 && idle > idleTimeout && !session.Remembered() {
+				// Abandoned session: end it server-side as well
+				if err := svc.DeleteSession(ctx, session.ID); err != nil {
+					logger.Error(ctx, "failed to delete idle session", "error", err)
+				}
+				clearSessionCookie(w)
 				http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
 				return
 			}
@@ -48,9 +67,26 @@ func Auth(svc AuthService) func(http.Handler) http.Handler {
 				return
 			}
 
+			// Slide the idle window forward
+			if idle >= sessionTouchInterval {
+				if err := svc.TouchSession(ctx, session.ID, now); err != nil {
+					logger.Error(ctx, "failed to update session last seen", "error", err)
+				}
+			}
+
 			// Add user to context
 			ctx = context.WithValue(ctx, UserContextKey, user)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// clearSessionCookie tells the browser to drop the session cookie
+func clearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:   "session",
+		Value:  "",
+		Path:   "/",
+		MaxAge: -1,
+	})
+}
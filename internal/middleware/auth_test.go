@@ -13,8 +13,10 @@ import (
 
 // mockAuthService is a test double for AuthService
 type mockAuthService struct {
-	getSessionFunc  func(ctx context.Context, sessionID string) (*models.Session, error)
-	getUserByIDFunc func(ctx context.Context, id int64) (*models.User, error)
+	getSessionFunc    func(ctx context.Context, sessionID string) (*models.Session, error)
+	getUserByIDFunc   func(ctx context.Context, id int64) (*models.User, error)
+	deleteSessionFunc func(ctx context.Context, sessionID string) error
+	touchSessionFunc  func(ctx context.Context, sessionID string, at time.Time) error
 }
 
 func (m *mockAuthService) GetSession(ctx context.Context, sessionID string) (*models.Session, error) {
@@ -31,10 +33,24 @@ func (m *mockAuthService) GetUserByID(ctx context.Context, id int64) (*models.Us
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockAuthService) DeleteSession(ctx context.Context, sessionID string) error {
+	if m.deleteSessionFunc != nil {
+		return m.deleteSessionFunc(ctx, sessionID)
+	}
+	return nil
+}
+
+func (m *mockAuthService) TouchSession(ctx context.Context, sessionID string, at time.Time) error {
+	if m.touchSessionFunc != nil {
+		return m.touchSessionFunc(ctx, sessionID, at)
+	}
+	return nil
+}
+
 func TestAuth_NoSessionCookie(t *testing.T) {
 	mock := &mockAuthService{}
 
-	handler := Auth(mock)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	handler := Auth(mock, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("Handler should not be called without session cookie")
 	}))
 
@@ -61,7 +77,7 @@ func TestAuth_InvalidSession(t *testing.T) {
 		},
 	}
 
-	handler := Auth(mock)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	handler := Auth(mock, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("Handler should not be called with invalid session")
 	}))
 
@@ -107,7 +123,7 @@ func TestAuth_ExpiredSession(t *testing.T) {
 		},
 	}
 
-	handler := Auth(mock)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	handler := Auth(mock, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("Handler should not be called with expired session")
 	}))
 
@@ -136,7 +152,7 @@ func TestAuth_UserNotFound(t *testing.T) {
 		},
 	}
 
-	handler := Auth(mock)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	handler := Auth(mock, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("Handler should not be called when user is not found")
 	}))
 
@@ -179,7 +195,7 @@ func TestAuth_ValidSession(t *testing.T) {
 	}
 
 	var capturedUser *models.User
-	handler := Auth(mock)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	handler := Auth(mock, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		user, ok := r.Context().Value(UserContextKey).(*models.User)
 		if ok {
 			capturedUser = user
@@ -228,7 +244,7 @@ func TestAuth_SessionIDPassedCorrectly(t *testing.T) {
 		},
 	}
 
-	handler := Auth(mock)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	handler := Auth(mock, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}))
 
@@ -261,7 +277,7 @@ func TestAuth_UserIDPassedCorrectly(t *testing.T) {
 		},
 	}
 
-	handler := Auth(mock)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	handler := Auth(mock, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}))
 
@@ -321,3 +337,136 @@ func TestUserContextKey_ValidUser(t *testing.T) {
 		t.Errorf("Username = %q, want %q", user.Username, testUser.Username)
 	}
 }
+
+func TestAuth_IdleSessionRejected(t *testing.T) {
+	var deleted string
+	mock := &mockAuthService{
+		getSessionFunc: func(ctx context.Context, sessionID string) (*models.Session, error) {
+			return &models.Session{
+				ID:         sessionID,
+				UserID:     1,
+				ExpiresAt:  time.Now().Add(24 * time.Hour),
+				LastSeenAt: time.Now().Add(-3 * time.Hour),
+			}, nil
+		},
+		getUserByIDFunc: func(ctx context.Context, id int64) (*models.User, error) {
+			return &models.User{ID: id}, nil
+		},
+		deleteSessionFunc: func(ctx context.Context, sessionID string) error {
+			deleted = sessionID
+			return nil
+		},
+	}
+
+	handler := Auth(mock, 2*time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler should not be called with an idle session")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "idle-session"})
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/admin/login" {
+		t.Errorf("got %d to %q, want redirect to /admin/login", rec.Code, rec.Header().Get("Location"))
+	}
+	if deleted != "idle-session" {
+		t.Errorf("deleted session = %q, want idle-session", deleted)
+	}
+
+	var cleared bool
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == "session" && c.MaxAge < 0 {
+			cleared = true
+		}
+	}
+	if !cleared {
+		t.Error("session cookie not cleared")
+	}
+}
+
+func TestAuth_RememberedSessionSurvivesIdle(t *testing.T) {
+	mock := &mockAuthService{
+		getSessionFunc: func(ctx context.Context, sessionID string) (*models.Session, error) {
+			return &models.Session{
+				ID:         sessionID,
+				UserID:     1,
+				Duration:   30 * 24 * time.Hour,
+				ExpiresAt:  time.Now().Add(20 * 24 * time.Hour),
+				LastSeenAt: time.Now().Add(-3 * 24 * time.Hour),
+			}, nil
+		},
+		getUserByIDFunc: func(ctx context.Context, id int64) (*models.User, error) {
+			return &models.User{ID: id}, nil
+		},
+		deleteSessionFunc: func(ctx context.Context, sessionID string) error {
+			t.Error("remembered session was deleted")
+			return nil
+		},
+	}
+
+	called := false
+	handler := Auth(mock, 2*time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "remembered-session"})
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !called {
+		t.Error("remembered session was rejected after the idle window")
+	}
+}
+
+func TestAuth_SlidesLastSeen(t *testing.T) {
+	tests := []struct {
+		name      string
+		idle      time.Duration
+		wantTouch bool
+	}{
+		{"recently touched", 10 * time.Second, false},
+		{"stale last seen", 5 * time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var touched bool
+			mock := &mockAuthService{
+				getSessionFunc: func(ctx context.Context, sessionID string) (*models.Session, error) {
+					return &models.Session{
+						ID:         sessionID,
+						UserID:     1,
+						ExpiresAt:  time.Now().Add(24 * time.Hour),
+						LastSeenAt: time.Now().Add(-tt.idle),
+					}, nil
+				},
+				getUserByIDFunc: func(ctx context.Context, id int64) (*models.User, error) {
+					return &models.User{ID: id}, nil
+				},
+				touchSessionFunc: func(ctx context.Context, sessionID string, at time.Time) error {
+					touched = true
+					return nil
+				},
+			}
+
+			handler := Auth(mock, 2*time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			req.AddCookie(&http.Cookie{Name: "session", Value: "active-session"})
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Status = %d, want 200", rec.Code)
+			}
+			if touched != tt.wantTouch {
+				t.Errorf("TouchSession called = %v, want %v", touched, tt.wantTouch)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+)
+
+// TrailingSlash is a CanonicalPath policy for trailing slashes
+type TrailingSlash string
+
+const (
+	// TrailingSlashStrip redirects /path/ to /path. It is the default.
+	TrailingSlashStrip TrailingSlash = "strip"
+	// TrailingSlashAdd redirects /path to /path/, except for file-like
+	// paths such as /feed.xml
+	TrailingSlashAdd TrailingSlash = "add"
+	// TrailingSlashOff turns CanonicalPath off entirely
+	TrailingSlashOff TrailingSlash = "off"
+)
+
+// canonicalExemptPrefixes are never redirected: static files and HTMX
+// partials aren't linked to directly, stored images and /go/ links are
+// embedded in feeds and OG tags as-is, the API is for clients rather than
+// browsers, and the admin redirects /admin/ to /admin itself
+var canonicalExemptPrefixes = []string{"/static/", "/htmx/", "/admin/", "/images/", "/go/", "/api/"}
+
+// canonicalExemptPaths are never redirected either: probes expect a plain
+// 200 from the exact path they were configured with
+var canonicalExemptPaths = []string{"/health", "/live", "/ready"}
+
+// CanonicalPath 301-redirects GET and HEAD requests to the canonical form
+// of their path: duplicate slashes collapsed and the trailing slash
+// stripped or added per policy. The query string is kept. The root path
+// and canonicalExemptPrefixes are left alone, as are other methods, since
+// browsers replay a 301 as GET. An unknown policy acts as
+// TrailingSlashStrip.
+//
+// Routes are registered without a trailing slash, so under
+// TrailingSlashAdd the slash of a canonical path is dropped again before
+// the request is passed on.
+func CanonicalPath(policy TrailingSlash) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if policy == TrailingSlashOff {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := r.URL.EscapedPath()
+			if current == "/" || canonicalExempt(current) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			canonical := canonicalPath(current, policy)
+			if canonical != current && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+				target := canonical
+				if r.URL.RawQuery != "" {
+					target += "?" + r.URL.RawQuery
+				}
+				http.Redirect(w, r, target, http.StatusMovedPermanently)
+				return
+			}
+
+			if policy == TrailingSlashAdd && canonical == current && strings.HasSuffix(current, "/") {
+				u := *r.URL
+				u.Path = strings.TrimSuffix(u.Path, "/")
+				u.RawPath = strings.TrimSuffix(u.RawPath, "/")
+				r.URL = &u
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// canonicalExempt reports whether the path p is never redirected
+func canonicalExempt(p string) bool {
+	if slices.Contains(canonicalExemptPaths, p) {
+		return true
+	}
+	for _, prefix := range canonicalExemptPrefixes {
+		// A prefix also exempts its own path without the slash
+		if p == strings.TrimSuffix(prefix, "/") || strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalPath returns the canonical form of the escaped path p under
+// policy
+func canonicalPath(p string, policy TrailingSlash) string {
+	for strings.Contains(p, "//") {
+		p = strings.ReplaceAll(p, "//", "/")
+	}
+	trimmed := strings.TrimSuffix(p, "/")
+	if trimmed == "" {
+		return "/"
+	}
+	if policy == TrailingSlashAdd {
+		// Leave file-like paths (feeds, robots.txt) without a slash
+		if strings.Contains(path.Base(trimmed), ".") {
+			return trimmed
+		}
+		return trimmed + "/"
+	}
+	return trimmed
+}
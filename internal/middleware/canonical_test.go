@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCanonicalPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy TrailingSlash
+		method string
+		target string
+		want   string // redirect location, or "" to pass through
+	}{
+		{"strip removes trailing slash", TrailingSlashStrip, http.MethodGet, "/posts/", "/posts"},
+		{"strip keeps query", TrailingSlashStrip, http.MethodGet, "/bookmarks/?sort=title", "/bookmarks?sort=title"},
+		{"strip leaves canonical path", TrailingSlashStrip, http.MethodGet, "/posts", ""},
+		{"add appends trailing slash", TrailingSlashAdd, http.MethodGet, "/posts", "/posts/"},
+		{"add leaves canonical path", TrailingSlashAdd, http.MethodGet, "/posts/", ""},
+		{"add skips file-like paths", TrailingSlashAdd, http.MethodGet, "/feed.xml", ""},
+		{"add strips slash from file-like paths", TrailingSlashAdd, http.MethodGet, "/feed.xml/", "/feed.xml"},
+		{"unknown policy strips", TrailingSlash("sideways"), http.MethodGet, "/tags/", "/tags"},
+		{"root is exempt when stripping", TrailingSlashStrip, http.MethodGet, "/", ""},
+		{"root is exempt when adding", TrailingSlashAdd, http.MethodGet, "/", ""},
+		{"collapses duplicate slashes", TrailingSlashStrip, http.MethodGet, "/posts//hello", "/posts/hello"},
+		{"collapses and strips", TrailingSlashStrip, http.MethodGet, "//posts///hello//", "/posts/hello"},
+		{"collapses and adds", TrailingSlashAdd, http.MethodGet, "/posts//hello", "/posts/hello/"},
+		{"collapses repeated root", TrailingSlashStrip, http.MethodGet, "///", "/"},
+		{"static is exempt", TrailingSlashStrip, http.MethodGet, "/static/css/", ""},
+		{"htmx partials are exempt", TrailingSlashAdd, http.MethodGet, "/htmx/bookmarks", ""},
+		{"admin is exempt", TrailingSlashStrip, http.MethodGet, "/admin/", ""},
+		{"images are exempt when adding", TrailingSlashAdd, http.MethodGet, "/images/5", ""},
+		{"go links are exempt when adding", TrailingSlashAdd, http.MethodGet, "/go/12", ""},
+		{"api is exempt when adding", TrailingSlashAdd, http.MethodGet, "/api/bookmarks", ""},
+		{"bare api is exempt when adding", TrailingSlashAdd, http.MethodGet, "/api/", ""},
+		{"health probe is exempt when adding", TrailingSlashAdd, http.MethodGet, "/health", ""},
+		{"live probe is exempt when adding", TrailingSlashAdd, http.MethodGet, "/live", ""},
+		{"ready probe is exempt when adding", TrailingSlashAdd, http.MethodGet, "/ready", ""},
+		{"probe with slash still stripped", TrailingSlashStrip, http.MethodGet, "/health/", "/health"},
+		{"HEAD is redirected", TrailingSlashStrip, http.MethodHead, "/posts/", "/posts"},
+		{"POST is not redirected", TrailingSlashStrip, http.MethodPost, "/posts/", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := CanonicalPath(tt.policy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if tt.want == "" {
+				if !called {
+					t.Errorf("redirected to %q, want pass through", rec.Header().Get("Location"))
+				}
+				return
+			}
+			if called {
+				t.Fatal("passed through, want redirect")
+			}
+			if rec.Code != http.StatusMovedPermanently {
+				t.Errorf("status = %d, want 301", rec.Code)
+			}
+			if got := rec.Header().Get("Location"); got != tt.want {
+				t.Errorf("Location = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalPath_AddRoutesWithoutSlash(t *testing.T) {
+	var routed string
+	handler := CanonicalPath(TrailingSlashAdd)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		routed = r.URL.Path
+	}))
+
+	for target, want := range map[string]string{
+		"/posts/hello/": "/posts/hello",
+		"/feed.xml":     "/feed.xml",
+		"/static/css/":  "/static/css/",
+	} {
+		routed = ""
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+		if routed != want {
+			t.Errorf("%s routed as %q, want %q", target, routed, want)
+		}
+	}
+}
+
+func TestCanonicalPath_Off(t *testing.T) {
+	called := false
+	handler := CanonicalPath(TrailingSlashOff)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/posts//", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !called {
+		t.Error("off policy redirected")
+	}
+}
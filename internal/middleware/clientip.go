@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type clientIPContextKey struct{}
+
+// ResolveClientIP works out the client IP address once per request and
+// stores it for ClientIP. Only set trustProxy when the server is reachable
+// solely through a reverse proxy that sets X-Forwarded-For; otherwise
+// clients can spoof the header.
+//
+// Behind a trusted proxy, X-Forwarded-For is read right to left: each proxy
+// appends the address it received the request from, so the rightmost hop
+// that isn't one of our own (loopback or private) proxies is the client.
+// Anything to its left was supplied by the client and can't be trusted.
+func ResolveClientIP(trustProxy bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := remoteIP(r)
+			if trustProxy {
+				ip = proxiedIP(r, ip)
+			}
+			ctx := context.WithValue(r.Context(), clientIPContextKey{}, ip)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ClientIP returns the client IP address that ResolveClientIP stored for
+// the request. Without ResolveClientIP it is the connection's address, so
+// clients talking to the server directly can't pick their own IP.
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPContextKey{}).(string); ok {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+// remoteIP returns the address the request's connection came from
+func remoteIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+// proxiedIP returns the client IP named by a trusted proxy's headers, or
+// fallback if they name none
+func proxiedIP(r *http.Request, fallback string) string {
+	if ip := forwardedFor(r.Header.Get("X-Forwarded-For")); ip != "" {
+		return ip
+	}
+	if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return addr.String()
+	}
+	return fallback
+}
+
+// forwardedFor returns the rightmost untrusted hop in an X-Forwarded-For
+// header, or the leftmost valid hop if every hop is an internal proxy.
+// Returns "" if the header holds no valid address.
+func forwardedFor(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	hops := strings.Split(header, ",")
+	var leftmost string
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// A garbled hop means everything to its left is unreliable
+			break
+		}
+		addr = addr.Unmap()
+		if !isInternalProxy(addr) {
+			return addr.String()
+		}
+		leftmost = addr.String()
+	}
+	return leftmost
+}
+
+// isInternalProxy reports whether addr belongs to a proxy on our side of
+// the network rather than to a client
+func isInternalProxy(addr netip.Addr) bool {
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast()
+}
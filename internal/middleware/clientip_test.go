@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		trust   bool
+		remote  string
+		headers map[string]string
+		want    string
+	}{
+		{"direct", false, "192.0.2.1:1234", nil, "192.0.2.1"},
+		{"untrusted ignores XFF", false, "192.0.2.1:1234", map[string]string{"X-Forwarded-For": "203.0.113.5"}, "192.0.2.1"},
+		{"untrusted ignores X-Real-IP", false, "192.0.2.1:1234", map[string]string{"X-Real-IP": "203.0.113.5"}, "192.0.2.1"},
+		{"trusted single hop", true, "10.0.0.2:1234", map[string]string{"X-Forwarded-For": "203.0.113.5"}, "203.0.113.5"},
+		{"trusted ignores spoofed left hops", true, "10.0.0.2:1234", map[string]string{"X-Forwarded-For": "1.2.3.4, 203.0.113.5"}, "203.0.113.5"},
+		{"trusted skips internal proxies", true, "127.0.0.1:1234", map[string]string{"X-Forwarded-For": "1.2.3.4, 203.0.113.5, 10.0.0.3"}, "203.0.113.5"},
+		{"trusted all internal", true, "127.0.0.1:1234", map[string]string{"X-Forwarded-For": "192.168.1.10, 10.0.0.3"}, "192.168.1.10"},
+		{"trusted IPv6", true, "[::1]:1234", map[string]string{"X-Forwarded-For": "2001:db8::7"}, "2001:db8::7"},
+		{"trusted garbled XFF falls back", true, "10.0.0.2:1234", map[string]string{"X-Forwarded-For": "203.0.113.5, nonsense"}, "10.0.0.2"},
+		{"trusted X-Real-IP", true, "10.0.0.2:1234", map[string]string{"X-Real-IP": "203.0.113.5"}, "203.0.113.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			var got string
+			ResolveClientIP(tt.trust)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ClientIP(r)
+			})).ServeHTTP(httptest.NewRecorder(), req)
+			if got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIP_WithoutResolver(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.5")
+	if got := ClientIP(req); got != "192.0.2.1" {
+		t.Errorf("ClientIP() = %q, want the connection address", got)
+	}
+}
+
+func TestLimit_BehindProxy(t *testing.T) {
+	do := func(handler http.Handler, xff string) int {
+		req := httptest.NewRequest(http.MethodPost, "/admin/login", nil)
+		req.RemoteAddr = "10.0.0.2:1234"
+		req.Header.Set("X-Forwarded-For", xff)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("trusted budgets each client", func(t *testing.T) {
+		handler := ResolveClientIP(true)(NewRateLimiter(1.0/60.0, 1).Limit(ok))
+
+		if got := do(handler, "203.0.113.5"); got != http.StatusOK {
+			t.Fatalf("first client: status = %d, want 200", got)
+		}
+		if got := do(handler, "198.51.100.9"); got != http.StatusOK {
+			t.Errorf("second client: status = %d, want 200", got)
+		}
+		// Prepending a fake hop doesn't buy a fresh budget
+		if got := do(handler, "1.2.3.4, 203.0.113.5"); got != http.StatusTooManyRequests {
+			t.Errorf("spoofed retry: status = %d, want 429", got)
+		}
+	})
+
+	t.Run("untrusted can't rotate spoofed headers", func(t *testing.T) {
+		handler := ResolveClientIP(false)(NewRateLimiter(1.0/60.0, 1).Limit(ok))
+
+		if got := do(handler, "203.0.113.5"); got != http.StatusOK {
+			t.Fatalf("first request: status = %d, want 200", got)
+		}
+		if got := do(handler, "198.51.100.9"); got != http.StatusTooManyRequests {
+			t.Errorf("spoofed second request: status = %d, want 429", got)
+		}
+	})
+}
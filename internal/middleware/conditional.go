@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ConditionalGet buffers successful GET/HEAD responses and answers
+// If-None-Match and If-Modified-Since with 304 Not Modified.
+//
+// If the handler doesn't set an ETag, one is derived from the rendered
+// body. The ETag is weak because Compress may re-encode the body after
+// this middleware runs. Last-Modified is only used when the handler sets
+// it, and If-None-Match takes precedence over If-Modified-Since.
+//
+// Only wrap pages that are safe to revalidate; admin pages and HTMX
+// partials should not use it.
+func ConditionalGet(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buf := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+
+		if buf.status != http.StatusOK {
+			w.WriteHeader(buf.status)
+			w.Write(buf.body.Bytes())
+			return
+		}
+
+		h := w.Header()
+		if h.Get("ETag") == "" {
+			sum := sha256.Sum256(buf.body.Bytes())
+			h.Set("ETag", `W/"`+hex.EncodeToString(sum[:8])+`"`)
+		}
+
+		if notModified(r, h) {
+			h.Del("Content-Type")
+			h.Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.body.Bytes())
+	})
+}
+
+// bufferedResponse holds the status and body until the handler returns
+type bufferedResponse struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.wroteHeader {
+		return
+	}
+	b.status = status
+	b.wroteHeader = true
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(p)
+}
+
+// notModified reports whether the request's validators match the response
+func notModified(r *http.Request, h http.Header) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, h.Get("ETag"))
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	lm := h.Get("Last-Modified")
+	if ims == "" || lm == "" {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lm)
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(since)
+}
+
+// etagMatches does the weak comparison If-None-Match calls for
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
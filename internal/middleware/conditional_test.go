@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var conditionalModified = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func conditionalHandler(status int, body string) http.Handler {
+	return ConditionalGet(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Last-Modified", conditionalModified.Format(http.TimeFormat))
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestConditionalGet_SetsETag(t *testing.T) {
+	handler := conditionalHandler(http.StatusOK, "<p>hello</p>")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts/hello", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if rec.Body.String() != "<p>hello</p>" {
+		t.Errorf("body = %q", rec.Body.String())
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("ETag not set")
+	}
+
+	// Same body, same ETag
+	again := httptest.NewRecorder()
+	handler.ServeHTTP(again, httptest.NewRequest(http.MethodGet, "/posts/hello", nil))
+	if got := again.Header().Get("ETag"); got != etag {
+		t.Errorf("ETag changed between identical responses: %q != %q", got, etag)
+	}
+
+	other := httptest.NewRecorder()
+	conditionalHandler(http.StatusOK, "<p>changed</p>").ServeHTTP(other, httptest.NewRequest(http.MethodGet, "/posts/hello", nil))
+	if got := other.Header().Get("ETag"); got == etag {
+		t.Error("ETag did not change with the body")
+	}
+}
+
+func TestConditionalGet_IfNoneMatch(t *testing.T) {
+	handler := conditionalHandler(http.StatusOK, "<p>hello</p>")
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/posts/hello", nil))
+	etag := first.Header().Get("ETag")
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"exact", etag, http.StatusNotModified},
+		{"in list", `"other", ` + etag, http.StatusNotModified},
+		{"wildcard", "*", http.StatusNotModified},
+		{"stale", `W/"0000"`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/posts/hello", nil)
+			req.Header.Set("If-None-Match", tt.header)
+			// If-None-Match wins even when If-Modified-Since would match
+			req.Header.Set("If-Modified-Since", conditionalModified.Format(http.TimeFormat))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("304 has a body: %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestConditionalGet_IfModifiedSince(t *testing.T) {
+	handler := conditionalHandler(http.StatusOK, "<p>hello</p>")
+
+	tests := []struct {
+		name  string
+		since time.Time
+		want  int
+	}{
+		{"same time", conditionalModified, http.StatusNotModified},
+		{"later", conditionalModified.Add(time.Hour), http.StatusNotModified},
+		{"earlier", conditionalModified.Add(-time.Hour), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/posts/hello", nil)
+			req.Header.Set("If-Modified-Since", tt.since.Format(http.TimeFormat))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionalGet_PassesThroughErrors(t *testing.T) {
+	handler := conditionalHandler(http.StatusNotFound, "not found")
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/missing", nil)
+	req.Header.Set("If-None-Match", "*")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+	if rec.Header().Get("ETag") != "" {
+		t.Error("ETag set on an error response")
+	}
+	if rec.Body.String() != "not found" {
+		t.Errorf("body = %q", rec.Body.String())
+	}
+}
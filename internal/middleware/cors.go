@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORS request and response header values for the JSON API
+const (
+	corsAllowMethods  = "GET, HEAD, OPTIONS"
+	corsAllowHeaders  = "Authorization, Content-Type, X-API-Key"
+	corsExposeHeaders = "Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset"
+	corsMaxAge        = 10 * time.Minute
+)
+
+// CORSConfig holds CORS middleware configuration
+type CORSConfig struct {
+	// AllowedOrigins lists origins such as "https://example.com" that may
+	// call the API from a browser. "*" allows any origin. Empty allows none.
+	AllowedOrigins []string
+
+	// AllowCredentials lets browsers send cookies and HTTP auth. The allowed
+	// origin is then echoed back, since browsers reject "*" with credentials.
+	// It is ignored for the "*" origin, which would otherwise hand any site
+	// credentialed access.
+	AllowCredentials bool
+}
+
+// CORS lets browser code on allowed origins call the wrapped handler. It
+// answers preflight requests itself and is meant for the public JSON API
+// only; admin and session routes must never be wrapped, as they rely on
+// same-origin cookies.
+func CORS(cfg CORSConfig) func(http.Handler) http.Handler {
+	anyOrigin := slices.Contains(cfg.AllowedOrigins, "*")
+	allowed := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		allowed[strings.TrimRight(origin, "/")] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Responses differ by origin, so shared caches must key on it
+			w.Header().Add("Vary", "Origin")
+
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if !anyOrigin && !allowed[origin] {
+				if preflight {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				// Serve the request without CORS headers; the browser
+				// keeps the response from the calling page
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if anyOrigin {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				if cfg.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+
+			if preflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+				w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+				w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(corsMaxAge.Seconds())))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
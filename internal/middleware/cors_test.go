@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func corsHandler(cfg CORSConfig) (http.Handler, *bool) {
+	called := false
+	return CORS(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})), &called
+}
+
+func preflight(origin string) *http.Request {
+	req := httptest.NewRequest(http.MethodOptions, "/api/bookmarks", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	req.Header.Set("Access-Control-Request-Headers", "authorization")
+	return req
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	handler, called := corsHandler(CORSConfig{AllowedOrigins: []string{"https://tools.example.com"}})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, preflight("https://tools.example.com"))
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", rec.Code)
+	}
+	if *called {
+		t.Error("preflight reached the wrapped handler")
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://tools.example.com" {
+		t.Errorf("Allow-Origin = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got != corsAllowMethods {
+		t.Errorf("Allow-Methods = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); got != corsAllowHeaders {
+		t.Errorf("Allow-Headers = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Allow-Credentials = %q, want unset", got)
+	}
+}
+
+func TestCORS_DisallowedOrigin(t *testing.T) {
+	handler, called := corsHandler(CORSConfig{AllowedOrigins: []string{"https://tools.example.com"}})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, preflight("https://evil.example"))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("preflight status = %d, want 403", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("preflight Allow-Origin = %q, want unset", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/bookmarks", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if !*called {
+		t.Error("simple request was not served")
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Allow-Origin = %q, want unset", got)
+	}
+}
+
+func TestCORS_AllowedRequest(t *testing.T) {
+	handler, called := corsHandler(CORSConfig{AllowedOrigins: []string{"https://tools.example.com/"}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/bookmarks", nil)
+	req.Header.Set("Origin", "https://tools.example.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if !*called || rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, called = %v", rec.Code, *called)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://tools.example.com" {
+		t.Errorf("Allow-Origin = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got != corsExposeHeaders {
+		t.Errorf("Expose-Headers = %q", got)
+	}
+	if got := rec.Header().Values("Vary"); len(got) == 0 || got[0] != "Origin" {
+		t.Errorf("Vary = %v, want Origin", got)
+	}
+}
+
+func TestCORS_Wildcard(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials bool
+		want        string
+	}{
+		{"without credentials", false, "*"},
+		{"with credentials never reflects origin", true, "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _ := corsHandler(CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: tt.credentials})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/bookmarks", nil)
+			req.Header.Set("Origin", "https://any.example")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.want {
+				t.Errorf("Allow-Origin = %q, want %q", got, tt.want)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+				t.Errorf("Allow-Credentials = %q, want none for the wildcard", got)
+			}
+		})
+	}
+}
+
+func TestCORS_NoOrigin(t *testing.T) {
+	handler, called := corsHandler(CORSConfig{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/bookmarks", nil))
+
+	if !*called {
+		t.Error("same-origin request was not served")
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Allow-Origin = %q, want unset", got)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"crypto/rand"
 	"crypto/subtle"
 	"encoding/base64"
+	"errors"
 	"net/http"
+	"strings"
 )
 
 const (
@@ -13,6 +15,10 @@ const (
 	csrfHeaderName = "X-CSRF-Token"
 	csrfFormField  = "csrf_token"
 	csrfTokenLen   = 32
+
+	// csrfFormMemory is how much of a multipart form is held in memory
+	// while looking for the token; larger files spill to temporary files
+	csrfFormMemory = 10 << 20
 )
 
 type csrfContextKey string
@@ -22,6 +28,11 @@ const CSRFTokenContextKey csrfContextKey = "csrf_token"
 // CSRFConfig holds CSRF middleware configuration
 type CSRFConfig struct {
 	Secure bool // Use Secure cookie flag (true for HTTPS/production)
+
+	// MaxBodyBytes caps the body of requests whose token has to be read
+	// from the form, since that parses the whole form before any handler
+	// runs. Zero leaves the body uncapped.
+	MaxBodyBytes int64
 }
 
 // CSRF middleware protects against Cross-Site Request Forgery attacks.
@@ -65,6 +76,13 @@ func CSRF(cfg CSRFConfig) func(http.Handler) http.Handler {
 			requestToken := r.Header.Get(csrfHeaderName)
 			if requestToken == "" {
 				// Try form field as fallback (for traditional form submissions)
+				if err := parseCSRFForm(w, r, cfg.MaxBodyBytes); err != nil {
+					var maxErr *http.MaxBytesError
+					if errors.As(err, &maxErr) {
+						http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+						return
+					}
+				}
 				requestToken = r.FormValue(csrfFormField)
 			}
 
@@ -79,6 +97,18 @@ func CSRF(cfg CSRFConfig) func(http.Handler) http.Handler {
 	}
 }
 
+// parseCSRFForm parses the request's form with the body capped at maxBytes,
+// so the handler finds it already parsed rather than reading it uncapped
+func parseCSRFForm(w http.ResponseWriter, r *http.Request, maxBytes int64) error {
+	if maxBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.ParseMultipartForm(csrfFormMemory)
+	}
+	return r.ParseForm()
+}
+
 // GetCSRFToken retrieves the CSRF token from the request context.
 // Use this in handlers to pass the token to templates.
 func GetCSRFToken(r *http.Request) string {
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"bytes"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -227,6 +229,48 @@ func TestCSRF_ValidTokenInFormField(t *testing.T) {
 	}
 }
 
+func TestCSRF_FormTokenBodyCapped(t *testing.T) {
+	token := "valid-token-12345"
+
+	newRequest := func(fileSize int) *http.Request {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		mw.WriteField("csrf_token", token)
+		part, _ := mw.CreateFormFile("file", "import.html")
+		part.Write(bytes.Repeat([]byte("x"), fileSize))
+		mw.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/import/preview", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: token})
+		return req
+	}
+
+	called := false
+	handler := CSRF(CSRFConfig{MaxBodyBytes: 1024})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		if r.MultipartForm == nil {
+			t.Error("form should reach the handler already parsed")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newRequest(4096))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized form: status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+	if called {
+		t.Error("handler called for an oversized form")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, newRequest(100))
+	if rec.Code != http.StatusOK || !called {
+		t.Errorf("small form: status = %d, called = %v; want 200 and the handler run", rec.Code, called)
+	}
+}
+
 func TestCSRF_HeaderTakesPrecedenceOverForm(t *testing.T) {
 	cookieToken := "cookie-token"
 	headerToken := "cookie-token" // matches cookie
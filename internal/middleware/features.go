@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/EC-9624/0xec.dev/internal/config"
+)
+
+// Features makes templates hide the optional features enabled reports as
+// switched off when rendering the request
+func Features(enabled func(name string) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(config.WithFeatures(r.Context(), enabled)))
+		})
+	}
+}
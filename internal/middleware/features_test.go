@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/config"
+)
+
+func TestFeatures(t *testing.T) {
+	cfg := &config.Config{Features: map[string]bool{config.FeatureAPI: true}}
+
+	var api, search bool
+	handler := Features(cfg.FeatureEnabled)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api = config.FeatureEnabledIn(r.Context(), config.FeatureAPI)
+		search = config.FeatureEnabledIn(r.Context(), config.FeatureSearch)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !api || search {
+		t.Errorf("api = %v, search = %v; want only api enabled", api, search)
+	}
+}
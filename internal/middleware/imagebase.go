@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+// ImageBaseURL makes templates rewrite stored image URLs to base (e.g. a
+// CDN) when rendering the request. An empty base keeps the local
+// /images/{id} paths.
+func ImageBaseURL(base string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if base == "" {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(models.WithImageBaseURL(r.Context(), base)))
+		})
+	}
+}
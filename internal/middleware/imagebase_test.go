@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestImageBaseURL(t *testing.T) {
+	for _, base := range []string{"", "https://cdn.example.net"} {
+		var got string
+		handler := ImageBaseURL(base)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = models.ImageURL(models.ImageBaseURL(r.Context()), 5)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if want := base + "/images/5"; got != want {
+			t.Errorf("base %q: image URL = %q, want %q", base, got, want)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// durationBuckets are the upper bounds (in seconds) of the request duration
+// histogram. They match the Prometheus client defaults, which cover the
+// range from cached pages (~5ms) to slow metadata fetches (~10s).
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// standardMethods are the request methods recorded under their own label.
+// Anything else a client sends is counted as "OTHER" so arbitrary verbs
+// can't create new series.
+var standardMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// routeContextKey holds the route a nested ServeMux reports back to
+// Instrument
+type routeContextKey struct{}
+
+// Metrics records HTTP request metrics and exposes them in the Prometheus
+// text format. Requests are labeled by method, ServeMux route pattern and
+// status; the pattern is used instead of the raw path to keep label
+// cardinality bounded.
+type Metrics struct {
+	mu       sync.Mutex
+	requests map[metricLabels]*requestStats
+	inFlight atomic.Int64
+}
+
+// metricLabels identifies one time series
+type metricLabels struct {
+	method string
+	route  string
+	status int
+}
+
+// requestStats holds the counter and histogram for one label set
+type requestStats struct {
+	count   uint64
+	sum     float64
+	buckets []uint64 // cumulative counts per durationBuckets entry
+}
+
+// NewMetrics creates an empty metrics registry
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: make(map[metricLabels]*requestStats),
+	}
+}
+
+// Instrument returns middleware that records every request. It must wrap
+// the ServeMux directly so the matched pattern is visible after routing.
+func (m *Metrics) Instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		m.inFlight.Add(1)
+		defer m.inFlight.Add(-1)
+
+		// A ServeMux wrapped in NestedRoute fills this in with its pattern
+		var nested string
+		r = r.WithContext(context.WithValue(r.Context(), routeContextKey{}, &nested))
+
+		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(wrapped, r)
+
+		// ServeMux sets r.Pattern on the request it routes
+		route := r.Pattern
+		if nested != "" {
+			route = nested
+		}
+		if route == "" {
+			route = "unmatched"
+		}
+		m.observe(metricLabels{method: metricMethod(r.Method), route: route, status: wrapped.statusCode}, time.Since(start))
+	})
+}
+
+// NestedRoute wraps a ServeMux mounted under a subtree pattern of the main
+// router (e.g. "/admin/") so Instrument labels its requests with the inner
+// pattern that matched, such as "GET /admin/posts/{slug}/edit". It must
+// wrap the inner ServeMux directly; without Instrument it does nothing.
+func NestedRoute(mux http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r)
+		if route, ok := r.Context().Value(routeContextKey{}).(*string); ok && r.Pattern != "" {
+			*route = r.Pattern
+		}
+	})
+}
+
+// metricMethod returns the method label for a request method
+func metricMethod(method string) string {
+	if standardMethods[method] {
+		return method
+	}
+	return "OTHER"
+}
+
+// observe records one finished request
+func (m *Metrics) observe(labels metricLabels, duration time.Duration) {
+	seconds := duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.requests[labels]
+	if !ok {
+		stats = &requestStats{buckets: make([]uint64, len(durationBuckets))}
+		m.requests[labels] = stats
+	}
+	stats.count++
+	stats.sum += seconds
+	for i, bound := range durationBuckets {
+		if seconds <= bound {
+			stats.buckets[i]++
+		}
+	}
+}
+
+// Handler serves the collected metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write([]byte(m.render()))
+	})
+}
+
+// render formats all series, sorted so scrapes are stable
+func (m *Metrics) render() string {
+	m.mu.Lock()
+	labels := make([]metricLabels, 0, len(m.requests))
+	snapshot := make(map[metricLabels]requestStats, len(m.requests))
+	for l, s := range m.requests {
+		labels = append(labels, l)
+		snapshot[l] = requestStats{count: s.count, sum: s.sum, buckets: append([]uint64(nil), s.buckets...)}
+	}
+	m.mu.Unlock()
+
+	sort.Slice(labels, func(i, j int) bool {
+		a, b := labels[i], labels[j]
+		if a.route != b.route {
+			return a.route < b.route
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.status < b.status
+	})
+
+	var b strings.Builder
+
+	b.WriteString("# HELP http_requests_total Total HTTP requests by method, route and status.\n")
+	b.WriteString("# TYPE http_requests_total counter\n")
+	for _, l := range labels {
+		fmt.Fprintf(&b, "http_requests_total{%s} %d\n", l.format(), snapshot[l].count)
+	}
+
+	b.WriteString("# HELP http_request_duration_seconds HTTP request duration in seconds.\n")
+	b.WriteString("# TYPE http_request_duration_seconds histogram\n")
+	for _, l := range labels {
+		s := snapshot[l]
+		for i, bound := range durationBuckets {
+			le := strconv.FormatFloat(bound, 'g', -1, 64)
+			fmt.Fprintf(&b, "http_request_duration_seconds_bucket{%s,le=%q} %d\n", l.format(), le, s.buckets[i])
+		}
+		fmt.Fprintf(&b, "http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", l.format(), s.count)
+		fmt.Fprintf(&b, "http_request_duration_seconds_sum{%s} %s\n", l.format(), strconv.FormatFloat(s.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "http_request_duration_seconds_count{%s} %d\n", l.format(), s.count)
+	}
+
+	b.WriteString("# HELP http_requests_in_flight HTTP requests currently being served.\n")
+	b.WriteString("# TYPE http_requests_in_flight gauge\n")
+	fmt.Fprintf(&b, "http_requests_in_flight %d\n", m.inFlight.Load())
+
+	return b.String()
+}
+
+// format renders the label set in Prometheus syntax
+func (l metricLabels) format() string {
+	return fmt.Sprintf("method=%q,route=%q,status=\"%d\"", l.method, l.route, l.status)
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics_LabelsByRoutePattern(t *testing.T) {
+	m := NewMetrics()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /posts/{slug}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := m.Instrument(mux)
+
+	for _, path := range []string{"/posts/one", "/posts/two", "/missing"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	want := []string{
+		`http_requests_total{method="GET",route="GET /posts/{slug}",status="200"} 2`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`http_request_duration_seconds_bucket{method="GET",route="GET /posts/{slug}",status="200",le="+Inf"} 2`,
+		`http_request_duration_seconds_count{method="GET",route="GET /posts/{slug}",status="200"} 2`,
+		`http_requests_in_flight 0`,
+	}
+	for _, w := range want {
+		if !strings.Contains(body, w) {
+			t.Errorf("metrics output missing %q\n%s", w, body)
+		}
+	}
+
+	// Raw paths must not leak into labels
+	if strings.Contains(body, "/posts/one") {
+		t.Error("metrics output contains raw request path")
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+}
+
+func TestMetrics_InFlight(t *testing.T) {
+	m := NewMetrics()
+	var during string
+	handler := m.Instrument(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		during = m.render()
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !strings.Contains(during, "http_requests_in_flight 1") {
+		t.Errorf("in-flight gauge during request:\n%s", during)
+	}
+	if !strings.Contains(m.render(), "http_requests_in_flight 0") {
+		t.Error("in-flight gauge not decremented after request")
+	}
+}
+
+func TestMetrics_NonStandardMethod(t *testing.T) {
+	m := NewMetrics()
+	handler := m.Instrument(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, method := range []string{"PROPFIND", "XYZZY", http.MethodPatch} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/", nil))
+	}
+
+	body := m.render()
+	for _, w := range []string{
+		`http_requests_total{method="OTHER",route="unmatched",status="200"} 2`,
+		`http_requests_total{method="PATCH",route="unmatched",status="200"} 1`,
+	} {
+		if !strings.Contains(body, w) {
+			t.Errorf("metrics output missing %q\n%s", w, body)
+		}
+	}
+	if strings.Contains(body, "XYZZY") || strings.Contains(body, "PROPFIND") {
+		t.Error("metrics output contains a client-supplied method")
+	}
+}
+
+func TestMetrics_NestedRoute(t *testing.T) {
+	m := NewMetrics()
+	inner := http.NewServeMux()
+	inner.HandleFunc("GET /admin/posts/{slug}/edit", func(w http.ResponseWriter, r *http.Request) {})
+	// A middleware between the muxes hands the inner mux a copy of the request
+	passThrough := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(r.Context()))
+		})
+	}
+	outer := http.NewServeMux()
+	outer.Handle("/admin/", passThrough(NestedRoute(inner)))
+	handler := m.Instrument(outer)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/admin/posts/one/edit", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/admin/missing", nil))
+
+	body := m.render()
+	for _, w := range []string{
+		`http_requests_total{method="GET",route="GET /admin/posts/{slug}/edit",status="200"} 1`,
+		`http_requests_total{method="GET",route="/admin/",status="404"} 1`,
+	} {
+		if !strings.Contains(body, w) {
+			t.Errorf("metrics output missing %q\n%s", w, body)
+		}
+	}
+}
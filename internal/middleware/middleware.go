@@ -9,37 +9,46 @@ import (
 	"github.com/EC-9624/0xec.dev/internal/logger"
 )
 
-// Logger logs HTTP requests with structured logging
-func Logger(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-
-		// Generate request ID (8 hex chars = 4 bytes)
-		requestID := generateRequestID()
-
-		// Add request ID to context
-		ctx := logger.WithRequestID(r.Context(), requestID)
-		r = r.WithContext(ctx)
+// RequestIDHeader carries the request ID between proxies, the app and clients
+const RequestIDHeader = "X-Request-ID"
 
-		// Set request ID header for client debugging
-		w.Header().Set("X-Request-ID", requestID)
+// maxRequestIDLength bounds incoming request IDs so clients can't bloat logs
+const maxRequestIDLength = 64
 
-		// Wrap response writer to capture status code
-		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+// RequestID reuses a well-formed incoming X-Request-ID or generates a new
+// one, stores it in the request context for logger's context helpers and
+// echoes it in the response header. It should wrap every other middleware
+// so all log lines for a request share the ID.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = generateRequestID()
+		}
 
-		next.ServeHTTP(wrapped, r)
+		r = r.WithContext(logger.WithRequestID(r.Context(), requestID))
+		w.Header().Set(RequestIDHeader, requestID)
 
-		// Log the request
-		logger.Info(ctx, "http request",
-			"method", r.Method,
-			"path", r.URL.Path,
-			"status", wrapped.statusCode,
-			"duration_ms", time.Since(start).Milliseconds(),
-			"user_agent", r.UserAgent(),
-		)
+		next.ServeHTTP(w, r)
 	})
 }
 
+// validRequestID accepts short IDs made of letters, digits, '-', '_' and '.'
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // generateRequestID creates a short unique request ID (8 hex characters)
 func generateRequestID() string {
 	b := make([]byte, 4)
@@ -49,6 +58,66 @@ func generateRequestID() string {
 	return hex.EncodeToString(b)
 }
 
+// DefaultSlowRequestThreshold is the slow request threshold of Logger
+const DefaultSlowRequestThreshold = time.Second
+
+// LoggerConfig configures LoggerWith
+type LoggerConfig struct {
+	// SlowThreshold is how long a request may take before it is logged at
+	// WARN instead of INFO. Zero never logs requests as slow.
+	SlowThreshold time.Duration
+}
+
+// LoggerWith logs HTTP requests with structured logging, warning about
+// requests slower than cfg.SlowThreshold. The request ID set by RequestID
+// is included automatically via the request context.
+func LoggerWith(cfg LoggerConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// Wrap response writer to capture status code
+			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(wrapped, r)
+
+			duration := time.Since(start)
+			if cfg.SlowThreshold > 0 && duration > cfg.SlowThreshold {
+				// ServeMux sets r.Pattern on the request it routes, so the
+				// route is known when nothing in between copied the request
+				route := r.Pattern
+				if route == "" {
+					route = "unmatched"
+				}
+				logger.Warn(r.Context(), "slow http request",
+					"method", r.Method,
+					"route", route,
+					"path", r.URL.Path,
+					"status", wrapped.statusCode,
+					"duration_ms", duration.Milliseconds(),
+					"threshold_ms", cfg.SlowThreshold.Milliseconds(),
+				)
+				return
+			}
+
+			// Log the request
+			logger.Info(r.Context(), "http request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", wrapped.statusCode,
+				"duration_ms", duration.Milliseconds(),
+				"user_agent", r.UserAgent(),
+			)
+		})
+	}
+}
+
+// Logger logs HTTP requests, warning about those slower than
+// DefaultSlowRequestThreshold
+func Logger(next http.Handler) http.Handler {
+	return LoggerWith(LoggerConfig{SlowThreshold: DefaultSlowRequestThreshold})(next)
+}
+
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
@@ -2,12 +2,18 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/logger"
 )
 
 func TestLogger(t *testing.T) {
@@ -41,6 +47,167 @@ func TestLogger(t *testing.T) {
 	}
 }
 
+// recordingHandler is a slog.Handler that keeps every record, with the
+// attributes added through With folded in
+type recordingHandler struct {
+	mu      *sync.Mutex
+	records *[]slog.Record
+	attrs   []slog.Attr
+}
+
+func newRecordingHandler() *recordingHandler {
+	return &recordingHandler{mu: &sync.Mutex{}, records: &[]slog.Record{}}
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	r = r.Clone()
+	r.AddAttrs(h.attrs...)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.records = append(*h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := *h
+	next.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &next
+}
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+// recordAttr returns the value of the record's attribute named key
+func recordAttr(r slog.Record, key string) (slog.Value, bool) {
+	var value slog.Value
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == key {
+			value, found = a.Value, true
+			return false
+		}
+		return true
+	})
+	return value, found
+}
+
+func TestLoggerWith_SlowRequest(t *testing.T) {
+	recorder := newRecordingHandler()
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(recorder))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /slow/{id}", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("GET /fast", func(w http.ResponseWriter, r *http.Request) {})
+	handler := RequestID(LoggerWith(LoggerConfig{SlowThreshold: 10 * time.Millisecond})(mux))
+
+	requests := []struct{ path, requestID string }{
+		{"/slow/1", "req-slow"},
+		{"/fast", "req-fast"},
+	}
+	for _, tt := range requests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set(RequestIDHeader, tt.requestID)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	records := *recorder.records
+	if len(records) != 2 {
+		t.Fatalf("got %d log records, want 2", len(records))
+	}
+
+	slow := records[0]
+	if slow.Level != slog.LevelWarn {
+		t.Errorf("slow request level = %v, want WARN", slow.Level)
+	}
+	want := map[string]string{
+		"method":     "GET",
+		"route":      "GET /slow/{id}",
+		"status":     "202",
+		"request_id": "req-slow",
+	}
+	for key, value := range want {
+		if got, ok := recordAttr(slow, key); !ok || got.String() != value {
+			t.Errorf("slow request %s = %q, want %q", key, got.String(), value)
+		}
+	}
+	if got, ok := recordAttr(slow, "duration_ms"); !ok || got.Int64() < 20 {
+		t.Errorf("slow request duration_ms = %v, want at least 20", got)
+	}
+
+	if fast := records[1]; fast.Level != slog.LevelInfo {
+		t.Errorf("fast request level = %v, want INFO", fast.Level)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		wantSame bool
+	}{
+		{"generates when missing", "", false},
+		{"reuses valid incoming ID", "req-1234_abc.def", true},
+		{"replaces ID with invalid characters", "bad id\nInjected: 1", false},
+		{"replaces overly long ID", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ctxID string
+			handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxID = logger.GetRequestID(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			headerID := rec.Header().Get(RequestIDHeader)
+			if headerID == "" {
+				t.Fatal("response missing X-Request-ID header")
+			}
+			if ctxID != headerID {
+				t.Errorf("context ID = %q, header ID = %q, want equal", ctxID, headerID)
+			}
+			if tt.wantSame && headerID != tt.incoming {
+				t.Errorf("request ID = %q, want incoming %q", headerID, tt.incoming)
+			}
+			if !tt.wantSame && headerID == tt.incoming {
+				t.Errorf("request ID = %q, want a generated ID", headerID)
+			}
+		})
+	}
+}
+
+func TestLogger_IncludesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	handler := RequestID(Logger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/test/path", nil)
+	req.Header.Set(RequestIDHeader, "abc123")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if !strings.Contains(buf.String(), "request_id=abc123") {
+		t.Errorf("log output missing request ID: %s", buf.String())
+	}
+}
+
 func TestLogger_CapturesStatusCode(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -160,6 +327,29 @@ func TestRecoverer(t *testing.T) {
 	}
 }
 
+func TestRecoverer_LogsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	handler := RequestID(Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("test panic")
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "panic-42")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if !strings.Contains(buf.String(), "request_id=panic-42") {
+		t.Errorf("panic log missing request ID: %s", buf.String())
+	}
+	if got := rec.Header().Get(RequestIDHeader); got != "panic-42" {
+		t.Errorf("X-Request-ID = %q, want panic-42", got)
+	}
+}
+
 func TestRecoverer_NoRecoveryNeeded(t *testing.T) {
 	called := false
 	handler := Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
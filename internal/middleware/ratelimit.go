@@ -1,21 +1,48 @@
 package middleware
 
 import (
-	"net"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/EC-9624/0xec.dev/internal/models"
+
 	"golang.org/x/time/rate"
 )
 
-// RateLimiter provides IP-based rate limiting
+// KeyFunc returns the bucket a request is counted against. Requests that
+// share a key share a budget.
+type KeyFunc func(r *http.Request) string
+
+// KeyByIP budgets requests per client IP address
+func KeyByIP(r *http.Request) string {
+	return "ip:" + ClientIP(r)
+}
+
+// KeyByUser budgets requests per signed-in user, falling back to the client
+// IP for anonymous requests. Use it behind Auth so the user is known.
+func KeyByUser(r *http.Request) string {
+	if user, ok := r.Context().Value(UserContextKey).(*models.User); ok && user != nil {
+		return "user:" + strconv.FormatInt(user.ID, 10)
+	}
+	return KeyByIP(r)
+}
+
+// minVisitorIdle is the shortest time a bucket is kept after its last
+// request, even when it would have refilled sooner
+const minVisitorIdle = 3 * time.Minute
+
+// RateLimiter is a token bucket rate limiter with one bucket per key
 type RateLimiter struct {
 	visitors map[string]*visitor
 	mu       sync.RWMutex
 	rate     rate.Limit
 	burst    int
+	key      KeyFunc
 }
 
 type visitor struct {
@@ -23,82 +50,147 @@ type visitor struct {
 	lastSeen time.Time
 }
 
-// NewRateLimiter creates a rate limiter.
+// NewRateLimiter creates a rate limiter keyed by client IP.
 // rps: requests per second allowed, burst: max burst size
 func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	return NewKeyedRateLimiter(rps, burst, KeyByIP)
+}
+
+// NewKeyedRateLimiter creates a rate limiter that budgets requests by the
+// given key function
+func NewKeyedRateLimiter(rps float64, burst int, key KeyFunc) *RateLimiter {
 	rl := &RateLimiter{
 		visitors: make(map[string]*visitor),
 		rate:     rate.Limit(rps),
 		burst:    burst,
+		key:      key,
 	}
 	go rl.cleanupVisitors()
 	return rl
 }
 
-func (rl *RateLimiter) getVisitor(ip string) *rate.Limiter {
+func (rl *RateLimiter) getVisitor(key string) *rate.Limiter {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	v, exists := rl.visitors[ip]
+	v, exists := rl.visitors[key]
 	if !exists {
 		limiter := rate.NewLimiter(rl.rate, rl.burst)
-		rl.visitors[ip] = &visitor{limiter: limiter, lastSeen: time.Now()}
+		rl.visitors[key] = &visitor{limiter: limiter, lastSeen: time.Now()}
 		return limiter
 	}
 	v.lastSeen = time.Now()
 	return v.limiter
 }
 
-// cleanupVisitors removes stale entries every minute
+// cleanupVisitors evicts stale buckets every minute
 func (rl *RateLimiter) cleanupVisitors() {
 	for {
 		time.Sleep(time.Minute)
-		rl.mu.Lock()
-		for ip, v := range rl.visitors {
-			if time.Since(v.lastSeen) > 3*time.Minute {
-				delete(rl.visitors, ip)
-			}
+		rl.evictStale(time.Now())
+	}
+}
+
+// evictStale drops buckets that have been idle long enough to refill
+// completely; a fresh bucket would behave the same, so forgetting them
+// only frees memory
+func (rl *RateLimiter) evictStale(now time.Time) {
+	idle := minVisitorIdle
+	if rl.rate > 0 {
+		if refill := time.Duration(float64(rl.burst) / float64(rl.rate) * float64(time.Second)); refill > idle {
+			idle = refill
+		}
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for key, v := range rl.visitors {
+		if now.Sub(v.lastSeen) > idle {
+			delete(rl.visitors, key)
 		}
-		rl.mu.Unlock()
 	}
 }
 
-// Limit returns middleware that rate limits requests by IP address
+// Limit returns middleware that rate limits requests by the limiter's key.
+// Rejected requests get 429 with a Retry-After header.
 func (rl *RateLimiter) Limit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ip := getClientIP(r)
-		limiter := rl.getVisitor(ip)
+		limiter := rl.getVisitor(rl.key(r))
 
 		if !limiter.Allow() {
-			w.Header().Set("Retry-After", "60")
-			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			tooManyRequests(w, r, rl.secondsUntilToken(limiter.Tokens()))
 			return
 		}
 		next.ServeHTTP(w, r)
 	})
 }
 
-// getClientIP extracts the client IP address from the request,
-// checking proxy headers first for deployments behind reverse proxies.
-func getClientIP(r *http.Request) string {
-	// Check X-Forwarded-For for proxied requests (e.g., behind nginx, Cloudflare)
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		// Take the first IP (original client IP)
-		if idx := strings.Index(xff, ","); idx != -1 {
-			return strings.TrimSpace(xff[:idx])
+// LimitAPI returns middleware for the public API, keyed like Limit. Every
+// response carries X-RateLimit-* headers; rejected requests also get
+// Retry-After. Sent API tokens are unvalidated here, so they never pick the
+// bucket: a client could otherwise get a fresh budget per made-up token.
+func (rl *RateLimiter) LimitAPI(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter := rl.getVisitor(rl.key(r))
+
+		allowed := limiter.Allow()
+		remaining := int(math.Max(0, math.Floor(limiter.Tokens())))
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rl.burst))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+		if !allowed {
+			retryAfter := rl.secondsUntilToken(limiter.Tokens())
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(retryAfter))
+			tooManyRequests(w, r, retryAfter)
+			return
 		}
-		return strings.TrimSpace(xff)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tooManyRequests writes a 429 response telling the client when to retry.
+// API and JSON clients get a JSON body, browsers a short HTML page.
+func tooManyRequests(w http.ResponseWriter, r *http.Request, retryAfter int) {
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprintf(w, `{"error":"too many requests","retry_after":%d}`, retryAfter)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusTooManyRequests)
+	fmt.Fprintf(w, `<!DOCTYPE html><html lang="en"><head><meta charset="utf-8"><title>Too Many Requests</title></head>`+
+		`<body><h1>Too Many Requests</h1><p>Please wait %d seconds and try again.</p></body></html>`, retryAfter)
+}
+
+// wantsJSON reports whether the client expects a JSON response: API paths
+// and requests that accept JSON but not HTML
+func wantsJSON(r *http.Request) bool {
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		return true
 	}
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "application/json") && !strings.Contains(accept, "text/html")
+}
 
-	// Check X-Real-IP (commonly set by nginx)
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return xri
+// secondsUntilToken reports how long until a limiter holding tokens can
+// allow another request, rounded up to whole seconds
+func (rl *RateLimiter) secondsUntilToken(tokens float64) int {
+	if rl.rate <= 0 {
+		return 60
 	}
+	wait := math.Ceil((1 - tokens) / float64(rl.rate))
+	return int(math.Max(1, wait))
+}
 
-	// Fall back to RemoteAddr
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		return r.RemoteAddr
+// APIToken returns the API token from an "Authorization: Bearer" or
+// X-API-Key header, or empty string for anonymous requests
+func APIToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
 	}
-	return ip
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
 }
@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestLimitAPI_TokensShareIPBudget(t *testing.T) {
+	rl := NewRateLimiter(1.0/60.0, 2)
+	handler := rl.LimitAPI(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/bookmarks", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := do("token-" + strconv.Itoa(i)); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i+1, rec.Code)
+		}
+	}
+
+	// A new made-up token from the same IP doesn't get a fresh budget
+	if rec := do("token-new"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("new token over IP budget: status = %d, want 429", rec.Code)
+	}
+	if n := len(rl.visitors); n != 1 {
+		t.Errorf("visitors = %d, want 1 bucket for the one IP", n)
+	}
+}
+
+func TestLimitAPI_AnonymousKeyedByIP(t *testing.T) {
+	rl := NewRateLimiter(1.0/60.0, 1)
+	handler := rl.LimitAPI(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/bookmarks", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if got := do("192.0.2.1:1234"); got != http.StatusOK {
+		t.Fatalf("first request: status = %d, want 200", got)
+	}
+	if got := do("192.0.2.1:1234"); got != http.StatusTooManyRequests {
+		t.Errorf("second request from same IP: status = %d, want 429", got)
+	}
+	if got := do("192.0.2.2:1234"); got != http.StatusOK {
+		t.Errorf("request from other IP: status = %d, want 200", got)
+	}
+}
+
+func TestLimitAPI_Headers(t *testing.T) {
+	rl := NewRateLimiter(1.0, 1)
+	handler := rl.LimitAPI(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/bookmarks", nil)
+	req.Header.Set("X-API-Key", "key-1")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("X-RateLimit-Limit"); got != "1" {
+		t.Errorf("X-RateLimit-Limit = %q, want 1", got)
+	}
+	if got := rec.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 0", got)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "" {
+		t.Errorf("Retry-After = %q on allowed request, want empty", got)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", rec.Code)
+	}
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 {
+		t.Errorf("Retry-After = %q, want positive seconds", rec.Header().Get("Retry-After"))
+	}
+	if got := rec.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 0", got)
+	}
+}
+
+func TestLimit_KeyByUser(t *testing.T) {
+	rl := NewKeyedRateLimiter(1.0/60.0, 1, KeyByUser)
+	handler := rl.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(userID int64) int {
+		req := httptest.NewRequest(http.MethodPost, "/admin/import", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req = req.WithContext(context.WithValue(req.Context(), UserContextKey, &models.User{ID: userID}))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if got := do(1); got != http.StatusOK {
+		t.Fatalf("user 1 first request: status = %d, want 200", got)
+	}
+	if got := do(1); got != http.StatusTooManyRequests {
+		t.Errorf("user 1 second request: status = %d, want 429", got)
+	}
+
+	// Another user behind the same IP has their own budget
+	if got := do(2); got != http.StatusOK {
+		t.Errorf("user 2 from same IP: status = %d, want 200", got)
+	}
+}
+
+func TestLimit_RejectionBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		accept      string
+		wantType    string
+		wantContent string
+	}{
+		{"browser", "/admin/login", "text/html,application/xhtml+xml", "text/html", "<h1>Too Many Requests</h1>"},
+		{"json client", "/admin/htmx/bookmarks/fetch-metadata", "application/json", "application/json", `"retry_after":`},
+		{"api path", "/api/bookmarks", "", "application/json", `"error":"too many requests"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rl := NewRateLimiter(1.0/60.0, 1)
+			handler := rl.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			var rec *httptest.ResponseRecorder
+			for i := 0; i < 2; i++ {
+				req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+				if tt.accept != "" {
+					req.Header.Set("Accept", tt.accept)
+				}
+				rec = httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+			}
+
+			if rec.Code != http.StatusTooManyRequests {
+				t.Fatalf("status = %d, want 429", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.wantType) {
+				t.Errorf("Content-Type = %q, want %s", got, tt.wantType)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantContent) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.wantContent)
+			}
+			if retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After")); err != nil || retryAfter < 1 {
+				t.Errorf("Retry-After = %q, want positive seconds", rec.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestEvictStale(t *testing.T) {
+	// One request per minute with a burst of 5 takes 5 minutes to refill
+	rl := NewRateLimiter(1.0/60.0, 5)
+	rl.getVisitor("ip:192.0.2.1")
+	rl.getVisitor("ip:192.0.2.2")
+
+	now := time.Now()
+	rl.visitors["ip:192.0.2.1"].lastSeen = now.Add(-4 * time.Minute)
+	rl.visitors["ip:192.0.2.2"].lastSeen = now.Add(-6 * time.Minute)
+
+	rl.evictStale(now)
+
+	if _, ok := rl.visitors["ip:192.0.2.1"]; !ok {
+		t.Error("bucket still refilling was evicted")
+	}
+	if _, ok := rl.visitors["ip:192.0.2.2"]; ok {
+		t.Error("refilled bucket was not evicted")
+	}
+}
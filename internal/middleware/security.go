@@ -1,45 +1,96 @@
 package middleware
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+)
 
-// SecurityHeaders adds security-related HTTP headers to all responses.
+// DefaultContentSecurityPolicy is the policy used when none is configured.
+// It's moderately strict: 'unsafe-inline' is allowed for styles (required
+// for Tailwind CSS) and https: for images (external cover images, favicons).
+const DefaultContentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' 'unsafe-inline'; " +
+	"style-src 'self' 'unsafe-inline'; " +
+	"img-src 'self' data: https:; " +
+	"font-src 'self'; " +
+	"connect-src 'self'; " +
+	"frame-ancestors 'none'"
+
+// SecurityConfig configures SecurityHeadersWith
+type SecurityConfig struct {
+	// ContentSecurityPolicy is the policy to send. Empty uses
+	// DefaultContentSecurityPolicy.
+	ContentSecurityPolicy string
+
+	// CSPReportOnly sends the policy as Content-Security-Policy-Report-Only,
+	// so violations are reported but not blocked. The enforcing header is
+	// not sent at the same time.
+	CSPReportOnly bool
+
+	// CSPReportURI, if set, is added as the policy's report-uri unless the
+	// policy already names one
+	CSPReportURI string
+
+	// HSTS adds Strict-Transport-Security. Only enable it with HTTPS.
+	HSTS bool
+}
+
+// policy returns the Content-Security-Policy value to send
+func (c SecurityConfig) policy() string {
+	policy := strings.TrimSpace(c.ContentSecurityPolicy)
+	if policy == "" {
+		policy = DefaultContentSecurityPolicy
+	}
+	if c.CSPReportURI != "" && !strings.Contains(policy, "report-uri") {
+		policy = strings.TrimSuffix(policy, ";") + "; report-uri " + c.CSPReportURI
+	}
+	return policy
+}
+
+// SecurityHeadersWith adds security-related HTTP headers to all responses,
+// using cfg for the Content Security Policy and HSTS
+func SecurityHeadersWith(cfg SecurityConfig) func(http.Handler) http.Handler {
+	cspHeader := "Content-Security-Policy"
+	if cfg.CSPReportOnly {
+		cspHeader = "Content-Security-Policy-Report-Only"
+	}
+	policy := cfg.policy()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Prevent clickjacking - disallow embedding in iframes
+			w.Header().Set("X-Frame-Options", "DENY")
+
+			// Prevent MIME type sniffing - browser should trust Content-Type header
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+
+			// Control referrer information sent with requests
+			w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
+
+			// Disable sensitive browser features
+			w.Header().Set("Permissions-Policy", "geolocation=(), microphone=(), camera=()")
+
+			w.Header().Set(cspHeader, policy)
+
+			if cfg.HSTS {
+				// HTTP Strict Transport Security - force HTTPS for 1 year
+				w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SecurityHeaders adds security-related HTTP headers to all responses,
+// enforcing the default Content Security Policy.
 // This provides defense against common web vulnerabilities.
 func SecurityHeaders(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Prevent clickjacking - disallow embedding in iframes
-		w.Header().Set("X-Frame-Options", "DENY")
-
-		// Prevent MIME type sniffing - browser should trust Content-Type header
-		w.Header().Set("X-Content-Type-Options", "nosniff")
-
-		// Control referrer information sent with requests
-		w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
-
-		// Disable sensitive browser features
-		w.Header().Set("Permissions-Policy", "geolocation=(), microphone=(), camera=()")
-
-		// Content Security Policy - moderate strictness
-		// Allows 'unsafe-inline' for styles (required for Tailwind CSS)
-		// Allows https: for images (external cover images, favicons)
-		w.Header().Set("Content-Security-Policy",
-			"default-src 'self'; "+
-				"script-src 'self' 'unsafe-inline'; "+
-				"style-src 'self' 'unsafe-inline'; "+
-				"img-src 'self' data: https:; "+
-				"font-src 'self'; "+
-				"connect-src 'self'; "+
-				"frame-ancestors 'none'")
-
-		next.ServeHTTP(w, r)
-	})
+	return SecurityHeadersWith(SecurityConfig{})(next)
 }
 
 // SecurityHeadersWithHSTS wraps SecurityHeaders and adds HSTS header.
 // Only use this in production with HTTPS enabled.
 func SecurityHeadersWithHSTS(next http.Handler) http.Handler {
-	return SecurityHeaders(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// HTTP Strict Transport Security - force HTTPS for 1 year
-		w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
-		next.ServeHTTP(w, r)
-	}))
+	return SecurityHeadersWith(SecurityConfig{HSTS: true})(next)
 }
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func securityHeaders(cfg SecurityConfig) http.Header {
+	handler := SecurityHeadersWith(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec.Header()
+}
+
+func TestSecurityHeadersWith_DefaultPolicy(t *testing.T) {
+	h := securityHeaders(SecurityConfig{})
+
+	if got := h.Get("Content-Security-Policy"); got != DefaultContentSecurityPolicy {
+		t.Errorf("CSP = %q, want the default", got)
+	}
+	if h.Get("Strict-Transport-Security") != "" {
+		t.Error("HSTS sent without being enabled")
+	}
+	if h.Get("X-Frame-Options") != "DENY" {
+		t.Error("X-Frame-Options missing")
+	}
+}
+
+func TestSecurityHeadersWith_CustomPolicy(t *testing.T) {
+	h := securityHeaders(SecurityConfig{
+		ContentSecurityPolicy: "default-src 'self'; img-src 'self';",
+		CSPReportURI:          "/csp-report",
+		HSTS:                  true,
+	})
+
+	if got, want := h.Get("Content-Security-Policy"), "default-src 'self'; img-src 'self'; report-uri /csp-report"; got != want {
+		t.Errorf("CSP = %q, want %q", got, want)
+	}
+	if h.Get("Strict-Transport-Security") == "" {
+		t.Error("HSTS missing")
+	}
+}
+
+func TestSecurityHeadersWith_ReportOnly(t *testing.T) {
+	h := securityHeaders(SecurityConfig{CSPReportOnly: true, CSPReportURI: "/csp-report"})
+
+	if got := h.Get("Content-Security-Policy"); got != "" {
+		t.Errorf("enforcing CSP sent in report-only mode: %q", got)
+	}
+	got := h.Get("Content-Security-Policy-Report-Only")
+	if !strings.HasPrefix(got, DefaultContentSecurityPolicy) || !strings.HasSuffix(got, "report-uri /csp-report") {
+		t.Errorf("Report-Only = %q, want the default policy with a report-uri", got)
+	}
+}
+
+func TestSecurityHeadersWith_KeepsExistingReportURI(t *testing.T) {
+	policy := "default-src 'self'; report-uri https://reports.example.com"
+	h := securityHeaders(SecurityConfig{ContentSecurityPolicy: policy, CSPReportURI: "/csp-report"})
+
+	if got := h.Get("Content-Security-Policy"); got != policy {
+		t.Errorf("CSP = %q, want %q unchanged", got, policy)
+	}
+}
@@ -12,16 +12,23 @@ type Bookmark struct {
 	URL          string         `json:"url"`
 	Title        string         `json:"title"`
 	Description  sql.NullString `json:"description"`
+	Note         sql.NullString `json:"note"` // personal note, admin only
 	CoverImage   sql.NullString `json:"cover_image"`
 	Favicon      sql.NullString `json:"favicon"`
 	Domain       sql.NullString `json:"domain"`
 	CollectionID sql.NullInt64  `json:"collection_id"`
 	IsPublic     bool           `json:"is_public"`
 	IsFavorite   bool           `json:"is_favorite"`
+	IsArchived   bool           `json:"is_archived"`
 	SortOrder    int            `json:"sort_order"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
+	CoverImageID sql.NullInt64  `json:"cover_image_id"`
+	FaviconID    sql.NullInt64  `json:"favicon_id"`
+	ThumbnailID  sql.NullInt64  `json:"thumbnail_id"`
+	ClickCount   int            `json:"click_count"`
 	Collection   *Collection    `json:"collection,omitempty"`
+	Tags         []Tag          `json:"tags,omitempty"` // not loaded for public listings
 }
 
 // GetDescription returns the description or empty string
@@ -32,16 +39,50 @@ func (b *Bookmark) GetDescription() string {
 	return ""
 }
 
-// GetCoverImage returns the cover image URL or empty string
+// GetNote returns the personal note or empty string
+func (b *Bookmark) GetNote() string {
+	if b.Note.Valid {
+		return b.Note.String
+	}
+	return ""
+}
+
+// GetCoverImage returns the cover image URL or empty string.
+// A locally stored image takes precedence over the remote URL.
 func (b *Bookmark) GetCoverImage() string {
+	if b.CoverImageID.Valid {
+		return ImagePath(b.CoverImageID.Int64)
+	}
 	if b.CoverImage.Valid {
 		return b.CoverImage.String
 	}
 	return ""
 }
 
-// GetFavicon returns the favicon URL or empty string
+// CoverImageURL returns the cover image URL for rendered output (HTML, OG
+// tags, feeds). Stored images are rewritten to the image base URL.
+func (b *Bookmark) CoverImageURL(base string) string {
+	if b.CoverImageID.Valid {
+		return ImageURL(base, b.CoverImageID.Int64)
+	}
+	return b.GetCoverImage()
+}
+
+// GetThumbnail returns the downscaled cover image URL for grid views.
+// Falls back to the full cover image when no thumbnail was generated.
+func (b *Bookmark) GetThumbnail(base string) string {
+	if b.ThumbnailID.Valid {
+		return ImageURL(base, b.ThumbnailID.Int64)
+	}
+	return b.CoverImageURL(base)
+}
+
+// GetFavicon returns the favicon URL or empty string.
+// A locally stored image takes precedence over the remote URL.
 func (b *Bookmark) GetFavicon() string {
+	if b.FaviconID.Valid {
+		return ImagePath(b.FaviconID.Int64)
+	}
 	if b.Favicon.Valid {
 		return b.Favicon.String
 	}
@@ -50,10 +91,13 @@ func (b *Bookmark) GetFavicon() string {
 
 // GetFaviconURL returns the favicon URL for display.
 // Falls back to Google's favicon service if no favicon is stored.
-func (b *Bookmark) GetFaviconURL() string {
-	// Use stored favicon URL if available
-	if b.Favicon.Valid && b.Favicon.String != "" {
-		return b.Favicon.String
+func (b *Bookmark) GetFaviconURL(base string) string {
+	// Use stored favicon if available
+	if b.FaviconID.Valid {
+		return ImageURL(base, b.FaviconID.Int64)
+	}
+	if favicon := b.GetFavicon(); favicon != "" {
+		return favicon
 	}
 	// Fall back to Google's favicon service
 	domain := b.GetDomain()
@@ -76,11 +120,16 @@ type CreateBookmarkInput struct {
 	URL          string `json:"url"`
 	Title        string `json:"title"`
 	Description  string `json:"description"`
+	Note         string `json:"note"`
 	CoverImage   string `json:"cover_image"`
 	Favicon      string `json:"favicon"`
 	CollectionID *int64 `json:"collection_id"`
 	IsPublic     bool   `json:"is_public"`
 	IsFavorite   bool   `json:"is_favorite"`
+
+	// KeepOriginalURL stores URL as given instead of stripping tracking
+	// parameters from it
+	KeepOriginalURL bool `json:"keep_original_url"`
 }
 
 // UpdateBookmarkInput represents input for updating a bookmark
@@ -88,6 +137,7 @@ type UpdateBookmarkInput struct {
 	URL          string `json:"url"`
 	Title        string `json:"title"`
 	Description  string `json:"description"`
+	Note         string `json:"note"`
 	CoverImage   string `json:"cover_image"`
 	Favicon      string `json:"favicon"`
 	CollectionID *int64 `json:"collection_id"`
@@ -102,6 +152,7 @@ func (input *CreateBookmarkInput) Validate() *FormErrors {
 	input.URL = strings.TrimSpace(input.URL)
 	input.Title = strings.TrimSpace(input.Title)
 	input.Description = strings.TrimSpace(input.Description)
+	input.Note = strings.TrimSpace(input.Note)
 	input.CoverImage = strings.TrimSpace(input.CoverImage)
 	input.Favicon = strings.TrimSpace(input.Favicon)
 
@@ -120,6 +171,7 @@ func (input *UpdateBookmarkInput) Validate() *FormErrors {
 	input.URL = strings.TrimSpace(input.URL)
 	input.Title = strings.TrimSpace(input.Title)
 	input.Description = strings.TrimSpace(input.Description)
+	input.Note = strings.TrimSpace(input.Note)
 	input.CoverImage = strings.TrimSpace(input.CoverImage)
 	input.Favicon = strings.TrimSpace(input.Favicon)
 
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"database/sql"
 	"strings"
 	"testing"
 )
@@ -304,3 +305,106 @@ func TestUpdateBookmarkInput_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestBookmark_ImageGetters(t *testing.T) {
+	tests := []struct {
+		name       string
+		bookmark   Bookmark
+		wantCover  string
+		wantFavico string
+	}{
+		{
+			name:       "no images",
+			bookmark:   Bookmark{},
+			wantCover:  "",
+			wantFavico: "",
+		},
+		{
+			name: "remote URLs",
+			bookmark: Bookmark{
+				CoverImage: sql.NullString{String: "https://example.com/cover.png", Valid: true},
+				Favicon:    sql.NullString{String: "https://example.com/favicon.ico", Valid: true},
+			},
+			wantCover:  "https://example.com/cover.png",
+			wantFavico: "https://example.com/favicon.ico",
+		},
+		{
+			name: "stored images take precedence",
+			bookmark: Bookmark{
+				CoverImage:   sql.NullString{String: "https://example.com/cover.png", Valid: true},
+				Favicon:      sql.NullString{String: "https://example.com/favicon.ico", Valid: true},
+				CoverImageID: sql.NullInt64{Int64: 12, Valid: true},
+				FaviconID:    sql.NullInt64{Int64: 13, Valid: true},
+			},
+			wantCover:  "/images/12",
+			wantFavico: "/images/13",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.bookmark.GetCoverImage(); got != tt.wantCover {
+				t.Errorf("GetCoverImage() = %q, want %q", got, tt.wantCover)
+			}
+			if got := tt.bookmark.GetFavicon(); got != tt.wantFavico {
+				t.Errorf("GetFavicon() = %q, want %q", got, tt.wantFavico)
+			}
+		})
+	}
+}
+
+func TestBookmark_CoverImageURL(t *testing.T) {
+	stored := Bookmark{
+		CoverImage:   sql.NullString{String: "https://example.com/cover.png", Valid: true},
+		CoverImageID: sql.NullInt64{Int64: 5, Valid: true},
+		FaviconID:    sql.NullInt64{Int64: 6, Valid: true},
+	}
+	remote := Bookmark{
+		CoverImage: sql.NullString{String: "https://example.com/cover.png", Valid: true},
+	}
+
+	// Without a base URL stored images use local paths
+	if got := stored.CoverImageURL(""); got != "/images/5" {
+		t.Errorf("CoverImageURL() without base = %q, want /images/5", got)
+	}
+	if got := stored.GetFaviconURL(""); got != "/images/6" {
+		t.Errorf("GetFaviconURL() without base = %q, want /images/6", got)
+	}
+
+	// With a base URL stored images are rewritten; remote URLs are untouched
+	const base = "https://cdn.example.net/"
+	if got := stored.CoverImageURL(base); got != "https://cdn.example.net/images/5" {
+		t.Errorf("CoverImageURL() with base = %q, want https://cdn.example.net/images/5", got)
+	}
+	if got := stored.GetFaviconURL(base); got != "https://cdn.example.net/images/6" {
+		t.Errorf("GetFaviconURL() with base = %q, want https://cdn.example.net/images/6", got)
+	}
+	if got := remote.CoverImageURL(base); got != "https://example.com/cover.png" {
+		t.Errorf("CoverImageURL() for remote image = %q, want original URL", got)
+	}
+	// The local path stays available for the app's own fallback serving
+	if got := stored.GetCoverImage(); got != "/images/5" {
+		t.Errorf("GetCoverImage() = %q, want /images/5", got)
+	}
+}
+
+func TestBookmark_GetThumbnail(t *testing.T) {
+	withThumb := Bookmark{
+		CoverImageID: sql.NullInt64{Int64: 5, Valid: true},
+		ThumbnailID:  sql.NullInt64{Int64: 7, Valid: true},
+	}
+	if got := withThumb.GetThumbnail(""); got != "/images/7" {
+		t.Errorf("GetThumbnail() = %q, want /images/7", got)
+	}
+	if got := withThumb.CoverImageURL(""); got != "/images/5" {
+		t.Errorf("CoverImageURL() = %q, want full image /images/5", got)
+	}
+
+	// Without a thumbnail the full cover image is used
+	noThumb := Bookmark{
+		CoverImage: sql.NullString{String: "https://example.com/cover.png", Valid: true},
+	}
+	if got := noThumb.GetThumbnail(""); got != "https://example.com/cover.png" {
+		t.Errorf("GetThumbnail() = %q, want cover image fallback", got)
+	}
+}
@@ -13,6 +13,7 @@ type Collection struct {
 	Slug          string         `json:"slug"`
 	Description   sql.NullString `json:"description"`
 	Color         sql.NullString `json:"color"`
+	CoverImage    sql.NullString `json:"cover_image"`
 	ParentID      sql.NullInt64  `json:"parent_id"`
 	SortOrder     int            `json:"sort_order"`
 	IsPublic      bool           `json:"is_public"`
@@ -38,12 +39,31 @@ func (c *Collection) GetColor() string {
 	return ""
 }
 
+// GetCoverImage returns the cover image URL or empty string
+func (c *Collection) GetCoverImage() string {
+	if c.CoverImage.Valid {
+		return c.CoverImage.String
+	}
+	return ""
+}
+
+// CoverImageURL returns the cover image URL for rendered output. A stored
+// image path is rewritten to the image base URL.
+func (c *Collection) CoverImageURL(base string) string {
+	cover := c.GetCoverImage()
+	if IsImagePath(cover) {
+		return imageURL(base, cover)
+	}
+	return cover
+}
+
 // CreateCollectionInput represents input for creating a collection
 type CreateCollectionInput struct {
 	Name        string `json:"name"`
 	Slug        string `json:"slug"`
 	Description string `json:"description"`
 	Color       string `json:"color"`
+	CoverImage  string `json:"cover_image"`
 	ParentID    *int64 `json:"parent_id"`
 	IsPublic    bool   `json:"is_public"`
 }
@@ -54,9 +74,9 @@ type UpdateCollectionInput struct {
 	Slug        string `json:"slug"`
 	Description string `json:"description"`
 	Color       string `json:"color"`
+	CoverImage  string `json:"cover_image"`
 	ParentID    *int64 `json:"parent_id"`
 	IsPublic    bool   `json:"is_public"`
-	SortOrder   int    `json:"sort_order"`
 }
 
 // Validate validates the CreateCollectionInput and returns field-level errors.
@@ -67,9 +87,10 @@ func (input *CreateCollectionInput) Validate() *FormErrors {
 	input.Slug = strings.TrimSpace(input.Slug)
 	input.Description = strings.TrimSpace(input.Description)
 	input.Color = strings.TrimSpace(input.Color)
+	input.CoverImage = strings.TrimSpace(input.CoverImage)
 
 	errors := NewFormErrors()
-	validateCollectionFields(input.Name, input.Slug, input.Description, input.Color, errors)
+	validateCollectionFields(input.Name, input.Slug, input.Description, input.Color, input.CoverImage, errors)
 	if errors.HasErrors() {
 		return errors
 	}
@@ -84,9 +105,10 @@ func (input *UpdateCollectionInput) Validate() *FormErrors {
 	input.Slug = strings.TrimSpace(input.Slug)
 	input.Description = strings.TrimSpace(input.Description)
 	input.Color = strings.TrimSpace(input.Color)
+	input.CoverImage = strings.TrimSpace(input.CoverImage)
 
 	errors := NewFormErrors()
-	validateCollectionFields(input.Name, input.Slug, input.Description, input.Color, errors)
+	validateCollectionFields(input.Name, input.Slug, input.Description, input.Color, input.CoverImage, errors)
 	if errors.HasErrors() {
 		return errors
 	}
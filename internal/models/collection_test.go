@@ -145,6 +145,24 @@ func TestCreateCollectionInput_Validate(t *testing.T) {
 			},
 			wantErrors: nil,
 		},
+		{
+			name: "invalid cover image",
+			input: CreateCollectionInput{
+				Name:       "My Collection",
+				Slug:       "my-collection",
+				CoverImage: "not a url",
+			},
+			wantErrors: []string{"cover_image"},
+		},
+		{
+			name: "uploaded cover image path",
+			input: CreateCollectionInput{
+				Name:       "My Collection",
+				Slug:       "my-collection",
+				CoverImage: "/images/4",
+			},
+			wantErrors: nil,
+		},
 		{
 			name: "invalid color - no hash",
 			input: CreateCollectionInput{
@@ -155,14 +173,23 @@ func TestCreateCollectionInput_Validate(t *testing.T) {
 			wantErrors: []string{"color"},
 		},
 		{
-			name: "invalid color - short",
+			name: "invalid color - wrong length",
 			input: CreateCollectionInput{
 				Name:  "My Collection",
 				Slug:  "my-collection",
-				Color: "#3b8",
+				Color: "#3b82",
 			},
 			wantErrors: []string{"color"},
 		},
+		{
+			name: "short color is valid",
+			input: CreateCollectionInput{
+				Name:  "My Collection",
+				Slug:  "my-collection",
+				Color: "#3B8",
+			},
+			wantErrors: nil,
+		},
 		{
 			name: "invalid color - invalid chars",
 			input: CreateCollectionInput{
@@ -252,7 +279,6 @@ func TestUpdateCollectionInput_Validate(t *testing.T) {
 				Description: "A great collection",
 				Color:       "#ff0000",
 				IsPublic:    true,
-				SortOrder:   5,
 			},
 			wantErrors: nil,
 		},
@@ -305,6 +331,15 @@ func TestUpdateCollectionInput_Validate(t *testing.T) {
 			},
 			wantErrors: []string{"description"},
 		},
+		{
+			name: "valid short color",
+			input: UpdateCollectionInput{
+				Name:  "My Collection",
+				Slug:  "my-collection",
+				Color: "#abc",
+			},
+			wantErrors: nil,
+		},
 		{
 			name: "invalid color",
 			input: UpdateCollectionInput{
@@ -1,8 +1,10 @@
 package models
 
 import (
+	"net"
 	"net/url"
 	"regexp"
+	"strings"
 )
 
 // FormErrors holds validation errors for forms
@@ -55,8 +57,8 @@ func (e *FormErrors) HasField(field string) bool {
 // Slug pattern: lowercase letters, numbers, and hyphens only
 var slugPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
 
-// Hex color pattern: # followed by 6 hex characters
-var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+// Hex color pattern: # followed by 3 or 6 hex characters
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
 
 // IsValidSlug checks if a string is a valid slug
 func IsValidSlug(s string) bool {
@@ -75,7 +77,28 @@ func IsValidURL(s string) bool {
 	return u.Scheme == "http" || u.Scheme == "https"
 }
 
-// IsValidHexColor checks if a string is a valid hex color
+// IsPublicURL checks if a string is an http(s) URL that doesn't point at
+// localhost or a private, loopback or link-local IP literal. Hostnames are
+// not resolved, so this guards against obvious internal targets only.
+func IsPublicURL(s string) bool {
+	if !IsValidURL(s) {
+		return false
+	}
+	u, _ := url.Parse(s)
+	host := strings.ToLower(u.Hostname())
+	if host == "" || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+			ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValidHexColor checks if a string is a valid #RGB or #RRGGBB hex color
 func IsValidHexColor(s string) bool {
 	if s == "" {
 		return true // Empty is valid (optional field)
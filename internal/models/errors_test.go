@@ -203,6 +203,39 @@ func TestIsValidURL(t *testing.T) {
 	}
 }
 
+func TestIsPublicURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		// Public URLs
+		{"https URL", "https://example.com/page", true},
+		{"public IP", "http://93.184.216.34/", true},
+
+		// Rejected URLs
+		{"empty string", "", false},
+		{"javascript", "javascript:alert(1)", false},
+		{"data URL", "data:text/html,hi", false},
+		{"localhost", "http://localhost:8080/admin", false},
+		{"localhost subdomain", "http://api.localhost/", false},
+		{"loopback IP", "http://127.0.0.1/", false},
+		{"private IP", "http://192.168.1.10/", false},
+		{"link-local IP", "http://169.254.169.254/latest/meta-data", false},
+		{"IPv6 loopback", "http://[::1]/", false},
+		{"unspecified IP", "http://0.0.0.0/", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsPublicURL(tt.input)
+			if got != tt.want {
+				t.Errorf("IsPublicURL(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsValidSlug(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -252,10 +285,14 @@ func TestIsValidHexColor(t *testing.T) {
 		{"black", "#000000", true},
 		{"white", "#ffffff", true},
 		{"red", "#ff0000", true},
+		{"short hex", "#3b8", true},
+		{"short uppercase hex", "#FFF", true},
 
 		// Invalid colors
 		{"no hash", "3b82f6", false},
-		{"short hex", "#3b8", false},
+		{"four digits", "#3b82", false},
+		{"five digits", "#3b82f", false},
+		{"short no hash", "fff", false},
 		{"too long", "#3b82f6ff", false},
 		{"invalid chars", "#gggggg", false},
 		{"double hash", "##3b82f6", false},
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// imageBaseURLContextKey holds the image base URL of a request
+type imageBaseURLContextKey struct{}
+
+// Image is an image blob stored in the database and served from /images/{id}
+type Image struct {
+	ID        int64     `json:"id"`
+	MimeType  string    `json:"mime_type"`
+	Data      []byte    `json:"-"`
+	Size      int64     `json:"size"`
+	SourceURL string    `json:"source_url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MaxCoverImageSize is the largest cover image file accepted as an upload
+const MaxCoverImageSize = 5 << 20 // 5 MB
+
+// ImagePath returns the site-relative URL a stored image is served from
+func ImagePath(id int64) string {
+	return "/images/" + strconv.FormatInt(id, 10)
+}
+
+// IsImagePath reports whether s is the /images/{id} path of a stored image
+func IsImagePath(s string) bool {
+	_, ok := ImageIDFromPath(s)
+	return ok
+}
+
+// ImageIDFromPath returns the stored image ID in an /images/{id} path
+func ImageIDFromPath(s string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(s, "/images/"), 10, 64)
+	if err != nil || id <= 0 || s != ImagePath(id) {
+		return 0, false
+	}
+	return id, true
+}
+
+// WithImageBaseURL returns ctx carrying the base URL (e.g. a CDN) stored
+// images are served from in rendered output. An empty base keeps the local
+// /images/{id} paths.
+func WithImageBaseURL(ctx context.Context, base string) context.Context {
+	return context.WithValue(ctx, imageBaseURLContextKey{}, base)
+}
+
+// ImageBaseURL returns the image base URL carried by ctx, or "" for none
+func ImageBaseURL(ctx context.Context) string {
+	base, _ := ctx.Value(imageBaseURLContextKey{}).(string)
+	return base
+}
+
+// ImageURL returns the public URL of a stored image under base, which may
+// be empty to use the local path
+func ImageURL(base string, id int64) string {
+	return imageURL(base, ImagePath(id))
+}
+
+// imageURL joins base and a stored image path
+func imageURL(base, path string) string {
+	return strings.TrimRight(base, "/") + path
+}
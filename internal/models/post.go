@@ -15,12 +15,28 @@ type Post struct {
 	Excerpt     sql.NullString `json:"excerpt"`
 	CoverImage  sql.NullString `json:"cover_image"`
 	IsDraft     bool           `json:"is_draft"`
+	IsFeatured  bool           `json:"is_featured"`
 	PublishedAt sql.NullTime   `json:"published_at"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   sql.NullTime   `json:"deleted_at"` // set while the post is in the trash
 	Tags        []Tag          `json:"tags,omitempty"`
 }
 
+// PostUpdateGrace is how long after publishing an edit still counts as part
+// of the original release. Publishing and draft toggles bump updated_at too,
+// so only changes beyond this grace are treated as meaningful updates.
+const PostUpdateGrace = 24 * time.Hour
+
+// MeaningfullyUpdated reports whether the post was revised after it was
+// published, ignoring the updated_at bumps that come with publishing itself.
+func (p *Post) MeaningfullyUpdated() bool {
+	if !p.PublishedAt.Valid {
+		return false
+	}
+	return p.UpdatedAt.Sub(p.PublishedAt.Time) > PostUpdateGrace
+}
+
 // GetExcerpt returns the excerpt or empty string
 func (p *Post) GetExcerpt() string {
 	if p.Excerpt.Valid {
@@ -37,6 +53,16 @@ func (p *Post) GetCoverImage() string {
 	return ""
 }
 
+// CoverImageURL returns the cover image URL for rendered output. An uploaded
+// /images/{id} cover is rewritten to the image base URL.
+func (p *Post) CoverImageURL(base string) string {
+	cover := p.GetCoverImage()
+	if IsImagePath(cover) {
+		return imageURL(base, cover)
+	}
+	return cover
+}
+
 // CreatePostInput represents input for creating a post
 type CreatePostInput struct {
 	Title      string  `json:"title"`
@@ -45,6 +71,7 @@ type CreatePostInput struct {
 	Excerpt    string  `json:"excerpt"`
 	CoverImage string  `json:"cover_image"`
 	IsDraft    bool    `json:"is_draft"`
+	IsFeatured bool    `json:"is_featured"`
 	TagIDs     []int64 `json:"tag_ids"`
 }
 
@@ -56,6 +83,7 @@ type UpdatePostInput struct {
 	Excerpt    string  `json:"excerpt"`
 	CoverImage string  `json:"cover_image"`
 	IsDraft    bool    `json:"is_draft"`
+	IsFeatured bool    `json:"is_featured"`
 	TagIDs     []int64 `json:"tag_ids"`
 }
 
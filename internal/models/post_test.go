@@ -1,6 +1,7 @@
 package models
 
 import (
+	"database/sql"
 	"strings"
 	"testing"
 )
@@ -193,6 +194,28 @@ func TestCreatePostInput_Validate(t *testing.T) {
 			},
 			wantErrors: []string{"cover_image"},
 		},
+		{
+			name: "uploaded cover image path",
+			input: CreatePostInput{
+				Title:      "My Post",
+				Slug:       "my-post",
+				Content:    "Content",
+				CoverImage: "/images/42",
+				IsDraft:    false,
+			},
+			wantErrors: nil,
+		},
+		{
+			name: "other site-relative cover image path",
+			input: CreatePostInput{
+				Title:      "My Post",
+				Slug:       "my-post",
+				Content:    "Content",
+				CoverImage: "/images/42/../../admin",
+				IsDraft:    false,
+			},
+			wantErrors: []string{"cover_image"},
+		},
 		{
 			name: "empty cover image is valid",
 			input: CreatePostInput{
@@ -335,6 +358,16 @@ func TestUpdatePostInput_Validate(t *testing.T) {
 			},
 			wantErrors: []string{"content"},
 		},
+		{
+			name: "empty Editor.js content when publishing",
+			input: UpdatePostInput{
+				Title:   "My Post",
+				Slug:    "my-post",
+				Content: `{"blocks":[{"type":"paragraph","data":{"text":""}}]}`,
+				IsDraft: false,
+			},
+			wantErrors: []string{"content"},
+		},
 		{
 			name: "content not required for draft",
 			input: UpdatePostInput{
@@ -405,3 +438,21 @@ func TestUpdatePostInput_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestPost_CoverImageURL(t *testing.T) {
+	uploaded := Post{CoverImage: sql.NullString{String: "/images/5", Valid: true}}
+	remote := Post{CoverImage: sql.NullString{String: "https://example.com/cover.png", Valid: true}}
+
+	if got := uploaded.CoverImageURL(""); got != "/images/5" {
+		t.Errorf("CoverImageURL() without base = %q, want /images/5", got)
+	}
+	if got := uploaded.CoverImageURL("https://cdn.example.net/"); got != "https://cdn.example.net/images/5" {
+		t.Errorf("CoverImageURL() with base = %q, want https://cdn.example.net/images/5", got)
+	}
+	if got := remote.CoverImageURL("https://cdn.example.net"); got != "https://example.com/cover.png" {
+		t.Errorf("CoverImageURL() for remote image = %q, want original URL", got)
+	}
+	if got := (&Post{}).CoverImageURL("https://cdn.example.net"); got != "" {
+		t.Errorf("CoverImageURL() without cover = %q, want empty", got)
+	}
+}
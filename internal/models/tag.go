@@ -1,19 +1,46 @@
 package models
 
 import (
+	"database/sql"
+	"strings"
 	"time"
 )
 
 // Tag represents a tag for posts
 type Tag struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Slug      string    `json:"slug"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int64          `json:"id"`
+	Name      string         `json:"name"`
+	Slug      string         `json:"slug"`
+	Color     sql.NullString `json:"color"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// GetColor returns the color or empty string
+func (t *Tag) GetColor() string {
+	if t.Color.Valid {
+		return t.Color.String
+	}
+	return ""
 }
 
 // CreateTagInput represents input for creating a tag
 type CreateTagInput struct {
-	Name string `json:"name"`
-	Slug string `json:"slug"`
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+	Color string `json:"color"`
+}
+
+// Validate validates the CreateTagInput and returns field-level errors.
+// It also trims whitespace from string fields.
+func (input *CreateTagInput) Validate() *FormErrors {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Slug = strings.TrimSpace(input.Slug)
+	input.Color = strings.TrimSpace(input.Color)
+
+	errors := NewFormErrors()
+	validateTagFields(input.Name, input.Slug, input.Color, errors)
+	if errors.HasErrors() {
+		return errors
+	}
+	return nil
 }
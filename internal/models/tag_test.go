@@ -0,0 +1,47 @@
+package models
+
+import "testing"
+
+func TestCreateTagInput_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      CreateTagInput
+		wantErrors []string
+	}{
+		{"valid without color", CreateTagInput{Name: "Go", Slug: "go"}, nil},
+		{"valid long color", CreateTagInput{Name: "Go", Slug: "go", Color: "#00ADD8"}, nil},
+		{"valid short color", CreateTagInput{Name: "Go", Slug: "go", Color: "#0ad"}, nil},
+		{"color is trimmed", CreateTagInput{Name: "Go", Slug: "go", Color: " #0ad "}, nil},
+		{"empty slug is allowed", CreateTagInput{Name: "Go"}, nil},
+		{"color without hash", CreateTagInput{Name: "Go", Slug: "go", Color: "00ADD8"}, []string{"color"}},
+		{"color name", CreateTagInput{Name: "Go", Slug: "go", Color: "blue"}, []string{"color"}},
+		{"color with css injection", CreateTagInput{Name: "Go", Slug: "go", Color: "#fff;background:url(x)"}, []string{"color"}},
+		{"missing name", CreateTagInput{Slug: "go"}, []string{"name"}},
+		{"invalid slug", CreateTagInput{Name: "Go", Slug: "Go Lang"}, []string{"slug"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := tt.input.Validate()
+
+			if tt.wantErrors == nil {
+				if errors != nil {
+					t.Errorf("Validate() returned errors, want nil: %+v", errors.Fields)
+				}
+				return
+			}
+
+			if errors == nil {
+				t.Fatalf("Validate() returned nil, want errors for fields: %v", tt.wantErrors)
+			}
+			if len(errors.Fields) != len(tt.wantErrors) {
+				t.Errorf("Validate() errors = %v, want fields %v", errors.Fields, tt.wantErrors)
+			}
+			for _, field := range tt.wantErrors {
+				if !errors.HasField(field) {
+					t.Errorf("Validate() missing error for field %q", field)
+				}
+			}
+		})
+	}
+}
@@ -1,20 +1,126 @@
 package models
 
-import "time"
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"strings"
+	"time"
+)
 
 // User represents an admin user
 type User struct {
-	ID           int64     `json:"id"`
-	Username     string    `json:"username"`
-	PasswordHash string    `json:"-"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                  int64        `json:"id"`
+	Username            string       `json:"username"`
+	PasswordHash        string       `json:"-"`
+	CreatedAt           time.Time    `json:"created_at"`
+	UpdatedAt           time.Time    `json:"updated_at"`
+	FailedLoginAttempts int          `json:"failed_login_attempts"`
+	LockedUntil         sql.NullTime `json:"locked_until"`
+}
+
+// IsLocked reports whether the account is locked out at the given time
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil.Valid && now.Before(u.LockedUntil.Time)
+}
+
+// Password strength limits. bcrypt ignores everything past 72 bytes, so
+// longer passwords would silently lose their tail.
+const (
+	minPasswordLength   = 12
+	maxPasswordBytes    = 72
+	minPasswordDistinct = 6
+)
+
+// PasswordStrengthError returns why a password is too weak to set, or ""
+// when it's acceptable
+func PasswordStrengthError(password string) string {
+	switch {
+	case len([]rune(password)) < minPasswordLength:
+		return "Password must be at least 12 characters"
+	case len(password) > maxPasswordBytes:
+		return "Password cannot exceed 72 bytes"
+	}
+
+	distinct := make(map[rune]struct{})
+	for _, r := range password {
+		distinct[r] = struct{}{}
+	}
+	if len(distinct) < minPasswordDistinct {
+		return "Password is too repetitive"
+	}
+	return ""
+}
+
+// ChangePasswordInput is the password change form
+type ChangePasswordInput struct {
+	CurrentPassword string
+	NewPassword     string
+	ConfirmPassword string
+}
+
+// Validate checks the form and returns field-level errors. Whether the
+// current password is correct is checked by the service.
+func (input *ChangePasswordInput) Validate() *FormErrors {
+	errors := NewFormErrors()
+
+	if input.CurrentPassword == "" {
+		errors.AddField("current_password", "Current password is required")
+	}
+	if msg := PasswordStrengthError(input.NewPassword); msg != "" {
+		errors.AddField("new_password", msg)
+	} else if input.NewPassword == input.CurrentPassword {
+		errors.AddField("new_password", "New password must differ from the current one")
+	} else if strings.TrimSpace(input.NewPassword) != input.NewPassword {
+		errors.AddField("new_password", "Password cannot start or end with a space")
+	}
+	if input.ConfirmPassword != input.NewPassword {
+		errors.AddField("confirm_password", "Passwords do not match")
+	}
+
+	if errors.HasErrors() {
+		return errors
+	}
+	return nil
 }
 
 // Session represents an authenticated session
 type Session struct {
-	ID        string    `json:"id"`
-	UserID    int64     `json:"user_id"`
-	ExpiresAt time.Time `json:"expires_at"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        string        `json:"-"` // the login credential; never expose
+	UserID    int64         `json:"user_id"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	CreatedAt time.Time     `json:"created_at"`
+	Duration  time.Duration `json:"duration"` // lifetime the session was issued with
+	UserAgent string        `json:"user_agent"`
+	IPAddress string        `json:"ip_address"`
+	// LastSeenAt is when the session last made an authenticated request,
+	// updated at most once a minute
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// SessionDuration is the lifetime of a login without "remember me"
+const SessionDuration = 24 * time.Hour
+
+// Remembered reports whether the session was issued by a "remember me"
+// login, which outlives a normal session
+func (s *Session) Remembered() bool {
+	return s.Duration > SessionDuration
+}
+
+// IdleFor returns how long the session has gone unused at the given time
+func (s *Session) IdleFor(now time.Time) time.Duration {
+	return now.Sub(s.LastSeenAt)
+}
+
+// Handle returns a stable, non-secret identifier for the session that can
+// be shown in pages and URLs in place of the ID
+func (s *Session) Handle() string {
+	sum := sha256.Sum256([]byte(s.ID))
+	return hex.EncodeToString(sum[:8])
+}
+
+// SessionClient identifies the browser a session is created for
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
 }
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestChangePasswordInput_Validate(t *testing.T) {
+	const strong = "correct horse battery"
+
+	tests := []struct {
+		name       string
+		input      ChangePasswordInput
+		wantErrors []string
+	}{
+		{
+			name:  "valid input",
+			input: ChangePasswordInput{CurrentPassword: "old-password", NewPassword: strong, ConfirmPassword: strong},
+		},
+		{
+			name:       "missing current password",
+			input:      ChangePasswordInput{NewPassword: strong, ConfirmPassword: strong},
+			wantErrors: []string{"current_password"},
+		},
+		{
+			name:       "too short",
+			input:      ChangePasswordInput{CurrentPassword: "old-password", NewPassword: "short1!", ConfirmPassword: "short1!"},
+			wantErrors: []string{"new_password"},
+		},
+		{
+			name:       "too long for bcrypt",
+			input:      ChangePasswordInput{CurrentPassword: "old-password", NewPassword: strings.Repeat("abcdefgh", 10), ConfirmPassword: strings.Repeat("abcdefgh", 10)},
+			wantErrors: []string{"new_password"},
+		},
+		{
+			name:       "repetitive",
+			input:      ChangePasswordInput{CurrentPassword: "old-password", NewPassword: "aaaaaaaaaaaaaaaa", ConfirmPassword: "aaaaaaaaaaaaaaaa"},
+			wantErrors: []string{"new_password"},
+		},
+		{
+			name:       "same as current",
+			input:      ChangePasswordInput{CurrentPassword: strong, NewPassword: strong, ConfirmPassword: strong},
+			wantErrors: []string{"new_password"},
+		},
+		{
+			name:       "confirmation mismatch",
+			input:      ChangePasswordInput{CurrentPassword: "old-password", NewPassword: strong, ConfirmPassword: strong + "!"},
+			wantErrors: []string{"confirm_password"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := tt.input.Validate()
+
+			if tt.wantErrors == nil {
+				if errors != nil {
+					t.Errorf("Validate() returned errors, want nil: %+v", errors.Fields)
+				}
+				return
+			}
+
+			if errors == nil {
+				t.Fatalf("Validate() returned nil, want errors for fields: %v", tt.wantErrors)
+			}
+			for field := range errors.Fields {
+				if !slices.Contains(tt.wantErrors, field) {
+					t.Errorf("Validate() unexpected error for field %q: %s", field, errors.Fields[field])
+				}
+			}
+			for _, field := range tt.wantErrors {
+				if !errors.HasField(field) {
+					t.Errorf("Validate() missing error for field %q", field)
+				}
+			}
+		})
+	}
+}
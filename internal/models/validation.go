@@ -1,6 +1,10 @@
 package models
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/EC-9624/0xec.dev/internal/renderer"
+)
 
 // ============================================
 // SHARED VALIDATION FUNCTIONS
@@ -59,20 +63,21 @@ func validatePostFields(title, slug, content, coverImage string, isDraft bool, e
 		errors.AddField("slug", "Slug can only contain lowercase letters, numbers, and hyphens")
 	}
 
-	// Content validation - required only if publishing
-	if !isDraft && strings.TrimSpace(content) == "" {
+	// Content validation - required only if publishing. An Editor.js
+	// document of empty blocks counts as empty.
+	if !isDraft && renderer.IsEmptyContent(content) {
 		errors.AddField("content", "Content is required when publishing")
 	}
 
-	// Cover image URL validation
-	if coverImage != "" && !IsValidURL(coverImage) {
+	// Cover image validation: a URL, or the path of an uploaded image
+	if coverImage != "" && !IsValidURL(coverImage) && !IsImagePath(coverImage) {
 		errors.AddField("cover_image", "Cover image must be a valid URL")
 	}
 }
 
 // validateCollectionFields validates common collection fields.
 // Call this from both CreateCollectionInput.Validate() and UpdateCollectionInput.Validate().
-func validateCollectionFields(name, slug, description, color string, errors *FormErrors) {
+func validateCollectionFields(name, slug, description, color, coverImage string, errors *FormErrors) {
 	// Name validation
 	nameTrimmed := strings.TrimSpace(name)
 	if nameTrimmed == "" {
@@ -96,8 +101,37 @@ func validateCollectionFields(name, slug, description, color string, errors *For
 		errors.AddField("description", "Description cannot exceed 500 characters")
 	}
 
-	// Color validation (optional, must be valid hex if provided)
+	validateColorField(color, errors)
+
+	// Cover image validation: a URL, or the path of an uploaded image
+	if coverImage != "" && !IsValidURL(coverImage) && !IsImagePath(coverImage) {
+		errors.AddField("cover_image", "Cover image must be a valid URL")
+	}
+}
+
+// validateTagFields validates tag fields.
+// Call this from CreateTagInput.Validate().
+func validateTagFields(name, slug, color string, errors *FormErrors) {
+	// Name validation
+	nameTrimmed := strings.TrimSpace(name)
+	if nameTrimmed == "" {
+		errors.AddField("name", "Name is required")
+	} else if len(nameTrimmed) > 50 {
+		errors.AddField("name", "Name cannot exceed 50 characters")
+	}
+
+	// Slug validation (optional)
+	if slug != "" && !IsValidSlug(slug) {
+		errors.AddField("slug", "Slug can only contain lowercase letters, numbers, and hyphens")
+	}
+
+	validateColorField(color, errors)
+}
+
+// validateColorField checks an optional color, which is written into
+// inline styles and so must be a plain #RGB or #RRGGBB hex code
+func validateColorField(color string, errors *FormErrors) {
 	if color != "" && !IsValidHexColor(color) {
-		errors.AddField("color", "Color must be a valid hex color (e.g., #3b82f6)")
+		errors.AddField("color", "Color must be a valid hex color (e.g., #3b82f6 or #38f)")
 	}
 }
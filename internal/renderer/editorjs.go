@@ -0,0 +1,410 @@
+// Package renderer converts stored post content between formats.
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// editorJSDocument is the saved output of Editor.js
+type editorJSDocument struct {
+	Blocks []editorJSBlock `json:"blocks"`
+}
+
+// editorJSBlock is a single Editor.js block. Data is decoded per block type.
+type editorJSBlock struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// IsEditorJS reports whether content is an Editor.js document rather than
+// Markdown
+func IsEditorJS(content string) bool {
+	_, ok := parseEditorJS(content)
+	return ok
+}
+
+func parseEditorJS(content string) (*editorJSDocument, bool) {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, "{") {
+		return nil, false
+	}
+
+	var doc editorJSDocument
+	if err := json.Unmarshal([]byte(trimmed), &doc); err != nil || doc.Blocks == nil {
+		return nil, false
+	}
+	return &doc, true
+}
+
+// EditorJSToMarkdown converts an Editor.js document to Markdown. Inline
+// formatting (bold, italic, code, links) is kept; block types with no
+// Markdown equivalent are dropped.
+func EditorJSToMarkdown(content string) (string, error) {
+	doc, ok := parseEditorJS(content)
+	if !ok {
+		return "", fmt.Errorf("not an Editor.js document")
+	}
+
+	var blocks []string
+	for _, block := range doc.Blocks {
+		md, err := blockToMarkdown(block)
+		if err != nil {
+			return "", fmt.Errorf("%s block: %w", block.Type, err)
+		}
+		if md != "" {
+			blocks = append(blocks, md)
+		}
+	}
+	return strings.Join(blocks, "\n\n") + "\n", nil
+}
+
+// blockToMarkdown renders one block, returning "" for blocks to skip
+func blockToMarkdown(block editorJSBlock) (string, error) {
+	switch block.Type {
+	case "paragraph":
+		var data struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			return "", err
+		}
+		return inlineToMarkdown(data.Text), nil
+
+	case "header":
+		var data struct {
+			Text  string `json:"text"`
+			Level int    `json:"level"`
+		}
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			return "", err
+		}
+		level := min(max(data.Level, 1), 6)
+		return strings.Repeat("#", level) + " " + inlineToMarkdown(data.Text), nil
+
+	case "list", "checklist":
+		var data struct {
+			Style string     `json:"style"`
+			Items []listItem `json:"items"`
+		}
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			return "", err
+		}
+		if block.Type == "checklist" {
+			data.Style = "checklist"
+		}
+		var b strings.Builder
+		writeList(&b, data.Items, data.Style, "")
+		return strings.TrimRight(b.String(), "\n"), nil
+
+	case "code":
+		var data struct {
+			Code     string `json:"code"`
+			Language string `json:"language"`
+		}
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			return "", err
+		}
+		fence := codeFence(data.Code)
+		return fence + data.Language + "\n" + strings.TrimRight(data.Code, "\n") + "\n" + fence, nil
+
+	case "quote":
+		var data struct {
+			Text    string `json:"text"`
+			Caption string `json:"caption"`
+		}
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			return "", err
+		}
+		text := inlineToMarkdown(data.Text)
+		if caption := inlineToMarkdown(data.Caption); caption != "" {
+			text += "\n\n— " + caption
+		}
+		return quoteLines(text), nil
+
+	case "delimiter":
+		return "---", nil
+
+	case "image":
+		var data struct {
+			File struct {
+				URL string `json:"url"`
+			} `json:"file"`
+			URL     string `json:"url"`
+			Caption string `json:"caption"`
+		}
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			return "", err
+		}
+		src := data.File.URL
+		if src == "" {
+			src = data.URL
+		}
+		if src == "" {
+			return "", nil
+		}
+		return "![" + plainText(data.Caption) + "](" + markdownURL(src) + ")", nil
+
+	case "embed":
+		var data struct {
+			Source string `json:"source"`
+		}
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			return "", err
+		}
+		if data.Source == "" {
+			return "", nil
+		}
+		return "<" + data.Source + ">", nil
+
+	case "table":
+		var data struct {
+			WithHeadings bool       `json:"withHeadings"`
+			Content      [][]string `json:"content"`
+		}
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			return "", err
+		}
+		return tableToMarkdown(data.Content, data.WithHeadings), nil
+
+	case "raw":
+		var data struct {
+			HTML string `json:"html"`
+		}
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(data.HTML), nil
+	}
+
+	return "", nil
+}
+
+// listItem is a list entry: a plain string in older Editor.js list tools,
+// an object with nested items in newer ones
+type listItem struct {
+	Content string
+	Checked bool
+	Items   []listItem
+}
+
+func (li *listItem) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		li.Content = s
+		return nil
+	}
+
+	var item struct {
+		Content string     `json:"content"`
+		Text    string     `json:"text"`
+		Checked bool       `json:"checked"`
+		Items   []listItem `json:"items"`
+		Meta    struct {
+			Checked bool `json:"checked"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(b, &item); err != nil {
+		return err
+	}
+	li.Content = item.Content
+	if li.Content == "" {
+		li.Content = item.Text
+	}
+	li.Checked = item.Checked || item.Meta.Checked
+	li.Items = item.Items
+	return nil
+}
+
+// writeList writes list items, indenting nested lists under their parent
+func writeList(b *strings.Builder, items []listItem, style, indent string) {
+	for i, item := range items {
+		var marker string
+		switch style {
+		case "ordered":
+			marker = strconv.Itoa(i+1) + ". "
+		case "checklist":
+			marker = "- [ ] "
+			if item.Checked {
+				marker = "- [x] "
+			}
+		default:
+			marker = "- "
+		}
+
+		b.WriteString(indent + marker + inlineToMarkdown(item.Content) + "\n")
+		if len(item.Items) > 0 {
+			writeList(b, item.Items, style, indent+strings.Repeat(" ", len(marker)))
+		}
+	}
+}
+
+// codeFence returns a backtick fence longer than any backtick run in code
+func codeFence(code string) string {
+	longest, run := 0, 0
+	for _, r := range code {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// quoteLines prefixes every line of text with a blockquote marker
+func quoteLines(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tableToMarkdown renders rows as a GFM table. Without headings an empty
+// header row is added, since GFM tables require one.
+func tableToMarkdown(rows [][]string, withHeadings bool) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	cols := 0
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+
+	cell := func(row []string, i int) string {
+		if i >= len(row) {
+			return ""
+		}
+		return strings.ReplaceAll(inlineToMarkdown(row[i]), "|", `\|`)
+	}
+	line := func(row []string) string {
+		cells := make([]string, cols)
+		for i := range cells {
+			cells[i] = cell(row, i)
+		}
+		return "| " + strings.Join(cells, " | ") + " |"
+	}
+
+	var lines []string
+	body := rows
+	if withHeadings {
+		lines = append(lines, line(rows[0]))
+		body = rows[1:]
+	} else {
+		lines = append(lines, line(make([]string, cols)))
+	}
+	lines = append(lines, "|"+strings.Repeat(" --- |", cols))
+	for _, row := range body {
+		lines = append(lines, line(row))
+	}
+	return strings.Join(lines, "\n")
+}
+
+var (
+	inlineTagPattern = regexp.MustCompile(`<(/?)([a-zA-Z]+)([^>]*)>`)
+	hrefPattern      = regexp.MustCompile(`href\s*=\s*"([^"]*)"`)
+)
+
+// inlineMarkers maps the inline tags Editor.js produces to Markdown. Tags
+// with no Markdown form (sub, sup, u, mark) are dropped and their text
+// kept, since posts render Markdown with raw HTML disabled.
+var inlineMarkers = map[string]string{
+	"b":      "**",
+	"strong": "**",
+	"i":      "_",
+	"em":     "_",
+	"code":   "`",
+	"s":      "~~",
+	"del":    "~~",
+	"strike": "~~",
+}
+
+// inlineToMarkdown converts Editor.js inline HTML to Markdown. Text between
+// tags is unescaped and has Markdown punctuation escaped.
+func inlineToMarkdown(s string) string {
+	var b strings.Builder
+	var hrefs []string
+	inCode := false
+
+	last := 0
+	for _, m := range inlineTagPattern.FindAllStringSubmatchIndex(s, -1) {
+		writeInlineText(&b, s[last:m[0]], inCode)
+		last = m[1]
+
+		closing := s[m[2]:m[3]] == "/"
+		tag := strings.ToLower(s[m[4]:m[5]])
+		attrs := s[m[6]:m[7]]
+
+		switch tag {
+		case "br":
+			b.WriteString("  \n")
+		case "a":
+			if closing {
+				if n := len(hrefs); n > 0 {
+					b.WriteString("](" + markdownURL(hrefs[n-1]) + ")")
+					hrefs = hrefs[:n-1]
+				}
+			} else {
+				href := ""
+				if hm := hrefPattern.FindStringSubmatch(attrs); hm != nil {
+					href = html.UnescapeString(hm[1])
+				}
+				hrefs = append(hrefs, href)
+				b.WriteString("[")
+			}
+		default:
+			if marker, ok := inlineMarkers[tag]; ok {
+				b.WriteString(marker)
+				if tag == "code" {
+					inCode = !closing
+				}
+			}
+		}
+	}
+	writeInlineText(&b, s[last:], inCode)
+
+	return strings.TrimSpace(b.String())
+}
+
+// markdownEscaper escapes characters that would otherwise start inline
+// Markdown syntax
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+)
+
+func writeInlineText(b *strings.Builder, text string, inCode bool) {
+	text = strings.ReplaceAll(html.UnescapeString(text), "\u00a0", " ")
+	if inCode {
+		b.WriteString(text)
+		return
+	}
+	b.WriteString(markdownEscaper.Replace(text))
+}
+
+// plainText strips inline HTML, for places like image alt text where
+// formatting isn't allowed
+func plainText(s string) string {
+	text := html.UnescapeString(inlineTagPattern.ReplaceAllString(s, ""))
+	return strings.NewReplacer("[", "", "]", "").Replace(strings.TrimSpace(text))
+}
+
+// markdownURL makes a URL safe to use as a Markdown link destination
+func markdownURL(u string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(u)
+}
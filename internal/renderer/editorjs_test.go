@@ -0,0 +1,177 @@
+package renderer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+func TestIsEditorJS(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{`{"time":1,"blocks":[]}`, true},
+		{`  {"blocks":[{"type":"paragraph","data":{"text":"hi"}}]}`, true},
+		{"# Markdown", false},
+		{`{"title":"not editor.js"}`, false},
+		{"{ not json", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsEditorJS(tt.content); got != tt.want {
+			t.Errorf("IsEditorJS(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestEditorJSToMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		block string
+		want  string
+	}{
+		{
+			"paragraph with inline formatting",
+			`{"type":"paragraph","data":{"text":"Some <b>bold</b>, <i>italic</i> and <code class=\"inline-code\">a_b</code> text"}}`,
+			"Some **bold**, _italic_ and `a_b` text",
+		},
+		{
+			"link",
+			`{"type":"paragraph","data":{"text":"See <a href=\"https://example.com/a?b=1&amp;c=2\">the docs</a>."}}`,
+			"See [the docs](https://example.com/a?b=1&c=2).",
+		},
+		{
+			"escapes markdown characters",
+			`{"type":"paragraph","data":{"text":"2 * 3 &lt;tag&gt; [x]"}}`,
+			`2 \* 3 \<tag> \[x\]`,
+		},
+		{
+			"strikethrough tags",
+			`{"type":"paragraph","data":{"text":"<s>old</s> <strike>older</strike> <del>oldest</del>"}}`,
+			"~~old~~ ~~older~~ ~~oldest~~",
+		},
+		{
+			"sub, sup and u keep their text",
+			`{"type":"paragraph","data":{"text":"H<sub>2</sub>O, x<sup>2</sup> and <u>underlined</u>"}}`,
+			"H2O, x2 and underlined",
+		},
+		{
+			"tags with attributes are not restored",
+			`{"type":"paragraph","data":{"text":"<sup onclick=\"alert(1)\">1</sup>"}}`,
+			"1",
+		},
+		{
+			"script stays escaped",
+			`{"type":"paragraph","data":{"text":"&lt;script&gt;alert(1)&lt;/script&gt;"}}`,
+			`\<script>alert(1)\</script>`,
+		},
+		{
+			"header",
+			`{"type":"header","data":{"text":"Title","level":2}}`,
+			"## Title",
+		},
+		{
+			"code with language",
+			`{"type":"code","data":{"code":"func main() {\n\tfmt.Println(\"hi\")\n}","language":"go"}}`,
+			"```go\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```",
+		},
+		{
+			"code containing a fence",
+			"{\"type\":\"code\",\"data\":{\"code\":\"```\\nnested\\n```\"}}",
+			"````\n```\nnested\n```\n````",
+		},
+		{
+			"unordered list of strings",
+			`{"type":"list","data":{"style":"unordered","items":["one","two"]}}`,
+			"- one\n- two",
+		},
+		{
+			"nested ordered list",
+			`{"type":"list","data":{"style":"ordered","items":[{"content":"one","items":[{"content":"inner","items":[]}]},{"content":"two","items":[]}]}}`,
+			"1. one\n   1. inner\n2. two",
+		},
+		{
+			"checklist",
+			`{"type":"checklist","data":{"items":[{"text":"done","checked":true},{"text":"todo","checked":false}]}}`,
+			"- [x] done\n- [ ] todo",
+		},
+		{
+			"quote with caption",
+			`{"type":"quote","data":{"text":"Stay hungry","caption":"Someone"}}`,
+			"> Stay hungry\n>\n> — Someone",
+		},
+		{
+			"image",
+			`{"type":"image","data":{"file":{"url":"/images/3"},"caption":"A <b>cat</b>"}}`,
+			"![A cat](/images/3)",
+		},
+		{
+			"table",
+			`{"type":"table","data":{"withHeadings":true,"content":[["Name","Value"],["a|b","1"]]}}`,
+			"| Name | Value |\n| --- | --- |\n| a\\|b | 1 |",
+		},
+		{
+			"unknown block",
+			`{"type":"warning","data":{"title":"x"}}`,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EditorJSToMarkdown(`{"blocks":[` + tt.block + `]}`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got = strings.TrimSuffix(got, "\n"); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditorJSToMarkdown_NotEditorJS(t *testing.T) {
+	if _, err := EditorJSToMarkdown("# Markdown"); err == nil {
+		t.Error("expected error for Markdown input")
+	}
+}
+
+// TestEditorJSToMarkdown_RoundTrip renders the exported Markdown and checks
+// code, lists, and links survive as they would in the original post
+func TestEditorJSToMarkdown_RoundTrip(t *testing.T) {
+	content := `{"blocks":[
+		{"type":"paragraph","data":{"text":"Read <a href=\"https://go.dev/doc\">the <b>docs</b></a> first."}},
+		{"type":"code","data":{"code":"if a < b && c {\n    return \"*x*\"\n}","language":"go"}},
+		{"type":"list","data":{"style":"unordered","items":[{"content":"first","items":[{"content":"nested","items":[]}]},{"content":"second","items":[]}]}},
+		{"type":"list","data":{"style":"ordered","items":["alpha","beta"]}}
+	]}`
+
+	md, err := EditorJSToMarkdown(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := goldmark.New(goldmark.WithExtensions(extension.GFM)).Convert([]byte(md), &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`<a href="https://go.dev/doc">the <strong>docs</strong></a>`,
+		`<pre><code class="language-go">if a &lt; b &amp;&amp; c {
+    return &quot;*x*&quot;
+}
+</code></pre>`,
+		"<ul>\n<li>first\n<ul>\n<li>nested</li>\n</ul>\n</li>\n<li>second</li>\n</ul>",
+		"<ol>\n<li>alpha</li>\n<li>beta</li>\n</ol>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered HTML missing %q\ngot:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package renderer
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// IsEmptyContent reports whether post content has nothing to publish: blank
+// Markdown, or an Editor.js document whose blocks are all empty. Text blocks
+// count as empty when only tags, whitespace or &nbsp; are left; delimiters
+// never count as content.
+func IsEmptyContent(content string) bool {
+	doc, ok := parseEditorJS(content)
+	if !ok {
+		return strings.TrimSpace(content) == ""
+	}
+
+	for _, block := range doc.Blocks {
+		if blockHasContent(block) {
+			return false
+		}
+	}
+	return true
+}
+
+// blockHasContent reports whether a block renders anything meaningful.
+// Blocks whose data can't be decoded count as content, so a malformed
+// document isn't mistaken for an empty one.
+func blockHasContent(block editorJSBlock) bool {
+	switch block.Type {
+	case "paragraph", "header", "quote":
+		var data struct {
+			Text    string `json:"text"`
+			Caption string `json:"caption"`
+		}
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			return true
+		}
+		return plainText(data.Text) != "" || plainText(data.Caption) != ""
+
+	case "list", "checklist":
+		var data struct {
+			Items []listItem `json:"items"`
+		}
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			return true
+		}
+		return listHasContent(data.Items)
+
+	case "code":
+		var data struct {
+			Code string `json:"code"`
+		}
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			return true
+		}
+		return strings.TrimSpace(data.Code) != ""
+
+	case "delimiter":
+		return false
+	}
+
+	md, err := blockToMarkdown(block)
+	return err != nil || md != ""
+}
+
+// listHasContent reports whether any item of a list, nested or not, has text
+func listHasContent(items []listItem) bool {
+	for _, item := range items {
+		if plainText(item.Content) != "" || listHasContent(item.Items) {
+			return true
+		}
+	}
+	return false
+}
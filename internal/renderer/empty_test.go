@@ -0,0 +1,31 @@
+package renderer
+
+import "testing"
+
+func TestIsEmptyContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"blank markdown", "  \n\t", true},
+		{"markdown", "# Title", false},
+		{"no blocks", `{"blocks":[]}`, true},
+		{"only an empty paragraph", `{"blocks":[{"type":"paragraph","data":{"text":""}}]}`, true},
+		{"paragraph of nbsp and br", `{"blocks":[{"type":"paragraph","data":{"text":"&nbsp; <br>"}}]}`, true},
+		{"empty header and delimiter", `{"blocks":[{"type":"header","data":{"text":"","level":2}},{"type":"delimiter","data":{}}]}`, true},
+		{"list of empty items", `{"blocks":[{"type":"list","data":{"style":"unordered","items":["",{"content":"<br>","items":[]}]}}]}`, true},
+		{"blank code", `{"blocks":[{"type":"code","data":{"code":"\n  "}}]}`, true},
+		{"paragraph with text", `{"blocks":[{"type":"paragraph","data":{"text":""}},{"type":"paragraph","data":{"text":"<b>Hi</b>"}}]}`, false},
+		{"nested list item", `{"blocks":[{"type":"list","data":{"items":[{"content":"","items":[{"content":"deep","items":[]}]}]}}]}`, false},
+		{"image", `{"blocks":[{"type":"image","data":{"file":{"url":"/uploads/a.png"}}}]}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsEmptyContent(tt.content); got != tt.want {
+				t.Errorf("IsEmptyContent(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
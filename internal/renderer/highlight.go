@@ -0,0 +1,65 @@
+package renderer
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxHighlights caps how many matches HighlightMatches marks in one field
+const MaxHighlights = 10
+
+// HighlightMatches HTML-escapes text and wraps each whole word matching a
+// term of query in <mark>, ignoring case. Terms are the words of the query,
+// so punctuation in it is ignored. Matching runs on the plain text before
+// escaping, so a term can't match inside an entity such as &amp;. At most
+// MaxHighlights words are marked.
+func HighlightMatches(text, query string) string {
+	terms := strings.FieldsFunc(query, isNotWordRune)
+	if len(terms) == 0 {
+		return html.EscapeString(text)
+	}
+
+	var b strings.Builder
+	marked := 0
+	last := 0 // end of the text already written
+	for i := 0; i < len(text) && marked < MaxHighlights; {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if isNotWordRune(r) {
+			i += size
+			continue
+		}
+
+		end := len(text)
+		if n := strings.IndexFunc(text[i:], isNotWordRune); n >= 0 {
+			end = i + n
+		}
+		if matchesTerm(text[i:end], terms) {
+			b.WriteString(html.EscapeString(text[last:i]))
+			b.WriteString("<mark>")
+			b.WriteString(html.EscapeString(text[i:end]))
+			b.WriteString("</mark>")
+			last = end
+			marked++
+		}
+		i = end
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+// isNotWordRune reports whether r separates words
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// matchesTerm reports whether word equals one of terms, ignoring case
+func matchesTerm(word string, terms []string) bool {
+	for _, t := range terms {
+		if strings.EqualFold(word, t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHighlightMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+	}{
+		{
+			name:  "multiple terms",
+			text:  "Go generics in Go 1.18",
+			query: "go GENERICS",
+			want:  "<mark>Go</mark> <mark>generics</mark> in <mark>Go</mark> 1.18",
+		},
+		{
+			name:  "whole words only",
+			text:  "Going to the gopher go-kart",
+			query: "go",
+			want:  "Going to the gopher <mark>go</mark>-kart",
+		},
+		{
+			name:  "term inside an entity",
+			text:  "Fish & Chips <lt>",
+			query: "amp lt",
+			want:  "Fish &amp; Chips &lt;<mark>lt</mark>&gt;",
+		},
+		{
+			name:  "text is escaped",
+			text:  `<script>alert("go")</script>`,
+			query: "go",
+			want:  "&lt;script&gt;alert(&#34;<mark>go</mark>&#34;)&lt;/script&gt;",
+		},
+		{
+			name:  "punctuation in query",
+			text:  "C++ and Rust",
+			query: "rust!",
+			want:  "C++ and <mark>Rust</mark>",
+		},
+		{
+			name:  "non-ASCII",
+			text:  "Über Café",
+			query: "café",
+			want:  "Über <mark>Café</mark>",
+		},
+		{
+			name:  "empty query",
+			text:  "a & b",
+			query: "  ",
+			want:  "a &amp; b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HighlightMatches(tt.text, tt.query); got != tt.want {
+				t.Errorf("HighlightMatches(%q, %q) = %q, want %q", tt.text, tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightMatches_Cap(t *testing.T) {
+	text := strings.Repeat("go ", MaxHighlights+5)
+	got := HighlightMatches(text, "go")
+	if n := strings.Count(got, "<mark>"); n != MaxHighlights {
+		t.Errorf("marked %d matches, want %d", n, MaxHighlights)
+	}
+	if !strings.HasSuffix(got, "go go go go go ") {
+		t.Errorf("text after the cap was dropped: %q", got)
+	}
+}
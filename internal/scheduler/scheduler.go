@@ -0,0 +1,169 @@
+// Package scheduler runs background maintenance jobs on cron-like schedules.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// JobFunc is the work a job performs. The context is cancelled on shutdown.
+type JobFunc func(ctx context.Context) error
+
+// JobStatus is a snapshot of a registered job for display and debugging
+type JobStatus struct {
+	Name      string
+	Schedule  string
+	LastRun   time.Time
+	LastError string
+	NextRun   time.Time
+	Running   bool
+}
+
+// job is a registered job and its run history
+type job struct {
+	name     string
+	expr     string
+	schedule Schedule
+	fn       JobFunc
+
+	lastRun   time.Time
+	lastError string
+	nextRun   time.Time
+	running   bool
+}
+
+// Scheduler runs registered jobs until its context is cancelled
+type Scheduler struct {
+	mu   sync.Mutex
+	jobs map[string]*job
+	wg   sync.WaitGroup
+}
+
+// New creates an empty scheduler
+func New() *Scheduler {
+	return &Scheduler{
+		jobs: make(map[string]*job),
+	}
+}
+
+// Add registers a job under a unique name. An empty expression disables the
+// job, which lets config turn individual jobs off.
+func (s *Scheduler) Add(name, expr string, fn JobFunc) error {
+	if expr == "" {
+		return nil
+	}
+	schedule, err := ParseSchedule(expr)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %s already registered", name)
+	}
+	s.jobs[name] = &job{name: name, expr: expr, schedule: schedule, fn: fn}
+	return nil
+}
+
+// Start launches every registered job in its own goroutine. Jobs stop when
+// ctx is cancelled; use Wait to block until running jobs have returned.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+}
+
+// Wait blocks until all job loops have exited after shutdown
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// Jobs returns the status of every registered job, sorted by name
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		statuses = append(statuses, JobStatus{
+			Name:      j.name,
+			Schedule:  j.expr,
+			LastRun:   j.lastRun,
+			LastError: j.lastError,
+			NextRun:   j.nextRun,
+			Running:   j.running,
+		})
+	}
+	sort.Slice(statuses, func(i, k int) bool { return statuses[i].Name < statuses[k].Name })
+	return statuses
+}
+
+// loop waits for each scheduled time and runs the job until ctx is done
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	defer s.wg.Done()
+
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			slog.Warn("job has no future run time", "job", j.name, "schedule", j.expr)
+			return
+		}
+		s.mu.Lock()
+		j.nextRun = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.run(ctx, j)
+	}
+}
+
+// run executes a job once, recording the outcome. A panicking job is
+// recovered and reported as an error so it can't take the scheduler down.
+func (s *Scheduler) run(ctx context.Context, j *job) {
+	s.mu.Lock()
+	j.running = true
+	s.mu.Unlock()
+
+	start := time.Now()
+	err := safeRun(ctx, j.fn)
+
+	s.mu.Lock()
+	j.running = false
+	j.lastRun = start
+	j.lastError = ""
+	if err != nil {
+		j.lastError = err.Error()
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		slog.Warn("scheduled job failed", "job", j.name, "error", err)
+	} else {
+		slog.Debug("scheduled job finished", "job", j.name, "duration_ms", time.Since(start).Milliseconds())
+	}
+}
+
+// safeRun calls fn, converting a panic into an error
+func safeRun(ctx context.Context, fn JobFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	base := time.Date(2026, 3, 4, 10, 17, 30, 0, time.UTC) // Wednesday
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{"every minute", "* * * * *", time.Date(2026, 3, 4, 10, 18, 0, 0, time.UTC), false},
+		{"hourly shorthand", "@hourly", time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC), false},
+		{"daily shorthand", "@daily", time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), false},
+		{"step minutes", "*/15 * * * *", time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC), false},
+		{"list and range", "0 9-17 * * 1,5", time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC), false},
+		{"sunday as 7", "30 2 * * 7", time.Date(2026, 3, 8, 2, 30, 0, 0, time.UTC), false},
+		{"day of month", "0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), false},
+		{"interval", "@every 90m", base.Add(90 * time.Minute), false},
+
+		{"too few fields", "* * *", time.Time{}, true},
+		{"out of range", "60 * * * *", time.Time{}, true},
+		{"bad range", "0 5-2 * * *", time.Time{}, true},
+		{"bad step", "*/0 * * * *", time.Time{}, true},
+		{"bad interval", "@every soon", time.Time{}, true},
+		{"negative interval", "@every -1m", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseSchedule(%q) error = nil, want error", tt.expr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) error = %v", tt.expr, err)
+			}
+			if got := schedule.Next(base); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchedule_ImpossibleDate(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseSchedule error = %v", err)
+	}
+	if got := schedule.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next() for Feb 30 = %v, want zero time", got)
+	}
+}
+
+func TestScheduler_RecoversPanickingJob(t *testing.T) {
+	s := New()
+
+	var panics, runs atomic.Int32
+	healthy := make(chan struct{}, 10)
+
+	if err := s.Add("panicker", "@every 5ms", func(ctx context.Context) error {
+		panics.Add(1)
+		panic("boom")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add("healthy", "@every 5ms", func(ctx context.Context) error {
+		runs.Add(1)
+		healthy <- struct{}{}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+
+	// The healthy job keeps running after the other job has panicked
+	deadline := time.After(2 * time.Second)
+	for runs.Load() < 3 || panics.Load() < 2 {
+		select {
+		case <-healthy:
+		case <-deadline:
+			t.Fatalf("jobs stalled: %d healthy runs, %d panics", runs.Load(), panics.Load())
+		}
+	}
+
+	cancel()
+	s.Wait()
+
+	var status JobStatus
+	for _, js := range s.Jobs() {
+		if js.Name == "panicker" {
+			status = js
+		}
+	}
+	if !strings.Contains(status.LastError, "panic: boom") {
+		t.Errorf("LastError = %q, want recorded panic", status.LastError)
+	}
+	if status.LastRun.IsZero() {
+		t.Error("LastRun not recorded for panicking job")
+	}
+}
+
+func TestScheduler_Add(t *testing.T) {
+	s := New()
+	noop := func(ctx context.Context) error { return nil }
+
+	if err := s.Add("job", "@hourly", noop); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := s.Add("job", "@daily", noop); err == nil {
+		t.Error("Add() with duplicate name should fail")
+	}
+	if err := s.Add("bad", "not a schedule", noop); err == nil {
+		t.Error("Add() with invalid schedule should fail")
+	}
+	if err := s.Add("disabled", "", noop); err != nil {
+		t.Errorf("Add() with empty schedule error = %v, want nil", err)
+	}
+
+	jobs := s.Jobs()
+	if len(jobs) != 1 || jobs[0].Name != "job" {
+		t.Errorf("Jobs() = %+v, want only the valid job", jobs)
+	}
+}
@@ -17,14 +17,19 @@ const (
 	ActionPostUpdated       = "post.updated"
 	ActionPostDeleted       = "post.deleted"
 	ActionPostPublished     = "post.published"
+	ActionPostRestored      = "post.restored"
 	ActionCollectionCreated = "collection.created"
 	ActionCollectionUpdated = "collection.updated"
 	ActionCollectionDeleted = "collection.deleted"
 	ActionTagCreated        = "tag.created"
 	ActionTagDeleted        = "tag.deleted"
+	ActionTagMerged         = "tag.merged"
 	ActionImportStarted     = "import.started"
 	ActionImportCompleted   = "import.completed"
 	ActionMetadataFetched   = "metadata.fetched"
+	ActionUserLocked        = "user.locked"
+	ActionPasswordChanged   = "user.password_changed"
+	ActionLogout            = "user.logout"
 )
 
 // Entity types
@@ -32,6 +37,9 @@ const (
 	EntityBookmark   = "bookmark"
 	EntityPost       = "post"
 	EntityCollection = "collection"
+	EntityTag        = "tag"
+	EntityUser       = "user"
+	EntitySite       = "site"
 )
 
 // Activity represents an activity log entry
@@ -45,8 +53,13 @@ type Activity struct {
 	CreatedAt  time.Time              `json:"created_at"`
 }
 
-// LogActivity logs a new activity
+// LogActivity logs a new activity. Activities that change content counts
+// also invalidate the cached dashboard stats.
 func (s *Service) LogActivity(ctx context.Context, action, entityType string, entityID int64, title string, metadata map[string]interface{}) (*Activity, error) {
+	if statsActions[action] {
+		s.invalidateDashboardStats()
+	}
+
 	var metadataJSON *string
 	if metadata != nil {
 		bytes, err := json.Marshal(metadata)
@@ -80,11 +93,25 @@ func (s *Service) LogActivity(ctx context.Context, action, entityType string, en
 	return dbActivityToModel(activity), nil
 }
 
-// ListRecentActivities returns recent activity logs
-func (s *Service) ListRecentActivities(ctx context.Context, limit, offset int) ([]Activity, error) {
-	activities, err := s.queries.ListRecentActivities(ctx, db.ListRecentActivitiesParams{
-		Limit:  int64(limit),
-		Offset: int64(offset),
+// ActivityFilter narrows an activity listing. Empty fields match everything.
+type ActivityFilter struct {
+	EntityType string // e.g. EntityPost
+	Action     string // full action, e.g. ActionPostPublished
+}
+
+// ActivityTypeCount is the number of logged activities for one entity type
+type ActivityTypeCount struct {
+	EntityType string `json:"entity_type"`
+	Count      int    `json:"count"`
+}
+
+// ListActivities returns activity logs matching filter, newest first
+func (s *Service) ListActivities(ctx context.Context, filter ActivityFilter, limit, offset int) ([]Activity, error) {
+	activities, err := s.queries.ListActivities(ctx, db.ListActivitiesParams{
+		EntityType: filter.EntityType,
+		Action:     filter.Action,
+		Limit:      int64(limit),
+		Offset:     int64(offset),
 	})
 	if err != nil {
 		return nil, err
@@ -98,6 +125,38 @@ func (s *Service) ListRecentActivities(ctx context.Context, limit, offset int) (
 	return result, nil
 }
 
+// CountActivities returns how many activity logs match filter
+func (s *Service) CountActivities(ctx context.Context, filter ActivityFilter) (int, error) {
+	count, err := s.queries.CountActivities(ctx, db.CountActivitiesParams{
+		EntityType: filter.EntityType,
+		Action:     filter.Action,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// CountActivitiesByType returns the number of activity logs per entity
+// type, most frequent first
+func (s *Service) CountActivitiesByType(ctx context.Context) ([]ActivityTypeCount, error) {
+	rows, err := s.queries.CountActivitiesByEntityType(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]ActivityTypeCount, 0, len(rows))
+	for _, row := range rows {
+		counts = append(counts, ActivityTypeCount{EntityType: row.EntityType, Count: int(row.Count)})
+	}
+	return counts, nil
+}
+
+// ListRecentActivities returns recent activity logs of every type
+func (s *Service) ListRecentActivities(ctx context.Context, limit, offset int) ([]Activity, error) {
+	return s.ListActivities(ctx, ActivityFilter{}, limit, offset)
+}
+
 // Helper to convert db.Activity to Activity model
 func dbActivityToModel(a db.Activity) *Activity {
 	activity := &Activity{
@@ -140,6 +199,8 @@ func GetActivityVerb(action string) string {
 		return "Deleted post"
 	case ActionPostPublished:
 		return "Published post"
+	case ActionPostRestored:
+		return "Restored post"
 	case ActionCollectionCreated:
 		return "Created collection"
 	case ActionCollectionUpdated:
@@ -150,12 +211,16 @@ func GetActivityVerb(action string) string {
 		return "Created tag"
 	case ActionTagDeleted:
 		return "Deleted tag"
+	case ActionTagMerged:
+		return "Merged tag"
 	case ActionImportStarted:
 		return "Started import"
 	case ActionImportCompleted:
 		return "Completed import"
 	case ActionMetadataFetched:
 		return "Fetched metadata"
+	case ActionPasswordChanged:
+		return "Changed password"
 	default:
 		return action
 	}
@@ -1,6 +1,9 @@
 package service
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 func TestGetActivityVerb(t *testing.T) {
 	tests := []struct {
@@ -26,6 +29,7 @@ func TestGetActivityVerb(t *testing.T) {
 		// Tag actions
 		{ActionTagCreated, "Created tag"},
 		{ActionTagDeleted, "Deleted tag"},
+		{ActionTagMerged, "Merged tag"},
 
 		// Import actions
 		{ActionImportStarted, "Started import"},
@@ -68,6 +72,7 @@ func TestActionConstants(t *testing.T) {
 		{"CollectionDeleted", ActionCollectionDeleted, "collection."},
 		{"TagCreated", ActionTagCreated, "tag."},
 		{"TagDeleted", ActionTagDeleted, "tag."},
+		{"TagMerged", ActionTagMerged, "tag."},
 		{"ImportStarted", ActionImportStarted, "import."},
 		{"ImportCompleted", ActionImportCompleted, "import."},
 		{"MetadataFetched", ActionMetadataFetched, "metadata."},
@@ -105,3 +110,106 @@ func TestEntityConstants(t *testing.T) {
 		})
 	}
 }
+
+// seedActivities logs a mix of post and bookmark activity
+func seedActivities(t *testing.T, s *Service) {
+	t.Helper()
+	ctx := context.Background()
+	entries := []struct{ action, entityType string }{
+		{ActionPostCreated, EntityPost},
+		{ActionPostPublished, EntityPost},
+		{ActionBookmarkCreated, EntityBookmark},
+		{ActionPostPublished, EntityPost},
+		{ActionBookmarkDeleted, EntityBookmark},
+	}
+	for i, e := range entries {
+		if _, err := s.LogActivity(ctx, e.action, e.entityType, int64(i+1), "", nil); err != nil {
+			t.Fatalf("LogActivity: %v", err)
+		}
+	}
+}
+
+func TestListActivities_Filter(t *testing.T) {
+	s := newTestService(t)
+	seedActivities(t, s)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		filter ActivityFilter
+		want   int
+	}{
+		{name: "unfiltered", filter: ActivityFilter{}, want: 5},
+		{name: "posts", filter: ActivityFilter{EntityType: EntityPost}, want: 3},
+		{name: "post publishes", filter: ActivityFilter{EntityType: EntityPost, Action: ActionPostPublished}, want: 2},
+		{name: "action only", filter: ActivityFilter{Action: ActionBookmarkDeleted}, want: 1},
+		{name: "no match", filter: ActivityFilter{EntityType: EntityTag}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ListActivities(ctx, tt.filter, 50, 0)
+			if err != nil {
+				t.Fatalf("ListActivities: %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("got %d activities, want %d", len(got), tt.want)
+			}
+			for _, a := range got {
+				if tt.filter.EntityType != "" && a.EntityType != tt.filter.EntityType {
+					t.Errorf("entity type = %q, want %q", a.EntityType, tt.filter.EntityType)
+				}
+				if tt.filter.Action != "" && a.Action != tt.filter.Action {
+					t.Errorf("action = %q, want %q", a.Action, tt.filter.Action)
+				}
+			}
+
+			count, err := s.CountActivities(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("CountActivities: %v", err)
+			}
+			if count != tt.want {
+				t.Errorf("CountActivities = %d, want %d", count, tt.want)
+			}
+		})
+	}
+}
+
+func TestListRecentActivities_Unfiltered(t *testing.T) {
+	s := newTestService(t)
+	seedActivities(t, s)
+
+	got, err := s.ListRecentActivities(context.Background(), 2, 0)
+	if err != nil {
+		t.Fatalf("ListRecentActivities: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d activities, want 2", len(got))
+	}
+	// Newest first; entries logged in the same second fall back to ID order
+	if got[0].Action != ActionBookmarkDeleted {
+		t.Errorf("first activity = %q, want %q", got[0].Action, ActionBookmarkDeleted)
+	}
+}
+
+func TestCountActivitiesByType(t *testing.T) {
+	s := newTestService(t)
+	seedActivities(t, s)
+
+	got, err := s.CountActivitiesByType(context.Background())
+	if err != nil {
+		t.Fatalf("CountActivitiesByType: %v", err)
+	}
+	want := []ActivityTypeCount{
+		{EntityType: EntityPost, Count: 3},
+		{EntityType: EntityBookmark, Count: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("counts[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
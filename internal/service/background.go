@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+// backgroundTasks tracks goroutines the service starts on its own so they
+// can be cancelled and waited on at shutdown
+type backgroundTasks struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newBackgroundTasks() *backgroundTasks {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &backgroundTasks{ctx: ctx, cancel: cancel}
+}
+
+// goBackground runs fn in a goroutine registered with the service. fn's
+// context is cancelled when Shutdown is called.
+func (s *Service) goBackground(fn func(ctx context.Context)) {
+	s.background.wg.Add(1)
+	go func() {
+		defer s.background.wg.Done()
+		fn(s.background.ctx)
+	}()
+}
+
+// Shutdown cancels background work and waits for it to return, or until
+// ctx is done, in which case ctx's error is returned
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.background.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.background.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+// ErrInvalidCursor is returned for a pagination cursor that can't be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorTimeFormat matches how SQLite's datetime() renders a timestamp, so
+// cursor times compare equal to the stored values
+const cursorTimeFormat = "2006-01-02 15:04:05"
+
+// unsortedSortOrder stands in for a NULL sort_order in cursors. NULLs sort
+// first and real sort orders are positive.
+const unsortedSortOrder = -1
+
+// BookmarkPage is one page of a cursor-paginated bookmark listing
+type BookmarkPage struct {
+	Bookmarks  []models.Bookmark
+	NextCursor string // opaque; empty on the last page
+}
+
+// bookmarkCursor is the sort position of the last bookmark on a page. The
+// recent sort pages by SortOrder and CreatedAt; other sorts record their
+// name in Sort and the last bookmark's sort key in Key.
+type bookmarkCursor struct {
+	SortOrder int64  `json:"s"`
+	CreatedAt string `json:"c"`
+	ID        int64  `json:"i"`
+	Sort      string `json:"o,omitempty"`
+	Key       string `json:"k,omitempty"`
+}
+
+// ListBookmarksCursor lists public bookmarks, optionally limited to a
+// collection or to favorites, one page of opts.Limit at a time in
+// opts.SortBy order. Pass the previous page's NextCursor to continue, or ""
+// for the first page. Unlike offsets, cursors don't skip or repeat bookmarks
+// when new ones are added while paging. A cursor from a different sort is
+// ErrInvalidCursor. Favorites always use the default order.
+func (s *Service) ListBookmarksCursor(ctx context.Context, opts BookmarkListOptions, cursor string) (*BookmarkPage, error) {
+	if !opts.PublicOnly {
+		return nil, errors.New("cursor pagination is only supported for public bookmarks")
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	limit := int64(opts.Limit) + 1
+
+	if opts.FavoritesOnly {
+		return s.listFavoriteBookmarksCursor(ctx, opts, cursor, limit)
+	}
+	if !opts.SortBy.isDefault() {
+		return s.listBookmarksCursorSorted(ctx, opts, cursor, limit)
+	}
+
+	var descendantIDs string
+	if opts.CollectionID != nil && opts.IncludeDescendants {
+		ids, err := s.descendantCollectionIDs(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		descendantIDs = ids
+	}
+
+	var rows []db.Bookmark
+	var err error
+	if cursor == "" {
+		if descendantIDs != "" {
+			rows, err = s.queries.ListPublicBookmarksInCollections(ctx, db.ListPublicBookmarksInCollectionsParams{
+				CollectionIds: descendantIDs,
+				Limit:         limit,
+			})
+		} else if opts.CollectionID != nil {
+			rows, err = s.queries.ListPublicBookmarksByCollection(ctx, db.ListPublicBookmarksByCollectionParams{
+				CollectionID: opts.CollectionID,
+				Limit:        limit,
+			})
+		} else {
+			rows, err = s.queries.ListPublicBookmarks(ctx, db.ListPublicBookmarksParams{
+				Limit: limit,
+			})
+		}
+	} else {
+		after, decodeErr := decodeBookmarkCursor(cursor)
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		if after.Sort != "" {
+			return nil, ErrInvalidCursor
+		}
+		if descendantIDs != "" {
+			rows, err = s.queries.ListPublicBookmarksInCollectionsAfter(ctx, db.ListPublicBookmarksInCollectionsAfterParams{
+				CollectionIds:  descendantIDs,
+				AfterSortOrder: after.SortOrder,
+				AfterCreatedAt: after.CreatedAt,
+				AfterID:        after.ID,
+				Limit:          limit,
+			})
+		} else if opts.CollectionID != nil {
+			rows, err = s.queries.ListPublicBookmarksByCollectionAfter(ctx, db.ListPublicBookmarksByCollectionAfterParams{
+				CollectionID:   opts.CollectionID,
+				AfterSortOrder: after.SortOrder,
+				AfterCreatedAt: after.CreatedAt,
+				AfterID:        after.ID,
+				Limit:          limit,
+			})
+		} else {
+			rows, err = s.queries.ListPublicBookmarksAfter(ctx, db.ListPublicBookmarksAfterParams{
+				AfterSortOrder: after.SortOrder,
+				AfterCreatedAt: after.CreatedAt,
+				AfterID:        after.ID,
+				Limit:          limit,
+			})
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newBookmarkPage(rows, opts.Limit, encodeBookmarkCursor), nil
+}
+
+// listFavoriteBookmarksCursor is ListBookmarksCursor for public favorites
+func (s *Service) listFavoriteBookmarksCursor(ctx context.Context, opts BookmarkListOptions, cursor string, limit int64) (*BookmarkPage, error) {
+	var rows []db.Bookmark
+	var err error
+	if cursor == "" {
+		rows, err = s.queries.ListPublicFavoriteBookmarks(ctx, db.ListPublicFavoriteBookmarksParams{
+			Limit: limit,
+		})
+	} else {
+		after, decodeErr := decodeBookmarkCursor(cursor)
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		if after.Sort != "" {
+			return nil, ErrInvalidCursor
+		}
+		rows, err = s.queries.ListPublicFavoriteBookmarksAfter(ctx, db.ListPublicFavoriteBookmarksAfterParams{
+			AfterSortOrder: after.SortOrder,
+			AfterCreatedAt: after.CreatedAt,
+			AfterID:        after.ID,
+			Limit:          limit,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newBookmarkPage(rows, opts.Limit, encodeBookmarkCursor), nil
+}
+
+// listBookmarksCursorSorted is ListBookmarksCursor for the non-default sorts
+func (s *Service) listBookmarksCursorSorted(ctx context.Context, opts BookmarkListOptions, cursor string, limit int64) (*BookmarkPage, error) {
+	scope, err := s.sortedScope(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []db.Bookmark
+	if cursor == "" {
+		rows, err = s.listPublicBookmarksSorted(ctx, opts.SortBy, scope, limit, 0)
+	} else {
+		after, decodeErr := decodeBookmarkCursor(cursor)
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		if after.Sort != string(opts.SortBy) {
+			return nil, ErrInvalidCursor
+		}
+		rows, err = s.listPublicBookmarksSortedAfter(ctx, opts.SortBy, scope, after, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newBookmarkPage(rows, opts.Limit, func(b db.Bookmark) string {
+		return encodeSortedBookmarkCursor(b, opts.SortBy)
+	}), nil
+}
+
+// newBookmarkPage trims rows, fetched with one extra, to a page of limit
+// and sets the cursor for the next page when there is one
+func newBookmarkPage(rows []db.Bookmark, limit int, encode func(db.Bookmark) string) *BookmarkPage {
+	page := &BookmarkPage{}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		page.NextCursor = encode(rows[len(rows)-1])
+	}
+
+	page.Bookmarks = make([]models.Bookmark, 0, len(rows))
+	for _, b := range rows {
+		page.Bookmarks = append(page.Bookmarks, *dbBookmarkToModel(b))
+	}
+	return page
+}
+
+// encodeBookmarkCursor builds the opaque cursor pointing just past b
+func encodeBookmarkCursor(b db.Bookmark) string {
+	c := bookmarkCursor{
+		SortOrder: unsortedSortOrder,
+		CreatedAt: derefTime(b.CreatedAt).UTC().Format(cursorTimeFormat),
+		ID:        b.ID,
+	}
+	if b.SortOrder != nil {
+		c.SortOrder = *b.SortOrder
+	}
+
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// encodeSortedBookmarkCursor builds the cursor pointing just past b in a
+// non-default sort
+func encodeSortedBookmarkCursor(b db.Bookmark, sort BookmarkSort) string {
+	c := bookmarkCursor{
+		SortOrder: unsortedSortOrder,
+		CreatedAt: derefTime(b.CreatedAt).UTC().Format(cursorTimeFormat),
+		ID:        b.ID,
+		Sort:      string(sort),
+		Key:       bookmarkSortKey(b, sort),
+	}
+
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeBookmarkCursor parses a cursor made by encodeBookmarkCursor or
+// encodeSortedBookmarkCursor
+func decodeBookmarkCursor(cursor string) (bookmarkCursor, error) {
+	var c bookmarkCursor
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 {
+		return c, ErrInvalidCursor
+	}
+	if _, err := time.Parse(cursorTimeFormat, c.CreatedAt); err != nil {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestListBookmarksCursor(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	for i := 1; i <= 5; i++ {
+		if _, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+			URL:      fmt.Sprintf("https://example.com/%d", i),
+			Title:    fmt.Sprintf("Bookmark %d", i),
+			IsPublic: true,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+		URL:   "https://example.com/private",
+		Title: "Private",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := BookmarkListOptions{PublicOnly: true, Limit: 2}
+	seen := make(map[int64]bool)
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+		page, err := svc.ListBookmarksCursor(ctx, opts, cursor)
+		if err != nil {
+			t.Fatalf("ListBookmarksCursor() error = %v", err)
+		}
+		for _, b := range page.Bookmarks {
+			if seen[b.ID] {
+				t.Errorf("bookmark %d returned twice", b.ID)
+			}
+			if !b.IsPublic {
+				t.Errorf("private bookmark %d returned", b.ID)
+			}
+			seen[b.ID] = true
+		}
+
+		// A bookmark added mid-scroll lands on the first page and must not
+		// shift the following pages
+		if pages == 0 {
+			if _, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+				URL:      "https://example.com/new",
+				Title:    "Added while scrolling",
+				IsPublic: true,
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if len(seen) != 5 {
+		t.Errorf("saw %d bookmarks, want the 5 public ones present at the start", len(seen))
+	}
+}
+
+func TestListBookmarksCursor_InvalidCursor(t *testing.T) {
+	svc := newTestService(t)
+	opts := BookmarkListOptions{PublicOnly: true, Limit: 10}
+
+	for _, cursor := range []string{"not base64!", "e30", "eyJpIjoxfQ"} {
+		if _, err := svc.ListBookmarksCursor(context.Background(), opts, cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("ListBookmarksCursor(%q) error = %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
+
+func TestListBookmarksCursor_Favorites(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	for i := 1; i <= 5; i++ {
+		if _, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+			URL:        fmt.Sprintf("https://example.com/%d", i),
+			Title:      fmt.Sprintf("Bookmark %d", i),
+			IsPublic:   true,
+			IsFavorite: i%2 == 1,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := BookmarkListOptions{PublicOnly: true, FavoritesOnly: true, Limit: 2}
+	seen := 0
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+		page, err := svc.ListBookmarksCursor(ctx, opts, cursor)
+		if err != nil {
+			t.Fatalf("ListBookmarksCursor() error = %v", err)
+		}
+		for _, b := range page.Bookmarks {
+			if !b.IsFavorite {
+				t.Errorf("non-favorite bookmark %d returned", b.ID)
+			}
+			seen++
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if seen != 3 {
+		t.Errorf("saw %d bookmarks, want the 3 favorites", seen)
+	}
+}
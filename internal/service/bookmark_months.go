@@ -0,0 +1,47 @@
+package service
+
+import "github.com/EC-9624/0xec.dev/internal/models"
+
+// UnknownMonthKey is the BookmarkMonthGroup key for bookmarks without a
+// creation time
+const UnknownMonthKey = "unknown"
+
+// BookmarkMonthGroup is a run of bookmarks added in the same month
+type BookmarkMonthGroup struct {
+	Key       string // "2006-01", or UnknownMonthKey
+	Label     string // "January 2006", or "Unknown"
+	Bookmarks []models.Bookmark
+}
+
+// GroupBookmarksByMonth splits bookmarks, already in reading-list order,
+// into consecutive runs by the month (UTC) they were added. Bookmarks
+// with a zero CreatedAt go under "Unknown".
+func GroupBookmarksByMonth(bookmarks []models.Bookmark) []BookmarkMonthGroup {
+	var groups []BookmarkMonthGroup
+	for _, b := range bookmarks {
+		key := BookmarkMonthKey(b)
+		if n := len(groups); n > 0 && groups[n-1].Key == key {
+			groups[n-1].Bookmarks = append(groups[n-1].Bookmarks, b)
+			continue
+		}
+
+		label := "Unknown"
+		if key != UnknownMonthKey {
+			label = b.CreatedAt.UTC().Format("January 2006")
+		}
+		groups = append(groups, BookmarkMonthGroup{
+			Key:       key,
+			Label:     label,
+			Bookmarks: []models.Bookmark{b},
+		})
+	}
+	return groups
+}
+
+// BookmarkMonthKey is the key of the month group b belongs in
+func BookmarkMonthKey(b models.Bookmark) string {
+	if b.CreatedAt.IsZero() {
+		return UnknownMonthKey
+	}
+	return b.CreatedAt.UTC().Format("2006-01")
+}
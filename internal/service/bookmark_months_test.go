@@ -0,0 +1,61 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestGroupBookmarksByMonth(t *testing.T) {
+	at := func(value string) time.Time {
+		t.Helper()
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	bookmarks := []models.Bookmark{
+		{ID: 1, CreatedAt: at("2025-01-01T00:00:00Z")},
+		{ID: 2, CreatedAt: at("2024-12-31T23:59:59Z")},
+		{ID: 3, CreatedAt: at("2024-12-01T00:00:00Z")},
+		{ID: 4, CreatedAt: at("2024-11-30T23:59:59Z")},
+		// Still November in UTC
+		{ID: 5, CreatedAt: at("2024-12-01T08:30:00+09:00")},
+		{ID: 6},
+		{ID: 7},
+	}
+
+	groups := GroupBookmarksByMonth(bookmarks)
+
+	want := []struct {
+		key, label string
+		ids        []int64
+	}{
+		{"2025-01", "January 2025", []int64{1}},
+		{"2024-12", "December 2024", []int64{2, 3}},
+		{"2024-11", "November 2024", []int64{4, 5}},
+		{UnknownMonthKey, "Unknown", []int64{6, 7}},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(groups), len(want))
+	}
+	for i, w := range want {
+		g := groups[i]
+		var ids []int64
+		for _, b := range g.Bookmarks {
+			ids = append(ids, b.ID)
+		}
+		if g.Key != w.key || g.Label != w.label || !slices.Equal(ids, w.ids) {
+			t.Errorf("group %d = {%q, %q, %v}, want {%q, %q, %v}", i, g.Key, g.Label, ids, w.key, w.label, w.ids)
+		}
+	}
+}
+
+func TestGroupBookmarksByMonth_Empty(t *testing.T) {
+	if groups := GroupBookmarksByMonth(nil); len(groups) != 0 {
+		t.Errorf("got %d groups, want none", len(groups))
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+)
+
+// BookmarkSort orders a public bookmark listing
+type BookmarkSort string
+
+const (
+	// BookmarkSortRecent keeps the manual collection order, newest first
+	// within it. It is the default.
+	BookmarkSortRecent BookmarkSort = "recent"
+	// BookmarkSortAdded is the reading list: newest added first, ignoring
+	// the manual order, and shown grouped by month
+	BookmarkSortAdded  BookmarkSort = "added"
+	BookmarkSortOldest BookmarkSort = "oldest"
+	BookmarkSortTitle  BookmarkSort = "title"
+	BookmarkSortDomain BookmarkSort = "domain"
+)
+
+// BookmarkSorts lists the sort options in the order they are offered
+var BookmarkSorts = []BookmarkSort{BookmarkSortRecent, BookmarkSortAdded, BookmarkSortOldest, BookmarkSortTitle, BookmarkSortDomain}
+
+// ParseBookmarkSort returns the sort named by s, or BookmarkSortRecent for
+// an empty or unknown name
+func ParseBookmarkSort(s string) BookmarkSort {
+	for _, sort := range BookmarkSorts {
+		if string(sort) == s {
+			return sort
+		}
+	}
+	return BookmarkSortRecent
+}
+
+// isDefault reports whether the sort uses the recent (manual order) queries
+func (b BookmarkSort) isDefault() bool {
+	return b == "" || b == BookmarkSortRecent
+}
+
+// sortedScope returns the collection_ids argument of the sorted queries:
+// "" for every collection, otherwise a JSON array of IDs
+func (s *Service) sortedScope(ctx context.Context, opts BookmarkListOptions) (string, error) {
+	if opts.CollectionID == nil {
+		return "", nil
+	}
+	if opts.IncludeDescendants {
+		return s.descendantCollectionIDs(ctx, opts)
+	}
+	return fmt.Sprintf("[%d]", *opts.CollectionID), nil
+}
+
+// listPublicBookmarksSorted runs the first-page query for a non-default sort
+func (s *Service) listPublicBookmarksSorted(ctx context.Context, sort BookmarkSort, scope string, limit, offset int64) ([]db.Bookmark, error) {
+	switch sort {
+	case BookmarkSortAdded:
+		return s.queries.ListPublicBookmarksNewest(ctx, db.ListPublicBookmarksNewestParams{
+			CollectionIds: scope, Limit: limit, Offset: offset,
+		})
+	case BookmarkSortOldest:
+		return s.queries.ListPublicBookmarksOldest(ctx, db.ListPublicBookmarksOldestParams{
+			CollectionIds: scope, Limit: limit, Offset: offset,
+		})
+	case BookmarkSortTitle:
+		return s.queries.ListPublicBookmarksByTitle(ctx, db.ListPublicBookmarksByTitleParams{
+			CollectionIds: scope, Limit: limit, Offset: offset,
+		})
+	case BookmarkSortDomain:
+		return s.queries.ListPublicBookmarksByDomain(ctx, db.ListPublicBookmarksByDomainParams{
+			CollectionIds: scope, Limit: limit, Offset: offset,
+		})
+	}
+	return nil, fmt.Errorf("unknown bookmark sort %q", sort)
+}
+
+// listPublicBookmarksSortedAfter runs the keyset query for a non-default
+// sort, continuing after the cursor's position
+func (s *Service) listPublicBookmarksSortedAfter(ctx context.Context, sort BookmarkSort, scope string, after bookmarkCursor, limit int64) ([]db.Bookmark, error) {
+	switch sort {
+	case BookmarkSortAdded:
+		return s.queries.ListPublicBookmarksNewestAfter(ctx, db.ListPublicBookmarksNewestAfterParams{
+			CollectionIds: scope, AfterKey: after.Key, AfterID: after.ID, Limit: limit,
+		})
+	case BookmarkSortOldest:
+		return s.queries.ListPublicBookmarksOldestAfter(ctx, db.ListPublicBookmarksOldestAfterParams{
+			CollectionIds: scope, AfterKey: after.Key, AfterID: after.ID, Limit: limit,
+		})
+	case BookmarkSortTitle:
+		return s.queries.ListPublicBookmarksByTitleAfter(ctx, db.ListPublicBookmarksByTitleAfterParams{
+			CollectionIds: scope, AfterKey: after.Key, AfterID: after.ID, Limit: limit,
+		})
+	case BookmarkSortDomain:
+		return s.queries.ListPublicBookmarksByDomainAfter(ctx, db.ListPublicBookmarksByDomainAfterParams{
+			CollectionIds: scope, AfterKey: after.Key, AfterID: after.ID, Limit: limit,
+		})
+	}
+	return nil, fmt.Errorf("unknown bookmark sort %q", sort)
+}
+
+// bookmarkSortKey is the value b is ordered by under sort. Title and domain
+// are compared case-insensitively by the queries, so they're kept as is.
+func bookmarkSortKey(b db.Bookmark, sort BookmarkSort) string {
+	switch sort {
+	case BookmarkSortAdded:
+		if b.CreatedAt == nil {
+			return ""
+		}
+		return b.CreatedAt.UTC().Format(cursorTimeFormat)
+	case BookmarkSortOldest:
+		return derefTime(b.CreatedAt).UTC().Format(cursorTimeFormat)
+	case BookmarkSortTitle:
+		return b.Title
+	case BookmarkSortDomain:
+		if b.Domain != nil {
+			return *b.Domain
+		}
+	}
+	return ""
+}
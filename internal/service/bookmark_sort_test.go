@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestParseBookmarkSort(t *testing.T) {
+	tests := map[string]BookmarkSort{
+		"":        BookmarkSortRecent,
+		"recent":  BookmarkSortRecent,
+		"added":   BookmarkSortAdded,
+		"oldest":  BookmarkSortOldest,
+		"title":   BookmarkSortTitle,
+		"domain":  BookmarkSortDomain,
+		"TITLE":   BookmarkSortRecent,
+		"popular": BookmarkSortRecent,
+	}
+	for in, want := range tests {
+		if got := ParseBookmarkSort(in); got != want {
+			t.Errorf("ParseBookmarkSort(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// createSortFixtures adds public bookmarks whose title, domain and creation
+// orders all differ, plus a private one that no listing may include
+func createSortFixtures(t *testing.T, svc *Service) {
+	t.Helper()
+	ctx := context.Background()
+	fixtures := []struct{ url, title string }{
+		{"https://charlie.example/1", "banana"},
+		{"https://alpha.example/1", "Cherry"},
+		{"https://bravo.example/1", "apple"},
+		{"https://alpha.example/2", "date"},
+	}
+	for _, f := range fixtures {
+		if _, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{URL: f.url, Title: f.title, IsPublic: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{URL: "https://aaa.example/", Title: "Aardvark"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func bookmarkTitles(bookmarks []models.Bookmark) []string {
+	titles := make([]string, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		titles = append(titles, b.Title)
+	}
+	return titles
+}
+
+// sortedTitles is the expected order of the fixtures under each sort
+var sortedTitles = map[BookmarkSort][]string{
+	BookmarkSortRecent: {"date", "apple", "Cherry", "banana"},
+	BookmarkSortAdded:  {"date", "apple", "Cherry", "banana"},
+	BookmarkSortOldest: {"banana", "Cherry", "apple", "date"},
+	BookmarkSortTitle:  {"apple", "banana", "Cherry", "date"},
+	BookmarkSortDomain: {"Cherry", "date", "apple", "banana"},
+}
+
+func TestListBookmarks_SortBy(t *testing.T) {
+	svc := newTestService(t)
+	createSortFixtures(t, svc)
+
+	for sort, want := range sortedTitles {
+		t.Run(string(sort), func(t *testing.T) {
+			bookmarks, err := svc.ListBookmarks(context.Background(), BookmarkListOptions{
+				PublicOnly: true,
+				SortBy:     sort,
+				Limit:      10,
+			})
+			if err != nil {
+				t.Fatalf("ListBookmarks() error = %v", err)
+			}
+			if got := bookmarkTitles(bookmarks); !slices.Equal(got, want) {
+				t.Errorf("titles = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestListBookmarksCursor_SortBy(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	createSortFixtures(t, svc)
+
+	for sort, want := range sortedTitles {
+		t.Run(string(sort), func(t *testing.T) {
+			opts := BookmarkListOptions{PublicOnly: true, SortBy: sort, Limit: 3}
+			var got []string
+			cursor := ""
+			for pages := 0; ; pages++ {
+				if pages > 3 {
+					t.Fatal("pagination did not terminate")
+				}
+				page, err := svc.ListBookmarksCursor(ctx, opts, cursor)
+				if err != nil {
+					t.Fatalf("ListBookmarksCursor() error = %v", err)
+				}
+				got = append(got, bookmarkTitles(page.Bookmarks)...)
+				if page.NextCursor == "" {
+					break
+				}
+				cursor = page.NextCursor
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("paged titles = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestListBookmarksCursor_SortMismatch(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	createSortFixtures(t, svc)
+
+	page, err := svc.ListBookmarksCursor(ctx, BookmarkListOptions{PublicOnly: true, SortBy: BookmarkSortTitle, Limit: 1}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sort := range []BookmarkSort{BookmarkSortRecent, BookmarkSortDomain} {
+		opts := BookmarkListOptions{PublicOnly: true, SortBy: sort, Limit: 1}
+		if _, err := svc.ListBookmarksCursor(ctx, opts, page.NextCursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("title cursor with %q sort: error = %v, want ErrInvalidCursor", sort, err)
+		}
+	}
+}
+
+func TestListBookmarks_SortByInCollection(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	createSortFixtures(t, svc)
+
+	collection, err := svc.CreateCollection(ctx, models.CreateCollectionInput{Name: "Reading", Slug: "reading", IsPublic: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"zebra", "Mango"} {
+		if _, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+			URL:          "https://example.com/" + title,
+			Title:        title,
+			IsPublic:     true,
+			CollectionID: &collection.ID,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, descendants := range []bool{false, true} {
+		bookmarks, err := svc.ListBookmarks(ctx, BookmarkListOptions{
+			PublicOnly:         true,
+			CollectionID:       &collection.ID,
+			IncludeDescendants: descendants,
+			SortBy:             BookmarkSortTitle,
+			Limit:              10,
+		})
+		if err != nil {
+			t.Fatalf("ListBookmarks() error = %v", err)
+		}
+		if got, want := bookmarkTitles(bookmarks), []string{"Mango", "zebra"}; !slices.Equal(got, want) {
+			t.Errorf("IncludeDescendants=%v: titles = %v, want %v", descendants, got, want)
+		}
+	}
+}
+
+func TestListBookmarks_FavoritesNewest(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	var ids []int64
+	for _, title := range []string{"first", "second", "third"} {
+		b, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+			URL:        "https://example.com/" + title,
+			Title:      title,
+			IsPublic:   true,
+			IsFavorite: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, b.ID)
+	}
+	// Position every bookmark, then move the oldest back to the top, so
+	// the manual order differs from the creation order
+	for _, id := range append(ids, ids[0]) {
+		if err := svc.MoveBookmark(ctx, id, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[BookmarkSort][]string{
+		BookmarkSortRecent: {"first", "third", "second"},
+		BookmarkSortAdded:  {"third", "second", "first"},
+	}
+	for sort, want := range tests {
+		bookmarks, err := svc.ListBookmarks(ctx, BookmarkListOptions{
+			PublicOnly:    true,
+			FavoritesOnly: true,
+			SortBy:        sort,
+			Limit:         2,
+		})
+		if err != nil {
+			t.Fatalf("ListBookmarks() error = %v", err)
+		}
+		if got := bookmarkTitles(bookmarks); !slices.Equal(got, want[:2]) {
+			t.Errorf("%s: titles = %v, want %v", sort, got, want[:2])
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+// BulkAddTagToBookmarks tags every bookmark in bookmarkIDs with tagID.
+// Bookmarks that already have the tag are left as they are.
+func (s *Service) BulkAddTagToBookmarks(ctx context.Context, bookmarkIDs []int64, tagID int64) error {
+	tag, err := s.queries.GetTagByID(ctx, tagID)
+	if err != nil {
+		return err
+	}
+
+	ids, err := json.Marshal(bookmarkIDs)
+	if err != nil {
+		return err
+	}
+	added, err := s.queries.AddTagToBookmarks(ctx, db.AddTagToBookmarksParams{
+		TagID:       tagID,
+		BookmarkIds: string(ids),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.LogActivity(ctx, ActionBookmarkUpdated, EntityBookmark, 0, tag.Name, map[string]interface{}{
+		"action":       "bulk_tag",
+		"tag_id":       tagID,
+		"count":        added,
+		"bookmark_ids": bookmarkIDs,
+	})
+
+	return nil
+}
+
+// BulkRemoveTag removes tagID from every bookmark in bookmarkIDs
+func (s *Service) BulkRemoveTag(ctx context.Context, bookmarkIDs []int64, tagID int64) error {
+	tag, err := s.queries.GetTagByID(ctx, tagID)
+	if err != nil {
+		return err
+	}
+
+	ids, err := json.Marshal(bookmarkIDs)
+	if err != nil {
+		return err
+	}
+	removed, err := s.queries.RemoveTagFromBookmarks(ctx, db.RemoveTagFromBookmarksParams{
+		TagID:       tagID,
+		BookmarkIds: string(ids),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.LogActivity(ctx, ActionBookmarkUpdated, EntityBookmark, 0, tag.Name, map[string]interface{}{
+		"action":       "bulk_untag",
+		"tag_id":       tagID,
+		"count":        removed,
+		"bookmark_ids": bookmarkIDs,
+	})
+
+	return nil
+}
+
+// loadBookmarkTags fills in the Tags of each bookmark with a single query
+func (s *Service) loadBookmarkTags(ctx context.Context, bookmarks []models.Bookmark) error {
+	if len(bookmarks) == 0 {
+		return nil
+	}
+
+	index := make(map[int64]int, len(bookmarks))
+	bookmarkIDs := make([]int64, len(bookmarks))
+	for i, b := range bookmarks {
+		index[b.ID] = i
+		bookmarkIDs[i] = b.ID
+	}
+	ids, err := json.Marshal(bookmarkIDs)
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.queries.ListTagsForBookmarks(ctx, string(ids))
+	if err != nil {
+		return err
+	}
+	for _, r := range rows {
+		i := index[r.BookmarkID]
+		bookmarks[i].Tags = append(bookmarks[i].Tags, *dbTagToModel(db.Tag{
+			ID:        r.ID,
+			Name:      r.Name,
+			Slug:      r.Slug,
+			Color:     r.Color,
+			CreatedAt: r.CreatedAt,
+		}))
+	}
+	return nil
+}
@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"net/url"
+	"time"
 
 	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+	"github.com/EC-9624/0xec.dev/internal/logger"
 	"github.com/EC-9624/0xec.dev/internal/models"
 )
 
@@ -15,23 +17,43 @@ type BookmarkListOptions struct {
 	FavoritesOnly bool
 	Limit         int
 	Offset        int
+
+	// IncludeDescendants extends a CollectionID filter to the bookmarks of
+	// all its sub-collections
+	IncludeDescendants bool
+
+	// Archived bookmarks are left out unless IncludeArchived is set, and
+	// ArchivedOnly lists nothing else. PublicOnly listings never include
+	// archived bookmarks.
+	IncludeArchived bool
+	ArchivedOnly    bool
+
+	// SortBy orders public, non-favorites listings. Empty means
+	// BookmarkSortRecent. Public favorites listings honour only
+	// BookmarkSortAdded.
+	SortBy BookmarkSort
 }
 
 // CreateBookmark creates a new bookmark
 func (s *Service) CreateBookmark(ctx context.Context, input models.CreateBookmarkInput) (*models.Bookmark, error) {
+	if !input.KeepOriginalURL {
+		input.URL = s.cleanBookmarkURL(input.URL)
+	}
 	domain := extractDomain(input.URL)
 
 	bookmark, err := s.queries.CreateBookmark(ctx, db.CreateBookmarkParams{
-		Url:          input.URL,
-		Title:        input.Title,
-		Description:  strPtr(input.Description),
-		CoverImage:   strPtr(input.CoverImage),
-		Favicon:      strPtr(input.Favicon),
-		Domain:       strPtr(domain),
-		CollectionID: input.CollectionID,
-		IsPublic:     boolToInt64Ptr(input.IsPublic),
-		IsFavorite:   boolToInt64Ptr(input.IsFavorite),
-		SortOrder:    nil,
+		Url:           input.URL,
+		NormalizedUrl: strPtr(s.normalizeBookmarkURL(input.URL)),
+		Title:         input.Title,
+		Description:   strPtr(input.Description),
+		Note:          strPtr(input.Note),
+		CoverImage:    strPtr(input.CoverImage),
+		Favicon:       strPtr(input.Favicon),
+		Domain:        strPtr(domain),
+		CollectionID:  input.CollectionID,
+		IsPublic:      boolToInt64Ptr(input.IsPublic),
+		IsFavorite:    boolToInt64Ptr(input.IsFavorite),
+		SortOrder:     nil,
 	})
 	if err != nil {
 		return nil, err
@@ -43,26 +65,47 @@ func (s *Service) CreateBookmark(ctx context.Context, input models.CreateBookmar
 	return s.GetBookmarkByID(ctx, bookmark.ID)
 }
 
-// UpdateBookmark updates an existing bookmark
+// UpdateBookmark updates an existing bookmark. Changing the cover image URL
+// drops any image stored for the old cover, which would otherwise keep
+// being shown in its place.
 func (s *Service) UpdateBookmark(ctx context.Context, id int64, input models.UpdateBookmarkInput) (*models.Bookmark, error) {
+	current, err := s.queries.GetBookmarkByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	domain := extractDomain(input.URL)
 
-	err := s.queries.UpdateBookmark(ctx, db.UpdateBookmarkParams{
-		Url:          input.URL,
-		Title:        input.Title,
-		Description:  strPtr(input.Description),
-		CoverImage:   strPtr(input.CoverImage),
-		Favicon:      strPtr(input.Favicon),
-		Domain:       strPtr(domain),
-		CollectionID: input.CollectionID,
-		IsPublic:     boolToInt64Ptr(input.IsPublic),
-		IsFavorite:   boolToInt64Ptr(input.IsFavorite),
-		ID:           id,
+	err = s.queries.UpdateBookmark(ctx, db.UpdateBookmarkParams{
+		Url:           input.URL,
+		NormalizedUrl: strPtr(s.normalizeBookmarkURL(input.URL)),
+		Title:         input.Title,
+		Description:   strPtr(input.Description),
+		Note:          strPtr(input.Note),
+		CoverImage:    strPtr(input.CoverImage),
+		Favicon:       strPtr(input.Favicon),
+		Domain:        strPtr(domain),
+		CollectionID:  input.CollectionID,
+		IsPublic:      boolToInt64Ptr(input.IsPublic),
+		IsFavorite:    boolToInt64Ptr(input.IsFavorite),
+		ID:            id,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	oldCover := ""
+	if current.CoverImage != nil {
+		oldCover = *current.CoverImage
+	}
+	if input.CoverImage != oldCover && (current.CoverImageID != nil || current.ThumbnailID != nil) {
+		err = s.queries.UpdateBookmarkImages(ctx, db.UpdateBookmarkImagesParams{ID: id})
+		if err != nil {
+			return nil, err
+		}
+		s.deleteImages(ctx, current.CoverImageID, current.ThumbnailID)
+	}
+
 	// Log activity
 	s.LogActivity(ctx, ActionBookmarkUpdated, EntityBookmark, id, input.Title, nil)
 
@@ -72,16 +115,15 @@ func (s *Service) UpdateBookmark(ctx context.Context, id int64, input models.Upd
 // DeleteBookmark deletes a bookmark
 func (s *Service) DeleteBookmark(ctx context.Context, id int64) error {
 	// Get bookmark title for activity log before deleting
-	bookmark, _ := s.GetBookmarkByID(ctx, id)
-	title := ""
-	if bookmark != nil {
-		title = bookmark.Title
-	}
+	current, _ := s.queries.GetBookmarkByID(ctx, id)
+	title := current.Title
 
 	err := s.queries.DeleteBookmark(ctx, id)
 	if err != nil {
 		return err
 	}
+	// Stored cover images belong to the bookmark alone
+	s.deleteImages(ctx, current.CoverImageID, current.ThumbnailID)
 
 	// Log activity
 	s.LogActivity(ctx, ActionBookmarkDeleted, EntityBookmark, id, title, nil)
@@ -96,7 +138,11 @@ func (s *Service) GetBookmarkByID(ctx context.Context, id int64) (*models.Bookma
 		return nil, err
 	}
 
-	return dbBookmarkToModel(bookmark), nil
+	result := []models.Bookmark{*dbBookmarkToModel(bookmark)}
+	if err := s.loadBookmarkTags(ctx, result); err != nil {
+		return nil, err
+	}
+	return &result[0], nil
 }
 
 // ListBookmarks retrieves bookmarks with optional filtering
@@ -104,20 +150,59 @@ func (s *Service) ListBookmarks(ctx context.Context, opts BookmarkListOptions) (
 	var bookmarks []db.Bookmark
 	var err error
 
+	// Archived bookmarks are never public
+	if opts.PublicOnly && opts.ArchivedOnly {
+		return []models.Bookmark{}, nil
+	}
+
 	limit := int64(opts.Limit)
 	offset := int64(opts.Offset)
+	archived, includeArchived := archiveFilter(opts)
 
 	// Handle different filter combinations
-	if opts.FavoritesOnly {
-		if opts.PublicOnly {
+	if opts.PublicOnly && !opts.FavoritesOnly && !opts.SortBy.isDefault() {
+		scope, scopeErr := s.sortedScope(ctx, opts)
+		if scopeErr != nil {
+			return nil, scopeErr
+		}
+		bookmarks, err = s.listPublicBookmarksSorted(ctx, opts.SortBy, scope, limit, offset)
+	} else if opts.FavoritesOnly {
+		if opts.PublicOnly && opts.SortBy == BookmarkSortAdded {
+			bookmarks, err = s.queries.ListPublicFavoriteBookmarksNewest(ctx, db.ListPublicFavoriteBookmarksNewestParams{
+				Limit:  limit,
+				Offset: offset,
+			})
+		} else if opts.PublicOnly {
 			bookmarks, err = s.queries.ListPublicFavoriteBookmarks(ctx, db.ListPublicFavoriteBookmarksParams{
 				Limit:  limit,
 				Offset: offset,
 			})
 		} else {
 			bookmarks, err = s.queries.ListFavoriteBookmarks(ctx, db.ListFavoriteBookmarksParams{
-				Limit:  limit,
-				Offset: offset,
+				Archived:        archived,
+				IncludeArchived: includeArchived,
+				Limit:           limit,
+				Offset:          offset,
+			})
+		}
+	} else if opts.CollectionID != nil && opts.IncludeDescendants {
+		ids, idsErr := s.descendantCollectionIDs(ctx, opts)
+		if idsErr != nil {
+			return nil, idsErr
+		}
+		if opts.PublicOnly {
+			bookmarks, err = s.queries.ListPublicBookmarksInCollections(ctx, db.ListPublicBookmarksInCollectionsParams{
+				CollectionIds: ids,
+				Limit:         limit,
+				Offset:        offset,
+			})
+		} else {
+			bookmarks, err = s.queries.ListBookmarksInCollections(ctx, db.ListBookmarksInCollectionsParams{
+				CollectionIds:   ids,
+				Archived:        archived,
+				IncludeArchived: includeArchived,
+				Limit:           limit,
+				Offset:          offset,
 			})
 		}
 	} else if opts.CollectionID != nil {
@@ -129,9 +214,11 @@ func (s *Service) ListBookmarks(ctx context.Context, opts BookmarkListOptions) (
 			})
 		} else {
 			bookmarks, err = s.queries.ListBookmarksByCollection(ctx, db.ListBookmarksByCollectionParams{
-				CollectionID: opts.CollectionID,
-				Limit:        limit,
-				Offset:       offset,
+				CollectionID:    opts.CollectionID,
+				Archived:        archived,
+				IncludeArchived: includeArchived,
+				Limit:           limit,
+				Offset:          offset,
 			})
 		}
 	} else {
@@ -142,8 +229,10 @@ func (s *Service) ListBookmarks(ctx context.Context, opts BookmarkListOptions) (
 			})
 		} else {
 			bookmarks, err = s.queries.ListAllBookmarks(ctx, db.ListAllBookmarksParams{
-				Limit:  limit,
-				Offset: offset,
+				Archived:        archived,
+				IncludeArchived: includeArchived,
+				Limit:           limit,
+				Offset:          offset,
 			})
 		}
 	}
@@ -157,6 +246,13 @@ func (s *Service) ListBookmarks(ctx context.Context, opts BookmarkListOptions) (
 		result = append(result, *dbBookmarkToModel(b))
 	}
 
+	// Tags are only shown in the admin
+	if !opts.PublicOnly {
+		if err := s.loadBookmarkTags(ctx, result); err != nil {
+			return nil, err
+		}
+	}
+
 	return result, nil
 }
 
@@ -165,29 +261,70 @@ func (s *Service) CountBookmarks(ctx context.Context, opts BookmarkListOptions)
 	var count int64
 	var err error
 
+	if opts.PublicOnly && opts.ArchivedOnly {
+		return 0, nil
+	}
+	archived, includeArchived := archiveFilter(opts)
+
 	if opts.FavoritesOnly {
 		if opts.PublicOnly {
 			count, err = s.queries.CountPublicFavoriteBookmarks(ctx)
 		} else {
-			count, err = s.queries.CountFavoriteBookmarks(ctx)
+			count, err = s.queries.CountFavoriteBookmarks(ctx, db.CountFavoriteBookmarksParams{
+				Archived:        archived,
+				IncludeArchived: includeArchived,
+			})
+		}
+	} else if opts.CollectionID != nil && opts.IncludeDescendants {
+		ids, idsErr := s.descendantCollectionIDs(ctx, opts)
+		if idsErr != nil {
+			return 0, idsErr
+		}
+		if opts.PublicOnly {
+			count, err = s.queries.CountPublicBookmarksInCollections(ctx, ids)
+		} else {
+			count, err = s.queries.CountBookmarksInCollections(ctx, db.CountBookmarksInCollectionsParams{
+				CollectionIds:   ids,
+				Archived:        archived,
+				IncludeArchived: includeArchived,
+			})
 		}
 	} else if opts.CollectionID != nil {
 		if opts.PublicOnly {
 			count, err = s.queries.CountPublicBookmarksByCollection(ctx, opts.CollectionID)
 		} else {
-			count, err = s.queries.CountBookmarksByCollection(ctx, opts.CollectionID)
+			count, err = s.queries.CountBookmarksByCollection(ctx, db.CountBookmarksByCollectionParams{
+				CollectionID:    opts.CollectionID,
+				Archived:        archived,
+				IncludeArchived: includeArchived,
+			})
 		}
 	} else {
 		if opts.PublicOnly {
 			count, err = s.queries.CountPublicBookmarks(ctx)
 		} else {
-			count, err = s.queries.CountAllBookmarks(ctx)
+			count, err = s.queries.CountAllBookmarks(ctx, db.CountAllBookmarksParams{
+				Archived:        archived,
+				IncludeArchived: includeArchived,
+			})
 		}
 	}
 
 	return int(count), err
 }
 
+// archiveFilter returns the archived and include_archived arguments of the
+// admin listing queries for opts
+func archiveFilter(opts BookmarkListOptions) (archived, includeArchived int64) {
+	if opts.ArchivedOnly {
+		return 1, 0
+	}
+	if opts.IncludeArchived {
+		return 0, 1
+	}
+	return 0, 0
+}
+
 // Helper function to convert sqlc Bookmark to domain model
 func dbBookmarkToModel(b db.Bookmark) *models.Bookmark {
 	return &models.Bookmark{
@@ -195,18 +332,37 @@ func dbBookmarkToModel(b db.Bookmark) *models.Bookmark {
 		URL:          b.Url,
 		Title:        b.Title,
 		Description:  toNullString(b.Description),
+		Note:         toNullString(b.Note),
 		CoverImage:   toNullString(b.CoverImage),
 		Favicon:      toNullString(b.Favicon),
 		Domain:       toNullString(b.Domain),
 		CollectionID: toNullInt64(b.CollectionID),
 		IsPublic:     derefInt64(b.IsPublic) == 1,
 		IsFavorite:   derefInt64(b.IsFavorite) == 1,
+		IsArchived:   b.IsArchived == 1,
 		SortOrder:    int(derefInt64(b.SortOrder)),
 		CreatedAt:    derefTime(b.CreatedAt),
 		UpdatedAt:    derefTime(b.UpdatedAt),
+		CoverImageID: toNullInt64(b.CoverImageID),
+		FaviconID:    toNullInt64(b.FaviconID),
+		ThumbnailID:  toNullInt64(b.ThumbnailID),
+		ClickCount:   int(b.ClickCount),
 	}
 }
 
+// IncrementBookmarkClick records that a bookmark was opened. The update runs
+// in the background so a redirect isn't held up by the write. Shutdown waits
+// for it; the write is short, so it isn't cancelled with other background
+// work.
+func (s *Service) IncrementBookmarkClick(ctx context.Context, id int64) {
+	ctx = context.WithoutCancel(ctx)
+	s.goBackground(func(context.Context) {
+		if err := s.queries.IncrementBookmarkClickCount(ctx, id); err != nil {
+			logger.Error(ctx, "failed to record bookmark click", "bookmark_id", id, "error", err)
+		}
+	})
+}
+
 // extractDomain extracts the domain from a URL
 func extractDomain(rawURL string) string {
 	parsed, err := url.Parse(rawURL)
@@ -236,138 +392,75 @@ func (s *Service) UpdateBookmarkFavorite(ctx context.Context, id int64, isFavori
 	})
 }
 
+// UpdateBookmarkArchived archives or unarchives a bookmark
+func (s *Service) UpdateBookmarkArchived(ctx context.Context, id int64, archived bool) error {
+	var isArchived int64
+	if archived {
+		isArchived = 1
+	}
+	return s.queries.UpdateBookmarkArchived(ctx, db.UpdateBookmarkArchivedParams{
+		IsArchived: isArchived,
+		ID:         id,
+	})
+}
+
 // MoveBookmark moves a bookmark to a new position within a collection (or unsorted).
 // collectionID: target collection (nil = unsorted column)
 // afterBookmarkID: bookmark ID to insert after (nil = insert at the beginning)
 func (s *Service) MoveBookmark(ctx context.Context, bookmarkID int64, collectionID *int64, afterBookmarkID *int64) error {
-	const (
-		defaultGap    = 1000
-		minGap        = 1
-		rebalanceGap  = 1000
-		firstPosition = 1000
-	)
-
 	// Get all bookmark sort orders in the target column
-	var sortOrders []struct {
-		ID        int64
-		SortOrder int64
+	sortOrders, err := s.bookmarkSortOrders(ctx, collectionID)
+	if err != nil {
+		return err
+	}
+
+	// Filter out the bookmark being moved (it might already be in this column)
+	sortOrders = withoutSortItems(sortOrders, bookmarkID)
+
+	newSortOrder, rebalanced := sortOrderAfter(sortOrders, afterBookmarkID)
+	if err := s.rebalanceBookmarks(ctx, collectionID, rebalanced); err != nil {
+		return err
 	}
 
+	// Update the bookmark's position
+	return s.queries.UpdateBookmarkPosition(ctx, db.UpdateBookmarkPositionParams{
+		CollectionID: collectionID,
+		SortOrder:    &newSortOrder,
+		ID:           bookmarkID,
+	})
+}
+
+// bookmarkSortOrders returns the bookmarks in a column in display order
+func (s *Service) bookmarkSortOrders(ctx context.Context, collectionID *int64) ([]sortItem, error) {
+	var sortOrders []sortItem
 	if collectionID != nil {
 		rows, err := s.queries.GetCollectionBookmarkSortOrders(ctx, collectionID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for _, r := range rows {
-			sortOrders = append(sortOrders, struct {
-				ID        int64
-				SortOrder int64
-			}{ID: r.ID, SortOrder: r.SortOrder})
+			sortOrders = append(sortOrders, sortItem{ID: r.ID, SortOrder: r.SortOrder})
 		}
 	} else {
 		rows, err := s.queries.GetUnsortedBookmarkSortOrders(ctx)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for _, r := range rows {
-			sortOrders = append(sortOrders, struct {
-				ID        int64
-				SortOrder int64
-			}{ID: r.ID, SortOrder: r.SortOrder})
+			sortOrders = append(sortOrders, sortItem{ID: r.ID, SortOrder: r.SortOrder})
 		}
 	}
-
-	// Filter out the bookmark being moved (it might already be in this column)
-	filtered := make([]struct {
-		ID        int64
-		SortOrder int64
-	}, 0, len(sortOrders))
-	for _, so := range sortOrders {
-		if so.ID != bookmarkID {
-			filtered = append(filtered, so)
-		}
-	}
-	sortOrders = filtered
-
-	// Calculate new sort_order based on position
-	var newSortOrder int64
-
-	if afterBookmarkID == nil {
-		// Insert at the beginning
-		if len(sortOrders) == 0 {
-			newSortOrder = firstPosition
-		} else {
-			// Place before the first item
-			firstOrder := sortOrders[0].SortOrder
-			if firstOrder > minGap {
-				newSortOrder = firstOrder / 2
-			} else {
-				// Need to rebalance - shift everything down
-				newSortOrder = firstPosition
-				if err := s.rebalanceBookmarks(ctx, collectionID, sortOrders, rebalanceGap, firstPosition+rebalanceGap); err != nil {
-					return err
-				}
-			}
-		}
-	} else {
-		// Find the position of afterBookmarkID
-		afterIndex := -1
-		for i, so := range sortOrders {
-			if so.ID == *afterBookmarkID {
-				afterIndex = i
-				break
-			}
-		}
-
-		if afterIndex == -1 {
-			// afterBookmarkID not found in target column, insert at end
-			if len(sortOrders) == 0 {
-				newSortOrder = firstPosition
-			} else {
-				newSortOrder = sortOrders[len(sortOrders)-1].SortOrder + defaultGap
-			}
-		} else if afterIndex == len(sortOrders)-1 {
-			// Insert at the end (after the last item)
-			newSortOrder = sortOrders[afterIndex].SortOrder + defaultGap
-		} else {
-			// Insert between afterIndex and afterIndex+1
-			prevOrder := sortOrders[afterIndex].SortOrder
-			nextOrder := sortOrders[afterIndex+1].SortOrder
-			gap := nextOrder - prevOrder
-
-			if gap > minGap {
-				newSortOrder = prevOrder + gap/2
-			} else {
-				// Need to rebalance
-				newSortOrder = prevOrder + rebalanceGap/2
-				if err := s.rebalanceBookmarks(ctx, collectionID, sortOrders, rebalanceGap, firstPosition); err != nil {
-					return err
-				}
-				// Recalculate position after rebalance
-				newSortOrder = int64(afterIndex+1)*rebalanceGap + rebalanceGap/2
-			}
-		}
-	}
-
-	// Update the bookmark's position
-	return s.queries.UpdateBookmarkPosition(ctx, db.UpdateBookmarkPositionParams{
-		CollectionID: collectionID,
-		SortOrder:    &newSortOrder,
-		ID:           bookmarkID,
-	})
+	return sortOrders, nil
 }
 
-// rebalanceBookmarks reassigns sort_order values with consistent gaps
-func (s *Service) rebalanceBookmarks(ctx context.Context, collectionID *int64, sortOrders []struct {
-	ID        int64
-	SortOrder int64
-}, gap int64, startAt int64) error {
-	for i, so := range sortOrders {
-		newOrder := startAt + int64(i)*gap
+// rebalanceBookmarks writes the sort_order of each item
+func (s *Service) rebalanceBookmarks(ctx context.Context, collectionID *int64, items []sortItem) error {
+	for _, item := range items {
+		newOrder := item.SortOrder
 		err := s.queries.UpdateBookmarkPosition(ctx, db.UpdateBookmarkPositionParams{
 			CollectionID: collectionID,
 			SortOrder:    &newOrder,
-			ID:           so.ID,
+			ID:           item.ID,
 		})
 		if err != nil {
 			return err
@@ -380,65 +473,24 @@ func (s *Service) rebalanceBookmarks(ctx context.Context, collectionID *int64, s
 // collectionID: target collection (nil = unsorted column)
 // afterBookmarkID: bookmark ID to insert after (nil = insert at the beginning)
 // Bookmarks are inserted in the order provided, maintaining their relative positions.
-func (s *Service) BulkMoveBookmarks(ctx context.Context, bookmarkIDs []int64, collectionID *int64, afterBookmarkID *int64) error {
+// Returns a token for UndoBulkMove that puts them back where they were.
+func (s *Service) BulkMoveBookmarks(ctx context.Context, bookmarkIDs []int64, collectionID *int64, afterBookmarkID *int64) (string, error) {
 	if len(bookmarkIDs) == 0 {
-		return nil
-	}
-
-	const (
-		defaultGap    = 1000
-		minGap        = 1
-		rebalanceGap  = 1000
-		firstPosition = 1000
-	)
-
-	// Get all bookmark sort orders in the target column (excluding the ones we're moving)
-	var sortOrders []struct {
-		ID        int64
-		SortOrder int64
-	}
-
-	if collectionID != nil {
-		rows, err := s.queries.GetCollectionBookmarkSortOrders(ctx, collectionID)
-		if err != nil {
-			return err
-		}
-		for _, r := range rows {
-			sortOrders = append(sortOrders, struct {
-				ID        int64
-				SortOrder int64
-			}{ID: r.ID, SortOrder: r.SortOrder})
-		}
-	} else {
-		rows, err := s.queries.GetUnsortedBookmarkSortOrders(ctx)
-		if err != nil {
-			return err
-		}
-		for _, r := range rows {
-			sortOrders = append(sortOrders, struct {
-				ID        int64
-				SortOrder int64
-			}{ID: r.ID, SortOrder: r.SortOrder})
-		}
+		return "", nil
 	}
 
-	// Create a set of bookmark IDs we're moving for fast lookup
-	movingSet := make(map[int64]bool)
-	for _, id := range bookmarkIDs {
-		movingSet[id] = true
+	// Remember where the bookmarks were so the move can be undone
+	previous, err := s.snapshotBookmarkPositions(ctx, bookmarkIDs)
+	if err != nil {
+		return "", err
 	}
 
-	// Filter out the bookmarks being moved
-	filtered := make([]struct {
-		ID        int64
-		SortOrder int64
-	}, 0, len(sortOrders))
-	for _, so := range sortOrders {
-		if !movingSet[so.ID] {
-			filtered = append(filtered, so)
-		}
+	// Get all bookmark sort orders in the target column (excluding the ones we're moving)
+	sortOrders, err := s.bookmarkSortOrders(ctx, collectionID)
+	if err != nil {
+		return "", err
 	}
-	sortOrders = filtered
+	sortOrders = withoutSortItems(sortOrders, bookmarkIDs...)
 
 	// Calculate starting sort_order based on afterBookmarkID
 	var startSortOrder int64
@@ -446,19 +498,19 @@ func (s *Service) BulkMoveBookmarks(ctx context.Context, bookmarkIDs []int64, co
 	if afterBookmarkID == nil {
 		// Insert at the beginning
 		if len(sortOrders) == 0 {
-			startSortOrder = firstPosition
+			startSortOrder = firstSortPosition
 		} else {
 			firstOrder := sortOrders[0].SortOrder
 			// Need enough room for all bookmarks
-			neededSpace := int64(len(bookmarkIDs)) * defaultGap
+			neededSpace := int64(len(bookmarkIDs)) * sortGap
 			if firstOrder > neededSpace {
 				startSortOrder = firstOrder - neededSpace
 			} else {
 				// Rebalance existing bookmarks to make room
-				startSortOrder = firstPosition
-				startOffset := firstPosition + int64(len(bookmarkIDs))*defaultGap
-				if err := s.rebalanceBookmarks(ctx, collectionID, sortOrders, rebalanceGap, startOffset); err != nil {
-					return err
+				startSortOrder = firstSortPosition
+				startOffset := firstSortPosition + int64(len(bookmarkIDs))*sortGap
+				if err := s.rebalanceBookmarks(ctx, collectionID, spreadSortItems(sortOrders, startOffset)); err != nil {
+					return "", err
 				}
 			}
 		}
@@ -475,31 +527,31 @@ func (s *Service) BulkMoveBookmarks(ctx context.Context, bookmarkIDs []int64, co
 		if afterIndex == -1 {
 			// afterBookmarkID not found, insert at end
 			if len(sortOrders) == 0 {
-				startSortOrder = firstPosition
+				startSortOrder = firstSortPosition
 			} else {
-				startSortOrder = sortOrders[len(sortOrders)-1].SortOrder + defaultGap
+				startSortOrder = sortOrders[len(sortOrders)-1].SortOrder + sortGap
 			}
 		} else if afterIndex == len(sortOrders)-1 {
 			// Insert after the last item
-			startSortOrder = sortOrders[afterIndex].SortOrder + defaultGap
+			startSortOrder = sortOrders[afterIndex].SortOrder + sortGap
 		} else {
 			// Insert between afterIndex and afterIndex+1
 			prevOrder := sortOrders[afterIndex].SortOrder
 			nextOrder := sortOrders[afterIndex+1].SortOrder
 			gap := nextOrder - prevOrder
-			neededSpace := int64(len(bookmarkIDs)) * minGap
+			neededSpace := int64(len(bookmarkIDs)) * minSortGap
 
 			if gap > neededSpace {
 				// Enough space, distribute evenly
 				startSortOrder = prevOrder + gap/int64(len(bookmarkIDs)+1)
 			} else {
 				// Need to rebalance
-				startSortOrder = prevOrder + defaultGap
+				startSortOrder = prevOrder + sortGap
 				// Rebalance items after the insertion point
 				itemsToRebalance := sortOrders[afterIndex+1:]
-				startOffset := startSortOrder + int64(len(bookmarkIDs))*defaultGap
-				if err := s.rebalanceBookmarks(ctx, collectionID, itemsToRebalance, rebalanceGap, startOffset); err != nil {
-					return err
+				startOffset := startSortOrder + int64(len(bookmarkIDs))*sortGap
+				if err := s.rebalanceBookmarks(ctx, collectionID, spreadSortItems(itemsToRebalance, startOffset)); err != nil {
+					return "", err
 				}
 			}
 		}
@@ -507,14 +559,14 @@ func (s *Service) BulkMoveBookmarks(ctx context.Context, bookmarkIDs []int64, co
 
 	// Update each bookmark with new collection and sort_order
 	for i, id := range bookmarkIDs {
-		sortOrder := startSortOrder + int64(i)*defaultGap
+		sortOrder := startSortOrder + int64(i)*sortGap
 		err := s.queries.UpdateBookmarkPosition(ctx, db.UpdateBookmarkPositionParams{
 			CollectionID: collectionID,
 			SortOrder:    &sortOrder,
 			ID:           id,
 		})
 		if err != nil {
-			return err
+			return "", err
 		}
 	}
 
@@ -526,16 +578,18 @@ func (s *Service) BulkMoveBookmarks(ctx context.Context, bookmarkIDs []int64, co
 		"bookmark_ids": bookmarkIDs,
 	})
 
-	return nil
+	return s.undo.put(previous, time.Now()), nil
 }
 
 // BulkDeleteBookmarks deletes multiple bookmarks
 func (s *Service) BulkDeleteBookmarks(ctx context.Context, bookmarkIDs []int64) error {
 	for _, id := range bookmarkIDs {
+		current, _ := s.queries.GetBookmarkByID(ctx, id)
 		err := s.queries.DeleteBookmark(ctx, id)
 		if err != nil {
 			return err
 		}
+		s.deleteImages(ctx, current.CoverImageID, current.ThumbnailID)
 	}
 
 	// Log activity for bulk delete
@@ -564,5 +618,16 @@ func (s *Service) ListUnsortedBookmarks(ctx context.Context, limit, offset int)
 		result = append(result, *dbBookmarkToModel(b))
 	}
 
+	if err := s.loadBookmarkTags(ctx, result); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
+
+// CountUnsortedBookmarks returns the number of unarchived bookmarks without
+// a collection
+func (s *Service) CountUnsortedBookmarks(ctx context.Context) (int, error) {
+	count, err := s.queries.CountUnsortedBookmarks(ctx)
+	return int(count), err
+}
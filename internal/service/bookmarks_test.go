@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestIncrementBookmarkClick(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	bookmark, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+		URL:      "https://example.com",
+		Title:    "Example",
+		IsPublic: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The request context may be gone before the background update runs
+	reqCtx, cancel := context.WithCancel(ctx)
+	svc.IncrementBookmarkClick(reqCtx, bookmark.ID)
+	svc.IncrementBookmarkClick(reqCtx, bookmark.ID)
+	cancel()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got, err := svc.GetBookmarkByID(ctx, bookmark.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ClickCount == 2 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("ClickCount = %d, want 2", got.ClickCount)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestIncrementBookmarkClick_ShutdownWaits(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	bookmark, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+		URL:   "https://example.com",
+		Title: "Example",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc.IncrementBookmarkClick(ctx, bookmark.ID)
+	if err := svc.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := svc.GetBookmarkByID(ctx, bookmark.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ClickCount != 1 {
+		t.Errorf("ClickCount = %d after shutdown, want 1", got.ClickCount)
+	}
+}
+
+func TestBulkTagBookmarks(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	var ids []int64
+	for _, url := range []string{"https://example.com/a", "https://example.com/b"} {
+		b, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{URL: url, Title: url})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, b.ID)
+	}
+	tag, err := svc.CreateTag(ctx, models.CreateTagInput{Name: "Reading", Slug: "reading"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tagCount := func(id int64) int {
+		t.Helper()
+		b, err := svc.GetBookmarkByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(b.Tags)
+	}
+
+	// Adding twice must not fail or duplicate the tag
+	for range 2 {
+		if err := svc.BulkAddTagToBookmarks(ctx, ids, tag.ID); err != nil {
+			t.Fatalf("BulkAddTagToBookmarks() error = %v", err)
+		}
+	}
+	for _, id := range ids {
+		if got := tagCount(id); got != 1 {
+			t.Errorf("bookmark %d has %d tags, want 1", id, got)
+		}
+	}
+
+	listed, err := svc.ListBookmarks(ctx, BookmarkListOptions{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range listed {
+		if len(b.Tags) != 1 || b.Tags[0].Slug != "reading" {
+			t.Errorf("listed bookmark %d tags = %+v, want [reading]", b.ID, b.Tags)
+		}
+	}
+
+	if err := svc.BulkRemoveTag(ctx, ids[:1], tag.ID); err != nil {
+		t.Fatalf("BulkRemoveTag() error = %v", err)
+	}
+	if got := tagCount(ids[0]); got != 0 {
+		t.Errorf("untagged bookmark has %d tags, want 0", got)
+	}
+	if got := tagCount(ids[1]); got != 1 {
+		t.Errorf("other bookmark has %d tags, want 1", got)
+	}
+
+	if err := svc.BulkAddTagToBookmarks(ctx, ids, 999); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("BulkAddTagToBookmarks(unknown tag) error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestListBookmarks_Archived(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	collection, err := svc.CreateCollection(ctx, models.CreateCollectionInput{Name: "Reading", Slug: "reading", IsPublic: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make(map[string]int64)
+	for _, b := range []struct {
+		title    string
+		public   bool
+		archived bool
+	}{
+		{"public", true, false},
+		{"public-archived", true, true},
+		{"private", false, false},
+		{"private-archived", false, true},
+	} {
+		created, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+			URL:          "https://example.com/" + b.title,
+			Title:        b.title,
+			CollectionID: &collection.ID,
+			IsPublic:     b.public,
+			IsFavorite:   true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.UpdateBookmarkArchived(ctx, created.ID, b.archived); err != nil {
+			t.Fatal(err)
+		}
+		ids[b.title] = created.ID
+	}
+
+	tests := []struct {
+		name string
+		opts BookmarkListOptions
+		want []string
+	}{
+		{"admin default", BookmarkListOptions{}, []string{"public", "private"}},
+		{"admin include archived", BookmarkListOptions{IncludeArchived: true}, []string{"public", "public-archived", "private", "private-archived"}},
+		{"admin archived only", BookmarkListOptions{ArchivedOnly: true}, []string{"public-archived", "private-archived"}},
+		{"admin archived only wins", BookmarkListOptions{ArchivedOnly: true, IncludeArchived: true}, []string{"public-archived", "private-archived"}},
+		{"admin collection", BookmarkListOptions{CollectionID: &collection.ID}, []string{"public", "private"}},
+		{"admin collection archived only", BookmarkListOptions{CollectionID: &collection.ID, IncludeDescendants: true, ArchivedOnly: true}, []string{"public-archived", "private-archived"}},
+		{"admin favorites include archived", BookmarkListOptions{FavoritesOnly: true, IncludeArchived: true}, []string{"public", "public-archived", "private", "private-archived"}},
+		{"public", BookmarkListOptions{PublicOnly: true}, []string{"public"}},
+		{"public include archived", BookmarkListOptions{PublicOnly: true, IncludeArchived: true}, []string{"public"}},
+		{"public archived only", BookmarkListOptions{PublicOnly: true, ArchivedOnly: true}, nil},
+		{"public collection", BookmarkListOptions{PublicOnly: true, CollectionID: &collection.ID, IncludeArchived: true}, []string{"public"}},
+		{"public collection tree", BookmarkListOptions{PublicOnly: true, CollectionID: &collection.ID, IncludeDescendants: true}, []string{"public"}},
+		{"public favorites", BookmarkListOptions{PublicOnly: true, FavoritesOnly: true, IncludeArchived: true}, []string{"public"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Limit = 10
+			got, err := svc.ListBookmarks(ctx, tt.opts)
+			if err != nil {
+				t.Fatalf("ListBookmarks() error = %v", err)
+			}
+			gotIDs := make(map[int64]bool, len(got))
+			for _, b := range got {
+				gotIDs[b.ID] = true
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("ListBookmarks() returned %d bookmarks, want %d", len(got), len(tt.want))
+			}
+			for _, title := range tt.want {
+				if !gotIDs[ids[title]] {
+					t.Errorf("ListBookmarks() is missing %q", title)
+				}
+			}
+
+			count, err := svc.CountBookmarks(ctx, tt.opts)
+			if err != nil {
+				t.Fatalf("CountBookmarks() error = %v", err)
+			}
+			if count != len(tt.want) {
+				t.Errorf("CountBookmarks() = %d, want %d", count, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestRefreshBookmarkMetadata_KeepsNote(t *testing.T) {
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><meta property="og:title" content="Fetched title"><meta property="og:description" content="Fetched description"></head></html>`))
+	}))
+	defer page.Close()
+
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	note := "Read the second half again.\nCompare with the RFC."
+	bookmark, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+		URL:   page.URL,
+		Title: "Untitled",
+		Note:  note,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.RefreshBookmarkMetadata(ctx, bookmark.ID); err != nil {
+		t.Fatalf("RefreshBookmarkMetadata() error = %v", err)
+	}
+
+	got, err := svc.GetBookmarkByID(ctx, bookmark.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Fetched title" || got.GetDescription() != "Fetched description" {
+		t.Errorf("metadata not refreshed: title %q, description %q", got.Title, got.GetDescription())
+	}
+	if got.GetNote() != note {
+		t.Errorf("GetNote() = %q, want %q", got.GetNote(), note)
+	}
+}
+
+func TestDeleteBookmark_DropsStoredImages(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	deletes := map[string]func(id int64) error{
+		"single": func(id int64) error { return svc.DeleteBookmark(ctx, id) },
+		"bulk":   func(id int64) error { return svc.BulkDeleteBookmarks(ctx, []int64{id}) },
+	}
+	for name, deleteBookmark := range deletes {
+		t.Run(name, func(t *testing.T) {
+			bookmark, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+				URL:   "https://example.com/" + name,
+				Title: "Page",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Wide enough to get a thumbnail as well
+			if _, err := svc.StoreBookmarkCover(ctx, bookmark.ID, "image/png", encodeTestImage(t, "png", 2*defaultThumbnailMaxWidth, 2)); err != nil {
+				t.Fatal(err)
+			}
+			stored, err := svc.GetBookmarkByID(ctx, bookmark.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !stored.CoverImageID.Valid || !stored.ThumbnailID.Valid {
+				t.Fatalf("stored images = cover %+v, thumbnail %+v; want both", stored.CoverImageID, stored.ThumbnailID)
+			}
+
+			if err := deleteBookmark(bookmark.ID); err != nil {
+				t.Fatal(err)
+			}
+			for _, id := range []int64{stored.CoverImageID.Int64, stored.ThumbnailID.Int64} {
+				if _, err := svc.GetImage(ctx, id); !errors.Is(err, sql.ErrNoRows) {
+					t.Errorf("GetImage(%d) error = %v, want sql.ErrNoRows", id, err)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateBookmark_CoverURLChangeDropsStoredImages(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	bookmark, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+		URL:        "https://example.com/page",
+		Title:      "Page",
+		CoverImage: "https://example.com/old.png",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := svc.StoreBookmarkCover(ctx, bookmark.ID, "image/png", encodeTestImage(t, "png", 2, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := models.UpdateBookmarkInput{
+		URL:        bookmark.URL,
+		Title:      "Renamed",
+		CoverImage: "https://example.com/old.png",
+	}
+
+	// Other edits keep the stored cover
+	got, err := svc.UpdateBookmark(ctx, bookmark.ID, update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.CoverImageID.Valid || got.CoverImageID.Int64 != stored.ID {
+		t.Fatalf("CoverImageID = %+v after a title edit, want %d", got.CoverImageID, stored.ID)
+	}
+
+	update.CoverImage = "https://example.com/new.png"
+	got, err = svc.UpdateBookmark(ctx, bookmark.ID, update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.CoverImageID.Valid || got.ThumbnailID.Valid {
+		t.Errorf("stored images kept after the cover URL changed: cover %+v, thumbnail %+v", got.CoverImageID, got.ThumbnailID)
+	}
+	if got.GetCoverImage() != "https://example.com/new.png" {
+		t.Errorf("GetCoverImage() = %q, want the new URL", got.GetCoverImage())
+	}
+	if _, err := svc.GetImage(ctx, stored.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetImage(old cover) error = %v, want sql.ErrNoRows", err)
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+)
+
+// ErrInvalidUndoToken is returned for an undo token that was never issued,
+// has already been used, or has expired
+var ErrInvalidUndoToken = errors.New("invalid or expired undo token")
+
+// BulkMoveUndoTTL is how long a bulk move can be undone
+const BulkMoveUndoTTL = 5 * time.Minute
+
+// bookmarkPosition is where a bookmark was before a bulk move
+type bookmarkPosition struct {
+	ID           int64
+	CollectionID *int64
+	SortOrder    *int64
+}
+
+// snapshotBookmarkPositions records the current collection and sort order
+// of each bookmark. Bookmarks that don't exist are skipped.
+func (s *Service) snapshotBookmarkPositions(ctx context.Context, bookmarkIDs []int64) ([]bookmarkPosition, error) {
+	positions := make([]bookmarkPosition, 0, len(bookmarkIDs))
+	for _, id := range bookmarkIDs {
+		b, err := s.queries.GetBookmarkByID(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, bookmarkPosition{ID: id, CollectionID: b.CollectionID, SortOrder: b.SortOrder})
+	}
+	return positions, nil
+}
+
+// UndoBulkMove puts the bookmarks moved by a BulkMoveBookmarks call back in
+// the collection and position they had before. A token can be used once,
+// within BulkMoveUndoTTL of the move.
+func (s *Service) UndoBulkMove(ctx context.Context, token string) error {
+	positions, ok := s.undo.take(token, time.Now())
+	if !ok {
+		return ErrInvalidUndoToken
+	}
+
+	ids := make([]int64, 0, len(positions))
+	for _, p := range positions {
+		err := s.queries.UpdateBookmarkPosition(ctx, db.UpdateBookmarkPositionParams{
+			CollectionID: p.CollectionID,
+			SortOrder:    p.SortOrder,
+			ID:           p.ID,
+		})
+		if err != nil {
+			return err
+		}
+		ids = append(ids, p.ID)
+	}
+
+	s.LogActivity(ctx, ActionBookmarkUpdated, EntityBookmark, 0, "", map[string]interface{}{
+		"action":       "undo_bulk_move",
+		"count":        len(ids),
+		"bookmark_ids": ids,
+	})
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestUndoBulkMove(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	var collections []*models.Collection
+	for _, name := range []string{"reading", "later"} {
+		c, err := svc.CreateCollection(ctx, models.CreateCollectionInput{Name: name, Slug: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		collections = append(collections, c)
+	}
+	reading, later := collections[0], collections[1]
+
+	var ids []int64
+	for i, collectionID := range []*int64{&reading.ID, &reading.ID, &reading.ID, &later.ID, nil} {
+		b, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+			URL:          "https://example.com/" + string(rune('a'+i)),
+			Title:        "Bookmark",
+			CollectionID: collectionID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, b.ID)
+	}
+
+	type position struct {
+		collectionID sql.NullInt64
+		sortOrder    int
+	}
+	positions := func() map[int64]position {
+		t.Helper()
+		got := make(map[int64]position)
+		for _, id := range ids {
+			b, err := svc.GetBookmarkByID(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got[id] = position{b.CollectionID, b.SortOrder}
+		}
+		return got
+	}
+	before := positions()
+
+	// Move the first two "reading" bookmarks and the unsorted one into "later"
+	moved := []int64{ids[0], ids[1], ids[4]}
+	token, err := svc.BulkMoveBookmarks(ctx, moved, &later.ID, &ids[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token == "" {
+		t.Fatal("BulkMoveBookmarks() returned no undo token")
+	}
+	for _, id := range moved {
+		if got := positions()[id].collectionID; got.Int64 != later.ID {
+			t.Fatalf("bookmark %d collection = %v after move, want %d", id, got, later.ID)
+		}
+	}
+
+	if err := svc.UndoBulkMove(ctx, token); err != nil {
+		t.Fatalf("UndoBulkMove() error = %v", err)
+	}
+	after := positions()
+	for _, id := range ids {
+		if after[id] != before[id] {
+			t.Errorf("bookmark %d position = %+v after undo, want %+v", id, after[id], before[id])
+		}
+	}
+
+	if err := svc.UndoBulkMove(ctx, token); !errors.Is(err, ErrInvalidUndoToken) {
+		t.Errorf("second UndoBulkMove() error = %v, want ErrInvalidUndoToken", err)
+	}
+	if err := svc.UndoBulkMove(ctx, "unknown"); !errors.Is(err, ErrInvalidUndoToken) {
+		t.Errorf("UndoBulkMove(unknown) error = %v, want ErrInvalidUndoToken", err)
+	}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+// Post card dimensions match the common Open Graph image size
+const (
+	cardWidth  = 1200
+	cardHeight = 630
+	cardMargin = 80
+
+	// cardLabelScale is the font scale of the site label at the bottom
+	cardLabelScale = 4
+)
+
+// cardTitleScales are tried in order until the title fits on the card
+var cardTitleScales = []int{10, 8, 6}
+
+// cardPalette holds the background colors a card can get. The color is
+// picked from the title so a post keeps its color between renders.
+var cardPalette = []color.RGBA{
+	{R: 0x1e, G: 0x3a, B: 0x5f, A: 0xff}, // navy
+	{R: 0x2d, G: 0x6a, B: 0x4f, A: 0xff}, // green
+	{R: 0x7c, G: 0x2d, B: 0x12, A: 0xff}, // rust
+	{R: 0x4c, G: 0x1d, B: 0x95, A: 0xff}, // violet
+	{R: 0x13, G: 0x4e, B: 0x4a, A: 0xff}, // teal
+	{R: 0x83, G: 0x18, B: 0x43, A: 0xff}, // plum
+	{R: 0x37, G: 0x41, B: 0x51, A: 0xff}, // slate
+}
+
+// postCard is a rendered card and the key of the content it was drawn from
+type postCard struct {
+	key string
+	png []byte
+}
+
+// PostCardImage returns a PNG card showing the post title and a site label.
+// Titles the card font cannot draw, such as ones in Japanese, get a card
+// showing just the label. Cards are cached per post and redrawn when the
+// title or label changes.
+func (s *Service) PostCardImage(post *models.Post, label string) ([]byte, error) {
+	key := cardKey(post.Title, label)
+
+	s.cardsMu.Lock()
+	card, ok := s.cards[post.ID]
+	s.cardsMu.Unlock()
+	if ok && card.key == key {
+		return card.png, nil
+	}
+
+	data, err := renderPostCard(post.Title, label)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cardsMu.Lock()
+	s.cards[post.ID] = postCard{key: key, png: data}
+	s.cardsMu.Unlock()
+
+	return data, nil
+}
+
+// cardKey hashes the text drawn on a card
+func cardKey(title, label string) string {
+	sum := sha256.Sum256([]byte(title + "\x00" + label))
+	return hex.EncodeToString(sum[:8])
+}
+
+// renderPostCard draws the title in white on a colored background, with the
+// label along the bottom edge, and encodes it as PNG
+func renderPostCard(title, label string) ([]byte, error) {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(title)))
+	title, label = cardTitleAndLabel(title, label)
+
+	bg := cardPalette[int(sum[0])%len(cardPalette)]
+
+	img := image.NewRGBA(image.Rect(0, 0, cardWidth, cardHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: bg}, image.Point{}, draw.Src)
+
+	// The title gets everything above the label row
+	textWidth := cardWidth - 2*cardMargin
+	titleHeight := cardHeight - 2*cardMargin - 2*cardGlyphHeight*cardLabelScale
+
+	var lines []string
+	var scale int
+	for _, scale = range cardTitleScales {
+		lines = wrapCardText(title, textWidth/cardAdvance(scale))
+		if len(lines) <= titleHeight/cardLineHeight(scale) {
+			break
+		}
+	}
+	if maxLines := titleHeight / cardLineHeight(scale); len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := []rune(lines[maxLines-1])
+		if room := textWidth/cardAdvance(scale) - 3; len(last) > room {
+			last = last[:room]
+		}
+		lines[maxLines-1] = strings.TrimRight(string(last), " ") + "..."
+	}
+
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	for i, line := range lines {
+		drawCardText(img, line, cardMargin, cardMargin+i*cardLineHeight(scale), scale, white)
+	}
+
+	if label != "" {
+		muted := color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xb0}
+		y := cardHeight - cardMargin - cardGlyphHeight*cardLabelScale
+		drawCardText(img, label, cardMargin, y, cardLabelScale, muted)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// cardTitleAndLabel returns the text drawn as a card's title and label.
+// A title with characters the font cannot draw would come out as a row of
+// '?', so the label is shown in its place instead.
+func cardTitleAndLabel(title, label string) (string, string) {
+	label, _ = cardText(label)
+	if text, ok := cardText(title); ok {
+		return text, label
+	}
+	return label, ""
+}
+
+// cardAdvance is the horizontal distance between characters at scale
+func cardAdvance(scale int) int {
+	return (cardGlyphWidth + 1) * scale
+}
+
+// cardLineHeight is the vertical distance between lines at scale
+func cardLineHeight(scale int) int {
+	return (cardGlyphHeight + 3) * scale
+}
+
+// wrapCardText breaks text into lines of at most maxChars characters,
+// splitting on spaces and hard-breaking words that are too long
+func wrapCardText(text string, maxChars int) []string {
+	if maxChars < 1 {
+		maxChars = 1
+	}
+
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(text) {
+		w := []rune(word)
+		for len(w) > maxChars {
+			if len(line) > 0 {
+				lines = append(lines, string(line))
+				line = nil
+			}
+			lines = append(lines, string(w[:maxChars]))
+			w = w[maxChars:]
+		}
+		if len(w) == 0 {
+			continue
+		}
+		switch {
+		case len(line) == 0:
+			line = w
+		case len(line)+1+len(w) <= maxChars:
+			line = append(append(line, ' '), w...)
+		default:
+			lines = append(lines, string(line))
+			line = w
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// drawCardText draws text with its top-left corner at (x, y), each font
+// pixel becoming a scale x scale square
+func drawCardText(img *image.RGBA, text string, x, y, scale int, c color.Color) {
+	src := &image.Uniform{C: c}
+	for _, r := range text {
+		glyph, ok := cardFont[r]
+		if !ok {
+			glyph = cardFont['?']
+		}
+		for row := 0; row < cardGlyphHeight; row++ {
+			for col := 0; col < cardGlyphWidth; col++ {
+				if glyph[row]&(1<<(cardGlyphWidth-1-col)) == 0 {
+					continue
+				}
+				px := image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale)
+				draw.Draw(img, px, src, image.Point{}, draw.Over)
+			}
+		}
+		x += cardAdvance(scale)
+	}
+}
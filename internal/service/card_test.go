@@ -0,0 +1,110 @@
+package service
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestRenderPostCard(t *testing.T) {
+	titles := []string{
+		"Hello, World",
+		"",
+		strings.Repeat("A very long title that will not fit on one line ", 10),
+		"Supercalifragilisticexpialidocious-and-then-some-more-letters",
+		"Ünïcödé títle ✨",
+		"日本語のタイトル",
+	}
+
+	for _, title := range titles {
+		data, err := renderPostCard(title, "example.com")
+		if err != nil {
+			t.Fatalf("renderPostCard(%q) error = %v", title, err)
+		}
+
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("decoding card for %q: %v", title, err)
+		}
+		if b := img.Bounds(); b.Dx() != cardWidth || b.Dy() != cardHeight {
+			t.Errorf("card size = %dx%d, want %dx%d", b.Dx(), b.Dy(), cardWidth, cardHeight)
+		}
+	}
+}
+
+func TestPostCardImage_CachesByTitle(t *testing.T) {
+	s := &Service{cards: make(map[int64]postCard)}
+	post := &models.Post{ID: 1, Title: "First title"}
+
+	first, err := s.PostCardImage(post, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := s.PostCardImage(post, "example.com")
+	if &first[0] != &again[0] {
+		t.Error("PostCardImage() re-rendered an unchanged card")
+	}
+
+	post.Title = "Second title"
+	renamed, _ := s.PostCardImage(post, "example.com")
+	if bytes.Equal(first, renamed) {
+		t.Error("PostCardImage() returned stale card after title change")
+	}
+}
+
+func TestWrapCardText(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxChars int
+		want     []string
+	}{
+		{"HELLO WORLD", 20, []string{"HELLO WORLD"}},
+		{"HELLO WORLD", 8, []string{"HELLO", "WORLD"}},
+		{"ABCDEFGHIJ KL", 4, []string{"ABCD", "EFGH", "IJ", "KL"}},
+		{"  ", 10, nil},
+	}
+
+	for _, tt := range tests {
+		got := wrapCardText(tt.text, tt.maxChars)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrapCardText(%q, %d) = %q, want %q", tt.text, tt.maxChars, got, tt.want)
+		}
+	}
+}
+
+func TestCardText(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"Hello, World", "HELLO, WORLD", true},
+		{"Ünïcödé títle", "UNICODE TITLE", true},
+		{"Straße — “quoted” …", "STRASSE - \"QUOTED\" ...", true},
+		{"Cafe\u0301 {braces}", "CAFE {BRACES}", true},
+		{"Go 1.25 リリース", "GO 1.25 リリース", false},
+		{"Launch ✨", "LAUNCH ✨", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := cardText(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("cardText(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestCardTitleAndLabel_UndrawableTitle(t *testing.T) {
+	title, label := cardTitleAndLabel("日本語のタイトル", "example.com")
+	if title != "EXAMPLE.COM" || label != "" {
+		t.Errorf("cardTitleAndLabel() = %q, %q; want the label as the title", title, label)
+	}
+
+	title, label = cardTitleAndLabel("Crème brûlée", "example.com")
+	if title != "CREME BRULEE" || label != "EXAMPLE.COM" {
+		t.Errorf("cardTitleAndLabel() = %q, %q; want folded title and label", title, label)
+	}
+}
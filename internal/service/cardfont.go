@@ -0,0 +1,158 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// cardGlyphWidth and cardGlyphHeight are the size of a cardFont glyph in
+// font pixels, before scaling
+const (
+	cardGlyphWidth  = 5
+	cardGlyphHeight = 7
+)
+
+// cardFont is a 5x7 bitmap font used to draw post card titles without an
+// external font dependency. Each row is a bitmask whose bit 4 is the
+// leftmost pixel. Letters are uppercase only; lowercase text is drawn in
+// capitals. The font only covers ASCII: cardText folds accented Latin
+// letters and typographic punctuation onto it, and titles in other scripts
+// cannot be drawn at all.
+var cardFont = map[rune][cardGlyphHeight]uint8{
+	' ':  {0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+	'!':  {0x04, 0x04, 0x04, 0x04, 0x04, 0x00, 0x04},
+	'"':  {0x0A, 0x0A, 0x00, 0x00, 0x00, 0x00, 0x00},
+	'#':  {0x0A, 0x1F, 0x0A, 0x0A, 0x0A, 0x1F, 0x0A},
+	'$':  {0x04, 0x0F, 0x14, 0x0E, 0x05, 0x1E, 0x04},
+	'%':  {0x18, 0x19, 0x02, 0x04, 0x08, 0x13, 0x03},
+	'&':  {0x0C, 0x12, 0x14, 0x08, 0x15, 0x12, 0x0D},
+	'\'': {0x04, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00},
+	'(':  {0x02, 0x04, 0x08, 0x08, 0x08, 0x04, 0x02},
+	')':  {0x08, 0x04, 0x02, 0x02, 0x02, 0x04, 0x08},
+	'*':  {0x00, 0x04, 0x15, 0x0E, 0x15, 0x04, 0x00},
+	'+':  {0x00, 0x04, 0x04, 0x1F, 0x04, 0x04, 0x00},
+	',':  {0x00, 0x00, 0x00, 0x00, 0x0C, 0x04, 0x08},
+	'-':  {0x00, 0x00, 0x00, 0x1F, 0x00, 0x00, 0x00},
+	'.':  {0x00, 0x00, 0x00, 0x00, 0x00, 0x0C, 0x0C},
+	'/':  {0x00, 0x01, 0x02, 0x04, 0x08, 0x10, 0x00},
+	'0':  {0x0E, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0E},
+	'1':  {0x04, 0x0C, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	'2':  {0x0E, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1F},
+	'3':  {0x1F, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0E},
+	'4':  {0x02, 0x06, 0x0A, 0x12, 0x1F, 0x02, 0x02},
+	'5':  {0x1F, 0x10, 0x1E, 0x01, 0x01, 0x11, 0x0E},
+	'6':  {0x06, 0x08, 0x10, 0x1E, 0x11, 0x11, 0x0E},
+	'7':  {0x1F, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08},
+	'8':  {0x0E, 0x11, 0x11, 0x0E, 0x11, 0x11, 0x0E},
+	'9':  {0x0E, 0x11, 0x11, 0x0F, 0x01, 0x02, 0x0C},
+	':':  {0x00, 0x0C, 0x0C, 0x00, 0x0C, 0x0C, 0x00},
+	';':  {0x00, 0x0C, 0x0C, 0x00, 0x0C, 0x04, 0x08},
+	'<':  {0x02, 0x04, 0x08, 0x10, 0x08, 0x04, 0x02},
+	'=':  {0x00, 0x00, 0x1F, 0x00, 0x1F, 0x00, 0x00},
+	'>':  {0x08, 0x04, 0x02, 0x01, 0x02, 0x04, 0x08},
+	'?':  {0x0E, 0x11, 0x01, 0x02, 0x04, 0x00, 0x04},
+	'@':  {0x0E, 0x11, 0x01, 0x0D, 0x15, 0x15, 0x0E},
+	'A':  {0x0E, 0x11, 0x11, 0x11, 0x1F, 0x11, 0x11},
+	'B':  {0x1E, 0x11, 0x11, 0x1E, 0x11, 0x11, 0x1E},
+	'C':  {0x0E, 0x11, 0x10, 0x10, 0x10, 0x11, 0x0E},
+	'D':  {0x1C, 0x12, 0x11, 0x11, 0x11, 0x12, 0x1C},
+	'E':  {0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x1F},
+	'F':  {0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x10},
+	'G':  {0x0E, 0x11, 0x10, 0x17, 0x11, 0x11, 0x0F},
+	'H':  {0x11, 0x11, 0x11, 0x1F, 0x11, 0x11, 0x11},
+	'I':  {0x0E, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	'J':  {0x07, 0x02, 0x02, 0x02, 0x02, 0x12, 0x0C},
+	'K':  {0x11, 0x12, 0x14, 0x18, 0x14, 0x12, 0x11},
+	'L':  {0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x1F},
+	'M':  {0x11, 0x1B, 0x15, 0x15, 0x11, 0x11, 0x11},
+	'N':  {0x11, 0x11, 0x19, 0x15, 0x13, 0x11, 0x11},
+	'O':  {0x0E, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0E},
+	'P':  {0x1E, 0x11, 0x11, 0x1E, 0x10, 0x10, 0x10},
+	'Q':  {0x0E, 0x11, 0x11, 0x11, 0x15, 0x12, 0x0D},
+	'R':  {0x1E, 0x11, 0x11, 0x1E, 0x14, 0x12, 0x11},
+	'S':  {0x0F, 0x10, 0x10, 0x0E, 0x01, 0x01, 0x1E},
+	'T':  {0x1F, 0x04, 0x04, 0x04, 0x04, 0x04, 0x04},
+	'U':  {0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0E},
+	'V':  {0x11, 0x11, 0x11, 0x11, 0x11, 0x0A, 0x04},
+	'W':  {0x11, 0x11, 0x11, 0x15, 0x15, 0x15, 0x0A},
+	'X':  {0x11, 0x11, 0x0A, 0x04, 0x0A, 0x11, 0x11},
+	'Y':  {0x11, 0x11, 0x11, 0x0A, 0x04, 0x04, 0x04},
+	'Z':  {0x1F, 0x01, 0x02, 0x04, 0x08, 0x10, 0x1F},
+	'[':  {0x0E, 0x08, 0x08, 0x08, 0x08, 0x08, 0x0E},
+	'\\': {0x00, 0x10, 0x08, 0x04, 0x02, 0x01, 0x00},
+	']':  {0x0E, 0x02, 0x02, 0x02, 0x02, 0x02, 0x0E},
+	'^':  {0x04, 0x0A, 0x11, 0x00, 0x00, 0x00, 0x00},
+	'_':  {0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x1F},
+	'`':  {0x08, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00},
+	'{':  {0x02, 0x04, 0x04, 0x08, 0x04, 0x04, 0x02},
+	'|':  {0x04, 0x04, 0x04, 0x04, 0x04, 0x04, 0x04},
+	'}':  {0x08, 0x04, 0x04, 0x02, 0x04, 0x04, 0x08},
+	'~':  {0x00, 0x00, 0x08, 0x15, 0x02, 0x00, 0x00},
+}
+
+// cardFolds maps characters cardFont has no glyph for to ASCII stand-ins.
+// Text is uppercased before folding, so only capitals are listed.
+var cardFolds = func() map[rune]string {
+	groups := map[string]string{
+		"ÀÁÂÃÄÅĀĂĄ":  "A",
+		"Æ":          "AE",
+		"ÇĆĈĊČ":      "C",
+		"ÐĎĐ":        "D",
+		"ÈÉÊËĒĔĖĘĚ":  "E",
+		"ĜĞĠĢ":       "G",
+		"ĤĦ":         "H",
+		"ÌÍÎÏĨĪĬĮİ":  "I",
+		"Ĳ":          "IJ",
+		"Ĵ":          "J",
+		"Ķ":          "K",
+		"ĹĻĽĿŁ":      "L",
+		"ÑŃŅŇ":       "N",
+		"ÒÓÔÕÖØŌŎŐ":  "O",
+		"Œ":          "OE",
+		"ŔŖŘ":        "R",
+		"ŚŜŞŠ":       "S",
+		"ß":          "SS",
+		"ŢŤŦ":        "T",
+		"Þ":          "TH",
+		"ÙÚÛÜŨŪŬŮŰŲ": "U",
+		"Ŵ":          "W",
+		"ÝŶŸ":        "Y",
+		"ŹŻŽ":        "Z",
+		"‘’‚′":       "'",
+		"“”„″«»":     "\"",
+		"‐‑‒–—―":     "-",
+		"…":          "...",
+		"•·":         "*",
+		"×":          "X",
+	}
+	folds := make(map[rune]string)
+	for runes, ascii := range groups {
+		for _, r := range runes {
+			folds[r] = ascii
+		}
+	}
+	return folds
+}()
+
+// cardText uppercases s and folds it onto the characters of cardFont. ok is
+// false when s has characters that cannot be drawn, such as Japanese text;
+// those are left in place and drawn as '?'.
+func cardText(s string) (text string, ok bool) {
+	ok = true
+	var b strings.Builder
+	for _, r := range strings.ToUpper(strings.TrimSpace(s)) {
+		if _, found := cardFont[r]; found {
+			b.WriteRune(r)
+		} else if ascii, found := cardFolds[r]; found {
+			b.WriteString(ascii)
+		} else if unicode.IsSpace(r) {
+			b.WriteRune(' ')
+		} else if unicode.Is(unicode.Mn, r) {
+			// Combining accents are dropped, leaving the base letter
+		} else {
+			b.WriteRune(r)
+			ok = false
+		}
+	}
+	return b.String(), ok
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+// ErrCollectionCycle is returned when a collection would become its own ancestor
+var ErrCollectionCycle = errors.New("collection cannot be nested inside itself or its sub-collections")
+
+// CollectionNode is a collection with its sub-collections
+type CollectionNode struct {
+	Collection models.Collection
+	Depth      int // 0 for top-level collections
+	Children   []CollectionNode
+}
+
+// TotalBookmarkCount returns the bookmark count of the collection and all
+// of its sub-collections
+func (n CollectionNode) TotalBookmarkCount() int {
+	total := n.Collection.BookmarkCount
+	for _, child := range n.Children {
+		total += child.TotalBookmarkCount()
+	}
+	return total
+}
+
+// FlattenCollectionTree returns the nodes of a tree depth-first, parents
+// before their children, for rendering as an indented list
+func FlattenCollectionTree(tree []CollectionNode) []CollectionNode {
+	var flat []CollectionNode
+	for _, node := range tree {
+		flat = append(flat, node)
+		flat = append(flat, FlattenCollectionTree(node.Children)...)
+	}
+	return flat
+}
+
+// ListCollectionTree retrieves collections arranged by parent. Siblings keep
+// the ListCollections order. With publicOnly, a public collection under a
+// private parent is listed at the top level.
+func (s *Service) ListCollectionTree(ctx context.Context, publicOnly bool) ([]CollectionNode, error) {
+	collections, err := s.ListCollections(ctx, publicOnly)
+	if err != nil {
+		return nil, err
+	}
+	return buildCollectionTree(collections), nil
+}
+
+// buildCollectionTree arranges collections by parent. Collections whose
+// parent isn't in the list, or that are caught in a parent cycle, become
+// top-level nodes.
+func buildCollectionTree(collections []models.Collection) []CollectionNode {
+	known := make(map[int64]bool, len(collections))
+	for _, c := range collections {
+		known[c.ID] = true
+	}
+
+	children := make(map[int64][]models.Collection)
+	var roots []models.Collection
+	for _, c := range collections {
+		if c.ParentID.Valid && c.ParentID.Int64 != c.ID && known[c.ParentID.Int64] {
+			children[c.ParentID.Int64] = append(children[c.ParentID.Int64], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+
+	visited := make(map[int64]bool, len(collections))
+	var build func(c models.Collection, depth int) CollectionNode
+	build = func(c models.Collection, depth int) CollectionNode {
+		visited[c.ID] = true
+		node := CollectionNode{Collection: c, Depth: depth}
+		for _, child := range children[c.ID] {
+			if !visited[child.ID] {
+				node.Children = append(node.Children, build(child, depth+1))
+			}
+		}
+		return node
+	}
+
+	tree := make([]CollectionNode, 0, len(roots))
+	for _, c := range roots {
+		tree = append(tree, build(c, 0))
+	}
+	for _, c := range collections {
+		if !visited[c.ID] {
+			tree = append(tree, build(c, 0))
+		}
+	}
+	return tree
+}
+
+// collectionScopeIDs returns rootID and the IDs of all its descendants
+func collectionScopeIDs(collections []models.Collection, rootID int64) []int64 {
+	children := make(map[int64][]int64)
+	for _, c := range collections {
+		if c.ParentID.Valid {
+			children[c.ParentID.Int64] = append(children[c.ParentID.Int64], c.ID)
+		}
+	}
+
+	ids := []int64{rootID}
+	seen := map[int64]bool{rootID: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
+
+// descendantCollectionIDs returns the JSON array of IDs that opts.CollectionID
+// covers with IncludeDescendants set, for the *InCollections queries. Public
+// listings only descend into public collections.
+func (s *Service) descendantCollectionIDs(ctx context.Context, opts BookmarkListOptions) (string, error) {
+	collections, err := s.ListCollections(ctx, opts.PublicOnly)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(collectionScopeIDs(collections, *opts.CollectionID))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// checkCollectionParent returns ErrCollectionCycle if making parentID the
+// parent of collection id would create a cycle
+func (s *Service) checkCollectionParent(ctx context.Context, id int64, parentID *int64) error {
+	if parentID == nil {
+		return nil
+	}
+
+	collections, err := s.ListCollections(ctx, false)
+	if err != nil {
+		return err
+	}
+	for _, descendant := range collectionScopeIDs(collections, id) {
+		if descendant == *parentID {
+			return ErrCollectionCycle
+		}
+	}
+	return nil
+}
+
+// inParentCycle reports whether following parent links from collection id
+// leads back to it, given each collection's parent by ID
+func inParentCycle(parents map[int64]int64, id int64) bool {
+	current, ok := parents[id]
+	for steps := 0; ok && steps < len(parents); steps++ {
+		if current == id {
+			return true
+		}
+		current, ok = parents[current]
+	}
+	return false
+}
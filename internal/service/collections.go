@@ -15,6 +15,7 @@ func (s *Service) CreateCollection(ctx context.Context, input models.CreateColle
 		Slug:        input.Slug,
 		Description: strPtr(input.Description),
 		Color:       strPtr(input.Color),
+		CoverImage:  strPtr(input.CoverImage),
 		ParentID:    input.ParentID,
 		SortOrder:   nil,
 		IsPublic:    boolToInt64Ptr(input.IsPublic),
@@ -31,15 +32,17 @@ func (s *Service) CreateCollection(ctx context.Context, input models.CreateColle
 
 // UpdateCollection updates an existing collection
 func (s *Service) UpdateCollection(ctx context.Context, id int64, input models.UpdateCollectionInput) (*models.Collection, error) {
-	sortOrder := int64(input.SortOrder)
+	if err := s.checkCollectionParent(ctx, id, input.ParentID); err != nil {
+		return nil, err
+	}
 
 	err := s.queries.UpdateCollection(ctx, db.UpdateCollectionParams{
 		Name:        input.Name,
 		Slug:        input.Slug,
 		Description: strPtr(input.Description),
 		Color:       strPtr(input.Color),
+		CoverImage:  strPtr(input.CoverImage),
 		ParentID:    input.ParentID,
-		SortOrder:   &sortOrder,
 		IsPublic:    boolToInt64Ptr(input.IsPublic),
 		ID:          id,
 	})
@@ -53,6 +56,36 @@ func (s *Service) UpdateCollection(ctx context.Context, id int64, input models.U
 	return s.GetCollectionByID(ctx, id)
 }
 
+// MoveCollection moves a collection to a new position in the collection order.
+// afterCollectionID: collection ID to insert after (nil = insert at the beginning)
+func (s *Service) MoveCollection(ctx context.Context, collectionID int64, afterCollectionID *int64) error {
+	rows, err := s.queries.GetCollectionSortOrders(ctx)
+	if err != nil {
+		return err
+	}
+	sortOrders := make([]sortItem, 0, len(rows))
+	for _, r := range rows {
+		sortOrders = append(sortOrders, sortItem{ID: r.ID, SortOrder: r.SortOrder})
+	}
+	sortOrders = withoutSortItems(sortOrders, collectionID)
+
+	newSortOrder, rebalanced := sortOrderAfter(sortOrders, afterCollectionID)
+	for _, item := range rebalanced {
+		order := item.SortOrder
+		if err := s.queries.UpdateCollectionPosition(ctx, db.UpdateCollectionPositionParams{
+			SortOrder: &order,
+			ID:        item.ID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return s.queries.UpdateCollectionPosition(ctx, db.UpdateCollectionPositionParams{
+		SortOrder: &newSortOrder,
+		ID:        collectionID,
+	})
+}
+
 // DeleteCollection deletes a collection
 func (s *Service) DeleteCollection(ctx context.Context, id int64) error {
 	// Get collection name for activity log before deleting
@@ -80,12 +113,7 @@ func (s *Service) GetCollectionByID(ctx context.Context, id int64) (*models.Coll
 		return nil, err
 	}
 
-	count, err := s.queries.GetCollectionBookmarkCount(ctx, &id)
-	if err != nil {
-		return nil, err
-	}
-
-	return dbCollectionToModel(collection, int(count)), nil
+	return dbCollectionRowToModel(db.ListAllCollectionsWithCountsRow(collection)), nil
 }
 
 // GetCollectionBySlug retrieves a collection by slug
@@ -95,12 +123,7 @@ func (s *Service) GetCollectionBySlug(ctx context.Context, slug string) (*models
 		return nil, err
 	}
 
-	count, err := s.queries.GetCollectionBookmarkCount(ctx, &collection.ID)
-	if err != nil {
-		return nil, err
-	}
-
-	return dbCollectionToModel(collection, int(count)), nil
+	return dbCollectionRowToModel(db.ListAllCollectionsWithCountsRow(collection)), nil
 }
 
 // ListCollections retrieves all collections with bookmark counts
@@ -131,23 +154,6 @@ func (s *Service) ListCollections(ctx context.Context, publicOnly bool) ([]model
 	return result, nil
 }
 
-// Helper function to convert sqlc Collection to domain model
-func dbCollectionToModel(c db.Collection, bookmarkCount int) *models.Collection {
-	return &models.Collection{
-		ID:            c.ID,
-		Name:          c.Name,
-		Slug:          c.Slug,
-		Description:   toNullString(c.Description),
-		Color:         toNullString(c.Color),
-		ParentID:      toNullInt64(c.ParentID),
-		SortOrder:     int(derefInt64(c.SortOrder)),
-		IsPublic:      derefInt64(c.IsPublic) == 1,
-		CreatedAt:     derefTime(c.CreatedAt),
-		UpdatedAt:     derefTime(c.UpdatedAt),
-		BookmarkCount: bookmarkCount,
-	}
-}
-
 // ============================================
 // INLINE EDITING METHODS
 // ============================================
@@ -216,6 +222,7 @@ type RecentBookmark struct {
 // CollectionWithRecent represents a collection with its recent bookmarks for board view
 type CollectionWithRecent struct {
 	Collection      models.Collection
+	Depth           int // Nesting level in the collection tree
 	RecentBookmarks []RecentBookmark
 }
 
@@ -240,16 +247,25 @@ func (s *Service) GetBoardViewData(ctx context.Context, recentLimit int) (*Board
 		return nil, err
 	}
 
-	// Build collections with recent bookmarks
+	// Fetch recent bookmarks for every collection in one query
+	recentByCollection, err := s.getRecentBookmarksForAllCollections(ctx, recentLimit)
+	if err != nil {
+		// Continue with empty recent on error
+		logger.Error(ctx, "failed to load recent bookmarks for board view", "error", err)
+		recentByCollection = map[int64][]RecentBookmark{}
+	}
+
+	// Build collections with recent bookmarks, sub-collections right after their parent
 	collectionsWithRecent := make([]CollectionWithRecent, 0, len(collections))
-	for _, c := range collections {
-		recent, err := s.GetRecentBookmarksByCollectionID(ctx, c.ID, recentLimit)
-		if err != nil {
-			// Continue with empty recent on error
+	for _, node := range FlattenCollectionTree(buildCollectionTree(collections)) {
+		c := node.Collection
+		recent := recentByCollection[c.ID]
+		if recent == nil {
 			recent = []RecentBookmark{}
 		}
 		collectionsWithRecent = append(collectionsWithRecent, CollectionWithRecent{
 			Collection:      c,
+			Depth:           node.Depth,
 			RecentBookmarks: recent,
 		})
 	}
@@ -306,6 +322,39 @@ func (s *Service) GetRecentBookmarksByCollectionID(ctx context.Context, collecti
 	return result, nil
 }
 
+// getRecentBookmarksForAllCollections returns up to limit recent bookmarks
+// per collection, keyed by collection ID
+func (s *Service) getRecentBookmarksForAllCollections(ctx context.Context, limit int) (map[int64][]RecentBookmark, error) {
+	rows, err := s.queries.GetRecentBookmarksForAllCollections(ctx, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int64][]RecentBookmark)
+	for _, r := range rows {
+		if r.CollectionID == nil {
+			continue
+		}
+		bookmark := RecentBookmark{
+			ID:    r.ID,
+			Title: r.Title,
+			URL:   r.Url,
+		}
+		if r.Domain != nil {
+			bookmark.Domain = *r.Domain
+		}
+		if r.IsFavorite != nil {
+			bookmark.IsFavorite = *r.IsFavorite == 1
+		}
+		if r.IsPublic != nil {
+			bookmark.IsPublic = *r.IsPublic == 1
+		}
+		result[*r.CollectionID] = append(result[*r.CollectionID], bookmark)
+	}
+
+	return result, nil
+}
+
 // GetRecentUnsortedBookmarks returns recent bookmarks without a collection
 func (s *Service) GetRecentUnsortedBookmarks(ctx context.Context, limit int) ([]RecentBookmark, error) {
 	rows, err := s.queries.ListRecentUnsortedBookmarks(ctx, int64(limit))
@@ -343,6 +392,7 @@ func dbCollectionRowToModel(c db.ListAllCollectionsWithCountsRow) *models.Collec
 		Slug:          c.Slug,
 		Description:   toNullString(c.Description),
 		Color:         toNullString(c.Color),
+		CoverImage:    toNullString(c.CoverImage),
 		ParentID:      toNullInt64(c.ParentID),
 		SortOrder:     int(derefInt64(c.SortOrder)),
 		IsPublic:      derefInt64(c.IsPublic) == 1,
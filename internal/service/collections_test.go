@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+// countingDB counts the statements run through it
+type countingDB struct {
+	db.DBTX
+	queries int
+}
+
+func (c *countingDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	c.queries++
+	return c.DBTX.ExecContext(ctx, query, args...)
+}
+
+func (c *countingDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	c.queries++
+	return c.DBTX.QueryContext(ctx, query, args...)
+}
+
+func (c *countingDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	c.queries++
+	return c.DBTX.QueryRowContext(ctx, query, args...)
+}
+
+func TestGetBoardViewData_ConstantQueries(t *testing.T) {
+	ctx := context.Background()
+
+	boardQueries := func(collections int) int {
+		t.Helper()
+		svc := newTestService(t)
+		for i := 0; i < collections; i++ {
+			c, err := svc.CreateCollection(ctx, models.CreateCollectionInput{
+				Name: fmt.Sprintf("Collection %d", i),
+				Slug: fmt.Sprintf("collection-%d", i),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for j := 0; j < 3; j++ {
+				if _, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+					URL:          fmt.Sprintf("https://example.com/%d/%d", i, j),
+					Title:        fmt.Sprintf("Bookmark %d-%d", i, j),
+					CollectionID: &c.ID,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		counter := &countingDB{DBTX: svc.db}
+		svc.queries = db.New(counter)
+
+		data, err := svc.GetBoardViewData(ctx, 2)
+		if err != nil {
+			t.Fatalf("GetBoardViewData() error = %v", err)
+		}
+		if len(data.Collections) != collections {
+			t.Fatalf("got %d collections, want %d", len(data.Collections), collections)
+		}
+		for _, c := range data.Collections {
+			if len(c.RecentBookmarks) != 2 {
+				t.Errorf("%s has %d recent bookmarks, want 2", c.Collection.Name, len(c.RecentBookmarks))
+			}
+			if c.Collection.BookmarkCount != 3 {
+				t.Errorf("%s count = %d, want 3", c.Collection.Name, c.Collection.BookmarkCount)
+			}
+		}
+		return counter.queries
+	}
+
+	one, many := boardQueries(1), boardQueries(10)
+	if one != many {
+		t.Errorf("board view ran %d queries for 1 collection and %d for 10, want the same", one, many)
+	}
+}
+
+func TestMoveCollection(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	ids := map[string]int64{}
+	for _, name := range []string{"a", "b", "c"} {
+		c, err := svc.CreateCollection(ctx, models.CreateCollectionInput{Name: name, Slug: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[name] = c.ID
+	}
+
+	order := func() string {
+		t.Helper()
+		collections, err := svc.ListCollections(ctx, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names string
+		for _, c := range collections {
+			names += c.Name
+		}
+		return names
+	}
+
+	afterA := ids["a"]
+	steps := []struct {
+		name    string
+		afterID *int64
+		want    string
+	}{
+		{"c", nil, "cab"},
+		{"b", &afterA, "cab"},
+		{"c", &afterA, "acb"},
+		{"a", nil, "acb"},
+	}
+	for _, step := range steps {
+		if err := svc.MoveCollection(ctx, ids[step.name], step.afterID); err != nil {
+			t.Fatalf("MoveCollection(%s) error = %v", step.name, err)
+		}
+		if got := order(); got != step.want {
+			t.Errorf("after moving %s: order = %q, want %q", step.name, got, step.want)
+		}
+	}
+
+	// Editing a collection keeps its position
+	if _, err := svc.UpdateCollection(ctx, ids["b"], models.UpdateCollectionInput{Name: "b", Slug: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := order(); got != "acb" {
+		t.Errorf("order after edit = %q, want %q", got, "acb")
+	}
+}
+
+func TestCollectionTree(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	create := func(name string, parentID *int64, public bool) int64 {
+		t.Helper()
+		c, err := svc.CreateCollection(ctx, models.CreateCollectionInput{Name: name, Slug: name, ParentID: parentID, IsPublic: public})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c.ID
+	}
+	addBookmark := func(path string, collectionID int64) {
+		t.Helper()
+		if _, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+			URL:          "https://example.com/" + path,
+			Title:        path,
+			CollectionID: &collectionID,
+			IsPublic:     true,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dev := create("dev", nil, true)
+	golang := create("go", &dev, true)
+	generics := create("generics", &golang, true)
+	secret := create("secret", &dev, false)
+	create("reading", nil, true)
+	addBookmark("dev", dev)
+	addBookmark("go", golang)
+	addBookmark("generics", generics)
+	addBookmark("secret", secret)
+
+	tree, err := svc.ListCollectionTree(ctx, false)
+	if err != nil {
+		t.Fatalf("ListCollectionTree() error = %v", err)
+	}
+	var got string
+	for _, node := range FlattenCollectionTree(tree) {
+		got += fmt.Sprintf("%d:%s ", node.Depth, node.Collection.Name)
+	}
+	if want := "0:dev 1:go 2:generics 1:secret 0:reading "; got != want {
+		t.Errorf("tree = %q, want %q", got, want)
+	}
+	if n := tree[0].TotalBookmarkCount(); n != 4 {
+		t.Errorf("dev TotalBookmarkCount() = %d, want 4", n)
+	}
+
+	tests := []struct {
+		name string
+		opts BookmarkListOptions
+		want int
+	}{
+		{"direct only", BookmarkListOptions{CollectionID: &dev}, 1},
+		{"with descendants", BookmarkListOptions{CollectionID: &dev, IncludeDescendants: true}, 4},
+		{"public skips private sub-collections", BookmarkListOptions{CollectionID: &dev, IncludeDescendants: true, PublicOnly: true}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Limit = 10
+			bookmarks, err := svc.ListBookmarks(ctx, tt.opts)
+			if err != nil {
+				t.Fatalf("ListBookmarks() error = %v", err)
+			}
+			count, err := svc.CountBookmarks(ctx, tt.opts)
+			if err != nil {
+				t.Fatalf("CountBookmarks() error = %v", err)
+			}
+			if len(bookmarks) != tt.want || count != tt.want {
+				t.Errorf("got %d bookmarks, count %d; want %d", len(bookmarks), count, tt.want)
+			}
+			if tt.opts.PublicOnly {
+				page, err := svc.ListBookmarksCursor(ctx, tt.opts, "")
+				if err != nil {
+					t.Fatalf("ListBookmarksCursor() error = %v", err)
+				}
+				if len(page.Bookmarks) != tt.want {
+					t.Errorf("cursor page has %d bookmarks, want %d", len(page.Bookmarks), tt.want)
+				}
+			}
+		})
+	}
+
+	for _, parent := range []int64{dev, generics} {
+		_, err := svc.UpdateCollection(ctx, dev, models.UpdateCollectionInput{Name: "dev", Slug: "dev", ParentID: &parent})
+		if !errors.Is(err, ErrCollectionCycle) {
+			t.Errorf("UpdateCollection(parent %d) error = %v, want ErrCollectionCycle", parent, err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/EC-9624/0xec.dev/internal/renderer"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// excerptLength is the target length, in characters, of a derived excerpt
+const excerptLength = 160
+
+// deriveExcerpt builds an excerpt from post content for posts saved without
+// one. Content is either Markdown or legacy Editor.js JSON. Headings are
+// skipped in favour of body text unless the post has nothing else.
+func deriveExcerpt(content string) string {
+	if renderer.IsEditorJS(content) {
+		md, err := renderer.EditorJSToMarkdown(content)
+		if err != nil {
+			return ""
+		}
+		content = md
+	}
+
+	body, headings := markdownText(content)
+	parts := body
+	if len(parts) == 0 {
+		parts = headings
+	}
+	return truncateExcerpt(strings.Join(parts, " "), excerptLength)
+}
+
+// markdownText collects plain text from Markdown, split into body text and
+// headings. Code blocks, raw HTML and images are left out.
+func markdownText(content string) (body, headings []string) {
+	source := []byte(content)
+	doc := goldmark.New().Parser().Parse(text.NewReader(source))
+
+	var current strings.Builder
+	inHeading := false
+	flush := func() {
+		if t := collapseSpace(current.String()); t != "" {
+			if inHeading {
+				headings = append(headings, t)
+			} else {
+				body = append(body, t)
+			}
+		}
+		current.Reset()
+	}
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch n := n.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock, *ast.HTMLBlock, *ast.RawHTML, *ast.Image:
+			return ast.WalkSkipChildren, nil
+		case *ast.Heading:
+			flush()
+			inHeading = entering
+		case *ast.Paragraph, *ast.TextBlock:
+			if !entering {
+				flush()
+			}
+		case *ast.Text:
+			if entering {
+				current.Write(n.Segment.Value(source))
+				if n.SoftLineBreak() || n.HardLineBreak() {
+					current.WriteByte(' ')
+				}
+			}
+		case *ast.String:
+			if entering {
+				current.Write(n.Value)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	flush()
+
+	return body, headings
+}
+
+// collapseSpace trims s and collapses runs of whitespace to single spaces
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncateExcerpt cuts s to at most maxLen characters at a word boundary
+// and appends an ellipsis when anything was cut
+func truncateExcerpt(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+
+	cut := runes[:maxLen]
+	if !unicode.IsSpace(runes[maxLen]) {
+		if i := strings.LastIndexFunc(string(cut), unicode.IsSpace); i > 0 {
+			cut = []rune(string(cut)[:i])
+		}
+	}
+	return strings.TrimRightFunc(string(cut), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "..."
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestDeriveExcerpt(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "editor.js header first",
+			content: `{"time":1700000000000,"blocks":[{"type":"header","data":{"text":"Introduction","level":2}},{"type":"paragraph","data":{"text":"Go is <b>simple</b>&nbsp;and fast."}}],"version":"2.28.0"}`,
+			want:    "Go is simple and fast.",
+		},
+		{
+			name:    "editor.js paragraph first",
+			content: `{"blocks":[{"type":"paragraph","data":{"text":"First words."}},{"type":"header","data":{"text":"Section","level":2}},{"type":"list","data":{"style":"unordered","items":["one","<i>two</i>"]}}]}`,
+			want:    "First words. one two",
+		},
+		{
+			name:    "editor.js headers only",
+			content: `{"blocks":[{"type":"header","data":{"text":"Just a title","level":1}}]}`,
+			want:    "Just a title",
+		},
+		{
+			name:    "markdown",
+			content: "# Title\n\nSome **bold** and [linked](https://example.com) text\nover two lines.\n\n```go\nfmt.Println(\"skip\")\n```\n\n![alt](/img.png) After image.",
+			want:    "Some bold and linked text over two lines. After image.",
+		},
+		{
+			name:    "json that is not editor.js",
+			content: `{"not": "blocks"}`,
+			want:    `{"not": "blocks"}`,
+		},
+		{
+			name:    "empty",
+			content: "",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deriveExcerpt(tt.content); got != tt.want {
+				t.Errorf("deriveExcerpt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeriveExcerpt_Truncates(t *testing.T) {
+	content := strings.Repeat("lorem ipsum ", 40)
+
+	got := deriveExcerpt(content)
+	if !strings.HasSuffix(got, "...") {
+		t.Fatalf("expected ellipsis, got %q", got)
+	}
+	if n := utf8.RuneCountInString(strings.TrimSuffix(got, "...")); n > excerptLength {
+		t.Errorf("excerpt is %d characters, want at most %d", n, excerptLength)
+	}
+	if strings.HasSuffix(got, " ...") || strings.HasSuffix(got, "lore...") {
+		t.Errorf("expected cut at a word boundary, got %q", got)
+	}
+}
+
+func TestCreatePost_DerivesExcerpt(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	post, err := svc.CreatePost(ctx, models.CreatePostInput{
+		Title:   "Derived",
+		Slug:    "derived",
+		Content: "Body text.",
+		IsDraft: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := post.GetExcerpt(); got != "Body text." {
+		t.Errorf("excerpt = %q, want %q", got, "Body text.")
+	}
+
+	post, err = svc.UpdatePost(ctx, post.ID, models.UpdatePostInput{
+		Title:   "Derived",
+		Slug:    "derived",
+		Content: "Body text.",
+		Excerpt: "Hand written",
+		IsDraft: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := post.GetExcerpt(); got != "Hand written" {
+		t.Errorf("excerpt = %q, want explicit excerpt kept", got)
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/renderer"
+)
+
+// ExportPostMarkdown renders a post as a Markdown file with YAML front
+// matter. Editor.js content is converted to Markdown; Markdown content is
+// written unchanged.
+func (s *Service) ExportPostMarkdown(ctx context.Context, id int64) (string, error) {
+	post, err := s.GetPostByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return postMarkdown(post)
+}
+
+// ExportPostsZip writes a zip archive to w holding every post, drafts
+// included, as <slug>.md
+func (s *Service) ExportPostsZip(ctx context.Context, w io.Writer) error {
+	// A negative limit means no limit in SQLite
+	posts, err := s.ListPosts(ctx, false, -1, 0)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, post := range posts {
+		md, err := postMarkdown(&post)
+		if err != nil {
+			return fmt.Errorf("export post %q: %w", post.Slug, err)
+		}
+
+		f, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     post.Slug + ".md",
+			Method:   zip.Deflate,
+			Modified: post.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, md); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// postMarkdown builds the exported file for a single post
+func postMarkdown(post *models.Post) (string, error) {
+	body := post.Content
+	if renderer.IsEditorJS(body) {
+		md, err := renderer.EditorJSToMarkdown(body)
+		if err != nil {
+			return "", err
+		}
+		body = md
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	b.WriteString("title: " + yamlString(post.Title) + "\n")
+	b.WriteString("slug: " + yamlString(post.Slug) + "\n")
+
+	tags := make([]string, 0, len(post.Tags))
+	for _, tag := range post.Tags {
+		tags = append(tags, yamlString(tag.Name))
+	}
+	b.WriteString("tags: [" + strings.Join(tags, ", ") + "]\n")
+
+	if post.PublishedAt.Valid {
+		b.WriteString("published_at: " + post.PublishedAt.Time.UTC().Format(time.RFC3339) + "\n")
+	}
+	fmt.Fprintf(&b, "draft: %t\n", post.IsDraft)
+	if excerpt := post.GetExcerpt(); excerpt != "" {
+		b.WriteString("excerpt: " + yamlString(excerpt) + "\n")
+	}
+	if cover := post.GetCoverImage(); cover != "" {
+		b.WriteString("cover_image: " + yamlString(cover) + "\n")
+	}
+	b.WriteString("---\n\n")
+
+	b.WriteString(strings.TrimRight(body, "\n") + "\n")
+	return b.String(), nil
+}
+
+// yamlString quotes s as a YAML scalar. JSON strings are valid YAML
+// double-quoted scalars, which sidesteps YAML's many special cases.
+func yamlString(s string) string {
+	out, _ := json.Marshal(s)
+	return string(out)
+}
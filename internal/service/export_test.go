@@ -0,0 +1,129 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestExportPostMarkdown(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	tag, err := svc.CreateTag(ctx, models.CreateTagInput{Name: "Go", Slug: "go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := svc.CreatePost(ctx, models.CreatePostInput{
+		Title:   `Quotes "and" colons: here`,
+		Slug:    "exported",
+		Content: `{"blocks":[{"type":"header","data":{"text":"Intro","level":2}},{"type":"paragraph","data":{"text":"Hello <a href=\"https://example.com\">world</a>"}}]}`,
+		Excerpt: "Short",
+		TagIDs:  []int64{tag.ID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := svc.ExportPostMarkdown(ctx, post.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantPrefix := "---\n" +
+		`title: "Quotes \"and\" colons: here"` + "\n" +
+		`slug: "exported"` + "\n" +
+		`tags: ["Go"]` + "\n" +
+		"published_at: "
+	if !strings.HasPrefix(got, wantPrefix) {
+		t.Errorf("front matter = %q, want prefix %q", got, wantPrefix)
+	}
+	wantTail := "draft: false\n" +
+		`excerpt: "Short"` + "\n" +
+		"---\n\n" +
+		"## Intro\n\nHello [world](https://example.com)\n"
+	if !strings.HasSuffix(got, wantTail) {
+		t.Errorf("export = %q, want suffix %q", got, wantTail)
+	}
+}
+
+func TestExportPostMarkdown_MarkdownDraft(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	post, err := svc.CreatePost(ctx, models.CreatePostInput{
+		Title:   "Draft",
+		Slug:    "draft",
+		Content: "Plain *markdown*\n\n```go\nx := 1\n```\n",
+		IsDraft: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := svc.ExportPostMarkdown(ctx, post.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "published_at:") {
+		t.Errorf("draft export should omit published_at:\n%s", got)
+	}
+	if !strings.Contains(got, "tags: []\ndraft: true\n") {
+		t.Errorf("export missing empty tags and draft flag:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "---\n\nPlain *markdown*\n\n```go\nx := 1\n```\n") {
+		t.Errorf("markdown body not kept verbatim:\n%s", got)
+	}
+}
+
+func TestExportPostsZip(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	for _, input := range []models.CreatePostInput{
+		{Title: "One", Slug: "one", Content: "First"},
+		{Title: "Two", Slug: "two", Content: "Second", IsDraft: true},
+	} {
+		if _, err := svc.CreatePost(ctx, input); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := svc.ExportPostsZip(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(data)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("zip has %d files, want 2: %v", len(files), files)
+	}
+	if !strings.HasSuffix(files["one.md"], "\nFirst\n") {
+		t.Errorf("one.md = %q", files["one.md"])
+	}
+	if !strings.Contains(files["two.md"], "draft: true\n") {
+		t.Errorf("two.md = %q, want draft included", files["two.md"])
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+// defaultFeaturedPostsMax is how many posts may be featured at once unless
+// SetFeaturedPostsMax overrides it
+const defaultFeaturedPostsMax = 3
+
+var (
+	ErrFeaturedDraft = errors.New("only published posts can be featured")
+	ErrFeaturedLimit = errors.New("too many featured posts; unfeature one first")
+)
+
+// SetFeaturedPostsMax caps how many posts can be featured at once. Zero or
+// negative removes the cap.
+func (s *Service) SetFeaturedPostsMax(n int) {
+	s.featuredMax = n
+}
+
+// SetPostFeatured features or unfeatures a post. Drafts can't be featured,
+// and featuring fails with ErrFeaturedLimit once the cap is reached.
+func (s *Service) SetPostFeatured(ctx context.Context, id int64, featured bool) error {
+	post, err := s.GetPostByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if featured && !post.IsFeatured {
+		if err := s.checkCanFeature(ctx, post.IsDraft); err != nil {
+			return err
+		}
+	}
+
+	if err := s.queries.UpdatePostFeatured(ctx, db.UpdatePostFeaturedParams{IsFeatured: *boolToInt64Ptr(featured), ID: id}); err != nil {
+		return err
+	}
+
+	s.LogActivity(ctx, ActionPostUpdated, EntityPost, id, post.Title, nil)
+	return nil
+}
+
+// ListFeaturedPosts returns up to limit featured posts, newest first; a
+// non-positive limit returns them all. Drafts and trashed posts are never
+// included.
+func (s *Service) ListFeaturedPosts(ctx context.Context, limit int) ([]models.Post, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite treats a negative LIMIT as no limit
+	}
+	posts, err := s.queries.ListFeaturedPosts(ctx, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Post, 0, len(posts))
+	for _, p := range posts {
+		tags, err := s.queries.GetPostTags(ctx, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *dbPostToModel(p, tags))
+	}
+
+	return result, nil
+}
+
+// checkCanFeature reports whether a post that isn't featured yet may
+// become featured
+func (s *Service) checkCanFeature(ctx context.Context, isDraft bool) error {
+	if isDraft {
+		return ErrFeaturedDraft
+	}
+	if s.featuredMax <= 0 {
+		return nil
+	}
+	count, err := s.queries.CountFeaturedPosts(ctx)
+	if err != nil {
+		return err
+	}
+	if count >= int64(s.featuredMax) {
+		return ErrFeaturedLimit
+	}
+	return nil
+}
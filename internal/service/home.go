@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+// HomeConfig controls the sections of the home page. A count of zero
+// hides its section.
+type HomeConfig struct {
+	Intro           string
+	ShowFeatured    bool
+	RecentPosts     int
+	RecentBookmarks int
+}
+
+// DefaultHomeConfig is the home page layout until SetHomeConfig overrides
+// it
+var DefaultHomeConfig = HomeConfig{
+	Intro:           "A personal space for writing and collecting interesting things from the web.",
+	ShowFeatured:    true,
+	RecentPosts:     5,
+	RecentBookmarks: 6,
+}
+
+// HomeData is everything the home page shows
+type HomeData struct {
+	Intro           string
+	FeaturedPosts   []models.Post
+	RecentPosts     []models.Post
+	RecentBookmarks []models.Bookmark
+}
+
+// SetHomeConfig sets which sections the home page shows and how much of
+// each
+func (s *Service) SetHomeConfig(cfg HomeConfig) {
+	s.home = cfg
+}
+
+// GetHomeData assembles the home page sections per the HomeConfig.
+// Featured posts are capped by the featured posts limit.
+func (s *Service) GetHomeData(ctx context.Context) (*HomeData, error) {
+	data := &HomeData{Intro: s.home.Intro}
+
+	var err error
+	if s.home.ShowFeatured {
+		data.FeaturedPosts, err = s.ListFeaturedPosts(ctx, s.featuredMax)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.home.RecentPosts > 0 {
+		data.RecentPosts, err = s.ListPosts(ctx, true, s.home.RecentPosts, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.home.RecentBookmarks > 0 {
+		data.RecentBookmarks, err = s.ListBookmarks(ctx, BookmarkListOptions{
+			PublicOnly: true,
+			Limit:      s.home.RecentBookmarks,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
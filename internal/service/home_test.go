@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+)
+
+func TestGetHomeData(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	for i := range 4 {
+		post := createTestPost(t, s, fmt.Sprintf("post-%d", i))
+		if i == 0 {
+			if err := s.SetPostFeatured(ctx, post.ID, true); err != nil {
+				t.Fatal(err)
+			}
+		}
+		url := fmt.Sprintf("https://example.com/%d", i)
+		if _, err := s.queries.CreateBookmark(ctx, db.CreateBookmarkParams{Url: url, Title: url, IsPublic: boolToInt64Ptr(true)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The defaults keep the original layout
+	data, err := s.GetHomeData(ctx)
+	if err != nil {
+		t.Fatalf("GetHomeData() error = %v", err)
+	}
+	if data.Intro != DefaultHomeConfig.Intro || len(data.FeaturedPosts) != 1 || len(data.RecentPosts) != 4 || len(data.RecentBookmarks) != 4 {
+		t.Errorf("default home = intro %q, %d featured, %d posts, %d bookmarks; want intro, 1, 4, 4",
+			data.Intro, len(data.FeaturedPosts), len(data.RecentPosts), len(data.RecentBookmarks))
+	}
+
+	s.SetHomeConfig(HomeConfig{Intro: "Hi", ShowFeatured: false, RecentPosts: 2, RecentBookmarks: 3})
+	data, err = s.GetHomeData(ctx)
+	if err != nil {
+		t.Fatalf("GetHomeData() error = %v", err)
+	}
+	if data.Intro != "Hi" || len(data.FeaturedPosts) != 0 || len(data.RecentPosts) != 2 || len(data.RecentBookmarks) != 3 {
+		t.Errorf("configured home = intro %q, %d featured, %d posts, %d bookmarks; want Hi, 0, 2, 3",
+			data.Intro, len(data.FeaturedPosts), len(data.RecentPosts), len(data.RecentBookmarks))
+	}
+
+	s.SetHomeConfig(HomeConfig{ShowFeatured: true})
+	data, err = s.GetHomeData(ctx)
+	if err != nil {
+		t.Fatalf("GetHomeData() error = %v", err)
+	}
+	if len(data.RecentPosts) != 0 || len(data.RecentBookmarks) != 0 {
+		t.Errorf("zero counts listed %d posts and %d bookmarks, want none", len(data.RecentPosts), len(data.RecentBookmarks))
+	}
+}
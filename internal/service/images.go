@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+// CreateImage stores an image blob. sourceURL records where it was
+// downloaded from and may be empty for uploads.
+func (s *Service) CreateImage(ctx context.Context, mimeType string, data []byte, sourceURL string) (*models.Image, error) {
+	image, err := s.queries.CreateImage(ctx, db.CreateImageParams{
+		MimeType:  mimeType,
+		Data:      data,
+		Size:      int64(len(data)),
+		SourceUrl: strPtr(sourceURL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbImageToModel(image), nil
+}
+
+// DeleteImage deletes a stored image
+func (s *Service) DeleteImage(ctx context.Context, id int64) error {
+	return s.queries.DeleteImage(ctx, id)
+}
+
+// deleteUnusedImagePath deletes the stored image behind an /images/{id}
+// cover path once nothing uses it as a cover any more. Duplicated posts
+// share their cover, so a replaced one may still be in use elsewhere.
+func (s *Service) deleteUnusedImagePath(ctx context.Context, path string) {
+	id, ok := models.ImageIDFromPath(path)
+	if !ok {
+		return
+	}
+	refs, err := s.queries.CountImagePathReferences(ctx, &path)
+	if err != nil || refs > 0 {
+		return
+	}
+	s.queries.DeleteImage(ctx, id)
+}
+
+// GetImage retrieves a stored image by ID
+func (s *Service) GetImage(ctx context.Context, id int64) (*models.Image, error) {
+	image, err := s.queries.GetImageByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dbImageToModel(image), nil
+}
+
+// maxImageDownloadBytes caps how much of a remote image is downloaded
+const maxImageDownloadBytes = 10 * 1024 * 1024
+
+// imageDownloadTimeout bounds a whole cover image download
+const imageDownloadTimeout = 15 * time.Second
+
+// DownloadAndStoreImage downloads a remote cover image, stores it together
+// with a downscaled thumbnail and points the bookmark at both. If the image
+// can't be decoded (or is already small) no thumbnail is stored and the grid
+// falls back to the full image. Images previously stored for the bookmark
+// are deleted.
+func (s *Service) DownloadAndStoreImage(ctx context.Context, bookmarkID int64, imageURL string) (*models.Image, error) {
+	data, err := downloadImage(ctx, s.imageClient, imageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	mimeType := http.DetectContentType(data)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return nil, fmt.Errorf("not an image: %s", mimeType)
+	}
+
+	return s.storeBookmarkCover(ctx, bookmarkID, mimeType, data, imageURL)
+}
+
+// StoreBookmarkCover stores an uploaded cover image for a bookmark, the same
+// way DownloadAndStoreImage does for remote ones
+func (s *Service) StoreBookmarkCover(ctx context.Context, bookmarkID int64, mimeType string, data []byte) (*models.Image, error) {
+	return s.storeBookmarkCover(ctx, bookmarkID, mimeType, data, "")
+}
+
+// storeBookmarkCover stores a cover image and its thumbnail and points the
+// bookmark at them, deleting the images they replace
+func (s *Service) storeBookmarkCover(ctx context.Context, bookmarkID int64, mimeType string, data []byte, imageURL string) (*models.Image, error) {
+	bookmark, err := s.queries.GetBookmarkByID(ctx, bookmarkID)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := s.CreateImage(ctx, mimeType, data, imageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var thumbnailID *int64
+	if thumb, thumbType, ok := makeThumbnail(data, s.thumbnailMaxWidth); ok {
+		// A missing thumbnail only costs bandwidth, so don't fail the store
+		if t, err := s.CreateImage(ctx, thumbType, thumb, imageURL); err == nil {
+			thumbnailID = &t.ID
+		}
+	}
+
+	err = s.queries.UpdateBookmarkImages(ctx, db.UpdateBookmarkImagesParams{
+		CoverImageID: &image.ID,
+		ThumbnailID:  thumbnailID,
+		ID:           bookmarkID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Clean up the images this download replaced
+	s.deleteImages(ctx, bookmark.CoverImageID, bookmark.ThumbnailID)
+
+	return image, nil
+}
+
+// deleteImages deletes the stored images with the given IDs, skipping nil
+// ones. Failures only leave an unreferenced row behind, so they're ignored.
+func (s *Service) deleteImages(ctx context.Context, ids ...*int64) {
+	for _, id := range ids {
+		if id != nil {
+			s.queries.DeleteImage(ctx, *id)
+		}
+	}
+}
+
+// downloadImage fetches a remote image with client, refusing bodies larger
+// than maxImageDownloadBytes
+func downloadImage(ctx context.Context, client *http.Client, imageURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status fetching image: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageDownloadBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageDownloadBytes {
+		return nil, fmt.Errorf("image exceeds %d bytes", maxImageDownloadBytes)
+	}
+	return data, nil
+}
+
+// dbImageToModel converts a database image to a domain model
+func dbImageToModel(i db.Image) *models.Image {
+	image := &models.Image{
+		ID:        i.ID,
+		MimeType:  i.MimeType,
+		Data:      i.Data,
+		Size:      i.Size,
+		CreatedAt: derefTime(i.CreatedAt),
+	}
+	if i.SourceUrl != nil {
+		image.SourceURL = *i.SourceUrl
+	}
+	return image
+}
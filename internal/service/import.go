@@ -2,11 +2,15 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
 	"github.com/EC-9624/0xec.dev/internal/models"
 )
 
@@ -97,36 +101,90 @@ func ParseChromeBookmarks(html string) ([]ImportedBookmark, error) {
 	return bookmarks, nil
 }
 
-// ImportBookmarks imports a list of bookmarks, handling duplicates
-// It fetches metadata for each bookmark in the background
+// importBatchSize is how many new bookmarks CreateBookmarksBatch inserts
+// per statement
+const importBatchSize = 500
+
+// importRow is the JSON shape CreateBookmarksBatch reads for each bookmark
+type importRow struct {
+	URL           string  `json:"url"`
+	NormalizedURL string  `json:"normalized_url"`
+	Title         string  `json:"title"`
+	Domain        *string `json:"domain"`
+	CollectionID  *int64  `json:"collection_id"`
+}
+
+// ImportBookmarks imports a list of bookmarks, handling duplicates.
+// New bookmarks go to the collection for their folder, created if needed
+// (see SetImportFolderDepth), or to defaultCollectionID when they aren't
+// in a folder. The import runs in a single transaction, inserting new
+// bookmarks in batches and logging one summary activity at the end. If it
+// fails, nothing is saved and the counts reached so far are returned with
+// the error. Metadata for the new bookmarks is fetched in the background.
 func (s *Service) ImportBookmarks(ctx context.Context, bookmarks []ImportedBookmark, defaultCollectionID *int64) (*ImportResult, error) {
 	result := &ImportResult{
 		Total: len(bookmarks),
 	}
 
-	// Collect IDs of newly created bookmarks for metadata fetching
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+	q := s.queries.WithTx(tx)
+
+	// New bookmarks wait in pending until the next batch insert; queued
+	// catches repeats of a URL that isn't in the table yet. Both compare
+	// normalized URLs.
+	var pending []importRow
+	queued := make(map[string]bool)
 	var createdIDs []int64
+	collections := newImportCollections(q)
+
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		data, err := json.Marshal(pending)
+		if err != nil {
+			return err
+		}
+		ids, err := q.CreateBookmarksBatch(ctx, string(data))
+		if err != nil {
+			return err
+		}
+		createdIDs = append(createdIDs, ids...)
+		result.Created += len(ids)
+		pending = pending[:0]
+		return nil
+	}
 
 	for _, ib := range bookmarks {
-		// Skip empty URLs
-		if ib.URL == "" {
+		// Skip empty URLs and URLs already queued by this import
+		normalized := s.normalizeBookmarkURL(ib.URL)
+		if ib.URL == "" || queued[normalized] {
 			result.Skipped++
 			continue
 		}
 
 		// Check if bookmark already exists by URL
-		existing, err := s.GetBookmarkByURL(ctx, ib.URL)
-		if err == nil && existing != nil {
+		existing, err := q.GetBookmarkByNormalizedURL(ctx, &normalized)
+		if err == nil {
 			// Update existing bookmark if title is empty
 			if existing.Title == "" && ib.Title != "" {
-				_, err := s.UpdateBookmark(ctx, existing.ID, models.UpdateBookmarkInput{
-					URL:          existing.URL,
-					Title:        ib.Title,
-					Description:  existing.GetDescription(),
-					CoverImage:   existing.GetCoverImage(),
-					CollectionID: getInt64Ptr(existing.CollectionID),
-					IsPublic:     existing.IsPublic,
-					IsFavorite:   existing.IsFavorite,
+				err := q.UpdateBookmark(ctx, db.UpdateBookmarkParams{
+					Url:           existing.Url,
+					NormalizedUrl: existing.NormalizedUrl,
+					Title:         ib.Title,
+					Description:   existing.Description,
+					Note:          existing.Note,
+					CoverImage:    existing.CoverImage,
+					Favicon:       existing.Favicon,
+					Domain:        existing.Domain,
+					CollectionID:  existing.CollectionID,
+					IsPublic:      existing.IsPublic,
+					IsFavorite:    existing.IsFavorite,
+					ID:            existing.ID,
 				})
 				if err != nil {
 					result.Errors = append(result.Errors, "Failed to update: "+ib.URL)
@@ -138,31 +196,62 @@ func (s *Service) ImportBookmarks(ctx context.Context, bookmarks []ImportedBookm
 			}
 			continue
 		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return result, err
+		}
 
-		// Create new bookmark
+		// Queue new bookmark
 		title := ib.Title
 		if title == "" {
 			title = ib.URL // Fallback to URL if no title
 		}
-
-		bookmark, err := s.CreateBookmark(ctx, models.CreateBookmarkInput{
-			URL:          ib.URL,
-			Title:        title,
-			CollectionID: defaultCollectionID,
-			IsPublic:     true,
-			IsFavorite:   false,
+		collectionID := defaultCollectionID
+		if path := s.importFolderPath(ib.Folder); len(path) > 0 {
+			id, err := collections.resolve(ctx, path)
+			if err != nil {
+				return result, err
+			}
+			collectionID = &id
+		}
+		pending = append(pending, importRow{
+			URL:           ib.URL,
+			NormalizedURL: normalized,
+			Title:         title,
+			Domain:        strPtr(extractDomain(ib.URL)),
+			CollectionID:  collectionID,
 		})
-		if err != nil {
-			result.Errors = append(result.Errors, "Failed to create: "+ib.URL)
-		} else {
-			result.Created++
-			createdIDs = append(createdIDs, bookmark.ID)
+		queued[normalized] = true
+
+		if len(pending) >= importBatchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
 		}
 	}
+	if err := flush(); err != nil {
+		return result, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, err
+	}
+
+	for _, c := range collections.created {
+		s.LogActivity(ctx, ActionCollectionCreated, EntityCollection, c.ID, c.Name, map[string]interface{}{
+			"source": "import",
+		})
+	}
+	s.LogActivity(ctx, ActionImportCompleted, EntityBookmark, 0, fmt.Sprintf("%d bookmarks", result.Created), map[string]interface{}{
+		"created": result.Created,
+		"updated": result.Updated,
+		"skipped": result.Skipped,
+	})
 
 	// Fetch metadata for newly created bookmarks in background
 	if len(createdIDs) > 0 {
-		go s.fetchMetadataForBookmarks(createdIDs)
+		s.goBackground(func(ctx context.Context) {
+			s.fetchMetadataForBookmarks(ctx, createdIDs)
+		})
 	}
 
 	return result, nil
@@ -184,8 +273,9 @@ func (s *Service) RefreshBookmarkMetadata(ctx context.Context, id int64) error {
 		URL:          bookmark.URL,
 		Title:        bookmark.Title,
 		Description:  bookmark.GetDescription(),
-		CoverImage:   bookmark.GetCoverImage(),
-		Favicon:      bookmark.GetFavicon(),
+		Note:         bookmark.GetNote(),
+		CoverImage:   bookmark.CoverImage.String,
+		Favicon:      bookmark.Favicon.String,
 		CollectionID: getInt64Ptr(bookmark.CollectionID),
 		IsPublic:     bookmark.IsPublic,
 		IsFavorite:   bookmark.IsFavorite,
@@ -205,15 +295,30 @@ func (s *Service) RefreshBookmarkMetadata(ctx context.Context, id int64) error {
 		input.Favicon = metadata.Favicon
 	}
 
-	_, err = s.UpdateBookmark(ctx, id, input)
-	return err
+	if _, err = s.UpdateBookmark(ctx, id, input); err != nil {
+		return err
+	}
+
+	// Store the cover locally so the grid can serve a thumbnail; the remote
+	// URL stays as the fallback if the download fails
+	if metadata.Image != "" {
+		s.DownloadAndStoreImage(ctx, id, metadata.Image)
+	}
+	return nil
 }
 
-// RefreshAllMissingMetadataAsync refreshes metadata in background with progress callback
-func (s *Service) RefreshAllMissingMetadataAsync(progressChan chan<- string) {
-	go func() {
+// refreshInterval spaces out requests when refreshing metadata in bulk
+const refreshInterval = 300 * time.Millisecond
+
+// RefreshAllMissingMetadataAsync refreshes metadata in background with
+// progress callback. The refresh stops early when ctx is cancelled (e.g.
+// the client watching progress disconnects) or the service shuts down.
+func (s *Service) RefreshAllMissingMetadataAsync(ctx context.Context, progressChan chan<- string) {
+	s.goBackground(func(bgCtx context.Context) {
 		defer close(progressChan)
-		ctx := context.Background()
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		defer context.AfterFunc(bgCtx, cancel)()
 
 		// Get all bookmarks
 		bookmarks, err := s.ListBookmarks(ctx, BookmarkListOptions{
@@ -244,6 +349,10 @@ func (s *Service) RefreshAllMissingMetadataAsync(progressChan chan<- string) {
 		updated := 0
 		failed := 0
 		for i, bookmark := range toRefresh {
+			if ctx.Err() != nil {
+				progressChan <- "error:Refresh cancelled"
+				return
+			}
 			err := s.RefreshBookmarkMetadata(ctx, bookmark.ID)
 			if err != nil {
 				failed++
@@ -251,18 +360,27 @@ func (s *Service) RefreshAllMissingMetadataAsync(progressChan chan<- string) {
 				updated++
 			}
 			progressChan <- fmt.Sprintf("progress:%d:%d:%s", i+1, total, bookmark.Title)
-			time.Sleep(300 * time.Millisecond)
+
+			select {
+			case <-ctx.Done():
+				progressChan <- "error:Refresh cancelled"
+				return
+			case <-time.After(refreshInterval):
+			}
 		}
 
 		progressChan <- fmt.Sprintf("done:%d:%d:Completed", updated, failed)
-	}()
+	})
 }
 
-// fetchMetadataForBookmarks fetches and updates metadata for a list of bookmark IDs
-func (s *Service) fetchMetadataForBookmarks(ids []int64) {
-	ctx := context.Background()
-
+// fetchMetadataForBookmarks fetches and updates metadata for a list of
+// bookmark IDs, stopping once ctx is cancelled
+func (s *Service) fetchMetadataForBookmarks(ctx context.Context, ids []int64) {
 	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+
 		// Get the bookmark
 		bookmark, err := s.GetBookmarkByID(ctx, id)
 		if err != nil {
@@ -280,8 +398,9 @@ func (s *Service) fetchMetadataForBookmarks(ids []int64) {
 			URL:          bookmark.URL,
 			Title:        bookmark.Title,
 			Description:  bookmark.GetDescription(),
-			CoverImage:   bookmark.GetCoverImage(),
-			Favicon:      bookmark.GetFavicon(),
+			Note:         bookmark.GetNote(),
+			CoverImage:   bookmark.CoverImage.String,
+			Favicon:      bookmark.Favicon.String,
 			CollectionID: getInt64Ptr(bookmark.CollectionID),
 			IsPublic:     bookmark.IsPublic,
 			IsFavorite:   bookmark.IsFavorite,
@@ -301,19 +420,47 @@ func (s *Service) fetchMetadataForBookmarks(ids []int64) {
 			input.Favicon = metadata.Favicon
 		}
 
-		s.UpdateBookmark(ctx, id, input)
+		if _, err := s.UpdateBookmark(ctx, id, input); err != nil {
+			continue
+		}
+
+		if input.CoverImage != "" && !bookmark.CoverImageID.Valid {
+			s.DownloadAndStoreImage(ctx, id, input.CoverImage)
+		}
 	}
 }
 
-// GetBookmarkByURL finds a bookmark by its URL
+// GetBookmarkByURL finds a bookmark whose URL normalizes to the same
+// form as url, so variants of a saved link are reported as duplicates
 func (s *Service) GetBookmarkByURL(ctx context.Context, url string) (*models.Bookmark, error) {
-	bookmark, err := s.queries.GetBookmarkByURL(ctx, url)
+	normalized := s.normalizeBookmarkURL(url)
+	bookmark, err := s.queries.GetBookmarkByNormalizedURL(ctx, &normalized)
 	if err != nil {
 		return nil, err
 	}
 	return dbBookmarkToModel(bookmark), nil
 }
 
+// BackfillNormalizedURLs stores the normalized URL of bookmarks saved
+// before normalization existed. It returns how many bookmarks it updated.
+func (s *Service) BackfillNormalizedURLs(ctx context.Context) (int, error) {
+	rows, err := s.queries.ListBookmarksWithoutNormalizedURL(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, r := range rows {
+		normalized := s.normalizeBookmarkURL(r.Url)
+		if err := s.queries.UpdateBookmarkNormalizedURL(ctx, db.UpdateBookmarkNormalizedURLParams{
+			NormalizedUrl: &normalized,
+			ID:            r.ID,
+		}); err != nil {
+			return i, err
+		}
+	}
+	return len(rows), nil
+}
+
 // Helper to parse Unix timestamp string
 func parseUnixTimestamp(s string) (time.Time, error) {
 	var ts int64
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+)
+
+// defaultImportFolderDepth is how many levels of import folders become
+// collections until SetImportFolderDepth overrides it
+const defaultImportFolderDepth = 1
+
+// browserRootFolders are the folders browsers wrap every export in. They
+// are skipped when mapping folders to collections, so their direct children
+// are the top-level folders.
+var browserRootFolders = map[string]bool{
+	"bookmarks bar":     true,
+	"bookmarks toolbar": true,
+	"bookmarks menu":    true,
+	"other bookmarks":   true,
+	"mobile bookmarks":  true,
+}
+
+// SetImportFolderDepth sets how many levels of import folders become
+// collections: 1 maps each top-level folder to a collection, 2 also maps
+// their subfolders to child collections, and so on. Zero or negative
+// imports everything into the chosen collection.
+func (s *Service) SetImportFolderDepth(depth int) {
+	s.importFolderDepth = depth
+}
+
+// importFolderPath returns the folder names an import folder path maps to,
+// outermost first, trimmed to the folder depth. It is empty for bookmarks
+// that go to the default collection. Folders whose name has nothing to
+// slugify end the path, so their bookmarks land in the parent's collection.
+func (s *Service) importFolderPath(folder string) []string {
+	if s.importFolderDepth <= 0 || folder == "" {
+		return nil
+	}
+
+	parts := strings.Split(folder, "/")
+	if browserRootFolders[strings.ToLower(strings.TrimSpace(parts[0]))] {
+		parts = parts[1:]
+	}
+
+	var path []string
+	for _, p := range parts {
+		if len(path) == s.importFolderDepth {
+			break
+		}
+		p = strings.TrimSpace(p)
+		if Slugify(p) == "" {
+			break
+		}
+		path = append(path, p)
+	}
+	return path
+}
+
+// importCollections resolves import folder paths to collection IDs within
+// an import transaction, reusing collections by slug and creating the rest.
+// A nested folder's slug is built from its whole path ("tech-go" for
+// Tech/Go), so same-named subfolders of different parents stay apart.
+type importCollections struct {
+	q       *db.Queries
+	bySlug  map[string]int64
+	created []db.Collection
+}
+
+func newImportCollections(q *db.Queries) *importCollections {
+	return &importCollections{q: q, bySlug: make(map[string]int64)}
+}
+
+// resolve returns the ID of the collection for path, creating it and its
+// parents if needed
+func (c *importCollections) resolve(ctx context.Context, path []string) (int64, error) {
+	var parentID *int64
+	var id int64
+	for i := range path {
+		slug := Slugify(strings.Join(path[:i+1], "/"))
+		if existing, ok := c.bySlug[slug]; ok {
+			id = existing
+		} else {
+			found, err := c.q.GetCollectionBySlug(ctx, slug)
+			switch {
+			case err == nil:
+				id = found.ID
+			case errors.Is(err, sql.ErrNoRows):
+				collection, err := c.q.CreateCollection(ctx, db.CreateCollectionParams{
+					Name:     path[i],
+					Slug:     slug,
+					ParentID: parentID,
+					IsPublic: boolToInt64Ptr(true),
+				})
+				if err != nil {
+					return 0, err
+				}
+				c.created = append(c.created, collection)
+				id = collection.ID
+			default:
+				return 0, err
+			}
+			c.bySlug[slug] = id
+		}
+		parent := id
+		parentID = &parent
+	}
+	return id, nil
+}
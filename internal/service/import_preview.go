@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrInvalidImportToken is returned for an import preview token that was
+// never issued, has already been committed, or has expired
+var ErrInvalidImportToken = errors.New("invalid or expired import preview")
+
+// ImportPreviewTTL is how long a previewed import can be committed
+const ImportPreviewTTL = 30 * time.Minute
+
+// ImportPreviewItem is a parsed bookmark as shown in an import preview
+type ImportPreviewItem struct {
+	ImportedBookmark
+
+	// ExistingID is the ID of the saved bookmark with the same URL, or 0
+	ExistingID int64
+
+	// Repeat is set when an earlier item in the file has the same URL
+	Repeat bool
+
+	// CollectionPath is the folder path whose collection the bookmark goes
+	// to, outermost first; empty for the preview's CollectionID
+	CollectionPath []string
+}
+
+// Duplicate reports whether importing the item would add nothing new
+func (i ImportPreviewItem) Duplicate() bool {
+	return i.ExistingID != 0 || i.Repeat
+}
+
+// ImportPreview is a parsed import file waiting to be committed
+type ImportPreview struct {
+	Token        string
+	Items        []ImportPreviewItem
+	CollectionID *int64
+}
+
+// importPreviewEntry is a parsed import file held until it is committed
+type importPreviewEntry struct {
+	bookmarks    []ImportedBookmark
+	collectionID *int64
+}
+
+// PreviewImport flags which of bookmarks are already saved or repeated in
+// the file, without writing anything. The parsed set is held for
+// ImportPreviewTTL under the returned preview's token, for CommitImport.
+func (s *Service) PreviewImport(ctx context.Context, bookmarks []ImportedBookmark, defaultCollectionID *int64) (*ImportPreview, error) {
+	preview := &ImportPreview{
+		Items:        make([]ImportPreviewItem, 0, len(bookmarks)),
+		CollectionID: defaultCollectionID,
+	}
+
+	seen := make(map[string]bool)
+	for _, ib := range bookmarks {
+		item := ImportPreviewItem{
+			ImportedBookmark: ib,
+			CollectionPath:   s.importFolderPath(ib.Folder),
+		}
+		normalized := s.normalizeBookmarkURL(ib.URL)
+		item.Repeat = seen[normalized]
+		seen[normalized] = true
+
+		existing, err := s.GetBookmarkByURL(ctx, ib.URL)
+		if err == nil {
+			item.ExistingID = existing.ID
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		preview.Items = append(preview.Items, item)
+	}
+
+	preview.Token = s.importPreviews.put(importPreviewEntry{bookmarks: bookmarks, collectionID: defaultCollectionID}, time.Now())
+	return preview, nil
+}
+
+// CommitImport imports the previewed bookmarks at the indexes in selected,
+// as ImportBookmarks does with the collection chosen for the preview as
+// the default. Indexes out of range are ignored. A token can be committed once, within ImportPreviewTTL.
+func (s *Service) CommitImport(ctx context.Context, token string, selected []int) (*ImportResult, error) {
+	entry, ok := s.importPreviews.take(token, time.Now())
+	if !ok {
+		return nil, ErrInvalidImportToken
+	}
+
+	bookmarks := make([]ImportedBookmark, 0, len(selected))
+	for _, i := range selected {
+		if i >= 0 && i < len(entry.bookmarks) {
+			bookmarks = append(bookmarks, entry.bookmarks[i])
+		}
+	}
+	return s.ImportBookmarks(ctx, bookmarks, entry.collectionID)
+}
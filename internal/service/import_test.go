@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+)
+
+func TestImportBookmarks(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	// Unreachable URLs keep the background metadata fetch off the network
+	if _, err := svc.queries.CreateBookmark(ctx, db.CreateBookmarkParams{Url: "http://127.0.0.1:1/untitled"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.queries.CreateBookmark(ctx, db.CreateBookmarkParams{Url: "http://127.0.0.1:1/titled", Title: "Kept"}); err != nil {
+		t.Fatal(err)
+	}
+	// Rows inserted without a normalized URL are matched after the backfill
+	if n, err := svc.BackfillNormalizedURLs(ctx); err != nil || n != 2 {
+		t.Fatalf("BackfillNormalizedURLs() = %d, %v; want 2, nil", n, err)
+	}
+
+	result, err := svc.ImportBookmarks(ctx, []ImportedBookmark{
+		{URL: "http://127.0.0.1:1/a", Title: "A"},
+		{URL: "http://127.0.0.1:1/b"},
+		{URL: "http://127.0.0.1:1/a/?utm_source=x", Title: "A again"},
+		{URL: ""},
+		{URL: "http://127.0.0.1:1/untitled", Title: "Now titled"},
+		{URL: "http://127.0.0.1:1/titled", Title: "Replaced"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("ImportBookmarks() error = %v", err)
+	}
+
+	want := ImportResult{Total: 6, Created: 2, Updated: 1, Skipped: 3}
+	if result.Total != want.Total || result.Created != want.Created || result.Updated != want.Updated || result.Skipped != want.Skipped {
+		t.Errorf("result = %+v, want %+v", *result, want)
+	}
+
+	b, err := svc.GetBookmarkByURL(ctx, "http://127.0.0.1:1/b")
+	if err != nil {
+		t.Fatalf("imported bookmark missing: %v", err)
+	}
+	if b.Title != "http://127.0.0.1:1/b" || !b.IsPublic || b.Domain.String != "127.0.0.1:1" {
+		t.Errorf("imported bookmark = %+v, want URL title, public, domain set", b)
+	}
+	if b, _ := svc.GetBookmarkByURL(ctx, "http://127.0.0.1:1/untitled"); b.Title != "Now titled" {
+		t.Errorf("untitled bookmark title = %q, want %q", b.Title, "Now titled")
+	}
+	if b, _ := svc.GetBookmarkByURL(ctx, "http://127.0.0.1:1/titled"); b.Title != "Kept" {
+		t.Errorf("titled bookmark title = %q, want it kept", b.Title)
+	}
+
+	activities, err := svc.ListRecentActivities(ctx, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activities) != 1 || activities[0].Action != ActionImportCompleted {
+		t.Errorf("activities = %+v, want a single import summary", activities)
+	}
+}
+
+func TestImportBookmarks_FoldersToCollections(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.ImportBookmarks(ctx, []ImportedBookmark{
+		{URL: "http://127.0.0.1:1/go", Folder: "Bookmarks bar/Tech/Go"},
+		{URL: "http://127.0.0.1:1/rust", Folder: "Bookmarks bar/Tech"},
+		{URL: "http://127.0.0.1:1/essay", Folder: "Bookmarks bar/Reading"},
+		{URL: "http://127.0.0.1:1/root", Folder: "Bookmarks bar"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("ImportBookmarks() error = %v", err)
+	}
+	// A second import reuses the collections by slug
+	_, err = svc.ImportBookmarks(ctx, []ImportedBookmark{
+		{URL: "http://127.0.0.1:1/novel", Folder: "Other Bookmarks/reading"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("second ImportBookmarks() error = %v", err)
+	}
+
+	collections, err := svc.ListCollections(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collections) != 2 {
+		t.Fatalf("collections = %+v, want Tech and Reading", collections)
+	}
+	tech, err := svc.GetCollectionBySlug(ctx, "tech")
+	if err != nil {
+		t.Fatalf("Tech collection missing: %v", err)
+	}
+	reading, err := svc.GetCollectionBySlug(ctx, "reading")
+	if err != nil {
+		t.Fatalf("Reading collection missing: %v", err)
+	}
+
+	// 0 is the default collection (none here)
+	want := map[string]int64{
+		"http://127.0.0.1:1/go":    tech.ID,
+		"http://127.0.0.1:1/rust":  tech.ID,
+		"http://127.0.0.1:1/essay": reading.ID,
+		"http://127.0.0.1:1/novel": reading.ID,
+		"http://127.0.0.1:1/root":  0,
+	}
+	for url, wantID := range want {
+		b, err := svc.GetBookmarkByURL(ctx, url)
+		if err != nil {
+			t.Fatalf("%s not imported: %v", url, err)
+		}
+		if b.CollectionID.Int64 != wantID {
+			t.Errorf("%s collection = %d, want %d", url, b.CollectionID.Int64, wantID)
+		}
+	}
+
+	activities, err := svc.ListRecentActivities(ctx, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := 0
+	for _, a := range activities {
+		if a.Action == ActionCollectionCreated {
+			created++
+		}
+	}
+	if created != 2 {
+		t.Errorf("collection.created activities = %d, want 2", created)
+	}
+}
+
+func TestImportFolderPath(t *testing.T) {
+	svc := newTestService(t)
+	svc.SetImportFolderDepth(2)
+
+	tests := []struct {
+		folder string
+		want   string
+	}{
+		{"", ""},
+		{"Bookmarks bar", ""},
+		{"Bookmarks bar/Tech", "Tech"},
+		{"Bookmarks bar/Tech/Go/Generics", "Tech/Go"},
+		{"Tech/日本語/Go", "Tech"},
+		{"Other Bookmarks/Reading", "Reading"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(svc.importFolderPath(tt.folder), "/"); got != tt.want {
+			t.Errorf("importFolderPath(%q) = %q, want %q", tt.folder, got, tt.want)
+		}
+	}
+
+	svc.SetImportFolderDepth(0)
+	if got := svc.importFolderPath("Bookmarks bar/Tech"); len(got) != 0 {
+		t.Errorf("importFolderPath() with depth 0 = %v, want none", got)
+	}
+}
+
+func TestPreviewImport_FlagsDuplicates(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	saved, err := svc.queries.CreateBookmark(ctx, db.CreateBookmarkParams{Url: "http://127.0.0.1:1/saved", Title: "Saved"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.BackfillNormalizedURLs(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	preview, err := svc.PreviewImport(ctx, []ImportedBookmark{
+		{URL: "http://127.0.0.1:1/new", Title: "New", Folder: "Bookmarks Bar/Tech"},
+		{URL: "http://127.0.0.1:1/saved/?utm_source=x", Title: "Saved again"},
+		{URL: "http://127.0.0.1:1/new", Title: "New again"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("PreviewImport() error = %v", err)
+	}
+
+	if len(preview.Items) != 3 {
+		t.Fatalf("len(Items) = %d, want 3", len(preview.Items))
+	}
+	if preview.Items[0].Duplicate() || preview.Items[0].Folder != "Bookmarks Bar/Tech" {
+		t.Errorf("Items[0] = %+v, want a new bookmark in its folder", preview.Items[0])
+	}
+	if preview.Items[1].ExistingID != saved.ID {
+		t.Errorf("Items[1].ExistingID = %d, want %d", preview.Items[1].ExistingID, saved.ID)
+	}
+	if !preview.Items[2].Repeat || preview.Items[2].ExistingID != 0 {
+		t.Errorf("Items[2] = %+v, want a repeat of Items[0]", preview.Items[2])
+	}
+
+	// Previewing writes nothing
+	if _, err := svc.GetBookmarkByURL(ctx, "http://127.0.0.1:1/new"); err == nil {
+		t.Error("preview saved a bookmark")
+	}
+
+	// Only the selected rows are imported, and the token works once
+	result, err := svc.CommitImport(ctx, preview.Token, []int{0})
+	if err != nil {
+		t.Fatalf("CommitImport() error = %v", err)
+	}
+	if result.Total != 1 || result.Created != 1 {
+		t.Errorf("result = %+v, want 1 created", *result)
+	}
+	if _, err := svc.CommitImport(ctx, preview.Token, []int{0}); !errors.Is(err, ErrInvalidImportToken) {
+		t.Errorf("second CommitImport() error = %v, want ErrInvalidImportToken", err)
+	}
+}
+
+func TestRefreshAllMissingMetadataAsync_StopsOnShutdown(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	for i := range 20 {
+		url := fmt.Sprintf("http://127.0.0.1:1/page-%d", i)
+		if _, err := svc.queries.CreateBookmark(ctx, db.CreateBookmarkParams{Url: url, Title: url}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	progress := make(chan string, 100)
+	svc.RefreshAllMissingMetadataAsync(ctx, progress)
+	if msg := <-progress; msg != "start:20" {
+		t.Fatalf("first message = %q, want start:20", msg)
+	}
+	<-progress
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := svc.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Shutdown() took %v, want the refresh loop to stop promptly", elapsed)
+	}
+
+	var last string
+	for msg := range progress {
+		last = msg
+	}
+	if !strings.HasPrefix(last, "error:") {
+		t.Errorf("last message = %q, want a cancellation error", last)
+	}
+}
+
+func TestRefreshAllMissingMetadataAsync_StopsOnCancel(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	for i := range 20 {
+		url := fmt.Sprintf("http://127.0.0.1:1/page-%d", i)
+		if _, err := svc.queries.CreateBookmark(ctx, db.CreateBookmarkParams{Url: url, Title: url}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	refreshCtx, cancel := context.WithCancel(ctx)
+	progress := make(chan string, 100)
+	svc.RefreshAllMissingMetadataAsync(refreshCtx, progress)
+	<-progress // start
+	<-progress // first bookmark
+	cancel()
+
+	var messages []string
+	timeout := time.After(time.Second)
+	for done := false; !done; {
+		select {
+		case msg, ok := <-progress:
+			if !ok {
+				done = true
+				break
+			}
+			messages = append(messages, msg)
+		case <-timeout:
+			t.Fatal("refresh kept running after its context was cancelled")
+		}
+	}
+
+	if len(messages) == 0 || messages[len(messages)-1] != "error:Refresh cancelled" {
+		t.Errorf("messages after cancel = %q, want to end with the cancellation", messages)
+	}
+	for _, msg := range messages {
+		if strings.HasPrefix(msg, "done:") {
+			t.Errorf("refresh completed after cancel: %q", msg)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/EC-9624/0xec.dev/internal/models"
@@ -19,11 +20,19 @@ type UserService interface {
 	GetUserByID(ctx context.Context, id int64) (*models.User, error)
 	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
 	ValidatePassword(user *models.User, password string) bool
-	CreateSession(ctx context.Context, userID int64, duration time.Duration) (*models.Session, error)
+	RecordFailedLogin(ctx context.Context, userID int64, maxAttempts int, lockout time.Duration) (bool, error)
+	ResetFailedLogins(ctx context.Context, userID int64) error
+	ChangePassword(ctx context.Context, userID int64, oldPassword, newPassword string) error
+	CreateSession(ctx context.Context, userID int64, duration time.Duration, client models.SessionClient) (*models.Session, error)
 	GetSession(ctx context.Context, sessionID string) (*models.Session, error)
+	TouchSession(ctx context.Context, sessionID string, at time.Time) error
 	DeleteSession(ctx context.Context, sessionID string) error
-	RotateSession(ctx context.Context, userID int64, oldSessionID string, duration time.Duration) (*models.Session, error)
-	CleanupExpiredSessions(ctx context.Context) error
+	RotateSession(ctx context.Context, userID int64, oldSessionID string, duration time.Duration, client models.SessionClient) (*models.Session, error)
+	ListSessionsForUser(ctx context.Context, userID int64) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID int64, sessionID string) error
+	RevokeAllOtherSessions(ctx context.Context, userID int64, currentSessionID string) (int64, error)
+	RevokeAllSessions(ctx context.Context, userID int64) (int64, error)
+	CleanupExpiredSessions(ctx context.Context) (int64, error)
 	EnsureAdminExists(ctx context.Context, username, password string) error
 }
 
@@ -34,32 +43,61 @@ type BookmarkService interface {
 	DeleteBookmark(ctx context.Context, id int64) error
 	GetBookmarkByID(ctx context.Context, id int64) (*models.Bookmark, error)
 	GetBookmarkByURL(ctx context.Context, url string) (*models.Bookmark, error)
+	BackfillNormalizedURLs(ctx context.Context) (int, error)
 	ListBookmarks(ctx context.Context, opts BookmarkListOptions) ([]models.Bookmark, error)
+	ListBookmarksCursor(ctx context.Context, opts BookmarkListOptions, cursor string) (*BookmarkPage, error)
 	ListUnsortedBookmarks(ctx context.Context, limit, offset int) ([]models.Bookmark, error)
+	CountUnsortedBookmarks(ctx context.Context) (int, error)
 	CountBookmarks(ctx context.Context, opts BookmarkListOptions) (int, error)
 	UpdateBookmarkPublic(ctx context.Context, id int64, isPublic bool) error
 	UpdateBookmarkFavorite(ctx context.Context, id int64, isFavorite bool) error
+	UpdateBookmarkArchived(ctx context.Context, id int64, archived bool) error
+	IncrementBookmarkClick(ctx context.Context, id int64)
 	MoveBookmark(ctx context.Context, bookmarkID int64, collectionID *int64, afterBookmarkID *int64) error
-	BulkMoveBookmarks(ctx context.Context, bookmarkIDs []int64, collectionID *int64, afterBookmarkID *int64) error
+	BulkMoveBookmarks(ctx context.Context, bookmarkIDs []int64, collectionID *int64, afterBookmarkID *int64) (string, error)
+	UndoBulkMove(ctx context.Context, token string) error
 	BulkDeleteBookmarks(ctx context.Context, bookmarkIDs []int64) error
+	BulkAddTagToBookmarks(ctx context.Context, bookmarkIDs []int64, tagID int64) error
+	BulkRemoveTag(ctx context.Context, bookmarkIDs []int64, tagID int64) error
 	RefreshBookmarkMetadata(ctx context.Context, id int64) error
-	RefreshAllMissingMetadataAsync(progressChan chan<- string)
+	RefreshAllMissingMetadataAsync(ctx context.Context, progressChan chan<- string)
 }
 
-// ImportService defines bookmark import operations
+// ImportService defines bookmark import and site export operations
 type ImportService interface {
 	ImportBookmarks(ctx context.Context, bookmarks []ImportedBookmark, defaultCollectionID *int64) (*ImportResult, error)
+	PreviewImport(ctx context.Context, bookmarks []ImportedBookmark, defaultCollectionID *int64) (*ImportPreview, error)
+	CommitImport(ctx context.Context, token string, selected []int) (*ImportResult, error)
+	ExportCollectionOPML(ctx context.Context, collectionID int64) (string, error)
+	ImportCollectionOPML(ctx context.Context, collectionID int64, r io.Reader) (*ImportResult, error)
+	ExportSite(ctx context.Context) (io.ReadCloser, error)
+	ImportSite(ctx context.Context, r io.Reader) (*SiteImportResult, error)
 }
 
 // PostService defines post management operations
 type PostService interface {
 	CreatePost(ctx context.Context, input models.CreatePostInput) (*models.Post, error)
 	UpdatePost(ctx context.Context, id int64, input models.UpdatePostInput) (*models.Post, error)
+	DuplicatePost(ctx context.Context, id int64) (*models.Post, error)
 	DeletePost(ctx context.Context, id int64) error
+	ListTrashedPosts(ctx context.Context) ([]models.Post, error)
+	RestorePost(ctx context.Context, id int64) error
+	PurgePost(ctx context.Context, id int64) error
+	PurgeTrashedPosts(ctx context.Context, retention time.Duration) (int, error)
 	GetPostByID(ctx context.Context, id int64) (*models.Post, error)
 	GetPostBySlug(ctx context.Context, slug string) (*models.Post, error)
+	GenerateUniqueSlug(ctx context.Context, title string, excludeID *int64) (string, error)
 	ListPosts(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error)
+	CountPosts(ctx context.Context, publishedOnly bool) (int, error)
+	ListPublishedPostsByTag(ctx context.Context, tagID int64) ([]models.Post, error)
 	UpdatePostDraft(ctx context.Context, id int64, isDraft bool) error
+	SetPostFeatured(ctx context.Context, id int64, featured bool) error
+	ListFeaturedPosts(ctx context.Context, limit int) ([]models.Post, error)
+	PostCardImage(post *models.Post, label string) ([]byte, error)
+	GeneratePostPreviewToken(postID int64) string
+	VerifyPostPreviewToken(postID int64, token string) error
+	ExportPostMarkdown(ctx context.Context, id int64) (string, error)
+	ExportPostsZip(ctx context.Context, w io.Writer) error
 }
 
 // CollectionService defines collection management operations
@@ -70,7 +108,9 @@ type CollectionService interface {
 	GetCollectionByID(ctx context.Context, id int64) (*models.Collection, error)
 	GetCollectionBySlug(ctx context.Context, slug string) (*models.Collection, error)
 	ListCollections(ctx context.Context, publicOnly bool) ([]models.Collection, error)
+	ListCollectionTree(ctx context.Context, publicOnly bool) ([]CollectionNode, error)
 	UpdateCollectionPublic(ctx context.Context, id int64, isPublic bool) error
+	MoveCollection(ctx context.Context, collectionID int64, afterCollectionID *int64) error
 	GetBookmarksByCollectionID(ctx context.Context, collectionID int64) ([]CollectionBookmark, error)
 	GetBoardViewData(ctx context.Context, recentLimit int) (*BoardViewData, error)
 }
@@ -79,9 +119,10 @@ type CollectionService interface {
 type TagService interface {
 	CreateTag(ctx context.Context, input models.CreateTagInput) (*models.Tag, error)
 	DeleteTag(ctx context.Context, id int64) error
+	MergeTags(ctx context.Context, sourceID, targetID int64) (int, error)
 	GetTagBySlug(ctx context.Context, slug string) (*models.Tag, error)
 	ListTags(ctx context.Context) ([]models.Tag, error)
-	GetTagsWithCounts(ctx context.Context) ([]TagWithCount, error)
+	GetTagsWithCounts(ctx context.Context, publishedOnly bool) ([]TagWithCount, error)
 	GetPostsByTagID(ctx context.Context, tagID int64) ([]TagPost, error)
 }
 
@@ -90,10 +131,27 @@ type StatsService interface {
 	GetDashboardStats(ctx context.Context) (*DashboardStats, error)
 }
 
+// HomeService defines home page operations
+type HomeService interface {
+	GetHomeData(ctx context.Context) (*HomeData, error)
+}
+
 // ActivityService defines activity logging operations
 type ActivityService interface {
 	LogActivity(ctx context.Context, action, entityType string, entityID int64, title string, metadata map[string]interface{}) (*Activity, error)
 	ListRecentActivities(ctx context.Context, limit, offset int) ([]Activity, error)
+	ListActivities(ctx context.Context, filter ActivityFilter, limit, offset int) ([]Activity, error)
+	CountActivities(ctx context.Context, filter ActivityFilter) (int, error)
+	CountActivitiesByType(ctx context.Context) ([]ActivityTypeCount, error)
+}
+
+// SearchService defines sitewide search and search logging and reporting
+// operations
+type SearchService interface {
+	Search(ctx context.Context, query string) (SearchResults, error)
+	RecordSearchQuery(ctx context.Context, query string, resultCount int) error
+	PruneSearchQueries(ctx context.Context, retention time.Duration) (int64, error)
+	GetZeroResultSearches(ctx context.Context, limit int) ([]SearchGap, error)
 }
 
 // MetadataService defines URL metadata fetching operations
@@ -101,10 +159,23 @@ type MetadataService interface {
 	FetchPageMetadata(ctx context.Context, url string) (*PageMetadata, error)
 }
 
+// ImageService defines stored image operations
+type ImageService interface {
+	CreateImage(ctx context.Context, mimeType string, data []byte, sourceURL string) (*models.Image, error)
+	GetImage(ctx context.Context, id int64) (*models.Image, error)
+	DeleteImage(ctx context.Context, id int64) error
+	StoreBookmarkCover(ctx context.Context, bookmarkID int64, mimeType string, data []byte) (*models.Image, error)
+}
+
 // ============================================
 // COMPOSITE SERVICE INTERFACE
 // ============================================
 
+// LifecycleService defines operations tied to the server's lifetime
+type LifecycleService interface {
+	Shutdown(ctx context.Context) error
+}
+
 // ServiceInterface combines all service interfaces.
 // Use this when you need access to all service functionality.
 type ServiceInterface interface {
@@ -114,9 +185,13 @@ type ServiceInterface interface {
 	CollectionService
 	TagService
 	StatsService
+	HomeService
 	ActivityService
+	SearchService
 	MetadataService
 	ImportService
+	ImageService
+	LifecycleService
 }
 
 // Ensure Service implements ServiceInterface at compile time
@@ -18,13 +18,34 @@ type PageMetadata struct {
 	Favicon     string
 }
 
-// FetchPageMetadata fetches and parses Open Graph and HTML metadata from a URL
+// defaultMetadataMaxBody is how much of a page is read by default. The
+// metadata lives in <head>, so the rest of a large page isn't needed.
+const defaultMetadataMaxBody = 512 * 1024
+
+// SetMetadataMaxBodySize caps how many bytes of a page are read when
+// fetching its metadata. Non-positive values keep the current setting.
+func (s *Service) SetMetadataMaxBodySize(n int64) {
+	if n > 0 {
+		s.metadataMaxBody = n
+	}
+}
+
+// FetchPageMetadata fetches and parses Open Graph and HTML metadata from a
+// URL. It returns ErrRobotsDisallowed if the site's robots.txt forbids the
+// fetch.
 func (s *Service) FetchPageMetadata(ctx context.Context, url string) (*PageMetadata, error) {
 	client := &http.Client{
 		Timeout: 10 * time.Second,
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	// Tracking params don't change the page, but can change what's served
+	// (or log the visit as a campaign click)
+	pageURL := s.cleanBookmarkURL(url)
+	if err := s.checkRobots(ctx, client, pageURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -34,14 +55,14 @@ func (s *Service) FetchPageMetadata(ctx context.Context, url string) (*PageMetad
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
 
-	resp, err := client.Do(req)
+	resp, err := s.metadataRetry.do(ctx, client, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// Limit reading to 1MB to avoid huge pages
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	// Limit reading to avoid downloading huge pages
+	body, err := io.ReadAll(io.LimitReader(resp.Body, s.metadataMaxBody))
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +94,8 @@ func (s *Service) FetchPageMetadata(ctx context.Context, url string) (*PageMetad
 		metadata.Description = extractMeta(html, `description`)
 	}
 
-	// Extract favicon
+	// Resolve relative image URLs and extract favicon
+	metadata.Image = resolveImageURL(metadata.Image, url)
 	metadata.Favicon = extractFavicon(html, url)
 
 	// Clean up
@@ -176,19 +198,46 @@ func extractFavicon(html, pageURL string) string {
 		re := regexp.MustCompile(pattern)
 		matches := re.FindStringSubmatch(html)
 		if len(matches) > 1 {
-			favicon := matches[1]
-			// Make absolute URL if relative
-			if strings.HasPrefix(favicon, "//") {
-				return parsedURL.Scheme + ":" + favicon
-			} else if strings.HasPrefix(favicon, "/") {
-				return baseURL + favicon
-			} else if !strings.HasPrefix(favicon, "http") {
-				return baseURL + "/" + favicon
-			}
-			return favicon
+			return absoluteURL(matches[1], parsedURL)
 		}
 	}
 
 	// Fall back to default /favicon.ico
 	return baseURL + "/favicon.ico"
 }
+
+// resolveImageURL makes an og:image or twitter:image URL absolute against
+// the page URL. Images that aren't http(s) once resolved are dropped.
+func resolveImageURL(image, pageURL string) string {
+	if image == "" {
+		return ""
+	}
+	parsedURL, err := url.Parse(pageURL)
+	if err != nil || parsedURL.Host == "" {
+		return ""
+	}
+	// Other schemes (data:, javascript:) aren't paths to join to the page
+	if ref, err := url.Parse(image); err != nil || (ref.Scheme != "" && ref.Scheme != "http" && ref.Scheme != "https") {
+		return ""
+	}
+
+	resolved, err := url.Parse(absoluteURL(image, parsedURL))
+	if err != nil || resolved.Host == "" || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+		return ""
+	}
+	return resolved.String()
+}
+
+// absoluteURL makes a URL found on a page absolute: protocol-relative URLs
+// take the page's scheme, and paths are joined to the page's origin
+func absoluteURL(ref string, page *url.URL) string {
+	baseURL := page.Scheme + "://" + page.Host
+	if strings.HasPrefix(ref, "//") {
+		return page.Scheme + ":" + ref
+	} else if strings.HasPrefix(ref, "/") {
+		return baseURL + ref
+	} else if !strings.HasPrefix(ref, "http") {
+		return baseURL + "/" + ref
+	}
+	return ref
+}
@@ -1,6 +1,16 @@
 package service
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
 
 func TestExtractMeta(t *testing.T) {
 	tests := []struct {
@@ -416,3 +426,232 @@ func TestExtractFavicon_InvalidURL(t *testing.T) {
 		t.Errorf("extractFavicon with invalid URL should return empty, got %q", got)
 	}
 }
+
+func TestResolveImageURL(t *testing.T) {
+	baseURL := "https://example.com"
+
+	tests := []struct {
+		name    string
+		image   string
+		pageURL string
+		want    string
+	}{
+		{"absolute URL", "https://cdn.example.com/cover.png", baseURL, "https://cdn.example.com/cover.png"},
+		{"protocol relative URL", "//cdn.example.com/cover.png", baseURL, "https://cdn.example.com/cover.png"},
+		{"relative URL with leading slash", "/img/cover.png", baseURL, "https://example.com/img/cover.png"},
+		{"relative URL without leading slash", "img/cover.png", baseURL, "https://example.com/img/cover.png"},
+		{"page URL with path and port", "/img/cover.png", "https://example.com:8080/posts/1", "https://example.com:8080/img/cover.png"},
+		{"http scheme", "//cdn.example.com/cover.png", "http://example.com", "http://cdn.example.com/cover.png"},
+		{"data URL", "data:image/png;base64,AAAA", baseURL, ""},
+		{"javascript URL", "javascript:alert(1)", baseURL, ""},
+		{"empty image", "", baseURL, ""},
+		{"invalid page URL", "/img/cover.png", "://invalid", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveImageURL(tt.image, tt.pageURL); got != tt.want {
+				t.Errorf("resolveImageURL(%q, %q) = %q, want %q", tt.image, tt.pageURL, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchPageMetadata_RetriesServerErrors(t *testing.T) {
+	var hits atomic.Int32
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		if hits.Add(1) <= 2 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`<html><head><meta property="og:title" content="Third time lucky"></head></html>`))
+	}))
+	defer page.Close()
+
+	svc := newTestService(t)
+	svc.SetMetadataRetry(3, time.Millisecond)
+
+	metadata, err := svc.FetchPageMetadata(context.Background(), page.URL)
+	if err != nil {
+		t.Fatalf("FetchPageMetadata() error = %v", err)
+	}
+	if metadata.Title != "Third time lucky" {
+		t.Errorf("Title = %q, want %q", metadata.Title, "Third time lucky")
+	}
+	if got := hits.Load(); got != 3 {
+		t.Errorf("server hit %d times, want 3", got)
+	}
+}
+
+func TestFetchPageMetadata_NoRetryOnClientError(t *testing.T) {
+	var hits atomic.Int32
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/robots.txt" {
+			hits.Add(1)
+		}
+		http.NotFound(w, r)
+	}))
+	defer page.Close()
+
+	svc := newTestService(t)
+	svc.SetMetadataRetry(3, time.Millisecond)
+
+	if _, err := svc.FetchPageMetadata(context.Background(), page.URL); err != nil {
+		t.Fatalf("FetchPageMetadata() error = %v", err)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("server hit %d times, want 1", got)
+	}
+}
+
+func TestFetchPageMetadata_RobotsDisallowed(t *testing.T) {
+	var robotsHits, pageHits atomic.Int32
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			robotsHits.Add(1)
+			w.Write([]byte("User-agent: *\nDisallow: /private\nAllow: /private/ok\n"))
+			return
+		}
+		pageHits.Add(1)
+		w.Write([]byte(`<title>Page</title>`))
+	}))
+	defer page.Close()
+
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := svc.FetchPageMetadata(ctx, page.URL+"/private/secret"); !errors.Is(err, ErrRobotsDisallowed) {
+		t.Errorf("FetchPageMetadata(disallowed) error = %v, want ErrRobotsDisallowed", err)
+	}
+	if pageHits.Load() != 0 {
+		t.Error("disallowed page was fetched")
+	}
+	if _, err := svc.FetchPageMetadata(ctx, page.URL+"/private/ok"); err != nil {
+		t.Errorf("FetchPageMetadata(allowed) error = %v", err)
+	}
+	if _, err := svc.FetchPageMetadata(ctx, page.URL+"/public"); err != nil {
+		t.Errorf("FetchPageMetadata(public) error = %v", err)
+	}
+	if got := robotsHits.Load(); got != 1 {
+		t.Errorf("robots.txt fetched %d times, want 1 (cached)", got)
+	}
+
+	svc.SetRespectRobots(false)
+	if _, err := svc.FetchPageMetadata(ctx, page.URL+"/private/secret"); err != nil {
+		t.Errorf("FetchPageMetadata() with robots ignored error = %v", err)
+	}
+}
+
+func TestFetchRobots_Failures(t *testing.T) {
+	tests := []struct {
+		status      int
+		wantFetched bool
+	}{
+		{http.StatusNotFound, true},
+		{http.StatusServiceUnavailable, false},
+	}
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		rules, fetched := fetchRobots(context.Background(), server.Client(), server.URL+"/robots.txt")
+		server.Close()
+		if rules != nil || fetched != tt.wantFetched {
+			t.Errorf("status %d: fetchRobots() = %v, %v; want no rules, %v", tt.status, rules, fetched, tt.wantFetched)
+		}
+	}
+
+	// An unreachable host is a failure too
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	if _, fetched := fetchRobots(context.Background(), server.Client(), server.URL+"/robots.txt"); fetched {
+		t.Error("fetchRobots() for a closed server reported fetched")
+	}
+}
+
+func TestRobotsCache_ExpiryAndBound(t *testing.T) {
+	var c robotsCache
+	now := time.Now()
+	rules := []robotsRule{{pattern: "/private"}}
+
+	c.put("https://failed.example", nil, robotsFailureTTL, now)
+	c.put("https://ok.example", rules, robotsCacheTTL, now)
+	later := now.Add(2 * robotsFailureTTL)
+	if _, ok := c.get("https://failed.example", later); ok {
+		t.Error("failed robots.txt still cached after robotsFailureTTL")
+	}
+	if got, ok := c.get("https://ok.example", later); !ok || len(got) != 1 {
+		t.Errorf("get(ok) = %v, %v; want the cached rules", got, ok)
+	}
+
+	for i := range robotsCacheMaxHosts + 10 {
+		c.put(fmt.Sprintf("https://host%d.example", i), nil, robotsCacheTTL, later.Add(time.Duration(i)*time.Second))
+	}
+	if n := len(c.entries); n > robotsCacheMaxHosts {
+		t.Errorf("cache holds %d hosts, want at most %d", n, robotsCacheMaxHosts)
+	}
+	// The newest host is kept and the oldest evicted
+	end := later.Add(time.Duration(robotsCacheMaxHosts+10) * time.Second)
+	if _, ok := c.get(fmt.Sprintf("https://host%d.example", robotsCacheMaxHosts+9), end); !ok {
+		t.Error("newest host was evicted")
+	}
+	if _, ok := c.get("https://ok.example", end); ok {
+		t.Error("oldest host survived eviction")
+	}
+}
+
+func TestFetchPageMetadata_BodyCap(t *testing.T) {
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><head><title>Early</title></head><body>`))
+		w.Write([]byte(strings.Repeat("x", 4096)))
+		w.Write([]byte(`<meta name="description" content="Too late"></body></html>`))
+	}))
+	defer page.Close()
+
+	svc := newTestService(t)
+	svc.SetMetadataMaxBodySize(1024)
+
+	metadata, err := svc.FetchPageMetadata(context.Background(), page.URL)
+	if err != nil {
+		t.Fatalf("FetchPageMetadata() error = %v", err)
+	}
+	if metadata.Title != "Early" || metadata.Description != "" {
+		t.Errorf("metadata = %+v, want only the title within the cap", metadata)
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	robots := `# comment
+User-agent: otherbot
+Disallow: /
+
+User-agent: 0xecbot
+User-agent: somebot
+Disallow: /drafts/
+Allow: /*.html$
+
+User-agent: *
+Disallow: /
+`
+	rules := parseRobots(strings.NewReader(robots), robotsAgent)
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/", true},
+		{"/posts/hello", true},
+		{"/drafts/wip", false},
+		{"/drafts/wip.html", true},
+		{"/drafts/wip.html?x=1", false},
+	}
+	for _, tt := range tests {
+		if got := robotsAllowed(rules, tt.path); got != tt.want {
+			t.Errorf("robotsAllowed(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/EC-9624/0xec.dev/internal/models"
@@ -16,12 +17,20 @@ type MockService struct {
 	GetUserByIDFunc            func(ctx context.Context, id int64) (*models.User, error)
 	GetUserByUsernameFunc      func(ctx context.Context, username string) (*models.User, error)
 	ValidatePasswordFunc       func(user *models.User, password string) bool
-	CreateSessionFunc          func(ctx context.Context, userID int64, duration time.Duration) (*models.Session, error)
+	CreateSessionFunc          func(ctx context.Context, userID int64, duration time.Duration, client models.SessionClient) (*models.Session, error)
 	GetSessionFunc             func(ctx context.Context, sessionID string) (*models.Session, error)
 	DeleteSessionFunc          func(ctx context.Context, sessionID string) error
-	RotateSessionFunc          func(ctx context.Context, userID int64, oldSessionID string, duration time.Duration) (*models.Session, error)
-	CleanupExpiredSessionsFunc func(ctx context.Context) error
+	RotateSessionFunc          func(ctx context.Context, userID int64, oldSessionID string, duration time.Duration, client models.SessionClient) (*models.Session, error)
+	CleanupExpiredSessionsFunc func(ctx context.Context) (int64, error)
 	EnsureAdminExistsFunc      func(ctx context.Context, username, password string) error
+	RecordFailedLoginFunc      func(ctx context.Context, userID int64, maxAttempts int, lockout time.Duration) (bool, error)
+	ResetFailedLoginsFunc      func(ctx context.Context, userID int64) error
+	ListSessionsForUserFunc    func(ctx context.Context, userID int64) ([]models.Session, error)
+	RevokeSessionFunc          func(ctx context.Context, userID int64, sessionID string) error
+	RevokeAllOtherSessionsFunc func(ctx context.Context, userID int64, currentSessionID string) (int64, error)
+	RevokeAllSessionsFunc      func(ctx context.Context, userID int64) (int64, error)
+	ChangePasswordFunc         func(ctx context.Context, userID int64, oldPassword, newPassword string) error
+	TouchSessionFunc           func(ctx context.Context, sessionID string, at time.Time) error
 
 	// Bookmark methods
 	CreateBookmarkFunc                 func(ctx context.Context, input models.CreateBookmarkInput) (*models.Bookmark, error)
@@ -29,28 +38,57 @@ type MockService struct {
 	DeleteBookmarkFunc                 func(ctx context.Context, id int64) error
 	GetBookmarkByIDFunc                func(ctx context.Context, id int64) (*models.Bookmark, error)
 	GetBookmarkByURLFunc               func(ctx context.Context, url string) (*models.Bookmark, error)
+	BackfillNormalizedURLsFunc         func(ctx context.Context) (int, error)
 	ListBookmarksFunc                  func(ctx context.Context, opts BookmarkListOptions) ([]models.Bookmark, error)
+	ListBookmarksCursorFunc            func(ctx context.Context, opts BookmarkListOptions, cursor string) (*BookmarkPage, error)
 	ListUnsortedBookmarksFunc          func(ctx context.Context, limit, offset int) ([]models.Bookmark, error)
+	CountUnsortedBookmarksFunc         func(ctx context.Context) (int, error)
 	CountBookmarksFunc                 func(ctx context.Context, opts BookmarkListOptions) (int, error)
 	UpdateBookmarkPublicFunc           func(ctx context.Context, id int64, isPublic bool) error
 	UpdateBookmarkFavoriteFunc         func(ctx context.Context, id int64, isFavorite bool) error
+	UpdateBookmarkArchivedFunc         func(ctx context.Context, id int64, archived bool) error
+	IncrementBookmarkClickFunc         func(ctx context.Context, id int64)
 	MoveBookmarkFunc                   func(ctx context.Context, bookmarkID int64, collectionID *int64, afterBookmarkID *int64) error
-	BulkMoveBookmarksFunc              func(ctx context.Context, bookmarkIDs []int64, collectionID *int64, afterBookmarkID *int64) error
+	BulkMoveBookmarksFunc              func(ctx context.Context, bookmarkIDs []int64, collectionID *int64, afterBookmarkID *int64) (string, error)
+	UndoBulkMoveFunc                   func(ctx context.Context, token string) error
 	BulkDeleteBookmarksFunc            func(ctx context.Context, bookmarkIDs []int64) error
+	BulkAddTagToBookmarksFunc          func(ctx context.Context, bookmarkIDs []int64, tagID int64) error
+	BulkRemoveTagFunc                  func(ctx context.Context, bookmarkIDs []int64, tagID int64) error
 	RefreshBookmarkMetadataFunc        func(ctx context.Context, id int64) error
-	RefreshAllMissingMetadataAsyncFunc func(progressChan chan<- string)
+	RefreshAllMissingMetadataAsyncFunc func(ctx context.Context, progressChan chan<- string)
 
 	// Import methods
-	ImportBookmarksFunc func(ctx context.Context, bookmarks []ImportedBookmark, defaultCollectionID *int64) (*ImportResult, error)
+	ImportBookmarksFunc      func(ctx context.Context, bookmarks []ImportedBookmark, defaultCollectionID *int64) (*ImportResult, error)
+	PreviewImportFunc        func(ctx context.Context, bookmarks []ImportedBookmark, defaultCollectionID *int64) (*ImportPreview, error)
+	CommitImportFunc         func(ctx context.Context, token string, selected []int) (*ImportResult, error)
+	ExportCollectionOPMLFunc func(ctx context.Context, collectionID int64) (string, error)
+	ImportCollectionOPMLFunc func(ctx context.Context, collectionID int64, r io.Reader) (*ImportResult, error)
+	ExportSiteFunc           func(ctx context.Context) (io.ReadCloser, error)
+	ImportSiteFunc           func(ctx context.Context, r io.Reader) (*SiteImportResult, error)
 
 	// Post methods
-	CreatePostFunc      func(ctx context.Context, input models.CreatePostInput) (*models.Post, error)
-	UpdatePostFunc      func(ctx context.Context, id int64, input models.UpdatePostInput) (*models.Post, error)
-	DeletePostFunc      func(ctx context.Context, id int64) error
-	GetPostByIDFunc     func(ctx context.Context, id int64) (*models.Post, error)
-	GetPostBySlugFunc   func(ctx context.Context, slug string) (*models.Post, error)
-	ListPostsFunc       func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error)
-	UpdatePostDraftFunc func(ctx context.Context, id int64, isDraft bool) error
+	CreatePostFunc               func(ctx context.Context, input models.CreatePostInput) (*models.Post, error)
+	UpdatePostFunc               func(ctx context.Context, id int64, input models.UpdatePostInput) (*models.Post, error)
+	DuplicatePostFunc            func(ctx context.Context, id int64) (*models.Post, error)
+	DeletePostFunc               func(ctx context.Context, id int64) error
+	ListTrashedPostsFunc         func(ctx context.Context) ([]models.Post, error)
+	RestorePostFunc              func(ctx context.Context, id int64) error
+	PurgePostFunc                func(ctx context.Context, id int64) error
+	PurgeTrashedPostsFunc        func(ctx context.Context, retention time.Duration) (int, error)
+	GetPostByIDFunc              func(ctx context.Context, id int64) (*models.Post, error)
+	GetPostBySlugFunc            func(ctx context.Context, slug string) (*models.Post, error)
+	GenerateUniqueSlugFunc       func(ctx context.Context, title string, excludeID *int64) (string, error)
+	ListPostsFunc                func(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error)
+	CountPostsFunc               func(ctx context.Context, publishedOnly bool) (int, error)
+	ListPublishedPostsByTagFunc  func(ctx context.Context, tagID int64) ([]models.Post, error)
+	UpdatePostDraftFunc          func(ctx context.Context, id int64, isDraft bool) error
+	SetPostFeaturedFunc          func(ctx context.Context, id int64, featured bool) error
+	ListFeaturedPostsFunc        func(ctx context.Context, limit int) ([]models.Post, error)
+	PostCardImageFunc            func(post *models.Post, label string) ([]byte, error)
+	GeneratePostPreviewTokenFunc func(postID int64) string
+	VerifyPostPreviewTokenFunc   func(postID int64, token string) error
+	ExportPostMarkdownFunc       func(ctx context.Context, id int64) (string, error)
+	ExportPostsZipFunc           func(ctx context.Context, w io.Writer) error
 
 	// Collection methods
 	CreateCollectionFunc           func(ctx context.Context, input models.CreateCollectionInput) (*models.Collection, error)
@@ -59,27 +97,51 @@ type MockService struct {
 	GetCollectionByIDFunc          func(ctx context.Context, id int64) (*models.Collection, error)
 	GetCollectionBySlugFunc        func(ctx context.Context, slug string) (*models.Collection, error)
 	ListCollectionsFunc            func(ctx context.Context, publicOnly bool) ([]models.Collection, error)
+	ListCollectionTreeFunc         func(ctx context.Context, publicOnly bool) ([]CollectionNode, error)
 	UpdateCollectionPublicFunc     func(ctx context.Context, id int64, isPublic bool) error
+	MoveCollectionFunc             func(ctx context.Context, collectionID int64, afterCollectionID *int64) error
 	GetBookmarksByCollectionIDFunc func(ctx context.Context, collectionID int64) ([]CollectionBookmark, error)
 	GetBoardViewDataFunc           func(ctx context.Context, recentLimit int) (*BoardViewData, error)
 
 	// Tag methods
 	CreateTagFunc         func(ctx context.Context, input models.CreateTagInput) (*models.Tag, error)
 	DeleteTagFunc         func(ctx context.Context, id int64) error
+	MergeTagsFunc         func(ctx context.Context, sourceID, targetID int64) (int, error)
 	GetTagBySlugFunc      func(ctx context.Context, slug string) (*models.Tag, error)
 	ListTagsFunc          func(ctx context.Context) ([]models.Tag, error)
-	GetTagsWithCountsFunc func(ctx context.Context) ([]TagWithCount, error)
+	GetTagsWithCountsFunc func(ctx context.Context, publishedOnly bool) ([]TagWithCount, error)
 	GetPostsByTagIDFunc   func(ctx context.Context, tagID int64) ([]TagPost, error)
 
 	// Stats methods
 	GetDashboardStatsFunc func(ctx context.Context) (*DashboardStats, error)
 
+	// Home methods
+	GetHomeDataFunc func(ctx context.Context) (*HomeData, error)
+
 	// Activity methods
-	LogActivityFunc          func(ctx context.Context, action, entityType string, entityID int64, title string, metadata map[string]interface{}) (*Activity, error)
-	ListRecentActivitiesFunc func(ctx context.Context, limit, offset int) ([]Activity, error)
+	LogActivityFunc           func(ctx context.Context, action, entityType string, entityID int64, title string, metadata map[string]interface{}) (*Activity, error)
+	ListRecentActivitiesFunc  func(ctx context.Context, limit, offset int) ([]Activity, error)
+	ListActivitiesFunc        func(ctx context.Context, filter ActivityFilter, limit, offset int) ([]Activity, error)
+	CountActivitiesFunc       func(ctx context.Context, filter ActivityFilter) (int, error)
+	CountActivitiesByTypeFunc func(ctx context.Context) ([]ActivityTypeCount, error)
 
 	// Metadata methods
 	FetchPageMetadataFunc func(ctx context.Context, url string) (*PageMetadata, error)
+
+	// Image methods
+	CreateImageFunc        func(ctx context.Context, mimeType string, data []byte, sourceURL string) (*models.Image, error)
+	GetImageFunc           func(ctx context.Context, id int64) (*models.Image, error)
+	DeleteImageFunc        func(ctx context.Context, id int64) error
+	StoreBookmarkCoverFunc func(ctx context.Context, bookmarkID int64, mimeType string, data []byte) (*models.Image, error)
+
+	// Search methods
+	SearchFunc                func(ctx context.Context, query string) (SearchResults, error)
+	RecordSearchQueryFunc     func(ctx context.Context, query string, resultCount int) error
+	PruneSearchQueriesFunc    func(ctx context.Context, retention time.Duration) (int64, error)
+	GetZeroResultSearchesFunc func(ctx context.Context, limit int) ([]SearchGap, error)
+
+	// Lifecycle methods
+	ShutdownFunc func(ctx context.Context) error
 }
 
 // Ensure MockService implements ServiceInterface
@@ -117,9 +179,9 @@ func (m *MockService) ValidatePassword(user *models.User, password string) bool
 	return false
 }
 
-func (m *MockService) CreateSession(ctx context.Context, userID int64, duration time.Duration) (*models.Session, error) {
+func (m *MockService) CreateSession(ctx context.Context, userID int64, duration time.Duration, client models.SessionClient) (*models.Session, error) {
 	if m.CreateSessionFunc != nil {
-		return m.CreateSessionFunc(ctx, userID, duration)
+		return m.CreateSessionFunc(ctx, userID, duration, client)
 	}
 	return nil, nil
 }
@@ -138,18 +200,18 @@ func (m *MockService) DeleteSession(ctx context.Context, sessionID string) error
 	return nil
 }
 
-func (m *MockService) RotateSession(ctx context.Context, userID int64, oldSessionID string, duration time.Duration) (*models.Session, error) {
+func (m *MockService) RotateSession(ctx context.Context, userID int64, oldSessionID string, duration time.Duration, client models.SessionClient) (*models.Session, error) {
 	if m.RotateSessionFunc != nil {
-		return m.RotateSessionFunc(ctx, userID, oldSessionID, duration)
+		return m.RotateSessionFunc(ctx, userID, oldSessionID, duration, client)
 	}
 	return nil, nil
 }
 
-func (m *MockService) CleanupExpiredSessions(ctx context.Context) error {
+func (m *MockService) CleanupExpiredSessions(ctx context.Context) (int64, error) {
 	if m.CleanupExpiredSessionsFunc != nil {
 		return m.CleanupExpiredSessionsFunc(ctx)
 	}
-	return nil
+	return 0, nil
 }
 
 func (m *MockService) EnsureAdminExists(ctx context.Context, username, password string) error {
@@ -159,6 +221,62 @@ func (m *MockService) EnsureAdminExists(ctx context.Context, username, password
 	return nil
 }
 
+func (m *MockService) RecordFailedLogin(ctx context.Context, userID int64, maxAttempts int, lockout time.Duration) (bool, error) {
+	if m.RecordFailedLoginFunc != nil {
+		return m.RecordFailedLoginFunc(ctx, userID, maxAttempts, lockout)
+	}
+	return false, nil
+}
+
+func (m *MockService) ResetFailedLogins(ctx context.Context, userID int64) error {
+	if m.ResetFailedLoginsFunc != nil {
+		return m.ResetFailedLoginsFunc(ctx, userID)
+	}
+	return nil
+}
+
+func (m *MockService) ListSessionsForUser(ctx context.Context, userID int64) ([]models.Session, error) {
+	if m.ListSessionsForUserFunc != nil {
+		return m.ListSessionsForUserFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockService) RevokeSession(ctx context.Context, userID int64, sessionID string) error {
+	if m.RevokeSessionFunc != nil {
+		return m.RevokeSessionFunc(ctx, userID, sessionID)
+	}
+	return nil
+}
+
+func (m *MockService) RevokeAllOtherSessions(ctx context.Context, userID int64, currentSessionID string) (int64, error) {
+	if m.RevokeAllOtherSessionsFunc != nil {
+		return m.RevokeAllOtherSessionsFunc(ctx, userID, currentSessionID)
+	}
+	return 0, nil
+}
+
+func (m *MockService) RevokeAllSessions(ctx context.Context, userID int64) (int64, error) {
+	if m.RevokeAllSessionsFunc != nil {
+		return m.RevokeAllSessionsFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *MockService) ChangePassword(ctx context.Context, userID int64, oldPassword, newPassword string) error {
+	if m.ChangePasswordFunc != nil {
+		return m.ChangePasswordFunc(ctx, userID, oldPassword, newPassword)
+	}
+	return nil
+}
+
+func (m *MockService) TouchSession(ctx context.Context, sessionID string, at time.Time) error {
+	if m.TouchSessionFunc != nil {
+		return m.TouchSessionFunc(ctx, sessionID, at)
+	}
+	return nil
+}
+
 // ============================================
 // BOOKMARK SERVICE METHODS
 // ============================================
@@ -198,6 +316,13 @@ func (m *MockService) GetBookmarkByURL(ctx context.Context, url string) (*models
 	return nil, nil
 }
 
+func (m *MockService) BackfillNormalizedURLs(ctx context.Context) (int, error) {
+	if m.BackfillNormalizedURLsFunc != nil {
+		return m.BackfillNormalizedURLsFunc(ctx)
+	}
+	return 0, nil
+}
+
 func (m *MockService) ListBookmarks(ctx context.Context, opts BookmarkListOptions) ([]models.Bookmark, error) {
 	if m.ListBookmarksFunc != nil {
 		return m.ListBookmarksFunc(ctx, opts)
@@ -205,6 +330,13 @@ func (m *MockService) ListBookmarks(ctx context.Context, opts BookmarkListOption
 	return nil, nil
 }
 
+func (m *MockService) ListBookmarksCursor(ctx context.Context, opts BookmarkListOptions, cursor string) (*BookmarkPage, error) {
+	if m.ListBookmarksCursorFunc != nil {
+		return m.ListBookmarksCursorFunc(ctx, opts, cursor)
+	}
+	return nil, nil
+}
+
 func (m *MockService) ListUnsortedBookmarks(ctx context.Context, limit, offset int) ([]models.Bookmark, error) {
 	if m.ListUnsortedBookmarksFunc != nil {
 		return m.ListUnsortedBookmarksFunc(ctx, limit, offset)
@@ -212,6 +344,13 @@ func (m *MockService) ListUnsortedBookmarks(ctx context.Context, limit, offset i
 	return nil, nil
 }
 
+func (m *MockService) CountUnsortedBookmarks(ctx context.Context) (int, error) {
+	if m.CountUnsortedBookmarksFunc != nil {
+		return m.CountUnsortedBookmarksFunc(ctx)
+	}
+	return 0, nil
+}
+
 func (m *MockService) CountBookmarks(ctx context.Context, opts BookmarkListOptions) (int, error) {
 	if m.CountBookmarksFunc != nil {
 		return m.CountBookmarksFunc(ctx, opts)
@@ -233,6 +372,19 @@ func (m *MockService) UpdateBookmarkFavorite(ctx context.Context, id int64, isFa
 	return nil
 }
 
+func (m *MockService) UpdateBookmarkArchived(ctx context.Context, id int64, archived bool) error {
+	if m.UpdateBookmarkArchivedFunc != nil {
+		return m.UpdateBookmarkArchivedFunc(ctx, id, archived)
+	}
+	return nil
+}
+
+func (m *MockService) IncrementBookmarkClick(ctx context.Context, id int64) {
+	if m.IncrementBookmarkClickFunc != nil {
+		m.IncrementBookmarkClickFunc(ctx, id)
+	}
+}
+
 func (m *MockService) MoveBookmark(ctx context.Context, bookmarkID int64, collectionID *int64, afterBookmarkID *int64) error {
 	if m.MoveBookmarkFunc != nil {
 		return m.MoveBookmarkFunc(ctx, bookmarkID, collectionID, afterBookmarkID)
@@ -240,10 +392,17 @@ func (m *MockService) MoveBookmark(ctx context.Context, bookmarkID int64, collec
 	return nil
 }
 
-func (m *MockService) BulkMoveBookmarks(ctx context.Context, bookmarkIDs []int64, collectionID *int64, afterBookmarkID *int64) error {
+func (m *MockService) BulkMoveBookmarks(ctx context.Context, bookmarkIDs []int64, collectionID *int64, afterBookmarkID *int64) (string, error) {
 	if m.BulkMoveBookmarksFunc != nil {
 		return m.BulkMoveBookmarksFunc(ctx, bookmarkIDs, collectionID, afterBookmarkID)
 	}
+	return "", nil
+}
+
+func (m *MockService) UndoBulkMove(ctx context.Context, token string) error {
+	if m.UndoBulkMoveFunc != nil {
+		return m.UndoBulkMoveFunc(ctx, token)
+	}
 	return nil
 }
 
@@ -254,6 +413,20 @@ func (m *MockService) BulkDeleteBookmarks(ctx context.Context, bookmarkIDs []int
 	return nil
 }
 
+func (m *MockService) BulkAddTagToBookmarks(ctx context.Context, bookmarkIDs []int64, tagID int64) error {
+	if m.BulkAddTagToBookmarksFunc != nil {
+		return m.BulkAddTagToBookmarksFunc(ctx, bookmarkIDs, tagID)
+	}
+	return nil
+}
+
+func (m *MockService) BulkRemoveTag(ctx context.Context, bookmarkIDs []int64, tagID int64) error {
+	if m.BulkRemoveTagFunc != nil {
+		return m.BulkRemoveTagFunc(ctx, bookmarkIDs, tagID)
+	}
+	return nil
+}
+
 func (m *MockService) RefreshBookmarkMetadata(ctx context.Context, id int64) error {
 	if m.RefreshBookmarkMetadataFunc != nil {
 		return m.RefreshBookmarkMetadataFunc(ctx, id)
@@ -261,9 +434,9 @@ func (m *MockService) RefreshBookmarkMetadata(ctx context.Context, id int64) err
 	return nil
 }
 
-func (m *MockService) RefreshAllMissingMetadataAsync(progressChan chan<- string) {
+func (m *MockService) RefreshAllMissingMetadataAsync(ctx context.Context, progressChan chan<- string) {
 	if m.RefreshAllMissingMetadataAsyncFunc != nil {
-		m.RefreshAllMissingMetadataAsyncFunc(progressChan)
+		m.RefreshAllMissingMetadataAsyncFunc(ctx, progressChan)
 	}
 }
 
@@ -278,6 +451,48 @@ func (m *MockService) ImportBookmarks(ctx context.Context, bookmarks []ImportedB
 	return nil, nil
 }
 
+func (m *MockService) PreviewImport(ctx context.Context, bookmarks []ImportedBookmark, defaultCollectionID *int64) (*ImportPreview, error) {
+	if m.PreviewImportFunc != nil {
+		return m.PreviewImportFunc(ctx, bookmarks, defaultCollectionID)
+	}
+	return nil, nil
+}
+
+func (m *MockService) CommitImport(ctx context.Context, token string, selected []int) (*ImportResult, error) {
+	if m.CommitImportFunc != nil {
+		return m.CommitImportFunc(ctx, token, selected)
+	}
+	return nil, nil
+}
+
+func (m *MockService) ExportCollectionOPML(ctx context.Context, collectionID int64) (string, error) {
+	if m.ExportCollectionOPMLFunc != nil {
+		return m.ExportCollectionOPMLFunc(ctx, collectionID)
+	}
+	return "", nil
+}
+
+func (m *MockService) ImportCollectionOPML(ctx context.Context, collectionID int64, r io.Reader) (*ImportResult, error) {
+	if m.ImportCollectionOPMLFunc != nil {
+		return m.ImportCollectionOPMLFunc(ctx, collectionID, r)
+	}
+	return nil, nil
+}
+
+func (m *MockService) ExportSite(ctx context.Context) (io.ReadCloser, error) {
+	if m.ExportSiteFunc != nil {
+		return m.ExportSiteFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockService) ImportSite(ctx context.Context, r io.Reader) (*SiteImportResult, error) {
+	if m.ImportSiteFunc != nil {
+		return m.ImportSiteFunc(ctx, r)
+	}
+	return nil, nil
+}
+
 // ============================================
 // POST SERVICE METHODS
 // ============================================
@@ -296,6 +511,13 @@ func (m *MockService) UpdatePost(ctx context.Context, id int64, input models.Upd
 	return nil, nil
 }
 
+func (m *MockService) DuplicatePost(ctx context.Context, id int64) (*models.Post, error) {
+	if m.DuplicatePostFunc != nil {
+		return m.DuplicatePostFunc(ctx, id)
+	}
+	return nil, nil
+}
+
 func (m *MockService) DeletePost(ctx context.Context, id int64) error {
 	if m.DeletePostFunc != nil {
 		return m.DeletePostFunc(ctx, id)
@@ -303,6 +525,34 @@ func (m *MockService) DeletePost(ctx context.Context, id int64) error {
 	return nil
 }
 
+func (m *MockService) ListTrashedPosts(ctx context.Context) ([]models.Post, error) {
+	if m.ListTrashedPostsFunc != nil {
+		return m.ListTrashedPostsFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockService) RestorePost(ctx context.Context, id int64) error {
+	if m.RestorePostFunc != nil {
+		return m.RestorePostFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockService) PurgePost(ctx context.Context, id int64) error {
+	if m.PurgePostFunc != nil {
+		return m.PurgePostFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockService) PurgeTrashedPosts(ctx context.Context, retention time.Duration) (int, error) {
+	if m.PurgeTrashedPostsFunc != nil {
+		return m.PurgeTrashedPostsFunc(ctx, retention)
+	}
+	return 0, nil
+}
+
 func (m *MockService) GetPostByID(ctx context.Context, id int64) (*models.Post, error) {
 	if m.GetPostByIDFunc != nil {
 		return m.GetPostByIDFunc(ctx, id)
@@ -317,6 +567,13 @@ func (m *MockService) GetPostBySlug(ctx context.Context, slug string) (*models.P
 	return nil, nil
 }
 
+func (m *MockService) GenerateUniqueSlug(ctx context.Context, title string, excludeID *int64) (string, error) {
+	if m.GenerateUniqueSlugFunc != nil {
+		return m.GenerateUniqueSlugFunc(ctx, title, excludeID)
+	}
+	return "", nil
+}
+
 func (m *MockService) ListPosts(ctx context.Context, publishedOnly bool, limit, offset int) ([]models.Post, error) {
 	if m.ListPostsFunc != nil {
 		return m.ListPostsFunc(ctx, publishedOnly, limit, offset)
@@ -324,6 +581,20 @@ func (m *MockService) ListPosts(ctx context.Context, publishedOnly bool, limit,
 	return nil, nil
 }
 
+func (m *MockService) CountPosts(ctx context.Context, publishedOnly bool) (int, error) {
+	if m.CountPostsFunc != nil {
+		return m.CountPostsFunc(ctx, publishedOnly)
+	}
+	return 0, nil
+}
+
+func (m *MockService) ListPublishedPostsByTag(ctx context.Context, tagID int64) ([]models.Post, error) {
+	if m.ListPublishedPostsByTagFunc != nil {
+		return m.ListPublishedPostsByTagFunc(ctx, tagID)
+	}
+	return nil, nil
+}
+
 func (m *MockService) UpdatePostDraft(ctx context.Context, id int64, isDraft bool) error {
 	if m.UpdatePostDraftFunc != nil {
 		return m.UpdatePostDraftFunc(ctx, id, isDraft)
@@ -331,6 +602,55 @@ func (m *MockService) UpdatePostDraft(ctx context.Context, id int64, isDraft boo
 	return nil
 }
 
+func (m *MockService) SetPostFeatured(ctx context.Context, id int64, featured bool) error {
+	if m.SetPostFeaturedFunc != nil {
+		return m.SetPostFeaturedFunc(ctx, id, featured)
+	}
+	return nil
+}
+
+func (m *MockService) ListFeaturedPosts(ctx context.Context, limit int) ([]models.Post, error) {
+	if m.ListFeaturedPostsFunc != nil {
+		return m.ListFeaturedPostsFunc(ctx, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockService) PostCardImage(post *models.Post, label string) ([]byte, error) {
+	if m.PostCardImageFunc != nil {
+		return m.PostCardImageFunc(post, label)
+	}
+	return nil, nil
+}
+
+func (m *MockService) GeneratePostPreviewToken(postID int64) string {
+	if m.GeneratePostPreviewTokenFunc != nil {
+		return m.GeneratePostPreviewTokenFunc(postID)
+	}
+	return ""
+}
+
+func (m *MockService) VerifyPostPreviewToken(postID int64, token string) error {
+	if m.VerifyPostPreviewTokenFunc != nil {
+		return m.VerifyPostPreviewTokenFunc(postID, token)
+	}
+	return nil
+}
+
+func (m *MockService) ExportPostMarkdown(ctx context.Context, id int64) (string, error) {
+	if m.ExportPostMarkdownFunc != nil {
+		return m.ExportPostMarkdownFunc(ctx, id)
+	}
+	return "", nil
+}
+
+func (m *MockService) ExportPostsZip(ctx context.Context, w io.Writer) error {
+	if m.ExportPostsZipFunc != nil {
+		return m.ExportPostsZipFunc(ctx, w)
+	}
+	return nil
+}
+
 // ============================================
 // COLLECTION SERVICE METHODS
 // ============================================
@@ -377,6 +697,13 @@ func (m *MockService) ListCollections(ctx context.Context, publicOnly bool) ([]m
 	return nil, nil
 }
 
+func (m *MockService) ListCollectionTree(ctx context.Context, publicOnly bool) ([]CollectionNode, error) {
+	if m.ListCollectionTreeFunc != nil {
+		return m.ListCollectionTreeFunc(ctx, publicOnly)
+	}
+	return nil, nil
+}
+
 func (m *MockService) UpdateCollectionPublic(ctx context.Context, id int64, isPublic bool) error {
 	if m.UpdateCollectionPublicFunc != nil {
 		return m.UpdateCollectionPublicFunc(ctx, id, isPublic)
@@ -384,6 +711,13 @@ func (m *MockService) UpdateCollectionPublic(ctx context.Context, id int64, isPu
 	return nil
 }
 
+func (m *MockService) MoveCollection(ctx context.Context, collectionID int64, afterCollectionID *int64) error {
+	if m.MoveCollectionFunc != nil {
+		return m.MoveCollectionFunc(ctx, collectionID, afterCollectionID)
+	}
+	return nil
+}
+
 func (m *MockService) GetBookmarksByCollectionID(ctx context.Context, collectionID int64) ([]CollectionBookmark, error) {
 	if m.GetBookmarksByCollectionIDFunc != nil {
 		return m.GetBookmarksByCollectionIDFunc(ctx, collectionID)
@@ -416,6 +750,13 @@ func (m *MockService) DeleteTag(ctx context.Context, id int64) error {
 	return nil
 }
 
+func (m *MockService) MergeTags(ctx context.Context, sourceID, targetID int64) (int, error) {
+	if m.MergeTagsFunc != nil {
+		return m.MergeTagsFunc(ctx, sourceID, targetID)
+	}
+	return 0, nil
+}
+
 func (m *MockService) GetTagBySlug(ctx context.Context, slug string) (*models.Tag, error) {
 	if m.GetTagBySlugFunc != nil {
 		return m.GetTagBySlugFunc(ctx, slug)
@@ -430,9 +771,9 @@ func (m *MockService) ListTags(ctx context.Context) ([]models.Tag, error) {
 	return nil, nil
 }
 
-func (m *MockService) GetTagsWithCounts(ctx context.Context) ([]TagWithCount, error) {
+func (m *MockService) GetTagsWithCounts(ctx context.Context, publishedOnly bool) ([]TagWithCount, error) {
 	if m.GetTagsWithCountsFunc != nil {
-		return m.GetTagsWithCountsFunc(ctx)
+		return m.GetTagsWithCountsFunc(ctx, publishedOnly)
 	}
 	return nil, nil
 }
@@ -455,6 +796,17 @@ func (m *MockService) GetDashboardStats(ctx context.Context) (*DashboardStats, e
 	return nil, nil
 }
 
+// ============================================
+// HOME SERVICE METHODS
+// ============================================
+
+func (m *MockService) GetHomeData(ctx context.Context) (*HomeData, error) {
+	if m.GetHomeDataFunc != nil {
+		return m.GetHomeDataFunc(ctx)
+	}
+	return nil, nil
+}
+
 // ============================================
 // ACTIVITY SERVICE METHODS
 // ============================================
@@ -473,6 +825,27 @@ func (m *MockService) ListRecentActivities(ctx context.Context, limit, offset in
 	return nil, nil
 }
 
+func (m *MockService) ListActivities(ctx context.Context, filter ActivityFilter, limit, offset int) ([]Activity, error) {
+	if m.ListActivitiesFunc != nil {
+		return m.ListActivitiesFunc(ctx, filter, limit, offset)
+	}
+	return nil, nil
+}
+
+func (m *MockService) CountActivities(ctx context.Context, filter ActivityFilter) (int, error) {
+	if m.CountActivitiesFunc != nil {
+		return m.CountActivitiesFunc(ctx, filter)
+	}
+	return 0, nil
+}
+
+func (m *MockService) CountActivitiesByType(ctx context.Context) ([]ActivityTypeCount, error) {
+	if m.CountActivitiesByTypeFunc != nil {
+		return m.CountActivitiesByTypeFunc(ctx)
+	}
+	return nil, nil
+}
+
 // ============================================
 // METADATA SERVICE METHODS
 // ============================================
@@ -483,3 +856,102 @@ func (m *MockService) FetchPageMetadata(ctx context.Context, url string) (*PageM
 	}
 	return nil, nil
 }
+
+// ============================================
+// IMAGE SERVICE METHODS
+// ============================================
+
+func (m *MockService) CreateImage(ctx context.Context, mimeType string, data []byte, sourceURL string) (*models.Image, error) {
+	if m.CreateImageFunc != nil {
+		return m.CreateImageFunc(ctx, mimeType, data, sourceURL)
+	}
+	return nil, nil
+}
+
+func (m *MockService) GetImage(ctx context.Context, id int64) (*models.Image, error) {
+	if m.GetImageFunc != nil {
+		return m.GetImageFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockService) DeleteImage(ctx context.Context, id int64) error {
+	if m.DeleteImageFunc != nil {
+		return m.DeleteImageFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockService) StoreBookmarkCover(ctx context.Context, bookmarkID int64, mimeType string, data []byte) (*models.Image, error) {
+	if m.StoreBookmarkCoverFunc != nil {
+		return m.StoreBookmarkCoverFunc(ctx, bookmarkID, mimeType, data)
+	}
+	return nil, nil
+}
+
+// ============================================
+// SEARCH SERVICE METHODS
+// ============================================
+
+func (m *MockService) Search(ctx context.Context, query string) (SearchResults, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, query)
+	}
+	return SearchResults{Query: query}, nil
+}
+
+func (m *MockService) RecordSearchQuery(ctx context.Context, query string, resultCount int) error {
+	if m.RecordSearchQueryFunc != nil {
+		return m.RecordSearchQueryFunc(ctx, query, resultCount)
+	}
+	return nil
+}
+
+func (m *MockService) PruneSearchQueries(ctx context.Context, retention time.Duration) (int64, error) {
+	if m.PruneSearchQueriesFunc != nil {
+		return m.PruneSearchQueriesFunc(ctx, retention)
+	}
+	return 0, nil
+}
+
+func (m *MockService) GetZeroResultSearches(ctx context.Context, limit int) ([]SearchGap, error) {
+	if m.GetZeroResultSearchesFunc != nil {
+		return m.GetZeroResultSearchesFunc(ctx, limit)
+	}
+	return nil, nil
+}
+
+// ============================================
+// LIFECYCLE SERVICE METHODS
+// ============================================
+
+func (m *MockService) Shutdown(ctx context.Context) error {
+	if m.ShutdownFunc != nil {
+		return m.ShutdownFunc(ctx)
+	}
+	return nil
+}
+
+// ============================================
+// COLLECTIONSERVICE SERVICE METHODS
+// ============================================
+
+// ============================================
+// TAGSERVICE SERVICE METHODS
+// ============================================
+
+// ============================================
+// BOOKMARKSERVICE SERVICE METHODS
+// ============================================
+
+// ============================================
+// POSTSERVICE SERVICE METHODS
+// ============================================
+
+// ============================================
+// IMAGESERVICE SERVICE METHODS
+// ============================================
+
+// ============================================
+// USERSERVICE SERVICE METHODS
+// ============================================
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrInvalidOPML is returned by ImportCollectionOPML for files that aren't
+// OPML
+var ErrInvalidOPML = errors.New("not an OPML file")
+
+// opmlDocument is an OPML 2.0 subscription list
+type opmlDocument struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    opmlHead `xml:"head"`
+	Body    opmlBody `xml:"body"`
+}
+
+type opmlHead struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type opmlBody struct {
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// opmlOutline is a feed, or a folder of them when it has children.
+// Readers differ on text vs title and on which URL attribute they set, so
+// both are read and written.
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	URL      string        `xml:"url,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// ExportCollectionOPML writes a collection's bookmarks as an OPML outline,
+// one feed per bookmark with its URL as xmlUrl. Archived bookmarks are
+// left out.
+func (s *Service) ExportCollectionOPML(ctx context.Context, collectionID int64) (string, error) {
+	collection, err := s.GetCollectionByID(ctx, collectionID)
+	if err != nil {
+		return "", err
+	}
+
+	opts := BookmarkListOptions{CollectionID: &collectionID}
+	total, err := s.CountBookmarks(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+	doc := opmlDocument{
+		Version: "2.0",
+		Head: opmlHead{
+			Title:       collection.Name,
+			DateCreated: time.Now().UTC().Format(time.RFC1123Z),
+		},
+	}
+	if total > 0 {
+		opts.Limit = total
+		bookmarks, err := s.ListBookmarks(ctx, opts)
+		if err != nil {
+			return "", err
+		}
+		for _, b := range bookmarks {
+			doc.Body.Outlines = append(doc.Body.Outlines, opmlOutline{
+				Text:   b.Title,
+				Title:  b.Title,
+				Type:   "rss",
+				XMLURL: b.URL,
+			})
+		}
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(out) + "\n", nil
+}
+
+// ParseOPML reads the feeds of an OPML file, flattening folders. A feed's
+// URL is its xmlUrl, falling back to htmlUrl and url; its title is text,
+// falling back to title. Outlines without a URL are skipped.
+func ParseOPML(r io.Reader) ([]ImportedBookmark, error) {
+	var doc opmlDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOPML, err)
+	}
+
+	var bookmarks []ImportedBookmark
+	var walk func([]opmlOutline)
+	walk = func(outlines []opmlOutline) {
+		for _, o := range outlines {
+			walk(o.Outlines)
+
+			url := firstNonEmpty(o.XMLURL, o.HTMLURL, o.URL)
+			if url == "" {
+				continue
+			}
+			bookmarks = append(bookmarks, ImportedBookmark{
+				URL:     url,
+				Title:   firstNonEmpty(o.Text, o.Title),
+				AddedAt: time.Now(),
+			})
+		}
+	}
+	walk(doc.Body.Outlines)
+	return bookmarks, nil
+}
+
+// ImportCollectionOPML creates bookmarks for the feeds in an OPML file, all
+// in the given collection. Feeds already saved are skipped as in
+// ImportBookmarks.
+func (s *Service) ImportCollectionOPML(ctx context.Context, collectionID int64, r io.Reader) (*ImportResult, error) {
+	if _, err := s.GetCollectionByID(ctx, collectionID); err != nil {
+		return nil, err
+	}
+
+	bookmarks, err := ParseOPML(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.ImportBookmarks(ctx, bookmarks, &collectionID)
+}
+
+// firstNonEmpty returns the first of values that isn't blank, trimmed
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
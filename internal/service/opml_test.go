@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestCollectionOPML_RoundTrip(t *testing.T) {
+	src := newTestService(t)
+	ctx := context.Background()
+
+	feeds, err := src.CreateCollection(ctx, models.CreateCollectionInput{Name: "Feeds & Blogs", Slug: "feeds"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unreachable URLs keep the background metadata fetch off the network
+	for _, b := range []models.CreateBookmarkInput{
+		{URL: "http://127.0.0.1:1/a.xml", Title: `Ann's "Notes" <weekly>`, CollectionID: &feeds.ID},
+		{URL: "http://127.0.0.1:1/b.xml?format=rss&v=2", Title: "B", CollectionID: &feeds.ID},
+		{URL: "http://127.0.0.1:1/elsewhere", Title: "Not in the collection"},
+	} {
+		if _, err := src.CreateBookmark(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opml, err := src.ExportCollectionOPML(ctx, feeds.ID)
+	if err != nil {
+		t.Fatalf("ExportCollectionOPML() error = %v", err)
+	}
+	if !strings.Contains(opml, "<title>Feeds &amp; Blogs</title>") || strings.Contains(opml, "elsewhere") {
+		t.Errorf("export = %s", opml)
+	}
+
+	dst := newTestService(t)
+	subs, err := dst.CreateCollection(ctx, models.CreateCollectionInput{Name: "Subscriptions", Slug: "subscriptions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := dst.ImportCollectionOPML(ctx, subs.ID, strings.NewReader(opml))
+	if err != nil {
+		t.Fatalf("ImportCollectionOPML() error = %v", err)
+	}
+	if result.Created != 2 {
+		t.Errorf("result = %+v, want 2 created", *result)
+	}
+
+	want := map[string]string{
+		"http://127.0.0.1:1/a.xml":                `Ann's "Notes" <weekly>`,
+		"http://127.0.0.1:1/b.xml?format=rss&v=2": "B",
+	}
+	for url, title := range want {
+		b, err := dst.GetBookmarkByURL(ctx, url)
+		if err != nil {
+			t.Fatalf("%s not imported: %v", url, err)
+		}
+		if b.Title != title || b.CollectionID.Int64 != subs.ID {
+			t.Errorf("%s = title %q in collection %d, want %q in %d", url, b.Title, b.CollectionID.Int64, title, subs.ID)
+		}
+	}
+}
+
+func TestParseOPML(t *testing.T) {
+	bookmarks, err := ParseOPML(strings.NewReader(`<?xml version="1.0"?>
+<opml version="1.0">
+  <head><title>Reader</title></head>
+  <body>
+    <outline text="Tech">
+      <outline title="Only a title" xmlUrl="https://a.example/feed"/>
+      <outline text="Site" htmlUrl="https://b.example/"/>
+    </outline>
+    <outline text="Folder without feeds"/>
+  </body>
+</opml>`))
+	if err != nil {
+		t.Fatalf("ParseOPML() error = %v", err)
+	}
+	if len(bookmarks) != 2 {
+		t.Fatalf("bookmarks = %+v, want 2", bookmarks)
+	}
+	if bookmarks[0].URL != "https://a.example/feed" || bookmarks[0].Title != "Only a title" {
+		t.Errorf("bookmarks[0] = %+v", bookmarks[0])
+	}
+	if bookmarks[1].URL != "https://b.example/" || bookmarks[1].Title != "Site" {
+		t.Errorf("bookmarks[1] = %+v", bookmarks[1])
+	}
+
+	if _, err := ParseOPML(strings.NewReader("not xml")); !errors.Is(err, ErrInvalidOPML) {
+		t.Errorf("ParseOPML(garbage) error = %v, want ErrInvalidOPML", err)
+	}
+}
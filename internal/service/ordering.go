@@ -0,0 +1,84 @@
+package service
+
+// Gap-based ordering shared by bookmarks and collections. Items are spaced
+// sortGap apart so a move usually rewrites a single row; when two neighbours
+// run out of room the whole list is respaced.
+const (
+	sortGap           = 1000
+	minSortGap        = 1
+	firstSortPosition = 1000
+)
+
+// sortItem is a row's ID and its current sort_order
+type sortItem struct {
+	ID        int64
+	SortOrder int64
+}
+
+// withoutSortItems returns items minus those with the given IDs
+func withoutSortItems(items []sortItem, ids ...int64) []sortItem {
+	skip := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		skip[id] = true
+	}
+	filtered := make([]sortItem, 0, len(items))
+	for _, item := range items {
+		if !skip[item.ID] {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// spreadSortItems returns items renumbered sortGap apart starting at startAt
+func spreadSortItems(items []sortItem, startAt int64) []sortItem {
+	spread := make([]sortItem, len(items))
+	for i, item := range items {
+		spread[i] = sortItem{ID: item.ID, SortOrder: startAt + int64(i)*sortGap}
+	}
+	return spread
+}
+
+// sortOrderAfter calculates the sort_order for an item placed after afterID
+// in items (nil = at the beginning, unknown ID = at the end). items must be
+// in order and must not contain the item being moved. When there is no room
+// left, the returned rebalanced items carry new sort orders that have to be
+// written before the moved item.
+func sortOrderAfter(items []sortItem, afterID *int64) (order int64, rebalanced []sortItem) {
+	if len(items) == 0 {
+		return firstSortPosition, nil
+	}
+
+	if afterID == nil {
+		// Place before the first item
+		if first := items[0].SortOrder; first > minSortGap {
+			return first / 2, nil
+		}
+		// Shift everything down to make room at the front
+		return firstSortPosition, spreadSortItems(items, firstSortPosition+sortGap)
+	}
+
+	afterIndex := -1
+	for i, item := range items {
+		if item.ID == *afterID {
+			afterIndex = i
+			break
+		}
+	}
+
+	if afterIndex == -1 {
+		// Not in this list, append at the end
+		return items[len(items)-1].SortOrder + sortGap, nil
+	}
+	if afterIndex == len(items)-1 {
+		return items[afterIndex].SortOrder + sortGap, nil
+	}
+
+	// Insert between afterIndex and afterIndex+1
+	prev := items[afterIndex].SortOrder
+	if gap := items[afterIndex+1].SortOrder - prev; gap > minSortGap {
+		return prev + gap/2, nil
+	}
+	rebalanced = spreadSortItems(items, firstSortPosition)
+	return rebalanced[afterIndex].SortOrder + sortGap/2, rebalanced
+}
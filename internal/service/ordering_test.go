@@ -0,0 +1,54 @@
+package service
+
+import "testing"
+
+func TestSortOrderAfter(t *testing.T) {
+	items := []sortItem{{ID: 1, SortOrder: 1000}, {ID: 2, SortOrder: 2000}, {ID: 3, SortOrder: 3000}}
+	id := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name           string
+		items          []sortItem
+		afterID        *int64
+		want           int64
+		wantRebalanced []sortItem
+	}{
+		{"empty list", nil, nil, firstSortPosition, nil},
+		{"beginning", items, nil, 500, nil},
+		{"between", items, id(1), 1500, nil},
+		{"end", items, id(3), 4000, nil},
+		{"unknown after ID", items, id(99), 4000, nil},
+		{
+			"beginning without room",
+			[]sortItem{{ID: 1, SortOrder: 1}, {ID: 2, SortOrder: 2}},
+			nil,
+			firstSortPosition,
+			[]sortItem{{ID: 1, SortOrder: 2000}, {ID: 2, SortOrder: 3000}},
+		},
+		{
+			"between without room",
+			[]sortItem{{ID: 1, SortOrder: 10}, {ID: 2, SortOrder: 11}, {ID: 3, SortOrder: 12}},
+			id(1),
+			1500,
+			[]sortItem{{ID: 1, SortOrder: 1000}, {ID: 2, SortOrder: 2000}, {ID: 3, SortOrder: 3000}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rebalanced := sortOrderAfter(tt.items, tt.afterID)
+			if got != tt.want {
+				t.Errorf("order = %d, want %d", got, tt.want)
+			}
+			if len(rebalanced) != len(tt.wantRebalanced) {
+				t.Fatalf("rebalanced = %v, want %v", rebalanced, tt.wantRebalanced)
+			}
+			for i := range rebalanced {
+				if rebalanced[i] != tt.wantRebalanced[i] {
+					t.Errorf("rebalanced = %v, want %v", rebalanced, tt.wantRebalanced)
+					break
+				}
+			}
+		})
+	}
+}
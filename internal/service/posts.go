@@ -3,14 +3,19 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
 	"github.com/EC-9624/0xec.dev/internal/models"
 )
 
-// CreatePost creates a new post
+// CreatePost creates a new post. A blank excerpt is derived from the content.
 func (s *Service) CreatePost(ctx context.Context, input models.CreatePostInput) (*models.Post, error) {
+	if input.Excerpt == "" {
+		input.Excerpt = deriveExcerpt(input.Content)
+	}
+
 	var publishedAt *time.Time
 	var isDraft int64 = 1
 	if !input.IsDraft {
@@ -18,6 +23,11 @@ func (s *Service) CreatePost(ctx context.Context, input models.CreatePostInput)
 		now := time.Now()
 		publishedAt = &now
 	}
+	if input.IsFeatured {
+		if err := s.checkCanFeature(ctx, input.IsDraft); err != nil {
+			return nil, err
+		}
+	}
 
 	post, err := s.queries.CreatePost(ctx, db.CreatePostParams{
 		Title:       input.Title,
@@ -32,6 +42,12 @@ func (s *Service) CreatePost(ctx context.Context, input models.CreatePostInput)
 		return nil, err
 	}
 
+	if input.IsFeatured {
+		if err := s.queries.UpdatePostFeatured(ctx, db.UpdatePostFeaturedParams{IsFeatured: 1, ID: post.ID}); err != nil {
+			return nil, err
+		}
+	}
+
 	// Add tags
 	if len(input.TagIDs) > 0 {
 		if err := s.setPostTags(ctx, post.ID, input.TagIDs); err != nil {
@@ -45,13 +61,19 @@ func (s *Service) CreatePost(ctx context.Context, input models.CreatePostInput)
 	return s.GetPostByID(ctx, post.ID)
 }
 
-// UpdatePost updates an existing post
+// UpdatePost updates an existing post. A blank excerpt is derived from the
+// content. A replaced stored cover image is deleted once no other post uses
+// it.
 func (s *Service) UpdatePost(ctx context.Context, id int64, input models.UpdatePostInput) (*models.Post, error) {
 	post, err := s.GetPostByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	if input.Excerpt == "" {
+		input.Excerpt = deriveExcerpt(input.Content)
+	}
+
 	var publishedAt *time.Time
 	var isDraft int64 = 1
 	if !input.IsDraft {
@@ -66,6 +88,15 @@ func (s *Service) UpdatePost(ctx context.Context, id int64, input models.UpdateP
 		}
 	}
 
+	// Featuring is checked only when it changes; moving a featured post
+	// back to drafts unfeatures it
+	featured := input.IsFeatured && !input.IsDraft
+	if input.IsFeatured && !post.IsFeatured {
+		if err := s.checkCanFeature(ctx, input.IsDraft); err != nil {
+			return nil, err
+		}
+	}
+
 	err = s.queries.UpdatePost(ctx, db.UpdatePostParams{
 		Title:       input.Title,
 		Slug:        input.Slug,
@@ -80,6 +111,16 @@ func (s *Service) UpdatePost(ctx context.Context, id int64, input models.UpdateP
 		return nil, err
 	}
 
+	if featured != post.IsFeatured {
+		if err := s.queries.UpdatePostFeatured(ctx, db.UpdatePostFeaturedParams{IsFeatured: *boolToInt64Ptr(featured), ID: id}); err != nil {
+			return nil, err
+		}
+	}
+
+	if old := post.GetCoverImage(); old != input.CoverImage {
+		s.deleteUnusedImagePath(ctx, old)
+	}
+
 	// Update tags
 	if err := s.setPostTags(ctx, id, input.TagIDs); err != nil {
 		return nil, err
@@ -96,32 +137,156 @@ func (s *Service) UpdatePost(ctx context.Context, id int64, input models.UpdateP
 	return s.GetPostByID(ctx, id)
 }
 
-// DeletePost deletes a post
+// DuplicatePost copies a post into a new draft titled "<title> (copy)"
+// under a fresh unique slug. Content, excerpt, cover image, and tags are
+// copied; published state and featuring are not.
+func (s *Service) DuplicatePost(ctx context.Context, id int64) (*models.Post, error) {
+	post, err := s.GetPostByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	title := post.Title + " (copy)"
+	slug, err := s.GenerateUniqueSlug(ctx, title, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIDs := make([]int64, 0, len(post.Tags))
+	for _, tag := range post.Tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	return s.CreatePost(ctx, models.CreatePostInput{
+		Title:      title,
+		Slug:       slug,
+		Content:    post.Content,
+		Excerpt:    post.Excerpt.String,
+		CoverImage: post.CoverImage.String,
+		IsDraft:    true,
+		TagIDs:     tagIDs,
+	})
+}
+
+// DeletePost moves a post to the trash. It disappears from every listing
+// and page but can be restored until it is purged.
 func (s *Service) DeletePost(ctx context.Context, id int64) error {
-	// Get post title for activity log before deleting
-	post, _ := s.GetPostByID(ctx, id)
-	title := ""
-	if post != nil {
-		title = post.Title
+	post, err := s.GetPostByID(ctx, id)
+	if err != nil {
+		return err
 	}
 
-	err := s.queries.DeletePost(ctx, id)
+	now := time.Now()
+	n, err := s.queries.TrashPost(ctx, db.TrashPostParams{DeletedAt: &now, ID: id})
 	if err != nil {
 		return err
 	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
 
 	// Log activity
-	s.LogActivity(ctx, ActionPostDeleted, EntityPost, id, title, nil)
+	s.LogActivity(ctx, ActionPostDeleted, EntityPost, id, post.Title, nil)
 
 	return nil
 }
 
-// GetPostByID retrieves a post by ID
+// ListTrashedPosts returns the posts in the trash, most recently deleted first
+func (s *Service) ListTrashedPosts(ctx context.Context) ([]models.Post, error) {
+	posts, err := s.queries.ListTrashedPosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Post, 0, len(posts))
+	for _, p := range posts {
+		tags, err := s.queries.GetPostTags(ctx, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *dbPostToModel(p, tags))
+	}
+
+	return result, nil
+}
+
+// RestorePost takes a post out of the trash. It returns sql.ErrNoRows if
+// the post isn't in the trash.
+func (s *Service) RestorePost(ctx context.Context, id int64) error {
+	n, err := s.queries.RestorePost(ctx, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	title := ""
+	if post, err := s.queries.GetPostByID(ctx, id); err == nil {
+		title = post.Title
+	}
+	s.LogActivity(ctx, ActionPostRestored, EntityPost, id, title, nil)
+
+	return nil
+}
+
+// PurgePost permanently deletes a post from the trash, along with its
+// activity history. It returns sql.ErrNoRows if the post isn't in the trash.
+func (s *Service) PurgePost(ctx context.Context, id int64) error {
+	current, _ := s.queries.GetPostByID(ctx, id)
+
+	n, err := s.queries.PurgePost(ctx, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := s.queries.DeleteActivitiesForEntity(ctx, db.DeleteActivitiesForEntityParams{
+		EntityType: EntityPost,
+		EntityID:   &id,
+	}); err != nil {
+		return err
+	}
+	if current.CoverImage != nil {
+		s.deleteUnusedImagePath(ctx, *current.CoverImage)
+	}
+	return nil
+}
+
+// PurgeTrashedPosts permanently deletes posts that have been in the trash
+// longer than retention and returns how many were removed
+func (s *Service) PurgeTrashedPosts(ctx context.Context, retention time.Duration) (int, error) {
+	cutoff := time.Now().Add(-retention)
+	ids, err := s.queries.ListPostsTrashedBefore(ctx, &cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		if err := s.PurgePost(ctx, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				// Restored or purged since the listing
+				continue
+			}
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// GetPostByID retrieves a post by ID. Posts in the trash are not found.
 func (s *Service) GetPostByID(ctx context.Context, id int64) (*models.Post, error) {
 	post, err := s.queries.GetPostByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if post.DeletedAt != nil {
+		return nil, sql.ErrNoRows
+	}
 
 	tags, err := s.queries.GetPostTags(ctx, id)
 	if err != nil {
@@ -131,12 +296,15 @@ func (s *Service) GetPostByID(ctx context.Context, id int64) (*models.Post, erro
 	return dbPostToModel(post, tags), nil
 }
 
-// GetPostBySlug retrieves a post by slug
+// GetPostBySlug retrieves a post by slug. Posts in the trash are not found.
 func (s *Service) GetPostBySlug(ctx context.Context, slug string) (*models.Post, error) {
 	post, err := s.queries.GetPostBySlug(ctx, slug)
 	if err != nil {
 		return nil, err
 	}
+	if post.DeletedAt != nil {
+		return nil, sql.ErrNoRows
+	}
 
 	tags, err := s.queries.GetPostTags(ctx, post.ID)
 	if err != nil {
@@ -178,6 +346,37 @@ func (s *Service) ListPosts(ctx context.Context, publishedOnly bool, limit, offs
 	return result, nil
 }
 
+// CountPosts returns the number of posts ListPosts pages through
+func (s *Service) CountPosts(ctx context.Context, publishedOnly bool) (int, error) {
+	var count int64
+	var err error
+	if publishedOnly {
+		count, err = s.queries.CountPublishedPosts(ctx)
+	} else {
+		count, err = s.queries.CountAllPosts(ctx)
+	}
+	return int(count), err
+}
+
+// ListPublishedPostsByTag retrieves published posts with the given tag, newest first
+func (s *Service) ListPublishedPostsByTag(ctx context.Context, tagID int64) ([]models.Post, error) {
+	posts, err := s.queries.ListPublishedPostsByTag(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Post, 0, len(posts))
+	for _, p := range posts {
+		tags, err := s.queries.GetPostTags(ctx, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *dbPostToModel(p, tags))
+	}
+
+	return result, nil
+}
+
 // setPostTags replaces all tags for a post
 func (s *Service) setPostTags(ctx context.Context, postID int64, tagIDs []int64) error {
 	if err := s.queries.DeletePostTags(ctx, postID); err != nil {
@@ -207,8 +406,10 @@ func dbPostToModel(p db.Post, tags []db.Tag) *models.Post {
 		CoverImage:  toNullString(p.CoverImage),
 		PublishedAt: toNullTime(p.PublishedAt),
 		IsDraft:     derefInt64(p.IsDraft) == 1,
+		IsFeatured:  p.IsFeatured == 1,
 		CreatedAt:   derefTime(p.CreatedAt),
 		UpdatedAt:   derefTime(p.UpdatedAt),
+		DeletedAt:   toNullTime(p.DeletedAt),
 	}
 
 	post.Tags = make([]models.Tag, 0, len(tags))
@@ -225,7 +426,7 @@ func dbPostToModel(p db.Post, tags []db.Tag) *models.Post {
 
 // UpdatePostDraft updates the draft status of a post
 // When publishing: sets is_draft=0 and published_at=now
-// When unpublishing: sets is_draft=1 and clears published_at
+// When unpublishing: sets is_draft=1, clears published_at and unfeatures it
 func (s *Service) UpdatePostDraft(ctx context.Context, id int64, isDraft bool) error {
 	post, err := s.GetPostByID(ctx, id)
 	if err != nil {
@@ -254,6 +455,11 @@ func (s *Service) UpdatePostDraft(ctx context.Context, id int64, isDraft bool) e
 	if err != nil {
 		return err
 	}
+	if isDraft && post.IsFeatured {
+		if err := s.queries.UpdatePostFeatured(ctx, db.UpdatePostFeaturedParams{ID: id}); err != nil {
+			return err
+		}
+	}
 
 	// Log activity
 	if !isDraft {
@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+// createTestPost creates a published post with the given slug
+func createTestPost(t *testing.T, s *Service, slug string) *models.Post {
+	t.Helper()
+	post, err := s.CreatePost(context.Background(), models.CreatePostInput{
+		Title:   "Post " + slug,
+		Slug:    slug,
+		Content: "Some content",
+	})
+	if err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+	return post
+}
+
+// countPostActivities returns how many activities are logged for a post
+func countPostActivities(t *testing.T, s *Service, postID int64) int {
+	t.Helper()
+	activities, err := s.ListActivities(context.Background(), ActivityFilter{EntityType: EntityPost}, 100, 0)
+	if err != nil {
+		t.Fatalf("ListActivities: %v", err)
+	}
+	n := 0
+	for _, a := range activities {
+		if a.EntityID == postID {
+			n++
+		}
+	}
+	return n
+}
+
+func TestDeletePost_MovesToTrash(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	kept := createTestPost(t, s, "kept")
+	trashed := createTestPost(t, s, "trashed")
+
+	if err := s.DeletePost(ctx, trashed.ID); err != nil {
+		t.Fatalf("DeletePost: %v", err)
+	}
+
+	for _, publishedOnly := range []bool{true, false} {
+		posts, err := s.ListPosts(ctx, publishedOnly, 10, 0)
+		if err != nil {
+			t.Fatalf("ListPosts: %v", err)
+		}
+		if len(posts) != 1 || posts[0].ID != kept.ID {
+			t.Errorf("ListPosts(publishedOnly=%v) = %v, want only the kept post", publishedOnly, posts)
+		}
+		if count, _ := s.CountPosts(ctx, publishedOnly); count != 1 {
+			t.Errorf("CountPosts(publishedOnly=%v) = %d, want 1", publishedOnly, count)
+		}
+	}
+
+	if _, err := s.GetPostBySlug(ctx, "trashed"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetPostBySlug on trashed post: err = %v, want sql.ErrNoRows", err)
+	}
+
+	trash, err := s.ListTrashedPosts(ctx)
+	if err != nil {
+		t.Fatalf("ListTrashedPosts: %v", err)
+	}
+	if len(trash) != 1 || trash[0].ID != trashed.ID || !trash[0].DeletedAt.Valid {
+		t.Errorf("ListTrashedPosts = %v, want the trashed post with DeletedAt set", trash)
+	}
+
+	// Soft delete keeps the post's history
+	if n := countPostActivities(t, s, trashed.ID); n != 2 {
+		t.Errorf("activities after soft delete = %d, want 2 (created, deleted)", n)
+	}
+
+	// The slug stays taken while the post is in the trash
+	slug, err := s.GenerateUniqueSlug(ctx, "trashed", nil)
+	if err != nil || slug != "trashed-2" {
+		t.Errorf("GenerateUniqueSlug = %q, %v; want trashed-2", slug, err)
+	}
+}
+
+func TestRestorePost(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	post := createTestPost(t, s, "restored")
+
+	if err := s.RestorePost(ctx, post.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("RestorePost on live post: err = %v, want sql.ErrNoRows", err)
+	}
+
+	if err := s.DeletePost(ctx, post.ID); err != nil {
+		t.Fatalf("DeletePost: %v", err)
+	}
+	if err := s.RestorePost(ctx, post.ID); err != nil {
+		t.Fatalf("RestorePost: %v", err)
+	}
+
+	if _, err := s.GetPostBySlug(ctx, "restored"); err != nil {
+		t.Errorf("GetPostBySlug after restore: %v", err)
+	}
+	if trash, _ := s.ListTrashedPosts(ctx); len(trash) != 0 {
+		t.Errorf("trash has %d posts after restore, want 0", len(trash))
+	}
+}
+
+func TestPurgePost(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	post := createTestPost(t, s, "purged")
+
+	if err := s.PurgePost(ctx, post.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("PurgePost on live post: err = %v, want sql.ErrNoRows", err)
+	}
+
+	if err := s.DeletePost(ctx, post.ID); err != nil {
+		t.Fatalf("DeletePost: %v", err)
+	}
+	if err := s.PurgePost(ctx, post.ID); err != nil {
+		t.Fatalf("PurgePost: %v", err)
+	}
+
+	if _, err := s.queries.GetPostByID(ctx, post.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("post row still exists after purge: err = %v", err)
+	}
+	if n := countPostActivities(t, s, post.ID); n != 0 {
+		t.Errorf("activities after purge = %d, want 0", n)
+	}
+}
+
+func TestPurgePost_DeletesUploadedCover(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	image, err := s.CreateImage(ctx, "image/png", []byte("png"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := s.CreatePost(ctx, models.CreatePostInput{
+		Title:      "Hello",
+		Slug:       "hello",
+		Content:    "Some content",
+		CoverImage: models.ImagePath(image.ID),
+		IsDraft:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.DeletePost(ctx, post.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetImage(ctx, image.ID); err != nil {
+		t.Fatalf("cover deleted while the post is only in the trash: %v", err)
+	}
+	if err := s.PurgePost(ctx, post.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetImage(ctx, image.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetImage(cover) after purge error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestPurgeTrashedPosts_Retention(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	old := createTestPost(t, s, "old")
+	recent := createTestPost(t, s, "recent")
+
+	longAgo := time.Now().Add(-40 * 24 * time.Hour)
+	if _, err := s.queries.TrashPost(ctx, db.TrashPostParams{DeletedAt: &longAgo, ID: old.ID}); err != nil {
+		t.Fatalf("TrashPost: %v", err)
+	}
+	if err := s.DeletePost(ctx, recent.ID); err != nil {
+		t.Fatalf("DeletePost: %v", err)
+	}
+
+	n, err := s.PurgeTrashedPosts(ctx, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("PurgeTrashedPosts: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("purged %d posts, want 1", n)
+	}
+
+	trash, _ := s.ListTrashedPosts(ctx)
+	if len(trash) != 1 || trash[0].ID != recent.ID {
+		t.Errorf("trash = %v, want only the recently deleted post", trash)
+	}
+}
+
+func TestSetPostFeatured_DraftRejected(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	draft, err := s.CreatePost(ctx, models.CreatePostInput{
+		Title:   "Draft",
+		Slug:    "draft",
+		Content: "Some content",
+		IsDraft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+
+	if err := s.SetPostFeatured(ctx, draft.ID, true); !errors.Is(err, ErrFeaturedDraft) {
+		t.Errorf("SetPostFeatured on draft: err = %v, want ErrFeaturedDraft", err)
+	}
+
+	_, err = s.CreatePost(ctx, models.CreatePostInput{
+		Title:      "Featured draft",
+		Slug:       "featured-draft",
+		Content:    "Some content",
+		IsDraft:    true,
+		IsFeatured: true,
+	})
+	if !errors.Is(err, ErrFeaturedDraft) {
+		t.Errorf("CreatePost featured draft: err = %v, want ErrFeaturedDraft", err)
+	}
+
+	featured, err := s.ListFeaturedPosts(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListFeaturedPosts: %v", err)
+	}
+	if len(featured) != 0 {
+		t.Errorf("ListFeaturedPosts = %v, want none", featured)
+	}
+}
+
+func TestDuplicatePost(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	tag, err := s.CreateTag(ctx, models.CreateTagInput{Name: "Go", Slug: "go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, err := s.CreatePost(ctx, models.CreatePostInput{
+		Title:      "Hello",
+		Slug:       "hello",
+		Content:    "Some content",
+		Excerpt:    "Short",
+		CoverImage: "https://example.com/cover.png",
+		IsFeatured: true,
+		TagIDs:     []int64{tag.ID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone, err := s.DuplicatePost(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("DuplicatePost() error = %v", err)
+	}
+	if clone.ID == original.ID || clone.Slug == original.Slug {
+		t.Errorf("clone = id %d slug %q, want a new post with its own slug", clone.ID, clone.Slug)
+	}
+	if clone.Title != "Hello (copy)" || clone.Content != original.Content || clone.GetExcerpt() != "Short" || clone.GetCoverImage() != original.GetCoverImage() {
+		t.Errorf("clone = %+v, want copied fields", clone)
+	}
+	if !clone.IsDraft || clone.PublishedAt.Valid || clone.IsFeatured {
+		t.Errorf("clone draft=%v published_at=%v featured=%v, want an unfeatured draft", clone.IsDraft, clone.PublishedAt, clone.IsFeatured)
+	}
+	if len(clone.Tags) != 1 || clone.Tags[0].ID != tag.ID {
+		t.Errorf("clone tags = %+v, want [go]", clone.Tags)
+	}
+	if n := countPostActivities(t, s, clone.ID); n != 1 {
+		t.Errorf("clone activities = %d, want 1", n)
+	}
+
+	// A second copy gets a slug of its own too
+	again, err := s.DuplicatePost(ctx, original.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Slug == clone.Slug {
+		t.Errorf("second clone reused slug %q", again.Slug)
+	}
+}
+
+func TestSetPostFeatured_Limit(t *testing.T) {
+	s := newTestService(t)
+	s.SetFeaturedPostsMax(2)
+	ctx := context.Background()
+	a := createTestPost(t, s, "a")
+	b := createTestPost(t, s, "b")
+	c := createTestPost(t, s, "c")
+
+	for _, p := range []*models.Post{a, b} {
+		if err := s.SetPostFeatured(ctx, p.ID, true); err != nil {
+			t.Fatalf("SetPostFeatured(%s): %v", p.Slug, err)
+		}
+	}
+	if err := s.SetPostFeatured(ctx, c.ID, true); !errors.Is(err, ErrFeaturedLimit) {
+		t.Errorf("SetPostFeatured past the cap: err = %v, want ErrFeaturedLimit", err)
+	}
+
+	// Re-featuring an already featured post doesn't count against the cap
+	if err := s.SetPostFeatured(ctx, a.ID, true); err != nil {
+		t.Errorf("SetPostFeatured on featured post: %v", err)
+	}
+
+	if err := s.SetPostFeatured(ctx, a.ID, false); err != nil {
+		t.Fatalf("SetPostFeatured(false): %v", err)
+	}
+	if err := s.SetPostFeatured(ctx, c.ID, true); err != nil {
+		t.Errorf("SetPostFeatured after freeing a slot: %v", err)
+	}
+
+	featured, err := s.ListFeaturedPosts(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListFeaturedPosts: %v", err)
+	}
+	if len(featured) != 2 {
+		t.Errorf("ListFeaturedPosts returned %d posts, want 2", len(featured))
+	}
+}
+
+func TestUpdatePostDraft_Unfeatures(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	post := createTestPost(t, s, "unpublished")
+	if err := s.SetPostFeatured(ctx, post.ID, true); err != nil {
+		t.Fatalf("SetPostFeatured: %v", err)
+	}
+
+	if err := s.UpdatePostDraft(ctx, post.ID, true); err != nil {
+		t.Fatalf("UpdatePostDraft: %v", err)
+	}
+
+	got, err := s.GetPostByID(ctx, post.ID)
+	if err != nil {
+		t.Fatalf("GetPostByID: %v", err)
+	}
+	if got.IsFeatured {
+		t.Error("unpublished post is still featured")
+	}
+}
+
+func TestUpdatePost_ReplacedCoverImageDeleted(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	image, err := s.CreateImage(ctx, "image/png", []byte("png"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := s.CreatePost(ctx, models.CreatePostInput{
+		Title:      "Hello",
+		Slug:       "hello",
+		Content:    "Some content",
+		CoverImage: models.ImagePath(image.ID),
+		IsDraft:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone, err := s.DuplicatePost(ctx, post.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := func(p *models.Post, cover string) {
+		t.Helper()
+		if _, err := s.UpdatePost(ctx, p.ID, models.UpdatePostInput{
+			Title:      p.Title,
+			Slug:       p.Slug,
+			Content:    p.Content,
+			CoverImage: cover,
+			IsDraft:    true,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The copy still uses the cover, so replacing it on one post keeps it
+	update(post, "https://example.com/new.png")
+	if _, err := s.GetImage(ctx, image.ID); err != nil {
+		t.Fatalf("shared cover deleted while still in use: %v", err)
+	}
+
+	update(clone, "")
+	if _, err := s.GetImage(ctx, image.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetImage() error = %v once unused, want sql.ErrNoRows", err)
+	}
+}
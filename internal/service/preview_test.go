@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPostPreviewToken(t *testing.T) {
+	svc := &Service{previewSecret: []byte("test-secret")}
+	now := time.Now()
+	token := svc.signPreviewToken(42, now.Add(time.Hour))
+
+	other := &Service{previewSecret: []byte("another-secret")}
+
+	tests := []struct {
+		name    string
+		svc     *Service
+		postID  int64
+		token   string
+		now     time.Time
+		wantErr bool
+	}{
+		{"valid", svc, 42, token, now, false},
+		{"other post", svc, 43, token, now, true},
+		{"expired", svc, 42, token, now.Add(2 * time.Hour), true},
+		{"other secret", other, 42, token, now, true},
+		{"tampered expiry", svc, 42, "9999999999" + token[len("9999999999"):], now, true},
+		{"malformed", svc, 42, "not-a-token", now, true},
+		{"empty", svc, 42, "", now, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.svc.verifyPreviewToken(tt.postID, tt.token, tt.now)
+			if tt.wantErr && !errors.Is(err, ErrInvalidPreviewToken) {
+				t.Errorf("verifyPreviewToken() error = %v, want ErrInvalidPreviewToken", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("verifyPreviewToken() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestGeneratePostPreviewToken(t *testing.T) {
+	svc := New(nil)
+	svc.SetPreviewSecret("configured-secret")
+
+	token := svc.GeneratePostPreviewToken(7)
+	if err := svc.VerifyPostPreviewToken(7, token); err != nil {
+		t.Fatalf("VerifyPostPreviewToken() error = %v", err)
+	}
+
+	svc.SetPreviewSecret("rotated-secret")
+	if err := svc.VerifyPostPreviewToken(7, token); err == nil {
+		t.Error("expected token to stop validating after the secret changes")
+	}
+}
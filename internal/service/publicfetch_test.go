@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"192.168.0.10", false},
+		{"172.16.5.4", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := isPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestDownloadAndStoreImage_RefusesInternalAddresses(t *testing.T) {
+	served := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(encodeTestImage(t, "png", 2, 2))
+	}))
+	defer server.Close()
+
+	svc := newTestService(t)
+	ctx := context.Background()
+	bookmark, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+		URL:   "https://example.com/page",
+		Title: "Page",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = svc.DownloadAndStoreImage(ctx, bookmark.ID, server.URL+"/cover.png")
+	if !errors.Is(err, errNonPublicAddress) {
+		t.Errorf("DownloadAndStoreImage() error = %v, want errNonPublicAddress", err)
+	}
+	if served {
+		t.Error("the loopback server was contacted")
+	}
+}
+
+func TestDownloadAndStoreImage_RefusesNamesResolvingInternal(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	bookmark, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+		URL:   "https://example.com/page",
+		Title: "Page",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Hostnames are checked by the address they resolve to at dial time
+	_, err = svc.DownloadAndStoreImage(ctx, bookmark.ID, "http://localhost:1/cover.png")
+	if !errors.Is(err, errNonPublicAddress) {
+		t.Errorf("DownloadAndStoreImage() error = %v, want errNonPublicAddress", err)
+	}
+}
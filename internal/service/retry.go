@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+)
+
+// retryPolicy retries HTTP requests that fail transiently, backing off
+// exponentially with jitter between attempts
+type retryPolicy struct {
+	attempts  int
+	baseDelay time.Duration
+}
+
+// defaultMetadataRetry is used until SetMetadataRetry is called
+var defaultMetadataRetry = retryPolicy{attempts: 3, baseDelay: 500 * time.Millisecond}
+
+// SetMetadataRetry sets how many times a metadata fetch is attempted and
+// the delay before the first retry. Attempts below 1 are treated as 1.
+func (s *Service) SetMetadataRetry(attempts int, baseDelay time.Duration) {
+	s.metadataRetry = retryPolicy{attempts: max(attempts, 1), baseDelay: max(baseDelay, 0)}
+}
+
+// do sends req, retrying timeouts and 5xx responses. The last response is
+// returned as is, even if it's a 5xx. Retries stop when ctx is done.
+func (p retryPolicy) do(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := client.Do(req)
+		if attempt >= p.attempts || ctx.Err() != nil || !retryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the wait before retry number attempt: the base delay
+// doubled per attempt, randomized by up to half either way
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	delay := p.baseDelay << (attempt - 1)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay)
+}
+
+// retryable reports whether a request outcome is worth retrying: timeouts
+// and server errors are, client errors and other failures aren't
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+	return resp.StatusCode >= 500
+}
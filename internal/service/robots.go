@@ -0,0 +1,234 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRobotsDisallowed is returned when a site's robots.txt forbids fetching
+// the page
+var ErrRobotsDisallowed = errors.New("robots.txt disallows fetching this page")
+
+// robotsAgent is the token matched against robots.txt user-agent lines.
+// Groups for "*" apply when no group names it.
+const robotsAgent = "0xecbot"
+
+// robotsCacheTTL is how long a host's robots.txt rules are reused
+const robotsCacheTTL = 30 * time.Minute
+
+// robotsFailureTTL is how long a robots.txt that couldn't be fetched counts
+// as allowing everything before it is tried again
+const robotsFailureTTL = time.Minute
+
+// robotsCacheMaxHosts bounds how many hosts' rules are cached at once
+const robotsCacheMaxHosts = 1000
+
+// robotsMaxSize caps how much of a robots.txt is read
+const robotsMaxSize = 512 * 1024
+
+// robotsRule is one Allow or Disallow line
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// robotsEntry is a host's cached rules
+type robotsEntry struct {
+	rules   []robotsRule
+	expires time.Time
+}
+
+// robotsCache holds robots.txt rules per scheme and host
+type robotsCache struct {
+	mu      sync.Mutex
+	entries map[string]robotsEntry
+}
+
+// get returns the cached rules for key, if they haven't expired
+func (c *robotsCache) get(key string, now time.Time) ([]robotsRule, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		return nil, false
+	}
+	return entry.rules, true
+}
+
+// put caches rules for key until ttl has passed. When the cache is full,
+// expired entries are dropped first, then the one closest to expiring.
+func (c *robotsCache) put(key string, rules []robotsRule, ttl time.Duration, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]robotsEntry)
+	}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= robotsCacheMaxHosts {
+		var soonest string
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			} else if soonest == "" || e.expires.Before(c.entries[soonest].expires) {
+				soonest = k
+			}
+		}
+		if len(c.entries) >= robotsCacheMaxHosts {
+			delete(c.entries, soonest)
+		}
+	}
+	c.entries[key] = robotsEntry{rules: rules, expires: now.Add(ttl)}
+}
+
+// SetRespectRobots turns robots.txt checks before metadata fetches on or off
+func (s *Service) SetRespectRobots(enabled bool) {
+	s.respectRobots = enabled
+}
+
+// checkRobots returns ErrRobotsDisallowed if the target site's robots.txt
+// forbids fetching rawURL. A missing or unreachable robots.txt allows
+// everything; an unreachable one is retried after robotsFailureTTL.
+func (s *Service) checkRobots(ctx context.Context, client *http.Client, rawURL string) error {
+	if !s.respectRobots {
+		return nil
+	}
+	target, err := url.Parse(rawURL)
+	if err != nil || target.Host == "" {
+		return nil
+	}
+
+	key := target.Scheme + "://" + target.Host
+	rules, ok := s.robots.get(key, time.Now())
+	if !ok {
+		var fetched bool
+		rules, fetched = fetchRobots(ctx, client, key+"/robots.txt")
+		ttl := robotsCacheTTL
+		if !fetched {
+			ttl = robotsFailureTTL
+		}
+		s.robots.put(key, rules, ttl, time.Now())
+	}
+
+	path := target.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if target.RawQuery != "" {
+		path += "?" + target.RawQuery
+	}
+	if !robotsAllowed(rules, path) {
+		return ErrRobotsDisallowed
+	}
+	return nil
+}
+
+// fetchRobots downloads and parses a robots.txt. A site without one has no
+// rules; fetched is false when the site couldn't be asked, because the
+// request failed or the server returned an error.
+func fetchRobots(ctx context.Context, client *http.Client, robotsURL string) (rules []robotsRule, fetched bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, false
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, false
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, true
+	}
+	return parseRobots(io.LimitReader(resp.Body, robotsMaxSize), robotsAgent), true
+}
+
+// parseRobots returns the rules that apply to agent: those of every group
+// naming it, or of the "*" groups if none do
+func parseRobots(r io.Reader, agent string) []robotsRule {
+	agent = strings.ToLower(agent)
+
+	var specific, wildcard []robotsRule
+	var groupAgents []string
+	inAgents := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Consecutive user-agent lines share the rules that follow
+			if !inAgents {
+				groupAgents = nil
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+			inAgents = true
+		case "allow", "disallow":
+			inAgents = false
+			if value == "" {
+				continue
+			}
+			rule := robotsRule{allow: key == "allow", pattern: value}
+			for _, a := range groupAgents {
+				if a == "*" {
+					wildcard = append(wildcard, rule)
+				} else if strings.Contains(agent, a) {
+					specific = append(specific, rule)
+				}
+			}
+		default:
+			inAgents = false
+		}
+	}
+
+	if specific != nil {
+		return specific
+	}
+	return wildcard
+}
+
+// robotsAllowed applies the longest matching rule to path; Allow wins ties
+// and a path no rule matches is allowed
+func robotsAllowed(rules []robotsRule, path string) bool {
+	allowed, longest := true, -1
+	for _, rule := range rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if n := len(rule.pattern); n > longest || (n == longest && rule.allow) {
+			allowed, longest = rule.allow, n
+		}
+	}
+	return allowed
+}
+
+// robotsMatch reports whether path matches a robots.txt pattern, where *
+// matches any run of characters and a trailing $ anchors the end
+func robotsMatch(pattern, path string) bool {
+	if !strings.ContainsAny(pattern, "*$") {
+		return strings.HasPrefix(path, pattern)
+	}
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	re, err := regexp.Compile(expr)
+	return err == nil && re.MatchString(path)
+}
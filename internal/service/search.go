@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/renderer"
+)
+
+const (
+	// maxSearchQueryLength caps stored search queries, in runes
+	maxSearchQueryLength = 100
+
+	// SearchResultsPerType caps how many posts, and how many bookmarks,
+	// Search returns
+	SearchResultsPerType = 10
+
+	// minMaskedDigits is how many digits a number needs before it's
+	// treated as possible personal data (phone, card, account number)
+	minMaskedDigits = 7
+)
+
+var (
+	// searchEmailPattern matches anything shaped like an email address
+	searchEmailPattern = regexp.MustCompile(`[^\s@]+@[^\s@]+\.[^\s@]+`)
+	// searchNumberPattern matches digit runs that may be phone or card
+	// numbers, allowing common separators between the digits
+	searchNumberPattern = regexp.MustCompile(`\+?\d[\d\s().-]{5,}\d`)
+)
+
+// SearchGap is a search term that readers tried and found nothing for
+type SearchGap struct {
+	Query    string `json:"query"`
+	Searches int    `json:"searches"`
+}
+
+// SearchResults are the published posts and public bookmarks matching a
+// sitewide search, each ranked by relevance
+type SearchResults struct {
+	Query     string
+	Posts     []models.Post
+	Bookmarks []models.Bookmark
+}
+
+// Total is how many results were found across both sections
+func (r SearchResults) Total() int {
+	return len(r.Posts) + len(r.Bookmarks)
+}
+
+// searchLikeEscaper escapes LIKE wildcards so a query matches literally
+var searchLikeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Search finds published posts and public bookmarks containing query as a
+// phrase, ignoring case, at most SearchResultsPerType of each. Title
+// matches rank above matches in the rest of the text. A blank query
+// returns no results.
+func (s *Service) Search(ctx context.Context, query string) (SearchResults, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	if r := []rune(query); len(r) > maxSearchQueryLength {
+		query = strings.TrimSpace(string(r[:maxSearchQueryLength]))
+	}
+	results := SearchResults{Query: query}
+	if query == "" {
+		return results, nil
+	}
+	pattern := "%" + searchLikeEscaper.Replace(query) + "%"
+
+	// Editor.js content also matches on its JSON keys, so fetch extra posts
+	// to make up for the ones dropped by postMatches
+	posts, err := s.queries.SearchPublishedPosts(ctx, db.SearchPublishedPostsParams{
+		Pattern: pattern,
+		Limit:   SearchResultsPerType * 2,
+	})
+	if err != nil {
+		return results, err
+	}
+	for _, p := range posts {
+		if len(results.Posts) == SearchResultsPerType {
+			break
+		}
+		if !postMatches(p, query) {
+			continue
+		}
+		tags, err := s.queries.GetPostTags(ctx, p.ID)
+		if err != nil {
+			return results, err
+		}
+		results.Posts = append(results.Posts, *dbPostToModel(p, tags))
+	}
+
+	bookmarks, err := s.queries.SearchPublicBookmarks(ctx, db.SearchPublicBookmarksParams{
+		Pattern: pattern,
+		Limit:   SearchResultsPerType,
+	})
+	if err != nil {
+		return results, err
+	}
+	for _, b := range bookmarks {
+		results.Bookmarks = append(results.Bookmarks, *dbBookmarkToModel(b))
+	}
+
+	return results, nil
+}
+
+// postMatches reports whether query appears in a post's readable text.
+// Markdown is taken as-is; Editor.js content is converted first, so a
+// query like "blocks" doesn't match every Editor.js post.
+func postMatches(p db.Post, query string) bool {
+	query = strings.ToLower(query)
+	if strings.Contains(strings.ToLower(p.Title), query) ||
+		(p.Excerpt != nil && strings.Contains(strings.ToLower(*p.Excerpt), query)) {
+		return true
+	}
+	content := p.Content
+	if renderer.IsEditorJS(content) {
+		md, err := renderer.EditorJSToMarkdown(content)
+		if err != nil {
+			return false
+		}
+		content = md
+	}
+	return strings.Contains(strings.ToLower(content), query)
+}
+
+// RecordSearchQuery logs a search and how many results it returned. Only
+// the sanitized query text is stored, with no IP or user agent. It does
+// nothing when search logging is disabled or nothing is left after
+// sanitizing.
+func (s *Service) RecordSearchQuery(ctx context.Context, query string, resultCount int) error {
+	if !s.searchLogging {
+		return nil
+	}
+	query = sanitizeSearchQuery(query)
+	if query == "" {
+		return nil
+	}
+	return s.queries.CreateSearchQuery(ctx, db.CreateSearchQueryParams{
+		Query:       query,
+		ResultCount: int64(resultCount),
+	})
+}
+
+// PruneSearchQueries deletes logged searches older than retention and
+// returns how many were removed
+func (s *Service) PruneSearchQueries(ctx context.Context, retention time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-retention).UTC()
+	return s.queries.DeleteSearchQueriesBefore(ctx, cutoff)
+}
+
+// GetZeroResultSearches reports the most frequent searches that returned no
+// results, most searched first
+func (s *Service) GetZeroResultSearches(ctx context.Context, limit int) ([]SearchGap, error) {
+	rows, err := s.queries.GetZeroResultSearches(ctx, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	gaps := make([]SearchGap, len(rows))
+	for i, row := range rows {
+		gaps[i] = SearchGap{Query: row.Query, Searches: int(row.Searches)}
+	}
+	return gaps, nil
+}
+
+// sanitizeSearchQuery normalizes a query so equivalent searches group
+// together, masks email addresses and long numbers, and caps its length
+func sanitizeSearchQuery(query string) string {
+	query = strings.Join(strings.Fields(strings.ToLower(query)), " ")
+
+	query = searchEmailPattern.ReplaceAllString(query, "[email]")
+	query = searchNumberPattern.ReplaceAllStringFunc(query, func(m string) string {
+		digits := 0
+		for _, r := range m {
+			if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+		// Short numbers like years and version strings are kept
+		if digits < minMaskedDigits {
+			return m
+		}
+		return "[number]"
+	})
+
+	if r := []rune(query); len(r) > maxSearchQueryLength {
+		query = strings.TrimSpace(string(r[:maxSearchQueryLength]))
+	}
+	return query
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/database"
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+// newTestService returns a Service backed by a fresh SQLite database
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+
+	conn, err := database.Init(filepath.Join(t.TempDir(), "test.db"), database.Options{Migrate: true})
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	svc := New(conn)
+	// Cleanups run last-in first-out, so background work stops before the
+	// database closes
+	t.Cleanup(func() { svc.Shutdown(context.Background()) })
+	return svc
+}
+
+func TestSanitizeSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"normalizes case and spacing", "  Go   GENERICS\t", "go generics"},
+		{"masks email", "contact me@example.com please", "contact [email] please"},
+		{"masks phone number", "call +1 (555) 123-4567", "call [number]"},
+		{"masks card number", "4111 1111 1111 1111", "[number]"},
+		{"keeps short numbers", "go 1.22 release 2024", "go 1.22 release 2024"},
+		{"blank", "   ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeSearchQuery(tt.query); got != tt.want {
+				t.Errorf("sanitizeSearchQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeSearchQuery_CapsLength(t *testing.T) {
+	long := ""
+	for len([]rune(long)) < 3*maxSearchQueryLength {
+		long += "héllo "
+	}
+	if got := []rune(sanitizeSearchQuery(long)); len(got) > maxSearchQueryLength {
+		t.Errorf("sanitized query has %d runes, want at most %d", len(got), maxSearchQueryLength)
+	}
+}
+
+func TestGetZeroResultSearches(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	searches := []struct {
+		query   string
+		results int
+	}{
+		{"sqlite wal", 0},
+		{"SQLite  WAL", 0},
+		{"htmx", 0},
+		{"golang", 4},
+		{"golang", 2},
+	}
+	for _, q := range searches {
+		if err := s.RecordSearchQuery(ctx, q.query, q.results); err != nil {
+			t.Fatalf("RecordSearchQuery(%q) error = %v", q.query, err)
+		}
+	}
+
+	gaps, err := s.GetZeroResultSearches(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetZeroResultSearches() error = %v", err)
+	}
+
+	want := []SearchGap{{Query: "sqlite wal", Searches: 2}, {Query: "htmx", Searches: 1}}
+	if len(gaps) != len(want) {
+		t.Fatalf("GetZeroResultSearches() = %+v, want %+v", gaps, want)
+	}
+	for i := range want {
+		if gaps[i] != want[i] {
+			t.Errorf("gaps[%d] = %+v, want %+v", i, gaps[i], want[i])
+		}
+	}
+}
+
+func TestPruneSearchQueries(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	for _, q := range []string{"old", "recent"} {
+		if err := s.RecordSearchQuery(ctx, q, 0); err != nil {
+			t.Fatalf("RecordSearchQuery(%q) error = %v", q, err)
+		}
+	}
+	if _, err := s.db.ExecContext(ctx, `UPDATE search_queries SET created_at = datetime('now', '-40 days') WHERE query = 'old'`); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := s.PruneSearchQueries(ctx, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("PruneSearchQueries() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("pruned %d searches, want 1", n)
+	}
+
+	gaps, _ := s.GetZeroResultSearches(ctx, 10)
+	if len(gaps) != 1 || gaps[0].Query != "recent" {
+		t.Errorf("remaining searches = %+v, want only the recent one", gaps)
+	}
+}
+
+func TestRecordSearchQuery_Disabled(t *testing.T) {
+	s := newTestService(t)
+	s.SetSearchLogging(false)
+	ctx := context.Background()
+
+	if err := s.RecordSearchQuery(ctx, "nothing here", 0); err != nil {
+		t.Fatalf("RecordSearchQuery() error = %v", err)
+	}
+
+	gaps, err := s.GetZeroResultSearches(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetZeroResultSearches() error = %v", err)
+	}
+	if len(gaps) != 0 {
+		t.Errorf("GetZeroResultSearches() = %+v, want none while logging is disabled", gaps)
+	}
+}
+
+func TestSearch_GroupsPostsAndBookmarks(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := s.CreatePost(ctx, models.CreatePostInput{
+		Title:   "Tuning SQLite for small sites",
+		Slug:    "tuning-sqlite",
+		Content: "WAL mode and friends.",
+	}); err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+	if _, err := s.CreatePost(ctx, models.CreatePostInput{
+		Title:   "SQLite drafts stay hidden",
+		Slug:    "sqlite-draft",
+		Content: "Not yet.",
+		IsDraft: true,
+	}); err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+	createTestPost(t, s, "unrelated")
+
+	if _, err := s.CreateBookmark(ctx, models.CreateBookmarkInput{
+		URL:      "https://sqlite.org/wal.html",
+		Title:    "Write-Ahead Logging",
+		IsPublic: true,
+	}); err != nil {
+		t.Fatalf("CreateBookmark: %v", err)
+	}
+	if _, err := s.CreateBookmark(ctx, models.CreateBookmarkInput{
+		URL:   "https://example.com/private",
+		Title: "Private SQLite notes",
+	}); err != nil {
+		t.Fatalf("CreateBookmark: %v", err)
+	}
+
+	results, err := s.Search(ctx, "  sqlite ")
+	if err != nil {
+		t.Fatalf("Search error = %v", err)
+	}
+	if results.Query != "sqlite" {
+		t.Errorf("Query = %q, want %q", results.Query, "sqlite")
+	}
+	if len(results.Posts) != 1 || results.Posts[0].Slug != "tuning-sqlite" {
+		t.Errorf("Posts = %+v, want only the published sqlite post", results.Posts)
+	}
+	if len(results.Bookmarks) != 1 || results.Bookmarks[0].Title != "Write-Ahead Logging" {
+		t.Errorf("Bookmarks = %+v, want only the public sqlite bookmark", results.Bookmarks)
+	}
+	if results.Total() != 2 {
+		t.Errorf("Total() = %d, want 2", results.Total())
+	}
+}
+
+func TestSearch_IgnoresEditorJSMarkup(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := s.CreatePost(ctx, models.CreatePostInput{
+		Title:   "Notes",
+		Slug:    "notes",
+		Content: `{"time":1,"blocks":[{"id":"a","type":"paragraph","data":{"text":"Hello"}}],"version":"2.28.0"}`,
+	}); err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+
+	results, err := s.Search(ctx, "paragraph")
+	if err != nil {
+		t.Fatalf("Search error = %v", err)
+	}
+	if len(results.Posts) != 0 {
+		t.Errorf("Posts = %d, want 0: block types aren't post text", len(results.Posts))
+	}
+}
+
+func TestSearch_EmptyQuery(t *testing.T) {
+	s := newTestService(t)
+	createTestPost(t, s, "anything")
+
+	results, err := s.Search(context.Background(), "   ")
+	if err != nil {
+		t.Fatalf("Search error = %v", err)
+	}
+	if results.Query != "" || results.Total() != 0 {
+		t.Errorf("Search(blank) = %+v, want empty results", results)
+	}
+}
+
+func TestSearch_IgnoresBookmarkNotes(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := s.CreateBookmark(ctx, models.CreateBookmarkInput{
+		URL:      "https://example.com/article",
+		Title:    "An article",
+		Note:     "remember the zanzibar idea",
+		IsPublic: true,
+	}); err != nil {
+		t.Fatalf("CreateBookmark: %v", err)
+	}
+
+	results, err := s.Search(ctx, "zanzibar")
+	if err != nil {
+		t.Fatalf("Search error = %v", err)
+	}
+	if len(results.Bookmarks) != 0 {
+		t.Errorf("Bookmarks = %d, want 0: notes are admin-only", len(results.Bookmarks))
+	}
+}
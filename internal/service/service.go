@@ -2,6 +2,9 @@ package service
 
 import (
 	"database/sql"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
 )
@@ -10,12 +13,104 @@ import (
 type Service struct {
 	queries *db.Queries
 	db      *sql.DB
+
+	// thumbnailMaxWidth bounds the width of generated cover thumbnails
+	thumbnailMaxWidth int
+
+	// searchLogging enables recording of search queries
+	searchLogging bool
+
+	// trackingParams are dropped from bookmark URLs for duplicate detection
+	trackingParams []string
+
+	// previewSecret signs draft preview tokens
+	previewSecret []byte
+
+	// cards caches generated post card images by post ID
+	cardsMu sync.Mutex
+	cards   map[int64]postCard
+
+	// stats caches the dashboard stats between writes
+	stats statsCache
+
+	// undo holds snapshots for undoing recent bulk moves
+	undo tokenStore[[]bookmarkPosition]
+
+	// importPreviews holds parsed import files awaiting a commit
+	importPreviews tokenStore[importPreviewEntry]
+
+	// importFolderDepth is how many levels of import folders become
+	// collections
+	importFolderDepth int
+
+	// metadataRetry controls retries of transient metadata fetch failures
+	metadataRetry retryPolicy
+
+	// respectRobots checks robots.txt before fetching page metadata;
+	// robots caches the rules per host
+	respectRobots bool
+	robots        robotsCache
+
+	// imageClient downloads cover images found on bookmarked pages. Their
+	// URLs are untrusted, so it only connects to public addresses.
+	imageClient *http.Client
+
+	// metadataMaxBody caps how much of a page is read for its metadata
+	metadataMaxBody int64
+
+	// featuredMax caps how many posts can be featured at once
+	featuredMax int
+
+	// home controls the home page sections
+	home HomeConfig
+
+	// background tracks metadata fetches and other work started without
+	// a request, so shutdown can cancel and wait for it
+	background *backgroundTasks
 }
 
 // New creates a new Service instance
 func New(database *sql.DB) *Service {
 	return &Service{
-		queries: db.New(database),
-		db:      database,
+		queries:           db.New(database),
+		db:                database,
+		thumbnailMaxWidth: defaultThumbnailMaxWidth,
+		cards:             make(map[int64]postCard),
+		previewSecret:     randomPreviewSecret(),
+		searchLogging:     true,
+		trackingParams:    DefaultTrackingParams,
+		stats:             statsCache{ttl: defaultDashboardStatsTTL},
+		undo:              tokenStore[[]bookmarkPosition]{ttl: BulkMoveUndoTTL},
+		importPreviews:    tokenStore[importPreviewEntry]{ttl: ImportPreviewTTL},
+		importFolderDepth: defaultImportFolderDepth,
+		background:        newBackgroundTasks(),
+		metadataRetry:     defaultMetadataRetry,
+		respectRobots:     true,
+		metadataMaxBody:   defaultMetadataMaxBody,
+		imageClient:       newPublicHTTPClient(imageDownloadTimeout),
+		featuredMax:       defaultFeaturedPostsMax,
+		home:              DefaultHomeConfig,
 	}
 }
+
+// SetThumbnailMaxWidth overrides the maximum width of generated cover
+// thumbnails. Non-positive values keep the current setting.
+func (s *Service) SetThumbnailMaxWidth(width int) {
+	if width > 0 {
+		s.thumbnailMaxWidth = width
+	}
+}
+
+// SetSearchLogging turns recording of search queries on or off
+func (s *Service) SetSearchLogging(enabled bool) {
+	s.searchLogging = enabled
+}
+
+// SetDashboardStatsTTL sets how long dashboard stats are cached. Zero or
+// negative disables the cache.
+func (s *Service) SetDashboardStatsTTL(ttl time.Duration) {
+	s.stats.mu.Lock()
+	defer s.stats.mu.Unlock()
+	s.stats.ttl = ttl
+	s.stats.value = nil
+}
@@ -0,0 +1,611 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+)
+
+// SiteExportVersion is the format version written by ExportSite. ImportSite
+// rejects files with any other version.
+const SiteExportVersion = 1
+
+// siteExportBatchSize is how many posts or bookmarks are read per query
+// while streaming an export
+const siteExportBatchSize = 200
+
+// ErrUnsupportedSiteExport is returned by ImportSite for files it can't read
+var ErrUnsupportedSiteExport = errors.New("unsupported site export format")
+
+// siteExport is the document written by ExportSite. Relationships are
+// stored by slug (and bookmarks by URL) so the file can be restored into a
+// database with different IDs. Users, sessions, images, and activity are
+// not included; cover images stored under /images/ must be re-uploaded.
+type siteExport struct {
+	Version     int                  `json:"version"`
+	ExportedAt  time.Time            `json:"exported_at"`
+	Tags        []exportedTag        `json:"tags"`
+	Collections []exportedCollection `json:"collections"`
+	Posts       []exportedPost       `json:"posts"`
+	Bookmarks   []exportedBookmark   `json:"bookmarks"`
+}
+
+type exportedTag struct {
+	Name  string  `json:"name"`
+	Slug  string  `json:"slug"`
+	Color *string `json:"color,omitempty"`
+}
+
+type exportedCollection struct {
+	Name        string  `json:"name"`
+	Slug        string  `json:"slug"`
+	Description *string `json:"description,omitempty"`
+	Color       *string `json:"color,omitempty"`
+	CoverImage  *string `json:"cover_image,omitempty"`
+	Parent      string  `json:"parent,omitempty"` // parent collection slug
+	SortOrder   int64   `json:"sort_order"`
+	IsPublic    bool    `json:"is_public"`
+}
+
+type exportedPost struct {
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	Content     string     `json:"content"`
+	Excerpt     *string    `json:"excerpt,omitempty"`
+	CoverImage  *string    `json:"cover_image,omitempty"`
+	IsDraft     bool       `json:"is_draft"`
+	IsFeatured  bool       `json:"is_featured,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	Tags        []string   `json:"tags"` // tag slugs
+}
+
+type exportedBookmark struct {
+	URL         string     `json:"url"`
+	Title       string     `json:"title"`
+	Description *string    `json:"description,omitempty"`
+	Note        *string    `json:"note,omitempty"`
+	CoverImage  *string    `json:"cover_image,omitempty"`
+	Favicon     *string    `json:"favicon,omitempty"`
+	Collection  string     `json:"collection,omitempty"` // collection slug
+	IsPublic    bool       `json:"is_public"`
+	IsFavorite  bool       `json:"is_favorite"`
+	IsArchived  bool       `json:"is_archived"`
+	SortOrder   int64      `json:"sort_order"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	Tags        []string   `json:"tags"` // tag slugs
+}
+
+// SiteImportResult holds the ImportSite counts for each kind of content
+type SiteImportResult struct {
+	Tags        ImportResult
+	Collections ImportResult
+	Posts       ImportResult
+	Bookmarks   ImportResult
+}
+
+// ExportSite returns a JSON export of every tag, collection, post, and
+// bookmark. The document is written as it is read from the database, so
+// large sites are never held in memory; a failure part way through
+// surfaces as a read error. The caller must Close the export, which stops
+// the writer if it was not read to the end. Cancelling ctx stops it too.
+func (s *Service) ExportSite(ctx context.Context) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pw.CloseWithError(s.writeSiteExport(ctx, pw))
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			pw.CloseWithError(ctx.Err())
+		case <-done:
+		}
+	}()
+	return pr, nil
+}
+
+func (s *Service) writeSiteExport(ctx context.Context, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	fmt.Fprintf(bw, `{"version":%d,"exported_at":`, SiteExportVersion)
+	if err := enc.Encode(time.Now().UTC()); err != nil {
+		return err
+	}
+
+	tags, err := s.queries.ListTags(ctx)
+	if err != nil {
+		return err
+	}
+	tagSlugs := make(map[int64]string, len(tags))
+	bw.WriteString(`,"tags":[`)
+	for i, tag := range tags {
+		tagSlugs[tag.ID] = tag.Slug
+		if err := writeExportItem(bw, enc, i, exportedTag{
+			Name:  tag.Name,
+			Slug:  tag.Slug,
+			Color: tag.Color,
+		}); err != nil {
+			return err
+		}
+	}
+
+	collections, err := s.queries.ListAllCollections(ctx)
+	if err != nil {
+		return err
+	}
+	collectionSlugs := make(map[int64]string, len(collections))
+	for _, c := range collections {
+		collectionSlugs[c.ID] = c.Slug
+	}
+	bw.WriteString(`],"collections":[`)
+	for i, c := range collections {
+		item := exportedCollection{
+			Name:        c.Name,
+			Slug:        c.Slug,
+			Description: c.Description,
+			Color:       c.Color,
+			CoverImage:  c.CoverImage,
+			SortOrder:   derefInt64(c.SortOrder),
+			IsPublic:    derefInt64(c.IsPublic) == 1,
+		}
+		if c.ParentID != nil {
+			item.Parent = collectionSlugs[*c.ParentID]
+		}
+		if err := writeExportItem(bw, enc, i, item); err != nil {
+			return err
+		}
+	}
+
+	bw.WriteString(`],"posts":[`)
+	written := 0
+	for offset := int64(0); ; offset += siteExportBatchSize {
+		posts, err := s.queries.ListAllPosts(ctx, db.ListAllPostsParams{
+			Limit:  siteExportBatchSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return err
+		}
+		for _, p := range posts {
+			postTags, err := s.queries.GetPostTags(ctx, p.ID)
+			if err != nil {
+				return err
+			}
+			slugs := make([]string, 0, len(postTags))
+			for _, t := range postTags {
+				slugs = append(slugs, t.Slug)
+			}
+			if err := writeExportItem(bw, enc, written, exportedPost{
+				Title:       p.Title,
+				Slug:        p.Slug,
+				Content:     p.Content,
+				Excerpt:     p.Excerpt,
+				CoverImage:  p.CoverImage,
+				IsDraft:     derefInt64(p.IsDraft) == 1,
+				IsFeatured:  p.IsFeatured == 1,
+				PublishedAt: p.PublishedAt,
+				CreatedAt:   p.CreatedAt,
+				UpdatedAt:   p.UpdatedAt,
+				Tags:        slugs,
+			}); err != nil {
+				return err
+			}
+			written++
+		}
+		if len(posts) < siteExportBatchSize {
+			break
+		}
+	}
+
+	bw.WriteString(`],"bookmarks":[`)
+	written = 0
+	for offset := int64(0); ; offset += siteExportBatchSize {
+		bookmarks, err := s.queries.ListAllBookmarks(ctx, db.ListAllBookmarksParams{
+			IncludeArchived: 1,
+			Limit:           siteExportBatchSize,
+			Offset:          offset,
+		})
+		if err != nil {
+			return err
+		}
+
+		ids := make([]int64, len(bookmarks))
+		for i, b := range bookmarks {
+			ids[i] = b.ID
+		}
+		idsJSON, err := json.Marshal(ids)
+		if err != nil {
+			return err
+		}
+		tagRows, err := s.queries.ListTagsForBookmarks(ctx, string(idsJSON))
+		if err != nil {
+			return err
+		}
+		bookmarkTags := make(map[int64][]string)
+		for _, row := range tagRows {
+			bookmarkTags[row.BookmarkID] = append(bookmarkTags[row.BookmarkID], row.Slug)
+		}
+
+		for _, b := range bookmarks {
+			item := exportedBookmark{
+				URL:         b.Url,
+				Title:       b.Title,
+				Description: b.Description,
+				Note:        b.Note,
+				CoverImage:  b.CoverImage,
+				Favicon:     b.Favicon,
+				IsPublic:    derefInt64(b.IsPublic) == 1,
+				IsFavorite:  derefInt64(b.IsFavorite) == 1,
+				IsArchived:  b.IsArchived == 1,
+				SortOrder:   derefInt64(b.SortOrder),
+				CreatedAt:   b.CreatedAt,
+				Tags:        bookmarkTags[b.ID],
+			}
+			if item.Tags == nil {
+				item.Tags = []string{}
+			}
+			if b.CollectionID != nil {
+				item.Collection = collectionSlugs[*b.CollectionID]
+			}
+			if err := writeExportItem(bw, enc, written, item); err != nil {
+				return err
+			}
+			written++
+		}
+		if len(bookmarks) < siteExportBatchSize {
+			break
+		}
+	}
+
+	bw.WriteString("]}\n")
+	return bw.Flush()
+}
+
+// writeExportItem writes one element of a JSON array, preceded by a comma
+// for every element after the first
+func writeExportItem(bw *bufio.Writer, enc *json.Encoder, index int, item any) error {
+	if index > 0 {
+		bw.WriteByte(',')
+	}
+	return enc.Encode(item)
+}
+
+// ImportSite restores a file written by ExportSite. Tags, collections, and
+// posts are matched by slug and bookmarks by normalized URL; matches are
+// updated in place and everything else is created. Post tags are replaced,
+// while bookmark tags are added to any the bookmark already has. The import
+// runs in a single transaction, so on error nothing is saved.
+func (s *Service) ImportSite(ctx context.Context, r io.Reader) (*SiteImportResult, error) {
+	var doc siteExport
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedSiteExport, err)
+	}
+	if doc.Version != SiteExportVersion {
+		return nil, fmt.Errorf("%w: version %d", ErrUnsupportedSiteExport, doc.Version)
+	}
+
+	result := &SiteImportResult{
+		Tags:        ImportResult{Total: len(doc.Tags)},
+		Collections: ImportResult{Total: len(doc.Collections)},
+		Posts:       ImportResult{Total: len(doc.Posts)},
+		Bookmarks:   ImportResult{Total: len(doc.Bookmarks)},
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	q := s.queries.WithTx(tx)
+
+	tagIDs, err := importSiteTags(ctx, q, doc.Tags, &result.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("import tags: %w", err)
+	}
+	collectionIDs, err := importSiteCollections(ctx, q, doc.Collections, &result.Collections)
+	if err != nil {
+		return nil, fmt.Errorf("import collections: %w", err)
+	}
+	if err := importSitePosts(ctx, q, doc.Posts, tagIDs, &result.Posts); err != nil {
+		return nil, fmt.Errorf("import posts: %w", err)
+	}
+	if err := s.importSiteBookmarks(ctx, q, doc.Bookmarks, tagIDs, collectionIDs, &result.Bookmarks); err != nil {
+		return nil, fmt.Errorf("import bookmarks: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.LogActivity(ctx, ActionImportCompleted, EntitySite, 0, "Site export", map[string]interface{}{
+		"posts":     result.Posts.Created + result.Posts.Updated,
+		"bookmarks": result.Bookmarks.Created + result.Bookmarks.Updated,
+	})
+
+	return result, nil
+}
+
+// importSiteTags finds or creates each tag, returning tag IDs by slug.
+// Tags have nothing to update, so existing ones count as skipped.
+func importSiteTags(ctx context.Context, q *db.Queries, tags []exportedTag, result *ImportResult) (map[string]int64, error) {
+	ids := make(map[string]int64, len(tags))
+	for _, t := range tags {
+		if t.Slug == "" || t.Name == "" {
+			result.Skipped++
+			continue
+		}
+
+		existing, err := q.GetTagBySlug(ctx, t.Slug)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Tag names are unique too; reuse a tag that was re-slugged
+			existing, err = q.GetTagByName(ctx, t.Name)
+		}
+		if err == nil {
+			ids[t.Slug] = existing.ID
+			result.Skipped++
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		created, err := q.CreateTag(ctx, db.CreateTagParams{Name: t.Name, Slug: t.Slug, Color: t.Color})
+		if err != nil {
+			return nil, fmt.Errorf("tag %q: %w", t.Slug, err)
+		}
+		ids[t.Slug] = created.ID
+		result.Created++
+	}
+	return ids, nil
+}
+
+// importSiteCollections upserts collections by slug and returns their IDs
+// by slug. Parents are linked in a second pass, since a child may come
+// before its parent in the file. Parents can only name collections in the
+// file, so the file alone decides whether they form a cycle; one that does
+// fails the import with ErrCollectionCycle.
+func importSiteCollections(ctx context.Context, q *db.Queries, collections []exportedCollection, result *ImportResult) (map[string]int64, error) {
+	ids := make(map[string]int64, len(collections))
+	for _, c := range collections {
+		if c.Slug == "" || c.Name == "" {
+			result.Skipped++
+			continue
+		}
+
+		existing, err := q.GetCollectionBySlug(ctx, c.Slug)
+		if err == nil {
+			ids[c.Slug] = existing.ID
+			result.Updated++
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		created, err := q.CreateCollection(ctx, db.CreateCollectionParams{
+			Name:        c.Name,
+			Slug:        c.Slug,
+			Description: c.Description,
+			Color:       c.Color,
+			CoverImage:  c.CoverImage,
+			SortOrder:   &c.SortOrder,
+			IsPublic:    boolToInt64Ptr(c.IsPublic),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("collection %q: %w", c.Slug, err)
+		}
+		ids[c.Slug] = created.ID
+		result.Created++
+	}
+
+	parents := make(map[int64]int64, len(collections))
+	for _, c := range collections {
+		id, ok := ids[c.Slug]
+		if !ok {
+			continue
+		}
+		if pid, ok := ids[c.Parent]; ok && c.Parent != "" {
+			parents[id] = pid
+		}
+	}
+
+	for _, c := range collections {
+		id, ok := ids[c.Slug]
+		if !ok {
+			continue
+		}
+		if inParentCycle(parents, id) {
+			return nil, fmt.Errorf("collection %q: %w", c.Slug, ErrCollectionCycle)
+		}
+		var parentID *int64
+		if pid, ok := parents[id]; ok {
+			parentID = &pid
+		}
+		if err := q.UpdateCollection(ctx, db.UpdateCollectionParams{
+			Name:        c.Name,
+			Slug:        c.Slug,
+			Description: c.Description,
+			Color:       c.Color,
+			CoverImage:  c.CoverImage,
+			ParentID:    parentID,
+			IsPublic:    boolToInt64Ptr(c.IsPublic),
+			ID:          id,
+		}); err != nil {
+			return nil, fmt.Errorf("collection %q: %w", c.Slug, err)
+		}
+	}
+	return ids, nil
+}
+
+// importSitePosts upserts posts by slug and replaces their tags
+func importSitePosts(ctx context.Context, q *db.Queries, posts []exportedPost, tagIDs map[string]int64, result *ImportResult) error {
+	for _, p := range posts {
+		if p.Slug == "" || p.Title == "" {
+			result.Skipped++
+			continue
+		}
+
+		var postID int64
+		existing, err := q.GetPostBySlug(ctx, p.Slug)
+		switch {
+		case err == nil:
+			postID = existing.ID
+			err = q.UpdatePost(ctx, db.UpdatePostParams{
+				Title:       p.Title,
+				Slug:        p.Slug,
+				Content:     p.Content,
+				Excerpt:     p.Excerpt,
+				CoverImage:  p.CoverImage,
+				IsDraft:     boolToInt64Ptr(p.IsDraft),
+				PublishedAt: p.PublishedAt,
+				ID:          postID,
+			})
+			if err != nil {
+				return fmt.Errorf("post %q: %w", p.Slug, err)
+			}
+			result.Updated++
+		case errors.Is(err, sql.ErrNoRows):
+			created, err := q.CreatePost(ctx, db.CreatePostParams{
+				Title:       p.Title,
+				Slug:        p.Slug,
+				Content:     p.Content,
+				Excerpt:     p.Excerpt,
+				CoverImage:  p.CoverImage,
+				IsDraft:     boolToInt64Ptr(p.IsDraft),
+				PublishedAt: p.PublishedAt,
+			})
+			if err != nil {
+				return fmt.Errorf("post %q: %w", p.Slug, err)
+			}
+			postID = created.ID
+			result.Created++
+		default:
+			return err
+		}
+
+		// Drafts are never featured, whatever the file says
+		if err := q.UpdatePostFeatured(ctx, db.UpdatePostFeaturedParams{
+			IsFeatured: *boolToInt64Ptr(p.IsFeatured && !p.IsDraft),
+			ID:         postID,
+		}); err != nil {
+			return fmt.Errorf("post %q: %w", p.Slug, err)
+		}
+
+		if err := q.DeletePostTags(ctx, postID); err != nil {
+			return err
+		}
+		for _, slug := range p.Tags {
+			tagID, ok := tagIDs[slug]
+			if !ok {
+				result.Errors = append(result.Errors, fmt.Sprintf("Post %s: unknown tag %s", p.Slug, slug))
+				continue
+			}
+			if err := q.AddPostTag(ctx, db.AddPostTagParams{PostID: postID, TagID: tagID}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importSiteBookmarks upserts bookmarks by normalized URL and adds their tags
+func (s *Service) importSiteBookmarks(ctx context.Context, q *db.Queries, bookmarks []exportedBookmark, tagIDs, collectionIDs map[string]int64, result *ImportResult) error {
+	for _, b := range bookmarks {
+		if b.URL == "" {
+			result.Skipped++
+			continue
+		}
+
+		normalized := s.normalizeBookmarkURL(b.URL)
+		var collectionID *int64
+		if id, ok := collectionIDs[b.Collection]; ok && b.Collection != "" {
+			collectionID = &id
+		}
+		title := b.Title
+		if title == "" {
+			title = b.URL
+		}
+
+		var bookmarkID int64
+		existing, err := q.GetBookmarkByNormalizedURL(ctx, &normalized)
+		switch {
+		case err == nil:
+			bookmarkID = existing.ID
+			err = q.UpdateBookmark(ctx, db.UpdateBookmarkParams{
+				Url:           b.URL,
+				NormalizedUrl: &normalized,
+				Title:         title,
+				Description:   b.Description,
+				Note:          b.Note,
+				CoverImage:    b.CoverImage,
+				Favicon:       b.Favicon,
+				Domain:        strPtr(extractDomain(b.URL)),
+				CollectionID:  collectionID,
+				IsPublic:      boolToInt64Ptr(b.IsPublic),
+				IsFavorite:    boolToInt64Ptr(b.IsFavorite),
+				ID:            bookmarkID,
+			})
+			if err != nil {
+				return fmt.Errorf("bookmark %s: %w", b.URL, err)
+			}
+			result.Updated++
+		case errors.Is(err, sql.ErrNoRows):
+			created, err := q.CreateBookmark(ctx, db.CreateBookmarkParams{
+				Url:           b.URL,
+				NormalizedUrl: &normalized,
+				Title:         title,
+				Description:   b.Description,
+				Note:          b.Note,
+				CoverImage:    b.CoverImage,
+				Favicon:       b.Favicon,
+				Domain:        strPtr(extractDomain(b.URL)),
+				CollectionID:  collectionID,
+				IsPublic:      boolToInt64Ptr(b.IsPublic),
+				IsFavorite:    boolToInt64Ptr(b.IsFavorite),
+				SortOrder:     &b.SortOrder,
+			})
+			if err != nil {
+				return fmt.Errorf("bookmark %s: %w", b.URL, err)
+			}
+			bookmarkID = created.ID
+			result.Created++
+		default:
+			return err
+		}
+
+		archived := int64(0)
+		if b.IsArchived {
+			archived = 1
+		}
+		if err := q.UpdateBookmarkArchived(ctx, db.UpdateBookmarkArchivedParams{IsArchived: archived, ID: bookmarkID}); err != nil {
+			return err
+		}
+
+		for _, slug := range b.Tags {
+			tagID, ok := tagIDs[slug]
+			if !ok {
+				result.Errors = append(result.Errors, fmt.Sprintf("Bookmark %s: unknown tag %s", b.URL, slug))
+				continue
+			}
+			if _, err := q.AddTagToBookmarks(ctx, db.AddTagToBookmarksParams{
+				TagID:       tagID,
+				BookmarkIds: "[" + strconv.FormatInt(bookmarkID, 10) + "]",
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+// seedSite fills svc with a tagged post, a nested collection, and an
+// archived, tagged bookmark inside it
+func seedSite(t *testing.T, svc *Service) {
+	t.Helper()
+	ctx := context.Background()
+
+	tag, err := svc.CreateTag(ctx, models.CreateTagInput{Name: "Go", Slug: "go", Color: "#00add8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreatePost(ctx, models.CreatePostInput{
+		Title:   "Hello",
+		Slug:    "hello",
+		Content: "Body",
+		TagIDs:  []int64{tag.ID},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	parent, err := svc.CreateCollection(ctx, models.CreateCollectionInput{Name: "Dev", Slug: "dev", IsPublic: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := svc.CreateCollection(ctx, models.CreateCollectionInput{Name: "Go Links", Slug: "go-links", ParentID: &parent.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bookmark, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+		URL:          "https://go.dev/",
+		Title:        "Go",
+		Note:         "private note",
+		CollectionID: &child.ID,
+		IsPublic:     true,
+		IsFavorite:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UpdateBookmarkArchived(ctx, bookmark.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.BulkAddTagToBookmarks(ctx, []int64{bookmark.ID}, tag.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exportSite(t *testing.T, svc *Service) string {
+	t.Helper()
+	r, err := svc.ExportSite(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestExportSite_CancelStopsExport(t *testing.T) {
+	svc := newTestService(t)
+	seedSite(t, svc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	export, err := svc.ExportSite(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer export.Close()
+	cancel()
+
+	if _, err := io.ReadAll(export); !errors.Is(err, context.Canceled) {
+		t.Errorf("reading a cancelled export: error = %v, want context.Canceled", err)
+	}
+}
+
+func TestExportSite(t *testing.T) {
+	svc := newTestService(t)
+	seedSite(t, svc)
+	ctx := context.Background()
+	if _, err := svc.CreateUser(ctx, "admin", "hunter22hunter22"); err != nil {
+		t.Fatal(err)
+	}
+
+	data := exportSite(t, svc)
+
+	var doc siteExport
+	if err := json.Unmarshal([]byte(data), &doc); err != nil {
+		t.Fatalf("export is not valid JSON: %v\n%s", err, data)
+	}
+	if doc.Version != SiteExportVersion {
+		t.Errorf("version = %d, want %d", doc.Version, SiteExportVersion)
+	}
+	if len(doc.Tags) != 1 || len(doc.Collections) != 2 || len(doc.Posts) != 1 || len(doc.Bookmarks) != 1 {
+		t.Fatalf("export counts: %d tags, %d collections, %d posts, %d bookmarks",
+			len(doc.Tags), len(doc.Collections), len(doc.Posts), len(doc.Bookmarks))
+	}
+	if got := doc.Posts[0].Tags; len(got) != 1 || got[0] != "go" {
+		t.Errorf("post tags = %v, want [go]", got)
+	}
+	b := doc.Bookmarks[0]
+	if b.Collection != "go-links" || !b.IsArchived || !b.IsFavorite || len(b.Tags) != 1 {
+		t.Errorf("bookmark = %+v, want collection, archived, favorite, and tag kept", b)
+	}
+
+	for _, secret := range []string{"admin", "password", "session"} {
+		if strings.Contains(data, secret) {
+			t.Errorf("export contains %q", secret)
+		}
+	}
+}
+
+func TestImportSite_RoundTrip(t *testing.T) {
+	src := newTestService(t)
+	seedSite(t, src)
+	data := exportSite(t, src)
+
+	dst := newTestService(t)
+	ctx := context.Background()
+
+	result, err := dst.ImportSite(ctx, strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Posts.Created != 1 || result.Bookmarks.Created != 1 || result.Collections.Created != 2 || result.Tags.Created != 1 {
+		t.Errorf("result = %+v, want everything created", result)
+	}
+
+	post, err := dst.GetPostBySlug(ctx, "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(post.Tags) != 1 || post.Tags[0].Slug != "go" {
+		t.Errorf("post tags = %+v, want [go]", post.Tags)
+	}
+	if !post.PublishedAt.Valid {
+		t.Error("published_at not restored")
+	}
+
+	tag, err := dst.GetTagBySlug(ctx, "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.GetColor() != "#00add8" {
+		t.Errorf("tag color = %q, want %q", tag.GetColor(), "#00add8")
+	}
+
+	child, err := dst.GetCollectionBySlug(ctx, "go-links")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, err := dst.GetCollectionBySlug(ctx, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !child.ParentID.Valid || child.ParentID.Int64 != parent.ID {
+		t.Errorf("child parent = %+v, want %d", child.ParentID, parent.ID)
+	}
+
+	bookmark, err := dst.GetBookmarkByURL(ctx, "https://go.dev/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bookmark.IsArchived || !bookmark.IsFavorite || bookmark.GetNote() != "private note" {
+		t.Errorf("bookmark = %+v, want archived favorite with note", bookmark)
+	}
+	if !bookmark.CollectionID.Valid || bookmark.CollectionID.Int64 != child.ID {
+		t.Errorf("bookmark collection = %+v, want %d", bookmark.CollectionID, child.ID)
+	}
+
+	// Importing again updates in place rather than duplicating
+	result, err = dst.ImportSite(ctx, strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Posts.Created != 0 || result.Posts.Updated != 1 || result.Bookmarks.Updated != 1 {
+		t.Errorf("second import = %+v, want updates only", result)
+	}
+	if count, _ := dst.CountPosts(ctx, false); count != 1 {
+		t.Errorf("post count = %d after re-import, want 1", count)
+	}
+}
+
+func TestImportSite_RejectsParentCycles(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{
+			name: "cycle within the file",
+			doc: `{"version":1,"collections":[
+				{"name":"A","slug":"a","parent":"b"},
+				{"name":"B","slug":"b","parent":"a"}]}`,
+		},
+		{
+			name: "self parent",
+			doc: `{"version":1,"collections":[
+				{"name":"A","slug":"a","parent":"a"}]}`,
+		},
+		{
+			name: "longer cycle",
+			doc: `{"version":1,"collections":[
+				{"name":"A","slug":"a","parent":"c"},
+				{"name":"B","slug":"b","parent":"a"},
+				{"name":"C","slug":"c","parent":"b"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService(t)
+
+			_, err := svc.ImportSite(context.Background(), strings.NewReader(tt.doc))
+			if !errors.Is(err, ErrCollectionCycle) {
+				t.Errorf("ImportSite() error = %v, want ErrCollectionCycle", err)
+			}
+		})
+	}
+}
+
+func TestImportSite_ReversesExistingParents(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	parent, err := svc.CreateCollection(ctx, models.CreateCollectionInput{Name: "A", Slug: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateCollection(ctx, models.CreateCollectionInput{Name: "B", Slug: "b", ParentID: &parent.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file nests a under b, the reverse of the database
+	doc := `{"version":1,"collections":[
+		{"name":"A","slug":"a","parent":"b"},
+		{"name":"B","slug":"b"}]}`
+	if _, err := svc.ImportSite(ctx, strings.NewReader(doc)); err != nil {
+		t.Fatalf("ImportSite() error = %v", err)
+	}
+
+	a, err := svc.GetCollectionBySlug(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := svc.GetCollectionBySlug(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.ParentID.Valid || a.ParentID.Int64 != b.ID || b.ParentID.Valid {
+		t.Errorf("parents: a = %+v, b = %+v, want a under b", a.ParentID, b.ParentID)
+	}
+}
+
+func TestImportSite_RejectsOtherFormats(t *testing.T) {
+	svc := newTestService(t)
+
+	for _, input := range []string{
+		"<html>bookmarks</html>",
+		`{"version":99,"posts":[]}`,
+	} {
+		_, err := svc.ImportSite(context.Background(), strings.NewReader(input))
+		if !errors.Is(err, ErrUnsupportedSiteExport) {
+			t.Errorf("ImportSite(%q) error = %v, want ErrUnsupportedSiteExport", input, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxSlugLength matches the limit enforced by post validation
+	maxSlugLength = 100
+
+	// fallbackSlug is used when a title has nothing left after slugifying
+	fallbackSlug = "post"
+)
+
+// slugTransliterations maps common accented and ligature letters to ASCII.
+// Anything else outside ASCII is dropped by Slugify.
+var slugTransliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ł': "l", 'ľ': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe",
+	'ř': "r",
+	'ß': "ss", 'ś': "s", 'š': "s", 'ş': "s",
+	'ť': "t", 'ţ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// Slugify turns a title into a URL slug: lowercase ASCII letters and digits
+// separated by single hyphens, at most maxSlugLength long. Accented letters
+// are transliterated; other punctuation and non-Latin characters are
+// dropped. The result may be empty.
+func Slugify(title string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	write := func(s string) {
+		if pendingHyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingHyphen = false
+		b.WriteString(s)
+	}
+
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			write(string(r))
+		case slugTransliterations[r] != "":
+			write(slugTransliterations[r])
+		case r == ' ' || r == '-' || r == '_' || r == '\t' || r == '\n' || r == '/' || r == '.':
+			pendingHyphen = true
+		}
+	}
+
+	return truncateSlug(b.String(), maxSlugLength)
+}
+
+// GenerateUniqueSlug slugifies title and appends -2, -3, … until no other
+// post uses the slug. excludeID is the post being edited, whose own slug
+// doesn't count as taken.
+func (s *Service) GenerateUniqueSlug(ctx context.Context, title string, excludeID *int64) (string, error) {
+	base := Slugify(title)
+	if base == "" {
+		base = fallbackSlug
+	}
+
+	slug := base
+	for n := 2; ; n++ {
+		post, err := s.queries.GetPostBySlug(ctx, slug)
+		if errors.Is(err, sql.ErrNoRows) {
+			return slug, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if excludeID != nil && post.ID == *excludeID {
+			return slug, nil
+		}
+
+		suffix := "-" + strconv.Itoa(n)
+		slug = truncateSlug(base, maxSlugLength-len(suffix)) + suffix
+	}
+}
+
+// truncateSlug cuts slug to at most n bytes without leaving a trailing hyphen
+func truncateSlug(slug string, n int) string {
+	if len(slug) > n {
+		slug = slug[:n]
+	}
+	return strings.TrimRight(slug, "-")
+}
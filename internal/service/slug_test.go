@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"plain title", "Hello World", "hello-world"},
+		{"punctuation", "What's new in Go 1.25?", "whats-new-in-go-1-25"},
+		{"repeated separators", "  Tips -- and   tricks_ ", "tips-and-tricks"},
+		{"accents", "Crème brûlée à Paris", "creme-brulee-a-paris"},
+		{"ligatures", "Straße & Æsir", "strasse-aesir"},
+		{"non-latin stripped", "Go 言語 入門", "go"},
+		{"nothing left", "日本語", ""},
+		{"long title", strings.Repeat("word ", 40), strings.TrimRight(strings.Repeat("word-", 20), "-")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Slugify(tt.title)
+			if got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+			if got != "" && !models.IsValidSlug(got) {
+				t.Errorf("Slugify(%q) = %q, not a valid slug", tt.title, got)
+			}
+		})
+	}
+}
+
+func TestGenerateUniqueSlug(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	first, err := svc.CreatePost(ctx, models.CreatePostInput{Title: "Hello World", Slug: "hello-world", IsDraft: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreatePost(ctx, models.CreatePostInput{Title: "Hello World", Slug: "hello-world-2", IsDraft: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		title     string
+		excludeID *int64
+		want      string
+	}{
+		{"free slug", "Something Else", nil, "something-else"},
+		{"taken twice", "Hello, World!", nil, "hello-world-3"},
+		{"own slug when editing", "Hello World", &first.ID, "hello-world"},
+		{"untranslatable title", "日本語", nil, "post"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GenerateUniqueSlug(ctx, tt.title, tt.excludeID)
+			if err != nil {
+				t.Fatalf("GenerateUniqueSlug() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GenerateUniqueSlug(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
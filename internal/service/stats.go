@@ -2,9 +2,15 @@ package service
 
 import (
 	"context"
+	"slices"
+	"sync"
 	"time"
 )
 
+// defaultDashboardStatsTTL is how long dashboard stats are cached unless
+// configured otherwise
+const defaultDashboardStatsTTL = 30 * time.Second
+
 // DashboardStats contains all statistics for the admin dashboard
 type DashboardStats struct {
 	// Total counts
@@ -31,8 +37,81 @@ type CollectionCount struct {
 	Count int
 }
 
-// GetDashboardStats retrieves all stats needed for the dashboard
+// statsCache memoizes the dashboard stats for a short TTL. Writes logged
+// through LogActivity invalidate it; gen guards against a slow load storing
+// stats that were invalidated while it ran.
+type statsCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	value   *DashboardStats
+	expires time.Time
+	gen     uint64
+}
+
+// statsActions are the logged activities that change dashboard counts
+var statsActions = map[string]bool{
+	ActionBookmarkCreated:   true,
+	ActionBookmarkUpdated:   true,
+	ActionBookmarkDeleted:   true,
+	ActionPostCreated:       true,
+	ActionPostUpdated:       true,
+	ActionPostDeleted:       true,
+	ActionPostPublished:     true,
+	ActionPostRestored:      true,
+	ActionCollectionCreated: true,
+	ActionCollectionUpdated: true,
+	ActionCollectionDeleted: true,
+	ActionTagCreated:        true,
+	ActionTagDeleted:        true,
+	ActionTagMerged:         true,
+	ActionImportCompleted:   true,
+}
+
+// GetDashboardStats retrieves all stats needed for the dashboard, serving
+// them from cache when a recent copy is available
 func (s *Service) GetDashboardStats(ctx context.Context) (*DashboardStats, error) {
+	s.stats.mu.Lock()
+	if s.stats.value != nil && time.Now().Before(s.stats.expires) {
+		stats := s.stats.value.clone()
+		s.stats.mu.Unlock()
+		return stats, nil
+	}
+	gen := s.stats.gen
+	s.stats.mu.Unlock()
+
+	stats, err := s.loadDashboardStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.stats.mu.Lock()
+	if s.stats.ttl > 0 && s.stats.gen == gen {
+		s.stats.value = stats.clone()
+		s.stats.expires = time.Now().Add(s.stats.ttl)
+	}
+	s.stats.mu.Unlock()
+
+	return stats, nil
+}
+
+// invalidateDashboardStats drops the cached stats so the next dashboard
+// load reads fresh counts
+func (s *Service) invalidateDashboardStats() {
+	s.stats.mu.Lock()
+	s.stats.value = nil
+	s.stats.gen++
+	s.stats.mu.Unlock()
+}
+
+// clone copies the stats so callers can't modify the cached value
+func (d *DashboardStats) clone() *DashboardStats {
+	c := *d
+	c.BookmarksByCollection = slices.Clone(d.BookmarksByCollection)
+	return &c
+}
+
+// loadDashboardStats queries all stats needed for the dashboard
+func (s *Service) loadDashboardStats(ctx context.Context) (*DashboardStats, error) {
 	// Get total counts
 	dbStats, err := s.queries.GetDatabaseStats(ctx)
 	if err != nil {
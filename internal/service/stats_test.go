@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestGetDashboardStats_Cache(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	totalBookmarks := func() int {
+		t.Helper()
+		stats, err := svc.GetDashboardStats(ctx)
+		if err != nil {
+			t.Fatalf("GetDashboardStats() error = %v", err)
+		}
+		return stats.TotalBookmarks
+	}
+	// insertQuietly adds a bookmark without logging activity, so only the
+	// TTL would make it show up
+	insertQuietly := func(url string) {
+		t.Helper()
+		if _, err := svc.queries.CreateBookmark(ctx, db.CreateBookmarkParams{Url: url, Title: url}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := totalBookmarks(); got != 0 {
+		t.Fatalf("TotalBookmarks = %d, want 0", got)
+	}
+
+	insertQuietly("https://example.com/quiet")
+	if got := totalBookmarks(); got != 0 {
+		t.Errorf("TotalBookmarks = %d, want cached 0", got)
+	}
+
+	if _, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{URL: "https://example.com/logged", Title: "Logged"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := totalBookmarks(); got != 2 {
+		t.Errorf("TotalBookmarks after logged create = %d, want 2", got)
+	}
+
+	svc.SetDashboardStatsTTL(0)
+	insertQuietly("https://example.com/uncached")
+	if got := totalBookmarks(); got != 3 {
+		t.Errorf("TotalBookmarks with cache disabled = %d, want 3", got)
+	}
+}
+
+func TestGetDashboardStats_RestoreInvalidates(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	post := createTestPost(t, svc, "restored")
+	if err := svc.DeletePost(ctx, post.ID); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := svc.GetDashboardStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.TotalPosts != 0 {
+		t.Fatalf("TotalPosts with the post in the trash = %d, want 0", stats.TotalPosts)
+	}
+
+	if err := svc.RestorePost(ctx, post.ID); err != nil {
+		t.Fatal(err)
+	}
+	if stats, _ := svc.GetDashboardStats(ctx); stats.TotalPosts != 1 {
+		t.Errorf("TotalPosts after restore = %d, want 1", stats.TotalPosts)
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
@@ -12,8 +13,9 @@ import (
 // CreateTag creates a new tag
 func (s *Service) CreateTag(ctx context.Context, input models.CreateTagInput) (*models.Tag, error) {
 	tag, err := s.queries.CreateTag(ctx, db.CreateTagParams{
-		Name: input.Name,
-		Slug: input.Slug,
+		Name:  input.Name,
+		Slug:  input.Slug,
+		Color: strPtr(input.Color),
 	})
 	if err != nil {
 		return nil, err
@@ -27,6 +29,60 @@ func (s *Service) DeleteTag(ctx context.Context, id int64) error {
 	return s.queries.DeleteTag(ctx, id)
 }
 
+// ErrMergeTagIntoItself is returned when MergeTags gets the same tag twice
+var ErrMergeTagIntoItself = errors.New("cannot merge a tag into itself")
+
+// MergeTags moves every post and bookmark tagged sourceID over to targetID
+// and deletes the source tag. Items that already have both tags keep a single
+// target tag. It returns the number of posts that had the source tag.
+func (s *Service) MergeTags(ctx context.Context, sourceID, targetID int64) (int, error) {
+	if sourceID == targetID {
+		return 0, ErrMergeTagIntoItself
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	q := s.queries.WithTx(tx)
+
+	source, err := q.GetTagByID(ctx, sourceID)
+	if err != nil {
+		return 0, err
+	}
+	target, err := q.GetTagByID(ctx, targetID)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := q.CountPostsByTagID(ctx, sourceID)
+	if err != nil {
+		return 0, err
+	}
+	if err := q.RetagPosts(ctx, db.RetagPostsParams{TargetID: targetID, SourceID: sourceID}); err != nil {
+		return 0, err
+	}
+	if err := q.RetagBookmarks(ctx, db.RetagBookmarksParams{TargetID: targetID, SourceID: sourceID}); err != nil {
+		return 0, err
+	}
+	// Cascades to the source tag's post_tags and bookmark_tags rows
+	if err := q.DeleteTag(ctx, sourceID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	s.LogActivity(ctx, ActionTagMerged, EntityTag, targetID, target.Name, map[string]interface{}{
+		"source": source.Name,
+		"posts":  affected,
+	})
+
+	return int(affected), nil
+}
+
 // GetTagBySlug retrieves a tag by slug
 func (s *Service) GetTagBySlug(ctx context.Context, slug string) (*models.Tag, error) {
 	tag, err := s.queries.GetTagBySlug(ctx, slug)
@@ -57,11 +113,25 @@ type TagWithCount struct {
 	Count int `json:"count"`
 }
 
-// GetTagsWithCounts returns all tags with their usage counts
-func (s *Service) GetTagsWithCounts(ctx context.Context) ([]TagWithCount, error) {
-	tags, err := s.queries.ListTagsWithCounts(ctx)
-	if err != nil {
-		return nil, err
+// GetTagsWithCounts returns tags with their usage counts, most used first.
+// With publishedOnly, only published posts are counted and unused tags are
+// left out.
+func (s *Service) GetTagsWithCounts(ctx context.Context, publishedOnly bool) ([]TagWithCount, error) {
+	var tags []db.ListTagsWithCountsRow
+	if publishedOnly {
+		rows, err := s.queries.ListPublishedTagsWithCounts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			tags = append(tags, db.ListTagsWithCountsRow(r))
+		}
+	} else {
+		var err error
+		tags, err = s.queries.ListTagsWithCounts(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	result := make([]TagWithCount, 0, len(tags))
@@ -71,6 +141,7 @@ func (s *Service) GetTagsWithCounts(ctx context.Context) ([]TagWithCount, error)
 				ID:        t.ID,
 				Name:      t.Name,
 				Slug:      t.Slug,
+				Color:     toNullString(t.Color),
 				CreatedAt: derefTime(t.CreatedAt),
 			},
 			Count: int(t.UsageCount),
@@ -126,6 +197,7 @@ func dbTagToModel(t db.Tag) *models.Tag {
 		ID:        t.ID,
 		Name:      t.Name,
 		Slug:      t.Slug,
+		Color:     toNullString(t.Color),
 		CreatedAt: derefTime(t.CreatedAt),
 	}
 }
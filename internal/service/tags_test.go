@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestMergeTags(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	newTag := func(name, slug string) int64 {
+		t.Helper()
+		tag, err := svc.CreateTag(ctx, models.CreateTagInput{Name: name, Slug: slug})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tag.ID
+	}
+	golang := newTag("Golang", "golang")
+	goTag := newTag("Go", "go")
+
+	for _, post := range []struct {
+		slug string
+		tags []int64
+	}{
+		{"only-golang", []int64{golang}},
+		{"both", []int64{golang, goTag}},
+		{"only-go", []int64{goTag}},
+	} {
+		if _, err := svc.CreatePost(ctx, models.CreatePostInput{Title: post.slug, Slug: post.slug, TagIDs: post.tags}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := svc.MergeTags(ctx, goTag, goTag); !errors.Is(err, ErrMergeTagIntoItself) {
+		t.Errorf("MergeTags(same) error = %v, want ErrMergeTagIntoItself", err)
+	}
+
+	affected, err := svc.MergeTags(ctx, golang, goTag)
+	if err != nil {
+		t.Fatalf("MergeTags() error = %v", err)
+	}
+	if affected != 2 {
+		t.Errorf("affected = %d, want 2", affected)
+	}
+
+	tags, err := svc.GetTagsWithCounts(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0].ID != goTag || tags[0].Count != 3 {
+		t.Errorf("tags after merge = %+v, want only Go with 3 posts", tags)
+	}
+
+	activities, err := svc.ListRecentActivities(ctx, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logged := false
+	for _, a := range activities {
+		if a.Action == ActionTagMerged && a.EntityID == goTag {
+			logged = true
+		}
+	}
+	if !logged {
+		t.Errorf("activities = %+v, want a tag merge", activities)
+	}
+}
+
+func TestGetTagsWithCounts_PublishedOnly(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	published, err := svc.CreateTag(ctx, models.CreateTagInput{Name: "Published", Slug: "published"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drafts, err := svc.CreateTag(ctx, models.CreateTagInput{Name: "Drafts", Slug: "drafts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateTag(ctx, models.CreateTagInput{Name: "Unused", Slug: "unused"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.CreatePost(ctx, models.CreatePostInput{Title: "Live", Slug: "live", TagIDs: []int64{published.ID, drafts.ID}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreatePost(ctx, models.CreatePostInput{Title: "Draft", Slug: "draft", IsDraft: true, TagIDs: []int64{drafts.ID}}); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := svc.GetTagsWithCounts(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Errorf("GetTagsWithCounts(false) returned %d tags, want 3", len(all))
+	}
+
+	public, err := svc.GetTagsWithCounts(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]int)
+	for _, tag := range public {
+		got[tag.Slug] = tag.Count
+	}
+	want := map[string]int{"published": 1, "drafts": 1}
+	if len(got) != len(want) || got["published"] != 1 || got["drafts"] != 1 {
+		t.Errorf("GetTagsWithCounts(true) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/gif" // register GIF decoding for thumbnail sources
+	"image/jpeg"
+	"image/png"
+)
+
+// defaultThumbnailMaxWidth is used when no width is configured
+const defaultThumbnailMaxWidth = 480
+
+// thumbnailJPEGQuality is the encoder quality for JPEG thumbnails
+const thumbnailJPEGQuality = 85
+
+// maxThumbnailSourcePixels caps the declared size of an image that is
+// decoded for a thumbnail. A small file can declare huge dimensions, and
+// decoding allocates memory for every pixel.
+const maxThumbnailSourcePixels = 50_000_000
+
+// makeThumbnail decodes a JPEG, PNG or GIF image and returns a copy scaled
+// down to maxWidth with the aspect ratio preserved. ok is false when the
+// source is already narrow enough, too large to decode safely or can't be
+// decoded, in which case the caller should keep using the original image.
+// JPEG sources are re-encoded as JPEG; everything else becomes PNG so
+// transparency survives.
+func makeThumbnail(data []byte, maxWidth int) (thumb []byte, mimeType string, ok bool) {
+	// Check the declared size before decoding allocates the pixels
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, "", false
+	}
+	if maxWidth <= 0 || cfg.Width <= maxWidth {
+		return nil, "", false
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > maxThumbnailSourcePixels {
+		return nil, "", false
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", false
+	}
+
+	bounds := src.Bounds()
+
+	height := bounds.Dy() * maxWidth / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := downscale(src, maxWidth, height)
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailJPEGQuality})
+		mimeType = "image/jpeg"
+	} else {
+		err = png.Encode(&buf, dst)
+		mimeType = "image/png"
+	}
+	if err != nil {
+		return nil, "", false
+	}
+	return buf.Bytes(), mimeType, true
+}
+
+// downscale resizes src to width x height by averaging the source pixels
+// that fall into each destination pixel. It is only meant for shrinking.
+func downscale(src image.Image, width, height int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for dy := 0; dy < height; dy++ {
+		y0 := dy * sh / height
+		y1 := max((dy+1)*sh/height, y0+1)
+		for dx := 0; dx < width; dx++ {
+			x0 := dx * sw / width
+			x1 := max((dx+1)*sw/width, x0+1)
+
+			var r, g, bl, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					pr, pg, pb, pa := src.At(b.Min.X+x, b.Min.Y+y).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					bl += uint64(pb)
+					a += uint64(pa)
+					n++
+				}
+			}
+
+			dst.SetRGBA(dx, dy, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
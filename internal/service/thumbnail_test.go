@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// encodeTestImage returns a solid-colour image of the given size in format
+func encodeTestImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "png":
+		err = png.Encode(&buf, img)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatalf("encoding %s: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+func TestMakeThumbnail(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		width    int
+		height   int
+		wantMime string
+		wantW    int
+		wantH    int
+	}{
+		{"jpeg stays jpeg", "jpeg", 1200, 800, "image/jpeg", 480, 320},
+		{"png stays png", "png", 960, 540, "image/png", 480, 270},
+		{"gif becomes png", "gif", 600, 300, "image/png", 480, 240},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := encodeTestImage(t, tt.format, tt.width, tt.height)
+
+			thumb, mimeType, ok := makeThumbnail(data, 480)
+			if !ok {
+				t.Fatal("makeThumbnail() ok = false, want true")
+			}
+			if mimeType != tt.wantMime {
+				t.Errorf("mimeType = %q, want %q", mimeType, tt.wantMime)
+			}
+
+			cfg, _, err := image.DecodeConfig(bytes.NewReader(thumb))
+			if err != nil {
+				t.Fatalf("decoding thumbnail: %v", err)
+			}
+			if cfg.Width != tt.wantW || cfg.Height != tt.wantH {
+				t.Errorf("thumbnail size = %dx%d, want %dx%d", cfg.Width, cfg.Height, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestMakeThumbnail_SkipsSmallImages(t *testing.T) {
+	data := encodeTestImage(t, "png", 480, 300)
+
+	if _, _, ok := makeThumbnail(data, 480); ok {
+		t.Error("makeThumbnail() ok = true for image already within max width")
+	}
+}
+
+func TestMakeThumbnail_UndecodableData(t *testing.T) {
+	if _, _, ok := makeThumbnail([]byte("<svg></svg>"), 480); ok {
+		t.Error("makeThumbnail() ok = true for undecodable data")
+	}
+}
+
+func TestMakeThumbnail_RejectsOversizedDimensions(t *testing.T) {
+	// A tiny PNG whose header claims 50000x50000 pixels
+	data := encodeTestImage(t, "png", 1, 1)
+	binary.BigEndian.PutUint32(data[16:20], 50000)
+	binary.BigEndian.PutUint32(data[20:24], 50000)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+
+	if _, _, ok := makeThumbnail(data, 480); ok {
+		t.Error("makeThumbnail() ok = true for an image over the pixel cap")
+	}
+}
+
+func TestDownscale_AveragesPixels(t *testing.T) {
+	// Alternating black and white columns average to mid grey
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			c := color.RGBA{A: 255}
+			if x%2 == 1 {
+				c = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+			}
+			src.Set(x, y, c)
+		}
+	}
+
+	dst := downscale(src, 2, 1)
+	got := dst.RGBAAt(0, 0)
+	if got.R < 126 || got.R > 128 || got.A != 255 {
+		t.Errorf("downscaled pixel = %+v, want mid grey", got)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// tokenStore holds values in memory under random one-time tokens until they
+// are taken or expire. Values don't survive a restart, which is fine for
+// the short-lived undo and import preview tokens kept in it.
+type tokenStore[T any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]tokenEntry[T]
+}
+
+type tokenEntry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// put stores value and returns the token to take it with. Expired entries
+// are dropped at the same time.
+func (s *tokenStore[T]) put(value T, now time.Time) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	token := hex.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries == nil {
+		s.entries = make(map[string]tokenEntry[T])
+	}
+	for t, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, t)
+		}
+	}
+	s.entries[token] = tokenEntry[T]{value: value, expires: now.Add(s.ttl)}
+	return token
+}
+
+// take removes and returns the value for token, if it hasn't expired
+func (s *tokenStore[T]) take(token string, now time.Time) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[token]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	delete(s.entries, token)
+	if !now.Before(e.expires) {
+		var zero T
+		return zero, false
+	}
+	return e.value, true
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenStore_Expiry(t *testing.T) {
+	store := tokenStore[[]bookmarkPosition]{ttl: time.Minute}
+	now := time.Now()
+	positions := []bookmarkPosition{{ID: 1}}
+
+	token := store.put(positions, now)
+	if _, ok := store.take(token, now.Add(time.Minute)); ok {
+		t.Error("take() succeeded after the TTL")
+	}
+
+	token = store.put(positions, now)
+	got, ok := store.take(token, now.Add(30*time.Second))
+	if !ok || len(got) != 1 || got[0].ID != 1 {
+		t.Errorf("take() = %v, %v; want the stored positions", got, ok)
+	}
+
+	// Expired entries are dropped when new ones are stored
+	store.put(positions, now)
+	store.put(positions, now.Add(2*time.Minute))
+	if len(store.entries) != 1 {
+		t.Errorf("store holds %d entries, want 1", len(store.entries))
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+)
+
+// DefaultTrackingParams are the query parameters dropped when normalizing
+// URLs unless configured otherwise. A trailing * matches any suffix.
+var DefaultTrackingParams = []string{
+	"utm_*",
+	"fbclid",
+	"gclid",
+	"dclid",
+	"msclkid",
+	"mc_cid",
+	"mc_eid",
+	"igshid",
+	"yclid",
+	"_hsenc",
+	"_hsmi",
+	"ref_src",
+}
+
+// NormalizeURL returns the form of raw used to detect duplicate bookmarks:
+// scheme and host lowercased, default ports and trailing slashes removed,
+// tracking parameters in DefaultTrackingParams dropped and the remaining
+// parameters sorted. Strings that don't parse as absolute URLs are returned
+// trimmed but otherwise unchanged.
+func NormalizeURL(raw string) string {
+	return normalizeURL(raw, DefaultTrackingParams)
+}
+
+// SetTrackingParams overrides the query parameters dropped when normalizing
+// bookmark URLs. An empty list keeps every parameter.
+func (s *Service) SetTrackingParams(params []string) {
+	s.trackingParams = make([]string, 0, len(params))
+	for _, p := range params {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			s.trackingParams = append(s.trackingParams, p)
+		}
+	}
+}
+
+// normalizeBookmarkURL normalizes raw with the configured tracking params
+func (s *Service) normalizeBookmarkURL(raw string) string {
+	return normalizeURL(raw, s.trackingParams)
+}
+
+// cleanBookmarkURL drops the configured tracking params from raw, leaving
+// everything else (case, path, param order, fragment) as the user typed it.
+// Unlike normalizeBookmarkURL, the result is meant to be stored and shown.
+func (s *Service) cleanBookmarkURL(raw string) string {
+	return stripTrackingParams(raw, s.trackingParams)
+}
+
+// stripTrackingParams removes query parameters matching trackingParams.
+// Strings that don't parse as absolute URLs are returned trimmed.
+func stripTrackingParams(raw string, trackingParams []string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.RawQuery == "" {
+		return raw
+	}
+
+	var kept []string
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		key, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if pair != "" && !isTrackingParam(key, trackingParams) {
+			kept = append(kept, pair)
+		}
+	}
+	u.RawQuery = strings.Join(kept, "&")
+	u.ForceQuery = false
+
+	return u.String()
+}
+
+// normalizeURL implements NormalizeURL with the given tracking params
+func normalizeURL(raw string, trackingParams []string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6 literal
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			if isTrackingParam(key, trackingParams) {
+				query.Del(key)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String()
+}
+
+// isTrackingParam reports whether the query key matches one of params
+func isTrackingParam(key string, params []string) bool {
+	key = strings.ToLower(key)
+	for _, p := range params {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"unchanged", "https://example.com/page", "https://example.com/page"},
+		{"trailing slash", "https://example.com/page/", "https://example.com/page"},
+		{"root slash", "https://example.com/", "https://example.com"},
+		{"host case", "HTTPS://Example.COM/Page", "https://example.com/Page"},
+		{"default https port", "https://example.com:443/page", "https://example.com/page"},
+		{"default http port", "http://example.com:80/page", "http://example.com/page"},
+		{"other port kept", "http://example.com:8080/page", "http://example.com:8080/page"},
+		{"utm params", "https://example.com/page?utm_source=x&utm_medium=y", "https://example.com/page"},
+		{"click ids", "https://example.com/page?fbclid=abc&gclid=def", "https://example.com/page"},
+		{"functional params kept and sorted", "https://example.com/search?q=go&utm_source=x&lang=en", "https://example.com/search?lang=en&q=go"},
+		{"tracking param case", "https://example.com/page?UTM_Source=x", "https://example.com/page"},
+		{"whitespace", "  https://example.com/page  ", "https://example.com/page"},
+		{"not absolute", "example.com/page", "example.com/page"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeURL(tt.raw); got != tt.want {
+				t.Errorf("NormalizeURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetBookmarkByURL_Normalized(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	saved, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{URL: "https://example.com/page", Title: "Page"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, variant := range []string{
+		"https://example.com/page/",
+		"https://EXAMPLE.com:443/page?utm_source=newsletter",
+	} {
+		got, err := svc.GetBookmarkByURL(ctx, variant)
+		if err != nil {
+			t.Errorf("GetBookmarkByURL(%q) error = %v", variant, err)
+			continue
+		}
+		if got.ID != saved.ID {
+			t.Errorf("GetBookmarkByURL(%q) = bookmark %d, want %d", variant, got.ID, saved.ID)
+		}
+		if got.URL != "https://example.com/page" {
+			t.Errorf("stored URL = %q, want the original", got.URL)
+		}
+	}
+
+	if _, err := svc.GetBookmarkByURL(ctx, "https://example.com/page?id=2"); err == nil {
+		t.Error("URL with a functional param matched a different bookmark")
+	}
+
+	// Custom params replace the built-in list
+	svc.SetTrackingParams([]string{"ref"})
+	if _, err := svc.GetBookmarkByURL(ctx, "https://example.com/page?utm_source=x"); err == nil {
+		t.Error("utm_source ignored after configuring a custom list")
+	}
+	if _, err := svc.GetBookmarkByURL(ctx, "https://example.com/page?ref=home"); err != nil {
+		t.Errorf("configured param not ignored: %v", err)
+	}
+}
+
+func TestStripTrackingParams(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"no query", "https://Example.com/Page/", "https://Example.com/Page/"},
+		{"only tracking", "https://example.com/post?fbclid=abc&utm_source=x", "https://example.com/post"},
+		{
+			"mixed keeps functional params in order",
+			"https://shop.example.com/item?id=42&utm_source=twitter&color=red&fbclid=abc&page=2#reviews",
+			"https://shop.example.com/item?id=42&color=red&page=2#reviews",
+		},
+		{"escaped values kept", "https://example.com/search?q=go%20lang&gclid=1", "https://example.com/search?q=go%20lang"},
+		{"lookalike kept", "https://example.com/page?utm=keep&ref=home", "https://example.com/page?utm=keep&ref=home"},
+		{"not absolute", "example.com/page?utm_source=x", "example.com/page?utm_source=x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripTrackingParams(tt.raw, DefaultTrackingParams); got != tt.want {
+				t.Errorf("stripTrackingParams(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateBookmark_StripsTrackingParams(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	cleaned, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+		URL:   "https://example.com/article?id=7&utm_source=facebook&fbclid=xyz",
+		Title: "Article",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cleaned.URL != "https://example.com/article?id=7" {
+		t.Errorf("URL = %q, want tracking params stripped", cleaned.URL)
+	}
+
+	kept, err := svc.CreateBookmark(ctx, models.CreateBookmarkInput{
+		URL:             "https://example.com/campaign?utm_source=newsletter",
+		Title:           "Campaign",
+		KeepOriginalURL: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept.URL != "https://example.com/campaign?utm_source=newsletter" {
+		t.Errorf("URL = %q, want the original kept", kept.URL)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
@@ -49,14 +51,106 @@ func (s *Service) GetUserByUsername(ctx context.Context, username string) (*mode
 	return dbUserToModel(user), nil
 }
 
-// ValidatePassword checks if the provided password matches the user's hash
+// dummyPasswordHash is compared against when a login names a user that
+// doesn't exist, so the response takes as long as a wrong password would
+// and doesn't reveal which usernames are valid.
+const dummyPasswordHash = "$2a$10$hZNwIhnL2EWwxece/C/mZOXzAbftu5cpJNM6VDiYsys3LeP/cG6LO"
+
+// ValidatePassword checks if the provided password matches the user's hash.
+// A nil user always fails, after the same bcrypt work as a real check.
 func (s *Service) ValidatePassword(user *models.User, password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
-	return err == nil
+	hash := dummyPasswordHash
+	if user != nil {
+		hash = user.PasswordHash
+	}
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	return err == nil && user != nil
+}
+
+// Errors returned by ChangePassword for input the user can correct
+var (
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	ErrWeakPassword      = errors.New("password is too weak")
+)
+
+// ChangePassword replaces the user's password after checking the current
+// one. The new password must pass models.PasswordStrengthError. Sessions
+// are left alone; revoking them is up to the caller.
+func (s *Service) ChangePassword(ctx context.Context, userID int64, oldPassword, newPassword string) error {
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !s.ValidatePassword(user, oldPassword) {
+		return ErrIncorrectPassword
+	}
+	if msg := models.PasswordStrengthError(newPassword); msg != "" {
+		return fmt.Errorf("%w: %s", ErrWeakPassword, msg)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	err = s.queries.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{
+		PasswordHash: string(hash),
+		ID:           userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Log activity
+	s.LogActivity(ctx, ActionPasswordChanged, EntityUser, userID, user.Username, nil)
+
+	return nil
+}
+
+// RecordFailedLogin counts a failed login for the user. Once maxAttempts is
+// reached the account is locked for the lockout duration and the counter
+// starts over. It reports whether this attempt locked the account.
+func (s *Service) RecordFailedLogin(ctx context.Context, userID int64, maxAttempts int, lockout time.Duration) (bool, error) {
+	attempts, err := s.queries.RecordFailedLogin(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if maxAttempts <= 0 || attempts < int64(maxAttempts) {
+		return false, nil
+	}
+
+	lockedUntil := time.Now().Add(lockout)
+	err = s.queries.LockUser(ctx, db.LockUserParams{
+		LockedUntil: &lockedUntil,
+		ID:          userID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// Log activity
+	username := ""
+	if user, err := s.GetUserByID(ctx, userID); err == nil {
+		username = user.Username
+	}
+	s.LogActivity(ctx, ActionUserLocked, EntityUser, userID, username, map[string]interface{}{
+		"attempts":     attempts,
+		"locked_until": lockedUntil.UTC().Format(time.RFC3339),
+	})
+
+	return true, nil
+}
+
+// ResetFailedLogins clears the failed login counter and any lockout
+func (s *Service) ResetFailedLogins(ctx context.Context, userID int64) error {
+	return s.queries.ResetFailedLogins(ctx, userID)
 }
 
+// maxUserAgentLength caps the user agent stored with a session
+const maxUserAgentLength = 512
+
 // CreateSession creates a new session for the user
-func (s *Service) CreateSession(ctx context.Context, userID int64, duration time.Duration) (*models.Session, error) {
+func (s *Service) CreateSession(ctx context.Context, userID int64, duration time.Duration, client models.SessionClient) (*models.Session, error) {
 	token := make([]byte, 32)
 	if _, err := rand.Read(token); err != nil {
 		return nil, err
@@ -65,10 +159,18 @@ func (s *Service) CreateSession(ctx context.Context, userID int64, duration time
 	sessionID := hex.EncodeToString(token)
 	expiresAt := time.Now().Add(duration)
 
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
 	session, err := s.queries.CreateSession(ctx, db.CreateSessionParams{
-		ID:        sessionID,
-		UserID:    userID,
-		ExpiresAt: expiresAt,
+		ID:              sessionID,
+		UserID:          userID,
+		ExpiresAt:       expiresAt,
+		DurationSeconds: int64(duration.Seconds()),
+		UserAgent:       userAgent,
+		IpAddress:       client.IPAddress,
 	})
 	if err != nil {
 		return nil, err
@@ -86,6 +188,10 @@ func (s *Service) GetSession(ctx context.Context, sessionID string) (*models.Ses
 	if err != nil {
 		return nil, err
 	}
+	// Treat expired sessions as missing even if cleanup hasn't removed them yet
+	if !session.ExpiresAt.After(time.Now()) {
+		return nil, sql.ErrNoRows
+	}
 	return dbSessionToModel(session), nil
 }
 
@@ -96,19 +202,94 @@ func (s *Service) DeleteSession(ctx context.Context, sessionID string) error {
 
 // RotateSession creates a new session and deletes the old one (if any).
 // This prevents session fixation attacks by ensuring a new session ID
-// is generated after successful authentication.
-func (s *Service) RotateSession(ctx context.Context, userID int64, oldSessionID string, duration time.Duration) (*models.Session, error) {
+// is generated after successful authentication. If the old session belonged
+// to the same user and was issued for longer (a "remember me" session), the
+// new session keeps that longer duration.
+func (s *Service) RotateSession(ctx context.Context, userID int64, oldSessionID string, duration time.Duration, client models.SessionClient) (*models.Session, error) {
 	// Delete old session if it exists (ignore errors - old session may not exist)
 	if oldSessionID != "" {
+		if old, err := s.GetSession(ctx, oldSessionID); err == nil && old.UserID == userID && old.Duration > duration {
+			duration = old.Duration
+		}
 		_ = s.DeleteSession(ctx, oldSessionID)
 	}
 
 	// Create new session
-	return s.CreateSession(ctx, userID, duration)
+	return s.CreateSession(ctx, userID, duration, client)
+}
+
+// TouchSession records that the session was used at the given time
+func (s *Service) TouchSession(ctx context.Context, sessionID string, at time.Time) error {
+	return s.queries.TouchSession(ctx, db.TouchSessionParams{
+		LastSeenAt: &at,
+		ID:         sessionID,
+	})
+}
+
+// ListSessionsForUser returns the user's unexpired sessions, newest first
+func (s *Service) ListSessionsForUser(ctx context.Context, userID int64) ([]models.Session, error) {
+	rows, err := s.queries.ListSessionsByUser(ctx, db.ListSessionsByUserParams{
+		UserID:    userID,
+		ExpiresAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]models.Session, len(rows))
+	for i, row := range rows {
+		sessions[i] = *dbSessionToModel(row)
+	}
+	return sessions, nil
 }
 
-// CleanupExpiredSessions removes all expired sessions
-func (s *Service) CleanupExpiredSessions(ctx context.Context) error {
+// RevokeSession deletes one of the user's sessions. It returns
+// sql.ErrNoRows if the session doesn't exist or belongs to another user.
+func (s *Service) RevokeSession(ctx context.Context, userID int64, sessionID string) error {
+	n, err := s.queries.DeleteUserSession(ctx, db.DeleteUserSessionParams{
+		ID:     sessionID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// RevokeAllOtherSessions deletes every session of the user except the
+// current one and returns how many were revoked
+func (s *Service) RevokeAllOtherSessions(ctx context.Context, userID int64, currentSessionID string) (int64, error) {
+	return s.queries.DeleteOtherUserSessions(ctx, db.DeleteOtherUserSessionsParams{
+		UserID: userID,
+		ID:     currentSessionID,
+	})
+}
+
+// RevokeAllSessions deletes every session of the user, including the
+// current one, and returns how many were revoked
+func (s *Service) RevokeAllSessions(ctx context.Context, userID int64) (int64, error) {
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := s.queries.DeleteAllUserSessions(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	s.LogActivity(ctx, ActionLogout, EntityUser, userID, user.Username, map[string]interface{}{
+		"sessions": n,
+	})
+	return n, nil
+}
+
+// CleanupExpiredSessions removes all expired sessions and returns how many
+// were deleted
+func (s *Service) CleanupExpiredSessions(ctx context.Context) (int64, error) {
 	return s.queries.CleanupExpiredSessions(ctx, time.Now())
 }
 
@@ -126,20 +307,34 @@ func (s *Service) EnsureAdminExists(ctx context.Context, username, password stri
 
 func dbUserToModel(u db.User) *models.User {
 	return &models.User{
-		ID:           u.ID,
-		Username:     u.Username,
-		PasswordHash: u.PasswordHash,
-		CreatedAt:    derefTime(u.CreatedAt),
-		UpdatedAt:    derefTime(u.UpdatedAt),
+		ID:                  u.ID,
+		Username:            u.Username,
+		PasswordHash:        u.PasswordHash,
+		CreatedAt:           derefTime(u.CreatedAt),
+		UpdatedAt:           derefTime(u.UpdatedAt),
+		FailedLoginAttempts: int(u.FailedLoginAttempts),
+		LockedUntil:         toNullTime(u.LockedUntil),
 	}
 }
 
 func dbSessionToModel(s db.Session) *models.Session {
+	createdAt := derefTime(s.CreatedAt)
+
+	// Sessions from before last_seen_at was tracked count from creation
+	lastSeenAt := derefTime(s.LastSeenAt)
+	if lastSeenAt.IsZero() {
+		lastSeenAt = createdAt
+	}
+
 	return &models.Session{
-		ID:        s.ID,
-		UserID:    s.UserID,
-		ExpiresAt: s.ExpiresAt,
-		CreatedAt: derefTime(s.CreatedAt),
+		ID:         s.ID,
+		UserID:     s.UserID,
+		ExpiresAt:  s.ExpiresAt,
+		CreatedAt:  createdAt,
+		Duration:   time.Duration(s.DurationSeconds) * time.Second,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IpAddress,
+		LastSeenAt: lastSeenAt,
 	}
 }
 
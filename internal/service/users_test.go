@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/EC-9624/0xec.dev/internal/database/sqlc/db"
+	"github.com/EC-9624/0xec.dev/internal/models"
+)
+
+func TestChangePassword(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	user, err := s.CreateUser(ctx, "admin", "old-password-123")
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	if err := s.ChangePassword(ctx, user.ID, "wrong-password", "new-password-456"); !errors.Is(err, ErrIncorrectPassword) {
+		t.Errorf("ChangePassword() with wrong password error = %v, want ErrIncorrectPassword", err)
+	}
+	if err := s.ChangePassword(ctx, user.ID, "old-password-123", "short"); !errors.Is(err, ErrWeakPassword) {
+		t.Errorf("ChangePassword() with weak password error = %v, want ErrWeakPassword", err)
+	}
+
+	if err := s.ChangePassword(ctx, user.ID, "old-password-123", "new-password-456"); err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+
+	updated, err := s.GetUserByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserByID() error = %v", err)
+	}
+	if s.ValidatePassword(updated, "old-password-123") {
+		t.Error("old password still valid after change")
+	}
+	if !s.ValidatePassword(updated, "new-password-456") {
+		t.Error("new password not accepted after change")
+	}
+}
+
+func TestTouchSession(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	user, err := s.CreateUser(ctx, "admin", "password-123")
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	session, err := s.CreateSession(ctx, user.ID, time.Hour, models.SessionClient{})
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if session.LastSeenAt.IsZero() {
+		t.Error("new session has no last seen time")
+	}
+
+	seen := time.Now().Add(-90 * time.Minute).UTC().Truncate(time.Second)
+	if err := s.TouchSession(ctx, session.ID, seen); err != nil {
+		t.Fatalf("TouchSession() error = %v", err)
+	}
+
+	got, err := s.GetSession(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("GetSession() error = %v", err)
+	}
+	if !got.LastSeenAt.Equal(seen) {
+		t.Errorf("LastSeenAt = %v, want %v", got.LastSeenAt, seen)
+	}
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	user, err := s.CreateUser(ctx, "admin", "password-123")
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	other, err := s.CreateUser(ctx, "other", "password-123")
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	for _, id := range []int64{user.ID, user.ID, other.ID} {
+		if _, err := s.CreateSession(ctx, id, time.Hour, models.SessionClient{}); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+	}
+
+	n, err := s.RevokeAllSessions(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("RevokeAllSessions() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("revoked %d sessions, want 2", n)
+	}
+
+	if sessions, _ := s.ListSessionsForUser(ctx, user.ID); len(sessions) != 0 {
+		t.Errorf("user has %d sessions left, want 0", len(sessions))
+	}
+	if sessions, _ := s.ListSessionsForUser(ctx, other.ID); len(sessions) != 1 {
+		t.Errorf("other user has %d sessions, want 1 untouched", len(sessions))
+	}
+
+	activities, err := s.ListRecentActivities(ctx, 1, 0)
+	if err != nil {
+		t.Fatalf("ListRecentActivities() error = %v", err)
+	}
+	if len(activities) != 1 || activities[0].Action != ActionLogout {
+		t.Errorf("activities = %+v, want a %s entry", activities, ActionLogout)
+	}
+}
+
+func TestGetSessionExpired(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	user, err := s.CreateUser(ctx, "admin", "password-123")
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	live, err := s.CreateSession(ctx, user.ID, time.Hour, models.SessionClient{})
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	// Insert directly so the expiry can be set in the past
+	expired, err := s.queries.CreateSession(ctx, db.CreateSessionParams{
+		ID:              "expired-session",
+		UserID:          user.ID,
+		ExpiresAt:       time.Now().Add(-time.Minute),
+		DurationSeconds: int64(time.Hour / time.Second),
+	})
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	if _, err := s.GetSession(ctx, expired.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetSession(expired) error = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := s.GetSession(ctx, live.ID); err != nil {
+		t.Errorf("GetSession(live) error = %v", err)
+	}
+
+	removed, err := s.CleanupExpiredSessions(ctx)
+	if err != nil {
+		t.Fatalf("CleanupExpiredSessions() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("CleanupExpiredSessions() = %d, want 1", removed)
+	}
+	if _, err := s.GetSession(ctx, live.ID); err != nil {
+		t.Errorf("GetSession(live) after cleanup error = %v", err)
+	}
+}
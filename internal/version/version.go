@@ -0,0 +1,11 @@
+// Package version holds build information injected at link time:
+//
+//	go build -ldflags "-X github.com/EC-9624/0xec.dev/internal/version.Version=v1.2.0 \
+//	  -X github.com/EC-9624/0xec.dev/internal/version.Commit=$(git rev-parse --short HEAD)"
+package version
+
+// Version is the release version, or "dev" for local builds
+var Version = "dev"
+
+// Commit is the git commit the binary was built from
+var Commit = "unknown"
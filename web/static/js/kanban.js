@@ -36,6 +36,14 @@
     };
   }
 
+  function createColumnDragState() {
+    return {
+      column: null,
+      nextSibling: null,
+      dropped: false,
+    };
+  }
+
   function createScrollState() {
     return {
       animationId: null,
@@ -78,6 +86,7 @@
   // ============================================
 
   const dragState = createDragState();
+  const columnDragState = createColumnDragState();
   const scrollState = createScrollState();
   const keyboardDragState = createKeyboardDragState();
   const selectionState = createSelectionState();
@@ -91,6 +100,10 @@
     Object.assign(dragState, createDragState());
   }
 
+  function resetColumnDragState() {
+    Object.assign(columnDragState, createColumnDragState());
+  }
+
   function resetScrollState() {
     Object.assign(scrollState, createScrollState());
   }
@@ -233,6 +246,33 @@
       });
   }
 
+  /**
+   * Move collection API call
+   * @param {string} collectionId - Collection being moved
+   * @param {string|null} afterCollectionId - Insert after this collection ID (null for first)
+   * @returns {Promise}
+   */
+  function moveCollectionAPI(collectionId, afterCollectionId) {
+    let body = "";
+    if (afterCollectionId) {
+      body = `after_id=${encodeURIComponent(afterCollectionId)}`;
+    }
+
+    return fetch(`/admin/htmx/collections/${collectionId}/move`, {
+      method: "POST",
+      headers: {
+        "X-CSRF-Token": getCSRFToken(),
+        "Content-Type": "application/x-www-form-urlencoded",
+      },
+      body: body,
+    }).then((response) => {
+      if (!response.ok) {
+        throw new Error(`Failed to move collection: ${response.status}`);
+      }
+      return response;
+    });
+  }
+
   // ============================================
   // SCREEN READER ANNOUNCEMENTS
   // ============================================
@@ -1061,6 +1101,11 @@
    * Handle drag start event
    */
   function handleDragStart(e) {
+    if (e.target.closest(".kanban-column-header[draggable]")) {
+      handleColumnDragStart(e);
+      return;
+    }
+
     const card = e.target.closest(".kanban-card");
     if (!card) return;
 
@@ -1131,6 +1176,94 @@
     dragState.placeholder = createPlaceholder();
   }
 
+  // ============================================
+  // COLUMN REORDERING
+  // ============================================
+
+  /**
+   * Start dragging a collection column by its header
+   */
+  function handleColumnDragStart(e) {
+    const column = e.target.closest(".kanban-column");
+    if (!column) return;
+
+    clearSelection();
+    columnDragState.column = column;
+    columnDragState.nextSibling = column.nextElementSibling;
+
+    setTimeout(() => {
+      column.classList.add("column-dragging");
+    }, 0);
+
+    e.dataTransfer.setData("text/plain", column.dataset.collectionId);
+    e.dataTransfer.effectAllowed = "move";
+  }
+
+  /**
+   * Move the dragged column next to the column under the cursor.
+   * Collection columns stay between the unsorted and new-collection columns.
+   */
+  function handleColumnDragOver(e) {
+    const board = document.getElementById("kanban-board");
+    const dragged = columnDragState.column;
+    const columns = Array.from(
+      board.querySelectorAll(".kanban-column:not([data-unsorted]):not(.kanban-new-column)")
+    ).filter((c) => c !== dragged);
+
+    let before = board.querySelector(".kanban-new-column");
+    for (const column of columns) {
+      const rect = column.getBoundingClientRect();
+      if (e.clientX < rect.left + rect.width / 2) {
+        before = column;
+        break;
+      }
+    }
+
+    if (dragged.nextElementSibling !== before) {
+      board.insertBefore(dragged, before);
+    }
+  }
+
+  /**
+   * Persist the new column position
+   */
+  function handleColumnDrop() {
+    const { column, nextSibling } = columnDragState;
+    columnDragState.dropped = true;
+    if (!column || column.nextElementSibling === nextSibling) return;
+
+    const prev = column.previousElementSibling;
+    const afterId = prev && prev.matches(".kanban-column:not([data-unsorted])")
+      ? prev.dataset.collectionId
+      : null;
+
+    moveCollectionAPI(column.dataset.collectionId, afterId)
+      .then(() => {
+        announce(`Moved ${getColumnName(column)}`);
+      })
+      .catch((error) => {
+        console.error("[Kanban] Error moving collection:", error);
+        column.parentNode.insertBefore(column, nextSibling);
+        showErrorToast("Failed to move collection. Please try again.");
+      });
+  }
+
+  /**
+   * Clean up after a column drag
+   */
+  function handleColumnDragEnd() {
+    const { column, nextSibling, dropped } = columnDragState;
+    column.classList.remove("column-dragging");
+
+    // Dropped outside the board, put the column back
+    if (!dropped) {
+      column.parentNode.insertBefore(column, nextSibling);
+    }
+
+    stopAutoScroll();
+    resetColumnDragState();
+  }
+
   /**
    * Add multi-drag badge showing count of cards being dragged
    */
@@ -1160,6 +1293,11 @@
    * Handle drag end event
    */
   function handleDragEnd(e) {
+    if (columnDragState.column) {
+      handleColumnDragEnd();
+      return;
+    }
+
     const card = e.target.closest(".kanban-card");
     if (!card) return;
 
@@ -1183,6 +1321,12 @@
     e.preventDefault();
     e.dataTransfer.dropEffect = "move";
 
+    if (columnDragState.column) {
+      updateScrollDirection(e.clientX, e.clientY);
+      handleColumnDragOver(e);
+      return;
+    }
+
     // Update auto-scroll based on cursor position
     if (dragState.isDragging) {
       updateScrollDirection(e.clientX, e.clientY);
@@ -1227,6 +1371,11 @@
     e.preventDefault();
     stopAutoScroll();
 
+    if (columnDragState.column) {
+      handleColumnDrop();
+      return;
+    }
+
     const column = e.target.closest(".kanban-column-content");
     if (!column || !dragState.draggedCard) return;
 
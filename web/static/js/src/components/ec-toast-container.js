@@ -78,9 +78,10 @@ class EcToastContainer extends HTMLElement {
    * Show a toast notification
    * @param {string} message - The message to display
    * @param {string} [type='error'] - Toast type: error, success, warning, info
+   * @param {number} [duration] - Auto-dismiss delay in milliseconds
    * @returns {HTMLElement} The toast element
    */
-  show(message, type = "error") {
+  show(message, type = "error", duration = EcToastContainer.CONFIG.DISMISS_DELAY) {
     const toast = document.createElement("div");
     toast.className = `toast toast-${type}`;
     toast.setAttribute("role", "alert");
@@ -95,10 +96,7 @@ class EcToastContainer extends HTMLElement {
     this.appendChild(toast);
 
     // Auto-dismiss
-    setTimeout(
-      () => this.dismiss(toast),
-      EcToastContainer.CONFIG.DISMISS_DELAY
-    );
+    setTimeout(() => this.dismiss(toast), duration);
 
     return toast;
   }
@@ -212,6 +210,18 @@ class EcToastContainer extends HTMLElement {
     return null;
   }
 
+  /**
+   * Extract the message from a 4xx error partial, so validation failures
+   * say what went wrong instead of a generic "Failed to save"
+   * @param {XMLHttpRequest} xhr
+   * @returns {string}
+   */
+  #clientErrorMessage(xhr) {
+    if (!xhr || xhr.status < 400 || xhr.status >= 500) return "";
+    const doc = new DOMParser().parseFromString(xhr.responseText, "text/html");
+    return doc.querySelector(".error-message")?.textContent.trim() || "";
+  }
+
   /**
    * Setup HTMX event handlers
    * @param {AbortSignal} signal
@@ -270,7 +280,8 @@ class EcToastContainer extends HTMLElement {
           return;
         }
 
-        this.show("Failed to save", "error");
+        const message = this.#clientErrorMessage(event.detail.xhr);
+        this.show(message || "Failed to save", "error");
       },
       { signal }
     );
@@ -1,3 +1,5 @@
+import { showError, clearError } from "../validation.js";
+
 /**
  * Split Markdown Editor
  * 
@@ -78,6 +80,7 @@
   let toolbar = null;
   let statusEl = null;
   let actionField = null;
+  let updatedAtField = null;
   let dropzone = null;
   let dropzoneOverlay = null;
   let imageInput = null;
@@ -118,6 +121,7 @@
     toolbar = document.querySelector("[data-toolbar]");
     statusEl = document.querySelector("[data-status]");
     actionField = document.querySelector("[data-action-field]");
+    updatedAtField = document.querySelector("[data-updated-at-field]");
     dropzone = document.querySelector("[data-dropzone]");
     dropzoneOverlay = document.querySelector("[data-dropzone-overlay]");
     imageInput = document.querySelector("[data-image-input]");
@@ -550,7 +554,8 @@
     });
   }
 
-  async function performSave(action) {
+  // force=true overwrites changes saved from another tab or session
+  async function performSave(action, force = false) {
     if (isNewPost && action === "save") return;
 
     const autosaveUrl = form?.dataset.autosave;
@@ -565,6 +570,7 @@
       if (actionField) actionField.value = action;
 
       const formData = new FormData(form);
+      if (force) formData.set("force", "true");
 
       const response = await fetch(autosaveUrl, {
         method: "PATCH",
@@ -575,11 +581,35 @@
       if (response.ok) {
         const data = await response.json();
         lastSavedContent = getContentSnapshot();
+        form.querySelectorAll(".input-error").forEach((input) => clearError(input));
+        if (updatedAtField && data.updated_at) updatedAtField.value = data.updated_at;
         updateStatus("saved", data.updated_at);
 
         if (data.is_draft !== undefined) {
           updatePublishState(data.is_draft);
         }
+      } else if (response.headers.get("Content-Type")?.includes("application/json")) {
+        // { errors: { field: message }, general: string, updated_at?: string }
+        const data = await response.json();
+        if (response.status === 409 && data.updated_at) {
+          const overwrite = confirm(
+            `${data.general}.\n\nSaved at ${new Date(data.updated_at).toLocaleString()}. Overwrite it with your version?`
+          );
+          if (overwrite) {
+            await performSave(action, true);
+          } else {
+            updateStatus("error", null, "Not saved: changed elsewhere. Reload to see the latest version.");
+          }
+          return;
+        }
+
+        const fieldErrors = data.errors || {};
+        for (const [name, message] of Object.entries(fieldErrors)) {
+          const input = form.querySelector(`[name="${name}"]`);
+          if (input) showError(input, message);
+        }
+        console.error("Save failed:", data);
+        updateStatus("error", null, data.general || Object.values(fieldErrors)[0]);
       } else {
         const errorText = await response.text();
         console.error("Save failed:", errorText);
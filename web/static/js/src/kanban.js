@@ -19,6 +19,8 @@
     maxSelection: 50,     // Maximum cards that can be selected
   };
 
+  const UNDO_TOAST_DURATION = 8000; // ms the "Undo" toast stays up after a bulk move
+
   // ============================================
   // STATE FACTORY FUNCTIONS
   // ============================================
@@ -36,6 +38,14 @@
     };
   }
 
+  function createColumnDragState() {
+    return {
+      column: null,
+      nextSibling: null,
+      dropped: false,
+    };
+  }
+
   function createScrollState() {
     return {
       animationId: null,
@@ -78,6 +88,7 @@
   // ============================================
 
   const dragState = createDragState();
+  const columnDragState = createColumnDragState();
   const scrollState = createScrollState();
   const keyboardDragState = createKeyboardDragState();
   const selectionState = createSelectionState();
@@ -91,6 +102,10 @@
     Object.assign(dragState, createDragState());
   }
 
+  function resetColumnDragState() {
+    Object.assign(columnDragState, createColumnDragState());
+  }
+
   function resetScrollState() {
     Object.assign(scrollState, createScrollState());
   }
@@ -218,11 +233,13 @@
       },
       body: JSON.stringify(payload),
     })
-      .then((response) => {
+      .then(async (response) => {
         if (!response.ok) {
           throw new Error(`Failed to move bookmarks: ${response.status}`);
         }
         if (onSuccess) onSuccess();
+        const data = await response.json().catch(() => ({}));
+        if (data.undo_token) showUndoMoveToast(data.undo_token, bookmarkIds.length);
         return response;
       })
       .catch((error) => {
@@ -233,6 +250,70 @@
       });
   }
 
+  /**
+   * Show a toast offering to undo a bulk move. Undoing reloads the board,
+   * since the server restores the original positions.
+   * @param {string} token - Undo token from the bulk move response
+   * @param {number} count - Number of bookmarks moved
+   */
+  function showUndoMoveToast(token, count) {
+    const toast = window.Toast?.show(`Moved ${count} ${pluralize(count, "bookmark")}`, "info", UNDO_TOAST_DURATION);
+    if (!toast) return;
+
+    const button = document.createElement("button");
+    button.type = "button";
+    button.className = "toast-action";
+    button.textContent = "Undo";
+    button.addEventListener("click", () => {
+      fetch("/admin/htmx/bookmarks/bulk/move/undo", {
+        method: "POST",
+        headers: {
+          "X-CSRF-Token": getCSRFToken(),
+          "Content-Type": "application/json",
+        },
+        body: JSON.stringify({ token }),
+      })
+        .then((response) => {
+          if (!response.ok) {
+            throw new Error(`Failed to undo move: ${response.status}`);
+          }
+          window.location.reload();
+        })
+        .catch((error) => {
+          console.error("[Kanban] Error undoing move:", error);
+          showErrorToast("This move can no longer be undone.");
+        });
+    });
+    toast.appendChild(button);
+  }
+
+  /**
+   * Move collection API call
+   * @param {string} collectionId - Collection being moved
+   * @param {string|null} afterCollectionId - Insert after this collection ID (null for first)
+   * @returns {Promise}
+   */
+  function moveCollectionAPI(collectionId, afterCollectionId) {
+    let body = "";
+    if (afterCollectionId) {
+      body = `after_id=${encodeURIComponent(afterCollectionId)}`;
+    }
+
+    return fetch(`/admin/htmx/collections/${collectionId}/move`, {
+      method: "POST",
+      headers: {
+        "X-CSRF-Token": getCSRFToken(),
+        "Content-Type": "application/x-www-form-urlencoded",
+      },
+      body: body,
+    }).then((response) => {
+      if (!response.ok) {
+        throw new Error(`Failed to move collection: ${response.status}`);
+      }
+      return response;
+    });
+  }
+
   // ============================================
   // SCREEN READER ANNOUNCEMENTS
   // ============================================
@@ -1061,6 +1142,11 @@
    * Handle drag start event
    */
   function handleDragStart(e) {
+    if (e.target.closest(".kanban-column-header[draggable]")) {
+      handleColumnDragStart(e);
+      return;
+    }
+
     const card = e.target.closest(".kanban-card");
     if (!card) return;
 
@@ -1131,6 +1217,94 @@
     dragState.placeholder = createPlaceholder();
   }
 
+  // ============================================
+  // COLUMN REORDERING
+  // ============================================
+
+  /**
+   * Start dragging a collection column by its header
+   */
+  function handleColumnDragStart(e) {
+    const column = e.target.closest(".kanban-column");
+    if (!column) return;
+
+    clearSelection();
+    columnDragState.column = column;
+    columnDragState.nextSibling = column.nextElementSibling;
+
+    setTimeout(() => {
+      column.classList.add("column-dragging");
+    }, 0);
+
+    e.dataTransfer.setData("text/plain", column.dataset.collectionId);
+    e.dataTransfer.effectAllowed = "move";
+  }
+
+  /**
+   * Move the dragged column next to the column under the cursor.
+   * Collection columns stay between the unsorted and new-collection columns.
+   */
+  function handleColumnDragOver(e) {
+    const board = document.getElementById("kanban-board");
+    const dragged = columnDragState.column;
+    const columns = Array.from(
+      board.querySelectorAll(".kanban-column:not([data-unsorted]):not(.kanban-new-column)")
+    ).filter((c) => c !== dragged);
+
+    let before = board.querySelector(".kanban-new-column");
+    for (const column of columns) {
+      const rect = column.getBoundingClientRect();
+      if (e.clientX < rect.left + rect.width / 2) {
+        before = column;
+        break;
+      }
+    }
+
+    if (dragged.nextElementSibling !== before) {
+      board.insertBefore(dragged, before);
+    }
+  }
+
+  /**
+   * Persist the new column position
+   */
+  function handleColumnDrop() {
+    const { column, nextSibling } = columnDragState;
+    columnDragState.dropped = true;
+    if (!column || column.nextElementSibling === nextSibling) return;
+
+    const prev = column.previousElementSibling;
+    const afterId = prev && prev.matches(".kanban-column:not([data-unsorted])")
+      ? prev.dataset.collectionId
+      : null;
+
+    moveCollectionAPI(column.dataset.collectionId, afterId)
+      .then(() => {
+        announce(`Moved ${getColumnName(column)}`);
+      })
+      .catch((error) => {
+        console.error("[Kanban] Error moving collection:", error);
+        column.parentNode.insertBefore(column, nextSibling);
+        showErrorToast("Failed to move collection. Please try again.");
+      });
+  }
+
+  /**
+   * Clean up after a column drag
+   */
+  function handleColumnDragEnd() {
+    const { column, nextSibling, dropped } = columnDragState;
+    column.classList.remove("column-dragging");
+
+    // Dropped outside the board, put the column back
+    if (!dropped) {
+      column.parentNode.insertBefore(column, nextSibling);
+    }
+
+    stopAutoScroll();
+    resetColumnDragState();
+  }
+
   /**
    * Add multi-drag badge showing count of cards being dragged
    */
@@ -1160,6 +1334,11 @@
    * Handle drag end event
    */
   function handleDragEnd(e) {
+    if (columnDragState.column) {
+      handleColumnDragEnd();
+      return;
+    }
+
     const card = e.target.closest(".kanban-card");
     if (!card) return;
 
@@ -1183,6 +1362,12 @@
     e.preventDefault();
     e.dataTransfer.dropEffect = "move";
 
+    if (columnDragState.column) {
+      updateScrollDirection(e.clientX, e.clientY);
+      handleColumnDragOver(e);
+      return;
+    }
+
     // Update auto-scroll based on cursor position
     if (dragState.isDragging) {
       updateScrollDirection(e.clientX, e.clientY);
@@ -1227,6 +1412,11 @@
     e.preventDefault();
     stopAutoScroll();
 
+    if (columnDragState.column) {
+      handleColumnDrop();
+      return;
+    }
+
     const column = e.target.closest(".kanban-column-content");
     if (!column || !dragState.draggedCard) return;
 
@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/EC-9624/0xec.dev/internal/service"
+)
+
+// ============================================
+// ACTIVITY HELPER FUNCTIONS
+// ============================================
+
+// activityActions lists the actions offered as filters, in display order
+var activityActions = []string{
+	service.ActionPostCreated,
+	service.ActionPostUpdated,
+	service.ActionPostPublished,
+	service.ActionPostDeleted,
+	service.ActionBookmarkCreated,
+	service.ActionBookmarkUpdated,
+	service.ActionBookmarkDeleted,
+	service.ActionCollectionCreated,
+	service.ActionCollectionUpdated,
+	service.ActionCollectionDeleted,
+	service.ActionTagCreated,
+	service.ActionTagDeleted,
+	service.ActionTagMerged,
+	service.ActionUserLocked,
+	service.ActionPasswordChanged,
+	service.ActionLogout,
+}
+
+// activityActionsFor returns the filterable actions for an entity type.
+// Actions are only offered once a type is selected.
+func activityActionsFor(entityType string) []string {
+	if entityType == "" {
+		return nil
+	}
+	var actions []string
+	for _, action := range activityActions {
+		if strings.HasPrefix(action, entityType+".") {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// activityTypeURL returns the activity log URL filtered to entityType
+func activityTypeURL(entityType string) string {
+	return activityActionURL(entityType, "")
+}
+
+// activityActionURL returns the activity log URL filtered to entityType and action
+func activityActionURL(entityType, action string) string {
+	params := url.Values{}
+	if entityType != "" {
+		params.Set("type", entityType)
+	}
+	if action != "" {
+		params.Set("action", action)
+	}
+	if len(params) == 0 {
+		return "/admin/activity"
+	}
+	return "/admin/activity?" + params.Encode()
+}
+
+// activityChipClass returns the filter chip class, highlighted when active
+func activityChipClass(active bool) string {
+	if active {
+		return "collection-chip collection-chip-active"
+	}
+	return "collection-chip"
+}
+
+// totalActivityCount sums the per-type counts
+func totalActivityCount(counts []service.ActivityTypeCount) int {
+	total := 0
+	for _, c := range counts {
+		total += c.Count
+	}
+	return total
+}
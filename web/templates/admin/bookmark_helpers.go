@@ -88,8 +88,13 @@ func bookmarkValue(bookmark *models.Bookmark, field string) string {
 		return bookmark.Title
 	case "description":
 		return bookmark.GetDescription()
+	case "note":
+		return bookmark.GetNote()
 	case "cover_image":
-		return bookmark.GetCoverImage()
+		// The raw URL field; stored images are shown via GetCoverImage
+		return bookmark.CoverImage.String
+	case "favicon":
+		return bookmark.Favicon.String
 	}
 	return ""
 }
@@ -106,8 +111,12 @@ func bookmarkFormValue(bookmark *models.Bookmark, input *models.CreateBookmarkIn
 			return input.Title
 		case "description":
 			return input.Description
+		case "note":
+			return input.Note
 		case "cover_image":
 			return input.CoverImage
+		case "favicon":
+			return input.Favicon
 		}
 	}
 	// Otherwise use the bookmark data
@@ -1,7 +1,11 @@
 package admin
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
 )
 
 // ============================================
@@ -12,6 +16,11 @@ import (
 // FORM HELPER FUNCTIONS
 // ============================================
 
+// collectionOPMLPath is the OPML export and import URL of a collection
+func collectionOPMLPath(collection *models.Collection) string {
+	return "/admin/collections/" + strconv.FormatInt(collection.ID, 10) + "/opml"
+}
+
 // collectionFormTitle returns the page title for the collection form
 func collectionFormTitle(collection *models.Collection, isNew bool) string {
 	if isNew {
@@ -34,6 +43,8 @@ func collectionFormValue(collection *models.Collection, input *models.CreateColl
 			return input.Description
 		case "color":
 			return input.Color
+		case "cover_image":
+			return input.CoverImage
 		}
 	}
 	// Otherwise use the collection data
@@ -47,6 +58,8 @@ func collectionFormValue(collection *models.Collection, input *models.CreateColl
 			return collection.GetDescription()
 		case "color":
 			return collection.GetColor()
+		case "cover_image":
+			return collection.GetCoverImage()
 		}
 	}
 	return ""
@@ -76,3 +89,36 @@ func collectionFormIsPublic(collection *models.Collection, input *models.CreateC
 	}
 	return true // Default to public for new collections
 }
+
+// collectionFormParentID returns the selected parent ID, or 0 for none
+func collectionFormParentID(collection *models.Collection, input *models.CreateCollectionInput) int64 {
+	if input != nil {
+		if input.ParentID != nil {
+			return *input.ParentID
+		}
+		return 0
+	}
+	if collection != nil && collection.ParentID.Valid {
+		return collection.ParentID.Int64
+	}
+	return 0
+}
+
+// collectionParentOptions flattens the collection tree for the parent
+// picker, leaving out collection and its sub-collections
+func collectionParentOptions(tree []service.CollectionNode, collection *models.Collection) []service.CollectionNode {
+	var options []service.CollectionNode
+	for _, node := range tree {
+		if collection != nil && node.Collection.ID == collection.ID {
+			continue
+		}
+		options = append(options, node)
+		options = append(options, collectionParentOptions(node.Children, collection)...)
+	}
+	return options
+}
+
+// collectionParentLabel indents an option by its depth in the tree
+func collectionParentLabel(node service.CollectionNode) string {
+	return strings.Repeat("— ", node.Depth) + node.Collection.Name
+}
@@ -23,6 +23,11 @@ func truncateText(text string, maxLen int) string {
 // FORM HELPER FUNCTIONS
 // ============================================
 
+// coverUploadMaxMB returns the cover upload limit in megabytes for hints
+func coverUploadMaxMB() string {
+	return strconv.Itoa(models.MaxCoverImageSize >> 20)
+}
+
 // postFormTitle returns the page title for the post form
 func postFormTitle(post *models.Post, isNew bool) string {
 	if isNew {
@@ -78,6 +83,14 @@ func postFormIsDraft(post *models.Post, input *models.CreatePostInput) bool {
 	return true // Default to draft for new posts
 }
 
+// postFormIsFeatured returns whether the featured checkbox should be checked
+func postFormIsFeatured(post *models.Post, input *models.CreatePostInput) bool {
+	if input != nil {
+		return input.IsFeatured
+	}
+	return post != nil && post.IsFeatured
+}
+
 // postFormTagIDs returns the tag IDs for the form, preferring input over post's tags
 func postFormTagIDs(post *models.Post, tags []models.Tag, input *models.CreatePostInput) []int64 {
 	// If we have input (from a failed submission), use that
@@ -103,6 +116,15 @@ func postFormAutosaveURL(post *models.Post, isNew bool) string {
 	return "/admin/posts/" + post.Slug + "/autosave"
 }
 
+// postFormUpdatedAt returns the post's updated_at as sent back by autosave,
+// so the server can detect edits made elsewhere (empty for new posts)
+func postFormUpdatedAt(post *models.Post, isNew bool) string {
+	if isNew || post == nil {
+		return ""
+	}
+	return post.UpdatedAt.Format(time.RFC3339)
+}
+
 // postFormIsDraftValue returns "true" or "false" string for hidden input
 func postFormIsDraftValue(post *models.Post, input *models.CreatePostInput) string {
 	if postFormIsDraft(post, input) {
@@ -166,3 +188,12 @@ func formatRelativeTime(t time.Time) string {
 		return t.Format("Jan 2, 2006")
 	}
 }
+
+// trashDescription summarizes the trash for the page header
+func trashDescription(count, retentionDays int) string {
+	desc := strconv.Itoa(count) + " posts"
+	if retentionDays > 0 {
+		desc += " · deleted for good after " + strconv.Itoa(retentionDays) + " days"
+	}
+	return desc
+}
@@ -0,0 +1,66 @@
+package components
+
+import (
+	"context"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// linkifyPattern finds URL candidates in plain text. Candidates are
+// validated before being linked.
+var linkifyPattern = regexp.MustCompile(`(?i)https?://[^\s<>"'` + "`" + `]+`)
+
+// Linkify renders plain text with http(s) URLs turned into links. All text,
+// URLs included, is HTML-escaped, so the input must be plain text rather
+// than HTML. Don't use it inside another link.
+func Linkify(text string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, linkifyHTML(text))
+		return err
+	})
+}
+
+// linkifyHTML escapes text and wraps valid URLs in anchors
+func linkifyHTML(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range linkifyPattern.FindAllStringIndex(text, -1) {
+		raw := trimURLPunctuation(text[m[0]:m[1]])
+		end := m[0] + len(raw)
+
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+
+		b.WriteString(html.EscapeString(text[last:m[0]]))
+		escaped := html.EscapeString(raw)
+		b.WriteString(`<a href="` + escaped + `" target="_blank" rel="noopener noreferrer" class="underline">` + escaped + `</a>`)
+		last = end
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+// trimURLPunctuation drops trailing punctuation that usually ends the
+// surrounding sentence rather than the URL. A closing parenthesis is kept
+// when the URL has a matching opening one.
+func trimURLPunctuation(u string) string {
+	for u != "" {
+		last := u[len(u)-1]
+		switch {
+		case strings.IndexByte(".,;:!?]", last) >= 0:
+			u = u[:len(u)-1]
+		case last == ')' && strings.Count(u, "(") < strings.Count(u, ")"):
+			u = u[:len(u)-1]
+		default:
+			return u
+		}
+	}
+	return u
+}
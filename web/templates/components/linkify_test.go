@@ -0,0 +1,50 @@
+package components
+
+import "testing"
+
+func TestLinkify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			"url and angle brackets",
+			"See https://example.com/a?b=1&c=2 for <details>.",
+			`See <a href="https://example.com/a?b=1&amp;c=2" target="_blank" rel="noopener noreferrer" class="underline">https://example.com/a?b=1&amp;c=2</a> for &lt;details&gt;.`,
+		},
+		{
+			"no urls",
+			"plain <b>text</b>",
+			"plain &lt;b&gt;text&lt;/b&gt;",
+		},
+		{
+			"url stops at markup",
+			`http://x.com/"onmouseover="alert(1)`,
+			`<a href="http://x.com/" target="_blank" rel="noopener noreferrer" class="underline">http://x.com/</a>&#34;onmouseover=&#34;alert(1)`,
+		},
+		{
+			"balanced parentheses kept",
+			"(see https://en.wikipedia.org/wiki/Go_(language))",
+			`(see <a href="https://en.wikipedia.org/wiki/Go_(language)" target="_blank" rel="noopener noreferrer" class="underline">https://en.wikipedia.org/wiki/Go_(language)</a>)`,
+		},
+		{
+			"other schemes not linked",
+			"javascript:alert(1) and ftp://example.com",
+			"javascript:alert(1) and ftp://example.com",
+		},
+		{
+			"scheme without host not linked",
+			"http:// is a prefix",
+			"http:// is a prefix",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := linkifyHTML(tt.text); got != tt.want {
+				t.Errorf("linkifyHTML(%q) =\n%s\nwant\n%s", tt.text, got, tt.want)
+			}
+		})
+	}
+}
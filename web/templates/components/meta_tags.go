@@ -0,0 +1,65 @@
+package components
+
+import (
+	"context"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// PageMeta describes a page for link previews and search engines. URL and
+// Image must be absolute; empty fields are left out.
+type PageMeta struct {
+	Title       string
+	Description string
+	URL         string // canonical URL
+	Image       string
+	Type        string // og:type, "website" when empty
+	SiteName    string
+}
+
+// MetaTags renders the canonical link plus Open Graph and Twitter card
+// tags for meta. It renders nothing for a zero PageMeta.
+func MetaTags(meta PageMeta) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if meta == (PageMeta{}) {
+			return nil
+		}
+
+		var b strings.Builder
+		tag := func(attr, name, content string) {
+			if content != "" {
+				b.WriteString(`<meta ` + attr + `="` + name + `" content="` + html.EscapeString(content) + `"/>` + "\n")
+			}
+		}
+
+		if meta.URL != "" {
+			b.WriteString(`<link rel="canonical" href="` + html.EscapeString(meta.URL) + `"/>` + "\n")
+		}
+		tag("name", "description", meta.Description)
+
+		ogType := meta.Type
+		if ogType == "" {
+			ogType = "website"
+		}
+		tag("property", "og:type", ogType)
+		tag("property", "og:title", meta.Title)
+		tag("property", "og:description", meta.Description)
+		tag("property", "og:url", meta.URL)
+		tag("property", "og:site_name", meta.SiteName)
+		tag("property", "og:image", meta.Image)
+
+		card := "summary"
+		if meta.Image != "" {
+			card = "summary_large_image"
+		}
+		tag("name", "twitter:card", card)
+		tag("name", "twitter:title", meta.Title)
+		tag("name", "twitter:description", meta.Description)
+
+		_, err := io.WriteString(w, b.String())
+		return err
+	})
+}
@@ -0,0 +1,68 @@
+package components
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMetaTags(t *testing.T) {
+	meta := PageMeta{
+		Title:       `Tom & Jerry's "<script>" post`,
+		Description: "Cats > mice",
+		URL:         "https://example.com/posts/tom?a=1&b=2",
+		Image:       "https://example.com/images/1",
+		Type:        "article",
+		SiteName:    "0xec",
+	}
+
+	var b strings.Builder
+	if err := MetaTags(meta).Render(context.Background(), &b); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	got := b.String()
+
+	for _, want := range []string{
+		`<link rel="canonical" href="https://example.com/posts/tom?a=1&amp;b=2"/>`,
+		`<meta name="description" content="Cats &gt; mice"/>`,
+		`<meta property="og:type" content="article"/>`,
+		`<meta property="og:title" content="Tom &amp; Jerry&#39;s &#34;&lt;script&gt;&#34; post"/>`,
+		`<meta property="og:url" content="https://example.com/posts/tom?a=1&amp;b=2"/>`,
+		`<meta property="og:site_name" content="0xec"/>`,
+		`<meta property="og:image" content="https://example.com/images/1"/>`,
+		`<meta name="twitter:card" content="summary_large_image"/>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("MetaTags() missing %s\ngot:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "<script>") {
+		t.Errorf("MetaTags() did not escape title:\n%s", got)
+	}
+}
+
+func TestMetaTags_Defaults(t *testing.T) {
+	var b strings.Builder
+	if err := MetaTags(PageMeta{Title: "Home"}).Render(context.Background(), &b); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	got := b.String()
+
+	if !strings.Contains(got, `<meta property="og:type" content="website"/>`) {
+		t.Errorf("MetaTags() og:type should default to website:\n%s", got)
+	}
+	if !strings.Contains(got, `<meta name="twitter:card" content="summary"/>`) {
+		t.Errorf("MetaTags() without image should use summary card:\n%s", got)
+	}
+	if strings.Contains(got, "og:image") || strings.Contains(got, "canonical") {
+		t.Errorf("MetaTags() should omit empty fields:\n%s", got)
+	}
+
+	b.Reset()
+	if err := MetaTags(PageMeta{}).Render(context.Background(), &b); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("MetaTags(zero) = %q, want empty", b.String())
+	}
+}
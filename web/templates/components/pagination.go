@@ -0,0 +1,44 @@
+package components
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Pagination describes which page of an offset-paginated list is shown
+type Pagination struct {
+	Page    int // 1-based current page
+	PerPage int
+	Total   int // items across all pages
+}
+
+// TotalPages returns the number of pages, at least 1
+func (p Pagination) TotalPages() int {
+	if p.PerPage <= 0 || p.Total <= p.PerPage {
+		return 1
+	}
+	return (p.Total + p.PerPage - 1) / p.PerPage
+}
+
+// HasPrev reports whether there is a page before the current one
+func (p Pagination) HasPrev() bool {
+	return p.Page > 1
+}
+
+// HasMore reports whether there is a page after the current one
+func (p Pagination) HasMore() bool {
+	return p.Page > 0 && p.Page < p.TotalPages()
+}
+
+// pageURL returns baseURL pointing at page, leaving page 1 unadorned.
+// baseURL may already have a query string.
+func pageURL(baseURL string, page int) string {
+	if page <= 1 {
+		return baseURL
+	}
+	sep := "?"
+	if strings.Contains(baseURL, "?") {
+		sep = "&"
+	}
+	return baseURL + sep + "page=" + strconv.Itoa(page)
+}
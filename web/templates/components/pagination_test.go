@@ -0,0 +1,53 @@
+package components
+
+import "testing"
+
+func TestPagination(t *testing.T) {
+	tests := []struct {
+		name      string
+		p         Pagination
+		wantPages int
+		wantPrev  bool
+		wantMore  bool
+	}{
+		{"empty", Pagination{Page: 1, PerPage: 10, Total: 0}, 1, false, false},
+		{"single page", Pagination{Page: 1, PerPage: 10, Total: 10}, 1, false, false},
+		{"first of three", Pagination{Page: 1, PerPage: 10, Total: 25}, 3, false, true},
+		{"middle", Pagination{Page: 2, PerPage: 10, Total: 25}, 3, true, true},
+		{"last", Pagination{Page: 3, PerPage: 10, Total: 25}, 3, true, false},
+		{"unpaginated", Pagination{}, 1, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.TotalPages(); got != tt.wantPages {
+				t.Errorf("TotalPages() = %d, want %d", got, tt.wantPages)
+			}
+			if got := tt.p.HasPrev(); got != tt.wantPrev {
+				t.Errorf("HasPrev() = %v, want %v", got, tt.wantPrev)
+			}
+			if got := tt.p.HasMore(); got != tt.wantMore {
+				t.Errorf("HasMore() = %v, want %v", got, tt.wantMore)
+			}
+		})
+	}
+}
+
+func TestPageURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		page    int
+		want    string
+	}{
+		{"/admin/posts", 1, "/admin/posts"},
+		{"/admin/posts", 2, "/admin/posts?page=2"},
+		{"/admin/bookmarks?view=table", 1, "/admin/bookmarks?view=table"},
+		{"/admin/bookmarks?view=table&collection=3", 4, "/admin/bookmarks?view=table&collection=3&page=4"},
+	}
+
+	for _, tt := range tests {
+		if got := pageURL(tt.baseURL, tt.page); got != tt.want {
+			t.Errorf("pageURL(%q, %d) = %q, want %q", tt.baseURL, tt.page, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package components
+
+// TagCloudSizes is the number of size classes used by TagCloud
+const TagCloudSizes = 5
+
+// TagCloudSize buckets a tag's post count into a size class from 1 to
+// TagCloudSizes, scaled linearly between the smallest and largest counts in
+// the cloud. When every tag has the same count they all get the smallest size.
+func TagCloudSize(count, minCount, maxCount int) int {
+	if maxCount <= minCount || count <= minCount {
+		return 1
+	}
+	if count >= maxCount {
+		return TagCloudSizes
+	}
+	return 1 + (count-minCount)*(TagCloudSizes-1)/(maxCount-minCount)
+}
@@ -0,0 +1,28 @@
+package components
+
+import "testing"
+
+func TestTagCloudSize(t *testing.T) {
+	tests := []struct {
+		name                      string
+		count, minCount, maxCount int
+		want                      int
+	}{
+		{"all equal", 3, 3, 3, 1},
+		{"smallest", 1, 1, 9, 1},
+		{"largest", 9, 1, 9, TagCloudSizes},
+		{"middle", 5, 1, 9, 3},
+		{"just above smallest", 2, 1, 9, 1},
+		{"just below largest", 8, 1, 9, 4},
+		{"out of range low", 0, 1, 9, 1},
+		{"out of range high", 20, 1, 9, TagCloudSizes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TagCloudSize(tt.count, tt.minCount, tt.maxCount); got != tt.want {
+				t.Errorf("TagCloudSize(%d, %d, %d) = %d, want %d", tt.count, tt.minCount, tt.maxCount, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,16 +1,21 @@
 package templates
 
-import "github.com/EC-9624/0xec.dev/internal/models"
+import (
+	"github.com/EC-9624/0xec.dev/internal/models"
+	"github.com/EC-9624/0xec.dev/internal/service"
+)
 
 // BookmarksData holds all data needed for bookmarks pages
 type BookmarksData struct {
 	Bookmarks         []models.Bookmark
-	Collections       []models.Collection
+	Collections       []service.CollectionNode // Public collection tree
 	ActiveCollection  *models.Collection
-	Total             int // Count for current view (filtered by collection if any)
-	TotalAllBookmarks int // Global count of all public bookmarks (for sidebar)
-	Page              int
-	HasMore           bool
+	Total             int    // Count for current view (filtered by collection if any)
+	TotalAllBookmarks int    // Global count of all public bookmarks (for sidebar)
+	TotalFavorites    int    // Count of public favorites (for sidebar)
+	FavoritesOnly     bool   // Favorites page rather than a collection or all bookmarks
+	NextCursor        string // Cursor for the next page, empty on the last page
+	Sort              service.BookmarkSort
 }
 
 // PostData holds all data needed for post pages